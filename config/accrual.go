@@ -0,0 +1,22 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strings"
+
+	"main/internal/usecase"
+)
+
+// LoadAccrualPolicy reads the revenue recognition frequency from the environment,
+// falling back to the policy defaults
+func LoadAccrualPolicy() usecase.AccrualPolicy {
+	policy := usecase.DefaultAccrualPolicy()
+	if frequency := strings.ToUpper(strings.TrimSpace(os.Getenv("ACCRUAL_FREQUENCY"))); frequency != "" {
+		if frequency != usecase.AccrualFrequencyDaily && frequency != usecase.AccrualFrequencyMonthly {
+			log.Fatalf("Nilai ACCRUAL_FREQUENCY tidak valid: %s", frequency)
+		}
+		policy.Frequency = frequency
+	}
+	return policy
+}
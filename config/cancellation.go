@@ -0,0 +1,17 @@
+package config
+
+import (
+	"time"
+
+	"main/internal/usecase"
+)
+
+// LoadCancellationPolicy reads the cooling-off window and signature timeout from the environment,
+// falling back to the policy defaults
+func LoadCancellationPolicy() usecase.CancellationPolicy {
+	policy := usecase.DefaultCancellationPolicy()
+	policy.WindowDays = envInt("CANCELLATION_WINDOW_DAYS", policy.WindowDays)
+	hours := envInt("CONTRACT_SIGNATURE_TIMEOUT_HOURS", int(policy.SignatureTimeout/time.Hour))
+	policy.SignatureTimeout = time.Duration(hours) * time.Hour
+	return policy
+}
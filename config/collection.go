@@ -0,0 +1,16 @@
+package config
+
+import "main/internal/usecase"
+
+// LoadCollectionPolicy reads the collections queue ranking and promise terms from the environment,
+// falling back to the policy defaults
+func LoadCollectionPolicy() usecase.CollectionPolicy {
+	policy := usecase.DefaultCollectionPolicy()
+	policy.DPDWeight = envFloat("COLLECTION_DPD_WEIGHT", policy.DPDWeight)
+	policy.AmountWeight = envFloat("COLLECTION_AMOUNT_WEIGHT", policy.AmountWeight)
+	policy.RiskWeight = envFloat("COLLECTION_RISK_WEIGHT", policy.RiskWeight)
+	policy.DPDCap = envInt("COLLECTION_DPD_CAP", policy.DPDCap)
+	policy.AmountCap = envFloat("COLLECTION_AMOUNT_CAP", policy.AmountCap)
+	policy.MaxPromiseDays = envInt("PROMISE_TO_PAY_MAX_DAYS", policy.MaxPromiseDays)
+	return policy
+}
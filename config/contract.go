@@ -0,0 +1,30 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+
+	"main/internal/usecase"
+)
+
+// LoadTransactionPolicy reads whether new contracts need the consumer OTP and signature, falling back to the policy defaults
+func LoadTransactionPolicy() usecase.TransactionPolicy {
+	policy := usecase.DefaultTransactionPolicy()
+	policy.RequireOTP = envBool("TRANSACTION_OTP_REQUIRED", policy.RequireOTP)
+	policy.RequireSignature = envBool("CONTRACT_SIGNATURE_REQUIRED", policy.RequireSignature)
+	return policy
+}
+
+// envBool parses a boolean setting such as "true", "false", "1" or "0"
+func envBool(key string, fallback bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Fatalf("Nilai %s tidak valid: %v", key, err)
+	}
+	return parsed
+}
@@ -46,6 +46,43 @@ func ConnectDB() *gorm.DB {
 		&model.Consumer{},
 		&model.ConsumerLimit{},
 		&model.Transaction{},
+		&model.KYCVerification{},
+		&model.WatchlistEntry{},
+		&model.WatchlistHit{},
+		&model.FraudAssessment{},
+		&model.Installment{},
+		&model.Payment{},
+		&model.TransactionCancellation{},
+		&model.Refund{},
+		&model.LimitHold{},
+		&model.Product{},
+		&model.ProductRate{},
+		&model.ProductPromo{},
+		&model.Merchant{},
+		&model.MerchantBranch{},
+		&model.MerchantAPIKey{},
+		&model.SettlementBatch{},
+		&model.SettlementItem{},
+		&model.JournalEntry{},
+		&model.JournalLine{},
+		&model.InterestAccrual{},
+		&model.Restructuring{},
+		&model.WriteOff{},
+		&model.Recovery{},
+		&model.CollectionCase{},
+		&model.ContactAttempt{},
+		&model.PromiseToPay{},
+		&model.NotificationPreference{},
+		&model.NotificationLog{},
+		&model.VirtualAccount{},
+		&model.PaymentCallback{},
+		&model.BankStatement{},
+		&model.BankStatementLine{},
+		&model.StatementArchive{},
+		&model.ContractTemplate{},
+		&model.ContractDocument{},
+		&model.ContractSignature{},
+		&model.OTPChallenge{},
 	)
 	if err != nil {
 		log.Fatal("Gagal melakukan migration:", err)
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+
+	"main/internal/usecase"
+)
+
+// LoadDelinquencyPolicy reads the late fee and default settings from the environment,
+// falling back to the policy defaults
+func LoadDelinquencyPolicy() usecase.DelinquencyPolicy {
+	policy := usecase.DefaultDelinquencyPolicy()
+	policy.LateFeeDailyRate = envFloat("LATE_FEE_DAILY_RATE", policy.LateFeeDailyRate)
+	policy.LateFeeCapRate = envFloat("LATE_FEE_CAP_RATE", policy.LateFeeCapRate)
+	policy.DefaultAfterDPD = envInt("DEFAULT_AFTER_DPD", policy.DefaultAfterDPD)
+	return policy
+}
+
+// envFloat reads a decimal environment variable, returning fallback when unset
+func envFloat(key string, fallback float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Fatalf("Nilai %s tidak valid: %v", key, err)
+	}
+	return parsed
+}
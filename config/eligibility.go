@@ -0,0 +1,41 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+
+	"main/internal/usecase"
+)
+
+// LoadEligibilityRules reads the borrower age policy from the environment,
+// falling back to the financing policy defaults
+func LoadEligibilityRules() usecase.EligibilityRules {
+	rules := usecase.DefaultEligibilityRules()
+	rules.MinAge = envInt("ELIGIBILITY_MIN_AGE", rules.MinAge)
+	rules.MinAgeMarried = envInt("ELIGIBILITY_MIN_AGE_MARRIED", rules.MinAgeMarried)
+	rules.MaxAgeAtMaturity = envInt("ELIGIBILITY_MAX_AGE_AT_MATURITY", rules.MaxAgeAtMaturity)
+	return rules
+}
+
+// LoadNIKPolicy reads whether NIKs with a regency or district the region table does not list yet
+// are accepted, falling back to the policy defaults
+func LoadNIKPolicy() usecase.NIKPolicy {
+	policy := usecase.DefaultNIKPolicy()
+	policy.SkipUnlistedRegencies = envBool("NIK_SKIP_UNLISTED_REGENCIES", policy.SkipUnlistedRegencies)
+	policy.SkipUnlistedDistricts = envBool("NIK_SKIP_UNLISTED_DISTRICTS", policy.SkipUnlistedDistricts)
+	return policy
+}
+
+// envInt reads an integer environment variable, returning fallback when unset
+func envInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		log.Fatalf("Nilai %s tidak valid: %v", key, err)
+	}
+	return parsed
+}
@@ -0,0 +1,17 @@
+package config
+
+import (
+	"log"
+	"os"
+
+	"main/internal/fraud"
+)
+
+// LoadFraudEngine loads the fraud rules from FRAUD_RULES_PATH, or the embedded defaults when unset
+func LoadFraudEngine() *fraud.Engine {
+	engine, err := fraud.LoadEngine(os.Getenv("FRAUD_RULES_PATH"))
+	if err != nil {
+		log.Fatal("Gagal memuat aturan fraud:", err)
+	}
+	return engine
+}
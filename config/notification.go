@@ -0,0 +1,31 @@
+package config
+
+import (
+	"os"
+
+	"main/internal/notification"
+	"main/internal/usecase"
+)
+
+// LoadNotificationPolicy reads the reminder days from the environment, falling back to the policy defaults
+func LoadNotificationPolicy() usecase.NotificationPolicy {
+	policy := usecase.DefaultNotificationPolicy()
+	policy.DaysBeforeDue = envInt("REMINDER_DAYS_BEFORE_DUE", policy.DaysBeforeDue)
+	policy.DaysAfterDue = envInt("REMINDER_DAYS_AFTER_DUE", policy.DaysAfterDue)
+	return policy
+}
+
+// LoadNotifiers returns the adapter of every channel. Until the SMS, WhatsApp and email gateways
+// are wired in, messages are written to NOTIFICATION_OUTBOX_DIR, or to the log when it is unset.
+func LoadNotifiers() map[string]notification.Notifier {
+	var notifier notification.Notifier = &notification.ConsoleNotifier{}
+	if dir := os.Getenv("NOTIFICATION_OUTBOX_DIR"); dir != "" {
+		notifier = &notification.FileNotifier{Dir: dir}
+	}
+
+	notifiers := make(map[string]notification.Notifier)
+	for _, channel := range notification.Channels {
+		notifiers[channel] = notifier
+	}
+	return notifiers
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"crypto/rand"
+	"log"
+	"os"
+	"time"
+
+	"main/internal/usecase"
+)
+
+// LoadOTPPolicy reads the one-time code settings from the environment, falling back to the policy defaults
+func LoadOTPPolicy() usecase.OTPPolicy {
+	policy := usecase.DefaultOTPPolicy()
+	policy.Digits = envInt("OTP_DIGITS", policy.Digits)
+	ttl := envInt("OTP_TTL_SECONDS", int(policy.TTL/time.Second))
+	policy.TTL = time.Duration(ttl) * time.Second
+	policy.MaxAttempts = envInt("OTP_MAX_ATTEMPTS", policy.MaxAttempts)
+	cooldown := envInt("OTP_RESEND_COOLDOWN_SECONDS", int(policy.ResendCooldown/time.Second))
+	policy.ResendCooldown = time.Duration(cooldown) * time.Second
+	policy.MaxSends = envInt("OTP_MAX_SENDS", policy.MaxSends)
+	return policy
+}
+
+// LoadOTPKey returns OTP_SECRET, the key of the stored code hashes. Without it a random key is used,
+// so codes sent before a restart can no longer be verified.
+func LoadOTPKey() []byte {
+	if secret := os.Getenv("OTP_SECRET"); secret != "" {
+		return []byte(secret)
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		log.Fatalf("Gagal membuat kunci OTP: %v", err)
+	}
+	log.Println("⚠ OTP_SECRET tidak diisi, memakai kunci acak; kode OTP tidak berlaku lagi setelah restart")
+	return key
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"main/internal/usecase"
+	"main/internal/va"
+)
+
+// LoadVirtualAccountPolicy reads the callback clock tolerance and statement date window from the environment,
+// falling back to the policy defaults
+func LoadVirtualAccountPolicy() usecase.VirtualAccountPolicy {
+	policy := usecase.DefaultVirtualAccountPolicy()
+	seconds := envInt("VA_CALLBACK_TOLERANCE_SECONDS", int(policy.CallbackTolerance/time.Second))
+	policy.CallbackTolerance = time.Duration(seconds) * time.Second
+	policy.StatementDateDays = envInt("VA_STATEMENT_DATE_DAYS", policy.StatementDateDays)
+	return policy
+}
+
+// LoadVirtualAccountBanks returns the banks listed in VA_BANKS (e.g. "BCA,BNI"), each with its
+// VA_<BANK>_PREFIX company code and VA_<BANK>_SECRET callback signing key.
+// Banks missing either are left out, so their callbacks are rejected.
+func LoadVirtualAccountBanks() map[string]va.Bank {
+	banks := make(map[string]va.Bank)
+	for _, code := range strings.Split(os.Getenv("VA_BANKS"), ",") {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code == "" {
+			continue
+		}
+		bank := va.Bank{
+			Code:   code,
+			Prefix: os.Getenv("VA_" + code + "_PREFIX"),
+			Secret: os.Getenv("VA_" + code + "_SECRET"),
+		}
+		if bank.Prefix == "" || bank.Secret == "" {
+			log.Printf("⚠ Bank VA %s dilewati: VA_%s_PREFIX dan VA_%s_SECRET wajib diisi\n", code, code, code)
+			continue
+		}
+		banks[code] = bank
+	}
+	return banks
+}
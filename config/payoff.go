@@ -0,0 +1,12 @@
+package config
+
+import "main/internal/usecase"
+
+// LoadPayoffPolicy reads the early settlement terms from the environment,
+// falling back to the policy defaults
+func LoadPayoffPolicy() usecase.PayoffPolicy {
+	policy := usecase.DefaultPayoffPolicy()
+	policy.EarlyTerminationFeeRate = envFloat("EARLY_TERMINATION_FEE_RATE", policy.EarlyTerminationFeeRate)
+	policy.QuoteValidityDays = envInt("PAYOFF_QUOTE_VALIDITY_DAYS", policy.QuoteValidityDays)
+	return policy
+}
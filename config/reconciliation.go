@@ -0,0 +1,11 @@
+package config
+
+import "main/internal/usecase"
+
+// LoadReconciliationPolicy reads the statement matching windows from the environment, falling back to the policy defaults
+func LoadReconciliationPolicy() usecase.ReconciliationPolicy {
+	policy := usecase.DefaultReconciliationPolicy()
+	policy.EarlyPaymentDays = envInt("RECON_EARLY_PAYMENT_DAYS", policy.EarlyPaymentDays)
+	policy.CallbackDateDays = envInt("RECON_CALLBACK_DATE_DAYS", policy.CallbackDateDays)
+	return policy
+}
@@ -0,0 +1,12 @@
+package config
+
+import "main/internal/usecase"
+
+// LoadRestructuringPolicy reads the restructuring limits from the environment,
+// falling back to the policy defaults
+func LoadRestructuringPolicy() usecase.RestructuringPolicy {
+	policy := usecase.DefaultRestructuringPolicy()
+	policy.MaxHolidayMonths = envInt("RESTRUCTURING_MAX_HOLIDAY_MONTHS", policy.MaxHolidayMonths)
+	policy.MaxRestructures = envInt("RESTRUCTURING_MAX_COUNT", policy.MaxRestructures)
+	return policy
+}
@@ -0,0 +1,17 @@
+package config
+
+import (
+	"os"
+
+	"main/internal/usecase"
+)
+
+// LoadSettlementPolicy reads the merchant disbursement terms from the environment,
+// falling back to the policy defaults
+func LoadSettlementPolicy() usecase.SettlementPolicy {
+	policy := usecase.DefaultSettlementPolicy()
+	if account := os.Getenv("SETTLEMENT_SOURCE_ACCOUNT"); account != "" {
+		policy.SourceAccount = account
+	}
+	return policy
+}
@@ -0,0 +1,11 @@
+package config
+
+import "main/internal/usecase"
+
+// LoadWriteOffPolicy reads the write-off eligibility from the environment,
+// falling back to the policy defaults
+func LoadWriteOffPolicy() usecase.WriteOffPolicy {
+	policy := usecase.DefaultWriteOffPolicy()
+	policy.MinDaysPastDue = envInt("WRITE_OFF_MIN_DPD", policy.MinDaysPastDue)
+	return policy
+}
@@ -6,6 +6,44 @@ CREATE DATABASE IF NOT EXISTS xyz_multifinance;
 USE xyz_multifinance;
 
 -- Drop existing tables (if any)
+DROP TABLE IF EXISTS late_fee_charges;
+DROP TABLE IF EXISTS otp_challenges;
+DROP TABLE IF EXISTS contract_signatures;
+DROP TABLE IF EXISTS contract_documents;
+DROP TABLE IF EXISTS contract_templates;
+DROP TABLE IF EXISTS statement_archives;
+DROP TABLE IF EXISTS bank_statement_lines;
+DROP TABLE IF EXISTS bank_statements;
+DROP TABLE IF EXISTS payment_callbacks;
+DROP TABLE IF EXISTS virtual_accounts;
+DROP TABLE IF EXISTS notification_logs;
+DROP TABLE IF EXISTS notification_preferences;
+DROP TABLE IF EXISTS promises_to_pay;
+DROP TABLE IF EXISTS contact_attempts;
+DROP TABLE IF EXISTS collection_cases;
+DROP TABLE IF EXISTS recoveries;
+DROP TABLE IF EXISTS write_offs;
+DROP TABLE IF EXISTS restructurings;
+DROP TABLE IF EXISTS interest_accruals;
+DROP TABLE IF EXISTS journal_lines;
+DROP TABLE IF EXISTS journal_entries;
+DROP TABLE IF EXISTS settlement_items;
+DROP TABLE IF EXISTS settlement_batches;
+DROP TABLE IF EXISTS merchant_api_keys;
+DROP TABLE IF EXISTS merchant_branches;
+DROP TABLE IF EXISTS merchants;
+DROP TABLE IF EXISTS product_promos;
+DROP TABLE IF EXISTS product_rates;
+DROP TABLE IF EXISTS products;
+DROP TABLE IF EXISTS limit_holds;
+DROP TABLE IF EXISTS refunds;
+DROP TABLE IF EXISTS transaction_cancellations;
+DROP TABLE IF EXISTS payments;
+DROP TABLE IF EXISTS installments;
+DROP TABLE IF EXISTS fraud_assessments;
+DROP TABLE IF EXISTS watchlist_hits;
+DROP TABLE IF EXISTS watchlist_entries;
+DROP TABLE IF EXISTS kyc_verifications;
 DROP TABLE IF EXISTS transactions;
 DROP TABLE IF EXISTS consumer_limits;
 DROP TABLE IF EXISTS consumers;
@@ -19,9 +57,15 @@ CREATE TABLE consumers (
     legal_name VARCHAR(255) NOT NULL COMMENT 'Nama Resmi di KTP',
     place_of_birth VARCHAR(255) COMMENT 'Tempat Lahir',
     date_of_birth DATETIME COMMENT 'Tanggal Lahir',
+    gender VARCHAR(10) COMMENT 'MALE, FEMALE (diturunkan dari NIK)',
+    marital_status VARCHAR(20) DEFAULT 'SINGLE' COMMENT 'SINGLE, MARRIED, DIVORCED, WIDOWED',
     salary DECIMAL(15, 2) NOT NULL COMMENT 'Gaji Konsumen',
+    phone_number VARCHAR(20) COMMENT 'Nomor HP untuk notifikasi SMS dan WhatsApp',
+    email VARCHAR(255) COMMENT 'Email untuk notifikasi',
     ktp_photo LONGTEXT COMMENT 'Foto KTP (Base64)',
     selfie_photo LONGTEXT COMMENT 'Foto Selfie Konsumen (Base64)',
+    kyc_status VARCHAR(20) DEFAULT 'PENDING' COMMENT 'PENDING, VERIFIED, REJECTED, MANUAL_REVIEW',
+    written_off BOOLEAN DEFAULT FALSE COMMENT 'Memiliki kontrak hapus buku, tidak dapat diberi limit baru',
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     deleted_at DATETIME NULL,
@@ -29,9 +73,76 @@ CREATE TABLE consumers (
     INDEX idx_nik (nik),
     INDEX idx_created_at (created_at),
     INDEX idx_deleted_at (deleted_at),
+    INDEX idx_kyc_status (kyc_status),
+    INDEX idx_written_off (written_off),
     CONSTRAINT check_salary CHECK (salary >= 0)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Tabel Konsumen PT XYZ Multifinance';
 
+-- Table: KYC Verifications
+-- History of automatic and manual KYC checks per consumer
+CREATE TABLE kyc_verifications (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    consumer_id BIGINT UNSIGNED NOT NULL,
+    status VARCHAR(20) NOT NULL COMMENT 'VERIFIED, REJECTED, MANUAL_REVIEW',
+    ocr_nik VARCHAR(16) COMMENT 'NIK hasil OCR KTP',
+    ocr_name VARCHAR(255) COMMENT 'Nama hasil OCR KTP',
+    face_match_score DECIMAL(5, 4) COMMENT 'Skor pencocokan wajah 0-1',
+    liveness_score DECIMAL(5, 4) COMMENT 'Skor liveness 0-1',
+    reason VARCHAR(500) COMMENT 'Alasan keputusan',
+    reviewed_by VARCHAR(255) COMMENT 'Analis yang melakukan review manual',
+    reviewed_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (consumer_id) REFERENCES consumers(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    INDEX idx_consumer_id (consumer_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Riwayat Verifikasi KYC';
+
+-- Table: Watchlist Entries
+-- Internal fraud blacklist and regulator-supplied lists
+CREATE TABLE watchlist_entries (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    source VARCHAR(50) NOT NULL COMMENT 'INTERNAL_FRAUD, OJK, PPATK, ...',
+    list_type VARCHAR(20) NOT NULL COMMENT 'BLACKLIST, WATCHLIST',
+    nik VARCHAR(16) COMMENT 'NIK (opsional untuk daftar regulator)',
+    full_name VARCHAR(255) NOT NULL,
+    date_of_birth DATETIME NULL,
+    reason VARCHAR(500),
+    active BOOLEAN DEFAULT TRUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    INDEX idx_source (source),
+    INDEX idx_nik (nik),
+    INDEX idx_date_of_birth (date_of_birth),
+    INDEX idx_active (active),
+    CONSTRAINT check_list_type CHECK (list_type IN ('BLACKLIST', 'WATCHLIST'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Daftar Hitam dan Watchlist';
+
+-- Table: Watchlist Hits
+-- Every screening match, kept for compliance review
+CREATE TABLE watchlist_hits (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    watchlist_entry_id BIGINT UNSIGNED NOT NULL,
+    consumer_id BIGINT UNSIGNED DEFAULT 0 COMMENT '0 jika disaring sebelum registrasi',
+    subject_nik VARCHAR(16),
+    subject_name VARCHAR(255),
+    context VARCHAR(20) NOT NULL COMMENT 'REGISTRATION, TRANSACTION',
+    reference VARCHAR(255) COMMENT 'Nomor kontrak untuk screening transaksi',
+    match_type VARCHAR(20) NOT NULL COMMENT 'EXACT_NIK, FUZZY_NAME_DOB',
+    score DECIMAL(5, 4),
+    action VARCHAR(10) NOT NULL COMMENT 'REVIEW, BLOCK',
+    status VARCHAR(20) DEFAULT 'OPEN' COMMENT 'OPEN, CLEARED, CONFIRMED',
+    reviewed_by VARCHAR(255),
+    review_note VARCHAR(500),
+    reviewed_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (watchlist_entry_id) REFERENCES watchlist_entries(id),
+    INDEX idx_subject_nik (subject_nik),
+    INDEX idx_consumer_id (consumer_id),
+    INDEX idx_status (status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Hasil Screening Watchlist';
+
 -- Table: Consumer Limits (Credit Limits)
 -- Tracks credit limits per tenor (1, 2, 3, 6 months) for each consumer
 CREATE TABLE consumer_limits (
@@ -40,6 +151,7 @@ CREATE TABLE consumer_limits (
     tenor INT NOT NULL COMMENT 'Tenor in months: 1, 2, 3, 6',
     limit_amount DECIMAL(15, 2) NOT NULL COMMENT 'Credit limit amount',
     used_amount DECIMAL(15, 2) DEFAULT 0 COMMENT 'Used amount from limit',
+    held_amount DECIMAL(15, 2) DEFAULT 0 COMMENT 'Limit yang ditahan oleh hold merchant',
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     
@@ -49,9 +161,113 @@ CREATE TABLE consumer_limits (
     INDEX idx_tenor (tenor),
     CONSTRAINT check_tenor CHECK (tenor IN (1, 2, 3, 6)),
     CONSTRAINT check_limit_amount CHECK (limit_amount > 0),
-    CONSTRAINT check_used_amount CHECK (used_amount >= 0)
+    CONSTRAINT check_used_amount CHECK (used_amount >= 0),
+    CONSTRAINT check_held_amount CHECK (held_amount >= 0)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Tabel Limit Kredit Konsumen';
 
+-- Table: Products (Financing Product Catalogue)
+-- Tenors, pricing and OTR range per financing product
+CREATE TABLE products (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(50) NOT NULL UNIQUE COMMENT 'Kode produk, mis. GADGET, MOTOR',
+    name VARCHAR(255) NOT NULL,
+    category VARCHAR(50) COMMENT 'ELECTRONICS, MOTORCYCLE, WHITE_GOODS, ...',
+    admin_fee_type VARCHAR(10) NOT NULL COMMENT 'FLAT, PERCENT',
+    admin_fee_value DECIMAL(15, 4) DEFAULT 0 COMMENT 'Nominal untuk FLAT, persen OTR untuk PERCENT',
+    min_otr DECIMAL(15, 2) DEFAULT 0,
+    max_otr DECIMAL(15, 2) DEFAULT 0 COMMENT '0 berarti tanpa batas',
+    min_down_payment_pct DECIMAL(5, 2) DEFAULT 0 COMMENT 'Minimal DP (% dari OTR)',
+    valid_from DATETIME NOT NULL,
+    valid_until DATETIME NULL,
+    active BOOLEAN DEFAULT TRUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    CONSTRAINT check_admin_fee_type CHECK (admin_fee_type IN ('FLAT', 'PERCENT')),
+    CONSTRAINT check_min_down_payment_pct CHECK (min_down_payment_pct >= 0 AND min_down_payment_pct < 100)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Katalog Produk Pembiayaan';
+
+-- Table: Product Rates
+-- Flat monthly interest rate per tenor offered by a product
+CREATE TABLE product_rates (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    product_id BIGINT UNSIGNED NOT NULL,
+    tenor INT NOT NULL,
+    interest_rate DECIMAL(7, 4) NOT NULL COMMENT 'Bunga flat per bulan (%)',
+
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    UNIQUE KEY idx_product_tenor (product_id, tenor),
+    CONSTRAINT check_product_tenor CHECK (tenor IN (1, 2, 3, 6))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Suku Bunga Produk per Tenor';
+
+-- Table: Product Promos
+-- Time-boxed pricing overrides, e.g. 0% for 3 months
+CREATE TABLE product_promos (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    product_id BIGINT UNSIGNED NOT NULL,
+    code VARCHAR(50) NOT NULL,
+    tenor INT DEFAULT 0 COMMENT '0 berlaku untuk semua tenor',
+    interest_rate DECIMAL(7, 4) DEFAULT 0 COMMENT 'Bunga flat per bulan (%) selama promo',
+    waive_admin_fee BOOLEAN DEFAULT FALSE,
+    valid_from DATETIME NOT NULL,
+    valid_until DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    INDEX idx_product_validity (product_id, valid_from, valid_until)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Promo Produk';
+
+-- Table: Merchants
+-- Dealers and online stores that originate financed contracts
+CREATE TABLE merchants (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(50) NOT NULL UNIQUE COMMENT 'Kode merchant',
+    name VARCHAR(255) NOT NULL,
+    category VARCHAR(50) COMMENT 'MOTOR_DEALER, ELECTRONICS_STORE, ONLINE_STORE, ...',
+    bank_name VARCHAR(100) COMMENT 'Bank rekening settlement',
+    bank_account_number VARCHAR(50) COMMENT 'Nomor rekening settlement',
+    bank_account_name VARCHAR(255) COMMENT 'Nama pemilik rekening',
+    mdr_rate DECIMAL(5, 2) DEFAULT 0 COMMENT 'Merchant discount rate (% dari nilai pembiayaan)',
+    status VARCHAR(20) DEFAULT 'ACTIVE' COMMENT 'ACTIVE, SUSPENDED, TERMINATED',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    INDEX idx_merchant_status (status),
+    CONSTRAINT check_merchant_status CHECK (status IN ('ACTIVE', 'SUSPENDED', 'TERMINATED')),
+    CONSTRAINT check_mdr_rate CHECK (mdr_rate >= 0 AND mdr_rate < 100)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Registri Merchant / Dealer';
+
+-- Table: Merchant Branches
+-- Outlets of a merchant
+CREATE TABLE merchant_branches (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    merchant_id BIGINT UNSIGNED NOT NULL,
+    code VARCHAR(50) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    city VARCHAR(100),
+    address TEXT,
+    active BOOLEAN DEFAULT TRUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (merchant_id) REFERENCES merchants(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    UNIQUE KEY unique_merchant_branch (merchant_id, code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Cabang Merchant';
+
+-- Table: Merchant API Keys
+-- Merchant-scoped API keys; only the SHA-256 hash is stored
+CREATE TABLE merchant_api_keys (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    merchant_id BIGINT UNSIGNED NOT NULL,
+    prefix VARCHAR(16) COMMENT 'Awalan key untuk identifikasi',
+    key_hash CHAR(64) NOT NULL UNIQUE COMMENT 'SHA-256 dari API key',
+    last_used_at DATETIME NULL,
+    revoked_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (merchant_id) REFERENCES merchants(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    INDEX idx_merchant_api_key_merchant (merchant_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='API Key Merchant';
+
 -- Table: Transactions (Financial Transactions)
 -- Records all financing transactions (purchases with installments)
 CREATE TABLE transactions (
@@ -60,11 +276,26 @@ CREATE TABLE transactions (
     contract_number VARCHAR(255) NOT NULL UNIQUE COMMENT 'Nomor Kontrak Unik',
     tenor INT NOT NULL COMMENT 'Tenor in months: 1, 2, 3, 6',
     otr DECIMAL(15, 2) NOT NULL COMMENT 'On The Road (OTR) Price',
+    down_payment DECIMAL(15, 2) DEFAULT 0 COMMENT 'Uang muka (DP); limit dan angsuran memakai OTR - DP',
     admin_fee DECIMAL(15, 2) DEFAULT 0 COMMENT 'Admin Fee',
     installment_amount DECIMAL(15, 2) NOT NULL COMMENT 'Jumlah Cicilan',
     interest_amount DECIMAL(15, 2) DEFAULT 0 COMMENT 'Bunga',
     asset_name VARCHAR(255) COMMENT 'Nama Aset yang Dibeli',
-    status VARCHAR(50) DEFAULT 'ACTIVE' COMMENT 'ACTIVE, COMPLETED, DEFAULTED',
+    product_code VARCHAR(50) COMMENT 'Kode produk pembiayaan',
+    promo_code VARCHAR(50) COMMENT 'Promo yang diterapkan saat pricing',
+    interest_rate DECIMAL(7, 4) DEFAULT 0 COMMENT 'Bunga flat per bulan (%)',
+    merchant_id BIGINT UNSIGNED DEFAULT 0 COMMENT 'Merchant asal kontrak',
+    branch_id BIGINT UNSIGNED DEFAULT 0 COMMENT 'Cabang merchant asal kontrak',
+    status VARCHAR(50) DEFAULT 'ACTIVE' COMMENT 'ACTIVE, COMPLETED, DEFAULTED, PENDING_REVIEW, PENDING_SIGNATURE, PENDING_DOWN_PAYMENT, REJECTED, CANCELLED, WRITTEN_OFF',
+    risk_score INT DEFAULT 0 COMMENT 'Skor risiko fraud',
+    risk_decision VARCHAR(10) COMMENT 'ALLOW, REVIEW, DENY',
+    days_past_due INT DEFAULT 0 COMMENT 'Hari keterlambatan terlama',
+    collectibility TINYINT DEFAULT 1 COMMENT 'Kolektibilitas OJK 1 (Lancar) - 5 (Macet)',
+    late_fee_amount DECIMAL(15, 2) DEFAULT 0 COMMENT 'Total denda keterlambatan',
+    restructure_count INT DEFAULT 0 COMMENT 'Jumlah restrukturisasi yang disetujui',
+    restructured_at DATE NULL COMMENT 'Tanggal efektif restrukturisasi terakhir',
+    activated_at DATETIME NULL COMMENT 'Waktu kontrak mulai berjalan; dasar settlement merchant',
+    otp_challenge_id BIGINT UNSIGNED NULL COMMENT 'Challenge OTP persetujuan konsumen saat transaksi dibuat',
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     
@@ -75,11 +306,670 @@ CREATE TABLE transactions (
     INDEX idx_status (status),
     INDEX idx_created_at (created_at),
     INDEX idx_tenor (tenor),
+    INDEX idx_merchant_id (merchant_id),
+    INDEX idx_activated_at (activated_at),
+    INDEX idx_merchant_created (merchant_id, created_at),
+    INDEX idx_product_code (product_code),
+    INDEX idx_collectibility (collectibility),
+    INDEX idx_otp_challenge_id (otp_challenge_id),
     CONSTRAINT check_otr CHECK (otr > 0),
+    CONSTRAINT check_down_payment CHECK (down_payment >= 0 AND down_payment < otr),
     CONSTRAINT check_installment CHECK (installment_amount > 0),
-    CONSTRAINT check_status CHECK (status IN ('ACTIVE', 'COMPLETED', 'DEFAULTED'))
+    CONSTRAINT check_status CHECK (status IN ('ACTIVE', 'COMPLETED', 'DEFAULTED', 'PENDING_REVIEW', 'PENDING_SIGNATURE', 'PENDING_DOWN_PAYMENT', 'REJECTED', 'CANCELLED', 'WRITTEN_OFF'))
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Tabel Transaksi Pembiayaan';
 
+-- Table: Fraud Assessments
+-- Fraud velocity rule outcome per transaction request (denied requests have transaction_id = 0)
+CREATE TABLE fraud_assessments (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    transaction_id BIGINT UNSIGNED DEFAULT 0,
+    consumer_id BIGINT UNSIGNED NOT NULL,
+    contract_number VARCHAR(255),
+    score INT NOT NULL,
+    decision VARCHAR(10) NOT NULL COMMENT 'ALLOW, REVIEW, DENY',
+    triggered_rules TEXT COMMENT 'JSON daftar rule yang terpicu',
+    reviewed_by VARCHAR(255),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+
+    INDEX idx_transaction_id (transaction_id),
+    INDEX idx_consumer_id (consumer_id),
+    INDEX idx_contract_number (contract_number)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Penilaian Risiko Fraud';
+
+-- Table: Installments
+-- Monthly repayment schedule per transaction, aged daily for DPD and late fees
+CREATE TABLE installments (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    transaction_id BIGINT UNSIGNED NOT NULL,
+    sequence INT NOT NULL COMMENT 'Angsuran ke-',
+    due_date DATE NOT NULL COMMENT 'Tanggal jatuh tempo',
+    principal_amount DECIMAL(15, 2) NOT NULL COMMENT 'Porsi pokok',
+    interest_amount DECIMAL(15, 2) DEFAULT 0 COMMENT 'Porsi bunga',
+    amount DECIMAL(15, 2) NOT NULL COMMENT 'Total tagihan angsuran',
+    paid_amount DECIMAL(15, 2) DEFAULT 0 COMMENT 'Uang yang diterima untuk angsuran',
+    waived_amount DECIMAL(15, 2) DEFAULT 0 COMMENT 'Bunga yang dibebaskan saat pelunasan dipercepat',
+    paid_at DATETIME,
+    late_fee DECIMAL(15, 2) DEFAULT 0 COMMENT 'Denda keterlambatan',
+    late_fee_paid DECIMAL(15, 2) DEFAULT 0 COMMENT 'Denda yang sudah dibayar',
+    days_past_due INT DEFAULT 0,
+    status VARCHAR(20) DEFAULT 'UNPAID' COMMENT 'UNPAID, PARTIAL, PAID, CANCELLED, RESCHEDULED',
+    restructuring_id BIGINT UNSIGNED DEFAULT 0 COMMENT 'Restrukturisasi yang membentuk jadwal ini, 0 untuk jadwal awal',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    UNIQUE KEY unique_transaction_sequence (transaction_id, sequence),
+    INDEX idx_due_date (due_date),
+    INDEX idx_status (status),
+    INDEX idx_restructuring_id (restructuring_id),
+    CONSTRAINT check_installment_status CHECK (status IN ('UNPAID', 'PARTIAL', 'PAID', 'CANCELLED', 'RESCHEDULED'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Jadwal Angsuran';
+
+-- Table: Late Fee Charges
+-- Late fee of an installment from the business date the daily aging or a restructuring changed it
+CREATE TABLE late_fee_charges (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    installment_id BIGINT UNSIGNED NOT NULL,
+    transaction_id BIGINT UNSIGNED NOT NULL,
+    business_date DATE NOT NULL COMMENT 'Tanggal bisnis perubahan denda',
+    late_fee DECIMAL(15, 2) NOT NULL COMMENT 'Total denda angsuran sejak tanggal ini',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (installment_id) REFERENCES installments(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    UNIQUE KEY idx_late_fee_charge_date (installment_id, business_date),
+    INDEX idx_transaction_id (transaction_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Riwayat Denda Keterlambatan';
+
+-- Table: Payments
+-- Money received against a transaction
+CREATE TABLE payments (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    transaction_id BIGINT UNSIGNED NOT NULL,
+    consumer_id BIGINT UNSIGNED NOT NULL,
+    type VARCHAR(20) NOT NULL COMMENT 'SETTLEMENT, INSTALLMENT, ADMIN_FEE, DOWN_PAYMENT',
+    amount DECIMAL(15, 2) NOT NULL,
+    reference VARCHAR(255) NOT NULL COMMENT 'Referensi bank / kanal pembayaran, diposting sekali',
+    paid_at DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    INDEX idx_transaction_id (transaction_id),
+    INDEX idx_consumer_id (consumer_id),
+    UNIQUE KEY unique_payment_reference (reference),
+    CONSTRAINT check_payment_amount CHECK (amount > 0)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Pembayaran';
+
+-- Table: Transaction Cancellations
+-- Full or partial cancellation within the cooling-off window
+CREATE TABLE transaction_cancellations (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    transaction_id BIGINT UNSIGNED NOT NULL,
+    reason_code VARCHAR(30) NOT NULL COMMENT 'COOLING_OFF, GOODS_RETURNED, MERCHANT_ERROR, SIGNATURE_EXPIRED',
+    note TEXT,
+    amount DECIMAL(15, 2) NOT NULL COMMENT 'Bagian OTR yang dibatalkan',
+    partial BOOLEAN DEFAULT FALSE,
+    merchant_amount DECIMAL(15, 2) DEFAULT 0 COMMENT 'Pokok yang dibatalkan dan DP yang dikembalikan, ditagih ke merchant',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    INDEX idx_transaction_id (transaction_id),
+    INDEX idx_cancellation_created (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Pembatalan Transaksi';
+
+-- Table: Refunds
+-- Money owed back to consumers, e.g. admin fee of a cancelled contract
+CREATE TABLE refunds (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    transaction_id BIGINT UNSIGNED NOT NULL,
+    consumer_id BIGINT UNSIGNED NOT NULL,
+    cancellation_id BIGINT UNSIGNED,
+    amount DECIMAL(15, 2) NOT NULL,
+    reason VARCHAR(255),
+    status VARCHAR(20) DEFAULT 'PENDING' COMMENT 'PENDING, PAID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    INDEX idx_transaction_id (transaction_id),
+    INDEX idx_consumer_id (consumer_id),
+    INDEX idx_status (status),
+    CONSTRAINT check_refund_amount CHECK (amount > 0)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Pengembalian Dana';
+
+-- Table: Limit Holds
+-- Limit reserved at merchant checkout, captured into a transaction when the goods ship
+CREATE TABLE limit_holds (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    consumer_id BIGINT UNSIGNED NOT NULL,
+    tenor INT NOT NULL,
+    amount DECIMAL(15, 2) NOT NULL,
+    merchant_id BIGINT UNSIGNED DEFAULT 0,
+    reference VARCHAR(255) COMMENT 'Nomor order / keranjang merchant',
+    status VARCHAR(20) DEFAULT 'AUTHORIZED' COMMENT 'AUTHORIZED, CAPTURED, VOIDED, EXPIRED',
+    expires_at DATETIME NOT NULL,
+    transaction_id BIGINT UNSIGNED DEFAULT 0 COMMENT 'Transaksi hasil capture',
+    closed_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (consumer_id) REFERENCES consumers(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    INDEX idx_consumer_id (consumer_id),
+    INDEX idx_merchant_id (merchant_id),
+    INDEX idx_status_expires (status, expires_at),
+    CONSTRAINT check_hold_amount CHECK (amount > 0),
+    CONSTRAINT check_hold_status CHECK (status IN ('AUTHORIZED', 'CAPTURED', 'VOIDED', 'EXPIRED'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Hold Limit Checkout Merchant';
+
+-- Table: Settlement Batches
+-- Daily disbursement per merchant: financed amount (OTR - DP) minus MDR
+CREATE TABLE settlement_batches (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    batch_number VARCHAR(100) NOT NULL UNIQUE COMMENT 'STL-YYYYMMDD-KODEMERCHANT, referensi transfer bank',
+    merchant_id BIGINT UNSIGNED NOT NULL,
+    business_date DATE NOT NULL,
+    transaction_count INT DEFAULT 0,
+    gross_amount DECIMAL(15, 2) DEFAULT 0 COMMENT 'Total nilai pembiayaan',
+    cancelled_amount DECIMAL(15, 2) DEFAULT 0 COMMENT 'Total pembatalan yang ditagih ke merchant',
+    mdr_amount DECIMAL(15, 2) DEFAULT 0 COMMENT 'Total MDR',
+    carried_amount DECIMAL(15, 2) DEFAULT 0 COMMENT 'Saldo negatif dari batch CARRIED_FORWARD sebelumnya',
+    net_amount DECIMAL(15, 2) DEFAULT 0 COMMENT 'Jumlah yang ditransfer ke merchant',
+    bank_name VARCHAR(100) COMMENT 'Rekening tujuan saat batch dibuat',
+    bank_account_number VARCHAR(50),
+    bank_account_name VARCHAR(255),
+    status VARCHAR(20) DEFAULT 'PENDING' COMMENT 'PENDING, EXPORTED, PAID, FAILED, CARRIED_FORWARD',
+    carried_to_batch_id BIGINT UNSIGNED NULL COMMENT 'Batch yang mengambil alih saldo batch CARRIED_FORWARD',
+    bank_reference VARCHAR(100) COMMENT 'Referensi dari file konfirmasi bank',
+    failure_reason VARCHAR(255),
+    exported_at DATETIME NULL,
+    paid_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (merchant_id) REFERENCES merchants(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    INDEX idx_settlement_business_date (business_date),
+    INDEX idx_settlement_merchant (merchant_id),
+    INDEX idx_settlement_status (status),
+    INDEX idx_settlement_carried_to (carried_to_batch_id),
+    CONSTRAINT check_settlement_net CHECK (net_amount = gross_amount - cancelled_amount - mdr_amount + carried_amount),
+    CONSTRAINT check_settlement_status CHECK (status IN ('PENDING', 'EXPORTED', 'PAID', 'FAILED', 'CARRIED_FORWARD'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Batch Settlement Merchant';
+
+-- Table: Settlement Items
+-- Contracts paid out in a settlement batch; each contract is settled once
+CREATE TABLE settlement_items (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    batch_id BIGINT UNSIGNED NOT NULL,
+    transaction_id BIGINT UNSIGNED NOT NULL,
+    cancellation_id BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '0 untuk nilai pembiayaan kontrak, selain itu pembatalan yang ditagih',
+    contract_number VARCHAR(255),
+    gross_amount DECIMAL(15, 2) DEFAULT 0,
+    cancelled_amount DECIMAL(15, 2) DEFAULT 0 COMMENT 'Pembatalan yang ditagih ke merchant',
+    mdr_amount DECIMAL(15, 2) DEFAULT 0,
+    net_amount DECIMAL(15, 2) DEFAULT 0,
+
+    FOREIGN KEY (batch_id) REFERENCES settlement_batches(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    UNIQUE INDEX idx_settlement_item_source (transaction_id, cancellation_id),
+    INDEX idx_settlement_item_batch (batch_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Detail Settlement Merchant';
+
+-- Table: Journal Entries
+-- Double-entry general ledger; one balanced entry per business event
+CREATE TABLE journal_entries (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    reference VARCHAR(100) NOT NULL UNIQUE COMMENT 'Referensi event, mencegah posting ganda (ACT-, CNL-, PAYOFF-, MDR-, PAY-, ACR-, RVA-, RST-, WO-, REC-)',
+    event_type VARCHAR(30) NOT NULL COMMENT 'ACTIVATION, CANCELLATION, PAYOFF, MDR, MERCHANT_PAYMENT, ACCRUAL, ACCRUAL_REVERSAL, RESTRUCTURING, WRITE_OFF, RECOVERY, INSTALLMENT_PAYMENT, PAYMENT_SUSPENSE, SUSPENSE_REFUND, ADMIN_FEE',
+    transaction_id BIGINT UNSIGNED DEFAULT 0 COMMENT '0 untuk event level merchant',
+    description VARCHAR(255),
+    posting_date DATE NOT NULL,
+    total_amount DECIMAL(15, 2) NOT NULL COMMENT 'Total debit = total kredit',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+
+    INDEX idx_journal_posting_date (posting_date),
+    INDEX idx_journal_event_type (event_type),
+    INDEX idx_journal_transaction (transaction_id),
+    CONSTRAINT check_journal_total CHECK (total_amount > 0)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Jurnal Buku Besar';
+
+-- Table: Journal Lines
+-- Debit or credit of a journal entry on an account of the chart of accounts
+CREATE TABLE journal_lines (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    entry_id BIGINT UNSIGNED NOT NULL,
+    account_code VARCHAR(10) NOT NULL COMMENT '1100 Kas, 1200 Piutang, 2100 Utang Merchant, 2200 Utang Refund, 2300 Bunga Ditangguhkan, 2400 Titipan Pembayaran, 41xx-46xx Pendapatan, 5100 Beban Hapus Buku',
+    debit DECIMAL(15, 2) DEFAULT 0,
+    credit DECIMAL(15, 2) DEFAULT 0,
+    memo VARCHAR(255),
+
+    FOREIGN KEY (entry_id) REFERENCES journal_entries(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    INDEX idx_journal_line_entry (entry_id),
+    INDEX idx_journal_line_account (account_code),
+    CONSTRAINT check_journal_line_amount CHECK (debit >= 0 AND credit >= 0 AND (debit = 0) <> (credit = 0))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Baris Jurnal Buku Besar';
+
+-- Table: Interest Accruals
+-- Interest income recognised per contract and business date (effective-interest method)
+CREATE TABLE interest_accruals (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    transaction_id BIGINT UNSIGNED NOT NULL,
+    accrual_date DATE NOT NULL,
+    amount DECIMAL(15, 2) NOT NULL COMMENT 'Bunga diakui pada tanggal ini, negatif untuk koreksi',
+    cumulative_amount DECIMAL(15, 2) NOT NULL COMMENT 'Total bunga diakui sampai tanggal ini',
+    revision INT DEFAULT 1 COMMENT 'Naik setiap tanggal yang sama dijalankan ulang dengan hasil berbeda',
+    reversed_at DATETIME NULL COMMENT 'Diisi saat akrual dibalik karena hapus buku',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    UNIQUE KEY idx_accrual_contract_date (transaction_id, accrual_date),
+    INDEX idx_accrual_date (accrual_date)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Akrual Pendapatan Bunga';
+
+-- Table: Restructurings
+-- Rescheduling of a distressed contract's outstanding balance on new terms, approved by a second officer
+CREATE TABLE restructurings (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    transaction_id BIGINT UNSIGNED NOT NULL,
+    reason VARCHAR(500),
+    old_tenor INT,
+    new_tenor INT NOT NULL COMMENT '1, 2, 3, 6 bulan',
+    old_interest_rate DECIMAL(7, 4),
+    new_interest_rate DECIMAL(7, 4) COMMENT 'Bunga flat per bulan (%)',
+    holiday_months INT DEFAULT 0 COMMENT 'Masa tenggang sebelum jadwal baru dimulai',
+    outstanding_principal DECIMAL(15, 2) COMMENT 'Sisa pokok',
+    capitalised_interest DECIMAL(15, 2) COMMENT 'Bunga jatuh tempo yang dikapitalisasi ke pokok baru',
+    waived_late_fees DECIMAL(15, 2) COMMENT 'Denda yang dihapuskan',
+    new_principal DECIMAL(15, 2),
+    new_interest_amount DECIMAL(15, 2),
+    new_installment_amount DECIMAL(15, 2),
+    status VARCHAR(20) DEFAULT 'PENDING_APPROVAL' COMMENT 'PENDING_APPROVAL, APPROVED, REJECTED',
+    requested_by VARCHAR(255) NOT NULL,
+    reviewed_by VARCHAR(255) COMMENT 'Harus berbeda dari pengaju',
+    review_note VARCHAR(500),
+    reviewed_at DATETIME NULL,
+    effective_date DATE NULL COMMENT 'Diisi saat disetujui',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    INDEX idx_restructuring_transaction (transaction_id),
+    INDEX idx_restructuring_status (status),
+    CONSTRAINT check_restructuring_status CHECK (status IN ('PENDING_APPROVAL', 'APPROVED', 'REJECTED'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Restrukturisasi Kredit';
+
+-- Table: Write-offs
+-- DEFAULTED contracts taken off the books, approved by a second officer
+CREATE TABLE write_offs (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    transaction_id BIGINT UNSIGNED NOT NULL,
+    consumer_id BIGINT UNSIGNED NOT NULL,
+    vintage VARCHAR(7) COMMENT 'Bulan pemesanan kontrak (YYYY-MM)',
+    days_past_due INT COMMENT 'DPD saat diajukan',
+    reason VARCHAR(500),
+    receivable_amount DECIMAL(15, 2) COMMENT 'Piutang yang dihapusbukukan',
+    deferred_interest DECIMAL(15, 2) COMMENT 'Bunga ditangguhkan yang ikut dihapus',
+    loss_amount DECIMAL(15, 2) COMMENT 'Beban penghapusan piutang',
+    reversed_accruals DECIMAL(15, 2) COMMENT 'Akrual bunga yang dibalik',
+    recovered_amount DECIMAL(15, 2) DEFAULT 0 COMMENT 'Total pemulihan setelah hapus buku',
+    status VARCHAR(20) DEFAULT 'PENDING_APPROVAL' COMMENT 'PENDING_APPROVAL, APPROVED, REJECTED',
+    requested_by VARCHAR(255) NOT NULL,
+    reviewed_by VARCHAR(255) COMMENT 'Harus berbeda dari pengaju',
+    review_note VARCHAR(500),
+    reviewed_at DATETIME NULL,
+    written_off_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    FOREIGN KEY (consumer_id) REFERENCES consumers(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    INDEX idx_write_off_transaction (transaction_id),
+    INDEX idx_write_off_vintage (vintage),
+    INDEX idx_write_off_status (status),
+    CONSTRAINT check_write_off_status CHECK (status IN ('PENDING_APPROVAL', 'APPROVED', 'REJECTED'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Hapus Buku Piutang';
+
+-- Table: Recoveries
+-- Money received on written-off contracts from collections or asset repossession
+CREATE TABLE recoveries (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    write_off_id BIGINT UNSIGNED NOT NULL,
+    transaction_id BIGINT UNSIGNED NOT NULL,
+    source VARCHAR(20) NOT NULL COMMENT 'COLLECTION, REPOSSESSION',
+    amount DECIMAL(15, 2) NOT NULL,
+    reference VARCHAR(255),
+    received_at DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (write_off_id) REFERENCES write_offs(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    INDEX idx_recovery_write_off (write_off_id),
+    INDEX idx_recovery_transaction (transaction_id),
+    CONSTRAINT check_recovery_amount CHECK (amount > 0),
+    CONSTRAINT check_recovery_source CHECK (source IN ('COLLECTION', 'REPOSSESSION'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Pemulihan Piutang Hapus Buku';
+
+-- Collections work queue: one case per overdue contract, ranked by DPD, amount and risk
+CREATE TABLE collection_cases (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    transaction_id BIGINT UNSIGNED NOT NULL,
+    consumer_id BIGINT UNSIGNED NOT NULL,
+    contract_number VARCHAR(255),
+    days_past_due INT DEFAULT 0,
+    overdue_amount DECIMAL(15, 2) DEFAULT 0 COMMENT 'Angsuran jatuh tempo belum dibayar termasuk denda',
+    outstanding_amount DECIMAL(15, 2) DEFAULT 0 COMMENT 'Seluruh sisa kewajiban',
+    risk_score INT DEFAULT 0,
+    priority DECIMAL(7, 2) DEFAULT 0 COMMENT 'Prioritas 0-100, dikerjakan dari yang tertinggi',
+    assigned_to VARCHAR(255),
+    assigned_at DATETIME NULL,
+    last_contact_at DATETIME NULL,
+    last_outcome VARCHAR(30),
+    broken_promises INT DEFAULT 0,
+    status VARCHAR(20) DEFAULT 'OPEN' COMMENT 'OPEN, RESOLVED',
+    resolved_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    UNIQUE KEY unique_collection_case_transaction (transaction_id),
+    INDEX idx_collection_case_consumer (consumer_id),
+    INDEX idx_collection_case_priority (priority),
+    INDEX idx_collection_case_assigned_to (assigned_to),
+    INDEX idx_collection_case_status (status),
+    CONSTRAINT check_collection_case_status CHECK (status IN ('OPEN', 'RESOLVED'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Antrian Penagihan';
+
+CREATE TABLE contact_attempts (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    case_id BIGINT UNSIGNED NOT NULL,
+    collector VARCHAR(255) NOT NULL,
+    channel VARCHAR(20) NOT NULL COMMENT 'PHONE, WHATSAPP, SMS, EMAIL, FIELD_VISIT',
+    outcome VARCHAR(30) NOT NULL COMMENT 'NO_ANSWER, WRONG_NUMBER, CONTACTED, PROMISE_TO_PAY, REFUSED_TO_PAY, ALREADY_PAID',
+    note VARCHAR(500),
+    attempted_at DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (case_id) REFERENCES collection_cases(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    INDEX idx_contact_attempt_case (case_id),
+    CONSTRAINT check_contact_attempt_channel CHECK (channel IN ('PHONE', 'WHATSAPP', 'SMS', 'EMAIL', 'FIELD_VISIT'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Riwayat Kontak Penagihan';
+
+CREATE TABLE promises_to_pay (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    case_id BIGINT UNSIGNED NOT NULL,
+    transaction_id BIGINT UNSIGNED NOT NULL,
+    contact_attempt_id BIGINT UNSIGNED,
+    promised_amount DECIMAL(15, 2) NOT NULL,
+    promised_date DATE NOT NULL,
+    paid_baseline DECIMAL(15, 2) DEFAULT 0 COMMENT 'Total pembayaran angsuran saat janji dibuat',
+    paid_amount DECIMAL(15, 2) DEFAULT 0 COMMENT 'Pembayaran sejak janji dibuat',
+    status VARCHAR(20) DEFAULT 'OPEN' COMMENT 'OPEN, KEPT, BROKEN',
+    created_by VARCHAR(255),
+    closed_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (case_id) REFERENCES collection_cases(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    INDEX idx_promise_case (case_id),
+    INDEX idx_promise_transaction (transaction_id),
+    INDEX idx_promise_date (promised_date),
+    INDEX idx_promise_status (status),
+    CONSTRAINT check_promise_amount CHECK (promised_amount > 0),
+    CONSTRAINT check_promise_status CHECK (status IN ('OPEN', 'KEPT', 'BROKEN'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Janji Bayar';
+
+-- How each consumer wants to be notified; consumers without a row get every channel in Indonesian
+CREATE TABLE notification_preferences (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    consumer_id BIGINT UNSIGNED NOT NULL,
+    language VARCHAR(2) NOT NULL DEFAULT 'id' COMMENT 'id, en',
+    sms_enabled BOOLEAN DEFAULT TRUE,
+    whatsapp_enabled BOOLEAN DEFAULT TRUE,
+    email_enabled BOOLEAN DEFAULT TRUE,
+    opted_out BOOLEAN DEFAULT FALSE COMMENT 'Tidak menerima notifikasi di semua saluran',
+    opted_out_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (consumer_id) REFERENCES consumers(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    UNIQUE KEY unique_notification_preference_consumer (consumer_id),
+    INDEX idx_notification_preference_opted_out (opted_out),
+    CONSTRAINT check_notification_language CHECK (language IN ('id', 'en'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Preferensi Notifikasi Konsumen';
+
+CREATE TABLE notification_logs (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    consumer_id BIGINT UNSIGNED NOT NULL,
+    transaction_id BIGINT UNSIGNED,
+    event VARCHAR(30) NOT NULL COMMENT 'TRANSACTION_BOOKED, PAYMENT_RECEIVED, STATUS_CHANGED, REMINDER_DUE_SOON, REMINDER_DUE_TODAY, REMINDER_OVERDUE',
+    channel VARCHAR(20) NOT NULL COMMENT 'SMS, WHATSAPP, EMAIL',
+    recipient VARCHAR(255),
+    language VARCHAR(2),
+    subject VARCHAR(255),
+    body TEXT,
+    dedup_key VARCHAR(100) COMMENT 'Satu pengiriman berhasil per kunci',
+    status VARCHAR(20) COMMENT 'SENT, FAILED',
+    provider_message_id VARCHAR(255),
+    error VARCHAR(500),
+    sent_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+
+    INDEX idx_notification_log_consumer (consumer_id),
+    INDEX idx_notification_log_transaction (transaction_id),
+    INDEX idx_notification_log_dedup_key (dedup_key),
+    INDEX idx_notification_log_status (status),
+    CONSTRAINT check_notification_log_status CHECK (status IN ('SENT', 'FAILED'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Log Pengiriman Notifikasi';
+
+-- Virtual Accounts Table
+CREATE TABLE virtual_accounts (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    transaction_id BIGINT UNSIGNED NOT NULL,
+    consumer_id BIGINT UNSIGNED NOT NULL,
+    bank_code VARCHAR(20) NOT NULL,
+    va_number VARCHAR(30) NOT NULL COMMENT 'Prefix bank + ID kontrak 10 digit + check digit Luhn',
+    status VARCHAR(20) DEFAULT 'ACTIVE' COMMENT 'ACTIVE, CLOSED',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    UNIQUE KEY uk_va_number (va_number),
+    INDEX idx_virtual_account_transaction (transaction_id),
+    INDEX idx_virtual_account_consumer (consumer_id),
+    INDEX idx_virtual_account_status (status),
+    CONSTRAINT check_virtual_account_status CHECK (status IN ('ACTIVE', 'CLOSED'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Virtual Account Pembayaran Angsuran';
+
+-- Payment Callbacks Table
+CREATE TABLE payment_callbacks (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    bank_code VARCHAR(20) NOT NULL,
+    external_id VARCHAR(100) NOT NULL COMMENT 'ID pembayaran dari bank, satu kali per bank',
+    va_number VARCHAR(30),
+    amount DECIMAL(15, 2) NOT NULL,
+    paid_at DATETIME NOT NULL,
+    status VARCHAR(20) COMMENT 'POSTED, SUSPENSE, RESOLVED, REFUNDED',
+    transaction_id BIGINT UNSIGNED,
+    payment_id BIGINT UNSIGNED,
+    suspense_reason VARCHAR(255) COMMENT 'Alasan pembayaran masuk titipan',
+    payload TEXT COMMENT 'Isi callback asli dari bank',
+    resolved_by VARCHAR(100),
+    resolution_note VARCHAR(500),
+    resolved_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    UNIQUE KEY idx_callback_bank_external (bank_code, external_id),
+    INDEX idx_payment_callback_va_number (va_number),
+    INDEX idx_payment_callback_status (status),
+    INDEX idx_payment_callback_transaction (transaction_id),
+    CONSTRAINT check_payment_callback_status CHECK (status IN ('POSTED', 'SUSPENSE', 'RESOLVED', 'REFUNDED')),
+    CONSTRAINT check_payment_callback_amount CHECK (amount > 0)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Callback Pembayaran Bank';
+
+-- Bank Statements Table
+CREATE TABLE bank_statements (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    format VARCHAR(10) NOT NULL COMMENT 'CSV, MT940',
+    account_number VARCHAR(50),
+    file_name VARCHAR(255),
+    file_hash VARCHAR(64) NOT NULL COMMENT 'SHA-256 file mutasi, satu kali impor per file',
+    credit_lines INT DEFAULT 0,
+    total_credit DECIMAL(15, 2) DEFAULT 0,
+    matched_count INT DEFAULT 0,
+    matched_amount DECIMAL(15, 2) DEFAULT 0,
+    partial_count INT DEFAULT 0,
+    unmatched_count INT DEFAULT 0,
+    imported_by VARCHAR(100),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    UNIQUE KEY uk_bank_statement_file_hash (file_hash),
+    CONSTRAINT check_bank_statement_format CHECK (format IN ('CSV', 'MT940'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Mutasi Rekening Bank';
+
+-- Bank Statement Lines Table
+CREATE TABLE bank_statement_lines (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    statement_id BIGINT UNSIGNED NOT NULL,
+    line_number INT,
+    value_date DATE,
+    amount DECIMAL(15, 2) NOT NULL,
+    reference VARCHAR(255),
+    description VARCHAR(500),
+    va_number VARCHAR(30),
+    status VARCHAR(20) COMMENT 'MATCHED, PARTIAL, UNMATCHED, IGNORED',
+    transaction_id BIGINT UNSIGNED,
+    installment_id BIGINT UNSIGNED COMMENT 'Angsuran yang diharapkan saat impor',
+    payment_id BIGINT UNSIGNED,
+    callback_id BIGINT UNSIGNED COMMENT 'Callback bank untuk dana yang sama',
+    match_note VARCHAR(500),
+    matched_by VARCHAR(100) COMMENT 'AUTO atau operator',
+    matched_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (statement_id) REFERENCES bank_statements(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    INDEX idx_statement_line_statement (statement_id),
+    INDEX idx_statement_line_value_date (value_date),
+    INDEX idx_statement_line_va_number (va_number),
+    INDEX idx_statement_line_status (status),
+    INDEX idx_statement_line_transaction (transaction_id),
+    INDEX idx_statement_line_callback (callback_id),
+    CONSTRAINT check_statement_line_status CHECK (status IN ('MATCHED', 'PARTIAL', 'UNMATCHED', 'IGNORED'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Baris Mutasi Rekening Bank';
+
+-- Statement Archives Table
+CREATE TABLE statement_archives (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    consumer_id BIGINT UNSIGNED NOT NULL,
+    transaction_id BIGINT UNSIGNED COMMENT '0 untuk seluruh kontrak konsumen',
+    period_from DATE NOT NULL,
+    period_to DATE NOT NULL,
+    format VARCHAR(10) NOT NULL COMMENT 'JSON, PDF',
+    file_name VARCHAR(255),
+    document_hash VARCHAR(64) NOT NULL COMMENT 'SHA-256 dokumen yang diserahkan',
+    content LONGBLOB,
+    size_bytes INT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (consumer_id) REFERENCES consumers(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    INDEX idx_statement_archive_consumer (consumer_id),
+    INDEX idx_statement_archive_transaction (transaction_id),
+    INDEX idx_statement_archive_hash (document_hash),
+    CONSTRAINT check_statement_archive_format CHECK (format IN ('JSON', 'PDF'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Arsip Laporan Rekening Konsumen';
+
+-- Contract Templates Table
+CREATE TABLE contract_templates (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(50) NOT NULL COMMENT 'FINANCING atau kode produk',
+    version INT NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL COMMENT 'Pasal-pasal kontrak dalam format text/template',
+    active BOOLEAN DEFAULT FALSE COMMENT 'Hanya versi terbaru yang aktif',
+    created_by VARCHAR(100),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE KEY idx_contract_template_version (code, version),
+    INDEX idx_contract_template_active (active)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Template Kontrak Pembiayaan';
+
+-- Contract Documents Table
+CREATE TABLE contract_documents (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    transaction_id BIGINT UNSIGNED NOT NULL,
+    template_id BIGINT UNSIGNED NOT NULL,
+    template_code VARCHAR(50),
+    template_version INT,
+    file_name VARCHAR(255),
+    document_hash VARCHAR(64) NOT NULL COMMENT 'SHA-256 PDF yang ditandatangani',
+    content LONGBLOB,
+    size_bytes INT,
+    status VARCHAR(20) DEFAULT 'PENDING' COMMENT 'PENDING, SIGNED, SUPERSEDED',
+    signed_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    FOREIGN KEY (template_id) REFERENCES contract_templates(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    INDEX idx_contract_document_transaction (transaction_id),
+    INDEX idx_contract_document_hash (document_hash),
+    INDEX idx_contract_document_status (status),
+    CONSTRAINT check_contract_document_status CHECK (status IN ('PENDING', 'SIGNED', 'SUPERSEDED'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Dokumen Kontrak Pembiayaan';
+
+-- Contract Signatures Table
+CREATE TABLE contract_signatures (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    transaction_id BIGINT UNSIGNED NOT NULL,
+    document_id BIGINT UNSIGNED NOT NULL,
+    document_hash VARCHAR(64) NOT NULL COMMENT 'Dokumen yang dikirimi kode OTP',
+    otp_hash VARCHAR(64) NOT NULL COMMENT 'HMAC kode OTP, kode asli tidak disimpan',
+    otp_channel VARCHAR(20),
+    otp_expires_at DATETIME NOT NULL,
+    attempts INT DEFAULT 0,
+    status VARCHAR(20) DEFAULT 'PENDING' COMMENT 'PENDING, SIGNED, EXPIRED, LOCKED',
+    signed_at DATETIME,
+    ip_address VARCHAR(45),
+    user_agent VARCHAR(500),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    FOREIGN KEY (document_id) REFERENCES contract_documents(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    INDEX idx_contract_signature_transaction (transaction_id),
+    INDEX idx_contract_signature_document (document_id),
+    INDEX idx_contract_signature_status (status),
+    CONSTRAINT check_contract_signature_status CHECK (status IN ('PENDING', 'SIGNED', 'EXPIRED', 'LOCKED'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Bukti Tanda Tangan Elektronik Kontrak';
+
+-- OTP Challenges Table
+CREATE TABLE otp_challenges (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    consumer_id BIGINT UNSIGNED NOT NULL,
+    purpose VARCHAR(30) NOT NULL COMMENT 'TRANSACTION',
+    merchant_id BIGINT UNSIGNED COMMENT '0 untuk transaksi tanpa merchant',
+    amount DECIMAL(15,2) COMMENT 'Jumlah pembiayaan yang disetujui konsumen',
+    code_hash VARCHAR(64) NOT NULL COMMENT 'HMAC kode OTP, kode asli tidak disimpan',
+    channel VARCHAR(20),
+    expires_at DATETIME NOT NULL,
+    attempts INT DEFAULT 0,
+    send_count INT DEFAULT 0,
+    last_sent_at DATETIME,
+    status VARCHAR(20) DEFAULT 'PENDING' COMMENT 'PENDING, CONSUMED, EXPIRED, LOCKED',
+    consumed_at DATETIME,
+    reference VARCHAR(255) COMMENT 'Nomor kontrak yang memakai persetujuan',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (consumer_id) REFERENCES consumers(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    INDEX idx_otp_challenge_consumer (consumer_id),
+    INDEX idx_otp_challenge_merchant (merchant_id),
+    INDEX idx_otp_challenge_status (status),
+    CONSTRAINT check_otp_challenge_status CHECK (status IN ('PENDING', 'CONSUMED', 'EXPIRED', 'LOCKED'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Challenge OTP Persetujuan Konsumen';
+
 -- Create views for business intelligence
 
 -- View: Consumer Overview
@@ -92,6 +982,7 @@ SELECT
     COUNT(DISTINCT cl.id) as total_limits,
     SUM(cl.limit_amount) as total_limit_amount,
     SUM(cl.used_amount) as total_used_amount,
+    SUM(cl.held_amount) as total_held_amount,
     COUNT(DISTINCT t.id) as total_transactions
 FROM consumers c
 LEFT JOIN consumer_limits cl ON c.id = cl.consumer_id
@@ -108,6 +999,7 @@ SELECT
     t.contract_number,
     t.tenor,
     t.otr,
+    t.down_payment,
     t.admin_fee,
     t.installment_amount,
     t.interest_amount,
@@ -140,6 +1032,7 @@ CREATE PROCEDURE sp_create_transaction(
     IN p_contract_number VARCHAR(255),
     IN p_tenor INT,
     IN p_otr DECIMAL(15, 2),
+    IN p_down_payment DECIMAL(15, 2),
     IN p_admin_fee DECIMAL(15, 2),
     IN p_installment_amount DECIMAL(15, 2),
     IN p_interest_amount DECIMAL(15, 2),
@@ -170,12 +1063,12 @@ BEGIN
         LEAVE sp_create_transaction;
     END IF;
 
-    -- Check and update limit
+    -- Check and update limit (only the financed amount, OTR - DP, is booked)
     IF NOT EXISTS (
         SELECT 1 FROM consumer_limits 
         WHERE consumer_id = p_consumer_id 
         AND tenor = p_tenor 
-        AND (used_amount + p_otr) <= limit_amount
+        AND (used_amount + held_amount + p_otr - p_down_payment) <= limit_amount
     ) THEN
         SET p_error_message = 'Insufficient credit limit';
         ROLLBACK;
@@ -184,16 +1077,16 @@ BEGIN
 
     -- Update limit usage
     UPDATE consumer_limits 
-    SET used_amount = used_amount + p_otr,
+    SET used_amount = used_amount + p_otr - p_down_payment,
         updated_at = CURRENT_TIMESTAMP
     WHERE consumer_id = p_consumer_id AND tenor = p_tenor;
 
     -- Insert transaction
     INSERT INTO transactions (
-        consumer_id, contract_number, tenor, otr, admin_fee, 
+        consumer_id, contract_number, tenor, otr, down_payment, admin_fee, 
         installment_amount, interest_amount, asset_name, status
     ) VALUES (
-        p_consumer_id, p_contract_number, p_tenor, p_otr, p_admin_fee,
+        p_consumer_id, p_contract_number, p_tenor, p_otr, p_down_payment, p_admin_fee,
         p_installment_amount, p_interest_amount, p_asset_name, 'ACTIVE'
     );
 
@@ -214,7 +1107,7 @@ CREATE PROCEDURE sp_get_consumer_available_limit(
     OUT p_available_limit DECIMAL(15, 2)
 )
 BEGIN
-    SELECT (limit_amount - used_amount) INTO p_available_limit
+    SELECT (limit_amount - used_amount - held_amount) INTO p_available_limit
     FROM consumer_limits
     WHERE consumer_id = p_consumer_id AND tenor = p_tenor;
 END$$
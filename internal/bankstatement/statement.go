@@ -0,0 +1,242 @@
+package bankstatement
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Statement file formats
+const (
+	FormatCSV   = "CSV"
+	FormatMT940 = "MT940"
+)
+
+// Statement is the account activity a bank reports for one account
+type Statement struct {
+	Format        string
+	AccountNumber string
+	Lines         []Line
+}
+
+// Line is one booking on the statement
+type Line struct {
+	Number      int // line in the file
+	ValueDate   time.Time
+	Amount      float64 // positive for credits, negative for debits
+	Reference   string  // bank reference
+	Description string
+	VANumber    string // only when the bank reports it in a column of its own
+}
+
+// Credit reports whether the line is money received
+func (l Line) Credit() bool {
+	return l.Amount > 0
+}
+
+// minVADigits is the shortest digit run in a reference or description that can be a VA number
+const minVADigits = 10
+
+var digitRun = regexp.MustCompile(`\d+`)
+
+// Candidates returns the possible VA numbers of the line: the VA column, then every
+// long enough digit run of the reference and description
+func (l Line) Candidates() []string {
+	var candidates []string
+	seen := make(map[string]bool)
+	add := func(value string) {
+		if value != "" && !seen[value] {
+			seen[value] = true
+			candidates = append(candidates, value)
+		}
+	}
+	add(strings.TrimSpace(l.VANumber))
+	for _, run := range digitRun.FindAllString(l.Reference+" "+l.Description, -1) {
+		if len(run) >= minVADigits {
+			add(run)
+		}
+	}
+	return candidates
+}
+
+// DetectFormat tells an MT940 file, which starts with a SWIFT block or a tag, from a CSV file
+func DetectFormat(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("{1:")) || bytes.HasPrefix(trimmed, []byte(":20:")) {
+		return FormatMT940
+	}
+	return FormatCSV
+}
+
+// Parse reads a statement in the given format, detecting it when empty.
+// Malformed lines are reported in the returned line errors and skipped.
+func Parse(data []byte, format string) (*Statement, []string, error) {
+	if format == "" {
+		format = DetectFormat(data)
+	}
+	switch strings.ToUpper(format) {
+	case FormatCSV:
+		return ParseCSV(data)
+	case FormatMT940:
+		return ParseMT940(data)
+	}
+	return nil, nil, fmt.Errorf("format mutasi %s tidak didukung, gunakan CSV atau MT940", format)
+}
+
+// csvDateLayouts are the value date formats accepted in CSV statements
+var csvDateLayouts = []string{"2006-01-02", "02/01/2006"}
+
+// ParseCSV reads a CSV statement with the header date,amount,description[,reference][,va_number][,type][,account].
+// Amounts are positive; type D or DB marks a debit, anything else is a credit.
+func ParseCSV(data []byte) (*Statement, []string, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("file mutasi tidak valid: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil, errors.New("file mutasi kosong")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"date", "amount", "description"} {
+		if _, ok := columns[required]; !ok {
+			return nil, nil, fmt.Errorf("kolom %s wajib ada pada header file mutasi", required)
+		}
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	statement := &Statement{Format: FormatCSV}
+	var lineErrors []string
+	for i, record := range records[1:] {
+		number := i + 2
+		if statement.AccountNumber == "" {
+			statement.AccountNumber = field(record, "account")
+		}
+		date, err := parseCSVDate(field(record, "date"))
+		if err != nil {
+			lineErrors = append(lineErrors, fmt.Sprintf("baris %d: tanggal tidak valid", number))
+			continue
+		}
+		amount, err := strconv.ParseFloat(strings.ReplaceAll(field(record, "amount"), ",", ""), 64)
+		if err != nil || amount <= 0 {
+			lineErrors = append(lineErrors, fmt.Sprintf("baris %d: jumlah tidak valid", number))
+			continue
+		}
+		switch strings.ToUpper(field(record, "type")) {
+		case "D", "DB", "DR", "DEBIT":
+			amount = -amount
+		}
+		statement.Lines = append(statement.Lines, Line{
+			Number:      number,
+			ValueDate:   date,
+			Amount:      amount,
+			Reference:   field(record, "reference"),
+			Description: field(record, "description"),
+			VANumber:    field(record, "va_number"),
+		})
+	}
+	return statement, lineErrors, nil
+}
+
+func parseCSVDate(value string) (time.Time, error) {
+	for _, layout := range csvDateLayouts {
+		if date, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("tanggal %q tidak dikenali", value)
+}
+
+// statementLine is the :61: field: value date YYMMDD, optional entry date MMDD, debit/credit mark
+// (R for reversals), optional funds code, amount with a decimal comma, transaction type and references
+var statementLine = regexp.MustCompile(`^(\d{6})(\d{4})?(RC|RD|C|D)([A-Z])?(\d+,\d{0,2})[A-Z][A-Z0-9]{3}([^/]*)(?://(.*))?$`)
+
+// ParseMT940 reads a SWIFT MT940 customer statement. Every :61: booking takes the text of the
+// :86: field that follows it as its description.
+func ParseMT940(data []byte) (*Statement, []string, error) {
+	statement := &Statement{Format: FormatMT940}
+	var lineErrors []string
+
+	type field struct {
+		tag    string
+		value  string
+		number int
+	}
+	var fields []field
+	for i, raw := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		line := strings.TrimRight(raw, " ")
+		if strings.HasPrefix(line, ":") {
+			if end := strings.Index(line[1:], ":"); end > 0 {
+				fields = append(fields, field{tag: line[1 : end+1], value: line[end+2:], number: i + 1})
+				continue
+			}
+		}
+		if line == "" || line == "-}" || line == "-" || strings.HasPrefix(line, "{") {
+			continue
+		}
+		if len(fields) > 0 {
+			fields[len(fields)-1].value += "\n" + line
+		}
+	}
+	if len(fields) == 0 {
+		return nil, nil, errors.New("file MT940 tidak berisi field")
+	}
+
+	for i, f := range fields {
+		switch f.tag {
+		case "25":
+			statement.AccountNumber = strings.TrimSpace(f.value)
+		case "61":
+			first, _, _ := strings.Cut(f.value, "\n")
+			match := statementLine.FindStringSubmatch(strings.TrimSpace(first))
+			if match == nil {
+				lineErrors = append(lineErrors, fmt.Sprintf("baris %d: field :61: tidak valid", f.number))
+				continue
+			}
+			date, err := time.ParseInLocation("060102", match[1], time.Local)
+			if err != nil {
+				lineErrors = append(lineErrors, fmt.Sprintf("baris %d: tanggal tidak valid", f.number))
+				continue
+			}
+			amount, err := strconv.ParseFloat(strings.Replace(match[5], ",", ".", 1), 64)
+			if err != nil {
+				lineErrors = append(lineErrors, fmt.Sprintf("baris %d: jumlah tidak valid", f.number))
+				continue
+			}
+			// A debit, or the reversal of a credit, takes money out of the account
+			if match[3] == "D" || match[3] == "RC" {
+				amount = -amount
+			}
+			line := Line{
+				Number:    f.number,
+				ValueDate: date,
+				Amount:    amount,
+				Reference: strings.TrimSpace(match[7]),
+			}
+			if customer := strings.TrimSpace(match[6]); line.Reference == "" && customer != "NONREF" {
+				line.Reference = customer
+			}
+			if i+1 < len(fields) && fields[i+1].tag == "86" {
+				line.Description = strings.Join(strings.Fields(fields[i+1].value), " ")
+			}
+			statement.Lines = append(statement.Lines, line)
+		}
+	}
+	return statement, lineErrors, nil
+}
@@ -0,0 +1,72 @@
+package bankstatement
+
+import (
+	"testing"
+	"time"
+)
+
+// Test: CSV statements carry signed amounts and report malformed lines
+func TestParseCSV(t *testing.T) {
+	data := []byte("account,date,amount,type,reference,description,va_number\n" +
+		"0123456789,2026-02-01,1100000,C,TRX-1,SETORAN VA,3935800000000015\n" +
+		"0123456789,01/02/2026,250000,D,PAY-1,TRANSFER KELUAR,\n" +
+		"0123456789,2026-02-31,100,C,X,BAD DATE,\n")
+
+	statement, lineErrors, err := Parse(data, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if statement.Format != FormatCSV || statement.AccountNumber != "0123456789" {
+		t.Errorf("Unexpected statement header %+v", statement)
+	}
+	if len(statement.Lines) != 2 || len(lineErrors) != 1 {
+		t.Fatalf("Expected 2 lines and 1 error, got %d and %v", len(statement.Lines), lineErrors)
+	}
+
+	credit, debit := statement.Lines[0], statement.Lines[1]
+	if !credit.Credit() || credit.Amount != 1100000 || credit.Candidates()[0] != "3935800000000015" {
+		t.Errorf("Unexpected credit line %+v", credit)
+	}
+	if debit.Credit() || debit.Amount != -250000 || !debit.ValueDate.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("Unexpected debit line %+v", debit)
+	}
+}
+
+// Test: MT940 bookings take their description from the following :86: field
+func TestParseMT940(t *testing.T) {
+	data := []byte("{1:F01BANKIDJAXXX0000000000}{4:\r\n" +
+		":20:STMT20260201\r\n" +
+		":25:0123456789\r\n" +
+		":28C:00001/001\r\n" +
+		":60F:C260131IDR10000000,00\r\n" +
+		":61:2602010201C1100000,00NTRFNONREF//BNK0001\r\n" +
+		":86:SETORAN VA 3935800000000015\r\n" +
+		"BUDI SANTOSO\r\n" +
+		":61:260201D250000,NTRFPAY-1\r\n" +
+		":86:TRANSFER KELUAR\r\n" +
+		":61:2602XXC5,00NTRFBAD\r\n" +
+		":62F:C260201IDR10850000,00\r\n" +
+		"-}")
+
+	statement, lineErrors, err := Parse(data, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if statement.Format != FormatMT940 || statement.AccountNumber != "0123456789" {
+		t.Errorf("Unexpected statement header %+v", statement)
+	}
+	if len(statement.Lines) != 2 || len(lineErrors) != 1 {
+		t.Fatalf("Expected 2 lines and 1 error, got %d and %v", len(statement.Lines), lineErrors)
+	}
+
+	credit := statement.Lines[0]
+	if credit.Amount != 1100000 || credit.Reference != "BNK0001" || credit.Description != "SETORAN VA 3935800000000015 BUDI SANTOSO" {
+		t.Errorf("Unexpected credit line %+v", credit)
+	}
+	if candidates := credit.Candidates(); len(candidates) != 1 || candidates[0] != "3935800000000015" {
+		t.Errorf("Expected the VA number as the only candidate, got %v", candidates)
+	}
+	if debit := statement.Lines[1]; debit.Amount != -250000 || debit.Reference != "PAY-1" {
+		t.Errorf("Unexpected debit line %+v", debit)
+	}
+}
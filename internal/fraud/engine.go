@@ -0,0 +1,249 @@
+package fraud
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"main/internal/model"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Risk decisions
+const (
+	DecisionAllow  = "ALLOW"
+	DecisionReview = "REVIEW"
+	DecisionDeny   = "DENY"
+)
+
+// Rule types
+const (
+	RuleConsumerVelocity      = "consumer_velocity"       // count and amount per consumer per window
+	RuleRepeatedAsset         = "repeated_asset"          // same asset name bought repeatedly
+	RuleFirstTransactionRatio = "first_transaction_ratio" // new consumer's first transaction vs its limit
+	RuleMerchantBurst         = "merchant_burst"          // many contracts from one merchant in a burst
+)
+
+//go:embed default_rules.yaml
+var defaultRules []byte
+
+// Config is the YAML rule configuration
+type Config struct {
+	Thresholds struct {
+		Review int `yaml:"review"`
+		Deny   int `yaml:"deny"`
+	} `yaml:"thresholds"`
+	Rules []Rule `yaml:"rules"`
+}
+
+// Rule is one configurable velocity rule
+type Rule struct {
+	Name           string        `yaml:"name"`
+	Type           string        `yaml:"type"`
+	Window         time.Duration `yaml:"window"`
+	MaxCount       int           `yaml:"max_count"`
+	MaxAmount      float64       `yaml:"max_amount"`
+	MaxRepeats     int           `yaml:"max_repeats"`
+	NewConsumerAge time.Duration `yaml:"new_consumer_age"`
+	MaxRatio       float64       `yaml:"max_ratio"`
+	Score          int           `yaml:"score"`
+	Action         string        `yaml:"action"` // optional: "deny" to deny on this rule alone
+}
+
+// Input is a transaction request with the history the rules need
+type Input struct {
+	ConsumerID        uint
+	MerchantID        uint
+	Amount            float64 // financed amount (OTR - down payment) of the request
+	AssetName         string
+	LimitAmount       float64
+	ConsumerCreatedAt time.Time
+	PriorTransactions int                 // all transactions ever booked by the consumer
+	ConsumerHistory   []model.Transaction // consumer transactions within MaxWindow
+	MerchantHistory   []model.Transaction // merchant transactions within MaxWindow
+	Now               time.Time
+}
+
+// RuleHit is a triggered rule
+type RuleHit struct {
+	Rule   string `json:"rule"`
+	Score  int    `json:"score"`
+	Detail string `json:"detail"`
+}
+
+// Assessment is the outcome of evaluating all rules
+type Assessment struct {
+	Score    int       `json:"score"`
+	Decision string    `json:"decision"`
+	Hits     []RuleHit `json:"hits"`
+}
+
+// Engine evaluates transaction requests against the configured rules
+type Engine struct {
+	config Config
+}
+
+// NewEngine validates the configuration and creates an Engine
+func NewEngine(config Config) (*Engine, error) {
+	if config.Thresholds.Review <= 0 || config.Thresholds.Deny < config.Thresholds.Review {
+		return nil, fmt.Errorf("threshold fraud tidak valid: review=%d deny=%d", config.Thresholds.Review, config.Thresholds.Deny)
+	}
+	for _, rule := range config.Rules {
+		if rule.Window <= 0 && rule.Type != RuleFirstTransactionRatio {
+			return nil, fmt.Errorf("rule %s: window wajib diisi", rule.Name)
+		}
+		// A limit left out reads as 0, which would make the rule fire on every transaction
+		switch rule.Type {
+		case RuleConsumerVelocity:
+			if rule.MaxCount < 0 || rule.MaxAmount < 0 || (rule.MaxCount == 0 && rule.MaxAmount == 0) {
+				return nil, fmt.Errorf("rule %s: max_count atau max_amount wajib diisi", rule.Name)
+			}
+		case RuleRepeatedAsset:
+			if rule.MaxRepeats <= 0 {
+				return nil, fmt.Errorf("rule %s: max_repeats wajib diisi", rule.Name)
+			}
+		case RuleMerchantBurst:
+			if rule.MaxCount <= 0 {
+				return nil, fmt.Errorf("rule %s: max_count wajib diisi", rule.Name)
+			}
+		case RuleFirstTransactionRatio:
+			if rule.MaxRatio <= 0 {
+				return nil, fmt.Errorf("rule %s: max_ratio wajib diisi", rule.Name)
+			}
+		default:
+			return nil, fmt.Errorf("rule %s: tipe %q tidak dikenal", rule.Name, rule.Type)
+		}
+	}
+	return &Engine{config: config}, nil
+}
+
+// ParseConfig reads a YAML rule configuration
+func ParseConfig(data []byte) (Config, error) {
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("konfigurasi rule fraud tidak valid: %w", err)
+	}
+	return config, nil
+}
+
+// LoadEngine builds an Engine from a YAML file, or from the embedded defaults when path is empty
+func LoadEngine(path string) (*Engine, error) {
+	data := defaultRules
+	if path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		data = content
+	}
+
+	config, err := ParseConfig(data)
+	if err != nil {
+		return nil, err
+	}
+	return NewEngine(config)
+}
+
+// MaxWindow is the longest look-back any rule needs, so callers can load enough history
+func (e *Engine) MaxWindow() time.Duration {
+	var window time.Duration
+	for _, rule := range e.config.Rules {
+		window = max(window, rule.Window)
+	}
+	return window
+}
+
+// Evaluate runs every rule and returns the risk score and decision
+func (e *Engine) Evaluate(input Input) Assessment {
+	assessment := Assessment{Decision: DecisionAllow}
+	forceDeny := false
+
+	for _, rule := range e.config.Rules {
+		detail, triggered := e.evaluateRule(rule, input)
+		if !triggered {
+			continue
+		}
+		assessment.Score += rule.Score
+		assessment.Hits = append(assessment.Hits, RuleHit{Rule: rule.Name, Score: rule.Score, Detail: detail})
+		if strings.EqualFold(rule.Action, "deny") {
+			forceDeny = true
+		}
+	}
+
+	switch {
+	case forceDeny || assessment.Score >= e.config.Thresholds.Deny:
+		assessment.Decision = DecisionDeny
+	case assessment.Score >= e.config.Thresholds.Review:
+		assessment.Decision = DecisionReview
+	}
+	return assessment
+}
+
+func (e *Engine) evaluateRule(rule Rule, input Input) (string, bool) {
+	since := input.Now.Add(-rule.Window)
+
+	switch rule.Type {
+	case RuleConsumerVelocity:
+		count, amount := 1, input.Amount // the request itself counts
+		for _, tx := range input.ConsumerHistory {
+			// Rejected and cancelled contracts never drew on the limit
+			if !tx.CreatedAt.After(since) || tx.Status == model.TransactionStatusRejected || tx.Status == model.TransactionStatusCancelled {
+				continue
+			}
+			count++
+			amount += tx.FinancedAmount()
+		}
+		if rule.MaxCount > 0 && count > rule.MaxCount {
+			return fmt.Sprintf("%d transaksi dalam %s (maks %d)", count, rule.Window, rule.MaxCount), true
+		}
+		if rule.MaxAmount > 0 && amount > rule.MaxAmount {
+			return fmt.Sprintf("total %.2f dalam %s (maks %.2f)", amount, rule.Window, rule.MaxAmount), true
+		}
+
+	case RuleRepeatedAsset:
+		asset := strings.ToUpper(strings.TrimSpace(input.AssetName))
+		if asset == "" {
+			return "", false
+		}
+		repeats := 0
+		for _, tx := range input.ConsumerHistory {
+			if tx.CreatedAt.After(since) && strings.ToUpper(strings.TrimSpace(tx.AssetName)) == asset {
+				repeats++
+			}
+		}
+		if repeats >= rule.MaxRepeats {
+			return fmt.Sprintf("aset %q sudah dibeli %d kali dalam %s", input.AssetName, repeats, rule.Window), true
+		}
+
+	case RuleFirstTransactionRatio:
+		if input.PriorTransactions > 0 || input.LimitAmount <= 0 {
+			return "", false
+		}
+		if rule.NewConsumerAge > 0 && input.Now.Sub(input.ConsumerCreatedAt) > rule.NewConsumerAge {
+			return "", false
+		}
+		ratio := input.Amount / input.LimitAmount
+		if ratio > rule.MaxRatio {
+			return fmt.Sprintf("transaksi pertama %.0f%% dari limit (maks %.0f%%)", ratio*100, rule.MaxRatio*100), true
+		}
+
+	case RuleMerchantBurst:
+		if input.MerchantID == 0 {
+			return "", false
+		}
+		count := 1
+		for _, tx := range input.MerchantHistory {
+			if tx.CreatedAt.After(since) {
+				count++
+			}
+		}
+		if count > rule.MaxCount {
+			return fmt.Sprintf("merchant %d membukukan %d kontrak dalam %s (maks %d)", input.MerchantID, count, rule.Window, rule.MaxCount), true
+		}
+	}
+
+	return "", false
+}
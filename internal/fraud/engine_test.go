@@ -0,0 +1,175 @@
+package fraud
+
+import (
+	"testing"
+	"time"
+
+	"main/internal/model"
+)
+
+var now = time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+func newDefaultEngine(t *testing.T) *Engine {
+	engine, err := LoadEngine("")
+	if err != nil {
+		t.Fatalf("Expected embedded rules to load, got %v", err)
+	}
+	return engine
+}
+
+// Test: Ordinary transaction is allowed
+func TestEvaluate_Allow(t *testing.T) {
+	engine := newDefaultEngine(t)
+
+	assessment := engine.Evaluate(Input{
+		ConsumerID:        1,
+		Amount:            1000000,
+		AssetName:         "Kulkas",
+		LimitAmount:       5000000,
+		ConsumerCreatedAt: now.AddDate(0, -6, 0),
+		PriorTransactions: 3,
+		Now:               now,
+	})
+
+	if assessment.Decision != DecisionAllow || assessment.Score != 0 {
+		t.Errorf("Expected ALLOW with score 0, got %s (%d)", assessment.Decision, assessment.Score)
+	}
+}
+
+// Test: Burst of transactions within a minute goes to review
+func TestEvaluate_ConsumerBurst(t *testing.T) {
+	engine := newDefaultEngine(t)
+
+	assessment := engine.Evaluate(Input{
+		ConsumerID:        1,
+		Amount:            1000000,
+		LimitAmount:       5000000,
+		ConsumerCreatedAt: now.AddDate(0, -6, 0),
+		PriorTransactions: 2,
+		ConsumerHistory: []model.Transaction{
+			{OTR: 1000000, CreatedAt: now.Add(-20 * time.Second)},
+			{OTR: 1000000, CreatedAt: now.Add(-40 * time.Second)},
+		},
+		Now: now,
+	})
+
+	if assessment.Decision != DecisionReview {
+		t.Errorf("Expected REVIEW, got %s (%d)", assessment.Decision, assessment.Score)
+	}
+}
+
+// Test: Burst of the same asset within a minute is denied
+func TestEvaluate_Deny(t *testing.T) {
+	engine := newDefaultEngine(t)
+
+	assessment := engine.Evaluate(Input{
+		ConsumerID:        1,
+		Amount:            1500000,
+		AssetName:         "iPhone 15",
+		LimitAmount:       5000000,
+		ConsumerCreatedAt: now.AddDate(0, -6, 0),
+		PriorTransactions: 2,
+		ConsumerHistory: []model.Transaction{
+			{OTR: 1500000, AssetName: "IPHONE 15", CreatedAt: now.Add(-10 * time.Second)},
+			{OTR: 1500000, AssetName: "iphone 15", CreatedAt: now.Add(-30 * time.Second)},
+		},
+		Now: now,
+	})
+
+	if assessment.Decision != DecisionDeny || len(assessment.Hits) != 2 {
+		t.Errorf("Expected DENY with 2 rule hits, got %s (%+v)", assessment.Decision, assessment.Hits)
+	}
+}
+
+// Test: Hourly amount counts the financed amount and skips rejected or cancelled contracts
+func TestEvaluate_ConsumerVelocityFinancedAmount(t *testing.T) {
+	engine := newDefaultEngine(t)
+
+	input := Input{
+		ConsumerID:        1,
+		Amount:            8000000,
+		AssetName:         "Kulkas",
+		LimitAmount:       50000000,
+		ConsumerCreatedAt: now.AddDate(0, -6, 0),
+		PriorTransactions: 3,
+		ConsumerHistory: []model.Transaction{
+			// OTR 20,000,000 with 10,000,000 down: only 10,000,000 is financed
+			{OTR: 20000000, DownPayment: 10000000, AssetName: "Motor", Status: model.TransactionStatusActive, CreatedAt: now.Add(-20 * time.Minute)},
+			{OTR: 15000000, AssetName: "Laptop", Status: model.TransactionStatusRejected, CreatedAt: now.Add(-30 * time.Minute)},
+			{OTR: 15000000, AssetName: "Televisi", Status: model.TransactionStatusCancelled, CreatedAt: now.Add(-40 * time.Minute)},
+		},
+		Now: now,
+	}
+
+	assessment := engine.Evaluate(input)
+	if assessment.Decision != DecisionAllow || len(assessment.Hits) != 0 {
+		t.Errorf("Expected ALLOW for 18,000,000 financed, got %s (%+v)", assessment.Decision, assessment.Hits)
+	}
+
+	// Once the earlier contract is financed in full the hour exceeds 25,000,000
+	input.ConsumerHistory[0].DownPayment = 0
+	assessment = engine.Evaluate(input)
+	if len(assessment.Hits) != 1 || assessment.Hits[0].Rule != "consumer_hourly_amount" {
+		t.Errorf("Expected consumer_hourly_amount hit, got %+v", assessment.Hits)
+	}
+}
+
+// Test: Merchant burst rule and a per-rule deny action from YAML
+func TestEvaluate_MerchantBurstForcedDeny(t *testing.T) {
+	config, err := ParseConfig([]byte(`
+thresholds:
+  review: 40
+  deny: 80
+rules:
+  - name: merchant_burst
+    type: merchant_burst
+    window: 5m
+    max_count: 2
+    score: 10
+    action: deny
+`))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	engine, err := NewEngine(config)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	assessment := engine.Evaluate(Input{
+		MerchantID: 7,
+		Amount:     1000000,
+		MerchantHistory: []model.Transaction{
+			{CreatedAt: now.Add(-time.Minute)},
+			{CreatedAt: now.Add(-2 * time.Minute)},
+		},
+		Now: now,
+	})
+
+	if assessment.Decision != DecisionDeny {
+		t.Errorf("Expected DENY, got %s", assessment.Decision)
+	}
+}
+
+// Test: Unknown rule types are rejected
+func TestNewEngine_InvalidRule(t *testing.T) {
+	config, _ := ParseConfig([]byte("thresholds: {review: 10, deny: 20}\nrules:\n  - {name: x, type: unknown}\n"))
+	if _, err := NewEngine(config); err == nil {
+		t.Error("Expected error for unknown rule type, got nil")
+	}
+}
+
+// Test: Rules without their limit are rejected instead of firing on every transaction
+func TestNewEngine_MissingLimit(t *testing.T) {
+	rules := map[string]string{
+		"repeated_asset":    "{name: x, type: repeated_asset, window: 24h}",
+		"merchant_burst":    "{name: x, type: merchant_burst, window: 1h, max_count: 0}",
+		"consumer_velocity": "{name: x, type: consumer_velocity, window: 1h}",
+	}
+	for ruleType, rule := range rules {
+		config, _ := ParseConfig([]byte("thresholds: {review: 10, deny: 20}\nrules:\n  - " + rule + "\n"))
+		if _, err := NewEngine(config); err == nil {
+			t.Errorf("%s: expected error for a missing limit, got nil", ruleType)
+		}
+	}
+}
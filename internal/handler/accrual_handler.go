@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"main/internal/usecase"
+)
+
+type AccrualHandler struct {
+	accrualUsecase usecase.AccrualUsecase
+}
+
+func NewAccrualHandler(accrualUsecase usecase.AccrualUsecase) *AccrualHandler {
+	return &AccrualHandler{
+		accrualUsecase: accrualUsecase,
+	}
+}
+
+// RunAccrual handles POST /api/v1/accruals/run?date=YYYY-MM-DD - manual or back-dated re-run of the end-of-day accrual
+func (h *AccrualHandler) RunAccrual(w http.ResponseWriter, r *http.Request) {
+	date, err := parseOptionalDate(r.URL.Query().Get("date"))
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid date, expected YYYY-MM-DD"})
+		return
+	}
+
+	result, err := h.accrualUsecase.RunAccrual(date)
+	if err != nil {
+		log.Println("Error running interest accrual:", err)
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Interest accrual completed",
+		"data":    result,
+	})
+}
+
+// GetAccruals handles GET /api/v1/transactions/{id}/accruals
+func (h *AccrualHandler) GetAccruals(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid transaction ID")
+	if !ok {
+		return
+	}
+
+	accruals, err := h.accrualUsecase.GetAccruals(id)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load accruals"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, accruals)
+}
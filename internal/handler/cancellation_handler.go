@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"main/internal/usecase"
+)
+
+type CancellationHandler struct {
+	cancellationUsecase usecase.CancellationUsecase
+}
+
+func NewCancellationHandler(cancellationUsecase usecase.CancellationUsecase) *CancellationHandler {
+	return &CancellationHandler{
+		cancellationUsecase: cancellationUsecase,
+	}
+}
+
+// CancelTransaction handles POST /api/v1/transactions/{id}/cancel - full or partial cancellation
+func (h *CancellationHandler) CancelTransaction(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid transaction ID")
+	if !ok {
+		return
+	}
+
+	var req usecase.CancelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	result, err := h.cancellationUsecase.CancelTransaction(id, req)
+	if err != nil {
+		log.Println("Error cancelling transaction:", err)
+		status := http.StatusBadRequest
+		if errors.Is(err, usecase.ErrCancellationWindowClosed) {
+			status = http.StatusConflict
+		}
+		respondJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Transaction cancelled successfully",
+		"data":    result,
+	})
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"main/internal/usecase"
+)
+
+type CollectionHandler struct {
+	collectionUsecase usecase.CollectionUsecase
+}
+
+func NewCollectionHandler(collectionUsecase usecase.CollectionUsecase) *CollectionHandler {
+	return &CollectionHandler{
+		collectionUsecase: collectionUsecase,
+	}
+}
+
+// RefreshQueue handles POST /api/v1/collections/queue/refresh?date=YYYY-MM-DD - rebuilds the queue for the date (default today)
+func (h *CollectionHandler) RefreshQueue(w http.ResponseWriter, r *http.Request) {
+	date, err := parseOptionalDate(r.URL.Query().Get("date"))
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid date, expected YYYY-MM-DD"})
+		return
+	}
+
+	result, err := h.collectionUsecase.RefreshQueue(date)
+	if err != nil {
+		log.Println("Error refreshing collections queue:", err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to refresh collections queue"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Collections queue refreshed",
+		"data":    result,
+	})
+}
+
+// GetQueue handles GET /api/v1/collections/queue?collector= - open cases, highest priority first
+func (h *CollectionHandler) GetQueue(w http.ResponseWriter, r *http.Request) {
+	cases, err := h.collectionUsecase.GetQueue(r.URL.Query().Get("collector"))
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load collections queue"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, cases)
+}
+
+// AutoAssign handles POST /api/v1/collections/assign - spreads unassigned cases over the collectors
+func (h *CollectionHandler) AutoAssign(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Collectors []string `json:"collectors"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+		return
+	}
+
+	assigned, err := h.collectionUsecase.AutoAssign(req.Collectors)
+	if err != nil {
+		log.Println("Error assigning collection cases:", err)
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Collection cases assigned successfully",
+		"data":    map[string]int{"assigned": assigned},
+	})
+}
+
+// AssignCase handles PUT /api/v1/collections/cases/{id}/assignee
+func (h *CollectionHandler) AssignCase(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid case ID")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Collector string `json:"collector"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+		return
+	}
+
+	collectionCase, err := h.collectionUsecase.AssignCase(id, req.Collector)
+	if err != nil {
+		log.Println("Error assigning collection case:", err)
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Collection case assigned successfully",
+		"data":    collectionCase,
+	})
+}
+
+// GetCase handles GET /api/v1/collections/cases/{id} - the case with its contact history and promises
+func (h *CollectionHandler) GetCase(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid case ID")
+	if !ok {
+		return
+	}
+
+	detail, err := h.collectionUsecase.GetCase(id)
+	if err != nil {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, detail)
+}
+
+// RecordContact handles POST /api/v1/collections/cases/{id}/contacts - logs a contact attempt and its outcome
+func (h *CollectionHandler) RecordContact(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid case ID")
+	if !ok {
+		return
+	}
+
+	var req usecase.ContactRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	result, err := h.collectionUsecase.RecordContact(id, req)
+	if err != nil {
+		log.Println("Error recording contact attempt:", err)
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "Contact attempt recorded successfully",
+		"data":    result,
+	})
+}
+
+// CheckPromises handles POST /api/v1/collections/promises/check?date=YYYY-MM-DD - marks promises kept or broken
+func (h *CollectionHandler) CheckPromises(w http.ResponseWriter, r *http.Request) {
+	date, err := parseOptionalDate(r.URL.Query().Get("date"))
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid date, expected YYYY-MM-DD"})
+		return
+	}
+
+	result, err := h.collectionUsecase.CheckPromises(date)
+	if err != nil {
+		log.Println("Error checking promises to pay:", err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to check promises to pay"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Promise-to-pay check completed",
+		"data":    result,
+	})
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
@@ -13,15 +14,18 @@ import (
 type ConsumerHandler struct {
 	consumerUsecase usecase.ConsumerUsecase
 	limitUsecase    usecase.ConsumerLimitUsecase
+	kycUsecase      usecase.KYCUsecase
 }
 
 func NewConsumerHandler(
 	consumerUsecase usecase.ConsumerUsecase,
 	limitUsecase usecase.ConsumerLimitUsecase,
+	kycUsecase usecase.KYCUsecase,
 ) *ConsumerHandler {
 	return &ConsumerHandler{
 		consumerUsecase: consumerUsecase,
 		limitUsecase:    limitUsecase,
+		kycUsecase:      kycUsecase,
 	}
 }
 
@@ -41,10 +45,20 @@ func (h *ConsumerHandler) RegisterConsumer(w http.ResponseWriter, r *http.Reques
 
 	if err := h.consumerUsecase.RegisterConsumer(&consumer); err != nil {
 		log.Println("Error registering consumer:", err)
-		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		respondJSON(w, http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
+	// Run automatic KYC right away; failures leave the consumer PENDING for a retry.
+	// Consumers flagged by watchlist screening are already waiting for manual review.
+	if consumer.KYCStatus == model.KYCStatusPending {
+		if verification, err := h.kycUsecase.VerifyConsumer(consumer.ID); err != nil {
+			log.Println("Error verifying consumer KYC:", err)
+		} else {
+			consumer.KYCStatus = verification.Status
+		}
+	}
+
 	w.WriteHeader(http.StatusCreated)
 	respondJSON(w, http.StatusCreated, map[string]interface{}{
 		"message": "Consumer registered successfully",
@@ -123,6 +137,26 @@ func (h *ConsumerHandler) GetConsumerLimits(w http.ResponseWriter, r *http.Reque
 	respondJSON(w, http.StatusOK, limits)
 }
 
+// errorResponse builds the error body, adding the rule code for eligibility failures
+func errorResponse(err error) map[string]string {
+	body := map[string]string{"error": err.Error()}
+	var eligibilityErr *usecase.EligibilityError
+	if errors.As(err, &eligibilityErr) {
+		body["code"] = eligibilityErr.Code
+	}
+	return body
+}
+
+// parsePathID reads a positive numeric path parameter, answering 400 when it is invalid
+func parsePathID(w http.ResponseWriter, r *http.Request, name, message string) (uint, bool) {
+	id, err := strconv.ParseUint(r.PathValue(name), 10, 32)
+	if err != nil || id == 0 {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": message})
+		return 0, false
+	}
+	return uint(id), true
+}
+
 func respondJSON(w http.ResponseWriter, statusCode int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"main/internal/usecase"
+)
+
+type ContractHandler struct {
+	contractUsecase usecase.ContractUsecase
+}
+
+func NewContractHandler(contractUsecase usecase.ContractUsecase) *ContractHandler {
+	return &ContractHandler{
+		contractUsecase: contractUsecase,
+	}
+}
+
+// CreateTemplate handles POST /api/v1/contract-templates - a new active version of a template
+func (h *ContractHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	var req usecase.ContractTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	template, err := h.contractUsecase.CreateTemplate(req)
+	if err != nil {
+		log.Println("Error creating contract template:", err)
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "Contract template version created",
+		"data":    template,
+	})
+}
+
+// GetTemplates handles GET /api/v1/contract-templates/{code} - every version, newest first
+func (h *ContractHandler) GetTemplates(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.contractUsecase.GetTemplates(r.PathValue("code"))
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, templates)
+}
+
+// GenerateContract handles POST /api/v1/transactions/{id}/contract
+func (h *ContractHandler) GenerateContract(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid transaction ID")
+	if !ok {
+		return
+	}
+
+	document, err := h.contractUsecase.GenerateContract(id)
+	if err != nil {
+		log.Println("Error generating contract:", err)
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Contract document generated",
+		"data":    document,
+	})
+}
+
+// GetContractDocument handles GET /api/v1/transactions/{id}/contract - the latest document as a PDF
+func (h *ContractHandler) GetContractDocument(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid transaction ID")
+	if !ok {
+		return
+	}
+
+	document, err := h.contractUsecase.GetContractDocument(id)
+	if err != nil {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+document.FileName+`"`)
+	w.Header().Set("X-Contract-Document-ID", strconv.FormatUint(uint64(document.ID), 10))
+	w.Header().Set("X-Contract-Status", document.Status)
+	w.Header().Set("X-Document-SHA256", document.DocumentHash)
+	w.WriteHeader(http.StatusOK)
+	w.Write(document.Content)
+}
+
+// GetContractDocuments handles GET /api/v1/transactions/{id}/contract/documents - every version, without content
+func (h *ContractHandler) GetContractDocuments(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid transaction ID")
+	if !ok {
+		return
+	}
+
+	documents, err := h.contractUsecase.GetContractDocuments(id)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, documents)
+}
+
+// RequestSignature handles POST /api/v1/transactions/{id}/contract/otp - sends a signing code to the consumer
+func (h *ContractHandler) RequestSignature(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid transaction ID")
+	if !ok {
+		return
+	}
+
+	signature, err := h.contractUsecase.RequestSignature(id)
+	if err != nil {
+		log.Println("Error requesting contract signature:", err)
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Signing code sent via " + signature.OTPChannel,
+		"data":    signature,
+	})
+}
+
+// SignContract handles POST /api/v1/transactions/{id}/contract/sign with {"otp": "..."}
+func (h *ContractHandler) SignContract(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid transaction ID")
+	if !ok {
+		return
+	}
+
+	var req usecase.SignContractRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+	req.IPAddress = clientIP(r)
+	req.UserAgent = r.UserAgent()
+
+	signature, err := h.contractUsecase.SignContract(id, req)
+	if err != nil {
+		log.Println("Error signing contract:", err)
+		status := http.StatusBadRequest
+		if errors.Is(err, usecase.ErrInvalidOTP) || errors.Is(err, usecase.ErrOTPExpired) || errors.Is(err, usecase.ErrSignatureLocked) {
+			status = http.StatusUnauthorized
+		}
+		respondJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Contract signed, transaction activated",
+		"data":    signature,
+	})
+}
+
+// GetSignatures handles GET /api/v1/transactions/{id}/contract/signatures - the signing evidence
+func (h *ContractHandler) GetSignatures(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid transaction ID")
+	if !ok {
+		return
+	}
+
+	signatures, err := h.contractUsecase.GetSignatures(id)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, signatures)
+}
+
+// clientIP is the first address of X-Forwarded-For when behind a proxy, else the peer address
+func clientIP(r *http.Request) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
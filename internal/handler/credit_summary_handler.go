@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"net/http"
+
+	"main/internal/usecase"
+)
+
+type CreditSummaryHandler struct {
+	creditSummaryUsecase usecase.CreditSummaryUsecase
+}
+
+func NewCreditSummaryHandler(creditSummaryUsecase usecase.CreditSummaryUsecase) *CreditSummaryHandler {
+	return &CreditSummaryHandler{
+		creditSummaryUsecase: creditSummaryUsecase,
+	}
+}
+
+// GetCreditSummary handles GET /api/v1/consumers/{id}/credit-summary - limits, exposure and next due
+func (h *CreditSummaryHandler) GetCreditSummary(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid consumer ID")
+	if !ok {
+		return
+	}
+
+	summary, err := h.creditSummaryUsecase.GetCreditSummary(id)
+	if err != nil {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, summary)
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"main/internal/usecase"
+)
+
+type DelinquencyHandler struct {
+	delinquencyUsecase usecase.DelinquencyUsecase
+}
+
+func NewDelinquencyHandler(delinquencyUsecase usecase.DelinquencyUsecase) *DelinquencyHandler {
+	return &DelinquencyHandler{
+		delinquencyUsecase: delinquencyUsecase,
+	}
+}
+
+// GetDelinquency handles GET /api/v1/transactions/{id}/delinquency - DPD, collectibility and late fees
+func (h *DelinquencyHandler) GetDelinquency(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid transaction ID")
+	if !ok {
+		return
+	}
+
+	view, err := h.delinquencyUsecase.GetDelinquency(id)
+	if err != nil {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, view)
+}
+
+// RunAging handles POST /api/v1/delinquency/aging?date=YYYY-MM-DD - manual re-run of the daily job
+func (h *DelinquencyHandler) RunAging(w http.ResponseWriter, r *http.Request) {
+	asOf, err := parseOptionalDate(r.URL.Query().Get("date"))
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid date, expected YYYY-MM-DD"})
+		return
+	}
+
+	result, err := h.delinquencyUsecase.RunDailyAging(asOf)
+	if err != nil {
+		log.Println("Error running DPD aging:", err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to run DPD aging"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "DPD aging completed",
+		"data":    result,
+	})
+}
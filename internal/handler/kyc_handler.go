@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"main/internal/usecase"
+)
+
+type KYCHandler struct {
+	kycUsecase usecase.KYCUsecase
+}
+
+func NewKYCHandler(kycUsecase usecase.KYCUsecase) *KYCHandler {
+	return &KYCHandler{
+		kycUsecase: kycUsecase,
+	}
+}
+
+// VerifyConsumer handles POST /api/v1/consumers/{id}/kyc/verify - re-runs automatic KYC checks
+func (h *KYCHandler) VerifyConsumer(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid consumer ID")
+	if !ok {
+		return
+	}
+
+	verification, err := h.kycUsecase.VerifyConsumer(id)
+	if err != nil {
+		log.Println("Error verifying consumer:", err)
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, verification)
+}
+
+// GetVerificationHistory handles GET /api/v1/consumers/{id}/kyc
+func (h *KYCHandler) GetVerificationHistory(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid consumer ID")
+	if !ok {
+		return
+	}
+
+	verifications, err := h.kycUsecase.GetVerificationHistory(id)
+	if err != nil {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": "KYC history not found"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, verifications)
+}
+
+// GetReviewQueue handles GET /api/v1/kyc/reviews - consumers waiting for an analyst
+func (h *KYCHandler) GetReviewQueue(w http.ResponseWriter, r *http.Request) {
+	queue, err := h.kycUsecase.GetManualReviewQueue()
+	if err != nil {
+		log.Println("Error loading KYC review queue:", err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load review queue"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, queue)
+}
+
+// ReviewConsumer handles POST /api/v1/kyc/reviews/{id} - analyst decision for a consumer
+func (h *KYCHandler) ReviewConsumer(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid consumer ID")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Decision   string `json:"decision"`
+		ReviewedBy string `json:"reviewed_by"`
+		Note       string `json:"note"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+		return
+	}
+
+	verification, err := h.kycUsecase.ReviewConsumer(id, req.Decision, req.ReviewedBy, req.Note)
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "KYC review recorded successfully",
+		"data":    verification,
+	})
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"main/internal/usecase"
+)
+
+type LedgerHandler struct {
+	ledgerUsecase usecase.LedgerUsecase
+}
+
+func NewLedgerHandler(ledgerUsecase usecase.LedgerUsecase) *LedgerHandler {
+	return &LedgerHandler{
+		ledgerUsecase: ledgerUsecase,
+	}
+}
+
+// GetTrialBalance handles GET /api/v1/ledger/trial-balance?date=YYYY-MM-DD - balances up to and including the date
+func (h *LedgerHandler) GetTrialBalance(w http.ResponseWriter, r *http.Request) {
+	date, err := parseOptionalDate(r.URL.Query().Get("date"))
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid date, expected YYYY-MM-DD"})
+		return
+	}
+
+	trial, err := h.ledgerUsecase.GetTrialBalance(date)
+	if err != nil {
+		log.Println("Error building trial balance:", err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to build trial balance"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, trial)
+}
+
+// ExportGL handles GET /api/v1/ledger/gl-export?date=YYYY-MM-DD - GL upload file of one posting date
+func (h *LedgerHandler) ExportGL(w http.ResponseWriter, r *http.Request) {
+	date, err := parseOptionalDate(r.URL.Query().Get("date"))
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid date, expected YYYY-MM-DD"})
+		return
+	}
+
+	export, err := h.ledgerUsecase.ExportGL(date)
+	if err != nil {
+		log.Println("Error exporting GL:", err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to export GL"})
+		return
+	}
+
+	w.Header().Set("Content-Type", export.ContentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+export.FileName+`"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(export.Content)
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"main/internal/model"
+	"main/internal/usecase"
+)
+
+type LimitHoldHandler struct {
+	transactionUsecase usecase.TransactionUsecase
+}
+
+func NewLimitHoldHandler(transactionUsecase usecase.TransactionUsecase) *LimitHoldHandler {
+	return &LimitHoldHandler{
+		transactionUsecase: transactionUsecase,
+	}
+}
+
+// AuthorizeHold handles POST /api/v1/limits/holds - reserve limit at cart confirmation
+func (h *LimitHoldHandler) AuthorizeHold(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ConsumerID       uint    `json:"consumer_id"`
+		Tenor            int     `json:"tenor"`
+		Amount           float64 `json:"amount"`
+		MerchantID       uint    `json:"merchant_id"`
+		Reference        string  `json:"reference"`
+		ExpiresInMinutes int     `json:"expires_in_minutes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	hold := &model.LimitHold{
+		ConsumerID: req.ConsumerID,
+		Tenor:      req.Tenor,
+		Amount:     req.Amount,
+		MerchantID: req.MerchantID,
+		Reference:  req.Reference,
+	}
+	if !scopeMerchantID(w, r, &hold.MerchantID) {
+		return
+	}
+	if err := h.transactionUsecase.AuthorizeHold(hold, time.Duration(req.ExpiresInMinutes)*time.Minute); err != nil {
+		log.Println("Error authorizing hold:", err)
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "Limit hold authorized",
+		"data":    hold,
+	})
+}
+
+// CaptureHold handles POST /api/v1/limits/holds/{id}/capture - book the held checkout as a transaction
+func (h *LimitHoldHandler) CaptureHold(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid hold ID")
+	if !ok || !h.holdVisible(w, r, id) {
+		return
+	}
+
+	var transaction model.Transaction
+	if err := json.NewDecoder(r.Body).Decode(&transaction); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.transactionUsecase.CaptureHold(id, &transaction); err != nil {
+		log.Println("Error capturing hold:", err)
+		status := http.StatusBadRequest
+		if errors.Is(err, usecase.ErrHoldExpired) {
+			status = http.StatusConflict
+		}
+		respondJSON(w, status, errorResponse(err))
+		return
+	}
+
+	message := "Limit hold captured"
+	switch transaction.Status {
+	case model.TransactionStatusPendingReview:
+		message = "Limit hold captured, transaction held for fraud review"
+	case model.TransactionStatusPendingSignature:
+		message = "Limit hold captured, contract awaiting consumer signature"
+	case model.TransactionStatusPendingDownPayment:
+		message = "Limit hold captured, contract awaiting down payment receipt"
+	}
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": message,
+		"data":    transaction,
+	})
+}
+
+// VoidHold handles POST /api/v1/limits/holds/{id}/void - release the reservation
+func (h *LimitHoldHandler) VoidHold(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid hold ID")
+	if !ok || !h.holdVisible(w, r, id) {
+		return
+	}
+
+	hold, err := h.transactionUsecase.VoidHold(id)
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Limit hold voided",
+		"data":    hold,
+	})
+}
+
+// GetHold handles GET /api/v1/limits/holds/{id}
+func (h *LimitHoldHandler) GetHold(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid hold ID")
+	if !ok {
+		return
+	}
+
+	hold, err := h.transactionUsecase.GetHold(id)
+	if err != nil || !merchantMayView(r, hold.MerchantID) {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": "Limit hold not found"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, hold)
+}
+
+// holdVisible answers 404 when a merchant API key asks for a hold of another merchant
+func (h *LimitHoldHandler) holdVisible(w http.ResponseWriter, r *http.Request, id uint) bool {
+	if _, scoped := merchantScope(r); !scoped {
+		return true
+	}
+	hold, err := h.transactionUsecase.GetHold(id)
+	if err != nil || !merchantMayView(r, hold.MerchantID) {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": "Limit hold not found"})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"main/internal/middleware"
+	"main/internal/model"
+	"main/internal/usecase"
+)
+
+type MerchantHandler struct {
+	merchantUsecase usecase.MerchantUsecase
+}
+
+func NewMerchantHandler(merchantUsecase usecase.MerchantUsecase) *MerchantHandler {
+	return &MerchantHandler{
+		merchantUsecase: merchantUsecase,
+	}
+}
+
+// RegisterMerchant handles POST /api/v1/merchants
+func (h *MerchantHandler) RegisterMerchant(w http.ResponseWriter, r *http.Request) {
+	var merchant model.Merchant
+	if err := json.NewDecoder(r.Body).Decode(&merchant); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.merchantUsecase.RegisterMerchant(&merchant); err != nil {
+		log.Println("Error registering merchant:", err)
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "Merchant registered successfully",
+		"data":    merchant,
+	})
+}
+
+// GetMerchants handles GET /api/v1/merchants
+func (h *MerchantHandler) GetMerchants(w http.ResponseWriter, r *http.Request) {
+	merchants, err := h.merchantUsecase.GetMerchants()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load merchants"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, merchants)
+}
+
+// GetMerchant handles GET /api/v1/merchants/{id}
+func (h *MerchantHandler) GetMerchant(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid merchant ID")
+	if !ok {
+		return
+	}
+
+	merchant, err := h.merchantUsecase.GetMerchant(id)
+	if err != nil || !merchantMayView(r, id) {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": "Merchant not found"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, merchant)
+}
+
+// UpdateMerchantStatus handles PUT /api/v1/merchants/{id}/status
+func (h *MerchantHandler) UpdateMerchantStatus(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid merchant ID")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Status string `json:"status"` // ACTIVE, SUSPENDED, TERMINATED
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	merchant, err := h.merchantUsecase.UpdateMerchantStatus(id, req.Status)
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Merchant status updated successfully",
+		"data":    merchant,
+	})
+}
+
+// AddBranch handles POST /api/v1/merchants/{id}/branches
+func (h *MerchantHandler) AddBranch(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid merchant ID")
+	if !ok {
+		return
+	}
+
+	var branch model.MerchantBranch
+	if err := json.NewDecoder(r.Body).Decode(&branch); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.merchantUsecase.AddBranch(id, &branch); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "Branch added successfully",
+		"data":    branch,
+	})
+}
+
+// IssueAPIKey handles POST /api/v1/merchants/{id}/api-keys - the plain key is only shown in this response
+func (h *MerchantHandler) IssueAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid merchant ID")
+	if !ok {
+		return
+	}
+
+	plain, key, err := h.merchantUsecase.IssueAPIKey(id)
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "API key issued, store it now as it cannot be shown again",
+		"data": map[string]interface{}{
+			"api_key": plain,
+			"key":     key,
+		},
+	})
+}
+
+// RevokeAPIKey handles DELETE /api/v1/merchants/{id}/api-keys/{keyId}
+func (h *MerchantHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid merchant ID")
+	if !ok {
+		return
+	}
+	keyID, ok := parsePathID(w, r, "keyId", "Invalid API key ID")
+	if !ok {
+		return
+	}
+
+	if err := h.merchantUsecase.RevokeAPIKey(id, keyID); err != nil {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "API key revoked"})
+}
+
+// GetMerchantTransactions handles GET /api/v1/merchants/{id}/transactions?from=&to= (YYYY-MM-DD)
+func (h *MerchantHandler) GetMerchantTransactions(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid merchant ID")
+	if !ok {
+		return
+	}
+	if !merchantMayView(r, id) {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": "Merchant not found"})
+		return
+	}
+
+	var from, to time.Time
+	var err error
+	if value := r.URL.Query().Get("from"); value != "" {
+		if from, err = time.ParseInLocation("2006-01-02", value, time.Local); err != nil {
+			respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid from date, expected YYYY-MM-DD"})
+			return
+		}
+	}
+	if value := r.URL.Query().Get("to"); value != "" {
+		if to, err = time.ParseInLocation("2006-01-02", value, time.Local); err != nil {
+			respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid to date, expected YYYY-MM-DD"})
+			return
+		}
+	}
+
+	report, err := h.merchantUsecase.GetMerchantTransactions(id, from, to)
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, report)
+}
+
+// merchantScope returns the merchant of a merchant API key request
+func merchantScope(r *http.Request) (uint, bool) {
+	return middleware.MerchantIDFromContext(r.Context())
+}
+
+// merchantMayView reports whether the caller may see data of the merchant; back-office requests see everything
+func merchantMayView(r *http.Request, merchantID uint) bool {
+	scope, scoped := merchantScope(r)
+	return !scoped || scope == merchantID
+}
+
+// scopeMerchantID stamps the caller's merchant on a request body, answering 403 when the body names another merchant
+func scopeMerchantID(w http.ResponseWriter, r *http.Request, merchantID *uint) bool {
+	scope, scoped := merchantScope(r)
+	if !scoped {
+		return true
+	}
+	if *merchantID != 0 && *merchantID != scope {
+		respondJSON(w, http.StatusForbidden, map[string]string{"error": "API key does not belong to this merchant"})
+		return false
+	}
+	*merchantID = scope
+	return true
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"main/internal/usecase"
+)
+
+type NotificationHandler struct {
+	notificationUsecase usecase.NotificationUsecase
+}
+
+func NewNotificationHandler(notificationUsecase usecase.NotificationUsecase) *NotificationHandler {
+	return &NotificationHandler{
+		notificationUsecase: notificationUsecase,
+	}
+}
+
+// GetPreference handles GET /api/v1/consumers/{id}/notification-preference
+func (h *NotificationHandler) GetPreference(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid consumer ID")
+	if !ok {
+		return
+	}
+
+	preference, err := h.notificationUsecase.GetPreference(id)
+	if err != nil {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, preference)
+}
+
+// UpdatePreference handles PUT /api/v1/consumers/{id}/notification-preference - language, channels and opt-out
+func (h *NotificationHandler) UpdatePreference(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid consumer ID")
+	if !ok {
+		return
+	}
+
+	var req usecase.PreferenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	preference, err := h.notificationUsecase.UpdatePreference(id, req)
+	if err != nil {
+		log.Println("Error updating notification preference:", err)
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Notification preference updated successfully",
+		"data":    preference,
+	})
+}
+
+// GetDeliveryLog handles GET /api/v1/consumers/{id}/notifications - every delivery attempt, newest first
+func (h *NotificationHandler) GetDeliveryLog(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid consumer ID")
+	if !ok {
+		return
+	}
+
+	entries, err := h.notificationUsecase.GetDeliveryLog(id)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load notifications"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, entries)
+}
+
+// SendDueReminders handles POST /api/v1/notifications/reminders/run?date=YYYY-MM-DD - sends the reminders of the date (default today)
+func (h *NotificationHandler) SendDueReminders(w http.ResponseWriter, r *http.Request) {
+	date, err := parseOptionalDate(r.URL.Query().Get("date"))
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid date, expected YYYY-MM-DD"})
+		return
+	}
+
+	result, err := h.notificationUsecase.SendDueReminders(date)
+	if err != nil {
+		log.Println("Error sending installment reminders:", err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to send installment reminders"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Installment reminders sent",
+		"data":    result,
+	})
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"main/internal/usecase"
+)
+
+type OTPHandler struct {
+	otpUsecase usecase.OTPUsecase
+}
+
+func NewOTPHandler(otpUsecase usecase.OTPUsecase) *OTPHandler {
+	return &OTPHandler{
+		otpUsecase: otpUsecase,
+	}
+}
+
+// CreateChallenge handles POST /api/v1/otp/challenges - sends the consumer a code to approve a transaction.
+// The challenge ID and the code go on the transaction as otp_challenge_id and otp_code.
+func (h *OTPHandler) CreateChallenge(w http.ResponseWriter, r *http.Request) {
+	var req usecase.OTPChallengeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	// Merchant API keys can only ask consent for their own merchant
+	if !scopeMerchantID(w, r, &req.MerchantID) {
+		return
+	}
+
+	challenge, err := h.otpUsecase.CreateChallenge(req)
+	if err != nil {
+		log.Println("Error creating OTP challenge:", err)
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "OTP sent via " + challenge.Channel,
+		"data":    challenge,
+	})
+}
+
+// GetChallenge handles GET /api/v1/otp/challenges/{id}
+func (h *OTPHandler) GetChallenge(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid challenge ID")
+	if !ok {
+		return
+	}
+
+	challenge, err := h.otpUsecase.GetChallenge(id)
+	if err != nil || !merchantMayView(r, challenge.MerchantID) {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": "OTP challenge not found"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, challenge)
+}
+
+// ResendChallenge handles POST /api/v1/otp/challenges/{id}/resend - a fresh code once the cooldown has passed
+func (h *OTPHandler) ResendChallenge(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid challenge ID")
+	if !ok {
+		return
+	}
+	if challenge, err := h.otpUsecase.GetChallenge(id); err != nil || !merchantMayView(r, challenge.MerchantID) {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": "OTP challenge not found"})
+		return
+	}
+
+	challenge, err := h.otpUsecase.ResendChallenge(id)
+	if err != nil {
+		log.Println("Error resending OTP challenge:", err)
+		status := http.StatusBadRequest
+		if errors.Is(err, usecase.ErrOTPCooldown) {
+			status = http.StatusTooManyRequests
+		}
+		respondJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "OTP resent via " + challenge.Channel,
+		"data":    challenge,
+	})
+}
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"main/internal/usecase"
+	"main/internal/va"
+)
+
+// maxCallbackBody caps the size of a bank callback body
+const maxCallbackBody = 64 << 10
+
+type PaymentHandler struct {
+	paymentUsecase        usecase.PaymentUsecase
+	virtualAccountUsecase usecase.VirtualAccountUsecase
+}
+
+func NewPaymentHandler(paymentUsecase usecase.PaymentUsecase, virtualAccountUsecase usecase.VirtualAccountUsecase) *PaymentHandler {
+	return &PaymentHandler{
+		paymentUsecase:        paymentUsecase,
+		virtualAccountUsecase: virtualAccountUsecase,
+	}
+}
+
+// PostPayment handles POST /api/v1/transactions/{id}/payments - installment payment received over the counter
+func (h *PaymentHandler) PostPayment(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid transaction ID")
+	if !ok {
+		return
+	}
+
+	var req usecase.InstallmentPaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	posting, err := h.paymentUsecase.PostInstallmentPayment(id, req)
+	if err != nil {
+		log.Println("Error posting installment payment:", err)
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Payment posted successfully",
+		"data":    posting,
+	})
+}
+
+// GetPayments handles GET /api/v1/transactions/{id}/payments
+func (h *PaymentHandler) GetPayments(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid transaction ID")
+	if !ok {
+		return
+	}
+
+	payments, err := h.paymentUsecase.GetPayments(id)
+	if err != nil {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, payments)
+}
+
+// IssueVirtualAccount handles POST /api/v1/transactions/{id}/virtual-accounts
+func (h *PaymentHandler) IssueVirtualAccount(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid transaction ID")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		BankCode string `json:"bank_code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	account, err := h.virtualAccountUsecase.IssueVirtualAccount(id, req.BankCode)
+	if err != nil {
+		log.Println("Error issuing virtual account:", err)
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Virtual account issued successfully",
+		"data":    account,
+	})
+}
+
+// GetVirtualAccounts handles GET /api/v1/transactions/{id}/virtual-accounts
+func (h *PaymentHandler) GetVirtualAccounts(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid transaction ID")
+	if !ok {
+		return
+	}
+
+	accounts, err := h.virtualAccountUsecase.GetVirtualAccounts(id)
+	if err != nil {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, accounts)
+}
+
+// HandleCallback handles POST /api/v1/payments/callbacks/{bank} - payment notification signed by the bank
+// in the X-Signature header over the X-Timestamp header and the raw body
+func (h *PaymentHandler) HandleCallback(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxCallbackBody))
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	callback, err := h.virtualAccountUsecase.HandleCallback(r.PathValue("bank"), r.Header.Get("X-Timestamp"), r.Header.Get("X-Signature"), body)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, usecase.ErrUnknownBank):
+			status = http.StatusNotFound
+		case errors.Is(err, va.ErrInvalidSignature), errors.Is(err, va.ErrStaleCallback):
+			status = http.StatusUnauthorized
+		case errors.Is(err, va.ErrInvalidCallback):
+			status = http.StatusBadRequest
+		default:
+			log.Println("Error handling payment callback:", err)
+		}
+		respondJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Callback received",
+		"data":    callback,
+	})
+}
+
+// GetSuspense handles GET /api/v1/payments/suspense - callbacks waiting for an operator
+func (h *PaymentHandler) GetSuspense(w http.ResponseWriter, r *http.Request) {
+	callbacks, err := h.virtualAccountUsecase.GetSuspense()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load payment suspense"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, callbacks)
+}
+
+// ResolveSuspense handles POST /api/v1/payments/suspense/{id}/resolve - post to a contract or refund
+func (h *PaymentHandler) ResolveSuspense(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid callback ID")
+	if !ok {
+		return
+	}
+
+	var req usecase.SuspenseResolutionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	callback, err := h.virtualAccountUsecase.ResolveSuspense(id, req)
+	if err != nil {
+		log.Println("Error resolving payment suspense:", err)
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Payment suspense resolved successfully",
+		"data":    callback,
+	})
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"main/internal/usecase"
+)
+
+type PayoffHandler struct {
+	payoffUsecase usecase.PayoffUsecase
+}
+
+func NewPayoffHandler(payoffUsecase usecase.PayoffUsecase) *PayoffHandler {
+	return &PayoffHandler{
+		payoffUsecase: payoffUsecase,
+	}
+}
+
+// GetPayoffQuote handles GET /api/v1/transactions/{id}/payoff-quote?date=YYYY-MM-DD
+func (h *PayoffHandler) GetPayoffQuote(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid transaction ID")
+	if !ok {
+		return
+	}
+
+	quoteDate, err := parseOptionalDate(r.URL.Query().Get("date"))
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid date, expected YYYY-MM-DD"})
+		return
+	}
+
+	quote, err := h.payoffUsecase.GetPayoffQuote(id, quoteDate)
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, quote)
+}
+
+// Settle handles POST /api/v1/transactions/{id}/settle - early payoff against a quote
+func (h *PayoffHandler) Settle(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid transaction ID")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		QuoteDate string  `json:"quote_date"`
+		Amount    float64 `json:"amount"`
+		Reference string  `json:"reference"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	quoteDate, err := parseOptionalDate(req.QuoteDate)
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid quote_date, expected YYYY-MM-DD"})
+		return
+	}
+
+	payment, err := h.payoffUsecase.Settle(id, quoteDate, req.Amount, req.Reference)
+	if err != nil {
+		log.Println("Error settling transaction:", err)
+		status := http.StatusBadRequest
+		if errors.Is(err, usecase.ErrPayoffQuoteExpired) {
+			status = http.StatusConflict
+		}
+		respondJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Transaction settled successfully",
+		"data":    payment,
+	})
+}
+
+// parseOptionalDate parses a YYYY-MM-DD date in local time, defaulting to now when empty
+func parseOptionalDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Now(), nil
+	}
+	return time.ParseInLocation("2006-01-02", value, time.Local)
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"main/internal/model"
+	"main/internal/usecase"
+)
+
+type ProductHandler struct {
+	productUsecase usecase.ProductUsecase
+}
+
+func NewProductHandler(productUsecase usecase.ProductUsecase) *ProductHandler {
+	return &ProductHandler{
+		productUsecase: productUsecase,
+	}
+}
+
+// CreateProduct handles POST /api/v1/products
+func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
+	var product model.Product
+	if err := json.NewDecoder(r.Body).Decode(&product); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.productUsecase.CreateProduct(&product); err != nil {
+		log.Println("Error creating product:", err)
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "Product created successfully",
+		"data":    product,
+	})
+}
+
+// GetProducts handles GET /api/v1/products
+func (h *ProductHandler) GetProducts(w http.ResponseWriter, r *http.Request) {
+	products, err := h.productUsecase.GetProducts()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load products"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, products)
+}
+
+// GetProduct handles GET /api/v1/products/{code}
+func (h *ProductHandler) GetProduct(w http.ResponseWriter, r *http.Request) {
+	product, err := h.productUsecase.GetProduct(r.PathValue("code"))
+	if err != nil {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": "Product not found"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, product)
+}
+
+// AddPromo handles POST /api/v1/products/{code}/promos
+func (h *ProductHandler) AddPromo(w http.ResponseWriter, r *http.Request) {
+	var promo model.ProductPromo
+	if err := json.NewDecoder(r.Body).Decode(&promo); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.productUsecase.AddPromo(r.PathValue("code"), &promo); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "Promo added successfully",
+		"data":    promo,
+	})
+}
+
+// GetPricing handles GET /api/v1/products/{code}/pricing?tenor=&otr=&dp= - price simulation
+func (h *ProductHandler) GetPricing(w http.ResponseWriter, r *http.Request) {
+	tenor, err := strconv.Atoi(r.URL.Query().Get("tenor"))
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid tenor"})
+		return
+	}
+	otr, err := strconv.ParseFloat(r.URL.Query().Get("otr"), 64)
+	if err != nil || otr <= 0 {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid otr"})
+		return
+	}
+
+	var downPayment float64
+	if value := r.URL.Query().Get("dp"); value != "" {
+		if downPayment, err = strconv.ParseFloat(value, 64); err != nil {
+			respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid dp"})
+			return
+		}
+	}
+
+	pricing, err := h.productUsecase.Price(r.PathValue("code"), tenor, otr, downPayment, time.Now())
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, pricing)
+}
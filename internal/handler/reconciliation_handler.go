@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+
+	"main/internal/usecase"
+)
+
+// maxStatementFile caps the size of an uploaded bank statement
+const maxStatementFile = 10 << 20
+
+type ReconciliationHandler struct {
+	reconciliationUsecase usecase.ReconciliationUsecase
+}
+
+func NewReconciliationHandler(reconciliationUsecase usecase.ReconciliationUsecase) *ReconciliationHandler {
+	return &ReconciliationHandler{
+		reconciliationUsecase: reconciliationUsecase,
+	}
+}
+
+// ImportStatement handles POST /api/v1/reconciliation/statements?format=CSV|MT940&file_name=&imported_by=
+// with the statement file as the body. The format is detected when omitted.
+func (h *ReconciliationHandler) ImportStatement(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxStatementFile))
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	query := r.URL.Query()
+	report, err := h.reconciliationUsecase.ImportStatement(data, query.Get("format"), query.Get("file_name"), query.Get("imported_by"))
+	if err != nil {
+		log.Println("Error importing bank statement:", err)
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Bank statement imported successfully",
+		"data":    report,
+	})
+}
+
+// GetStatements handles GET /api/v1/reconciliation/statements
+func (h *ReconciliationHandler) GetStatements(w http.ResponseWriter, r *http.Request) {
+	statements, err := h.reconciliationUsecase.GetStatements()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load bank statements"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, statements)
+}
+
+// GetReport handles GET /api/v1/reconciliation/statements/{id} - matched, partial and unmatched lines
+func (h *ReconciliationHandler) GetReport(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid statement ID")
+	if !ok {
+		return
+	}
+
+	report, err := h.reconciliationUsecase.GetReport(id)
+	if err != nil {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, report)
+}
+
+// MatchLine handles POST /api/v1/reconciliation/lines/{id}/match - post, link or ignore a line
+func (h *ReconciliationHandler) MatchLine(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid statement line ID")
+	if !ok {
+		return
+	}
+
+	var req usecase.ManualMatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	line, err := h.reconciliationUsecase.MatchLine(id, req)
+	if err != nil {
+		log.Println("Error matching statement line:", err)
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Statement line matched successfully",
+		"data":    line,
+	})
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"main/internal/usecase"
+)
+
+type RestructuringHandler struct {
+	restructuringUsecase usecase.RestructuringUsecase
+}
+
+func NewRestructuringHandler(restructuringUsecase usecase.RestructuringUsecase) *RestructuringHandler {
+	return &RestructuringHandler{
+		restructuringUsecase: restructuringUsecase,
+	}
+}
+
+// RequestRestructuring handles POST /api/v1/transactions/{id}/restructurings - proposes new terms for approval
+func (h *RestructuringHandler) RequestRestructuring(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid transaction ID")
+	if !ok {
+		return
+	}
+
+	var req usecase.RestructureRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	restructuring, err := h.restructuringUsecase.RequestRestructuring(id, req)
+	if err != nil {
+		log.Println("Error requesting restructuring:", err)
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "Restructuring submitted for approval",
+		"data":    restructuring,
+	})
+}
+
+// GetRestructurings handles GET /api/v1/transactions/{id}/restructurings
+func (h *RestructuringHandler) GetRestructurings(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid transaction ID")
+	if !ok {
+		return
+	}
+
+	restructurings, err := h.restructuringUsecase.GetRestructurings(id)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load restructurings"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, restructurings)
+}
+
+// GetPendingRestructurings handles GET /api/v1/restructurings/pending - the approval queue
+func (h *RestructuringHandler) GetPendingRestructurings(w http.ResponseWriter, r *http.Request) {
+	restructurings, err := h.restructuringUsecase.GetPendingRestructurings()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load restructurings"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, restructurings)
+}
+
+// ReviewRestructuring handles POST /api/v1/restructurings/{id}/review
+func (h *RestructuringHandler) ReviewRestructuring(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid restructuring ID")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Decision   string `json:"decision"` // APPROVE, REJECT
+		ReviewedBy string `json:"reviewed_by"`
+		Note       string `json:"note"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+		return
+	}
+	if req.Decision != "APPROVE" && req.Decision != "REJECT" {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Decision must be APPROVE or REJECT"})
+		return
+	}
+
+	restructuring, err := h.restructuringUsecase.ReviewRestructuring(id, req.Decision == "APPROVE", req.ReviewedBy, req.Note)
+	if err != nil {
+		log.Println("Error reviewing restructuring:", err)
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Restructuring review recorded successfully",
+		"data":    restructuring,
+	})
+}
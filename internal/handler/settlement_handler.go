@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"main/internal/usecase"
+)
+
+type SettlementHandler struct {
+	settlementUsecase usecase.SettlementUsecase
+}
+
+func NewSettlementHandler(settlementUsecase usecase.SettlementUsecase) *SettlementHandler {
+	return &SettlementHandler{
+		settlementUsecase: settlementUsecase,
+	}
+}
+
+// GenerateBatches handles POST /api/v1/settlements/batches?date=YYYY-MM-DD - manual re-run of the daily batching
+func (h *SettlementHandler) GenerateBatches(w http.ResponseWriter, r *http.Request) {
+	date, err := parseOptionalDate(r.URL.Query().Get("date"))
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid date, expected YYYY-MM-DD"})
+		return
+	}
+
+	batches, err := h.settlementUsecase.GenerateBatches(date)
+	if err != nil {
+		log.Println("Error generating settlement batches:", err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to generate settlement batches"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Settlement batches generated",
+		"data":    batches,
+	})
+}
+
+// GetBatches handles GET /api/v1/settlements/batches?date=YYYY-MM-DD
+func (h *SettlementHandler) GetBatches(w http.ResponseWriter, r *http.Request) {
+	date, err := parseOptionalDate(r.URL.Query().Get("date"))
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid date, expected YYYY-MM-DD"})
+		return
+	}
+
+	batches, err := h.settlementUsecase.GetBatches(date)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load settlement batches"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, batches)
+}
+
+// GetBatch handles GET /api/v1/settlements/batches/{id} - batch with its contracts
+func (h *SettlementHandler) GetBatch(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid batch ID")
+	if !ok {
+		return
+	}
+
+	batch, err := h.settlementUsecase.GetBatch(id)
+	if err != nil {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": "Settlement batch not found"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, batch)
+}
+
+// ExportBatches handles POST /api/v1/settlements/export?date=YYYY-MM-DD&format=CSV|FIXED - bank bulk-transfer file
+func (h *SettlementHandler) ExportBatches(w http.ResponseWriter, r *http.Request) {
+	date, err := parseOptionalDate(r.URL.Query().Get("date"))
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid date, expected YYYY-MM-DD"})
+		return
+	}
+
+	export, err := h.settlementUsecase.ExportBatches(date, r.URL.Query().Get("format"))
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	w.Header().Set("Content-Type", export.ContentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+export.FileName+`"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(export.Content)
+}
+
+// ImportConfirmation handles POST /api/v1/settlements/confirmations with the bank's text/csv confirmation file
+func (h *SettlementHandler) ImportConfirmation(w http.ResponseWriter, r *http.Request) {
+	result, err := h.settlementUsecase.ImportConfirmation(r.Body)
+	if err != nil {
+		log.Println("Error importing settlement confirmation:", err)
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Settlement confirmation imported",
+		"data":    result,
+	})
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"main/internal/model"
+	"main/internal/usecase"
+)
+
+// maxStatementDocument caps the size of a statement uploaded for verification
+const maxStatementDocument = 10 << 20
+
+type StatementHandler struct {
+	statementUsecase usecase.StatementUsecase
+}
+
+func NewStatementHandler(statementUsecase usecase.StatementUsecase) *StatementHandler {
+	return &StatementHandler{
+		statementUsecase: statementUsecase,
+	}
+}
+
+// GetStatement handles GET /api/v1/consumers/{id}/statements?from=&to=&transaction_id=&format=json|pdf.
+// The body is the archived document byte for byte, so its SHA-256 matches the X-Document-SHA256 header.
+func (h *StatementHandler) GetStatement(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid consumer ID")
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	req := usecase.StatementRequest{Format: query.Get("format")}
+	for _, param := range []struct {
+		name   string
+		target *time.Time
+	}{{"from", &req.From}, {"to", &req.To}} {
+		if value := query.Get(param.name); value != "" {
+			date, err := time.ParseInLocation("2006-01-02", value, time.Local)
+			if err != nil {
+				respondJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("Invalid %s date, expected YYYY-MM-DD", param.name)})
+				return
+			}
+			*param.target = date
+		}
+	}
+	if value := query.Get("transaction_id"); value != "" {
+		transactionID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid transaction ID"})
+			return
+		}
+		req.TransactionID = uint(transactionID)
+	}
+
+	document, err := h.statementUsecase.GenerateStatement(id, req)
+	if err != nil {
+		log.Println("Error generating statement:", err)
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeStatementDocument(w, document.Archive, document.ContentType, document.Content)
+}
+
+// GetArchives handles GET /api/v1/consumers/{id}/statements/archive - statements handed out, without content
+func (h *StatementHandler) GetArchives(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid consumer ID")
+	if !ok {
+		return
+	}
+
+	archives, err := h.statementUsecase.GetArchives(id)
+	if err != nil {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, archives)
+}
+
+// GetArchive handles GET /api/v1/statements/archive/{id} - an archived statement as it was handed out
+func (h *StatementHandler) GetArchive(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid archive ID")
+	if !ok {
+		return
+	}
+
+	archive, err := h.statementUsecase.GetArchive(id)
+	if err != nil {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	}
+
+	contentType := "application/json"
+	if archive.Format == model.StatementFormatPDF {
+		contentType = "application/pdf"
+	}
+	writeStatementDocument(w, archive, contentType, archive.Content)
+}
+
+// VerifyStatement handles POST /api/v1/statements/verify with a statement file as the body
+func (h *StatementHandler) VerifyStatement(w http.ResponseWriter, r *http.Request) {
+	content, err := io.ReadAll(io.LimitReader(r.Body, maxStatementDocument))
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	archive, err := h.statementUsecase.VerifyStatement(content)
+	if err != nil {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Statement matches the archive",
+		"data":    archive,
+	})
+}
+
+func writeStatementDocument(w http.ResponseWriter, archive *model.StatementArchive, contentType string, content []byte) {
+	w.Header().Set("Content-Type", contentType)
+	if archive.Format == model.StatementFormatPDF {
+		w.Header().Set("Content-Disposition", `attachment; filename="`+archive.FileName+`"`)
+	}
+	w.Header().Set("X-Statement-Archive-ID", strconv.FormatUint(uint64(archive.ID), 10))
+	w.Header().Set("X-Document-SHA256", archive.DocumentHash)
+	w.WriteHeader(http.StatusOK)
+	w.Write(content)
+}
@@ -34,16 +34,31 @@ func (h *TransactionHandler) CreateTransaction(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	// Merchant API keys can only originate contracts for their own merchant
+	if !scopeMerchantID(w, r, &transaction.MerchantID) {
+		return
+	}
+
 	// This method uses mutex to handle concurrent transactions safely
 	if err := h.transactionUsecase.CreateTransaction(&transaction); err != nil {
 		log.Println("Error creating transaction:", err)
-		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		respondJSON(w, http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
+	message := "Transaction created successfully"
+	switch transaction.Status {
+	case model.TransactionStatusPendingReview:
+		message = "Transaction held for fraud review"
+	case model.TransactionStatusPendingSignature:
+		message = "Transaction created, contract awaiting consumer signature"
+	case model.TransactionStatusPendingDownPayment:
+		message = "Transaction created, contract awaiting down payment receipt"
+	}
+
 	w.WriteHeader(http.StatusCreated)
 	respondJSON(w, http.StatusCreated, map[string]interface{}{
-		"message": "Transaction created successfully",
+		"message": message,
 		"data":    transaction,
 	})
 }
@@ -62,7 +77,7 @@ func (h *TransactionHandler) GetTransaction(w http.ResponseWriter, r *http.Reque
 	}
 
 	transaction, err := h.transactionUsecase.GetTransaction(uint(id))
-	if err != nil {
+	if err != nil || !merchantMayView(r, transaction.MerchantID) {
 		respondJSON(w, http.StatusNotFound, map[string]string{"error": "Transaction not found"})
 		return
 	}
@@ -89,10 +104,20 @@ func (h *TransactionHandler) GetConsumerTransactions(w http.ResponseWriter, r *h
 		return
 	}
 
+	if merchantID, scoped := merchantScope(r); scoped {
+		owned := []model.Transaction{}
+		for _, transaction := range transactions {
+			if transaction.MerchantID == merchantID {
+				owned = append(owned, transaction)
+			}
+		}
+		transactions = owned
+	}
+
 	respondJSON(w, http.StatusOK, transactions)
 }
 
-// UpdateTransactionStatus handles PUT /api/transactions/{id}/status
+// UpdateTransactionStatus handles PUT /api/transactions/{id}/status - closes a fully paid contract as COMPLETED
 func (h *TransactionHandler) UpdateTransactionStatus(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPut {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -121,3 +146,72 @@ func (h *TransactionHandler) UpdateTransactionStatus(w http.ResponseWriter, r *h
 
 	respondJSON(w, http.StatusOK, map[string]string{"message": "Transaction status updated successfully"})
 }
+
+// GetFlaggedTransactions handles GET /api/v1/fraud/reviews - transactions held by the fraud rules
+func (h *TransactionHandler) GetFlaggedTransactions(w http.ResponseWriter, r *http.Request) {
+	transactions, err := h.transactionUsecase.GetFlaggedTransactions()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load fraud reviews"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, transactions)
+}
+
+// ReviewFlaggedTransaction handles POST /api/v1/fraud/reviews/{id}
+func (h *TransactionHandler) ReviewFlaggedTransaction(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid transaction ID")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Decision   string `json:"decision"` // APPROVE, REJECT
+		ReviewedBy string `json:"reviewed_by"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+		return
+	}
+	if req.Decision != "APPROVE" && req.Decision != "REJECT" {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Decision must be APPROVE or REJECT"})
+		return
+	}
+
+	transaction, err := h.transactionUsecase.ReviewFlaggedTransaction(id, req.Decision == "APPROVE", req.ReviewedBy)
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Fraud review recorded successfully",
+		"data":    transaction,
+	})
+}
+
+// RecordUpfrontPayment handles POST /api/v1/transactions/{id}/upfront-payments - records the admin fee or down payment receipt
+func (h *TransactionHandler) RecordUpfrontPayment(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid transaction ID")
+	if !ok {
+		return
+	}
+
+	var req usecase.UpfrontPaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+		return
+	}
+
+	payment, err := h.transactionUsecase.RecordUpfrontPayment(id, req)
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "Upfront payment recorded successfully",
+		"data":    payment,
+	})
+}
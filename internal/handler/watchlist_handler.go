@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"main/internal/model"
+	"main/internal/usecase"
+)
+
+type WatchlistHandler struct {
+	watchlistUsecase usecase.WatchlistUsecase
+}
+
+func NewWatchlistHandler(watchlistUsecase usecase.WatchlistUsecase) *WatchlistHandler {
+	return &WatchlistHandler{
+		watchlistUsecase: watchlistUsecase,
+	}
+}
+
+// AddEntry handles POST /api/v1/watchlist/entries
+func (h *WatchlistHandler) AddEntry(w http.ResponseWriter, r *http.Request) {
+	var entry model.WatchlistEntry
+	if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+		return
+	}
+
+	if err := h.watchlistUsecase.AddEntry(&entry); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "Watchlist entry added successfully",
+		"data":    entry,
+	})
+}
+
+// ImportCSV handles POST /api/v1/watchlist/import?source=&list_type=&replace= with a text/csv body
+func (h *WatchlistHandler) ImportCSV(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	replace := query.Get("replace") == "true"
+
+	result, err := h.watchlistUsecase.ImportCSV(query.Get("source"), query.Get("list_type"), r.Body, replace)
+	if err != nil {
+		log.Println("Error importing watchlist:", err)
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Watchlist imported successfully",
+		"data":    result,
+	})
+}
+
+// GetHits handles GET /api/v1/watchlist/hits?status=
+func (h *WatchlistHandler) GetHits(w http.ResponseWriter, r *http.Request) {
+	hits, err := h.watchlistUsecase.GetHits(r.URL.Query().Get("status"))
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load watchlist hits"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, hits)
+}
+
+// ReviewHit handles POST /api/v1/watchlist/hits/{id}/review
+func (h *WatchlistHandler) ReviewHit(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid hit ID")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Decision   string `json:"decision"`
+		ReviewedBy string `json:"reviewed_by"`
+		Note       string `json:"note"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+		return
+	}
+
+	hit, err := h.watchlistUsecase.ReviewHit(id, req.Decision, req.ReviewedBy, req.Note)
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Watchlist hit reviewed successfully",
+		"data":    hit,
+	})
+}
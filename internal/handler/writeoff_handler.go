@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"main/internal/usecase"
+)
+
+type WriteOffHandler struct {
+	writeOffUsecase usecase.WriteOffUsecase
+}
+
+func NewWriteOffHandler(writeOffUsecase usecase.WriteOffUsecase) *WriteOffHandler {
+	return &WriteOffHandler{
+		writeOffUsecase: writeOffUsecase,
+	}
+}
+
+// GetEligibleContracts handles GET /api/v1/write-offs/eligible - DEFAULTED contracts past the write-off DPD
+func (h *WriteOffHandler) GetEligibleContracts(w http.ResponseWriter, r *http.Request) {
+	transactions, err := h.writeOffUsecase.GetEligibleContracts()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load eligible contracts"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, transactions)
+}
+
+// RequestWriteOff handles POST /api/v1/transactions/{id}/write-off - proposes the write-off for approval
+func (h *WriteOffHandler) RequestWriteOff(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid transaction ID")
+	if !ok {
+		return
+	}
+
+	var req usecase.WriteOffRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	writeOff, err := h.writeOffUsecase.RequestWriteOff(id, req)
+	if err != nil {
+		log.Println("Error requesting write-off:", err)
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "Write-off submitted for approval",
+		"data":    writeOff,
+	})
+}
+
+// GetPendingWriteOffs handles GET /api/v1/write-offs/pending - the approval queue
+func (h *WriteOffHandler) GetPendingWriteOffs(w http.ResponseWriter, r *http.Request) {
+	writeOffs, err := h.writeOffUsecase.GetPendingWriteOffs()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load write-offs"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, writeOffs)
+}
+
+// ReviewWriteOff handles POST /api/v1/write-offs/{id}/review
+func (h *WriteOffHandler) ReviewWriteOff(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid write-off ID")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Decision   string `json:"decision"` // APPROVE, REJECT
+		ReviewedBy string `json:"reviewed_by"`
+		Note       string `json:"note"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request format"})
+		return
+	}
+	if req.Decision != "APPROVE" && req.Decision != "REJECT" {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Decision must be APPROVE or REJECT"})
+		return
+	}
+
+	writeOff, err := h.writeOffUsecase.ReviewWriteOff(id, req.Decision == "APPROVE", req.ReviewedBy, req.Note)
+	if err != nil {
+		log.Println("Error reviewing write-off:", err)
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Write-off review recorded successfully",
+		"data":    writeOff,
+	})
+}
+
+// RecordRecovery handles POST /api/v1/transactions/{id}/recoveries - money received after write-off
+func (h *WriteOffHandler) RecordRecovery(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid transaction ID")
+	if !ok {
+		return
+	}
+
+	var req usecase.RecoveryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	recovery, err := h.writeOffUsecase.RecordRecovery(id, req)
+	if err != nil {
+		log.Println("Error recording recovery:", err)
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "Recovery recorded successfully",
+		"data":    recovery,
+	})
+}
+
+// GetRecoveries handles GET /api/v1/transactions/{id}/recoveries
+func (h *WriteOffHandler) GetRecoveries(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePathID(w, r, "id", "Invalid transaction ID")
+	if !ok {
+		return
+	}
+
+	recoveries, err := h.writeOffUsecase.GetRecoveries(id)
+	if err != nil {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, recoveries)
+}
+
+// GetRecoveryByVintage handles GET /api/v1/write-offs/recovery-by-vintage
+func (h *WriteOffHandler) GetRecoveryByVintage(w http.ResponseWriter, r *http.Request) {
+	report, err := h.writeOffUsecase.GetRecoveryByVintage()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to build recovery report"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,114 @@
+package ktp
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"time"
+
+	"main/internal/model"
+)
+
+// Gender values derived from the NIK date of birth segment
+const (
+	GenderMale   = "MALE"
+	GenderFemale = "FEMALE"
+)
+
+var (
+	ErrInvalidNIKFormat    = errors.New("NIK harus terdiri dari 16 digit angka")
+	ErrUnknownProvince     = errors.New("kode provinsi pada NIK tidak dikenal")
+	ErrUnknownRegency      = errors.New("kode kabupaten/kota pada NIK tidak dikenal")
+	ErrInvalidDistrict     = errors.New("kode kecamatan pada NIK tidak valid")
+	ErrInvalidNIKBirthDate = errors.New("tanggal lahir pada NIK tidak valid")
+	ErrInvalidNIKSequence  = errors.New("nomor urut pada NIK tidak valid")
+)
+
+var nikPattern = regexp.MustCompile(`^[0-9]{16}$`)
+
+// ParseNIK decodes and validates a NIK against the embedded region table
+//
+// Layout: PP RR DD ddmmyy SSSS
+//   - PP   province code (Kemendagri)
+//   - RR   regency/city code
+//   - DD   district (kecamatan) code
+//   - ddmmyy date of birth, day + 40 for women
+//   - SSSS registration sequence number
+//
+// Regency and district codes are only looked up where the table lists them for the parent region;
+// RegencyChecked and DistrictChecked tell the caller which levels were.
+func ParseNIK(nik string) (*model.NIKInfo, error) {
+	return parseNIKAt(nik, time.Now())
+}
+
+// parseNIKAt decodes a NIK using now to resolve the two digit birth year
+func parseNIKAt(nik string, now time.Time) (*model.NIKInfo, error) {
+	if !nikPattern.MatchString(nik) {
+		return nil, ErrInvalidNIKFormat
+	}
+
+	info := &model.NIKInfo{
+		ProvinceCode:   nik[0:2],
+		RegencyCode:    nik[2:4],
+		DistrictCode:   nik[4:6],
+		SequenceNumber: nik[12:16],
+	}
+
+	province, ok := ProvinceName(info.ProvinceCode)
+	if !ok {
+		return nil, ErrUnknownProvince
+	}
+	info.Province = province
+
+	// Regencies and districts are only looked up where the table lists them for the parent region
+	if info.RegencyCode == "00" {
+		return nil, ErrUnknownRegency
+	}
+	if regions.listed[info.ProvinceCode] {
+		regency, ok := RegencyName(info.ProvinceCode, info.RegencyCode)
+		if !ok {
+			return nil, ErrUnknownRegency
+		}
+		info.Regency = regency
+		info.RegencyChecked = true
+	}
+
+	if info.DistrictCode == "00" {
+		return nil, ErrInvalidDistrict
+	}
+	if regions.listed[info.ProvinceCode+"."+info.RegencyCode] {
+		district, ok := DistrictName(info.ProvinceCode, info.RegencyCode, info.DistrictCode)
+		if !ok {
+			return nil, ErrInvalidDistrict
+		}
+		info.District = district
+		info.DistrictChecked = true
+	}
+	if info.SequenceNumber == "0000" {
+		return nil, ErrInvalidNIKSequence
+	}
+
+	day, _ := strconv.Atoi(nik[6:8])
+	month, _ := strconv.Atoi(nik[8:10])
+	year, _ := strconv.Atoi(nik[10:12])
+
+	info.Gender = GenderMale
+	if day > 40 {
+		info.Gender = GenderFemale
+		day -= 40
+	}
+
+	// NIK only carries two year digits; a year that lies in the future belongs to the previous century
+	year += 2000
+	if year > now.Year() {
+		year -= 100
+	}
+
+	dob := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if day < 1 || month < 1 || month > 12 || dob.Day() != day || dob.After(now) {
+		return nil, ErrInvalidNIKBirthDate
+	}
+	info.DateOfBirth = dob
+
+	return info, nil
+}
@@ -0,0 +1,98 @@
+package ktp
+
+import (
+	"testing"
+	"time"
+)
+
+var referenceNow = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Test: Valid NIK for a man born in Jakarta Barat
+func TestParseNIK_Male(t *testing.T) {
+	info, err := parseNIKAt("3174011501900001", referenceNow)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if info.Province != "DKI JAKARTA" || info.Regency != "KOTA ADMINISTRASI JAKARTA BARAT" {
+		t.Errorf("Unexpected region %s / %s", info.Province, info.Regency)
+	}
+	if info.Gender != GenderMale {
+		t.Errorf("Expected gender %s, got %s", GenderMale, info.Gender)
+	}
+	if !info.DateOfBirth.Equal(time.Date(1990, 1, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected date of birth %v", info.DateOfBirth)
+	}
+	if info.SequenceNumber != "0001" {
+		t.Errorf("Expected sequence 0001, got %s", info.SequenceNumber)
+	}
+}
+
+// Test: Women have 40 added to the day of birth
+func TestParseNIK_Female(t *testing.T) {
+	info, err := parseNIKAt("3273015506050002", referenceNow)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if info.Gender != GenderFemale {
+		t.Errorf("Expected gender %s, got %s", GenderFemale, info.Gender)
+	}
+	if !info.DateOfBirth.Equal(time.Date(2005, 6, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected date of birth %v", info.DateOfBirth)
+	}
+}
+
+// Test: Province without listed regencies only validates the province
+func TestParseNIK_ProvinceOnly(t *testing.T) {
+	info, err := parseNIKAt("1271010101850003", referenceNow)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if info.Province != "SUMATERA UTARA" || info.Regency != "" {
+		t.Errorf("Unexpected region %s / %s", info.Province, info.Regency)
+	}
+	if info.RegencyChecked || info.DistrictChecked {
+		t.Error("Expected the unlisted regency and district reported as not checked")
+	}
+}
+
+// Test: Invalid NIKs
+func TestParseNIK_Invalid(t *testing.T) {
+	cases := map[string]error{
+		"12345":            ErrInvalidNIKFormat,
+		"31740115019000AB": ErrInvalidNIKFormat,
+		"9974011501900001": ErrUnknownProvince,
+		"3199011501900001": ErrUnknownRegency,
+		"3174001501900001": ErrInvalidDistrict,
+		"3174013202900001": ErrInvalidNIKBirthDate, // 32 Feb
+		"3174011513900001": ErrInvalidNIKBirthDate, // month 13
+		"3174011501900000": ErrInvalidNIKSequence,
+	}
+
+	for nik, expected := range cases {
+		if _, err := parseNIKAt(nik, referenceNow); err != expected {
+			t.Errorf("NIK %s: expected %v, got %v", nik, expected, err)
+		}
+	}
+}
+
+// Test: Districts are checked for regencies whose districts the table lists
+func TestParseNIK_District(t *testing.T) {
+	full := regions
+	defer func() { regions = full }()
+	regions = loadRegionTable("kode,nama\n31,DKI JAKARTA\n31.74,KOTA ADMINISTRASI JAKARTA BARAT\n31.74.01,CENGKARENG\n")
+
+	info, err := parseNIKAt("3174011501900001", referenceNow)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if info.District != "CENGKARENG" || !info.RegencyChecked || !info.DistrictChecked {
+		t.Errorf("Expected regency and district checked, got %+v", info)
+	}
+
+	if _, err := parseNIKAt("3174091501900001", referenceNow); err != ErrInvalidDistrict {
+		t.Errorf("Expected ErrInvalidDistrict for an unlisted district, got %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package ktp
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"log"
+	"strings"
+)
+
+// wilayahCSV is the Kemendagri region code table (kode wilayah administrasi).
+// Province rows use a two digit code ("31"), regency rows "31.74" and district rows "31.74.01".
+//
+// Scope: the embedded table lists all 38 provinces, but regencies only for DKI Jakarta (31),
+// Jawa Barat (32) and Banten (36) and no districts yet. A level is only checked where the table
+// lists it for the parent region and ParseNIK reports the levels it could not check; whether such
+// NIKs are accepted is the registration NIK policy (NIK_SKIP_UNLISTED_REGENCIES/_DISTRICTS).
+// Dropping in the full Kemendagri export turns on regency and district checks everywhere
+// without a code change.
+//
+//go:embed wilayah.csv
+var wilayahCSV string
+
+// regionTable holds province, regency and district names keyed by their dotted code
+type regionTable struct {
+	provinces map[string]string
+	regencies map[string]string
+	districts map[string]string
+	// listed marks the parent codes whose children are in the table: "31" for its regencies,
+	// "31.74" for its districts
+	listed map[string]bool
+}
+
+var regions = loadRegionTable(wilayahCSV)
+
+func loadRegionTable(data string) *regionTable {
+	table := &regionTable{
+		provinces: make(map[string]string),
+		regencies: make(map[string]string),
+		districts: make(map[string]string),
+		listed:    make(map[string]bool),
+	}
+
+	records, err := csv.NewReader(strings.NewReader(data)).ReadAll()
+	if err != nil {
+		log.Fatal("Gagal membaca tabel wilayah:", err)
+	}
+
+	for i, record := range records {
+		if i == 0 || len(record) < 2 {
+			continue // header
+		}
+		code, name := strings.TrimSpace(record[0]), strings.TrimSpace(record[1])
+		switch strings.Count(code, ".") {
+		case 0:
+			table.provinces[code] = name
+		case 1:
+			table.regencies[code] = name
+		case 2:
+			table.districts[code] = name
+		default:
+			continue // villages are not encoded in the NIK
+		}
+		if dot := strings.LastIndex(code, "."); dot > 0 {
+			table.listed[code[:dot]] = true
+		}
+	}
+	return table
+}
+
+// ProvinceName returns the province name for a two digit province code
+func ProvinceName(code string) (string, bool) {
+	name, ok := regions.provinces[code]
+	return name, ok
+}
+
+// RegencyName returns the regency/city name for a province and regency code pair
+func RegencyName(provinceCode, regencyCode string) (string, bool) {
+	name, ok := regions.regencies[provinceCode+"."+regencyCode]
+	return name, ok
+}
+
+// DistrictName returns the district (kecamatan) name for a province, regency and district code
+func DistrictName(provinceCode, regencyCode, districtCode string) (string, bool) {
+	name, ok := regions.districts[provinceCode+"."+regencyCode+"."+districtCode]
+	return name, ok
+}
@@ -0,0 +1,47 @@
+package kyc
+
+// FakeKTPOCR is a deterministic local OCR provider.
+// It returns the KTPData registered for a photo and ErrDocumentUnreadable otherwise.
+type FakeKTPOCR struct {
+	Results map[string]KTPData
+}
+
+// NewFakeKTPOCR creates a FakeKTPOCR without any readable photo
+func NewFakeKTPOCR() *FakeKTPOCR {
+	return &FakeKTPOCR{Results: make(map[string]KTPData)}
+}
+
+func (f *FakeKTPOCR) ExtractKTP(ktpPhoto string) (*KTPData, error) {
+	if ktpPhoto == "" {
+		return nil, ErrEmptyPhoto
+	}
+	data, exists := f.Results[ktpPhoto]
+	if !exists {
+		return nil, ErrDocumentUnreadable
+	}
+	return &data, nil
+}
+
+// FakeFaceMatch is a deterministic local face match provider returning a fixed score
+type FakeFaceMatch struct {
+	Score float64
+}
+
+func (f *FakeFaceMatch) MatchFaces(ktpPhoto, selfiePhoto string) (float64, error) {
+	if ktpPhoto == "" || selfiePhoto == "" {
+		return 0, ErrEmptyPhoto
+	}
+	return f.Score, nil
+}
+
+// FakeLiveness is a deterministic local liveness provider returning a fixed score
+type FakeLiveness struct {
+	Score float64
+}
+
+func (f *FakeLiveness) CheckLiveness(selfiePhoto string) (float64, error) {
+	if selfiePhoto == "" {
+		return 0, ErrEmptyPhoto
+	}
+	return f.Score, nil
+}
@@ -0,0 +1,33 @@
+package kyc
+
+import "errors"
+
+var (
+	ErrDocumentUnreadable = errors.New("dokumen KTP tidak dapat dibaca")
+	ErrEmptyPhoto         = errors.New("foto tidak boleh kosong")
+)
+
+// KTPData is the identity data read from a KTP photo
+type KTPData struct {
+	NIK          string `json:"nik"`
+	FullName     string `json:"full_name"`
+	PlaceOfBirth string `json:"place_of_birth"`
+	DateOfBirth  string `json:"date_of_birth"` // dd-mm-yyyy as printed on the card
+}
+
+// KTPOCRProvider extracts identity data from a KTP photo
+type KTPOCRProvider interface {
+	ExtractKTP(ktpPhoto string) (*KTPData, error)
+}
+
+// FaceMatchProvider compares the face on the KTP with the selfie.
+// The score ranges from 0 (different person) to 1 (same person).
+type FaceMatchProvider interface {
+	MatchFaces(ktpPhoto, selfiePhoto string) (float64, error)
+}
+
+// LivenessProvider checks that the selfie was taken of a live person.
+// The score ranges from 0 (spoof) to 1 (live).
+type LivenessProvider interface {
+	CheckLiveness(selfiePhoto string) (float64, error)
+}
@@ -0,0 +1,288 @@
+package ledger
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Account codes of the chart of accounts
+const (
+	Cash                      = "1100"
+	Receivable                = "1200"
+	MerchantPayable           = "2100"
+	RefundPayable             = "2200"
+	UnearnedInterest          = "2300"
+	PaymentSuspense           = "2400"
+	InterestIncome            = "4100"
+	AdminFeeIncome            = "4200"
+	MDRIncome                 = "4300"
+	LateFeeIncome             = "4400"
+	EarlyTerminationFeeIncome = "4500"
+	RecoveryIncome            = "4600"
+	WriteOffExpense           = "5100"
+)
+
+// Account types, which decide the normal balance side
+const (
+	TypeAsset     = "ASSET"
+	TypeLiability = "LIABILITY"
+	TypeIncome    = "INCOME"
+	TypeExpense   = "EXPENSE"
+)
+
+// Account is one account of the chart of accounts
+type Account struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+	Type string `json:"type"` // ASSET, LIABILITY, INCOME, EXPENSE
+}
+
+// DebitNormal reports whether a debit increases the account
+func (a Account) DebitNormal() bool {
+	return a.Type == TypeAsset || a.Type == TypeExpense
+}
+
+var chartOfAccounts = []Account{
+	{Cash, "Kas dan Bank", TypeAsset},
+	{Receivable, "Piutang Pembiayaan", TypeAsset},
+	{MerchantPayable, "Utang Merchant", TypeLiability},
+	{RefundPayable, "Utang Refund Konsumen", TypeLiability},
+	{UnearnedInterest, "Pendapatan Bunga Ditangguhkan", TypeLiability},
+	{PaymentSuspense, "Titipan Pembayaran Belum Teridentifikasi", TypeLiability},
+	{InterestIncome, "Pendapatan Bunga", TypeIncome},
+	{AdminFeeIncome, "Pendapatan Biaya Admin", TypeIncome},
+	{MDRIncome, "Pendapatan MDR", TypeIncome},
+	{LateFeeIncome, "Pendapatan Denda", TypeIncome},
+	{EarlyTerminationFeeIncome, "Pendapatan Penalti Pelunasan", TypeIncome},
+	{RecoveryIncome, "Pendapatan Pemulihan Piutang Hapus Buku", TypeIncome},
+	{WriteOffExpense, "Beban Penghapusan Piutang", TypeExpense},
+}
+
+// ChartOfAccounts returns every account ordered by code
+func ChartOfAccounts() []Account {
+	accounts := make([]Account, len(chartOfAccounts))
+	copy(accounts, chartOfAccounts)
+	return accounts
+}
+
+// LookupAccount finds an account by code
+func LookupAccount(code string) (Account, bool) {
+	for _, account := range chartOfAccounts {
+		if account.Code == code {
+			return account, true
+		}
+	}
+	return Account{}, false
+}
+
+var ErrUnbalanced = errors.New("jurnal tidak seimbang: total debit harus sama dengan total kredit")
+
+// Line is one debit or credit of a journal entry
+type Line struct {
+	Account string
+	Debit   float64
+	Credit  float64
+	Memo    string
+}
+
+// Entry is a journal entry for one business event. Reference identifies the event,
+// so posting the same event twice is detected.
+type Entry struct {
+	Reference     string
+	EventType     string
+	TransactionID uint
+	PostingDate   time.Time
+	Description   string
+	Lines         []Line
+}
+
+// NewEntry starts an entry without lines
+func NewEntry(reference, eventType string, transactionID uint, postingDate time.Time, description string) *Entry {
+	return &Entry{
+		Reference:     reference,
+		EventType:     eventType,
+		TransactionID: transactionID,
+		PostingDate:   postingDate,
+		Description:   description,
+	}
+}
+
+// Debit adds a debit line; zero amounts are left out
+func (e *Entry) Debit(account string, amount float64, memo string) *Entry {
+	if toSen(amount) != 0 {
+		e.Lines = append(e.Lines, Line{Account: account, Debit: amount, Memo: memo})
+	}
+	return e
+}
+
+// Credit adds a credit line; zero amounts are left out
+func (e *Entry) Credit(account string, amount float64, memo string) *Entry {
+	if toSen(amount) != 0 {
+		e.Lines = append(e.Lines, Line{Account: account, Credit: amount, Memo: memo})
+	}
+	return e
+}
+
+// Signed debits a positive amount and credits a negative one, for figures that may go either way
+func (e *Entry) Signed(account string, amount float64, memo string) *Entry {
+	if amount < 0 {
+		return e.Credit(account, -amount, memo)
+	}
+	return e.Debit(account, amount, memo)
+}
+
+// Empty reports whether the event moved no money
+func (e *Entry) Empty() bool {
+	return len(e.Lines) == 0
+}
+
+// Total returns the debit side of the entry
+func (e *Entry) Total() float64 {
+	var debit int64
+	for _, line := range e.Lines {
+		debit += toSen(line.Debit)
+	}
+	return float64(debit) / 100
+}
+
+// Validate checks the entry can be posted: known accounts, one positive side per line,
+// at least two lines and debits equal to credits to the sen
+func (e *Entry) Validate() error {
+	if e.Reference == "" {
+		return errors.New("referensi jurnal wajib diisi")
+	}
+	if len(e.Lines) < 2 {
+		return fmt.Errorf("jurnal %s minimal memiliki dua baris", e.Reference)
+	}
+
+	var debit, credit int64
+	for _, line := range e.Lines {
+		if _, ok := LookupAccount(line.Account); !ok {
+			return fmt.Errorf("akun %s tidak terdaftar", line.Account)
+		}
+		d, c := toSen(line.Debit), toSen(line.Credit)
+		if d < 0 || c < 0 {
+			return fmt.Errorf("jurnal %s memiliki jumlah negatif pada akun %s", e.Reference, line.Account)
+		}
+		if (d == 0) == (c == 0) {
+			return fmt.Errorf("baris akun %s pada jurnal %s harus berisi debit atau kredit", line.Account, e.Reference)
+		}
+		debit += d
+		credit += c
+	}
+	if debit != credit {
+		return fmt.Errorf("%w (%s: debit %.2f, kredit %.2f)", ErrUnbalanced, e.Reference, float64(debit)/100, float64(credit)/100)
+	}
+	return nil
+}
+
+// PostedLine is a journal line together with the entry it belongs to
+type PostedLine struct {
+	PostingDate   time.Time
+	Reference     string
+	EventType     string
+	TransactionID uint
+	Account       string
+	Debit         float64
+	Credit        float64
+	Memo          string
+}
+
+// AccountBalance is one row of the trial balance
+type AccountBalance struct {
+	Account
+	Debit   float64 `json:"debit"`
+	Credit  float64 `json:"credit"`
+	Balance float64 `json:"balance"` // on the normal side of the account
+}
+
+// TrialBalance lists the balance of every account up to a date
+type TrialBalance struct {
+	AsOf        time.Time        `json:"as_of"`
+	Accounts    []AccountBalance `json:"accounts"`
+	TotalDebit  float64          `json:"total_debit"`
+	TotalCredit float64          `json:"total_credit"`
+	Balanced    bool             `json:"balanced"`
+}
+
+// BuildTrialBalance sums posted lines per account. Every account of the chart is listed, including unused ones.
+func BuildTrialBalance(asOf time.Time, lines []PostedLine) *TrialBalance {
+	debits := make(map[string]int64)
+	credits := make(map[string]int64)
+	for _, line := range lines {
+		debits[line.Account] += toSen(line.Debit)
+		credits[line.Account] += toSen(line.Credit)
+	}
+
+	trial := &TrialBalance{AsOf: asOf}
+	var totalDebit, totalCredit int64
+	for _, account := range chartOfAccounts {
+		d, c := debits[account.Code], credits[account.Code]
+		balance := c - d
+		if account.DebitNormal() {
+			balance = d - c
+		}
+		trial.Accounts = append(trial.Accounts, AccountBalance{
+			Account: account,
+			Debit:   float64(d) / 100,
+			Credit:  float64(c) / 100,
+			Balance: float64(balance) / 100,
+		})
+		totalDebit += d
+		totalCredit += c
+	}
+	trial.TotalDebit = float64(totalDebit) / 100
+	trial.TotalCredit = float64(totalCredit) / 100
+	trial.Balanced = totalDebit == totalCredit
+	return trial
+}
+
+// WriteGL writes posted lines as the general ledger CSV of the core accounting system,
+// ordered by posting date and reference
+func WriteGL(w io.Writer, lines []PostedLine) error {
+	sorted := make([]PostedLine, len(lines))
+	copy(sorted, lines)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].PostingDate.Equal(sorted[j].PostingDate) {
+			return sorted[i].PostingDate.Before(sorted[j].PostingDate)
+		}
+		return sorted[i].Reference < sorted[j].Reference
+	})
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{
+		"posting_date", "reference", "event_type", "transaction_id",
+		"account_code", "account_name", "debit", "credit", "memo",
+	}); err != nil {
+		return err
+	}
+	for _, line := range sorted {
+		account, _ := LookupAccount(line.Account)
+		if err := writer.Write([]string{
+			line.PostingDate.Format("2006-01-02"),
+			line.Reference,
+			line.EventType,
+			strconv.FormatUint(uint64(line.TransactionID), 10),
+			line.Account,
+			account.Name,
+			strconv.FormatFloat(line.Debit, 'f', 2, 64),
+			strconv.FormatFloat(line.Credit, 'f', 2, 64),
+			line.Memo,
+		}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// toSen converts rupiah to whole sen so sums are exact
+func toSen(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
@@ -0,0 +1,108 @@
+package ledger
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testDate = time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+// Test: Balanced entry passes validation; zero amounts are left out
+func TestEntry_ValidateBalanced(t *testing.T) {
+	entry := NewEntry("ACT-KTR-001", "ACTIVATION", 1, testDate, "aktivasi").
+		Debit(Receivable, 1060000.10, "").
+		Credit(MerchantPayable, 1000000, "").
+		Credit(UnearnedInterest, 60000.10, "").
+		Debit(Cash, 0, "").
+		Credit(AdminFeeIncome, 0, "")
+
+	if err := entry.Validate(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(entry.Lines) != 3 {
+		t.Errorf("Expected zero lines to be skipped, got %d lines", len(entry.Lines))
+	}
+	if entry.Total() != 1060000.10 {
+		t.Errorf("Expected total 1060000.10, got %.2f", entry.Total())
+	}
+}
+
+// Test: Debits must equal credits to the sen
+func TestEntry_ValidateUnbalanced(t *testing.T) {
+	entry := NewEntry("ACT-KTR-001", "ACTIVATION", 1, testDate, "").
+		Debit(Receivable, 1000000.01, "").
+		Credit(MerchantPayable, 1000000, "")
+
+	if err := entry.Validate(); !errors.Is(err, ErrUnbalanced) {
+		t.Errorf("Expected ErrUnbalanced, got %v", err)
+	}
+}
+
+// Test: Unknown accounts, negative amounts and single-line entries are rejected
+func TestEntry_ValidateRejectsMalformedLines(t *testing.T) {
+	cases := map[string]*Entry{
+		"unknown account": NewEntry("X-1", "TEST", 0, testDate, "").Debit("9999", 10, "").Credit(Cash, 10, ""),
+		"negative amount": NewEntry("X-2", "TEST", 0, testDate, "").Debit(Cash, -10, "").Credit(Receivable, -10, ""),
+		"single line":     NewEntry("X-3", "TEST", 0, testDate, "").Debit(Cash, 10, ""),
+		"no reference":    NewEntry("", "TEST", 0, testDate, "").Debit(Cash, 10, "").Credit(Receivable, 10, ""),
+	}
+	for name, entry := range cases {
+		if err := entry.Validate(); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
+
+// Test: Trial balance lists every account on its normal side and balances
+func TestBuildTrialBalance(t *testing.T) {
+	lines := []PostedLine{
+		{Account: Receivable, Debit: 1060000},
+		{Account: MerchantPayable, Credit: 1000000},
+		{Account: UnearnedInterest, Credit: 60000},
+		{Account: MerchantPayable, Debit: 970000},
+		{Account: Cash, Credit: 970000},
+	}
+
+	trial := BuildTrialBalance(testDate, lines)
+	if len(trial.Accounts) != len(ChartOfAccounts()) {
+		t.Fatalf("Expected every account listed, got %d", len(trial.Accounts))
+	}
+	if !trial.Balanced || trial.TotalDebit != 2030000 || trial.TotalCredit != 2030000 {
+		t.Errorf("Expected balanced totals of 2030000, got debit %.2f credit %.2f", trial.TotalDebit, trial.TotalCredit)
+	}
+
+	balances := make(map[string]float64)
+	for _, account := range trial.Accounts {
+		balances[account.Code] = account.Balance
+	}
+	if balances[Cash] != -970000 {
+		t.Errorf("Expected cash balance -970000, got %.2f", balances[Cash])
+	}
+	if balances[MerchantPayable] != 30000 {
+		t.Errorf("Expected merchant payable 30000, got %.2f", balances[MerchantPayable])
+	}
+}
+
+// Test: GL file is ordered by posting date and carries account names
+func TestWriteGL(t *testing.T) {
+	lines := []PostedLine{
+		{PostingDate: testDate.AddDate(0, 0, 1), Reference: "B", EventType: "TEST", Account: Cash, Credit: 10},
+		{PostingDate: testDate, Reference: "A", EventType: "TEST", TransactionID: 7, Account: Receivable, Debit: 10, Memo: "x"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteGL(&buf, lines); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	rows := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(rows) != 3 {
+		t.Fatalf("Expected header and 2 rows, got %d", len(rows))
+	}
+	if rows[1] != "2026-03-02,A,TEST,7,1200,Piutang Pembiayaan,10.00,0.00,x" {
+		t.Errorf("Unexpected GL row %q", rows[1])
+	}
+}
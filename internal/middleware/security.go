@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"log"
+	"mime"
 	"net/http"
 	"strings"
 )
@@ -39,11 +40,10 @@ func SecurityHeaders(next http.Handler) http.Handler {
 // Protection against: OWASP A03:2021 – Injection
 func InputValidation(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Validate content type
+		// Validate content type: JSON everywhere, file types only on the upload endpoints
 		if r.Method == "POST" || r.Method == "PUT" {
-			contentType := r.Header.Get("Content-Type")
-			if contentType == "" || !strings.Contains(contentType, "application/json") {
-				http.Error(w, "Content-Type must be application/json", http.StatusBadRequest)
+			if allowed := allowedContentTypes(r.Method, r.URL.Path); !hasContentType(r.Header.Get("Content-Type"), allowed) {
+				http.Error(w, "Content-Type must be "+strings.Join(allowed, " or "), http.StatusBadRequest)
 				return
 			}
 		}
@@ -63,6 +63,38 @@ func InputValidation(next http.Handler) http.Handler {
 	})
 }
 
+// fileUploads lists the endpoints that take a file instead of JSON. text/plain is a CORS simple
+// content type, so it is never accepted globally: a cross-site form post could reach a JSON endpoint
+// without a preflight.
+var fileUploads = map[string][]string{
+	"POST /api/v1/watchlist/import":          {"text/csv", "text/plain"},
+	"POST /api/v1/settlements/confirmations": {"text/csv"},
+	"POST /api/v1/reconciliation/statements": {"text/csv", "text/plain"}, // MT940 has no registered type
+	"POST /api/v1/statements/verify":         {"application/pdf", "application/json"},
+}
+
+// allowedContentTypes returns the body types a request may send
+func allowedContentTypes(method, path string) []string {
+	if allowed, ok := fileUploads[method+" "+strings.TrimSuffix(path, "/")]; ok {
+		return allowed
+	}
+	return []string{"application/json"}
+}
+
+// hasContentType reports whether the Content-Type header names one of the allowed media types
+func hasContentType(contentType string, allowed []string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, candidate := range allowed {
+		if mediaType == candidate {
+			return true
+		}
+	}
+	return false
+}
+
 // RateLimiting middleware to prevent brute force attacks
 // Protection against: OWASP A07:2021 – Identification and Authentication Failures
 func RateLimiting(maxRequests int, windowSize int64) func(http.Handler) http.Handler {
@@ -101,7 +133,7 @@ func CORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "http://localhost:3000") // Specify allowed origin
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key")
 		w.Header().Set("Access-Control-Max-Age", "3600")
 
 		if r.Method == "OPTIONS" {
@@ -1,6 +1,8 @@
 package model
 
-import "time"
+import (
+	"time"
+)
 
 // Consumer represents a customer of PT XYZ Multifinance
 type Consumer struct {
@@ -10,16 +12,79 @@ type Consumer struct {
 	LegalName      string          `gorm:"not null;type:varchar(255)" json:"legal_name"`
 	PlaceOfBirth   string          `gorm:"type:varchar(255)" json:"place_of_birth"`
 	DateOfBirth    time.Time       `json:"date_of_birth"`
+	Gender         string          `gorm:"type:varchar(10)" json:"gender"`                          // MALE, FEMALE (derived from NIK)
+	MaritalStatus  string          `gorm:"type:varchar(20);default:'SINGLE'" json:"marital_status"` // SINGLE, MARRIED, DIVORCED, WIDOWED
 	Salary         float64         `gorm:"type:decimal(15,2)" json:"salary"`
+	PhoneNumber    string          `gorm:"type:varchar(20)" json:"phone_number,omitempty"` // SMS and WhatsApp notifications
+	Email          string          `gorm:"type:varchar(255)" json:"email,omitempty"`
 	KTPPhoto       string          `gorm:"type:text" json:"ktp_photo"`
 	SelfiePhoto    string          `gorm:"type:text" json:"selfie_photo"`
+	KYCStatus      string          `gorm:"type:varchar(20);default:'PENDING';index" json:"kyc_status"` // PENDING, VERIFIED, REJECTED, MANUAL_REVIEW
+	WrittenOff     bool            `gorm:"default:false;index" json:"written_off"`                     // has a written-off contract, no new limits are granted
 	CreatedAt      time.Time       `json:"created_at"`
 	UpdatedAt      time.Time       `json:"updated_at"`
 	DeletedAt      *time.Time      `gorm:"index" json:"deleted_at,omitempty"`
+	NIKInfo        *NIKInfo        `gorm:"-" json:"nik_info,omitempty"`
 	ConsumerLimits []ConsumerLimit `gorm:"foreignKey:ConsumerID" json:"limits,omitempty"`
 	Transactions   []Transaction   `gorm:"foreignKey:ConsumerID" json:"transactions,omitempty"`
 }
 
+// NIKInfo holds the data encoded in a NIK (Nomor Induk Kependudukan), decoded by package ktp
+type NIKInfo struct {
+	ProvinceCode    string    `json:"province_code"`
+	Province        string    `json:"province"`
+	RegencyCode     string    `json:"regency_code"`
+	Regency         string    `json:"regency,omitempty"`
+	RegencyChecked  bool      `json:"regency_checked"` // false when the region table does not list the regencies of the province yet
+	DistrictCode    string    `json:"district_code"`
+	District        string    `json:"district,omitempty"`
+	DistrictChecked bool      `json:"district_checked"` // false when the region table does not list the districts of the regency yet
+	DateOfBirth     time.Time `json:"date_of_birth"`
+	Gender          string    `json:"gender"`
+	SequenceNumber  string    `json:"sequence_number"`
+}
+
+// Marital statuses as printed on the KTP (status perkawinan)
+const (
+	MaritalStatusSingle   = "SINGLE"
+	MaritalStatusMarried  = "MARRIED"
+	MaritalStatusDivorced = "DIVORCED"
+	MaritalStatusWidowed  = "WIDOWED"
+)
+
+// HasBeenMarried reports whether the consumer is or has been married,
+// which lowers the legal age of majority
+func (c *Consumer) HasBeenMarried() bool {
+	switch c.MaritalStatus {
+	case MaritalStatusMarried, MaritalStatusDivorced, MaritalStatusWidowed:
+		return true
+	}
+	return false
+}
+
+// KYC statuses of a consumer
+const (
+	KYCStatusPending      = "PENDING"
+	KYCStatusVerified     = "VERIFIED"
+	KYCStatusRejected     = "REJECTED"
+	KYCStatusManualReview = "MANUAL_REVIEW"
+)
+
+// KYCVerification records one KYC check of a consumer, automatic or by an analyst
+type KYCVerification struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	ConsumerID     uint       `gorm:"index;not null" json:"consumer_id"`
+	Status         string     `gorm:"type:varchar(20);not null" json:"status"`
+	OCRNIK         string     `gorm:"type:varchar(16)" json:"ocr_nik"`
+	OCRName        string     `gorm:"type:varchar(255)" json:"ocr_name"`
+	FaceMatchScore float64    `gorm:"type:decimal(5,4)" json:"face_match_score"`
+	LivenessScore  float64    `gorm:"type:decimal(5,4)" json:"liveness_score"`
+	Reason         string     `gorm:"type:varchar(500)" json:"reason"`
+	ReviewedBy     string     `gorm:"type:varchar(255)" json:"reviewed_by,omitempty"`
+	ReviewedAt     *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
 // ConsumerLimit represents the credit limit for a consumer
 type ConsumerLimit struct {
 	ID          uint      `gorm:"primaryKey" json:"id"`
@@ -28,23 +93,898 @@ type ConsumerLimit struct {
 	Tenor       int       `gorm:"not null" json:"tenor"` // 1, 2, 3, 6 bulan
 	LimitAmount float64   `gorm:"type:decimal(15,2);not null" json:"limit_amount"`
 	UsedAmount  float64   `gorm:"type:decimal(15,2);default:0" json:"used_amount"`
+	HeldAmount  float64   `gorm:"type:decimal(15,2);default:0" json:"held_amount"` // reserved by open limit holds
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+// Available returns the limit left after booked contracts and open holds
+func (l *ConsumerLimit) Available() float64 {
+	return l.LimitAmount - l.UsedAmount - l.HeldAmount
+}
+
+// Limit hold statuses
+const (
+	LimitHoldStatusAuthorized = "AUTHORIZED"
+	LimitHoldStatusCaptured   = "CAPTURED"
+	LimitHoldStatusVoided     = "VOIDED"
+	LimitHoldStatusExpired    = "EXPIRED"
+)
+
+// LimitHold reserves part of a consumer limit for a merchant checkout until the goods ship
+type LimitHold struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	ConsumerID    uint       `gorm:"index;not null" json:"consumer_id"`
+	Tenor         int        `gorm:"not null" json:"tenor"`
+	Amount        float64    `gorm:"type:decimal(15,2);not null" json:"amount"`
+	MerchantID    uint       `gorm:"index" json:"merchant_id"`
+	Reference     string     `gorm:"type:varchar(255)" json:"reference"` // merchant order / cart reference
+	Status        string     `gorm:"type:varchar(20);default:'AUTHORIZED';index" json:"status"`
+	ExpiresAt     time.Time  `gorm:"index" json:"expires_at"`
+	TransactionID uint       `json:"transaction_id,omitempty"` // set on capture
+	ClosedAt      *time.Time `json:"closed_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
 // Transaction represents a financial transaction
 type Transaction struct {
-	ID                uint      `gorm:"primaryKey" json:"id"`
-	ConsumerID        uint      `gorm:"index;not null" json:"consumer_id"`
-	Consumer          Consumer  `json:"consumer,omitempty"`
-	ContractNumber    string    `gorm:"unique;not null;type:varchar(255)" json:"contract_number"`
-	Tenor             int       `gorm:"not null" json:"tenor"` // 1, 2, 3, 6 bulan
-	OTR               float64   `gorm:"type:decimal(15,2);not null" json:"otr"`
-	AdminFee          float64   `gorm:"type:decimal(15,2)" json:"admin_fee"`
-	InstallmentAmount float64   `gorm:"type:decimal(15,2);not null" json:"installment_amount"`
-	InterestAmount    float64   `gorm:"type:decimal(15,2)" json:"interest_amount"`
-	AssetName         string    `gorm:"type:varchar(255)" json:"asset_name"`
-	Status            string    `gorm:"type:varchar(50);default:'ACTIVE'" json:"status"` // ACTIVE, COMPLETED, DEFAULTED
-	CreatedAt         time.Time `json:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at"`
+	ID                uint       `gorm:"primaryKey" json:"id"`
+	ConsumerID        uint       `gorm:"index;not null" json:"consumer_id"`
+	Consumer          Consumer   `json:"consumer,omitempty"`
+	ContractNumber    string     `gorm:"unique;not null;type:varchar(255)" json:"contract_number"`
+	Tenor             int        `gorm:"not null" json:"tenor"` // 1, 2, 3, 6 bulan
+	OTR               float64    `gorm:"type:decimal(15,2);not null" json:"otr"`
+	DownPayment       float64    `gorm:"type:decimal(15,2);default:0" json:"down_payment"` // paid by the consumer, not financed
+	AdminFee          float64    `gorm:"type:decimal(15,2)" json:"admin_fee"`
+	InstallmentAmount float64    `gorm:"type:decimal(15,2);not null" json:"installment_amount"`
+	InterestAmount    float64    `gorm:"type:decimal(15,2)" json:"interest_amount"`
+	AssetName         string     `gorm:"type:varchar(255)" json:"asset_name"`
+	ProductCode       string     `gorm:"type:varchar(50);index" json:"product_code"`
+	PromoCode         string     `gorm:"type:varchar(50)" json:"promo_code,omitempty"`    // promo applied when pricing
+	InterestRate      float64    `gorm:"type:decimal(7,4)" json:"interest_rate"`          // flat monthly rate used for pricing
+	MerchantID        uint       `gorm:"index" json:"merchant_id,omitempty"`              // originating merchant, 0 when booked directly
+	BranchID          uint       `gorm:"index" json:"branch_id,omitempty"`                // merchant branch / outlet, 0 when not known
+	Status            string     `gorm:"type:varchar(50);default:'ACTIVE'" json:"status"` // ACTIVE, COMPLETED, DEFAULTED, PENDING_REVIEW, PENDING_SIGNATURE, PENDING_DOWN_PAYMENT, REJECTED, CANCELLED, WRITTEN_OFF
+	RiskScore         int        `gorm:"default:0" json:"risk_score"`
+	RiskDecision      string     `gorm:"type:varchar(10)" json:"risk_decision,omitempty"` // ALLOW, REVIEW, DENY
+	DaysPastDue       int        `gorm:"default:0" json:"days_past_due"`
+	Collectibility    int        `gorm:"default:1" json:"collectibility"` // OJK grade 1 (Lancar) - 5 (Macet)
+	LateFeeAmount     float64    `gorm:"type:decimal(15,2);default:0" json:"late_fee_amount"`
+	RestructureCount  int        `gorm:"default:0" json:"restructure_count,omitempty"`
+	RestructuredAt    *time.Time `json:"restructured_at,omitempty"`               // effective date of the latest restructuring
+	ActivatedAt       *time.Time `gorm:"index" json:"activated_at,omitempty"`     // when the contract started running, the merchant is owed from then
+	OTPChallengeID    uint       `gorm:"index" json:"otp_challenge_id,omitempty"` // consumer consent the contract was booked with
+	OTPCode           string     `gorm:"-" json:"otp_code,omitempty"`             // code of the challenge, only on the request
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// FinancedAmount is the principal booked against the limit and amortized: OTR minus the down payment
+func (t *Transaction) FinancedAmount() float64 {
+	return t.OTR - t.DownPayment
+}
+
+// Admin fee types of a product
+const (
+	AdminFeeTypeFlat    = "FLAT"    // fixed amount per contract
+	AdminFeeTypePercent = "PERCENT" // percentage of the OTR
+)
+
+// Product is a financing product with its own tenors and pricing
+type Product struct {
+	ID                uint           `gorm:"primaryKey" json:"id"`
+	Code              string         `gorm:"uniqueIndex;not null;type:varchar(50)" json:"code"`
+	Name              string         `gorm:"type:varchar(255);not null" json:"name"`
+	Category          string         `gorm:"type:varchar(50)" json:"category"` // e.g. ELECTRONICS, MOTORCYCLE, WHITE_GOODS
+	AdminFeeType      string         `gorm:"type:varchar(10);not null" json:"admin_fee_type"`
+	AdminFeeValue     float64        `gorm:"type:decimal(15,4)" json:"admin_fee_value"` // amount for FLAT, percent for PERCENT
+	MinOTR            float64        `gorm:"type:decimal(15,2)" json:"min_otr"`
+	MaxOTR            float64        `gorm:"type:decimal(15,2)" json:"max_otr"`                       // 0 means no maximum
+	MinDownPaymentPct float64        `gorm:"type:decimal(5,2);default:0" json:"min_down_payment_pct"` // minimum DP as percent of the OTR
+	ValidFrom         time.Time      `json:"valid_from"`
+	ValidUntil        *time.Time     `json:"valid_until,omitempty"`
+	Active            bool           `gorm:"default:true" json:"active"`
+	Rates             []ProductRate  `gorm:"foreignKey:ProductID" json:"rates"`
+	Promos            []ProductPromo `gorm:"foreignKey:ProductID" json:"promos,omitempty"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+}
+
+// ProductRate is the flat monthly interest rate of a product for one tenor
+type ProductRate struct {
+	ID           uint    `gorm:"primaryKey" json:"id"`
+	ProductID    uint    `gorm:"uniqueIndex:idx_product_tenor;not null" json:"product_id"`
+	Tenor        int     `gorm:"uniqueIndex:idx_product_tenor;not null" json:"tenor"`
+	InterestRate float64 `gorm:"type:decimal(7,4);not null" json:"interest_rate"` // percent per month, flat
+}
+
+// ProductPromo overrides the pricing of a product for a period, e.g. 0% interest for 3 months
+type ProductPromo struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	ProductID     uint      `gorm:"index;not null" json:"product_id"`
+	Code          string    `gorm:"type:varchar(50);not null" json:"code"`
+	Tenor         int       `json:"tenor"` // 0 applies to every tenor of the product
+	InterestRate  float64   `gorm:"type:decimal(7,4)" json:"interest_rate"`
+	WaiveAdminFee bool      `json:"waive_admin_fee"`
+	ValidFrom     time.Time `json:"valid_from"`
+	ValidUntil    time.Time `json:"valid_until"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Merchant statuses
+const (
+	MerchantStatusActive     = "ACTIVE"
+	MerchantStatusSuspended  = "SUSPENDED"  // temporarily cannot originate contracts
+	MerchantStatusTerminated = "TERMINATED" // partnership ended
+)
+
+// Merchant is a dealer or online store that originates financed contracts
+type Merchant struct {
+	ID                uint             `gorm:"primaryKey" json:"id"`
+	Code              string           `gorm:"uniqueIndex;not null;type:varchar(50)" json:"code"`
+	Name              string           `gorm:"type:varchar(255);not null" json:"name"`
+	Category          string           `gorm:"type:varchar(50)" json:"category"` // e.g. MOTOR_DEALER, ELECTRONICS_STORE, ONLINE_STORE
+	BankName          string           `gorm:"type:varchar(100)" json:"bank_name"`
+	BankAccountNumber string           `gorm:"type:varchar(50)" json:"bank_account_number"`
+	BankAccountName   string           `gorm:"type:varchar(255)" json:"bank_account_name"`
+	MDRRate           float64          `gorm:"type:decimal(5,2);default:0" json:"mdr_rate"`           // merchant discount rate, percent of the financed amount
+	Status            string           `gorm:"type:varchar(20);default:'ACTIVE';index" json:"status"` // ACTIVE, SUSPENDED, TERMINATED
+	Branches          []MerchantBranch `gorm:"foreignKey:MerchantID" json:"branches,omitempty"`
+	CreatedAt         time.Time        `json:"created_at"`
+	UpdatedAt         time.Time        `json:"updated_at"`
+}
+
+// MerchantBranch is an outlet of a merchant
+type MerchantBranch struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	MerchantID uint      `gorm:"index;not null" json:"merchant_id"`
+	Code       string    `gorm:"type:varchar(50);not null" json:"code"`
+	Name       string    `gorm:"type:varchar(255);not null" json:"name"`
+	City       string    `gorm:"type:varchar(100)" json:"city"`
+	Address    string    `gorm:"type:text" json:"address"`
+	Active     bool      `gorm:"default:true" json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// MerchantAPIKey lets a merchant call the API for its own contracts. Only the SHA-256 hash of the key is stored.
+type MerchantAPIKey struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	MerchantID uint       `gorm:"index;not null" json:"merchant_id"`
+	Prefix     string     `gorm:"type:varchar(16)" json:"prefix"` // first characters of the key, to tell keys apart
+	KeyHash    string     `gorm:"uniqueIndex;not null;type:varchar(64)" json:"-"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Settlement batch statuses
+const (
+	SettlementStatusPending        = "PENDING"         // waiting to be exported to the bank
+	SettlementStatusExported       = "EXPORTED"        // sent to the bank in a bulk-transfer file
+	SettlementStatusPaid           = "PAID"            // confirmed by the bank
+	SettlementStatusFailed         = "FAILED"          // rejected by the bank, exported again with the next file
+	SettlementStatusCarriedForward = "CARRIED_FORWARD" // net amount not positive, taken off the next batch of the merchant
+)
+
+// SettlementBatch is the daily disbursement to one merchant: the financed amount of its contracts less the
+// cancellations billed back to it and MDR
+type SettlementBatch struct {
+	ID                uint             `gorm:"primaryKey" json:"id"`
+	BatchNumber       string           `gorm:"uniqueIndex;not null;type:varchar(100)" json:"batch_number"`
+	MerchantID        uint             `gorm:"index;not null" json:"merchant_id"`
+	BusinessDate      time.Time        `gorm:"type:date;index" json:"business_date"`
+	TransactionCount  int              `json:"transaction_count"`
+	GrossAmount       float64          `gorm:"type:decimal(15,2)" json:"gross_amount"`     // OTR minus down payment, as activated
+	CancelledAmount   float64          `gorm:"type:decimal(15,2)" json:"cancelled_amount"` // billed back to the merchant for cancellations
+	MDRAmount         float64          `gorm:"type:decimal(15,2)" json:"mdr_amount"`
+	CarriedAmount     float64          `gorm:"type:decimal(15,2)" json:"carried_amount"` // balance brought from earlier CARRIED_FORWARD batches, zero or negative
+	NetAmount         float64          `gorm:"type:decimal(15,2)" json:"net_amount"`     // transferred to the merchant
+	BankName          string           `gorm:"type:varchar(100)" json:"bank_name"`       // beneficiary snapshot at batching
+	BankAccountNumber string           `gorm:"type:varchar(50)" json:"bank_account_number"`
+	BankAccountName   string           `gorm:"type:varchar(255)" json:"bank_account_name"`
+	Status            string           `gorm:"type:varchar(20);default:'PENDING';index" json:"status"` // PENDING, EXPORTED, PAID, FAILED, CARRIED_FORWARD
+	CarriedToBatchID  uint             `gorm:"index" json:"carried_to_batch_id,omitempty"`             // batch that took over the balance of a CARRIED_FORWARD batch
+	BankReference     string           `gorm:"type:varchar(100)" json:"bank_reference,omitempty"`
+	FailureReason     string           `gorm:"type:varchar(255)" json:"failure_reason,omitempty"`
+	ExportedAt        *time.Time       `json:"exported_at,omitempty"`
+	PaidAt            *time.Time       `json:"paid_at,omitempty"`
+	Items             []SettlementItem `gorm:"foreignKey:BatchID" json:"items,omitempty"`
+	CreatedAt         time.Time        `json:"created_at"`
+	UpdatedAt         time.Time        `json:"updated_at"`
+}
+
+// SettlementItem is one line of a settlement batch: the financed amount of a contract, settled once, or
+// one cancellation of it billed back to the merchant
+type SettlementItem struct {
+	ID              uint    `gorm:"primaryKey" json:"id"`
+	BatchID         uint    `gorm:"index;not null" json:"batch_id"`
+	TransactionID   uint    `gorm:"uniqueIndex:idx_settlement_item_source;not null" json:"transaction_id"`
+	CancellationID  uint    `gorm:"uniqueIndex:idx_settlement_item_source;not null;default:0" json:"cancellation_id,omitempty"` // 0 for the financed amount
+	ContractNumber  string  `gorm:"type:varchar(255)" json:"contract_number"`
+	GrossAmount     float64 `gorm:"type:decimal(15,2)" json:"gross_amount"`
+	CancelledAmount float64 `gorm:"type:decimal(15,2)" json:"cancelled_amount"`
+	MDRAmount       float64 `gorm:"type:decimal(15,2)" json:"mdr_amount"`
+	NetAmount       float64 `gorm:"type:decimal(15,2)" json:"net_amount"`
+}
+
+// InterestAccrual is the interest income recognised for a contract on one business date.
+// Re-running the date adjusts the row in place and bumps the revision.
+type InterestAccrual struct {
+	ID               uint       `gorm:"primaryKey" json:"id"`
+	TransactionID    uint       `gorm:"uniqueIndex:idx_accrual_contract_date;not null" json:"transaction_id"`
+	AccrualDate      time.Time  `gorm:"type:date;uniqueIndex:idx_accrual_contract_date;not null;index" json:"accrual_date"`
+	Amount           float64    `gorm:"type:decimal(15,2);not null" json:"amount"`            // negative when a correction lowers the income
+	CumulativeAmount float64    `gorm:"type:decimal(15,2);not null" json:"cumulative_amount"` // recognised up to and including the date
+	Revision         int        `gorm:"default:1" json:"revision"`
+	ReversedAt       *time.Time `json:"reversed_at,omitempty"` // set when the contract is written off
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// Restructuring statuses
+const (
+	RestructuringStatusPending  = "PENDING_APPROVAL"
+	RestructuringStatusApproved = "APPROVED"
+	RestructuringStatusRejected = "REJECTED"
+)
+
+// Restructuring reschedules the outstanding balance of a distressed contract on new terms.
+// The unpaid installments of the old schedule are kept as RESCHEDULED and the new schedule
+// is linked through Installment.RestructuringID. Amounts are recomputed on approval.
+type Restructuring struct {
+	ID                   uint       `gorm:"primaryKey" json:"id"`
+	TransactionID        uint       `gorm:"index;not null" json:"transaction_id"`
+	Reason               string     `gorm:"type:varchar(500)" json:"reason"`
+	OldTenor             int        `json:"old_tenor"`
+	NewTenor             int        `gorm:"not null" json:"new_tenor"`
+	OldInterestRate      float64    `gorm:"type:decimal(7,4)" json:"old_interest_rate"`
+	NewInterestRate      float64    `gorm:"type:decimal(7,4)" json:"new_interest_rate"` // percent per month, flat
+	HolidayMonths        int        `gorm:"default:0" json:"holiday_months"`            // months without installments before the new schedule starts
+	OutstandingPrincipal float64    `gorm:"type:decimal(15,2)" json:"outstanding_principal"`
+	CapitalisedInterest  float64    `gorm:"type:decimal(15,2)" json:"capitalised_interest"` // overdue interest added to the new principal
+	WaivedLateFees       float64    `gorm:"type:decimal(15,2)" json:"waived_late_fees"`
+	NewPrincipal         float64    `gorm:"type:decimal(15,2)" json:"new_principal"`
+	NewInterestAmount    float64    `gorm:"type:decimal(15,2)" json:"new_interest_amount"`
+	NewInstallmentAmount float64    `gorm:"type:decimal(15,2)" json:"new_installment_amount"`
+	Status               string     `gorm:"type:varchar(20);default:'PENDING_APPROVAL';index" json:"status"` // PENDING_APPROVAL, APPROVED, REJECTED
+	RequestedBy          string     `gorm:"type:varchar(255);not null" json:"requested_by"`
+	ReviewedBy           string     `gorm:"type:varchar(255)" json:"reviewed_by,omitempty"`
+	ReviewNote           string     `gorm:"type:varchar(500)" json:"review_note,omitempty"`
+	ReviewedAt           *time.Time `json:"reviewed_at,omitempty"`
+	EffectiveDate        *time.Time `gorm:"type:date" json:"effective_date,omitempty"` // set on approval
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+}
+
+// Write-off statuses
+const (
+	WriteOffStatusPending  = "PENDING_APPROVAL"
+	WriteOffStatusApproved = "APPROVED"
+	WriteOffStatusRejected = "REJECTED"
+)
+
+// WriteOff takes a DEFAULTED contract off the books. Amounts are taken from the ledger on approval.
+type WriteOff struct {
+	ID               uint       `gorm:"primaryKey" json:"id"`
+	TransactionID    uint       `gorm:"index;not null" json:"transaction_id"`
+	ConsumerID       uint       `gorm:"index;not null" json:"consumer_id"`
+	Vintage          string     `gorm:"type:varchar(7);index" json:"vintage"` // booking month of the contract, YYYY-MM
+	DaysPastDue      int        `json:"days_past_due"`
+	Reason           string     `gorm:"type:varchar(500)" json:"reason"`
+	ReceivableAmount float64    `gorm:"type:decimal(15,2)" json:"receivable_amount"` // receivable taken off the books
+	DeferredInterest float64    `gorm:"type:decimal(15,2)" json:"deferred_interest"` // unearned interest released with it
+	LossAmount       float64    `gorm:"type:decimal(15,2)" json:"loss_amount"`       // charged to the write-off expense
+	ReversedAccruals float64    `gorm:"type:decimal(15,2)" json:"reversed_accruals"`
+	RecoveredAmount  float64    `gorm:"type:decimal(15,2);default:0" json:"recovered_amount"`
+	Status           string     `gorm:"type:varchar(20);default:'PENDING_APPROVAL';index" json:"status"` // PENDING_APPROVAL, APPROVED, REJECTED
+	RequestedBy      string     `gorm:"type:varchar(255);not null" json:"requested_by"`
+	ReviewedBy       string     `gorm:"type:varchar(255)" json:"reviewed_by,omitempty"`
+	ReviewNote       string     `gorm:"type:varchar(500)" json:"review_note,omitempty"`
+	ReviewedAt       *time.Time `json:"reviewed_at,omitempty"`
+	WrittenOffAt     *time.Time `json:"written_off_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// Recovery sources
+const (
+	RecoverySourceCollection   = "COLLECTION"   // paid by the consumer after collection efforts
+	RecoverySourceRepossession = "REPOSSESSION" // proceeds of the repossessed asset
+)
+
+// Recovery is money received on a contract after it was written off
+type Recovery struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	WriteOffID    uint      `gorm:"index;not null" json:"write_off_id"`
+	TransactionID uint      `gorm:"index;not null" json:"transaction_id"`
+	Source        string    `gorm:"type:varchar(20);not null" json:"source"` // COLLECTION, REPOSSESSION
+	Amount        float64   `gorm:"type:decimal(15,2);not null" json:"amount"`
+	Reference     string    `gorm:"type:varchar(255)" json:"reference"`
+	ReceivedAt    time.Time `json:"received_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Collection case statuses
+const (
+	CollectionCaseOpen     = "OPEN"
+	CollectionCaseResolved = "RESOLVED" // no longer overdue, or closed by payoff or write-off
+)
+
+// CollectionCase is an overdue contract in the collections work queue. The figures are refreshed
+// from the contract and its installments every time the queue is rebuilt.
+type CollectionCase struct {
+	ID                uint       `gorm:"primaryKey" json:"id"`
+	TransactionID     uint       `gorm:"uniqueIndex;not null" json:"transaction_id"`
+	ConsumerID        uint       `gorm:"index;not null" json:"consumer_id"`
+	ContractNumber    string     `gorm:"type:varchar(255)" json:"contract_number"`
+	DaysPastDue       int        `json:"days_past_due"`
+	OverdueAmount     float64    `gorm:"type:decimal(15,2)" json:"overdue_amount"`     // installments due and unpaid, late fees included
+	OutstandingAmount float64    `gorm:"type:decimal(15,2)" json:"outstanding_amount"` // everything still owed on the schedule
+	RiskScore         int        `json:"risk_score"`
+	Priority          float64    `gorm:"type:decimal(7,2);index" json:"priority"` // 0-100, worked highest first
+	AssignedTo        string     `gorm:"type:varchar(255);index" json:"assigned_to,omitempty"`
+	AssignedAt        *time.Time `json:"assigned_at,omitempty"`
+	LastContactAt     *time.Time `json:"last_contact_at,omitempty"`
+	LastOutcome       string     `gorm:"type:varchar(30)" json:"last_outcome,omitempty"`
+	BrokenPromises    int        `gorm:"default:0" json:"broken_promises"`
+	Status            string     `gorm:"type:varchar(20);default:'OPEN';index" json:"status"` // OPEN, RESOLVED
+	ResolvedAt        *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// Contact channels and outcomes of a collection attempt
+const (
+	ContactChannelPhone      = "PHONE"
+	ContactChannelWhatsApp   = "WHATSAPP"
+	ContactChannelSMS        = "SMS"
+	ContactChannelEmail      = "EMAIL"
+	ContactChannelFieldVisit = "FIELD_VISIT"
+
+	ContactOutcomeNoAnswer     = "NO_ANSWER"
+	ContactOutcomeWrongNumber  = "WRONG_NUMBER"
+	ContactOutcomeContacted    = "CONTACTED"
+	ContactOutcomePromiseToPay = "PROMISE_TO_PAY"
+	ContactOutcomeRefusedToPay = "REFUSED_TO_PAY"
+	ContactOutcomeAlreadyPaid  = "ALREADY_PAID"
+)
+
+// ContactAttempt is one attempt of a collector to reach the consumer of a case
+type ContactAttempt struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	CaseID      uint      `gorm:"index;not null" json:"case_id"`
+	Collector   string    `gorm:"type:varchar(255);not null" json:"collector"`
+	Channel     string    `gorm:"type:varchar(20);not null" json:"channel"`
+	Outcome     string    `gorm:"type:varchar(30);not null" json:"outcome"`
+	Note        string    `gorm:"type:varchar(500)" json:"note,omitempty"`
+	AttemptedAt time.Time `json:"attempted_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Promise-to-pay statuses
+const (
+	PromiseStatusOpen   = "OPEN"
+	PromiseStatusKept   = "KEPT"
+	PromiseStatusBroken = "BROKEN"
+)
+
+// PromiseToPay is a consumer's commitment to pay an amount by a date. It is kept when the installments
+// of the contract received at least the promised amount since the promise was made.
+type PromiseToPay struct {
+	ID               uint       `gorm:"primaryKey" json:"id"`
+	CaseID           uint       `gorm:"index;not null" json:"case_id"`
+	TransactionID    uint       `gorm:"index;not null" json:"transaction_id"`
+	ContactAttemptID uint       `json:"contact_attempt_id"`
+	PromisedAmount   float64    `gorm:"type:decimal(15,2);not null" json:"promised_amount"`
+	PromisedDate     time.Time  `gorm:"type:date;not null;index" json:"promised_date"`
+	PaidBaseline     float64    `gorm:"type:decimal(15,2)" json:"paid_baseline"` // installment payments of the contract when the promise was made
+	PaidAmount       float64    `gorm:"type:decimal(15,2);default:0" json:"paid_amount"`
+	Status           string     `gorm:"type:varchar(20);default:'OPEN';index" json:"status"` // OPEN, KEPT, BROKEN
+	CreatedBy        string     `gorm:"type:varchar(255)" json:"created_by"`
+	ClosedAt         *time.Time `json:"closed_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// TableName keeps the plural on promises rather than GORM's promise_to_pays
+func (PromiseToPay) TableName() string {
+	return "promises_to_pay"
+}
+
+// NotificationPreference is how a consumer wants to be notified. Consumers without one get every
+// channel they have a contact for, in Indonesian.
+type NotificationPreference struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	ConsumerID      uint       `gorm:"uniqueIndex;not null" json:"consumer_id"`
+	Language        string     `gorm:"type:varchar(2);not null" json:"language"` // id, en
+	SMSEnabled      bool       `json:"sms_enabled"`
+	WhatsAppEnabled bool       `gorm:"column:whatsapp_enabled" json:"whatsapp_enabled"`
+	EmailEnabled    bool       `json:"email_enabled"`
+	OptedOut        bool       `gorm:"index" json:"opted_out"` // no notifications on any channel
+	OptedOutAt      *time.Time `json:"opted_out_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// Notification delivery statuses
+const (
+	NotificationStatusSent   = "SENT"
+	NotificationStatusFailed = "FAILED"
+)
+
+// NotificationLog is one delivery attempt of a notification on one channel
+type NotificationLog struct {
+	ID                uint       `gorm:"primaryKey" json:"id"`
+	ConsumerID        uint       `gorm:"index;not null" json:"consumer_id"`
+	TransactionID     uint       `gorm:"index" json:"transaction_id,omitempty"`
+	Event             string     `gorm:"type:varchar(30);not null" json:"event"`
+	Channel           string     `gorm:"type:varchar(20);not null" json:"channel"` // SMS, WHATSAPP, EMAIL
+	Recipient         string     `gorm:"type:varchar(255)" json:"recipient"`
+	Language          string     `gorm:"type:varchar(2)" json:"language"`
+	Subject           string     `gorm:"type:varchar(255)" json:"subject,omitempty"`
+	Body              string     `gorm:"type:text" json:"body"`
+	DedupKey          string     `gorm:"type:varchar(100);index" json:"dedup_key"` // one successful delivery per key
+	Status            string     `gorm:"type:varchar(20);index" json:"status"`     // SENT, FAILED
+	ProviderMessageID string     `gorm:"type:varchar(255)" json:"provider_message_id,omitempty"`
+	Error             string     `gorm:"type:varchar(500)" json:"error,omitempty"`
+	SentAt            *time.Time `json:"sent_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// Virtual account statuses
+const (
+	VirtualAccountActive = "ACTIVE"
+	VirtualAccountClosed = "CLOSED" // contract no longer takes payments, transfers go to suspense
+)
+
+// VirtualAccount is the bank account number a consumer transfers installments of one contract to
+type VirtualAccount struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	TransactionID uint      `gorm:"index;not null" json:"transaction_id"`
+	ConsumerID    uint      `gorm:"index;not null" json:"consumer_id"`
+	BankCode      string    `gorm:"type:varchar(20);not null" json:"bank_code"`
+	VANumber      string    `gorm:"type:varchar(30);uniqueIndex;not null" json:"va_number"`
+	Status        string    `gorm:"type:varchar(20);index" json:"status"` // ACTIVE, CLOSED
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Payment callback statuses
+const (
+	PaymentCallbackPosted   = "POSTED"   // applied to the contract of the virtual account
+	PaymentCallbackSuspense = "SUSPENSE" // money received but not applied, waiting for an operator
+	PaymentCallbackResolved = "RESOLVED" // applied from suspense to a contract
+	PaymentCallbackRefunded = "REFUNDED" // returned to the sender from suspense
+)
+
+// PaymentCallback is a payment notification received from a bank. A bank's payment ID is stored once,
+// so a replayed callback never posts twice.
+type PaymentCallback struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	BankCode       string     `gorm:"type:varchar(20);uniqueIndex:idx_callback_bank_external;not null" json:"bank_code"`
+	ExternalID     string     `gorm:"type:varchar(100);uniqueIndex:idx_callback_bank_external;not null" json:"external_id"`
+	VANumber       string     `gorm:"type:varchar(30);index" json:"va_number"`
+	Amount         float64    `gorm:"type:decimal(15,2);not null" json:"amount"`
+	PaidAt         time.Time  `json:"paid_at"`
+	Status         string     `gorm:"type:varchar(20);index" json:"status"` // POSTED, SUSPENSE, RESOLVED, REFUNDED
+	TransactionID  uint       `gorm:"index" json:"transaction_id,omitempty"`
+	PaymentID      uint       `json:"payment_id,omitempty"`
+	SuspenseReason string     `gorm:"type:varchar(255)" json:"suspense_reason,omitempty"`
+	Payload        string     `gorm:"type:text" json:"payload"`
+	ResolvedBy     string     `gorm:"type:varchar(100)" json:"resolved_by,omitempty"`
+	ResolutionNote string     `gorm:"type:varchar(500)" json:"resolution_note,omitempty"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// BankStatement is an imported bank statement file. A file is imported once, identified by its hash.
+type BankStatement struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	Format         string    `gorm:"type:varchar(10);not null" json:"format"` // CSV, MT940
+	AccountNumber  string    `gorm:"type:varchar(50)" json:"account_number"`
+	FileName       string    `gorm:"type:varchar(255)" json:"file_name"`
+	FileHash       string    `gorm:"type:varchar(64);uniqueIndex;not null" json:"file_hash"` // SHA-256 of the file
+	CreditLines    int       `json:"credit_lines"`
+	TotalCredit    float64   `gorm:"type:decimal(15,2);default:0" json:"total_credit"`
+	MatchedCount   int       `json:"matched_count"`
+	MatchedAmount  float64   `gorm:"type:decimal(15,2);default:0" json:"matched_amount"`
+	PartialCount   int       `json:"partial_count"`
+	UnmatchedCount int       `json:"unmatched_count"`
+	ImportedBy     string    `gorm:"type:varchar(100)" json:"imported_by"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// Bank statement line match statuses
+const (
+	StatementLineMatched   = "MATCHED"   // reconciled to a payment or a payment callback
+	StatementLinePartial   = "PARTIAL"   // VA found but the amount, date or contract does not fit the expected installment
+	StatementLineUnmatched = "UNMATCHED" // no VA number found
+	StatementLineIgnored   = "IGNORED"   // not a repayment, excluded by an operator
+)
+
+// BankStatementLine is one credit of an imported bank statement and what it was reconciled to
+type BankStatementLine struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	StatementID   uint       `gorm:"index;not null" json:"statement_id"`
+	LineNumber    int        `json:"line_number"`
+	ValueDate     time.Time  `gorm:"type:date;index" json:"value_date"`
+	Amount        float64    `gorm:"type:decimal(15,2);not null" json:"amount"`
+	Reference     string     `gorm:"type:varchar(255)" json:"reference"`
+	Description   string     `gorm:"type:varchar(500)" json:"description"`
+	VANumber      string     `gorm:"type:varchar(30);index" json:"va_number,omitempty"`
+	Status        string     `gorm:"type:varchar(20);index" json:"status"` // MATCHED, PARTIAL, UNMATCHED, IGNORED
+	TransactionID uint       `gorm:"index" json:"transaction_id,omitempty"`
+	InstallmentID uint       `json:"installment_id,omitempty"` // the expected installment at import
+	PaymentID     uint       `json:"payment_id,omitempty"`
+	CallbackID    uint       `gorm:"index" json:"callback_id,omitempty"`
+	MatchNote     string     `gorm:"type:varchar(500)" json:"match_note,omitempty"`
+	MatchedBy     string     `gorm:"type:varchar(100)" json:"matched_by,omitempty"` // AUTO or the operator
+	MatchedAt     *time.Time `json:"matched_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// Statement document formats
+const (
+	StatementFormatJSON = "JSON"
+	StatementFormatPDF  = "PDF"
+)
+
+// StatementArchive is a consumer account statement exactly as it was handed out. The hash proves
+// later that a statement shown by the consumer is the one generated.
+type StatementArchive struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	ConsumerID    uint      `gorm:"index;not null" json:"consumer_id"`
+	TransactionID uint      `gorm:"index" json:"transaction_id,omitempty"` // 0 for every contract of the consumer
+	PeriodFrom    time.Time `gorm:"type:date;not null" json:"period_from"`
+	PeriodTo      time.Time `gorm:"type:date;not null" json:"period_to"`
+	Format        string    `gorm:"type:varchar(10);not null" json:"format"` // JSON, PDF
+	FileName      string    `gorm:"type:varchar(255)" json:"file_name"`
+	DocumentHash  string    `gorm:"type:varchar(64);index;not null" json:"document_hash"` // SHA-256 of the content
+	Content       []byte    `gorm:"type:longblob" json:"-"`
+	SizeBytes     int       `json:"size_bytes"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ContractTemplate is one version of the wording of a financing contract. A new version replaces
+// the active one; documents keep pointing at the version they were generated from.
+type ContractTemplate struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Code      string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_contract_template_version" json:"code"` // FINANCING or a product code
+	Version   int       `gorm:"not null;uniqueIndex:idx_contract_template_version" json:"version"`
+	Title     string    `gorm:"type:varchar(255);not null" json:"title"`
+	Body      string    `gorm:"type:text;not null" json:"body"` // clauses as a text/template
+	Active    bool      `gorm:"default:false;index" json:"active"`
+	CreatedBy string    `gorm:"type:varchar(100)" json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Contract document statuses
+const (
+	ContractDocumentPending    = "PENDING"    // waiting for the consumer signature
+	ContractDocumentSigned     = "SIGNED"     // signed, the binding version of the contract
+	ContractDocumentSuperseded = "SUPERSEDED" // replaced by a newer document before it was signed
+)
+
+// ContractDocument is a rendered financing contract. The hash is what the consumer signs.
+type ContractDocument struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	TransactionID   uint       `gorm:"index;not null" json:"transaction_id"`
+	TemplateID      uint       `gorm:"not null" json:"template_id"`
+	TemplateCode    string     `gorm:"type:varchar(50)" json:"template_code"`
+	TemplateVersion int        `json:"template_version"`
+	FileName        string     `gorm:"type:varchar(255)" json:"file_name"`
+	DocumentHash    string     `gorm:"type:varchar(64);index;not null" json:"document_hash"` // SHA-256 of the PDF
+	Content         []byte     `gorm:"type:longblob" json:"-"`
+	SizeBytes       int        `json:"size_bytes"`
+	Status          string     `gorm:"type:varchar(20);default:'PENDING';index" json:"status"` // PENDING, SIGNED, SUPERSEDED
+	SignedAt        *time.Time `json:"signed_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// Contract signature statuses
+const (
+	SignatureStatusPending = "PENDING" // OTP sent, waiting for the consumer
+	SignatureStatusSigned  = "SIGNED"
+	SignatureStatusExpired = "EXPIRED" // OTP expired or replaced by a new request
+	SignatureStatusLocked  = "LOCKED"  // too many wrong codes
+)
+
+// ContractSignature is one OTP signing attempt on a contract document and, once signed, the evidence of the signature
+type ContractSignature struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	TransactionID uint       `gorm:"index;not null" json:"transaction_id"`
+	DocumentID    uint       `gorm:"index;not null" json:"document_id"`
+	DocumentHash  string     `gorm:"type:varchar(64);not null" json:"document_hash"` // document the OTP was sent for
+	OTPHash       string     `gorm:"type:varchar(64);not null" json:"otp_hash"`      // HMAC of the code bound to the contract and document
+	OTPChannel    string     `gorm:"type:varchar(20)" json:"otp_channel"`
+	OTPExpiresAt  time.Time  `json:"otp_expires_at"`
+	Attempts      int        `gorm:"default:0" json:"attempts"`
+	Status        string     `gorm:"type:varchar(20);default:'PENDING';index" json:"status"` // PENDING, SIGNED, EXPIRED, LOCKED
+	SignedAt      *time.Time `json:"signed_at,omitempty"`
+	IPAddress     string     `gorm:"type:varchar(45)" json:"ip_address,omitempty"`
+	UserAgent     string     `gorm:"type:varchar(500)" json:"user_agent,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// OTP challenge purposes
+const (
+	OTPPurposeTransaction = "TRANSACTION" // consent to book a contract on the consumer limit
+)
+
+// OTP challenge statuses
+const (
+	OTPStatusPending  = "PENDING"  // code sent, waiting for the action it was issued for
+	OTPStatusConsumed = "CONSUMED" // used by that action, cannot be used again
+	OTPStatusExpired  = "EXPIRED"
+	OTPStatusLocked   = "LOCKED" // too many wrong codes
+)
+
+// OTPChallenge is a one-time code sent to a consumer to consent to one action. The code is only
+// stored as an HMAC bound to the purpose, consumer, merchant and amount, so it is useless for anything else.
+type OTPChallenge struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	ConsumerID uint       `gorm:"index;not null" json:"consumer_id"`
+	Purpose    string     `gorm:"type:varchar(30);not null" json:"purpose"` // TRANSACTION
+	MerchantID uint       `gorm:"index" json:"merchant_id,omitempty"`
+	Amount     float64    `gorm:"type:decimal(15,2)" json:"amount"`
+	CodeHash   string     `gorm:"type:varchar(64);not null" json:"-"`
+	Channel    string     `gorm:"type:varchar(20)" json:"channel"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	Attempts   int        `gorm:"default:0" json:"attempts"`
+	SendCount  int        `gorm:"default:0" json:"send_count"`
+	LastSentAt time.Time  `json:"last_sent_at"`
+	Status     string     `gorm:"type:varchar(20);default:'PENDING';index" json:"status"` // PENDING, CONSUMED, EXPIRED, LOCKED
+	ConsumedAt *time.Time `json:"consumed_at,omitempty"`
+	Reference  string     `gorm:"type:varchar(255)" json:"reference,omitempty"` // contract number the consent is for, given up front or by the action that consumed it
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// Journal event types
+const (
+	JournalEventActivation      = "ACTIVATION"
+	JournalEventCancellation    = "CANCELLATION"
+	JournalEventPayoff          = "PAYOFF"
+	JournalEventMDR             = "MDR"
+	JournalEventMerchantPayment = "MERCHANT_PAYMENT"
+	JournalEventAccrual         = "ACCRUAL"
+	JournalEventAccrualReversal = "ACCRUAL_REVERSAL"
+	JournalEventRestructuring   = "RESTRUCTURING"
+	JournalEventWriteOff        = "WRITE_OFF"
+	JournalEventRecovery        = "RECOVERY"
+	JournalEventInstallment     = "INSTALLMENT_PAYMENT"
+	JournalEventSuspense        = "PAYMENT_SUSPENSE"
+	JournalEventSuspenseRefund  = "SUSPENSE_REFUND"
+	JournalEventAdminFee        = "ADMIN_FEE"
+)
+
+// JournalEntry is a balanced double-entry posting for one business event.
+// Reference is unique so an event is never posted twice.
+type JournalEntry struct {
+	ID            uint          `gorm:"primaryKey" json:"id"`
+	Reference     string        `gorm:"type:varchar(100);uniqueIndex;not null" json:"reference"`
+	EventType     string        `gorm:"type:varchar(30);not null;index" json:"event_type"`
+	TransactionID uint          `gorm:"index" json:"transaction_id,omitempty"` // 0 for merchant-level events
+	Description   string        `gorm:"type:varchar(255)" json:"description"`
+	PostingDate   time.Time     `gorm:"type:date;not null;index" json:"posting_date"`
+	TotalAmount   float64       `gorm:"type:decimal(15,2);not null" json:"total_amount"` // sum of the debits
+	Lines         []JournalLine `gorm:"foreignKey:EntryID" json:"lines,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+}
+
+// JournalLine is one debit or credit of a journal entry
+type JournalLine struct {
+	ID          uint    `gorm:"primaryKey" json:"id"`
+	EntryID     uint    `gorm:"index;not null" json:"entry_id"`
+	AccountCode string  `gorm:"type:varchar(10);not null;index" json:"account_code"`
+	Debit       float64 `gorm:"type:decimal(15,2);default:0" json:"debit"`
+	Credit      float64 `gorm:"type:decimal(15,2);default:0" json:"credit"`
+	Memo        string  `gorm:"type:varchar(255)" json:"memo,omitempty"`
+}
+
+// Installment statuses
+const (
+	InstallmentStatusUnpaid      = "UNPAID"
+	InstallmentStatusPartial     = "PARTIAL"
+	InstallmentStatusPaid        = "PAID"
+	InstallmentStatusCancelled   = "CANCELLED"
+	InstallmentStatusRescheduled = "RESCHEDULED" // replaced by a restructured schedule, kept for history
+)
+
+// Installment is one monthly due of a transaction's repayment schedule
+type Installment struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	TransactionID   uint       `gorm:"index;not null" json:"transaction_id"`
+	Sequence        int        `gorm:"not null" json:"sequence"` // 1..tenor
+	DueDate         time.Time  `gorm:"index;not null" json:"due_date"`
+	PrincipalAmount float64    `gorm:"type:decimal(15,2);not null" json:"principal_amount"`
+	InterestAmount  float64    `gorm:"type:decimal(15,2);default:0" json:"interest_amount"`
+	Amount          float64    `gorm:"type:decimal(15,2);not null" json:"amount"`         // principal + interest
+	PaidAmount      float64    `gorm:"type:decimal(15,2);default:0" json:"paid_amount"`   // money received
+	WaivedAmount    float64    `gorm:"type:decimal(15,2);default:0" json:"waived_amount"` // interest forgiven on early payoff
+	PaidAt          *time.Time `json:"paid_at,omitempty"`
+	LateFee         float64    `gorm:"type:decimal(15,2);default:0" json:"late_fee"`
+	LateFeePaid     float64    `gorm:"type:decimal(15,2);default:0" json:"late_fee_paid"`
+	DaysPastDue     int        `gorm:"default:0" json:"days_past_due"`
+	Status          string     `gorm:"type:varchar(20);default:'UNPAID';index" json:"status"` // UNPAID, PARTIAL, PAID, CANCELLED, RESCHEDULED
+	RestructuringID uint       `gorm:"index" json:"restructuring_id,omitempty"`               // schedule created by a restructuring, 0 for the original
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// Outstanding returns the part of the installment amount neither paid nor waived, excluding late fees.
+// Cancelled and rescheduled installments owe nothing.
+func (i *Installment) Outstanding() float64 {
+	if i.Status == InstallmentStatusCancelled || i.Status == InstallmentStatusRescheduled {
+		return 0
+	}
+	if remaining := i.Amount - i.PaidAmount - i.WaivedAmount; remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// OutstandingLateFee returns the late fee accrued on the installment that is not paid yet
+func (i *Installment) OutstandingLateFee() float64 {
+	if i.Status == InstallmentStatusCancelled || i.Status == InstallmentStatusRescheduled {
+		return 0
+	}
+	if remaining := i.LateFee - i.LateFeePaid; remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// LateFeeCharge is the late fee of an installment as of the business date the daily aging or a restructuring
+// changed it, so the fee owed on any past day can be read back
+type LateFeeCharge struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	InstallmentID uint      `gorm:"uniqueIndex:idx_late_fee_charge_date;not null" json:"installment_id"`
+	TransactionID uint      `gorm:"index;not null" json:"transaction_id"`
+	BusinessDate  time.Time `gorm:"type:date;uniqueIndex:idx_late_fee_charge_date;not null" json:"business_date"`
+	LateFee       float64   `gorm:"type:decimal(15,2);not null" json:"late_fee"` // total late fee of the installment from the date on
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Payment types
+const (
+	PaymentTypeSettlement  = "SETTLEMENT"   // early payoff of the whole contract
+	PaymentTypeInstallment = "INSTALLMENT"  // regular repayment, applied to the oldest installments first
+	PaymentTypeAdminFee    = "ADMIN_FEE"    // receipt of the admin fee, paid upfront by the consumer
+	PaymentTypeDownPayment = "DOWN_PAYMENT" // receipt of the down payment, collected by the merchant before the contract activates
+)
+
+// Payment is money received against a transaction
+type Payment struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	TransactionID uint      `gorm:"index;not null" json:"transaction_id"`
+	ConsumerID    uint      `gorm:"index;not null" json:"consumer_id"`
+	Type          string    `gorm:"type:varchar(20);not null" json:"type"`
+	Amount        float64   `gorm:"type:decimal(15,2);not null" json:"amount"`
+	Reference     string    `gorm:"type:varchar(255);uniqueIndex;not null" json:"reference"` // bank / channel reference, posted once
+	PaidAt        time.Time `json:"paid_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Transaction statuses
+const (
+	TransactionStatusActive             = "ACTIVE"
+	TransactionStatusCompleted          = "COMPLETED"
+	TransactionStatusDefaulted          = "DEFAULTED"
+	TransactionStatusPendingReview      = "PENDING_REVIEW"       // held by fraud rules, limit reserved
+	TransactionStatusPendingSignature   = "PENDING_SIGNATURE"    // contract document not signed by the consumer yet, limit reserved
+	TransactionStatusPendingDownPayment = "PENDING_DOWN_PAYMENT" // down payment receipt not posted yet, limit reserved
+	TransactionStatusRejected           = "REJECTED"             // rejected by fraud review, limit released
+	TransactionStatusCancelled          = "CANCELLED"            // cancelled within the cooling-off window, limit released
+	TransactionStatusWrittenOff         = "WRITTEN_OFF"          // defaulted contract taken off the books, limit released
+)
+
+// Cancellation reason codes
+const (
+	CancelReasonCoolingOff       = "COOLING_OFF"       // consumer withdrew within the cooling-off period
+	CancelReasonGoodsReturned    = "GOODS_RETURNED"    // merchant took back all or part of the goods
+	CancelReasonMerchantError    = "MERCHANT_ERROR"    // contract booked with wrong data
+	CancelReasonSignatureExpired = "SIGNATURE_EXPIRED" // consumer did not sign the contract in time, set by the system
+)
+
+// TransactionCancellation records a full or partial cancellation of a transaction
+type TransactionCancellation struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	TransactionID  uint      `gorm:"index;not null" json:"transaction_id"`
+	ReasonCode     string    `gorm:"type:varchar(30);not null" json:"reason_code"`
+	Note           string    `gorm:"type:text" json:"note"`
+	Amount         float64   `gorm:"type:decimal(15,2);not null" json:"amount"` // cancelled part of the OTR
+	Partial        bool      `json:"partial"`
+	MerchantAmount float64   `gorm:"type:decimal(15,2);default:0" json:"merchant_amount"` // financed principal cancelled plus down payment refunded, billed to the merchant
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Refund statuses
+const (
+	RefundStatusPending = "PENDING"
+	RefundStatusPaid    = "PAID"
+)
+
+// Refund is money owed back to a consumer, such as the admin fee of a cancelled contract
+type Refund struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	TransactionID  uint      `gorm:"index;not null" json:"transaction_id"`
+	ConsumerID     uint      `gorm:"index;not null" json:"consumer_id"`
+	CancellationID uint      `gorm:"index" json:"cancellation_id"`
+	Amount         float64   `gorm:"type:decimal(15,2);not null" json:"amount"`
+	Reason         string    `gorm:"type:varchar(255)" json:"reason"`
+	Status         string    `gorm:"type:varchar(20);default:'PENDING';index" json:"status"` // PENDING, PAID
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// FraudAssessment stores the fraud rule evaluation of a transaction request,
+// including denied requests that never became a transaction
+type FraudAssessment struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	TransactionID  uint      `gorm:"index" json:"transaction_id,omitempty"` // 0 when denied
+	ConsumerID     uint      `gorm:"index;not null" json:"consumer_id"`
+	ContractNumber string    `gorm:"type:varchar(255);index" json:"contract_number"`
+	Score          int       `gorm:"not null" json:"score"`
+	Decision       string    `gorm:"type:varchar(10);not null" json:"decision"` // ALLOW, REVIEW, DENY
+	TriggeredRules string    `gorm:"type:text" json:"triggered_rules"`          // JSON array of rule hits
+	ReviewedBy     string    `gorm:"type:varchar(255)" json:"reviewed_by,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Watchlist list types
+const (
+	WatchlistTypeBlacklist = "BLACKLIST" // exact matches block credit
+	WatchlistTypeWatchlist = "WATCHLIST" // matches require manual review
+)
+
+// Screening decisions and watchlist hit review statuses
+const (
+	ScreeningClear  = "CLEAR"
+	ScreeningReview = "REVIEW"
+	ScreeningBlock  = "BLOCK"
+
+	HitStatusOpen      = "OPEN"
+	HitStatusCleared   = "CLEARED"   // false positive
+	HitStatusConfirmed = "CONFIRMED" // true match
+)
+
+// WatchlistEntry is a person on the internal fraud blacklist or a regulator-supplied list
+type WatchlistEntry struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	Source      string     `gorm:"type:varchar(50);not null;index" json:"source"` // e.g. INTERNAL_FRAUD, OJK, PPATK
+	ListType    string     `gorm:"type:varchar(20);not null" json:"list_type"`    // BLACKLIST, WATCHLIST
+	NIK         string     `gorm:"type:varchar(16);index" json:"nik"`
+	FullName    string     `gorm:"type:varchar(255);not null" json:"full_name"`
+	DateOfBirth *time.Time `gorm:"index" json:"date_of_birth,omitempty"`
+	Reason      string     `gorm:"type:varchar(500)" json:"reason"`
+	Active      bool       `gorm:"default:true;index" json:"active"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// WatchlistHit records a screening match for compliance review
+type WatchlistHit struct {
+	ID               uint       `gorm:"primaryKey" json:"id"`
+	WatchlistEntryID uint       `gorm:"index;not null" json:"watchlist_entry_id"`
+	ConsumerID       uint       `gorm:"index" json:"consumer_id,omitempty"` // 0 when screened before registration
+	SubjectNIK       string     `gorm:"type:varchar(16);index" json:"subject_nik"`
+	SubjectName      string     `gorm:"type:varchar(255)" json:"subject_name"`
+	Context          string     `gorm:"type:varchar(20);not null" json:"context"`     // REGISTRATION, TRANSACTION
+	Reference        string     `gorm:"type:varchar(255)" json:"reference,omitempty"` // e.g. contract number
+	MatchType        string     `gorm:"type:varchar(20);not null" json:"match_type"`  // EXACT_NIK, FUZZY_NAME_DOB
+	Score            float64    `gorm:"type:decimal(5,4)" json:"score"`
+	Action           string     `gorm:"type:varchar(10);not null" json:"action"` // REVIEW, BLOCK
+	Status           string     `gorm:"type:varchar(20);default:'OPEN';index" json:"status"`
+	ReviewedBy       string     `gorm:"type:varchar(255)" json:"reviewed_by,omitempty"`
+	ReviewNote       string     `gorm:"type:varchar(500)" json:"review_note,omitempty"`
+	ReviewedAt       *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
 }
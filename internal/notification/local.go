@@ -0,0 +1,69 @@
+package notification
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ConsoleNotifier is a local channel that writes every message to the log
+type ConsoleNotifier struct {
+	mu   sync.Mutex
+	sent int
+}
+
+func (n *ConsoleNotifier) Send(msg Message) (string, error) {
+	if msg.Recipient == "" {
+		return "", ErrEmptyRecipient
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent++
+	log.Printf("✓ [%s] ke %s: %s\n", msg.Channel, msg.Recipient, msg.Body)
+	return fmt.Sprintf("console-%d", n.sent), nil
+}
+
+// FileNotifier is a local channel that appends every message as a JSON line to <Dir>/<channel>.jsonl,
+// standing in for the SMS, WhatsApp and email gateways outside production
+type FileNotifier struct {
+	Dir string
+	mu  sync.Mutex
+}
+
+func (n *FileNotifier) Send(msg Message) (string, error) {
+	if msg.Recipient == "" {
+		return "", ErrEmptyRecipient
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if err := os.MkdirAll(n.Dir, 0o750); err != nil {
+		return "", err
+	}
+	file, err := os.OpenFile(filepath.Join(n.Dir, strings.ToLower(msg.Channel)+".jsonl"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o640)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	id := fmt.Sprintf("file-%d", time.Now().UnixNano())
+	line, err := json.Marshal(map[string]string{
+		"id":        id,
+		"channel":   msg.Channel,
+		"recipient": msg.Recipient,
+		"subject":   msg.Subject,
+		"body":      msg.Body,
+	})
+	if err != nil {
+		return "", err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return "", err
+	}
+	return id, nil
+}
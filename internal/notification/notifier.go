@@ -0,0 +1,28 @@
+package notification
+
+import "errors"
+
+// Delivery channels
+const (
+	ChannelSMS      = "SMS"
+	ChannelWhatsApp = "WHATSAPP"
+	ChannelEmail    = "EMAIL"
+)
+
+// Channels lists every channel in the order they are tried
+var Channels = []string{ChannelWhatsApp, ChannelSMS, ChannelEmail}
+
+var ErrEmptyRecipient = errors.New("penerima notifikasi tidak boleh kosong")
+
+// Message is one rendered notification for one recipient. Subject is only used by email.
+type Message struct {
+	Channel   string
+	Recipient string // phone number for SMS and WhatsApp, address for email
+	Subject   string
+	Body      string
+}
+
+// Notifier delivers messages over one channel and returns the message ID of the provider
+type Notifier interface {
+	Send(msg Message) (string, error)
+}
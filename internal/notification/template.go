@@ -0,0 +1,243 @@
+package notification
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Languages of the templates; consumers without a preference get Indonesian
+const (
+	LanguageIndonesian = "id"
+	LanguageEnglish    = "en"
+)
+
+// Notification events, each with a template per language
+const (
+	EventTransactionBooked = "TRANSACTION_BOOKED"
+	EventPaymentReceived   = "PAYMENT_RECEIVED"
+	EventStatusChanged     = "STATUS_CHANGED"
+	EventDueSoon           = "REMINDER_DUE_SOON"  // H-3
+	EventDueToday          = "REMINDER_DUE_TODAY" // H-0
+	EventOverdue           = "REMINDER_OVERDUE"   // H+1
+	EventSigningOTP        = "CONTRACT_SIGNING_OTP"
+	EventTransactionOTP    = "TRANSACTION_OTP"
+)
+
+// TemplateData is what the templates can refer to. Amount is the transaction, payment or installment amount of the event.
+type TemplateData struct {
+	Name              string
+	ContractNumber    string
+	Amount            float64
+	InstallmentAmount float64
+	Tenor             int
+	Sequence          int
+	DueDate           time.Time
+	Status            string
+	Code              string    // one-time code, masked in the delivery log
+	ExpiresAt         time.Time // when the code stops working
+	MerchantName      string
+}
+
+type messageTemplate struct {
+	subject string
+	body    string
+}
+
+var templateSources = map[string]map[string]messageTemplate{
+	EventTransactionBooked: {
+		LanguageIndonesian: {
+			subject: "Transaksi {{.ContractNumber}} berhasil",
+			body: "Halo {{.Name}}, transaksi {{.ContractNumber}} sebesar {{rupiah .Amount}} telah tercatat pada limit Anda. " +
+				"Cicilan {{.Tenor}} x {{rupiah .InstallmentAmount}}, jatuh tempo pertama {{date .DueDate}}.",
+		},
+		LanguageEnglish: {
+			subject: "Transaction {{.ContractNumber}} confirmed",
+			body: "Hi {{.Name}}, transaction {{.ContractNumber}} of {{rupiah .Amount}} has been booked on your limit. " +
+				"Installments {{.Tenor}} x {{rupiah .InstallmentAmount}}, first due on {{date .DueDate}}.",
+		},
+	},
+	EventPaymentReceived: {
+		LanguageIndonesian: {
+			subject: "Pembayaran {{.ContractNumber}} diterima",
+			body:    "Halo {{.Name}}, pembayaran {{rupiah .Amount}} untuk kontrak {{.ContractNumber}} telah kami terima. Terima kasih.",
+		},
+		LanguageEnglish: {
+			subject: "Payment for {{.ContractNumber}} received",
+			body:    "Hi {{.Name}}, we have received your payment of {{rupiah .Amount}} for contract {{.ContractNumber}}. Thank you.",
+		},
+	},
+	EventStatusChanged: {
+		LanguageIndonesian: {
+			subject: "Status kontrak {{.ContractNumber}}",
+			body:    "Halo {{.Name}}, status kontrak {{.ContractNumber}} kini {{status .Status}}.",
+		},
+		LanguageEnglish: {
+			subject: "Contract {{.ContractNumber}} status",
+			body:    "Hi {{.Name}}, contract {{.ContractNumber}} is now {{status .Status}}.",
+		},
+	},
+	EventDueSoon: {
+		LanguageIndonesian: {
+			subject: "Cicilan {{.ContractNumber}} jatuh tempo {{date .DueDate}}",
+			body: "Halo {{.Name}}, cicilan ke-{{.Sequence}} kontrak {{.ContractNumber}} sebesar {{rupiah .Amount}} " +
+				"jatuh tempo pada {{date .DueDate}}. Mohon siapkan pembayaran Anda.",
+		},
+		LanguageEnglish: {
+			subject: "{{.ContractNumber}} installment due on {{date .DueDate}}",
+			body: "Hi {{.Name}}, installment {{.Sequence}} of contract {{.ContractNumber}} for {{rupiah .Amount}} " +
+				"is due on {{date .DueDate}}. Please prepare your payment.",
+		},
+	},
+	EventDueToday: {
+		LanguageIndonesian: {
+			subject: "Cicilan {{.ContractNumber}} jatuh tempo hari ini",
+			body: "Halo {{.Name}}, cicilan ke-{{.Sequence}} kontrak {{.ContractNumber}} sebesar {{rupiah .Amount}} " +
+				"jatuh tempo hari ini. Bayar hari ini untuk menghindari denda.",
+		},
+		LanguageEnglish: {
+			subject: "{{.ContractNumber}} installment due today",
+			body: "Hi {{.Name}}, installment {{.Sequence}} of contract {{.ContractNumber}} for {{rupiah .Amount}} " +
+				"is due today. Pay today to avoid late fees.",
+		},
+	},
+	EventOverdue: {
+		LanguageIndonesian: {
+			subject: "Cicilan {{.ContractNumber}} terlambat",
+			body: "Halo {{.Name}}, cicilan ke-{{.Sequence}} kontrak {{.ContractNumber}} sebesar {{rupiah .Amount}} " +
+				"telah melewati jatuh tempo {{date .DueDate}}. Segera lakukan pembayaran untuk menghindari denda tambahan.",
+		},
+		LanguageEnglish: {
+			subject: "{{.ContractNumber}} installment overdue",
+			body: "Hi {{.Name}}, installment {{.Sequence}} of contract {{.ContractNumber}} for {{rupiah .Amount}} " +
+				"was due on {{date .DueDate}}. Please pay now to avoid further late fees.",
+		},
+	},
+	EventSigningOTP: {
+		LanguageIndonesian: {
+			subject: "Kode tanda tangan kontrak {{.ContractNumber}}",
+			body: "Kode OTP untuk menandatangani kontrak {{.ContractNumber}} sebesar {{rupiah .Amount}}: {{.Code}}. " +
+				"Berlaku sampai pukul {{time .ExpiresAt}}. JANGAN berikan kode ini kepada siapa pun, termasuk petugas kami.",
+		},
+		LanguageEnglish: {
+			subject: "Signing code for contract {{.ContractNumber}}",
+			body: "Your OTP to sign contract {{.ContractNumber}} of {{rupiah .Amount}} is {{.Code}}. " +
+				"Valid until {{time .ExpiresAt}}. NEVER share this code with anyone, including our staff.",
+		},
+	},
+	EventTransactionOTP: {
+		LanguageIndonesian: {
+			subject: "Kode persetujuan transaksi",
+			body: "Kode OTP untuk menyetujui transaksi{{if .ContractNumber}} {{.ContractNumber}}{{end}} sebesar {{rupiah .Amount}}{{if .MerchantName}} di {{.MerchantName}}{{end}} " +
+				"pada limit Anda: {{.Code}}. Berlaku sampai pukul {{time .ExpiresAt}}. " +
+				"Abaikan pesan ini jika Anda tidak sedang bertransaksi dan JANGAN berikan kode ini kepada siapa pun.",
+		},
+		LanguageEnglish: {
+			subject: "Transaction approval code",
+			body: "Your OTP to approve transaction{{if .ContractNumber}} {{.ContractNumber}}{{end}} of {{rupiah .Amount}}{{if .MerchantName}} at {{.MerchantName}}{{end}} " +
+				"on your limit is {{.Code}}. Valid until {{time .ExpiresAt}}. " +
+				"Ignore this message if you are not making a purchase and NEVER share this code with anyone.",
+		},
+	},
+}
+
+var statusLabels = map[string]map[string]string{
+	LanguageIndonesian: {
+		"ACTIVE":               "aktif",
+		"COMPLETED":            "lunas",
+		"DEFAULTED":            "macet",
+		"REJECTED":             "ditolak",
+		"CANCELLED":            "dibatalkan",
+		"PENDING_SIGNATURE":    "menunggu tanda tangan",
+		"PENDING_DOWN_PAYMENT": "menunggu pembayaran DP",
+	},
+	LanguageEnglish: {
+		"ACTIVE":               "active",
+		"COMPLETED":            "paid off",
+		"DEFAULTED":            "in default",
+		"REJECTED":             "rejected",
+		"CANCELLED":            "cancelled",
+		"PENDING_SIGNATURE":    "awaiting signature",
+		"PENDING_DOWN_PAYMENT": "awaiting down payment",
+	},
+}
+
+type parsedTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+var templates = parseTemplates()
+
+func parseTemplates() map[string]map[string]parsedTemplate {
+	parsed := make(map[string]map[string]parsedTemplate)
+	for event, languages := range templateSources {
+		parsed[event] = make(map[string]parsedTemplate)
+		for language, source := range languages {
+			funcs := templateFuncs(language)
+			parsed[event][language] = parsedTemplate{
+				subject: template.Must(template.New(event + "-subject").Funcs(funcs).Parse(source.subject)),
+				body:    template.Must(template.New(event + "-body").Funcs(funcs).Parse(source.body)),
+			}
+		}
+	}
+	return parsed
+}
+
+func templateFuncs(language string) template.FuncMap {
+	return template.FuncMap{
+		"rupiah": FormatRupiah,
+		"date":   func(t time.Time) string { return t.Format("02-01-2006") },
+		"time":   func(t time.Time) string { return t.Format("15:04") },
+		"status": func(status string) string {
+			if label, ok := statusLabels[language][status]; ok {
+				return label
+			}
+			return status
+		},
+	}
+}
+
+// SupportedLanguage reports whether templates exist in the language
+func SupportedLanguage(language string) bool {
+	return language == LanguageIndonesian || language == LanguageEnglish
+}
+
+// Render fills the template of the event in the language, falling back to Indonesian
+func Render(event, language string, data TemplateData) (subject, body string, err error) {
+	languages, ok := templates[event]
+	if !ok {
+		return "", "", fmt.Errorf("template notifikasi %s tidak ditemukan", event)
+	}
+	tmpl, ok := languages[language]
+	if !ok {
+		tmpl = languages[LanguageIndonesian]
+	}
+
+	var subjectBuf, bodyBuf strings.Builder
+	if err := tmpl.subject.Execute(&subjectBuf, data); err != nil {
+		return "", "", err
+	}
+	if err := tmpl.body.Execute(&bodyBuf, data); err != nil {
+		return "", "", err
+	}
+	return subjectBuf.String(), bodyBuf.String(), nil
+}
+
+// FormatRupiah writes an amount the Indonesian way, rounded to the rupiah: Rp1.100.000
+func FormatRupiah(amount float64) string {
+	digits := fmt.Sprintf("%d", int64(math.Round(math.Abs(amount))))
+	var grouped strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+	if amount < 0 {
+		return "-Rp" + grouped.String()
+	}
+	return "Rp" + grouped.String()
+}
@@ -0,0 +1,82 @@
+package notification
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test: Amounts use the Indonesian thousands separator
+func TestFormatRupiah(t *testing.T) {
+	cases := map[float64]string{
+		0:          "Rp0",
+		950:        "Rp950",
+		1100000:    "Rp1.100.000",
+		1234567.6:  "Rp1.234.568",
+		-150000:    "-Rp150.000",
+		1000000000: "Rp1.000.000.000",
+	}
+	for amount, expected := range cases {
+		if got := FormatRupiah(amount); got != expected {
+			t.Errorf("FormatRupiah(%.2f) = %q, expected %q", amount, got, expected)
+		}
+	}
+}
+
+// Test: Every event renders in both languages and unknown languages fall back to Indonesian
+func TestRender(t *testing.T) {
+	data := TemplateData{
+		Name:           "Budi",
+		ContractNumber: "CTR-001",
+		Amount:         1100000,
+		Sequence:       2,
+		DueDate:        time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		Status:         "COMPLETED",
+	}
+
+	for event := range templateSources {
+		for _, language := range []string{LanguageIndonesian, LanguageEnglish} {
+			subject, body, err := Render(event, language, data)
+			if err != nil || subject == "" || !strings.Contains(body, "CTR-001") {
+				t.Errorf("Expected %s/%s to render, got %q, %q, %v", event, language, subject, body, err)
+			}
+		}
+	}
+
+	_, body, _ := Render(EventDueSoon, LanguageIndonesian, data)
+	if body != "Halo Budi, cicilan ke-2 kontrak CTR-001 sebesar Rp1.100.000 jatuh tempo pada 01-03-2026. Mohon siapkan pembayaran Anda." {
+		t.Errorf("Unexpected body %q", body)
+	}
+	_, body, _ = Render(EventStatusChanged, "fr", data)
+	if body != "Halo Budi, status kontrak CTR-001 kini lunas." {
+		t.Errorf("Expected Indonesian fallback, got %q", body)
+	}
+	if _, _, err := Render("UNKNOWN", LanguageIndonesian, data); err == nil {
+		t.Error("Expected error for unknown event, got nil")
+	}
+}
+
+// Test: The file channel appends one JSON line per message to the file of the channel
+func TestFileNotifier(t *testing.T) {
+	dir := t.TempDir()
+	notifier := &FileNotifier{Dir: dir}
+
+	if _, err := notifier.Send(Message{Channel: ChannelSMS, Body: "hi"}); err != ErrEmptyRecipient {
+		t.Errorf("Expected ErrEmptyRecipient, got %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := notifier.Send(Message{Channel: ChannelSMS, Recipient: "081234567890", Body: "hi"}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "sms.jsonl"))
+	if err != nil {
+		t.Fatalf("Expected outbox file, got %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(content)), "\n"); len(lines) != 2 || !strings.Contains(lines[0], `"recipient":"081234567890"`) {
+		t.Errorf("Expected 2 JSON lines, got %q", content)
+	}
+}
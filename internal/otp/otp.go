@@ -0,0 +1,43 @@
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math/big"
+)
+
+var ErrInvalidLength = errors.New("panjang kode OTP harus 4 sampai 10 digit")
+
+// Generate returns a random numeric code of the given number of digits, leading zeros kept
+func Generate(digits int) (string, error) {
+	if digits < 4 || digits > 10 {
+		return "", ErrInvalidLength
+	}
+	code := make([]byte, digits)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		code[i] = byte('0' + n.Int64())
+	}
+	return string(code), nil
+}
+
+// Hash keys the code to what it confirms, so a stored hash cannot be replayed for another action
+// and short codes cannot be brute forced from the database without the key
+func Hash(key []byte, binding, code string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(binding))
+	mac.Write([]byte{0})
+	mac.Write([]byte(code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify compares a code with a stored hash in constant time
+func Verify(key []byte, binding, code, hash string) bool {
+	return hmac.Equal([]byte(Hash(key, binding, code)), []byte(hash))
+}
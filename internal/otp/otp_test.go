@@ -0,0 +1,45 @@
+package otp
+
+import "testing"
+
+var testKey = []byte("test-otp-key")
+
+// Test: Codes have the requested number of digits and keep leading zeros
+func TestGenerate(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		code, err := Generate(6)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(code) != 6 {
+			t.Fatalf("Expected 6 digits, got %q", code)
+		}
+		for _, c := range code {
+			if c < '0' || c > '9' {
+				t.Fatalf("Expected digits only, got %q", code)
+			}
+		}
+	}
+
+	if _, err := Generate(3); err != ErrInvalidLength {
+		t.Errorf("Expected ErrInvalidLength, got %v", err)
+	}
+}
+
+// Test: A hash only verifies the same code for the same binding and key
+func TestVerify(t *testing.T) {
+	hash := Hash(testKey, "SIGN:1:abc", "123456")
+
+	if !Verify(testKey, "SIGN:1:abc", "123456", hash) {
+		t.Error("Expected the code verified")
+	}
+	if Verify(testKey, "SIGN:1:abc", "123457", hash) {
+		t.Error("Expected a wrong code rejected")
+	}
+	if Verify(testKey, "SIGN:2:abc", "123456", hash) {
+		t.Error("Expected the code rejected for another binding")
+	}
+	if Verify([]byte("other-key"), "SIGN:1:abc", "123456", hash) {
+		t.Error("Expected the code rejected under another key")
+	}
+}
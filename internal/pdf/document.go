@@ -0,0 +1,307 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A4 portrait in points, with the same margin on every side
+const (
+	pageWidth    = 595.28
+	pageHeight   = 841.89
+	margin       = 50.0
+	contentWidth = pageWidth - 2*margin
+	footerY      = 30.0
+)
+
+// Font sizes and line spacing
+const (
+	titleSize   = 16.0
+	headingSize = 12.0
+	textSize    = 10.0
+	tableSize   = 8.5
+	footerSize  = 8.0
+	leading     = 1.4 // line height as a multiple of the font size
+)
+
+// Column is one column of a table. Widths are in points; Right aligns amounts.
+type Column struct {
+	Title string
+	Width float64
+	Right bool
+}
+
+// Document lays out text and tables top to bottom on A4 pages using the built-in Helvetica fonts,
+// so no font files are needed. Output is deterministic: the same calls give the same bytes.
+type Document struct {
+	title string
+	pages []*bytes.Buffer
+	y     float64
+}
+
+// New starts a document; the title goes into the document info and the footer of every page
+func New(title string) *Document {
+	d := &Document{title: title}
+	d.newPage()
+	return d
+}
+
+func (d *Document) newPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = pageHeight - margin
+}
+
+// ensure starts a new page when less than height is left above the footer
+func (d *Document) ensure(height float64) {
+	if d.y-height < margin {
+		d.newPage()
+	}
+}
+
+func (d *Document) page() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+// Title writes the document title in large bold type
+func (d *Document) Title(text string) {
+	d.line(text, titleSize, true)
+	d.Space(6)
+}
+
+// Heading writes a section heading with a rule under it
+func (d *Document) Heading(text string) {
+	d.ensure(headingSize * leading * 3)
+	d.Space(6)
+	d.line(text, headingSize, true)
+	d.rule(d.y+headingSize*0.3, margin, pageWidth-margin)
+	d.Space(4)
+}
+
+// Text writes a paragraph, wrapped to the page width
+func (d *Document) Text(text string) {
+	for _, paragraph := range strings.Split(text, "\n") {
+		for _, line := range wrap(paragraph, textSize, false, contentWidth) {
+			d.line(line, textSize, false)
+		}
+	}
+}
+
+// Field writes a label and its value on one line, the value wrapped next to the label
+func (d *Document) Field(label, value string) {
+	const labelWidth = 160.0
+	lines := wrap(value, textSize, false, contentWidth-labelWidth)
+	d.ensure(float64(len(lines)) * textSize * leading)
+	d.text(margin, d.y-textSize, label, textSize, true)
+	for _, line := range lines {
+		d.text(margin+labelWidth, d.y-textSize, line, textSize, false)
+		d.y -= textSize * leading
+	}
+}
+
+// Table writes rows under a bold header row. The header is repeated on every page the table spans.
+func (d *Document) Table(columns []Column, rows [][]string) {
+	rowHeight := tableSize * leading * 1.2
+	header := func() {
+		d.ensure(rowHeight * 2)
+		d.row(columns, nil, true)
+		d.rule(d.y+tableSize*0.4, margin, margin+tableWidth(columns))
+	}
+	header()
+	for _, row := range rows {
+		if d.y-rowHeight < margin {
+			d.newPage()
+			header()
+		}
+		d.row(columns, row, false)
+	}
+	d.rule(d.y+tableSize*0.4, margin, margin+tableWidth(columns))
+	d.Space(4)
+}
+
+func (d *Document) row(columns []Column, cells []string, bold bool) {
+	x := margin
+	for i, column := range columns {
+		value := column.Title
+		if !bold {
+			value = ""
+			if i < len(cells) {
+				value = cells[i]
+			}
+		}
+		value = truncate(value, tableSize, bold, column.Width-4)
+		left := x + 2
+		if column.Right {
+			left = x + column.Width - 2 - textWidth(value, tableSize, bold)
+		}
+		d.text(left, d.y-tableSize, value, tableSize, bold)
+		x += column.Width
+	}
+	d.y -= tableSize * leading * 1.2
+}
+
+// Space moves the cursor down by the given number of points
+func (d *Document) Space(points float64) {
+	d.y -= points
+	if d.y < margin {
+		d.newPage()
+	}
+}
+
+func (d *Document) line(text string, size float64, bold bool) {
+	d.ensure(size * leading)
+	d.text(margin, d.y-size, text, size, bold)
+	d.y -= size * leading
+}
+
+func (d *Document) text(x, y float64, text string, size float64, bold bool) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(d.page(), "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, escape(text))
+}
+
+func (d *Document) rule(y, x1, x2 float64) {
+	fmt.Fprintf(d.page(), "0.5 w %.2f %.2f m %.2f %.2f l S\n", x1, y, x2, y)
+}
+
+// Bytes renders the document with a page number footer on every page
+func (d *Document) Bytes() []byte {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objects 1-4: catalog, page tree, regular and bold font; then a page and its content per page
+	const firstPage = 5
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range d.pages {
+		content := page.String() + fmt.Sprintf("BT /F1 %.1f Tf %.2f %.2f Td (%s) Tj ET\n",
+			footerSize, margin, footerY, escape(truncate(d.title, footerSize, false, contentWidth-80)))
+		footer := fmt.Sprintf("Halaman %d dari %d", i+1, len(d.pages))
+		content += fmt.Sprintf("BT /F1 %.1f Tf %.2f %.2f Td (%s) Tj ET\n",
+			footerSize, pageWidth-margin-textWidth(footer, footerSize, false), footerY, escape(footer))
+
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, firstPage+2*i+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+	object(fmt.Sprintf("<< /Title (%s) /Producer (multifinance) >>", escape(d.title)))
+	info := len(offsets)
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, info, xref)
+	return out.Bytes()
+}
+
+func tableWidth(columns []Column) float64 {
+	var width float64
+	for _, column := range columns {
+		width += column.Width
+	}
+	return width
+}
+
+// escape makes text safe inside a PDF string. Characters outside printable ASCII become '?'.
+func escape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 32 || r > 126:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// wrap breaks text into lines no wider than width, at spaces where possible
+func wrap(text string, size float64, bold bool, width float64) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return []string{""}
+	}
+	var lines []string
+	current := ""
+	for _, word := range words {
+		candidate := word
+		if current != "" {
+			candidate = current + " " + word
+		}
+		if textWidth(candidate, size, bold) <= width || current == "" {
+			current = candidate
+			continue
+		}
+		lines = append(lines, current)
+		current = word
+	}
+	lines = append(lines, truncate(current, size, bold, width))
+	for i, line := range lines[:len(lines)-1] {
+		lines[i] = truncate(line, size, bold, width)
+	}
+	return lines
+}
+
+// truncate shortens text that does not fit the width, ending it with ".."
+func truncate(text string, size float64, bold bool, width float64) string {
+	if textWidth(text, size, bold) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && textWidth(string(runes)+"..", size, bold) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + ".."
+}
+
+// textWidth measures text in points with the Helvetica metrics. Bold is about 5% wider.
+func textWidth(text string, size float64, bold bool) float64 {
+	var units int
+	for _, r := range text {
+		if r >= 32 && r <= 126 {
+			units += helveticaWidths[r-32]
+		} else {
+			units += helveticaWidths['?'-32]
+		}
+	}
+	width := float64(units) * size / 1000
+	if bold {
+		width *= 1.05
+	}
+	return width
+}
+
+// helveticaWidths are the advance widths of printable ASCII in Helvetica, in 1/1000 of the font size
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space to /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, // 0 to 9
+	278, 278, 584, 584, 584, 556, 1015, // : to @
+	667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, // A to M
+	722, 778, 667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, // N to Z
+	278, 278, 278, 469, 556, 333, // [ to `
+	556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, // a to m
+	556, 556, 556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, // n to z
+	334, 260, 334, 584, // { to ~
+}
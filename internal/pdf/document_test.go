@@ -0,0 +1,84 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+// Test: The cross-reference table points at every object
+func TestBytes_Structure(t *testing.T) {
+	doc := New("Laporan")
+	doc.Title("Laporan Rekening")
+	doc.Field("Konsumen", "Budi (Santoso)")
+	out := doc.Bytes()
+
+	if !bytes.HasPrefix(out, []byte("%PDF-1.4")) || !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+		t.Fatal("Expected PDF header and trailer")
+	}
+	if !bytes.Contains(out, []byte(`(Budi \(Santoso\))`)) {
+		t.Error("Expected parentheses escaped in the text")
+	}
+
+	match := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	if match == nil {
+		t.Fatal("Expected startxref")
+	}
+	xref, _ := strconv.Atoi(string(match[1]))
+	if !bytes.HasPrefix(out[xref:], []byte("xref\n")) {
+		t.Fatalf("Expected startxref to point at the xref table, got %q", out[xref:xref+10])
+	}
+	offsets := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out[xref:], -1)
+	if len(offsets) != 7 {
+		t.Fatalf("Expected 7 objects for a one page document, got %d", len(offsets))
+	}
+	for i, offset := range offsets {
+		at, _ := strconv.Atoi(string(offset[1]))
+		if want := fmt.Sprintf("%d 0 obj", i+1); !bytes.HasPrefix(out[at:], []byte(want)) {
+			t.Errorf("Expected object %d at offset %d", i+1, at)
+		}
+	}
+}
+
+// Test: Long tables continue on new pages and output is deterministic
+func TestTable_PageBreak(t *testing.T) {
+	build := func() []byte {
+		doc := New("Tabel")
+		rows := make([][]string, 80)
+		for i := range rows {
+			rows[i] = []string{strconv.Itoa(i + 1), "Rp1.100.000"}
+		}
+		doc.Table([]Column{{Title: "No", Width: 40}, {Title: "Jumlah", Width: 100, Right: true}}, rows)
+		return doc.Bytes()
+	}
+
+	out := build()
+	if !bytes.Contains(out, []byte("/Count 2")) || !bytes.Contains(out, []byte("(Halaman 2 dari 2)")) {
+		t.Error("Expected 80 rows to span two pages")
+	}
+	if bytes.Count(out, []byte("(Jumlah)")) != 2 {
+		t.Error("Expected the header repeated on the second page")
+	}
+	if !bytes.Equal(out, build()) {
+		t.Error("Expected identical output for identical documents")
+	}
+}
+
+// Test: Text is wrapped within the width and non-ASCII characters are replaced
+func TestWrapAndEscape(t *testing.T) {
+	lines := wrap("pembayaran angsuran kontrak pembiayaan konsumen", textSize, false, 100)
+	if len(lines) < 2 {
+		t.Fatalf("Expected the text wrapped, got %v", lines)
+	}
+	for _, line := range lines {
+		if textWidth(line, textSize, false) > 100 {
+			t.Errorf("Expected %q within 100 points", line)
+		}
+	}
+
+	if got := escape(`Rp 1.000 \ “ok”`); got != `Rp 1.000 \\ ?ok?` {
+		t.Errorf("Expected backslash escaped and quotes replaced, got %q", got)
+	}
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"main/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// InterestAccrualRepository defines all operations for InterestAccrual entity
+type InterestAccrualRepository interface {
+	Create(accrual *model.InterestAccrual) error
+	GetByTransactionID(transactionID uint) ([]model.InterestAccrual, error)
+	Update(accrual *model.InterestAccrual) error
+}
+
+// interestAccrualRepository is the implementation of InterestAccrualRepository
+type interestAccrualRepository struct {
+	db *gorm.DB
+}
+
+// NewInterestAccrualRepository creates a new instance of InterestAccrualRepository
+func NewInterestAccrualRepository(db *gorm.DB) InterestAccrualRepository {
+	return &interestAccrualRepository{db: db}
+}
+
+func (r *interestAccrualRepository) Create(accrual *model.InterestAccrual) error {
+	return r.db.Create(accrual).Error
+}
+
+func (r *interestAccrualRepository) GetByTransactionID(transactionID uint) ([]model.InterestAccrual, error) {
+	var accruals []model.InterestAccrual
+	err := r.db.Where("transaction_id = ?", transactionID).Order("accrual_date ASC").Find(&accruals).Error
+	return accruals, err
+}
+
+func (r *interestAccrualRepository) Update(accrual *model.InterestAccrual) error {
+	return r.db.Save(accrual).Error
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"main/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// BankStatementRepository defines all operations for BankStatement entity
+type BankStatementRepository interface {
+	Create(statement *model.BankStatement) error
+	GetByID(id uint) (*model.BankStatement, error)
+	GetByFileHash(fileHash string) (*model.BankStatement, error)
+	GetAll() ([]model.BankStatement, error)
+	Update(statement *model.BankStatement) error
+}
+
+// bankStatementRepository is the implementation of BankStatementRepository
+type bankStatementRepository struct {
+	db *gorm.DB
+}
+
+// NewBankStatementRepository creates a new instance of BankStatementRepository
+func NewBankStatementRepository(db *gorm.DB) BankStatementRepository {
+	return &bankStatementRepository{db: db}
+}
+
+func (r *bankStatementRepository) Create(statement *model.BankStatement) error {
+	return r.db.Create(statement).Error
+}
+
+func (r *bankStatementRepository) GetByID(id uint) (*model.BankStatement, error) {
+	var statement model.BankStatement
+	err := r.db.First(&statement, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &statement, nil
+}
+
+func (r *bankStatementRepository) GetByFileHash(fileHash string) (*model.BankStatement, error) {
+	var statement model.BankStatement
+	err := r.db.Where("file_hash = ?", fileHash).First(&statement).Error
+	if err != nil {
+		return nil, err
+	}
+	return &statement, nil
+}
+
+func (r *bankStatementRepository) GetAll() ([]model.BankStatement, error) {
+	var statements []model.BankStatement
+	err := r.db.Order("created_at DESC").Find(&statements).Error
+	return statements, err
+}
+
+func (r *bankStatementRepository) Update(statement *model.BankStatement) error {
+	return r.db.Save(statement).Error
+}
+
+// BankStatementLineRepository defines all operations for BankStatementLine entity
+type BankStatementLineRepository interface {
+	Create(line *model.BankStatementLine) error
+	GetByID(id uint) (*model.BankStatementLine, error)
+	GetByStatementID(statementID uint) ([]model.BankStatementLine, error)
+	GetByCallbackID(callbackID uint) (*model.BankStatementLine, error)
+	GetByVANumber(vaNumber string) ([]model.BankStatementLine, error)
+	Update(line *model.BankStatementLine) error
+}
+
+// bankStatementLineRepository is the implementation of BankStatementLineRepository
+type bankStatementLineRepository struct {
+	db *gorm.DB
+}
+
+// NewBankStatementLineRepository creates a new instance of BankStatementLineRepository
+func NewBankStatementLineRepository(db *gorm.DB) BankStatementLineRepository {
+	return &bankStatementLineRepository{db: db}
+}
+
+func (r *bankStatementLineRepository) Create(line *model.BankStatementLine) error {
+	return r.db.Create(line).Error
+}
+
+func (r *bankStatementLineRepository) GetByID(id uint) (*model.BankStatementLine, error) {
+	var line model.BankStatementLine
+	err := r.db.First(&line, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &line, nil
+}
+
+func (r *bankStatementLineRepository) GetByStatementID(statementID uint) ([]model.BankStatementLine, error) {
+	var lines []model.BankStatementLine
+	err := r.db.Where("statement_id = ?", statementID).Order("line_number ASC").Find(&lines).Error
+	return lines, err
+}
+
+// GetByCallbackID returns the statement line a payment callback was reconciled to
+func (r *bankStatementLineRepository) GetByCallbackID(callbackID uint) (*model.BankStatementLine, error) {
+	var line model.BankStatementLine
+	err := r.db.Where("callback_id = ?", callbackID).First(&line).Error
+	if err != nil {
+		return nil, err
+	}
+	return &line, nil
+}
+
+// GetByVANumber returns the lines paid into a virtual account across all statements, oldest first
+func (r *bankStatementLineRepository) GetByVANumber(vaNumber string) ([]model.BankStatementLine, error) {
+	var lines []model.BankStatementLine
+	err := r.db.Where("va_number = ?", vaNumber).Order("id ASC").Find(&lines).Error
+	return lines, err
+}
+
+func (r *bankStatementLineRepository) Update(line *model.BankStatementLine) error {
+	return r.db.Save(line).Error
+}
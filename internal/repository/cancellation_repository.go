@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"time"
+
+	"main/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// TransactionCancellationRepository defines all operations for TransactionCancellation entity
+type TransactionCancellationRepository interface {
+	Create(cancellation *model.TransactionCancellation) error
+	GetByTransactionID(transactionID uint) ([]model.TransactionCancellation, error)
+	GetBilledBefore(before time.Time) ([]model.TransactionCancellation, error)
+}
+
+// transactionCancellationRepository is the implementation of TransactionCancellationRepository
+type transactionCancellationRepository struct {
+	db *gorm.DB
+}
+
+// NewTransactionCancellationRepository creates a new instance of TransactionCancellationRepository
+func NewTransactionCancellationRepository(db *gorm.DB) TransactionCancellationRepository {
+	return &transactionCancellationRepository{db: db}
+}
+
+func (r *transactionCancellationRepository) Create(cancellation *model.TransactionCancellation) error {
+	return r.db.Create(cancellation).Error
+}
+
+func (r *transactionCancellationRepository) GetByTransactionID(transactionID uint) ([]model.TransactionCancellation, error) {
+	var cancellations []model.TransactionCancellation
+	err := r.db.Where("transaction_id = ?", transactionID).Order("created_at ASC").Find(&cancellations).Error
+	return cancellations, err
+}
+
+// GetBilledBefore returns the cancellations made before the given time that bill an amount back to a merchant
+func (r *transactionCancellationRepository) GetBilledBefore(before time.Time) ([]model.TransactionCancellation, error) {
+	var cancellations []model.TransactionCancellation
+	err := r.db.Where("merchant_amount > 0 AND created_at < ?", before).Order("id ASC").Find(&cancellations).Error
+	return cancellations, err
+}
+
+// RefundRepository defines all operations for Refund entity
+type RefundRepository interface {
+	Create(refund *model.Refund) error
+	GetByTransactionID(transactionID uint) ([]model.Refund, error)
+}
+
+// refundRepository is the implementation of RefundRepository
+type refundRepository struct {
+	db *gorm.DB
+}
+
+// NewRefundRepository creates a new instance of RefundRepository
+func NewRefundRepository(db *gorm.DB) RefundRepository {
+	return &refundRepository{db: db}
+}
+
+func (r *refundRepository) Create(refund *model.Refund) error {
+	return r.db.Create(refund).Error
+}
+
+func (r *refundRepository) GetByTransactionID(transactionID uint) ([]model.Refund, error) {
+	var refunds []model.Refund
+	err := r.db.Where("transaction_id = ?", transactionID).Order("created_at ASC").Find(&refunds).Error
+	return refunds, err
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"main/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// CollectionCaseRepository defines all operations for CollectionCase entity
+type CollectionCaseRepository interface {
+	Create(collectionCase *model.CollectionCase) error
+	GetByID(id uint) (*model.CollectionCase, error)
+	GetByTransactionID(transactionID uint) (*model.CollectionCase, error)
+	GetByStatus(status string) ([]model.CollectionCase, error)
+	Update(collectionCase *model.CollectionCase) error
+}
+
+// collectionCaseRepository is the implementation of CollectionCaseRepository
+type collectionCaseRepository struct {
+	db *gorm.DB
+}
+
+// NewCollectionCaseRepository creates a new instance of CollectionCaseRepository
+func NewCollectionCaseRepository(db *gorm.DB) CollectionCaseRepository {
+	return &collectionCaseRepository{db: db}
+}
+
+func (r *collectionCaseRepository) Create(collectionCase *model.CollectionCase) error {
+	return r.db.Create(collectionCase).Error
+}
+
+func (r *collectionCaseRepository) GetByID(id uint) (*model.CollectionCase, error) {
+	var collectionCase model.CollectionCase
+	err := r.db.First(&collectionCase, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &collectionCase, nil
+}
+
+func (r *collectionCaseRepository) GetByTransactionID(transactionID uint) (*model.CollectionCase, error) {
+	var collectionCase model.CollectionCase
+	err := r.db.Where("transaction_id = ?", transactionID).First(&collectionCase).Error
+	if err != nil {
+		return nil, err
+	}
+	return &collectionCase, nil
+}
+
+func (r *collectionCaseRepository) GetByStatus(status string) ([]model.CollectionCase, error) {
+	var cases []model.CollectionCase
+	err := r.db.Where("status = ?", status).Order("priority DESC").Find(&cases).Error
+	return cases, err
+}
+
+func (r *collectionCaseRepository) Update(collectionCase *model.CollectionCase) error {
+	return r.db.Save(collectionCase).Error
+}
+
+// ContactAttemptRepository defines all operations for ContactAttempt entity
+type ContactAttemptRepository interface {
+	Create(attempt *model.ContactAttempt) error
+	GetByCaseID(caseID uint) ([]model.ContactAttempt, error)
+}
+
+// contactAttemptRepository is the implementation of ContactAttemptRepository
+type contactAttemptRepository struct {
+	db *gorm.DB
+}
+
+// NewContactAttemptRepository creates a new instance of ContactAttemptRepository
+func NewContactAttemptRepository(db *gorm.DB) ContactAttemptRepository {
+	return &contactAttemptRepository{db: db}
+}
+
+func (r *contactAttemptRepository) Create(attempt *model.ContactAttempt) error {
+	return r.db.Create(attempt).Error
+}
+
+func (r *contactAttemptRepository) GetByCaseID(caseID uint) ([]model.ContactAttempt, error) {
+	var attempts []model.ContactAttempt
+	err := r.db.Where("case_id = ?", caseID).Order("attempted_at ASC").Find(&attempts).Error
+	return attempts, err
+}
+
+// PromiseToPayRepository defines all operations for PromiseToPay entity
+type PromiseToPayRepository interface {
+	Create(promise *model.PromiseToPay) error
+	GetByCaseID(caseID uint) ([]model.PromiseToPay, error)
+	GetByStatus(status string) ([]model.PromiseToPay, error)
+	Update(promise *model.PromiseToPay) error
+}
+
+// promiseToPayRepository is the implementation of PromiseToPayRepository
+type promiseToPayRepository struct {
+	db *gorm.DB
+}
+
+// NewPromiseToPayRepository creates a new instance of PromiseToPayRepository
+func NewPromiseToPayRepository(db *gorm.DB) PromiseToPayRepository {
+	return &promiseToPayRepository{db: db}
+}
+
+func (r *promiseToPayRepository) Create(promise *model.PromiseToPay) error {
+	return r.db.Create(promise).Error
+}
+
+func (r *promiseToPayRepository) GetByCaseID(caseID uint) ([]model.PromiseToPay, error) {
+	var promises []model.PromiseToPay
+	err := r.db.Where("case_id = ?", caseID).Order("created_at ASC").Find(&promises).Error
+	return promises, err
+}
+
+func (r *promiseToPayRepository) GetByStatus(status string) ([]model.PromiseToPay, error) {
+	var promises []model.PromiseToPay
+	err := r.db.Where("status = ?", status).Order("promised_date ASC").Find(&promises).Error
+	return promises, err
+}
+
+func (r *promiseToPayRepository) Update(promise *model.PromiseToPay) error {
+	return r.db.Save(promise).Error
+}
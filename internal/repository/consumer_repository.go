@@ -1,9 +1,12 @@
 package repository
 
 import (
+	"time"
+
 	"main/internal/model"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ConsumerRepository defines all operations for Consumer entity
@@ -12,6 +15,7 @@ type ConsumerRepository interface {
 	GetByID(id uint) (*model.Consumer, error)
 	GetByNIK(nik string) (*model.Consumer, error)
 	GetAll() ([]model.Consumer, error)
+	GetByKYCStatus(status string) ([]model.Consumer, error)
 	Update(consumer *model.Consumer) error
 	Delete(id uint) error
 }
@@ -21,8 +25,10 @@ type ConsumerLimitRepository interface {
 	Create(limit *model.ConsumerLimit) error
 	GetByID(id uint) (*model.ConsumerLimit, error)
 	GetByConsumerAndTenor(consumerID uint, tenor int) (*model.ConsumerLimit, error)
+	GetByConsumerAndTenorForUpdate(consumerID uint, tenor int) (*model.ConsumerLimit, error)
 	GetByConsumerID(consumerID uint) ([]model.ConsumerLimit, error)
 	Update(limit *model.ConsumerLimit) error
+	UpdateUsage(limit *model.ConsumerLimit) error
 	Delete(id uint) error
 }
 
@@ -30,9 +36,16 @@ type ConsumerLimitRepository interface {
 type TransactionRepository interface {
 	Create(transaction *model.Transaction) error
 	GetByID(id uint) (*model.Transaction, error)
+	GetByIDForUpdate(id uint) (*model.Transaction, error)
 	GetByContractNumber(contractNumber string) (*model.Transaction, error)
 	GetByConsumerID(consumerID uint) ([]model.Transaction, error)
+	GetByConsumerSince(consumerID uint, since time.Time) ([]model.Transaction, error)
+	GetByMerchantSince(merchantID uint, since time.Time) ([]model.Transaction, error)
+	GetByStatus(status string) ([]model.Transaction, error)
+	GetMerchantActivatedBefore(before time.Time) ([]model.Transaction, error)
+	CountByConsumerID(consumerID uint) (int64, error)
 	Update(transaction *model.Transaction) error
+	UpdateAging(transaction *model.Transaction) error
 	Delete(id uint) error
 }
 
@@ -74,6 +87,12 @@ func (r *consumerRepository) GetAll() ([]model.Consumer, error) {
 	return consumers, err
 }
 
+func (r *consumerRepository) GetByKYCStatus(status string) ([]model.Consumer, error) {
+	var consumers []model.Consumer
+	err := r.db.Where("kyc_status = ?", status).Order("created_at ASC").Find(&consumers).Error
+	return consumers, err
+}
+
 func (r *consumerRepository) Update(consumer *model.Consumer) error {
 	return r.db.Save(consumer).Error
 }
@@ -108,6 +127,14 @@ func (r *consumerLimitRepository) GetByConsumerAndTenor(consumerID uint, tenor i
 	return &limit, err
 }
 
+// GetByConsumerAndTenorForUpdate returns the limit and locks its row until the surrounding database transaction ends
+func (r *consumerLimitRepository) GetByConsumerAndTenorForUpdate(consumerID uint, tenor int) (*model.ConsumerLimit, error) {
+	var limit model.ConsumerLimit
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("consumer_id = ? AND tenor = ?", consumerID, tenor).First(&limit).Error
+	return &limit, err
+}
+
 func (r *consumerLimitRepository) GetByConsumerID(consumerID uint) ([]model.ConsumerLimit, error) {
 	var limits []model.ConsumerLimit
 	err := r.db.Where("consumer_id = ?", consumerID).Find(&limits).Error
@@ -118,6 +145,11 @@ func (r *consumerLimitRepository) Update(limit *model.ConsumerLimit) error {
 	return r.db.Save(limit).Error
 }
 
+// UpdateUsage writes only the used and held amounts, leaving the limit amount to the limit administration
+func (r *consumerLimitRepository) UpdateUsage(limit *model.ConsumerLimit) error {
+	return r.db.Model(limit).Select("used_amount", "held_amount", "updated_at").Updates(limit).Error
+}
+
 func (r *consumerLimitRepository) Delete(id uint) error {
 	return r.db.Delete(&model.ConsumerLimit{}, id).Error
 }
@@ -145,6 +177,16 @@ func (r *transactionRepository) GetByID(id uint) (*model.Transaction, error) {
 	return &transaction, nil
 }
 
+// GetByIDForUpdate returns the transaction and locks its row until the surrounding database transaction ends
+func (r *transactionRepository) GetByIDForUpdate(id uint) (*model.Transaction, error) {
+	var transaction model.Transaction
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&transaction).Error
+	if err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
+
 func (r *transactionRepository) GetByContractNumber(contractNumber string) (*model.Transaction, error) {
 	var transaction model.Transaction
 	err := r.db.Where("contract_number = ?", contractNumber).First(&transaction).Error
@@ -160,10 +202,49 @@ func (r *transactionRepository) GetByConsumerID(consumerID uint) ([]model.Transa
 	return transactions, err
 }
 
+func (r *transactionRepository) GetByConsumerSince(consumerID uint, since time.Time) ([]model.Transaction, error) {
+	var transactions []model.Transaction
+	err := r.db.Where("consumer_id = ? AND created_at >= ?", consumerID, since).Find(&transactions).Error
+	return transactions, err
+}
+
+func (r *transactionRepository) GetByMerchantSince(merchantID uint, since time.Time) ([]model.Transaction, error) {
+	var transactions []model.Transaction
+	err := r.db.Where("merchant_id = ? AND created_at >= ?", merchantID, since).Find(&transactions).Error
+	return transactions, err
+}
+
+func (r *transactionRepository) GetByStatus(status string) ([]model.Transaction, error) {
+	var transactions []model.Transaction
+	err := r.db.Where("status = ?", status).Order("created_at ASC").Find(&transactions).Error
+	return transactions, err
+}
+
+// GetMerchantActivatedBefore returns the merchant contracts activated before the given time, whatever their status now
+func (r *transactionRepository) GetMerchantActivatedBefore(before time.Time) ([]model.Transaction, error) {
+	var transactions []model.Transaction
+	err := r.db.Where("merchant_id <> 0 AND activated_at IS NOT NULL AND activated_at < ?", before).
+		Order("activated_at ASC").Find(&transactions).Error
+	return transactions, err
+}
+
+func (r *transactionRepository) CountByConsumerID(consumerID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Transaction{}).Where("consumer_id = ?", consumerID).Count(&count).Error
+	return count, err
+}
+
 func (r *transactionRepository) Update(transaction *model.Transaction) error {
 	return r.db.Save(transaction).Error
 }
 
+// UpdateAging writes only the columns owned by the daily aging job, leaving the rest of the row to other writers
+func (r *transactionRepository) UpdateAging(transaction *model.Transaction) error {
+	return r.db.Model(transaction).
+		Select("days_past_due", "collectibility", "late_fee_amount", "status", "updated_at").
+		Updates(transaction).Error
+}
+
 func (r *transactionRepository) Delete(id uint) error {
 	return r.db.Delete(&model.Transaction{}, id).Error
 }
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"main/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// ContractTemplateRepository defines all operations for ContractTemplate entity
+type ContractTemplateRepository interface {
+	Create(template *model.ContractTemplate) error
+	GetByID(id uint) (*model.ContractTemplate, error)
+	GetActive(code string) (*model.ContractTemplate, error)
+	GetByCode(code string) ([]model.ContractTemplate, error)
+	Update(template *model.ContractTemplate) error
+}
+
+// contractTemplateRepository is the implementation of ContractTemplateRepository
+type contractTemplateRepository struct {
+	db *gorm.DB
+}
+
+// NewContractTemplateRepository creates a new instance of ContractTemplateRepository
+func NewContractTemplateRepository(db *gorm.DB) ContractTemplateRepository {
+	return &contractTemplateRepository{db: db}
+}
+
+func (r *contractTemplateRepository) Create(template *model.ContractTemplate) error {
+	return r.db.Create(template).Error
+}
+
+func (r *contractTemplateRepository) GetByID(id uint) (*model.ContractTemplate, error) {
+	var template model.ContractTemplate
+	err := r.db.First(&template, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *contractTemplateRepository) GetActive(code string) (*model.ContractTemplate, error) {
+	var template model.ContractTemplate
+	err := r.db.Where("code = ? AND active = ?", code, true).Order("version DESC").First(&template).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// GetByCode lists every version of a template, newest first
+func (r *contractTemplateRepository) GetByCode(code string) ([]model.ContractTemplate, error) {
+	var templates []model.ContractTemplate
+	err := r.db.Where("code = ?", code).Order("version DESC").Find(&templates).Error
+	return templates, err
+}
+
+func (r *contractTemplateRepository) Update(template *model.ContractTemplate) error {
+	return r.db.Save(template).Error
+}
+
+// ContractDocumentRepository defines all operations for ContractDocument entity
+type ContractDocumentRepository interface {
+	Create(document *model.ContractDocument) error
+	GetByID(id uint) (*model.ContractDocument, error)
+	GetLatest(transactionID uint) (*model.ContractDocument, error)
+	GetByTransactionID(transactionID uint) ([]model.ContractDocument, error)
+	Update(document *model.ContractDocument) error
+}
+
+// contractDocumentRepository is the implementation of ContractDocumentRepository
+type contractDocumentRepository struct {
+	db *gorm.DB
+}
+
+// NewContractDocumentRepository creates a new instance of ContractDocumentRepository
+func NewContractDocumentRepository(db *gorm.DB) ContractDocumentRepository {
+	return &contractDocumentRepository{db: db}
+}
+
+func (r *contractDocumentRepository) Create(document *model.ContractDocument) error {
+	return r.db.Create(document).Error
+}
+
+func (r *contractDocumentRepository) GetByID(id uint) (*model.ContractDocument, error) {
+	var document model.ContractDocument
+	err := r.db.First(&document, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &document, nil
+}
+
+// GetLatest returns the newest document of a contract with its content
+func (r *contractDocumentRepository) GetLatest(transactionID uint) (*model.ContractDocument, error) {
+	var document model.ContractDocument
+	err := r.db.Where("transaction_id = ?", transactionID).Order("id DESC").First(&document).Error
+	if err != nil {
+		return nil, err
+	}
+	return &document, nil
+}
+
+// GetByTransactionID lists the documents of a contract without their content
+func (r *contractDocumentRepository) GetByTransactionID(transactionID uint) ([]model.ContractDocument, error) {
+	var documents []model.ContractDocument
+	err := r.db.Omit("content").Where("transaction_id = ?", transactionID).Order("id DESC").Find(&documents).Error
+	return documents, err
+}
+
+func (r *contractDocumentRepository) Update(document *model.ContractDocument) error {
+	return r.db.Save(document).Error
+}
+
+// ContractSignatureRepository defines all operations for ContractSignature entity
+type ContractSignatureRepository interface {
+	Create(signature *model.ContractSignature) error
+	GetPending(transactionID uint) (*model.ContractSignature, error)
+	GetByTransactionID(transactionID uint) ([]model.ContractSignature, error)
+	Update(signature *model.ContractSignature) error
+}
+
+// contractSignatureRepository is the implementation of ContractSignatureRepository
+type contractSignatureRepository struct {
+	db *gorm.DB
+}
+
+// NewContractSignatureRepository creates a new instance of ContractSignatureRepository
+func NewContractSignatureRepository(db *gorm.DB) ContractSignatureRepository {
+	return &contractSignatureRepository{db: db}
+}
+
+func (r *contractSignatureRepository) Create(signature *model.ContractSignature) error {
+	return r.db.Create(signature).Error
+}
+
+func (r *contractSignatureRepository) GetPending(transactionID uint) (*model.ContractSignature, error) {
+	var signature model.ContractSignature
+	err := r.db.Where("transaction_id = ? AND status = ?", transactionID, model.SignatureStatusPending).
+		Order("id DESC").First(&signature).Error
+	if err != nil {
+		return nil, err
+	}
+	return &signature, nil
+}
+
+func (r *contractSignatureRepository) GetByTransactionID(transactionID uint) ([]model.ContractSignature, error) {
+	var signatures []model.ContractSignature
+	err := r.db.Where("transaction_id = ?", transactionID).Order("id DESC").Find(&signatures).Error
+	return signatures, err
+}
+
+func (r *contractSignatureRepository) Update(signature *model.ContractSignature) error {
+	return r.db.Save(signature).Error
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"main/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// FraudAssessmentRepository defines all operations for FraudAssessment entity
+type FraudAssessmentRepository interface {
+	Create(assessment *model.FraudAssessment) error
+	GetByTransactionID(transactionID uint) (*model.FraudAssessment, error)
+	Update(assessment *model.FraudAssessment) error
+}
+
+// fraudAssessmentRepository is the implementation of FraudAssessmentRepository
+type fraudAssessmentRepository struct {
+	db *gorm.DB
+}
+
+// NewFraudAssessmentRepository creates a new instance of FraudAssessmentRepository
+func NewFraudAssessmentRepository(db *gorm.DB) FraudAssessmentRepository {
+	return &fraudAssessmentRepository{db: db}
+}
+
+func (r *fraudAssessmentRepository) Create(assessment *model.FraudAssessment) error {
+	return r.db.Create(assessment).Error
+}
+
+func (r *fraudAssessmentRepository) GetByTransactionID(transactionID uint) (*model.FraudAssessment, error) {
+	var assessment model.FraudAssessment
+	err := r.db.Where("transaction_id = ?", transactionID).Order("id DESC").First(&assessment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &assessment, nil
+}
+
+func (r *fraudAssessmentRepository) Update(assessment *model.FraudAssessment) error {
+	return r.db.Save(assessment).Error
+}
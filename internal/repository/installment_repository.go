@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"main/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InstallmentRepository defines all operations for Installment entity
+type InstallmentRepository interface {
+	CreateBatch(installments []model.Installment) error
+	GetByTransactionID(transactionID uint) ([]model.Installment, error)
+	GetByTransactionIDForUpdate(transactionID uint) ([]model.Installment, error)
+	Update(installment *model.Installment) error
+	UpdateAging(installment *model.Installment) error
+}
+
+// installmentRepository is the implementation of InstallmentRepository
+type installmentRepository struct {
+	db *gorm.DB
+}
+
+// NewInstallmentRepository creates a new instance of InstallmentRepository
+func NewInstallmentRepository(db *gorm.DB) InstallmentRepository {
+	return &installmentRepository{db: db}
+}
+
+func (r *installmentRepository) CreateBatch(installments []model.Installment) error {
+	return r.db.Create(&installments).Error
+}
+
+func (r *installmentRepository) GetByTransactionID(transactionID uint) ([]model.Installment, error) {
+	var installments []model.Installment
+	err := r.db.Where("transaction_id = ?", transactionID).Order("sequence ASC").Find(&installments).Error
+	return installments, err
+}
+
+// GetByTransactionIDForUpdate returns the schedule and locks its rows until the surrounding database transaction ends
+func (r *installmentRepository) GetByTransactionIDForUpdate(transactionID uint) ([]model.Installment, error) {
+	var installments []model.Installment
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("transaction_id = ?", transactionID).Order("sequence ASC").Find(&installments).Error
+	return installments, err
+}
+
+func (r *installmentRepository) Update(installment *model.Installment) error {
+	return r.db.Save(installment).Error
+}
+
+// UpdateAging writes only the DPD and late fee of the installment
+func (r *installmentRepository) UpdateAging(installment *model.Installment) error {
+	return r.db.Model(installment).Select("days_past_due", "late_fee", "updated_at").Updates(installment).Error
+}
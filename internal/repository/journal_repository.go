@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"time"
+
+	"main/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// JournalRepository defines all operations for JournalEntry entity
+type JournalRepository interface {
+	Create(entry *model.JournalEntry) error
+	GetByReference(reference string) (*model.JournalEntry, error)
+	GetByPostingDate(date time.Time) ([]model.JournalEntry, error)
+	GetUntil(date time.Time) ([]model.JournalEntry, error)
+	GetByTransactionID(transactionID uint) ([]model.JournalEntry, error)
+}
+
+// journalRepository is the implementation of JournalRepository
+type journalRepository struct {
+	db *gorm.DB
+}
+
+// NewJournalRepository creates a new instance of JournalRepository
+func NewJournalRepository(db *gorm.DB) JournalRepository {
+	return &journalRepository{db: db}
+}
+
+// Create stores the entry together with its lines in one insert
+func (r *journalRepository) Create(entry *model.JournalEntry) error {
+	return r.db.Create(entry).Error
+}
+
+func (r *journalRepository) GetByReference(reference string) (*model.JournalEntry, error) {
+	var entry model.JournalEntry
+	err := r.db.Preload("Lines").Where("reference = ?", reference).First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *journalRepository) GetByPostingDate(date time.Time) ([]model.JournalEntry, error) {
+	var entries []model.JournalEntry
+	err := r.db.Preload("Lines").Where("posting_date = ?", date.Format("2006-01-02")).Order("id ASC").Find(&entries).Error
+	return entries, err
+}
+
+// GetUntil returns every entry posted on or before the date
+func (r *journalRepository) GetUntil(date time.Time) ([]model.JournalEntry, error) {
+	var entries []model.JournalEntry
+	err := r.db.Preload("Lines").Where("posting_date <= ?", date.Format("2006-01-02")).Order("id ASC").Find(&entries).Error
+	return entries, err
+}
+
+func (r *journalRepository) GetByTransactionID(transactionID uint) ([]model.JournalEntry, error) {
+	var entries []model.JournalEntry
+	err := r.db.Preload("Lines").Where("transaction_id = ?", transactionID).Order("id ASC").Find(&entries).Error
+	return entries, err
+}
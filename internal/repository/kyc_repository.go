@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"main/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// KYCVerificationRepository defines all operations for KYCVerification entity
+type KYCVerificationRepository interface {
+	Create(verification *model.KYCVerification) error
+	GetLatestByConsumerID(consumerID uint) (*model.KYCVerification, error)
+	GetByConsumerID(consumerID uint) ([]model.KYCVerification, error)
+}
+
+// kycVerificationRepository is the implementation of KYCVerificationRepository
+type kycVerificationRepository struct {
+	db *gorm.DB
+}
+
+// NewKYCVerificationRepository creates a new instance of KYCVerificationRepository
+func NewKYCVerificationRepository(db *gorm.DB) KYCVerificationRepository {
+	return &kycVerificationRepository{db: db}
+}
+
+func (r *kycVerificationRepository) Create(verification *model.KYCVerification) error {
+	return r.db.Create(verification).Error
+}
+
+func (r *kycVerificationRepository) GetLatestByConsumerID(consumerID uint) (*model.KYCVerification, error) {
+	var verification model.KYCVerification
+	err := r.db.Where("consumer_id = ?", consumerID).Order("id DESC").First(&verification).Error
+	if err != nil {
+		return nil, err
+	}
+	return &verification, nil
+}
+
+func (r *kycVerificationRepository) GetByConsumerID(consumerID uint) ([]model.KYCVerification, error) {
+	var verifications []model.KYCVerification
+	err := r.db.Where("consumer_id = ?", consumerID).Order("id ASC").Find(&verifications).Error
+	return verifications, err
+}
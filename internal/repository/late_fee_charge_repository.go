@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"main/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// LateFeeChargeRepository defines all operations for LateFeeCharge entity
+type LateFeeChargeRepository interface {
+	Create(charge *model.LateFeeCharge) error
+	GetByTransactionID(transactionID uint) ([]model.LateFeeCharge, error)
+	Update(charge *model.LateFeeCharge) error
+}
+
+// lateFeeChargeRepository is the implementation of LateFeeChargeRepository
+type lateFeeChargeRepository struct {
+	db *gorm.DB
+}
+
+// NewLateFeeChargeRepository creates a new instance of LateFeeChargeRepository
+func NewLateFeeChargeRepository(db *gorm.DB) LateFeeChargeRepository {
+	return &lateFeeChargeRepository{db: db}
+}
+
+func (r *lateFeeChargeRepository) Create(charge *model.LateFeeCharge) error {
+	return r.db.Create(charge).Error
+}
+
+func (r *lateFeeChargeRepository) GetByTransactionID(transactionID uint) ([]model.LateFeeCharge, error) {
+	var charges []model.LateFeeCharge
+	err := r.db.Where("transaction_id = ?", transactionID).Order("business_date ASC, id ASC").Find(&charges).Error
+	return charges, err
+}
+
+func (r *lateFeeChargeRepository) Update(charge *model.LateFeeCharge) error {
+	return r.db.Save(charge).Error
+}
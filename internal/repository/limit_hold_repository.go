@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"time"
+
+	"main/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// LimitHoldRepository defines all operations for LimitHold entity
+type LimitHoldRepository interface {
+	Create(hold *model.LimitHold) error
+	GetByID(id uint) (*model.LimitHold, error)
+	GetExpired(now time.Time) ([]model.LimitHold, error)
+	Update(hold *model.LimitHold) error
+}
+
+// limitHoldRepository is the implementation of LimitHoldRepository
+type limitHoldRepository struct {
+	db *gorm.DB
+}
+
+// NewLimitHoldRepository creates a new instance of LimitHoldRepository
+func NewLimitHoldRepository(db *gorm.DB) LimitHoldRepository {
+	return &limitHoldRepository{db: db}
+}
+
+func (r *limitHoldRepository) Create(hold *model.LimitHold) error {
+	return r.db.Create(hold).Error
+}
+
+func (r *limitHoldRepository) GetByID(id uint) (*model.LimitHold, error) {
+	var hold model.LimitHold
+	err := r.db.First(&hold, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
+
+// GetExpired returns authorized holds whose expiry has passed
+func (r *limitHoldRepository) GetExpired(now time.Time) ([]model.LimitHold, error) {
+	var holds []model.LimitHold
+	err := r.db.Where("status = ? AND expires_at <= ?", model.LimitHoldStatusAuthorized, now).Find(&holds).Error
+	return holds, err
+}
+
+func (r *limitHoldRepository) Update(hold *model.LimitHold) error {
+	return r.db.Save(hold).Error
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"main/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// MerchantRepository defines all operations for Merchant entity
+type MerchantRepository interface {
+	Create(merchant *model.Merchant) error
+	GetByID(id uint) (*model.Merchant, error)
+	GetByCode(code string) (*model.Merchant, error)
+	GetAll() ([]model.Merchant, error)
+	Update(merchant *model.Merchant) error
+	CreateBranch(branch *model.MerchantBranch) error
+}
+
+// merchantRepository is the implementation of MerchantRepository
+type merchantRepository struct {
+	db *gorm.DB
+}
+
+// NewMerchantRepository creates a new instance of MerchantRepository
+func NewMerchantRepository(db *gorm.DB) MerchantRepository {
+	return &merchantRepository{db: db}
+}
+
+func (r *merchantRepository) Create(merchant *model.Merchant) error {
+	return r.db.Create(merchant).Error
+}
+
+func (r *merchantRepository) GetByID(id uint) (*model.Merchant, error) {
+	var merchant model.Merchant
+	err := r.db.Preload("Branches").First(&merchant, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &merchant, nil
+}
+
+func (r *merchantRepository) GetByCode(code string) (*model.Merchant, error) {
+	var merchant model.Merchant
+	err := r.db.Preload("Branches").Where("code = ?", code).First(&merchant).Error
+	if err != nil {
+		return nil, err
+	}
+	return &merchant, nil
+}
+
+func (r *merchantRepository) GetAll() ([]model.Merchant, error) {
+	var merchants []model.Merchant
+	err := r.db.Order("code ASC").Find(&merchants).Error
+	return merchants, err
+}
+
+// Update saves the merchant fields only; branches are managed separately
+func (r *merchantRepository) Update(merchant *model.Merchant) error {
+	return r.db.Omit("Branches").Save(merchant).Error
+}
+
+func (r *merchantRepository) CreateBranch(branch *model.MerchantBranch) error {
+	return r.db.Create(branch).Error
+}
+
+// MerchantAPIKeyRepository defines all operations for MerchantAPIKey entity
+type MerchantAPIKeyRepository interface {
+	Create(key *model.MerchantAPIKey) error
+	GetByID(id uint) (*model.MerchantAPIKey, error)
+	GetByHash(hash string) (*model.MerchantAPIKey, error)
+	GetByMerchantID(merchantID uint) ([]model.MerchantAPIKey, error)
+	Update(key *model.MerchantAPIKey) error
+}
+
+// merchantAPIKeyRepository is the implementation of MerchantAPIKeyRepository
+type merchantAPIKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewMerchantAPIKeyRepository creates a new instance of MerchantAPIKeyRepository
+func NewMerchantAPIKeyRepository(db *gorm.DB) MerchantAPIKeyRepository {
+	return &merchantAPIKeyRepository{db: db}
+}
+
+func (r *merchantAPIKeyRepository) Create(key *model.MerchantAPIKey) error {
+	return r.db.Create(key).Error
+}
+
+func (r *merchantAPIKeyRepository) GetByID(id uint) (*model.MerchantAPIKey, error) {
+	var key model.MerchantAPIKey
+	err := r.db.First(&key, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *merchantAPIKeyRepository) GetByHash(hash string) (*model.MerchantAPIKey, error) {
+	var key model.MerchantAPIKey
+	err := r.db.Where("key_hash = ?", hash).First(&key).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *merchantAPIKeyRepository) GetByMerchantID(merchantID uint) ([]model.MerchantAPIKey, error) {
+	var keys []model.MerchantAPIKey
+	err := r.db.Where("merchant_id = ?", merchantID).Order("created_at DESC").Find(&keys).Error
+	return keys, err
+}
+
+func (r *merchantAPIKeyRepository) Update(key *model.MerchantAPIKey) error {
+	return r.db.Save(key).Error
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"main/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// NotificationPreferenceRepository defines all operations for NotificationPreference entity
+type NotificationPreferenceRepository interface {
+	Create(preference *model.NotificationPreference) error
+	GetByConsumerID(consumerID uint) (*model.NotificationPreference, error)
+	Update(preference *model.NotificationPreference) error
+}
+
+// notificationPreferenceRepository is the implementation of NotificationPreferenceRepository
+type notificationPreferenceRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationPreferenceRepository creates a new instance of NotificationPreferenceRepository
+func NewNotificationPreferenceRepository(db *gorm.DB) NotificationPreferenceRepository {
+	return &notificationPreferenceRepository{db: db}
+}
+
+func (r *notificationPreferenceRepository) Create(preference *model.NotificationPreference) error {
+	return r.db.Create(preference).Error
+}
+
+func (r *notificationPreferenceRepository) GetByConsumerID(consumerID uint) (*model.NotificationPreference, error) {
+	var preference model.NotificationPreference
+	err := r.db.Where("consumer_id = ?", consumerID).First(&preference).Error
+	if err != nil {
+		return nil, err
+	}
+	return &preference, nil
+}
+
+func (r *notificationPreferenceRepository) Update(preference *model.NotificationPreference) error {
+	return r.db.Save(preference).Error
+}
+
+// NotificationLogRepository defines all operations for NotificationLog entity
+type NotificationLogRepository interface {
+	Create(entry *model.NotificationLog) error
+	GetByConsumerID(consumerID uint) ([]model.NotificationLog, error)
+	HasSent(dedupKey string) (bool, error)
+}
+
+// notificationLogRepository is the implementation of NotificationLogRepository
+type notificationLogRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationLogRepository creates a new instance of NotificationLogRepository
+func NewNotificationLogRepository(db *gorm.DB) NotificationLogRepository {
+	return &notificationLogRepository{db: db}
+}
+
+func (r *notificationLogRepository) Create(entry *model.NotificationLog) error {
+	return r.db.Create(entry).Error
+}
+
+func (r *notificationLogRepository) GetByConsumerID(consumerID uint) ([]model.NotificationLog, error) {
+	var entries []model.NotificationLog
+	err := r.db.Where("consumer_id = ?", consumerID).Order("created_at DESC").Find(&entries).Error
+	return entries, err
+}
+
+// HasSent reports whether a notification with the key was already delivered
+func (r *notificationLogRepository) HasSent(dedupKey string) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.NotificationLog{}).
+		Where("dedup_key = ? AND status = ?", dedupKey, model.NotificationStatusSent).
+		Count(&count).Error
+	return count > 0, err
+}
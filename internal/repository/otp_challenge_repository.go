@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"main/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OTPChallengeRepository defines all operations for OTPChallenge entity
+type OTPChallengeRepository interface {
+	Create(challenge *model.OTPChallenge) error
+	GetByID(id uint) (*model.OTPChallenge, error)
+	GetByIDForUpdate(id uint) (*model.OTPChallenge, error)
+	Update(challenge *model.OTPChallenge) error
+}
+
+// otpChallengeRepository is the implementation of OTPChallengeRepository
+type otpChallengeRepository struct {
+	db *gorm.DB
+}
+
+// NewOTPChallengeRepository creates a new instance of OTPChallengeRepository
+func NewOTPChallengeRepository(db *gorm.DB) OTPChallengeRepository {
+	return &otpChallengeRepository{db: db}
+}
+
+func (r *otpChallengeRepository) Create(challenge *model.OTPChallenge) error {
+	return r.db.Create(challenge).Error
+}
+
+func (r *otpChallengeRepository) GetByID(id uint) (*model.OTPChallenge, error) {
+	var challenge model.OTPChallenge
+	err := r.db.First(&challenge, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+// GetByIDForUpdate returns the challenge and locks its row until the surrounding database transaction ends
+func (r *otpChallengeRepository) GetByIDForUpdate(id uint) (*model.OTPChallenge, error) {
+	var challenge model.OTPChallenge
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&challenge, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+func (r *otpChallengeRepository) Update(challenge *model.OTPChallenge) error {
+	return r.db.Save(challenge).Error
+}
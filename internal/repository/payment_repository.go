@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"main/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// PaymentRepository defines all operations for Payment entity
+type PaymentRepository interface {
+	Create(payment *model.Payment) error
+	GetByTransactionID(transactionID uint) ([]model.Payment, error)
+	GetByReference(reference string) (*model.Payment, error)
+}
+
+// paymentRepository is the implementation of PaymentRepository
+type paymentRepository struct {
+	db *gorm.DB
+}
+
+// NewPaymentRepository creates a new instance of PaymentRepository
+func NewPaymentRepository(db *gorm.DB) PaymentRepository {
+	return &paymentRepository{db: db}
+}
+
+func (r *paymentRepository) Create(payment *model.Payment) error {
+	return r.db.Create(payment).Error
+}
+
+func (r *paymentRepository) GetByTransactionID(transactionID uint) ([]model.Payment, error) {
+	var payments []model.Payment
+	err := r.db.Where("transaction_id = ?", transactionID).Order("paid_at ASC").Find(&payments).Error
+	return payments, err
+}
+
+func (r *paymentRepository) GetByReference(reference string) (*model.Payment, error) {
+	var payment model.Payment
+	err := r.db.Where("reference = ?", reference).First(&payment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"main/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// ProductRepository defines all operations for Product entity
+type ProductRepository interface {
+	Create(product *model.Product) error
+	GetByCode(code string) (*model.Product, error)
+	GetAll() ([]model.Product, error)
+	Update(product *model.Product) error
+	CreatePromo(promo *model.ProductPromo) error
+}
+
+// productRepository is the implementation of ProductRepository
+type productRepository struct {
+	db *gorm.DB
+}
+
+// NewProductRepository creates a new instance of ProductRepository
+func NewProductRepository(db *gorm.DB) ProductRepository {
+	return &productRepository{db: db}
+}
+
+// Create stores the product together with its tenor rates
+func (r *productRepository) Create(product *model.Product) error {
+	return r.db.Create(product).Error
+}
+
+func (r *productRepository) GetByCode(code string) (*model.Product, error) {
+	var product model.Product
+	err := r.db.Preload("Rates").Preload("Promos").Where("code = ?", code).First(&product).Error
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (r *productRepository) GetAll() ([]model.Product, error) {
+	var products []model.Product
+	err := r.db.Preload("Rates").Preload("Promos").Order("code ASC").Find(&products).Error
+	return products, err
+}
+
+// Update saves the product fields only; rates and promos are managed separately
+func (r *productRepository) Update(product *model.Product) error {
+	return r.db.Omit("Rates", "Promos").Save(product).Error
+}
+
+func (r *productRepository) CreatePromo(promo *model.ProductPromo) error {
+	return r.db.Create(promo).Error
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"main/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// RestructuringRepository defines all operations for Restructuring entity
+type RestructuringRepository interface {
+	Create(restructuring *model.Restructuring) error
+	GetByID(id uint) (*model.Restructuring, error)
+	GetByTransactionID(transactionID uint) ([]model.Restructuring, error)
+	GetByStatus(status string) ([]model.Restructuring, error)
+	Update(restructuring *model.Restructuring) error
+}
+
+// restructuringRepository is the implementation of RestructuringRepository
+type restructuringRepository struct {
+	db *gorm.DB
+}
+
+// NewRestructuringRepository creates a new instance of RestructuringRepository
+func NewRestructuringRepository(db *gorm.DB) RestructuringRepository {
+	return &restructuringRepository{db: db}
+}
+
+func (r *restructuringRepository) Create(restructuring *model.Restructuring) error {
+	return r.db.Create(restructuring).Error
+}
+
+func (r *restructuringRepository) GetByID(id uint) (*model.Restructuring, error) {
+	var restructuring model.Restructuring
+	err := r.db.First(&restructuring, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &restructuring, nil
+}
+
+func (r *restructuringRepository) GetByTransactionID(transactionID uint) ([]model.Restructuring, error) {
+	var restructurings []model.Restructuring
+	err := r.db.Where("transaction_id = ?", transactionID).Order("created_at ASC").Find(&restructurings).Error
+	return restructurings, err
+}
+
+func (r *restructuringRepository) GetByStatus(status string) ([]model.Restructuring, error) {
+	var restructurings []model.Restructuring
+	err := r.db.Where("status = ?", status).Order("created_at ASC").Find(&restructurings).Error
+	return restructurings, err
+}
+
+func (r *restructuringRepository) Update(restructuring *model.Restructuring) error {
+	return r.db.Save(restructuring).Error
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"time"
+
+	"main/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// SettlementRepository defines all operations for SettlementBatch entity
+type SettlementRepository interface {
+	Create(batch *model.SettlementBatch) error
+	GetByID(id uint) (*model.SettlementBatch, error)
+	GetByBatchNumber(batchNumber string) (*model.SettlementBatch, error)
+	GetByBusinessDate(date time.Time) ([]model.SettlementBatch, error)
+	GetItemsByTransactionIDs(transactionIDs []uint) ([]model.SettlementItem, error)
+	GetCarriedForward(merchantID uint) ([]model.SettlementBatch, error)
+	Update(batch *model.SettlementBatch) error
+}
+
+// settlementRepository is the implementation of SettlementRepository
+type settlementRepository struct {
+	db *gorm.DB
+}
+
+// NewSettlementRepository creates a new instance of SettlementRepository
+func NewSettlementRepository(db *gorm.DB) SettlementRepository {
+	return &settlementRepository{db: db}
+}
+
+// Create stores the batch together with its items
+func (r *settlementRepository) Create(batch *model.SettlementBatch) error {
+	return r.db.Create(batch).Error
+}
+
+func (r *settlementRepository) GetByID(id uint) (*model.SettlementBatch, error) {
+	var batch model.SettlementBatch
+	err := r.db.Preload("Items").First(&batch, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+func (r *settlementRepository) GetByBatchNumber(batchNumber string) (*model.SettlementBatch, error) {
+	var batch model.SettlementBatch
+	err := r.db.Where("batch_number = ?", batchNumber).First(&batch).Error
+	if err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+func (r *settlementRepository) GetByBusinessDate(date time.Time) ([]model.SettlementBatch, error) {
+	var batches []model.SettlementBatch
+	err := r.db.Where("business_date = ?", date.Format("2006-01-02")).Order("batch_number ASC").Find(&batches).Error
+	return batches, err
+}
+
+func (r *settlementRepository) GetItemsByTransactionIDs(transactionIDs []uint) ([]model.SettlementItem, error) {
+	var items []model.SettlementItem
+	if len(transactionIDs) == 0 {
+		return items, nil
+	}
+	err := r.db.Where("transaction_id IN ?", transactionIDs).Find(&items).Error
+	return items, err
+}
+
+// GetCarriedForward returns the CARRIED_FORWARD batches of the merchant whose balance no later batch has taken yet
+func (r *settlementRepository) GetCarriedForward(merchantID uint) ([]model.SettlementBatch, error) {
+	var batches []model.SettlementBatch
+	err := r.db.Where("merchant_id = ? AND status = ? AND (carried_to_batch_id IS NULL OR carried_to_batch_id = 0)",
+		merchantID, model.SettlementStatusCarriedForward).Order("id ASC").Find(&batches).Error
+	return batches, err
+}
+
+// Update saves the batch fields only; items never change after batching
+func (r *settlementRepository) Update(batch *model.SettlementBatch) error {
+	return r.db.Omit("Items").Save(batch).Error
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"main/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// StatementArchiveRepository defines all operations for StatementArchive entity
+type StatementArchiveRepository interface {
+	Create(archive *model.StatementArchive) error
+	GetByID(id uint) (*model.StatementArchive, error)
+	GetByConsumerID(consumerID uint) ([]model.StatementArchive, error)
+	GetByHash(documentHash string) (*model.StatementArchive, error)
+}
+
+// statementArchiveRepository is the implementation of StatementArchiveRepository
+type statementArchiveRepository struct {
+	db *gorm.DB
+}
+
+// NewStatementArchiveRepository creates a new instance of StatementArchiveRepository
+func NewStatementArchiveRepository(db *gorm.DB) StatementArchiveRepository {
+	return &statementArchiveRepository{db: db}
+}
+
+func (r *statementArchiveRepository) Create(archive *model.StatementArchive) error {
+	return r.db.Create(archive).Error
+}
+
+func (r *statementArchiveRepository) GetByID(id uint) (*model.StatementArchive, error) {
+	var archive model.StatementArchive
+	err := r.db.First(&archive, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &archive, nil
+}
+
+// GetByConsumerID lists the archived statements without their content
+func (r *statementArchiveRepository) GetByConsumerID(consumerID uint) ([]model.StatementArchive, error) {
+	var archives []model.StatementArchive
+	err := r.db.Omit("content").Where("consumer_id = ?", consumerID).Order("created_at DESC").Find(&archives).Error
+	return archives, err
+}
+
+func (r *statementArchiveRepository) GetByHash(documentHash string) (*model.StatementArchive, error) {
+	var archive model.StatementArchive
+	err := r.db.Omit("content").Where("document_hash = ?", documentHash).First(&archive).Error
+	if err != nil {
+		return nil, err
+	}
+	return &archive, nil
+}
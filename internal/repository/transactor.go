@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+)
+
+// Repositories groups the repositories a business event writes to, all bound to the same connection
+type Repositories struct {
+	Transactions       TransactionRepository
+	Limits             ConsumerLimitRepository
+	Consumers          ConsumerRepository
+	Installments       InstallmentRepository
+	Payments           PaymentRepository
+	LimitHolds         LimitHoldRepository
+	FraudAssessments   FraudAssessmentRepository
+	Cancellations      TransactionCancellationRepository
+	Refunds            RefundRepository
+	Settlements        SettlementRepository
+	Journal            JournalRepository
+	Accruals           InterestAccrualRepository
+	Restructurings     RestructuringRepository
+	WriteOffs          WriteOffRepository
+	Recoveries         RecoveryRepository
+	VirtualAccounts    VirtualAccountRepository
+	PaymentCallbacks   PaymentCallbackRepository
+	BankStatementLines BankStatementLineRepository
+	ContractSignatures ContractSignatureRepository
+	LateFeeCharges     LateFeeChargeRepository
+	OTPChallenges      OTPChallengeRepository
+}
+
+// NewRepositories creates the repositories of a connection or of an open database transaction
+func NewRepositories(db *gorm.DB) *Repositories {
+	return &Repositories{
+		Transactions:       NewTransactionRepository(db),
+		Limits:             NewConsumerLimitRepository(db),
+		Consumers:          NewConsumerRepository(db),
+		Installments:       NewInstallmentRepository(db),
+		Payments:           NewPaymentRepository(db),
+		LimitHolds:         NewLimitHoldRepository(db),
+		FraudAssessments:   NewFraudAssessmentRepository(db),
+		Cancellations:      NewTransactionCancellationRepository(db),
+		Refunds:            NewRefundRepository(db),
+		Settlements:        NewSettlementRepository(db),
+		Journal:            NewJournalRepository(db),
+		Accruals:           NewInterestAccrualRepository(db),
+		Restructurings:     NewRestructuringRepository(db),
+		WriteOffs:          NewWriteOffRepository(db),
+		Recoveries:         NewRecoveryRepository(db),
+		VirtualAccounts:    NewVirtualAccountRepository(db),
+		PaymentCallbacks:   NewPaymentCallbackRepository(db),
+		BankStatementLines: NewBankStatementLineRepository(db),
+		ContractSignatures: NewContractSignatureRepository(db),
+		LateFeeCharges:     NewLateFeeChargeRepository(db),
+		OTPChallenges:      NewOTPChallengeRepository(db),
+	}
+}
+
+// Transactor runs a unit of work in one database transaction.
+// The repositories passed to fn write inside the transaction; fn returning an error rolls all of it back.
+type Transactor interface {
+	Transaction(fn func(tx *Repositories) error) error
+}
+
+// transactor is the implementation of Transactor
+type transactor struct {
+	db *gorm.DB
+}
+
+// NewTransactor creates a new instance of Transactor
+func NewTransactor(db *gorm.DB) Transactor {
+	return &transactor{db: db}
+}
+
+func (t *transactor) Transaction(fn func(tx *Repositories) error) error {
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		return fn(NewRepositories(tx))
+	})
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"main/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// VirtualAccountRepository defines all operations for VirtualAccount entity
+type VirtualAccountRepository interface {
+	Create(account *model.VirtualAccount) error
+	GetByNumber(vaNumber string) (*model.VirtualAccount, error)
+	GetByTransactionID(transactionID uint) ([]model.VirtualAccount, error)
+	Update(account *model.VirtualAccount) error
+}
+
+// virtualAccountRepository is the implementation of VirtualAccountRepository
+type virtualAccountRepository struct {
+	db *gorm.DB
+}
+
+// NewVirtualAccountRepository creates a new instance of VirtualAccountRepository
+func NewVirtualAccountRepository(db *gorm.DB) VirtualAccountRepository {
+	return &virtualAccountRepository{db: db}
+}
+
+func (r *virtualAccountRepository) Create(account *model.VirtualAccount) error {
+	return r.db.Create(account).Error
+}
+
+func (r *virtualAccountRepository) GetByNumber(vaNumber string) (*model.VirtualAccount, error) {
+	var account model.VirtualAccount
+	err := r.db.Where("va_number = ?", vaNumber).First(&account).Error
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *virtualAccountRepository) GetByTransactionID(transactionID uint) ([]model.VirtualAccount, error) {
+	var accounts []model.VirtualAccount
+	err := r.db.Where("transaction_id = ?", transactionID).Order("bank_code ASC").Find(&accounts).Error
+	return accounts, err
+}
+
+func (r *virtualAccountRepository) Update(account *model.VirtualAccount) error {
+	return r.db.Save(account).Error
+}
+
+// PaymentCallbackRepository defines all operations for PaymentCallback entity
+type PaymentCallbackRepository interface {
+	Create(callback *model.PaymentCallback) error
+	GetByID(id uint) (*model.PaymentCallback, error)
+	GetByExternalID(bankCode, externalID string) (*model.PaymentCallback, error)
+	GetByVANumber(vaNumber string) ([]model.PaymentCallback, error)
+	GetByStatus(status string) ([]model.PaymentCallback, error)
+	Update(callback *model.PaymentCallback) error
+}
+
+// paymentCallbackRepository is the implementation of PaymentCallbackRepository
+type paymentCallbackRepository struct {
+	db *gorm.DB
+}
+
+// NewPaymentCallbackRepository creates a new instance of PaymentCallbackRepository
+func NewPaymentCallbackRepository(db *gorm.DB) PaymentCallbackRepository {
+	return &paymentCallbackRepository{db: db}
+}
+
+func (r *paymentCallbackRepository) Create(callback *model.PaymentCallback) error {
+	return r.db.Create(callback).Error
+}
+
+func (r *paymentCallbackRepository) GetByID(id uint) (*model.PaymentCallback, error) {
+	var callback model.PaymentCallback
+	err := r.db.First(&callback, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &callback, nil
+}
+
+func (r *paymentCallbackRepository) GetByExternalID(bankCode, externalID string) (*model.PaymentCallback, error) {
+	var callback model.PaymentCallback
+	err := r.db.Where("bank_code = ? AND external_id = ?", bankCode, externalID).First(&callback).Error
+	if err != nil {
+		return nil, err
+	}
+	return &callback, nil
+}
+
+func (r *paymentCallbackRepository) GetByVANumber(vaNumber string) ([]model.PaymentCallback, error) {
+	var callbacks []model.PaymentCallback
+	err := r.db.Where("va_number = ?", vaNumber).Order("paid_at ASC").Find(&callbacks).Error
+	return callbacks, err
+}
+
+func (r *paymentCallbackRepository) GetByStatus(status string) ([]model.PaymentCallback, error) {
+	var callbacks []model.PaymentCallback
+	err := r.db.Where("status = ?", status).Order("created_at ASC").Find(&callbacks).Error
+	return callbacks, err
+}
+
+func (r *paymentCallbackRepository) Update(callback *model.PaymentCallback) error {
+	return r.db.Save(callback).Error
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"time"
+
+	"main/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// WatchlistRepository defines all operations for WatchlistEntry entity
+type WatchlistRepository interface {
+	Create(entry *model.WatchlistEntry) error
+	Update(entry *model.WatchlistEntry) error
+	GetActiveByNIK(nik string) ([]model.WatchlistEntry, error)
+	GetActiveByDateOfBirth(dateOfBirth time.Time) ([]model.WatchlistEntry, error)
+	GetBySource(source string) ([]model.WatchlistEntry, error)
+}
+
+// WatchlistHitRepository defines all operations for WatchlistHit entity
+type WatchlistHitRepository interface {
+	Create(hit *model.WatchlistHit) error
+	GetByID(id uint) (*model.WatchlistHit, error)
+	GetByStatus(status string) ([]model.WatchlistHit, error)
+	GetBySubjectNIK(nik string) ([]model.WatchlistHit, error)
+	Update(hit *model.WatchlistHit) error
+}
+
+// watchlistRepository is the implementation of WatchlistRepository
+type watchlistRepository struct {
+	db *gorm.DB
+}
+
+// NewWatchlistRepository creates a new instance of WatchlistRepository
+func NewWatchlistRepository(db *gorm.DB) WatchlistRepository {
+	return &watchlistRepository{db: db}
+}
+
+func (r *watchlistRepository) Create(entry *model.WatchlistEntry) error {
+	return r.db.Create(entry).Error
+}
+
+func (r *watchlistRepository) Update(entry *model.WatchlistEntry) error {
+	return r.db.Save(entry).Error
+}
+
+func (r *watchlistRepository) GetActiveByNIK(nik string) ([]model.WatchlistEntry, error) {
+	var entries []model.WatchlistEntry
+	err := r.db.Where("nik = ? AND active = ?", nik, true).Find(&entries).Error
+	return entries, err
+}
+
+func (r *watchlistRepository) GetActiveByDateOfBirth(dateOfBirth time.Time) ([]model.WatchlistEntry, error) {
+	var entries []model.WatchlistEntry
+	err := r.db.Where("DATE(date_of_birth) = ? AND active = ?", dateOfBirth.Format("2006-01-02"), true).Find(&entries).Error
+	return entries, err
+}
+
+func (r *watchlistRepository) GetBySource(source string) ([]model.WatchlistEntry, error) {
+	var entries []model.WatchlistEntry
+	err := r.db.Where("source = ?", source).Find(&entries).Error
+	return entries, err
+}
+
+// watchlistHitRepository is the implementation of WatchlistHitRepository
+type watchlistHitRepository struct {
+	db *gorm.DB
+}
+
+// NewWatchlistHitRepository creates a new instance of WatchlistHitRepository
+func NewWatchlistHitRepository(db *gorm.DB) WatchlistHitRepository {
+	return &watchlistHitRepository{db: db}
+}
+
+func (r *watchlistHitRepository) Create(hit *model.WatchlistHit) error {
+	return r.db.Create(hit).Error
+}
+
+func (r *watchlistHitRepository) GetByID(id uint) (*model.WatchlistHit, error) {
+	var hit model.WatchlistHit
+	err := r.db.Where("id = ?", id).First(&hit).Error
+	if err != nil {
+		return nil, err
+	}
+	return &hit, nil
+}
+
+func (r *watchlistHitRepository) GetByStatus(status string) ([]model.WatchlistHit, error) {
+	var hits []model.WatchlistHit
+	err := r.db.Where("status = ?", status).Order("created_at ASC").Find(&hits).Error
+	return hits, err
+}
+
+func (r *watchlistHitRepository) GetBySubjectNIK(nik string) ([]model.WatchlistHit, error) {
+	var hits []model.WatchlistHit
+	err := r.db.Where("subject_nik = ?", nik).Order("created_at ASC").Find(&hits).Error
+	return hits, err
+}
+
+func (r *watchlistHitRepository) Update(hit *model.WatchlistHit) error {
+	return r.db.Save(hit).Error
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"main/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// WriteOffRepository defines all operations for WriteOff entity
+type WriteOffRepository interface {
+	Create(writeOff *model.WriteOff) error
+	GetByID(id uint) (*model.WriteOff, error)
+	GetByTransactionID(transactionID uint) ([]model.WriteOff, error)
+	GetByStatus(status string) ([]model.WriteOff, error)
+	Update(writeOff *model.WriteOff) error
+}
+
+// writeOffRepository is the implementation of WriteOffRepository
+type writeOffRepository struct {
+	db *gorm.DB
+}
+
+// NewWriteOffRepository creates a new instance of WriteOffRepository
+func NewWriteOffRepository(db *gorm.DB) WriteOffRepository {
+	return &writeOffRepository{db: db}
+}
+
+func (r *writeOffRepository) Create(writeOff *model.WriteOff) error {
+	return r.db.Create(writeOff).Error
+}
+
+func (r *writeOffRepository) GetByID(id uint) (*model.WriteOff, error) {
+	var writeOff model.WriteOff
+	err := r.db.First(&writeOff, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &writeOff, nil
+}
+
+func (r *writeOffRepository) GetByTransactionID(transactionID uint) ([]model.WriteOff, error) {
+	var writeOffs []model.WriteOff
+	err := r.db.Where("transaction_id = ?", transactionID).Order("created_at ASC").Find(&writeOffs).Error
+	return writeOffs, err
+}
+
+func (r *writeOffRepository) GetByStatus(status string) ([]model.WriteOff, error) {
+	var writeOffs []model.WriteOff
+	err := r.db.Where("status = ?", status).Order("created_at ASC").Find(&writeOffs).Error
+	return writeOffs, err
+}
+
+func (r *writeOffRepository) Update(writeOff *model.WriteOff) error {
+	return r.db.Save(writeOff).Error
+}
+
+// RecoveryRepository defines all operations for Recovery entity
+type RecoveryRepository interface {
+	Create(recovery *model.Recovery) error
+	GetByWriteOffID(writeOffID uint) ([]model.Recovery, error)
+}
+
+// recoveryRepository is the implementation of RecoveryRepository
+type recoveryRepository struct {
+	db *gorm.DB
+}
+
+// NewRecoveryRepository creates a new instance of RecoveryRepository
+func NewRecoveryRepository(db *gorm.DB) RecoveryRepository {
+	return &recoveryRepository{db: db}
+}
+
+func (r *recoveryRepository) Create(recovery *model.Recovery) error {
+	return r.db.Create(recovery).Error
+}
+
+func (r *recoveryRepository) GetByWriteOffID(writeOffID uint) ([]model.Recovery, error) {
+	var recoveries []model.Recovery
+	err := r.db.Where("write_off_id = ?", writeOffID).Order("received_at ASC").Find(&recoveries).Error
+	return recoveries, err
+}
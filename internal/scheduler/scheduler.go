@@ -0,0 +1,55 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+)
+
+// Daily runs job every day at the given local hour and minute, passing the run time.
+// A failing job is logged and retried at the next run.
+func Daily(name string, hour, minute int, job func(now time.Time) error) {
+	go func() {
+		for {
+			next := nextRun(time.Now(), hour, minute)
+			time.Sleep(time.Until(next))
+			run(name, job)
+		}
+	}()
+	log.Printf("✓ Scheduled job %s: daily at %02d:%02d\n", name, hour, minute)
+}
+
+// Every runs job at a fixed interval, passing the run time
+func Every(name string, interval time.Duration, job func(now time.Time) error) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			run(name, job)
+		}
+	}()
+	log.Printf("✓ Scheduled job %s: every %s\n", name, interval)
+}
+
+func run(name string, job func(now time.Time) error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("✗ Job %s panic: %v\n", name, r)
+		}
+	}()
+
+	started := time.Now()
+	if err := job(started); err != nil {
+		log.Printf("✗ Job %s gagal: %v\n", name, err)
+		return
+	}
+	log.Printf("✓ Job %s selesai dalam %s\n", name, time.Since(started))
+}
+
+// nextRun returns the next occurrence of hour:minute after now
+func nextRun(now time.Time, hour, minute int) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
@@ -0,0 +1,204 @@
+package settlement
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Confirmation statuses reported by the bank
+const (
+	ConfirmationSuccess = "SUCCESS"
+	ConfirmationFailed  = "FAILED"
+)
+
+// Fixed-width layout: every record is recordLength characters followed by CRLF
+const (
+	recordLength         = 128
+	accountNumberWidth   = 20
+	accountNameWidth     = 35
+	bankNameWidth        = 20
+	referenceWidth       = 30
+	detailAmountWidth    = 15
+	totalAmountWidth     = 18
+	recordCountWidth     = 6
+	fixedWidthDateLayout = "20060102"
+)
+
+// FileHeader identifies the debited account and value date of a bulk-transfer file
+type FileHeader struct {
+	SourceAccount string
+	ValueDate     time.Time
+}
+
+// Transfer is one credit to a merchant account
+type Transfer struct {
+	Reference     string // settlement batch number, echoed back in the confirmation
+	BankName      string
+	AccountNumber string
+	AccountName   string
+	Amount        float64
+}
+
+// Confirmation is one line of the bank's confirmation file
+type Confirmation struct {
+	Line          int
+	Reference     string
+	Status        string // SUCCESS, FAILED
+	BankReference string
+	Amount        float64
+	Reason        string
+}
+
+// WriteCSV writes transfers as a CSV bulk-transfer file with a header row
+func WriteCSV(w io.Writer, header FileHeader, transfers []Transfer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{
+		"reference", "source_account", "value_date", "beneficiary_bank",
+		"beneficiary_account", "beneficiary_name", "amount",
+	}); err != nil {
+		return err
+	}
+	for _, transfer := range transfers {
+		if err := writer.Write([]string{
+			transfer.Reference,
+			header.SourceAccount,
+			header.ValueDate.Format("2006-01-02"),
+			transfer.BankName,
+			transfer.AccountNumber,
+			transfer.AccountName,
+			strconv.FormatFloat(transfer.Amount, 'f', 2, 64),
+		}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteFixedWidth writes transfers as a fixed-width bulk-transfer file:
+//
+//	H | value date YYYYMMDD | source account (20) | record count (6) | total in sen (18)
+//	D | account number (20) | account name (35) | bank name (20) | amount in sen (15) | reference (30)
+//	T | record count (6) | total in sen (18)
+//
+// Text is upper case, left aligned and space padded; numbers are zero padded.
+func WriteFixedWidth(w io.Writer, header FileHeader, transfers []Transfer) error {
+	var total int64
+	for _, transfer := range transfers {
+		total += toSen(transfer.Amount)
+	}
+	count := len(transfers)
+
+	records := []string{
+		"H" + header.ValueDate.Format(fixedWidthDateLayout) +
+			text(header.SourceAccount, accountNumberWidth) +
+			number(int64(count), recordCountWidth) +
+			number(total, totalAmountWidth),
+	}
+	for _, transfer := range transfers {
+		records = append(records, "D"+
+			text(transfer.AccountNumber, accountNumberWidth)+
+			text(transfer.AccountName, accountNameWidth)+
+			text(transfer.BankName, bankNameWidth)+
+			number(toSen(transfer.Amount), detailAmountWidth)+
+			text(transfer.Reference, referenceWidth))
+	}
+	records = append(records, "T"+number(int64(count), recordCountWidth)+number(total, totalAmountWidth))
+
+	for _, record := range records {
+		if _, err := io.WriteString(w, text(record, recordLength)+"\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseConfirmations reads a bank confirmation CSV with the header
+// reference,status,bank_reference,amount[,reason].
+// Malformed lines are reported in the returned line errors and skipped.
+func ParseConfirmations(r io.Reader) ([]Confirmation, []string, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("file konfirmasi tidak valid: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil, errors.New("file konfirmasi kosong")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"reference", "status", "amount"} {
+		if _, ok := columns[required]; !ok {
+			return nil, nil, fmt.Errorf("kolom %s wajib ada pada header file konfirmasi", required)
+		}
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var confirmations []Confirmation
+	var lineErrors []string
+	for i, record := range records[1:] {
+		line := i + 2
+		confirmation := Confirmation{
+			Line:          line,
+			Reference:     field(record, "reference"),
+			Status:        strings.ToUpper(field(record, "status")),
+			BankReference: field(record, "bank_reference"),
+			Reason:        field(record, "reason"),
+		}
+		if confirmation.Reference == "" {
+			lineErrors = append(lineErrors, fmt.Sprintf("baris %d: reference kosong", line))
+			continue
+		}
+		if confirmation.Status != ConfirmationSuccess && confirmation.Status != ConfirmationFailed {
+			lineErrors = append(lineErrors, fmt.Sprintf("baris %d: status harus SUCCESS atau FAILED", line))
+			continue
+		}
+		amount, err := strconv.ParseFloat(field(record, "amount"), 64)
+		if err != nil {
+			lineErrors = append(lineErrors, fmt.Sprintf("baris %d: jumlah tidak valid", line))
+			continue
+		}
+		confirmation.Amount = amount
+		confirmations = append(confirmations, confirmation)
+	}
+	return confirmations, lineErrors, nil
+}
+
+// toSen converts rupiah to whole sen, the unit of fixed-width amounts
+func toSen(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// text upper-cases, strips non-printable ASCII and pads or truncates to width
+func text(value string, width int) string {
+	clean := strings.Map(func(r rune) rune {
+		if r < 32 || r > 126 {
+			return ' '
+		}
+		return r
+	}, strings.ToUpper(value))
+	if len(clean) > width {
+		return clean[:width]
+	}
+	return clean + strings.Repeat(" ", width-len(clean))
+}
+
+func number(value int64, width int) string {
+	return fmt.Sprintf("%0*d", width, value)
+}
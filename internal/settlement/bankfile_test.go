@@ -0,0 +1,89 @@
+package settlement
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testHeader = FileHeader{SourceAccount: "8880001111", ValueDate: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)}
+
+var testTransfers = []Transfer{
+	{Reference: "STL-20260301-DEALER-01", BankName: "BCA", AccountNumber: "1234567890", AccountName: "PT Motor Jaya", Amount: 2910000},
+	{Reference: "STL-20260301-TOKO-02", BankName: "Mandiri", AccountNumber: "9876543210", AccountName: "Toko Elektronik Sentosa Abadi Makmur Jaya", Amount: 1234.5},
+}
+
+// Test: CSV file has a header row and one row per transfer
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, testHeader, testTransfers); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected header and 2 rows, got %d lines", len(lines))
+	}
+	if lines[1] != "STL-20260301-DEALER-01,8880001111,2026-03-02,BCA,1234567890,PT Motor Jaya,2910000.00" {
+		t.Errorf("Unexpected CSV row %q", lines[1])
+	}
+}
+
+// Test: Fixed-width records have the same length, amounts in sen and a matching trailer
+func TestWriteFixedWidth(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteFixedWidth(&buf, testHeader, testTransfers); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	records := strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n")
+	if len(records) != 4 {
+		t.Fatalf("Expected header, 2 details and trailer, got %d records", len(records))
+	}
+	for i, record := range records {
+		if len(record) != recordLength {
+			t.Errorf("Record %d has length %d, expected %d", i, len(record), recordLength)
+		}
+	}
+
+	if !strings.HasPrefix(records[0], "H20260302"+"8880001111          "+"000002"+"000000000291123450") {
+		t.Errorf("Unexpected header %q", records[0])
+	}
+	// name truncated to 35 characters, 1.234,50 as 123450 sen
+	detail := records[2]
+	if detail[21:56] != "TOKO ELEKTRONIK SENTOSA ABADI MAKMU" || detail[76:91] != "000000000123450" {
+		t.Errorf("Unexpected detail %q", detail)
+	}
+	if !strings.HasPrefix(records[3], "T000002000000000291123450") {
+		t.Errorf("Unexpected trailer %q", records[3])
+	}
+}
+
+// Test: Confirmation lines are parsed, malformed lines reported
+func TestParseConfirmations(t *testing.T) {
+	data := "reference,status,bank_reference,amount,reason\n" +
+		"STL-20260301-DEALER-01,success,TRF001,2910000,\n" +
+		"STL-20260301-TOKO-02,FAILED,,1234.50,rekening tidak aktif\n" +
+		",SUCCESS,TRF003,100,\n" +
+		"STL-X,PENDING,TRF004,100,\n" +
+		"STL-Y,SUCCESS,TRF005,abc,\n"
+
+	confirmations, lineErrors, err := ParseConfirmations(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(confirmations) != 2 || len(lineErrors) != 3 {
+		t.Fatalf("Expected 2 confirmations and 3 line errors, got %d and %v", len(confirmations), lineErrors)
+	}
+	if confirmations[0].Status != ConfirmationSuccess || confirmations[0].BankReference != "TRF001" {
+		t.Errorf("Unexpected confirmation %+v", confirmations[0])
+	}
+	if confirmations[1].Reason != "rekening tidak aktif" || confirmations[1].Amount != 1234.5 {
+		t.Errorf("Unexpected confirmation %+v", confirmations[1])
+	}
+
+	if _, _, err := ParseConfirmations(strings.NewReader("reference,bank_reference\nSTL-1,TRF\n")); err == nil {
+		t.Error("Expected error for missing columns, got nil")
+	}
+}
@@ -0,0 +1,381 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"main/internal/ledger"
+	"main/internal/model"
+	"main/internal/repository"
+)
+
+// Accrual frequencies
+const (
+	AccrualFrequencyDaily   = "DAILY"
+	AccrualFrequencyMonthly = "MONTHLY"
+)
+
+// AccrualPolicy holds the revenue recognition settings
+type AccrualPolicy struct {
+	Frequency string // DAILY recognises every business date, MONTHLY only at month end
+}
+
+// DefaultAccrualPolicy returns daily accrual
+func DefaultAccrualPolicy() AccrualPolicy {
+	return AccrualPolicy{Frequency: AccrualFrequencyDaily}
+}
+
+// AccrualRunResult summarises one run of the accrual job
+type AccrualRunResult struct {
+	BusinessDate       time.Time `json:"business_date"`
+	Frequency          string    `json:"frequency"`
+	Skipped            bool      `json:"skipped,omitempty"` // not a month end under monthly accrual
+	ContractsAccrued   int       `json:"contracts_accrued"`
+	ContractsSuspended int       `json:"contracts_suspended"` // DEFAULTED contracts, which do not accrue
+	InterestRecognised float64   `json:"interest_recognised"` // net income posted by this run, corrections included
+}
+
+// AccrualUsecase defines all business logic operations for interest income recognition
+type AccrualUsecase interface {
+	RunAccrual(businessDate time.Time) (*AccrualRunResult, error)
+	GetAccruals(transactionID uint) ([]model.InterestAccrual, error)
+	ReverseAccruals(tx *repository.Repositories, transactionID uint, at time.Time) (float64, error)
+}
+
+// accrualUsecase is the implementation of AccrualUsecase
+type accrualUsecase struct {
+	transactionRepo repository.TransactionRepository
+	installmentRepo repository.InstallmentRepository
+	accrualRepo     repository.InterestAccrualRepository
+	transactor      repository.Transactor
+	policy          AccrualPolicy
+	mu              sync.Mutex
+}
+
+// NewAccrualUsecase creates a new instance of AccrualUsecase
+func NewAccrualUsecase(
+	transactionRepo repository.TransactionRepository,
+	installmentRepo repository.InstallmentRepository,
+	accrualRepo repository.InterestAccrualRepository,
+	transactor repository.Transactor,
+	policy AccrualPolicy,
+) AccrualUsecase {
+	return &accrualUsecase{
+		transactionRepo: transactionRepo,
+		installmentRepo: installmentRepo,
+		accrualRepo:     accrualRepo,
+		transactor:      transactor,
+		policy:          policy,
+	}
+}
+
+// RunAccrual recognises the interest every ACTIVE contract has earned up to the business date.
+// The target is recomputed from the schedule and only the difference with what is already
+// recognised is posted, so re-running a date is idempotent and re-running a back date after
+// a correction posts the adjustment. DEFAULTED contracts are non-performing and do not accrue.
+func (u *accrualUsecase) RunAccrual(businessDate time.Time) (*AccrualRunResult, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	date := dateOnly(businessDate)
+	if date.After(dateOnly(time.Now())) {
+		return nil, errors.New("tanggal akrual belum berlaku")
+	}
+
+	result := &AccrualRunResult{BusinessDate: date, Frequency: u.policy.Frequency}
+	if u.policy.Frequency == AccrualFrequencyMonthly && date.AddDate(0, 0, 1).Day() != 1 {
+		result.Skipped = true
+		return result, nil
+	}
+
+	defaulted, err := u.transactionRepo.GetByStatus(model.TransactionStatusDefaulted)
+	if err != nil {
+		return nil, err
+	}
+	result.ContractsSuspended = len(defaulted)
+
+	active, err := u.transactionRepo.GetByStatus(model.TransactionStatusActive)
+	if err != nil {
+		return nil, err
+	}
+	for i := range active {
+		transaction := &active[i]
+		if dateOnly(transaction.CreatedAt).After(date) {
+			continue
+		}
+		recognised, err := u.accrueTransaction(transaction, date)
+		if err != nil {
+			return nil, err
+		}
+		if recognised != 0 {
+			result.ContractsAccrued++
+			result.InterestRecognised = roundMoney(result.InterestRecognised + recognised)
+		}
+	}
+
+	log.Printf("✓ Akrual bunga %s: %d kontrak, Rp %.2f, %d kontrak default tidak diakru\n",
+		date.Format("2006-01-02"), result.ContractsAccrued, result.InterestRecognised, result.ContractsSuspended)
+	return result, nil
+}
+
+// accrueTransaction brings the accrual row of the date in line with the effective-interest target
+// and posts the movement. New rows post on the business date; corrections post on the run date.
+func (u *accrualUsecase) accrueTransaction(transaction *model.Transaction, date time.Time) (float64, error) {
+	installments, err := u.installmentRepo.GetByTransactionID(transaction.ID)
+	if err != nil {
+		return 0, err
+	}
+	accruals, err := u.accrualRepo.GetByTransactionID(transaction.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	// Interest up to a restructuring is settled by its true-up and the new schedule earns from its effective date
+	var restructured time.Time
+	if transaction.RestructuredAt != nil {
+		restructured = dateOnly(*transaction.RestructuredAt)
+		if !date.After(restructured) {
+			return 0, nil
+		}
+	}
+
+	var base, before float64
+	var existing *model.InterestAccrual
+	for i := range accruals {
+		accrual := &accruals[i]
+		if accrual.ReversedAt != nil {
+			continue
+		}
+		if !restructured.IsZero() && !accrual.AccrualDate.After(restructured) {
+			base += accrual.Amount
+		}
+		switch {
+		case dateOnly(accrual.AccrualDate).Equal(date):
+			existing = accrual
+		case accrual.AccrualDate.Before(date):
+			before += accrual.Amount
+		}
+	}
+	target := roundMoney(base + recognisedInterest(transaction, installments, date))
+	amount := roundMoney(target - before)
+
+	now := time.Now()
+	reference := fmt.Sprintf("ACR-%s-%s", transaction.ContractNumber, date.Format("20060102"))
+	postingDate := date
+	var movement float64
+	if existing == nil {
+		movement = amount
+	} else {
+		movement = roundMoney(amount - existing.Amount)
+	}
+	if movement == 0 {
+		return 0, nil
+	}
+
+	err = u.transactor.Transaction(func(tx *repository.Repositories) error {
+		if existing == nil {
+			if err := tx.Accruals.Create(&model.InterestAccrual{
+				TransactionID:    transaction.ID,
+				AccrualDate:      date,
+				Amount:           amount,
+				CumulativeAmount: target,
+				Revision:         1,
+				CreatedAt:        now,
+				UpdatedAt:        now,
+			}); err != nil {
+				return err
+			}
+		} else {
+			existing.Amount = amount
+			existing.CumulativeAmount = target
+			existing.Revision++
+			existing.UpdatedAt = now
+			if err := tx.Accruals.Update(existing); err != nil {
+				return err
+			}
+			reference = fmt.Sprintf("%s-R%d", reference, existing.Revision)
+			postingDate = now
+		}
+
+		entry := ledger.NewEntry(reference, model.JournalEventAccrual, transaction.ID, postingDate,
+			fmt.Sprintf("akrual bunga %s per %s", transaction.ContractNumber, date.Format("2006-01-02"))).
+			Signed(ledger.UnearnedInterest, movement, "bunga diakui").
+			Signed(ledger.InterestIncome, -movement, "bunga diakui")
+		return ledgerIn(tx).Post(entry)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return movement, nil
+}
+
+func (u *accrualUsecase) GetAccruals(transactionID uint) ([]model.InterestAccrual, error) {
+	return u.accrualRepo.GetByTransactionID(transactionID)
+}
+
+// ReverseAccruals takes back the interest recognised for a contract and not collected, for contracts written off.
+// Interest already paid with the installments stays earned. It runs inside the database transaction of the
+// caller, closes the open accruals and returns the reversed amount; a contract without open accruals reverses nothing.
+func (u *accrualUsecase) ReverseAccruals(tx *repository.Repositories, transactionID uint, at time.Time) (float64, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	transaction, err := tx.Transactions.GetByID(transactionID)
+	if err != nil {
+		return 0, errors.New("transaksi tidak ditemukan")
+	}
+	accruals, err := tx.Accruals.GetByTransactionID(transactionID)
+	if err != nil {
+		return 0, err
+	}
+
+	var accrued float64
+	for i := range accruals {
+		accrual := &accruals[i]
+		if accrual.ReversedAt != nil {
+			continue
+		}
+		accrued += accrual.Amount
+		accrual.ReversedAt = &at
+		accrual.UpdatedAt = time.Now()
+		if err := tx.Accruals.Update(accrual); err != nil {
+			return 0, err
+		}
+	}
+
+	installments, err := tx.Installments.GetByTransactionID(transactionID)
+	if err != nil {
+		return 0, err
+	}
+	total := roundMoney(accrued - paidInterest(installments))
+	if total <= 0 {
+		return 0, nil
+	}
+
+	entry := ledger.NewEntry("RVA-"+transaction.ContractNumber, model.JournalEventAccrualReversal, transaction.ID, at,
+		"pembalikan akrual bunga "+transaction.ContractNumber).
+		Signed(ledger.InterestIncome, total, "pembalikan akrual").
+		Signed(ledger.UnearnedInterest, -total, "pembalikan akrual")
+	if err := ledgerIn(tx).Post(entry); err != nil {
+		return 0, err
+	}
+
+	log.Printf("✓ Akrual bunga kontrak %s dibalik: Rp %.2f\n", transaction.ContractNumber, total)
+	return total, nil
+}
+
+// paidInterest is the interest collected on the installments; partial payments cover principal and interest proportionally
+func paidInterest(installments []model.Installment) float64 {
+	var paid float64
+	for _, installment := range installments {
+		if installment.Status == model.InstallmentStatusCancelled || installment.Amount <= 0 {
+			continue
+		}
+		paid += installment.InterestAmount * min(installment.PaidAmount, installment.Amount) / installment.Amount
+	}
+	return roundMoney(paid)
+}
+
+// recognisedInterest is the interest a contract has earned up to and including the date:
+// whole periods that have fallen due plus the elapsed days of the running period.
+// For a restructured contract only the current schedule counts, from the restructuring date.
+func recognisedInterest(transaction *model.Transaction, installments []model.Installment, date time.Time) float64 {
+	scheduled := currentSchedule(installments)
+	periods := effectiveInterestByPeriod(scheduled)
+	periodStart := dateOnly(transaction.CreatedAt)
+	if transaction.RestructuredAt != nil {
+		periodStart = dateOnly(*transaction.RestructuredAt)
+	}
+	var earned float64
+	for i, installment := range scheduled {
+		dueDate := dateOnly(installment.DueDate)
+		switch {
+		case !date.Before(dueDate):
+			earned += periods[i]
+		case date.After(periodStart):
+			earned += periods[i] * float64(daysBetween(periodStart, date)) / float64(daysBetween(periodStart, dueDate))
+		}
+		periodStart = dueDate
+	}
+	return roundMoney(earned)
+}
+
+// currentSchedule returns the installments of the latest schedule in sequence order,
+// leaving out cancelled installments and those replaced by a restructuring
+func currentSchedule(installments []model.Installment) []model.Installment {
+	var latest uint
+	for _, installment := range installments {
+		latest = max(latest, installment.RestructuringID)
+	}
+
+	var scheduled []model.Installment
+	for _, installment := range installments {
+		if installment.RestructuringID != latest ||
+			installment.Status == model.InstallmentStatusCancelled ||
+			installment.Status == model.InstallmentStatusRescheduled {
+			continue
+		}
+		scheduled = append(scheduled, installment)
+	}
+	sort.Slice(scheduled, func(i, j int) bool { return scheduled[i].Sequence < scheduled[j].Sequence })
+	return scheduled
+}
+
+// effectiveInterestByPeriod spreads the scheduled interest over the periods with the effective-interest
+// method: each period earns the constant rate that discounts the installments back to the principal,
+// applied to the balance still outstanding. The last period absorbs the rounding.
+func effectiveInterestByPeriod(installments []model.Installment) []float64 {
+	periods := make([]float64, len(installments))
+	var principal, interest float64
+	for _, installment := range installments {
+		principal += installment.PrincipalAmount
+		interest += installment.InterestAmount
+	}
+	if len(installments) == 0 || principal <= 0 || interest <= 0 {
+		for i, installment := range installments {
+			periods[i] = installment.InterestAmount
+		}
+		return periods
+	}
+
+	rate := effectiveRate(principal, installments)
+	balance := principal
+	var allocated float64
+	for i, installment := range installments {
+		if i == len(installments)-1 {
+			periods[i] = interest - allocated
+			break
+		}
+		periods[i] = balance * rate
+		allocated += periods[i]
+		balance = balance + periods[i] - installment.Amount
+	}
+	return periods
+}
+
+// effectiveRate solves the periodic rate at which the present value of the installments equals the principal
+func effectiveRate(principal float64, installments []model.Installment) float64 {
+	presentValue := func(rate float64) float64 {
+		var value float64
+		for i, installment := range installments {
+			value += installment.Amount / math.Pow(1+rate, float64(i+1))
+		}
+		return value
+	}
+
+	low, high := 0.0, 1.0
+	for i := 0; i < 100; i++ {
+		mid := (low + high) / 2
+		if presentValue(mid) > principal {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return (low + high) / 2
+}
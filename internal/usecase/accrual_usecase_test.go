@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"main/internal/ledger"
+	"main/internal/model"
+	"main/internal/repository"
+)
+
+// MockInterestAccrualRepository for testing
+type MockInterestAccrualRepository struct {
+	accruals []model.InterestAccrual
+}
+
+func (m *MockInterestAccrualRepository) Create(accrual *model.InterestAccrual) error {
+	accrual.ID = uint(len(m.accruals) + 1)
+	m.accruals = append(m.accruals, *accrual)
+	return nil
+}
+
+func (m *MockInterestAccrualRepository) GetByTransactionID(transactionID uint) ([]model.InterestAccrual, error) {
+	var accruals []model.InterestAccrual
+	for _, accrual := range m.accruals {
+		if accrual.TransactionID == transactionID {
+			accruals = append(accruals, accrual)
+		}
+	}
+	return accruals, nil
+}
+
+func (m *MockInterestAccrualRepository) Update(accrual *model.InterestAccrual) error {
+	for i := range m.accruals {
+		if m.accruals[i].ID == accrual.ID {
+			m.accruals[i] = *accrual
+			return nil
+		}
+	}
+	return nil
+}
+
+type accrualFixture struct {
+	uc              AccrualUsecase
+	transactionRepo *MockTransactionRepository
+	installmentRepo *MockInstallmentRepository
+	accrualRepo     *MockInterestAccrualRepository
+	ledger          LedgerUsecase
+	journalRepo     *MockJournalRepository
+}
+
+// transactor runs units of work against the fixture repositories
+func (f *accrualFixture) transactor() *MockTransactor {
+	return newTestTransactor(repository.Repositories{
+		Transactions: f.transactionRepo,
+		Installments: f.installmentRepo,
+		Accruals:     f.accrualRepo,
+		Journal:      f.journalRepo,
+	})
+}
+
+// newAccrualFixture books and activates the test contract on 1 Jan 2026:
+// 3.000.000 principal and 300.000 interest in three installments of 1.100.000
+func newAccrualFixture(policy AccrualPolicy) *accrualFixture {
+	f := &accrualFixture{
+		transactionRepo: NewMockTransactionRepository(),
+		installmentRepo: &MockInstallmentRepository{},
+		accrualRepo:     &MockInterestAccrualRepository{},
+	}
+	start := time.Date(2026, 1, 1, 10, 0, 0, 0, time.Local)
+	transaction := bookTestContract(f.transactionRepo, f.installmentRepo, start)
+
+	f.ledger, f.journalRepo = newTestLedger()
+	f.ledger.Post(activationEntry(transaction, start))
+
+	f.uc = NewAccrualUsecase(f.transactionRepo, f.installmentRepo, f.accrualRepo, f.transactor(), policy)
+	return f
+}
+
+// Test: Effective interest is front-loaded on the outstanding balance and adds up to the contract interest
+func TestEffectiveInterestByPeriod(t *testing.T) {
+	schedule := buildInstallmentSchedule(1, 3000000, 300000, 3, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	periods := effectiveInterestByPeriod(schedule)
+
+	// Effective rate 4.9212% a month on 3.000.000, then on 2.047.636,77
+	expected := []float64{147636.77, 100768.83, 51594.40}
+	total := 0.0
+	for i, interest := range periods {
+		if math.Abs(interest-expected[i]) > 0.01 {
+			t.Errorf("Period %d: expected %.2f, got %.2f", i+1, expected[i], interest)
+		}
+		total += interest
+	}
+	if roundMoney(total) != 300000 {
+		t.Errorf("Expected periods to add up to 300000, got %.2f", total)
+	}
+}
+
+// Test: Daily accrual recognises elapsed days of the running period and re-running the date changes nothing
+func TestRunAccrual_DailyIdempotent(t *testing.T) {
+	f := newAccrualFixture(DefaultAccrualPolicy())
+
+	// 14 of 31 days of the first period
+	result, err := f.uc.RunAccrual(time.Date(2026, 1, 15, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.ContractsAccrued != 1 || result.InterestRecognised != 66674.67 {
+		t.Errorf("Expected 66674.67 recognised on 1 contract, got %.2f on %d", result.InterestRecognised, result.ContractsAccrued)
+	}
+
+	again, _ := f.uc.RunAccrual(time.Date(2026, 1, 15, 0, 0, 0, 0, time.Local))
+	if again.ContractsAccrued != 0 || len(f.accrualRepo.accruals) != 1 {
+		t.Errorf("Expected re-run to change nothing, got %d contracts and %d rows", again.ContractsAccrued, len(f.accrualRepo.accruals))
+	}
+
+	// The next day only adds the movement since the previous date
+	next, _ := f.uc.RunAccrual(time.Date(2026, 2, 1, 0, 0, 0, 0, time.Local))
+	if next.InterestRecognised != roundMoney(147636.77-66674.67) {
+		t.Errorf("Expected %.2f recognised at the first due date, got %.2f", 147636.77-66674.67, next.InterestRecognised)
+	}
+	if accountBalance(t, f.ledger, ledger.InterestIncome) != 147636.77 ||
+		accountBalance(t, f.ledger, ledger.UnearnedInterest) != roundMoney(300000-147636.77) {
+		t.Error("Expected interest moved from deferred to income")
+	}
+}
+
+// Test: Re-running a back date after a correction posts only the adjustment
+func TestRunAccrual_BackDatedCorrection(t *testing.T) {
+	f := newAccrualFixture(DefaultAccrualPolicy())
+	date := time.Date(2026, 1, 15, 0, 0, 0, 0, time.Local)
+	f.uc.RunAccrual(date)
+
+	// Halve the contract interest as a partial return would
+	for _, installment := range f.installmentRepo.installments {
+		installment.InterestAmount = roundMoney(installment.InterestAmount / 2)
+		installment.Amount = roundMoney(installment.PrincipalAmount + installment.InterestAmount)
+	}
+
+	result, err := f.uc.RunAccrual(date)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.InterestRecognised >= 0 {
+		t.Errorf("Expected a negative adjustment, got %.2f", result.InterestRecognised)
+	}
+
+	accrual := f.accrualRepo.accruals[0]
+	if len(f.accrualRepo.accruals) != 1 || accrual.Revision != 2 || accrual.CumulativeAmount >= 66674.67 {
+		t.Errorf("Expected the row revised in place, got %+v", f.accrualRepo.accruals)
+	}
+	if accountBalance(t, f.ledger, ledger.InterestIncome) != accrual.CumulativeAmount {
+		t.Errorf("Expected income %.2f after the correction, got %.2f", accrual.CumulativeAmount, accountBalance(t, f.ledger, ledger.InterestIncome))
+	}
+}
+
+// Test: DEFAULTED contracts stop accruing
+func TestRunAccrual_DefaultedSuspended(t *testing.T) {
+	f := newAccrualFixture(DefaultAccrualPolicy())
+	transaction, _ := f.transactionRepo.GetByID(1)
+	transaction.Status = model.TransactionStatusDefaulted
+
+	result, err := f.uc.RunAccrual(time.Date(2026, 1, 15, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.ContractsAccrued != 0 || result.ContractsSuspended != 1 || len(f.accrualRepo.accruals) != 0 {
+		t.Errorf("Expected no accrual for the defaulted contract, got %+v", result)
+	}
+}
+
+// Test: Monthly accrual only runs at month end
+func TestRunAccrual_Monthly(t *testing.T) {
+	f := newAccrualFixture(AccrualPolicy{Frequency: AccrualFrequencyMonthly})
+
+	result, _ := f.uc.RunAccrual(time.Date(2026, 1, 15, 0, 0, 0, 0, time.Local))
+	if !result.Skipped || len(f.accrualRepo.accruals) != 0 {
+		t.Error("Expected mid-month run to be skipped")
+	}
+
+	result, _ = f.uc.RunAccrual(time.Date(2026, 1, 31, 0, 0, 0, 0, time.Local))
+	if result.Skipped || result.ContractsAccrued != 1 {
+		t.Errorf("Expected month-end accrual, got %+v", result)
+	}
+}
+
+// Test: Reversal takes the recognised interest not yet paid back to deferred, once
+func TestReverseAccruals(t *testing.T) {
+	f := newAccrualFixture(DefaultAccrualPolicy())
+	f.uc.RunAccrual(time.Date(2026, 1, 15, 0, 0, 0, 0, time.Local))
+	f.uc.RunAccrual(time.Date(2026, 1, 20, 0, 0, 0, 0, time.Local))
+
+	reversed, err := f.uc.ReverseAccruals(f.transactor().repos, 1, time.Now())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if reversed <= 66674.67 {
+		t.Errorf("Expected both days reversed, got %.2f", reversed)
+	}
+	if accountBalance(t, f.ledger, ledger.InterestIncome) != 0 || accountBalance(t, f.ledger, ledger.UnearnedInterest) != 300000 {
+		t.Error("Expected income reversed to deferred interest")
+	}
+
+	if again, _ := f.uc.ReverseAccruals(f.transactor().repos, 1, time.Now()); again != 0 {
+		t.Errorf("Expected nothing left to reverse, got %.2f", again)
+	}
+}
@@ -0,0 +1,375 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"main/internal/ledger"
+	"main/internal/model"
+	"main/internal/repository"
+)
+
+var ErrCancellationWindowClosed = errors.New("batas waktu pembatalan transaksi sudah lewat")
+
+var validCancelReasons = map[string]bool{
+	model.CancelReasonCoolingOff:    true,
+	model.CancelReasonGoodsReturned: true,
+	model.CancelReasonMerchantError: true,
+}
+
+// CancellationPolicy holds the cooling-off terms and how long a contract waits for the consumer signature
+type CancellationPolicy struct {
+	WindowDays       int           // days after booking a transaction can still be cancelled
+	SignatureTimeout time.Duration // contracts not signed this long after booking are cancelled
+}
+
+// DefaultCancellationPolicy returns a 7 day cooling-off window and cancels contracts unsigned after 3 days
+func DefaultCancellationPolicy() CancellationPolicy {
+	return CancellationPolicy{
+		WindowDays:       7,
+		SignatureTimeout: 72 * time.Hour,
+	}
+}
+
+// CancelRequest describes a cancellation. Amount is the returned part of the OTR;
+// zero or the full OTR cancels the whole transaction.
+type CancelRequest struct {
+	ReasonCode string  `json:"reason_code"`
+	Amount     float64 `json:"amount"`
+	Note       string  `json:"note"`
+}
+
+// CancellationResult is the outcome of a cancellation
+type CancellationResult struct {
+	Transaction       *model.Transaction             `json:"transaction"`
+	Cancellation      *model.TransactionCancellation `json:"cancellation"`
+	Refund            *model.Refund                  `json:"refund,omitempty"`
+	DownPaymentRefund *model.Refund                  `json:"down_payment_refund,omitempty"` // full cancellations only
+}
+
+// CancellationUsecase defines all business logic operations for transaction cancellation
+type CancellationUsecase interface {
+	CancelTransaction(transactionID uint, req CancelRequest) (*CancellationResult, error)
+	CancelUnsignedTransactions(now time.Time) (int, error)
+}
+
+// cancellationUsecase is the implementation of CancellationUsecase
+type cancellationUsecase struct {
+	transactionRepo repository.TransactionRepository
+	installmentRepo repository.InstallmentRepository
+	paymentRepo     repository.PaymentRepository
+	refundRepo      repository.RefundRepository
+	transactor      repository.Transactor
+	notifier        EventNotifier
+	policy          CancellationPolicy
+	mu              sync.Mutex
+}
+
+// NewCancellationUsecase creates a new instance of CancellationUsecase
+func NewCancellationUsecase(
+	transactionRepo repository.TransactionRepository,
+	installmentRepo repository.InstallmentRepository,
+	paymentRepo repository.PaymentRepository,
+	refundRepo repository.RefundRepository,
+	transactor repository.Transactor,
+	notifier EventNotifier,
+	policy CancellationPolicy,
+) CancellationUsecase {
+	return &cancellationUsecase{
+		transactionRepo: transactionRepo,
+		installmentRepo: installmentRepo,
+		paymentRepo:     paymentRepo,
+		refundRepo:      refundRepo,
+		transactor:      transactor,
+		notifier:        notifier,
+		policy:          policy,
+	}
+}
+
+// CancelTransaction cancels all or part of a transaction within the cooling-off window,
+// releases the cancelled amount to the limit and refunds the matching share of the admin fee
+func (u *cancellationUsecase) CancelTransaction(transactionID uint, req CancelRequest) (*CancellationResult, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	req.ReasonCode = strings.ToUpper(strings.TrimSpace(req.ReasonCode))
+	if !validCancelReasons[req.ReasonCode] {
+		return nil, errors.New("kode alasan pembatalan tidak valid")
+	}
+	if req.Amount < 0 {
+		return nil, errors.New("jumlah pembatalan tidak boleh negatif")
+	}
+
+	transaction, err := u.transactionRepo.GetByID(transactionID)
+	if err != nil {
+		return nil, errors.New("transaksi tidak ditemukan")
+	}
+	if transaction.Status != model.TransactionStatusActive && transaction.Status != model.TransactionStatusPendingReview &&
+		transaction.Status != model.TransactionStatusPendingSignature && transaction.Status != model.TransactionStatusPendingDownPayment {
+		return nil, fmt.Errorf("transaksi berstatus %s tidak dapat dibatalkan", transaction.Status)
+	}
+	if transaction.RestructureCount > 0 {
+		return nil, errors.New("transaksi yang sudah direstrukturisasi tidak dapat dibatalkan")
+	}
+
+	now := time.Now()
+	if daysBetween(transaction.CreatedAt, now) > u.policy.WindowDays {
+		return nil, ErrCancellationWindowClosed
+	}
+	return u.cancel(transaction, req, now)
+}
+
+// CancelUnsignedTransactions cancels every contract still waiting for the consumer signature past the
+// signature timeout, releasing its limit, and returns how many were cancelled
+func (u *cancellationUsecase) CancelUnsignedTransactions(now time.Time) (int, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	transactions, err := u.transactionRepo.GetByStatus(model.TransactionStatusPendingSignature)
+	if err != nil {
+		return 0, err
+	}
+
+	cancelled := 0
+	for i := range transactions {
+		transaction := &transactions[i]
+		if now.Before(transaction.CreatedAt.Add(u.policy.SignatureTimeout)) {
+			continue
+		}
+		req := CancelRequest{
+			ReasonCode: model.CancelReasonSignatureExpired,
+			Note:       fmt.Sprintf("kontrak tidak ditandatangani dalam %s", u.policy.SignatureTimeout),
+		}
+		if _, err := u.cancel(transaction, req, now); err != nil {
+			return cancelled, err
+		}
+		cancelled++
+	}
+	if cancelled > 0 {
+		log.Printf("✓ %d kontrak yang tidak ditandatangani dibatalkan\n", cancelled)
+	}
+	return cancelled, nil
+}
+
+// cancel carries out a cancellation the caller has validated for the status and timing of the transaction
+func (u *cancellationUsecase) cancel(transaction *model.Transaction, req CancelRequest, now time.Time) (*CancellationResult, error) {
+	installments, err := u.installmentRepo.GetByTransactionID(transaction.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, installment := range installments {
+		if installment.PaidAmount > 0 {
+			return nil, errors.New("transaksi tidak dapat dibatalkan setelah angsuran dibayar")
+		}
+	}
+
+	amount := req.Amount
+	if amount == 0 {
+		amount = transaction.OTR
+	}
+	if amount > transaction.OTR {
+		return nil, errors.New("jumlah pembatalan melebihi OTR transaksi")
+	}
+	partial := amount < transaction.OTR
+	if partial && amount >= transaction.FinancedAmount() {
+		return nil, errors.New("jumlah pembatalan parsial harus kurang dari jumlah pembiayaan, gunakan pembatalan penuh")
+	}
+
+	refundAmount, err := u.adminFeeRefund(transaction, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	var downPayment float64
+	if !partial {
+		if downPayment, err = upfrontReceived(u.paymentRepo, transaction.ID, model.PaymentTypeDownPayment); err != nil {
+			return nil, err
+		}
+	}
+
+	// A partial return is taken off the financed principal; a full cancellation frees the whole financed amount
+	released := amount
+	if !partial {
+		released = transaction.FinancedAmount()
+	}
+	previousStatus := transaction.Status
+	activated := previousStatus == model.TransactionStatusActive
+	interestBefore := transaction.InterestAmount
+
+	// The merchant gives back the financing it was owed for the cancelled part and the down payment it collected;
+	// settlement takes it off the next batch of the merchant
+	var merchantAmount float64
+	if transaction.MerchantID != 0 {
+		merchantAmount = downPayment
+		if activated {
+			merchantAmount += released
+		}
+	}
+
+	result := &CancellationResult{Transaction: transaction}
+	err = u.transactor.Transaction(func(tx *repository.Repositories) error {
+		if err := releaseLimit(tx.Limits, transaction.ConsumerID, transaction.Tenor, released); err != nil {
+			return err
+		}
+
+		if partial {
+			if err := reduceTransaction(tx.Installments, transaction, installments, amount); err != nil {
+				return err
+			}
+		} else {
+			for i := range installments {
+				installments[i].Status = model.InstallmentStatusCancelled
+				installments[i].UpdatedAt = now
+				if err := tx.Installments.Update(&installments[i]); err != nil {
+					return err
+				}
+			}
+			transaction.Status = model.TransactionStatusCancelled
+		}
+
+		transaction.UpdatedAt = now
+		if err := tx.Transactions.Update(transaction); err != nil {
+			return err
+		}
+
+		result.Cancellation = &model.TransactionCancellation{
+			TransactionID:  transaction.ID,
+			ReasonCode:     req.ReasonCode,
+			Note:           req.Note,
+			Amount:         amount,
+			Partial:        partial,
+			MerchantAmount: roundMoney(merchantAmount),
+			CreatedAt:      now,
+		}
+		if err := tx.Cancellations.Create(result.Cancellation); err != nil {
+			return err
+		}
+
+		if refundAmount > 0 {
+			result.Refund = &model.Refund{
+				TransactionID:  transaction.ID,
+				ConsumerID:     transaction.ConsumerID,
+				CancellationID: result.Cancellation.ID,
+				Amount:         refundAmount,
+				Reason:         "pengembalian biaya admin: " + req.ReasonCode,
+				Status:         model.RefundStatusPending,
+				CreatedAt:      now,
+				UpdatedAt:      now,
+			}
+			if err := tx.Refunds.Create(result.Refund); err != nil {
+				return err
+			}
+		}
+
+		if downPayment > 0 {
+			result.DownPaymentRefund = &model.Refund{
+				TransactionID:  transaction.ID,
+				ConsumerID:     transaction.ConsumerID,
+				CancellationID: result.Cancellation.ID,
+				Amount:         downPayment,
+				Reason:         "pengembalian DP: " + req.ReasonCode,
+				Status:         model.RefundStatusPending,
+				CreatedAt:      now,
+				UpdatedAt:      now,
+			}
+			if err := tx.Refunds.Create(result.DownPaymentRefund); err != nil {
+				return err
+			}
+		}
+
+		var adminFeeRefund float64
+		if result.Refund != nil {
+			adminFeeRefund = result.Refund.Amount
+		}
+		// Contracts still under fraud review or waiting for the signature or the down payment were never activated:
+		// only the admin fee and the down payment received are moved to the refund payable
+		if !activated {
+			return ledgerIn(tx).Post(cancellationEntry(transaction, result.Cancellation, 0, 0, 0, adminFeeRefund, downPayment))
+		}
+		// A partial return releases its share of the deferred interest and the next accrual run
+		// trues up the income; a full cancellation releases the whole deferred balance and reverses accruals
+		books := ledgerIn(tx)
+		interest := roundMoney(interestBefore - transaction.InterestAmount)
+		deferred := interest
+		if !partial {
+			interest = interestBefore
+			var err error
+			if deferred, err = books.ContractBalance(transaction.ID, ledger.UnearnedInterest); err != nil {
+				return err
+			}
+		}
+		return books.Post(cancellationEntry(transaction, result.Cancellation, released, interest, deferred, adminFeeRefund, downPayment))
+	})
+	if err != nil {
+		return nil, err
+	}
+	refundAmount += downPayment
+
+	u.notifier.NotifyStatusChanged(transaction, previousStatus)
+
+	log.Printf("✓ Kontrak %s dibatalkan (%s): Rp %.2f, refund Rp %.2f\n", transaction.ContractNumber, req.ReasonCode, amount, refundAmount)
+	return result, nil
+}
+
+// adminFeeRefund returns the admin fee paid and not yet refunded above the admin fee the contract keeps once
+// amount of its OTR is cancelled. The down payment is only refunded on a full cancellation, which closes the contract.
+func (u *cancellationUsecase) adminFeeRefund(transaction *model.Transaction, amount float64) (float64, error) {
+	remaining, err := upfrontReceived(u.paymentRepo, transaction.ID, model.PaymentTypeAdminFee)
+	if err != nil {
+		return 0, err
+	}
+	refunds, err := u.refundRepo.GetByTransactionID(transaction.ID)
+	if err != nil {
+		return 0, err
+	}
+	for _, refund := range refunds {
+		remaining -= refund.Amount
+	}
+
+	refund := roundMoney(remaining - adminFeeAfter(transaction, amount))
+	if refund <= 0 {
+		return 0, nil
+	}
+	return refund, nil
+}
+
+// adminFeeAfter returns the admin fee of the contract once amount of its OTR is cancelled: the fee of the part
+// kept, pro rata, whether or not the fee has been paid yet
+func adminFeeAfter(transaction *model.Transaction, amount float64) float64 {
+	if amount >= transaction.OTR {
+		return 0
+	}
+	return roundMoney(transaction.AdminFee * (transaction.OTR - amount) / transaction.OTR)
+}
+
+// reduceTransaction lowers the OTR and the financed principal by the returned amount, takes the admin fee down pro rata
+// and rebuilds the unpaid schedule. The down payment is kept.
+func reduceTransaction(installmentRepo repository.InstallmentRepository, transaction *model.Transaction, installments []model.Installment, amount float64) error {
+	ratio := (transaction.FinancedAmount() - amount) / transaction.FinancedAmount()
+
+	transaction.AdminFee = adminFeeAfter(transaction, amount)
+	transaction.OTR = roundMoney(transaction.OTR - amount)
+	transaction.InterestAmount = roundMoney(transaction.InterestAmount * ratio)
+	transaction.InstallmentAmount = roundMoney(transaction.InstallmentAmount * ratio)
+
+	schedule := buildInstallmentSchedule(transaction.ID, transaction.FinancedAmount(), transaction.InterestAmount, transaction.Tenor, transaction.CreatedAt)
+	for i := range installments {
+		installment := &installments[i]
+		if installment.Sequence < 1 || installment.Sequence > len(schedule) {
+			continue
+		}
+		rebuilt := schedule[installment.Sequence-1]
+		installment.PrincipalAmount = rebuilt.PrincipalAmount
+		installment.InterestAmount = rebuilt.InterestAmount
+		installment.Amount = rebuilt.Amount
+		installment.UpdatedAt = time.Now()
+		if err := installmentRepo.Update(installment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,297 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"main/internal/ledger"
+	"main/internal/model"
+	"main/internal/repository"
+)
+
+// MockTransactionCancellationRepository for testing
+type MockTransactionCancellationRepository struct {
+	cancellations []model.TransactionCancellation
+}
+
+func (m *MockTransactionCancellationRepository) Create(cancellation *model.TransactionCancellation) error {
+	cancellation.ID = uint(len(m.cancellations) + 1)
+	m.cancellations = append(m.cancellations, *cancellation)
+	return nil
+}
+
+func (m *MockTransactionCancellationRepository) GetByTransactionID(transactionID uint) ([]model.TransactionCancellation, error) {
+	var cancellations []model.TransactionCancellation
+	for _, cancellation := range m.cancellations {
+		if cancellation.TransactionID == transactionID {
+			cancellations = append(cancellations, cancellation)
+		}
+	}
+	return cancellations, nil
+}
+
+func (m *MockTransactionCancellationRepository) GetBilledBefore(before time.Time) ([]model.TransactionCancellation, error) {
+	var cancellations []model.TransactionCancellation
+	for _, cancellation := range m.cancellations {
+		if cancellation.MerchantAmount > 0 && cancellation.CreatedAt.Before(before) {
+			cancellations = append(cancellations, cancellation)
+		}
+	}
+	return cancellations, nil
+}
+
+// MockRefundRepository for testing
+type MockRefundRepository struct {
+	refunds []model.Refund
+}
+
+func (m *MockRefundRepository) Create(refund *model.Refund) error {
+	refund.ID = uint(len(m.refunds) + 1)
+	m.refunds = append(m.refunds, *refund)
+	return nil
+}
+
+func (m *MockRefundRepository) GetByTransactionID(transactionID uint) ([]model.Refund, error) {
+	var refunds []model.Refund
+	for _, refund := range m.refunds {
+		if refund.TransactionID == transactionID {
+			refunds = append(refunds, refund)
+		}
+	}
+	return refunds, nil
+}
+
+type cancellationFixture struct {
+	uc              CancellationUsecase
+	transactionRepo *MockTransactionRepository
+	installmentRepo *MockInstallmentRepository
+	limitRepo       *MockConsumerLimitRepository
+	paymentRepo     *MockPaymentRepository
+	refundRepo      *MockRefundRepository
+	ledger          LedgerUsecase
+}
+
+// newCancellationFixture books the test contract on start with a 150.000 admin fee paid upfront
+func newCancellationFixture(start time.Time) *cancellationFixture {
+	f := &cancellationFixture{
+		transactionRepo: NewMockTransactionRepository(),
+		installmentRepo: &MockInstallmentRepository{},
+		limitRepo:       NewMockConsumerLimitRepository(),
+		refundRepo:      &MockRefundRepository{},
+	}
+	transaction := bookTestContract(f.transactionRepo, f.installmentRepo, start)
+	transaction.AdminFee = 150000
+	f.limitRepo.Create(&model.ConsumerLimit{ConsumerID: 1, Tenor: 3, LimitAmount: 10000000, UsedAmount: 3000000})
+
+	paymentRepo := &MockPaymentRepository{}
+	f.paymentRepo = paymentRepo
+	adminFee := &model.Payment{TransactionID: transaction.ID, ConsumerID: 1, Type: model.PaymentTypeAdminFee, Amount: 150000, Reference: "ADM-RCPT-1"}
+	paymentRepo.Create(adminFee)
+
+	var journalRepo *MockJournalRepository
+	f.ledger, journalRepo = newTestLedger()
+	f.ledger.Post(activationEntry(transaction, start))
+	f.ledger.Post(adminFeeEntry(transaction, adminFee))
+
+	f.uc = NewCancellationUsecase(
+		f.transactionRepo,
+		f.installmentRepo,
+		paymentRepo,
+		f.refundRepo,
+		newTestTransactor(repository.Repositories{
+			Transactions:  f.transactionRepo,
+			Limits:        f.limitRepo,
+			Installments:  f.installmentRepo,
+			Payments:      paymentRepo,
+			Cancellations: &MockTransactionCancellationRepository{},
+			Refunds:       f.refundRepo,
+			Journal:       journalRepo,
+		}),
+		&MockEventNotifier{},
+		DefaultCancellationPolicy(),
+	)
+	return f
+}
+
+func (f *cancellationFixture) usedAmount() float64 {
+	limit, _ := f.limitRepo.GetByConsumerAndTenor(1, 3)
+	return limit.UsedAmount
+}
+
+// Test: Full cancellation releases the limit, cancels the schedule and refunds the admin fee
+func TestCancelTransaction_Full(t *testing.T) {
+	f := newCancellationFixture(time.Now().AddDate(0, 0, -2))
+
+	result, err := f.uc.CancelTransaction(1, CancelRequest{ReasonCode: "cooling_off"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Transaction.Status != model.TransactionStatusCancelled {
+		t.Errorf("Expected CANCELLED, got %s", result.Transaction.Status)
+	}
+	if f.usedAmount() != 0 {
+		t.Errorf("Expected used amount 0, got %.2f", f.usedAmount())
+	}
+	if result.Refund == nil || result.Refund.Amount != 150000 {
+		t.Fatal("Expected admin fee refund of 150000")
+	}
+
+	installments, _ := f.installmentRepo.GetByTransactionID(1)
+	for _, installment := range installments {
+		if installment.Status != model.InstallmentStatusCancelled {
+			t.Errorf("Expected installment %d CANCELLED, got %s", installment.Sequence, installment.Status)
+		}
+	}
+	// The receivable is reversed and the admin fee moves to the refund payable
+	if accountBalance(t, f.ledger, ledger.Receivable) != 0 || accountBalance(t, f.ledger, ledger.UnearnedInterest) != 0 ||
+		accountBalance(t, f.ledger, ledger.RefundPayable) != 150000 || accountBalance(t, f.ledger, ledger.AdminFeeIncome) != 0 {
+		t.Error("Expected cancellation entry reversing the activation")
+	}
+}
+
+// Test: An admin fee that was never received is not refunded
+func TestCancelTransaction_AdminFeeNotReceived(t *testing.T) {
+	f := newCancellationFixture(time.Now().AddDate(0, 0, -2))
+	f.paymentRepo.payments = nil
+
+	result, err := f.uc.CancelTransaction(1, CancelRequest{ReasonCode: "cooling_off"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Refund != nil {
+		t.Errorf("Expected no refund without an admin fee receipt, got %.2f", result.Refund.Amount)
+	}
+	if len(f.refundRepo.refunds) != 0 {
+		t.Errorf("Expected no refund recorded, got %d", len(f.refundRepo.refunds))
+	}
+}
+
+// Test: Returning one item reduces the contract and refunds the admin fee pro rata
+func TestCancelTransaction_Partial(t *testing.T) {
+	f := newCancellationFixture(time.Now().AddDate(0, 0, -2))
+
+	result, err := f.uc.CancelTransaction(1, CancelRequest{ReasonCode: model.CancelReasonGoodsReturned, Amount: 1000000})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	transaction := result.Transaction
+	if transaction.Status != model.TransactionStatusActive || transaction.OTR != 2000000 || transaction.InterestAmount != 200000 {
+		t.Errorf("Expected ACTIVE with OTR 2000000 interest 200000, got %s %.2f %.2f", transaction.Status, transaction.OTR, transaction.InterestAmount)
+	}
+	if f.usedAmount() != 2000000 {
+		t.Errorf("Expected used amount 2000000, got %.2f", f.usedAmount())
+	}
+	if result.Refund == nil || result.Refund.Amount != 50000 {
+		t.Fatal("Expected admin fee refund of 50000")
+	}
+
+	installments, _ := f.installmentRepo.GetByTransactionID(1)
+	total := 0.0
+	for _, installment := range installments {
+		total += installment.Amount
+	}
+	if roundMoney(total) != 2200000 {
+		t.Errorf("Expected rebuilt schedule total 2200000, got %.2f", total)
+	}
+	if accountBalance(t, f.ledger, ledger.Receivable) != 2200000 || accountBalance(t, f.ledger, ledger.MerchantPayable) != 2000000 {
+		t.Error("Expected receivable and merchant payable reduced by the returned item")
+	}
+
+	// Cancelling the rest refunds only what is left of the admin fee
+	result, _ = f.uc.CancelTransaction(1, CancelRequest{ReasonCode: model.CancelReasonGoodsReturned})
+	if result.Refund == nil || result.Refund.Amount != 100000 {
+		t.Error("Expected remaining admin fee refund of 100000")
+	}
+}
+
+// Test: A partial return lowers the admin fee pro rata whether or not it was paid; only a receipt above it is refunded
+func TestCancelTransaction_PartialAdminFee(t *testing.T) {
+	f := newCancellationFixture(time.Now().AddDate(0, 0, -2))
+	f.paymentRepo.payments = nil
+
+	result, err := f.uc.CancelTransaction(1, CancelRequest{ReasonCode: model.CancelReasonGoodsReturned, Amount: 1000000})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Transaction.AdminFee != 100000 || result.Refund != nil {
+		t.Errorf("Expected admin fee 100000 and nothing refunded, got %.2f and %+v", result.Transaction.AdminFee, result.Refund)
+	}
+
+	// 80.000 is received later; returning another 1.000.000 leaves 50.000 of admin fee, so 30.000 goes back
+	f.paymentRepo.Create(&model.Payment{TransactionID: 1, ConsumerID: 1, Type: model.PaymentTypeAdminFee, Amount: 80000, Reference: "ADM-RCPT-2"})
+	result, err = f.uc.CancelTransaction(1, CancelRequest{ReasonCode: model.CancelReasonGoodsReturned, Amount: 1000000})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Transaction.AdminFee != 50000 || result.Refund == nil || result.Refund.Amount != 30000 {
+		t.Errorf("Expected admin fee 50000 and 30000 refunded, got %.2f and %+v", result.Transaction.AdminFee, result.Refund)
+	}
+}
+
+// Test: Cancellation outside the window is refused
+func TestCancelTransaction_WindowClosed(t *testing.T) {
+	f := newCancellationFixture(time.Now().AddDate(0, 0, -10))
+
+	if _, err := f.uc.CancelTransaction(1, CancelRequest{ReasonCode: model.CancelReasonCoolingOff}); !errors.Is(err, ErrCancellationWindowClosed) {
+		t.Errorf("Expected ErrCancellationWindowClosed, got %v", err)
+	}
+	if f.usedAmount() != 3000000 {
+		t.Errorf("Expected limit untouched, got used amount %.2f", f.usedAmount())
+	}
+}
+
+// Test: Cancellation after an installment payment or with an unknown reason is refused
+func TestCancelTransaction_Refused(t *testing.T) {
+	f := newCancellationFixture(time.Now().AddDate(0, 0, -2))
+
+	if _, err := f.uc.CancelTransaction(1, CancelRequest{ReasonCode: "CHANGED_MIND"}); err == nil {
+		t.Error("Expected error for unknown reason code, got nil")
+	}
+
+	f.installmentRepo.installments[0].PaidAmount = 100000
+	if _, err := f.uc.CancelTransaction(1, CancelRequest{ReasonCode: model.CancelReasonCoolingOff}); err == nil {
+		t.Error("Expected error after an installment payment, got nil")
+	}
+}
+
+// Test: Contracts left unsigned past the signature timeout are cancelled and give their limit back
+func TestCancelUnsignedTransactions(t *testing.T) {
+	now := time.Now()
+	f := newCancellationFixture(now.AddDate(0, 0, -4))
+	unsigned, _ := f.transactionRepo.GetByID(1)
+	unsigned.Status = model.TransactionStatusPendingSignature
+	recent := bookTestContract(f.transactionRepo, f.installmentRepo, now.Add(-time.Hour))
+	recent.Status = model.TransactionStatusPendingSignature
+
+	if _, err := f.uc.CancelTransaction(1, CancelRequest{ReasonCode: model.CancelReasonSignatureExpired}); err == nil {
+		t.Error("Expected the system reason refused for a manual cancellation, got nil")
+	}
+
+	cancelled, err := f.uc.CancelUnsignedTransactions(now)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cancelled != 1 {
+		t.Errorf("Expected 1 contract cancelled, got %d", cancelled)
+	}
+	unsigned, _ = f.transactionRepo.GetByID(1)
+	recent, _ = f.transactionRepo.GetByID(recent.ID)
+	if unsigned.Status != model.TransactionStatusCancelled || recent.Status != model.TransactionStatusPendingSignature {
+		t.Errorf("Expected only the expired contract cancelled, got %s and %s", unsigned.Status, recent.Status)
+	}
+	if f.usedAmount() != 0 {
+		t.Errorf("Expected used amount 0, got %.2f", f.usedAmount())
+	}
+	installments, _ := f.installmentRepo.GetByTransactionID(1)
+	for _, installment := range installments {
+		if installment.Status != model.InstallmentStatusCancelled {
+			t.Errorf("Expected installment %d CANCELLED, got %s", installment.Sequence, installment.Status)
+		}
+	}
+	if len(f.refundRepo.refunds) != 1 || f.refundRepo.refunds[0].Amount != 150000 {
+		t.Errorf("Expected the admin fee received refunded, got %+v", f.refundRepo.refunds)
+	}
+}
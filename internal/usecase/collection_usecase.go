@@ -0,0 +1,557 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"main/internal/model"
+	"main/internal/repository"
+)
+
+var validContactChannels = map[string]bool{
+	model.ContactChannelPhone:      true,
+	model.ContactChannelWhatsApp:   true,
+	model.ContactChannelSMS:        true,
+	model.ContactChannelEmail:      true,
+	model.ContactChannelFieldVisit: true,
+}
+
+var validContactOutcomes = map[string]bool{
+	model.ContactOutcomeNoAnswer:     true,
+	model.ContactOutcomeWrongNumber:  true,
+	model.ContactOutcomeContacted:    true,
+	model.ContactOutcomePromiseToPay: true,
+	model.ContactOutcomeRefusedToPay: true,
+	model.ContactOutcomeAlreadyPaid:  true,
+}
+
+// CollectionPolicy holds the queue ranking and promise-to-pay terms. The priority of a case is
+// 0-100: the weighted sum of its DPD, outstanding amount and risk score, each scaled to its cap.
+type CollectionPolicy struct {
+	DPDWeight      float64
+	AmountWeight   float64
+	RiskWeight     float64
+	DPDCap         int     // DPD at which the DPD component is at its maximum
+	AmountCap      float64 // outstanding amount at which the amount component is at its maximum
+	MaxPromiseDays int     // furthest a promised payment date may be from the contact date
+}
+
+// DefaultCollectionPolicy weighs DPD 50%, amount 30% and risk 20%, capped at 90 DPD and Rp 10.000.000,
+// with promises up to 14 days ahead
+func DefaultCollectionPolicy() CollectionPolicy {
+	return CollectionPolicy{
+		DPDWeight:      0.5,
+		AmountWeight:   0.3,
+		RiskWeight:     0.2,
+		DPDCap:         90,
+		AmountCap:      10000000,
+		MaxPromiseDays: 14,
+	}
+}
+
+// QueueRefreshResult summarises one rebuild of the collections queue
+type QueueRefreshResult struct {
+	BusinessDate  time.Time `json:"business_date"`
+	CasesOpened   int       `json:"cases_opened"`
+	CasesUpdated  int       `json:"cases_updated"`
+	CasesResolved int       `json:"cases_resolved"`
+	OpenCases     int       `json:"open_cases"`
+}
+
+// PromiseCheckResult summarises one run of promise-to-pay breach detection
+type PromiseCheckResult struct {
+	BusinessDate    time.Time `json:"business_date"`
+	PromisesChecked int       `json:"promises_checked"`
+	PromisesKept    int       `json:"promises_kept"`
+	PromisesBroken  int       `json:"promises_broken"`
+}
+
+// ContactRequest records a contact attempt; a PROMISE_TO_PAY outcome also needs the promised amount and date
+type ContactRequest struct {
+	Collector      string    `json:"collector"`
+	Channel        string    `json:"channel"`
+	Outcome        string    `json:"outcome"`
+	Note           string    `json:"note"`
+	PromisedAmount float64   `json:"promised_amount"`
+	PromisedDate   time.Time `json:"promised_date"`
+}
+
+// ContactResult is the recorded attempt and the promise it produced, if any
+type ContactResult struct {
+	Attempt *model.ContactAttempt `json:"attempt"`
+	Promise *model.PromiseToPay   `json:"promise,omitempty"`
+}
+
+// CaseDetail is a collection case with its contact history and promises
+type CaseDetail struct {
+	Case     *model.CollectionCase  `json:"case"`
+	Attempts []model.ContactAttempt `json:"attempts"`
+	Promises []model.PromiseToPay   `json:"promises"`
+}
+
+// CollectionUsecase defines all business logic operations for the collections work queue
+type CollectionUsecase interface {
+	RefreshQueue(asOf time.Time) (*QueueRefreshResult, error)
+	GetQueue(collector string) ([]model.CollectionCase, error)
+	GetCase(id uint) (*CaseDetail, error)
+	AssignCase(id uint, collector string) (*model.CollectionCase, error)
+	AutoAssign(collectors []string) (int, error)
+	RecordContact(caseID uint, req ContactRequest) (*ContactResult, error)
+	CheckPromises(asOf time.Time) (*PromiseCheckResult, error)
+}
+
+// collectionUsecase is the implementation of CollectionUsecase
+type collectionUsecase struct {
+	transactionRepo repository.TransactionRepository
+	installmentRepo repository.InstallmentRepository
+	caseRepo        repository.CollectionCaseRepository
+	attemptRepo     repository.ContactAttemptRepository
+	promiseRepo     repository.PromiseToPayRepository
+	policy          CollectionPolicy
+	mu              sync.Mutex
+}
+
+// NewCollectionUsecase creates a new instance of CollectionUsecase
+func NewCollectionUsecase(
+	transactionRepo repository.TransactionRepository,
+	installmentRepo repository.InstallmentRepository,
+	caseRepo repository.CollectionCaseRepository,
+	attemptRepo repository.ContactAttemptRepository,
+	promiseRepo repository.PromiseToPayRepository,
+	policy CollectionPolicy,
+) CollectionUsecase {
+	return &collectionUsecase{
+		transactionRepo: transactionRepo,
+		installmentRepo: installmentRepo,
+		caseRepo:        caseRepo,
+		attemptRepo:     attemptRepo,
+		promiseRepo:     promiseRepo,
+		policy:          policy,
+	}
+}
+
+// RefreshQueue opens or updates a case for every ACTIVE or DEFAULTED contract with an overdue installment
+// and resolves the open cases of contracts that are no longer overdue. A contract that falls behind again
+// reopens its case, keeping the collector.
+func (u *collectionUsecase) RefreshQueue(asOf time.Time) (*QueueRefreshResult, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	date := dateOnly(asOf)
+	now := time.Now()
+	result := &QueueRefreshResult{BusinessDate: date}
+	overdue := make(map[uint]bool)
+
+	for _, status := range []string{model.TransactionStatusActive, model.TransactionStatusDefaulted} {
+		transactions, err := u.transactionRepo.GetByStatus(status)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range transactions {
+			transaction := &transactions[i]
+			installments, err := u.installmentRepo.GetByTransactionID(transaction.ID)
+			if err != nil {
+				return nil, err
+			}
+			dpd, overdueAmount, outstanding := collectionExposure(installments, date)
+			if dpd <= 0 {
+				continue
+			}
+			overdue[transaction.ID] = true
+
+			collectionCase, err := u.caseRepo.GetByTransactionID(transaction.ID)
+			isNew := err != nil
+			if isNew {
+				collectionCase = &model.CollectionCase{
+					TransactionID:  transaction.ID,
+					ConsumerID:     transaction.ConsumerID,
+					ContractNumber: transaction.ContractNumber,
+					CreatedAt:      now,
+				}
+			}
+			collectionCase.DaysPastDue = dpd
+			collectionCase.OverdueAmount = overdueAmount
+			collectionCase.OutstandingAmount = outstanding
+			collectionCase.RiskScore = transaction.RiskScore
+			collectionCase.Priority = u.priority(dpd, outstanding, transaction.RiskScore)
+			collectionCase.Status = model.CollectionCaseOpen
+			collectionCase.ResolvedAt = nil
+			collectionCase.UpdatedAt = now
+
+			if isNew {
+				if err := u.caseRepo.Create(collectionCase); err != nil {
+					return nil, err
+				}
+				result.CasesOpened++
+			} else {
+				if err := u.caseRepo.Update(collectionCase); err != nil {
+					return nil, err
+				}
+				result.CasesUpdated++
+			}
+		}
+	}
+
+	open, err := u.caseRepo.GetByStatus(model.CollectionCaseOpen)
+	if err != nil {
+		return nil, err
+	}
+	for i := range open {
+		collectionCase := &open[i]
+		if overdue[collectionCase.TransactionID] {
+			continue
+		}
+		collectionCase.Status = model.CollectionCaseResolved
+		collectionCase.ResolvedAt = &now
+		collectionCase.UpdatedAt = now
+		if err := u.caseRepo.Update(collectionCase); err != nil {
+			return nil, err
+		}
+		result.CasesResolved++
+	}
+	result.OpenCases = len(overdue)
+
+	log.Printf("✓ Antrian penagihan %s: %d kasus terbuka (%d baru, %d selesai)\n",
+		date.Format("2006-01-02"), result.OpenCases, result.CasesOpened, result.CasesResolved)
+	return result, nil
+}
+
+// GetQueue returns the open cases, highest priority first, optionally only those of one collector
+func (u *collectionUsecase) GetQueue(collector string) ([]model.CollectionCase, error) {
+	open, err := u.caseRepo.GetByStatus(model.CollectionCaseOpen)
+	if err != nil {
+		return nil, err
+	}
+
+	collector = strings.TrimSpace(collector)
+	queue := make([]model.CollectionCase, 0, len(open))
+	for _, collectionCase := range open {
+		if collector == "" || strings.EqualFold(collectionCase.AssignedTo, collector) {
+			queue = append(queue, collectionCase)
+		}
+	}
+	sortQueue(queue)
+	return queue, nil
+}
+
+func (u *collectionUsecase) GetCase(id uint) (*CaseDetail, error) {
+	collectionCase, err := u.caseRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("kasus penagihan tidak ditemukan")
+	}
+	attempts, err := u.attemptRepo.GetByCaseID(id)
+	if err != nil {
+		return nil, err
+	}
+	promises, err := u.promiseRepo.GetByCaseID(id)
+	if err != nil {
+		return nil, err
+	}
+	return &CaseDetail{Case: collectionCase, Attempts: attempts, Promises: promises}, nil
+}
+
+// AssignCase gives an open case to a collector, replacing any earlier assignment
+func (u *collectionUsecase) AssignCase(id uint, collector string) (*model.CollectionCase, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	collector = strings.TrimSpace(collector)
+	if collector == "" {
+		return nil, errors.New("nama kolektor tidak boleh kosong")
+	}
+	collectionCase, err := u.getOpenCase(id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	collectionCase.AssignedTo = collector
+	collectionCase.AssignedAt = &now
+	collectionCase.UpdatedAt = now
+	if err := u.caseRepo.Update(collectionCase); err != nil {
+		return nil, err
+	}
+	return collectionCase, nil
+}
+
+// AutoAssign hands the unassigned open cases, highest priority first, to the collector with the fewest open cases.
+// It returns the number of cases assigned.
+func (u *collectionUsecase) AutoAssign(collectors []string) (int, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	var names []string
+	load := make(map[string]int)
+	for _, collector := range collectors {
+		collector = strings.TrimSpace(collector)
+		if _, exists := load[collector]; collector == "" || exists {
+			continue
+		}
+		load[collector] = 0
+		names = append(names, collector)
+	}
+	if len(names) == 0 {
+		return 0, errors.New("daftar kolektor tidak boleh kosong")
+	}
+
+	open, err := u.caseRepo.GetByStatus(model.CollectionCaseOpen)
+	if err != nil {
+		return 0, err
+	}
+	sortQueue(open)
+
+	var unassigned []*model.CollectionCase
+	for i := range open {
+		if open[i].AssignedTo == "" {
+			unassigned = append(unassigned, &open[i])
+		} else if _, ok := load[open[i].AssignedTo]; ok {
+			load[open[i].AssignedTo]++
+		}
+	}
+
+	now := time.Now()
+	for _, collectionCase := range unassigned {
+		collector := names[0]
+		for _, name := range names[1:] {
+			if load[name] < load[collector] {
+				collector = name
+			}
+		}
+		collectionCase.AssignedTo = collector
+		collectionCase.AssignedAt = &now
+		collectionCase.UpdatedAt = now
+		if err := u.caseRepo.Update(collectionCase); err != nil {
+			return 0, err
+		}
+		load[collector]++
+	}
+
+	log.Printf("✓ %d kasus penagihan dibagikan ke %d kolektor\n", len(unassigned), len(names))
+	return len(unassigned), nil
+}
+
+// RecordContact logs a contact attempt on an open case. A PROMISE_TO_PAY outcome opens a promise
+// measured against the installment payments of the contract from this moment.
+func (u *collectionUsecase) RecordContact(caseID uint, req ContactRequest) (*ContactResult, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	req.Collector = strings.TrimSpace(req.Collector)
+	req.Channel = strings.ToUpper(strings.TrimSpace(req.Channel))
+	req.Outcome = strings.ToUpper(strings.TrimSpace(req.Outcome))
+	if req.Collector == "" {
+		return nil, errors.New("nama kolektor tidak boleh kosong")
+	}
+	if !validContactChannels[req.Channel] {
+		return nil, errors.New("saluran kontak tidak valid")
+	}
+	if !validContactOutcomes[req.Outcome] {
+		return nil, errors.New("hasil kontak tidak valid")
+	}
+
+	collectionCase, err := u.getOpenCase(caseID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var promise *model.PromiseToPay
+	if req.Outcome == model.ContactOutcomePromiseToPay {
+		if promise, err = u.newPromise(collectionCase, req, now); err != nil {
+			return nil, err
+		}
+	}
+
+	attempt := &model.ContactAttempt{
+		CaseID:      collectionCase.ID,
+		Collector:   req.Collector,
+		Channel:     req.Channel,
+		Outcome:     req.Outcome,
+		Note:        req.Note,
+		AttemptedAt: now,
+		CreatedAt:   now,
+	}
+	if err := u.attemptRepo.Create(attempt); err != nil {
+		return nil, err
+	}
+	if promise != nil {
+		promise.ContactAttemptID = attempt.ID
+		if err := u.promiseRepo.Create(promise); err != nil {
+			return nil, err
+		}
+	}
+
+	collectionCase.LastContactAt = &now
+	collectionCase.LastOutcome = req.Outcome
+	collectionCase.UpdatedAt = now
+	if err := u.caseRepo.Update(collectionCase); err != nil {
+		return nil, err
+	}
+	return &ContactResult{Attempt: attempt, Promise: promise}, nil
+}
+
+// newPromise validates the promise of a contact and takes the payment baseline of the contract
+func (u *collectionUsecase) newPromise(collectionCase *model.CollectionCase, req ContactRequest, now time.Time) (*model.PromiseToPay, error) {
+	if req.PromisedAmount <= 0 {
+		return nil, errors.New("jumlah janji bayar harus lebih dari 0")
+	}
+	promisedDate := dateOnly(req.PromisedDate)
+	today := dateOnly(now)
+	if promisedDate.Before(today) || promisedDate.After(today.AddDate(0, 0, u.policy.MaxPromiseDays)) {
+		return nil, fmt.Errorf("tanggal janji bayar harus antara hari ini dan %d hari ke depan", u.policy.MaxPromiseDays)
+	}
+
+	promises, err := u.promiseRepo.GetByCaseID(collectionCase.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range promises {
+		if existing.Status == model.PromiseStatusOpen {
+			return nil, errors.New("kasus masih memiliki janji bayar yang berjalan")
+		}
+	}
+
+	paid, err := u.paidToDate(collectionCase.TransactionID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.PromiseToPay{
+		CaseID:         collectionCase.ID,
+		TransactionID:  collectionCase.TransactionID,
+		PromisedAmount: roundMoney(req.PromisedAmount),
+		PromisedDate:   promisedDate,
+		PaidBaseline:   paid,
+		Status:         model.PromiseStatusOpen,
+		CreatedBy:      req.Collector,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// CheckPromises closes open promises: kept once the contract received the promised amount,
+// broken when the promised date has passed without it. Broken promises are counted on the case.
+func (u *collectionUsecase) CheckPromises(asOf time.Time) (*PromiseCheckResult, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	date := dateOnly(asOf)
+	result := &PromiseCheckResult{BusinessDate: date}
+
+	promises, err := u.promiseRepo.GetByStatus(model.PromiseStatusOpen)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range promises {
+		promise := &promises[i]
+		result.PromisesChecked++
+
+		paid, err := u.paidToDate(promise.TransactionID)
+		if err != nil {
+			return nil, err
+		}
+		promise.PaidAmount = roundMoney(math.Max(paid-promise.PaidBaseline, 0))
+
+		switch {
+		case promise.PaidAmount >= promise.PromisedAmount:
+			promise.Status = model.PromiseStatusKept
+			promise.ClosedAt = &now
+			result.PromisesKept++
+		case date.After(dateOnly(promise.PromisedDate)):
+			promise.Status = model.PromiseStatusBroken
+			promise.ClosedAt = &now
+			result.PromisesBroken++
+			if err := u.recordBrokenPromise(promise.CaseID, now); err != nil {
+				return nil, err
+			}
+		}
+		promise.UpdatedAt = now
+		if err := u.promiseRepo.Update(promise); err != nil {
+			return nil, err
+		}
+	}
+
+	if result.PromisesBroken > 0 {
+		log.Printf("⚠ Janji bayar %s: %d ditepati, %d diingkari\n", date.Format("2006-01-02"), result.PromisesKept, result.PromisesBroken)
+	}
+	return result, nil
+}
+
+func (u *collectionUsecase) recordBrokenPromise(caseID uint, now time.Time) error {
+	collectionCase, err := u.caseRepo.GetByID(caseID)
+	if err != nil {
+		return err
+	}
+	collectionCase.BrokenPromises++
+	collectionCase.UpdatedAt = now
+	return u.caseRepo.Update(collectionCase)
+}
+
+// paidToDate sums the payments received on the installments of a contract, rescheduled ones included
+func (u *collectionUsecase) paidToDate(transactionID uint) (float64, error) {
+	installments, err := u.installmentRepo.GetByTransactionID(transactionID)
+	if err != nil {
+		return 0, err
+	}
+	var paid float64
+	for _, installment := range installments {
+		paid += installment.PaidAmount
+	}
+	return roundMoney(paid), nil
+}
+
+func (u *collectionUsecase) getOpenCase(id uint) (*model.CollectionCase, error) {
+	collectionCase, err := u.caseRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("kasus penagihan tidak ditemukan")
+	}
+	if collectionCase.Status != model.CollectionCaseOpen {
+		return nil, errors.New("kasus penagihan sudah selesai")
+	}
+	return collectionCase, nil
+}
+
+// priority scores a case 0-100 from its DPD, outstanding amount and risk score
+func (u *collectionUsecase) priority(dpd int, outstanding float64, riskScore int) float64 {
+	score := u.policy.DPDWeight*math.Min(float64(dpd)/float64(u.policy.DPDCap), 1) +
+		u.policy.AmountWeight*math.Min(outstanding/u.policy.AmountCap, 1) +
+		u.policy.RiskWeight*math.Min(float64(riskScore)/100, 1)
+	return roundMoney(score * 100)
+}
+
+// collectionExposure returns the worst DPD, the overdue amount and the total still owed on the date.
+// Late fees count towards both amounts.
+func collectionExposure(installments []model.Installment, date time.Time) (int, float64, float64) {
+	maxDPD := 0
+	var overdue, outstanding float64
+	for _, installment := range installments {
+		owed := installment.Outstanding()
+		if owed <= 0 {
+			continue
+		}
+		outstanding += owed + installment.OutstandingLateFee()
+		if dpd := daysBetween(installment.DueDate, date); dpd > 0 {
+			maxDPD = max(maxDPD, dpd)
+			overdue += owed + installment.OutstandingLateFee()
+		}
+	}
+	return maxDPD, roundMoney(overdue), roundMoney(outstanding)
+}
+
+// sortQueue orders cases by priority, then DPD, highest first
+func sortQueue(cases []model.CollectionCase) {
+	sort.SliceStable(cases, func(i, j int) bool {
+		if cases[i].Priority != cases[j].Priority {
+			return cases[i].Priority > cases[j].Priority
+		}
+		return cases[i].DaysPastDue > cases[j].DaysPastDue
+	})
+}
@@ -0,0 +1,269 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"main/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// MockCollectionCaseRepository for testing
+type MockCollectionCaseRepository struct {
+	cases []*model.CollectionCase
+}
+
+func (m *MockCollectionCaseRepository) Create(collectionCase *model.CollectionCase) error {
+	collectionCase.ID = uint(len(m.cases) + 1)
+	m.cases = append(m.cases, collectionCase)
+	return nil
+}
+
+func (m *MockCollectionCaseRepository) GetByID(id uint) (*model.CollectionCase, error) {
+	for _, collectionCase := range m.cases {
+		if collectionCase.ID == id {
+			return collectionCase, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockCollectionCaseRepository) GetByTransactionID(transactionID uint) (*model.CollectionCase, error) {
+	for _, collectionCase := range m.cases {
+		if collectionCase.TransactionID == transactionID {
+			return collectionCase, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockCollectionCaseRepository) GetByStatus(status string) ([]model.CollectionCase, error) {
+	var cases []model.CollectionCase
+	for _, collectionCase := range m.cases {
+		if collectionCase.Status == status {
+			cases = append(cases, *collectionCase)
+		}
+	}
+	return cases, nil
+}
+
+func (m *MockCollectionCaseRepository) Update(collectionCase *model.CollectionCase) error {
+	for i := range m.cases {
+		if m.cases[i].ID == collectionCase.ID {
+			*m.cases[i] = *collectionCase
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+// MockContactAttemptRepository for testing
+type MockContactAttemptRepository struct {
+	attempts []model.ContactAttempt
+}
+
+func (m *MockContactAttemptRepository) Create(attempt *model.ContactAttempt) error {
+	attempt.ID = uint(len(m.attempts) + 1)
+	m.attempts = append(m.attempts, *attempt)
+	return nil
+}
+
+func (m *MockContactAttemptRepository) GetByCaseID(caseID uint) ([]model.ContactAttempt, error) {
+	var attempts []model.ContactAttempt
+	for _, attempt := range m.attempts {
+		if attempt.CaseID == caseID {
+			attempts = append(attempts, attempt)
+		}
+	}
+	return attempts, nil
+}
+
+// MockPromiseToPayRepository for testing
+type MockPromiseToPayRepository struct {
+	promises []*model.PromiseToPay
+}
+
+func (m *MockPromiseToPayRepository) Create(promise *model.PromiseToPay) error {
+	promise.ID = uint(len(m.promises) + 1)
+	m.promises = append(m.promises, promise)
+	return nil
+}
+
+func (m *MockPromiseToPayRepository) GetByCaseID(caseID uint) ([]model.PromiseToPay, error) {
+	var promises []model.PromiseToPay
+	for _, promise := range m.promises {
+		if promise.CaseID == caseID {
+			promises = append(promises, *promise)
+		}
+	}
+	return promises, nil
+}
+
+func (m *MockPromiseToPayRepository) GetByStatus(status string) ([]model.PromiseToPay, error) {
+	var promises []model.PromiseToPay
+	for _, promise := range m.promises {
+		if promise.Status == status {
+			promises = append(promises, *promise)
+		}
+	}
+	return promises, nil
+}
+
+func (m *MockPromiseToPayRepository) Update(promise *model.PromiseToPay) error {
+	for i := range m.promises {
+		if m.promises[i].ID == promise.ID {
+			*m.promises[i] = *promise
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+type collectionFixture struct {
+	uc              CollectionUsecase
+	transactionRepo *MockTransactionRepository
+	installmentRepo *MockInstallmentRepository
+	caseRepo        *MockCollectionCaseRepository
+}
+
+// collectionDate is the business date of the queue: the test contract booked on 1 Jan 2026 is 5 days
+// overdue and the riskier one booked on 17 Nov 2025 is 51 days overdue
+var collectionDate = time.Date(2026, 2, 6, 0, 0, 0, 0, time.Local)
+
+// newCollectionFixture books both contracts
+func newCollectionFixture() *collectionFixture {
+	f := &collectionFixture{
+		transactionRepo: NewMockTransactionRepository(),
+		installmentRepo: &MockInstallmentRepository{},
+		caseRepo:        &MockCollectionCaseRepository{},
+	}
+	bookTestContract(f.transactionRepo, f.installmentRepo, time.Date(2026, 1, 1, 10, 0, 0, 0, time.Local))
+	risky := bookTestContract(f.transactionRepo, f.installmentRepo, time.Date(2025, 11, 17, 10, 0, 0, 0, time.Local))
+	risky.RiskScore = 80
+
+	f.uc = NewCollectionUsecase(f.transactionRepo, f.installmentRepo, f.caseRepo,
+		&MockContactAttemptRepository{}, &MockPromiseToPayRepository{}, DefaultCollectionPolicy())
+	return f
+}
+
+// payInstallments records a payment on every installment of the contract, oldest first
+func (f *collectionFixture) payInstallments(transactionID uint, amount float64) {
+	for _, installment := range f.installmentRepo.installments {
+		if installment.TransactionID != transactionID || amount <= 0 {
+			continue
+		}
+		paid := min(amount, installment.Outstanding())
+		installment.PaidAmount += paid
+		if installment.Outstanding() == 0 {
+			installment.Status = model.InstallmentStatusPaid
+		}
+		amount -= paid
+	}
+}
+
+// Test: Overdue contracts are queued by priority and resolved once they catch up
+func TestRefreshQueue(t *testing.T) {
+	f := newCollectionFixture()
+
+	result, err := f.uc.RefreshQueue(collectionDate)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.CasesOpened != 2 || result.OpenCases != 2 {
+		t.Fatalf("Expected 2 cases opened, got %+v", result)
+	}
+
+	queue, _ := f.uc.GetQueue("")
+	if len(queue) != 2 || queue[0].TransactionID != 2 {
+		t.Fatalf("Expected the riskier, longer overdue contract first, got %+v", queue)
+	}
+	first := queue[1]
+	if first.DaysPastDue != 5 || first.OverdueAmount != 1100000 || first.OutstandingAmount != 3300000 {
+		t.Errorf("Expected 5 DPD with 1100000 overdue of 3300000, got %+v", first)
+	}
+	// 50% x 5/90 + 30% x 3.300.000/10.000.000 + 20% x 0
+	if first.Priority != 12.68 {
+		t.Errorf("Expected priority 12.68, got %.2f", first.Priority)
+	}
+
+	f.payInstallments(1, 1100000)
+	result, _ = f.uc.RefreshQueue(collectionDate)
+	if result.CasesResolved != 1 || result.OpenCases != 1 {
+		t.Errorf("Expected the paid contract resolved, got %+v", result)
+	}
+	if collectionCase, _ := f.caseRepo.GetByID(first.ID); collectionCase.Status != model.CollectionCaseResolved {
+		t.Errorf("Expected case RESOLVED, got %s", collectionCase.Status)
+	}
+}
+
+// Test: Unassigned cases go to the collector with the fewest open cases
+func TestAutoAssign(t *testing.T) {
+	f := newCollectionFixture()
+	f.uc.RefreshQueue(collectionDate)
+
+	if _, err := f.uc.AutoAssign([]string{" "}); err == nil {
+		t.Error("Expected error without collectors, got nil")
+	}
+	if _, err := f.uc.AssignCase(1, "desk-1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	assigned, err := f.uc.AutoAssign([]string{"desk-1", "desk-2"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if assigned != 1 {
+		t.Errorf("Expected 1 case assigned, got %d", assigned)
+	}
+	if queue, _ := f.uc.GetQueue("desk-2"); len(queue) != 1 || queue[0].TransactionID != 2 {
+		t.Errorf("Expected desk-2 to get the remaining case, got %+v", queue)
+	}
+}
+
+// Test: A promise to pay is kept by payments after it was made and broken once its date passes
+func TestPromiseToPay(t *testing.T) {
+	f := newCollectionFixture()
+	f.uc.RefreshQueue(collectionDate)
+	today := dateOnly(time.Now())
+
+	contact := ContactRequest{Collector: "desk-1", Channel: "PHONE", Outcome: "PROMISE_TO_PAY", PromisedAmount: 1100000}
+	contact.PromisedDate = today.AddDate(0, 0, 30)
+	if _, err := f.uc.RecordContact(1, contact); err == nil {
+		t.Error("Expected error for a promise beyond the allowed days, got nil")
+	}
+	if _, err := f.uc.RecordContact(1, ContactRequest{Collector: "desk-1", Channel: "PIGEON", Outcome: "NO_ANSWER"}); err == nil {
+		t.Error("Expected error for unknown channel, got nil")
+	}
+
+	contact.PromisedDate = today.AddDate(0, 0, 3)
+	result, err := f.uc.RecordContact(1, contact)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Promise == nil || result.Promise.ContactAttemptID != result.Attempt.ID {
+		t.Fatalf("Expected promise linked to the contact attempt, got %+v", result)
+	}
+	if _, err := f.uc.RecordContact(1, contact); err == nil {
+		t.Error("Expected error for a second open promise, got nil")
+	}
+	if _, err := f.uc.RecordContact(2, contact); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	f.payInstallments(1, 1100000)
+	f.payInstallments(2, 500000)
+	check, err := f.uc.CheckPromises(today.AddDate(0, 0, 4))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if check.PromisesChecked != 2 || check.PromisesKept != 1 || check.PromisesBroken != 1 {
+		t.Errorf("Expected 1 kept and 1 broken promise, got %+v", check)
+	}
+
+	detail, _ := f.uc.GetCase(2)
+	if detail.Case.BrokenPromises != 1 || detail.Case.LastOutcome != model.ContactOutcomePromiseToPay ||
+		len(detail.Attempts) != 1 || detail.Promises[0].PaidAmount != 500000 {
+		t.Errorf("Expected one broken promise with 500000 paid, got %+v", detail)
+	}
+}
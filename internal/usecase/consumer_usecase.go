@@ -2,11 +2,13 @@ package usecase
 
 import (
 	"errors"
+	"fmt"
 	"log"
-	"regexp"
 	"sync"
 	"time"
 
+	"main/internal/fraud"
+	"main/internal/ktp"
 	"main/internal/model"
 	"main/internal/repository"
 
@@ -32,33 +34,89 @@ type ConsumerLimitUsecase interface {
 
 // TransactionUsecase defines all business logic operations for Transaction
 type TransactionUsecase interface {
+	ContractActivator
 	CreateTransaction(transaction *model.Transaction) error
 	GetTransaction(id uint) (*model.Transaction, error)
 	GetConsumerTransactions(consumerID uint) ([]model.Transaction, error)
 	UpdateTransactionStatus(id uint, status string) error
+	GetFlaggedTransactions() ([]model.Transaction, error)
+	ReviewFlaggedTransaction(id uint, approve bool, reviewer string) (*model.Transaction, error)
+	AuthorizeHold(hold *model.LimitHold, ttl time.Duration) error
+	CaptureHold(holdID uint, transaction *model.Transaction) error
+	VoidHold(holdID uint) (*model.LimitHold, error)
+	GetHold(holdID uint) (*model.LimitHold, error)
+	ReleaseExpiredHolds(now time.Time) (int, error)
+	RecordUpfrontPayment(id uint, req UpfrontPaymentRequest) (*model.Payment, error)
+}
+
+// ErrNIKRegionUnlisted is returned for a NIK whose regency or district the region table cannot check yet
+var ErrNIKRegionUnlisted = errors.New("kode kabupaten/kota atau kecamatan pada NIK belum tercantum di tabel wilayah")
+
+// NIKPolicy holds how the region codes of a NIK are checked at registration. The embedded Kemendagri
+// table lists all provinces, regencies only for DKI Jakarta, Jawa Barat and Banten, and no districts yet.
+type NIKPolicy struct {
+	SkipUnlistedRegencies bool // accept a regency code of a province the table has no regencies for, on the structural check alone
+	SkipUnlistedDistricts bool // accept a district code of a regency the table has no districts for, on the structural check alone
+}
+
+// DefaultNIKPolicy accepts the levels the embedded table does not list yet, so registrations outside
+// the listed provinces keep working until the full table is shipped
+func DefaultNIKPolicy() NIKPolicy {
+	return NIKPolicy{
+		SkipUnlistedRegencies: true,
+		SkipUnlistedDistricts: true,
+	}
 }
 
 // consumerUsecase is the implementation of ConsumerUsecase
 type consumerUsecase struct {
-	repo repository.ConsumerRepository
-	mu   sync.RWMutex
+	repo      repository.ConsumerRepository
+	rules     EligibilityRules
+	nikPolicy NIKPolicy
+	screener  WatchlistScreener
+	mu        sync.RWMutex
 }
 
 // NewConsumerUsecase creates a new instance of ConsumerUsecase
-func NewConsumerUsecase(repo repository.ConsumerRepository) ConsumerUsecase {
-	return &consumerUsecase{repo: repo}
+func NewConsumerUsecase(
+	repo repository.ConsumerRepository,
+	rules EligibilityRules,
+	nikPolicy NIKPolicy,
+	screener WatchlistScreener,
+) ConsumerUsecase {
+	return &consumerUsecase{
+		repo:      repo,
+		rules:     rules,
+		nikPolicy: nikPolicy,
+		screener:  screener,
+	}
 }
 
-// ValidateNIK validates Indonesian ID number format
+// ValidateNIK validates Indonesian ID number format and region code
 func (u *consumerUsecase) ValidateNIK(nik string) error {
-	if len(nik) != 16 {
-		return errors.New("NIK must be 16 characters")
+	_, err := u.parseNIK(nik)
+	return err
+}
+
+// parseNIK decodes a NIK and applies the policy to the region levels the table could not check
+func (u *consumerUsecase) parseNIK(nik string) (*model.NIKInfo, error) {
+	info, err := ktp.ParseNIK(nik)
+	if err != nil {
+		return nil, err
 	}
-	matched, err := regexp.MatchString("^[0-9]{16}$", nik)
-	if err != nil || !matched {
-		return errors.New("NIK must contain only numbers")
+	if !info.RegencyChecked {
+		if !u.nikPolicy.SkipUnlistedRegencies {
+			return nil, ErrNIKRegionUnlisted
+		}
+		log.Printf("⚠ Kabupaten/kota %s.%s tidak tercantum di tabel wilayah, hanya dicek strukturnya\n", info.ProvinceCode, info.RegencyCode)
 	}
-	return nil
+	if !info.DistrictChecked {
+		if !u.nikPolicy.SkipUnlistedDistricts {
+			return nil, ErrNIKRegionUnlisted
+		}
+		log.Printf("⚠ Kecamatan %s.%s.%s tidak tercantum di tabel wilayah, hanya dicek strukturnya\n", info.ProvinceCode, info.RegencyCode, info.DistrictCode)
+	}
+	return info, nil
 }
 
 // RegisterConsumer registers a new consumer with validation
@@ -71,8 +129,9 @@ func (u *consumerUsecase) RegisterConsumer(consumer *model.Consumer) error {
 		return errors.New("nama lengkap, nama sah, dan NIK tidak boleh kosong")
 	}
 
-	// Validation 2: Validate NIK format
-	if err := u.ValidateNIK(consumer.NIK); err != nil {
+	// Validation 2: Validate NIK format and decode its embedded data
+	nikInfo, err := u.parseNIK(consumer.NIK)
+	if err != nil {
 		return err
 	}
 
@@ -91,6 +150,53 @@ func (u *consumerUsecase) RegisterConsumer(consumer *model.Consumer) error {
 		return errors.New("tanggal lahir tidak valid")
 	}
 
+	// Validation 6: Date of birth must match the one encoded in the NIK
+	if consumer.DateOfBirth.IsZero() {
+		consumer.DateOfBirth = nikInfo.DateOfBirth
+	} else if !sameDate(nikInfo.DateOfBirth, consumer.DateOfBirth) {
+		return errors.New("tanggal lahir tidak sesuai dengan NIK")
+	}
+
+	consumer.Gender = nikInfo.Gender
+	consumer.NIKInfo = nikInfo
+
+	// Validation 7: Marital status and age eligibility
+	if consumer.MaritalStatus == "" {
+		consumer.MaritalStatus = model.MaritalStatusSingle
+	}
+	validMaritalStatuses := map[string]bool{
+		model.MaritalStatusSingle: true, model.MaritalStatusMarried: true,
+		model.MaritalStatusDivorced: true, model.MaritalStatusWidowed: true,
+	}
+	if !validMaritalStatuses[consumer.MaritalStatus] {
+		return errors.New("status perkawinan tidak valid")
+	}
+	if err := u.rules.CheckRegistration(consumer, time.Now()); err != nil {
+		return err
+	}
+
+	// Validation 8: Blacklist / watchlist screening
+	screening, err := u.screener.Screen(ScreeningSubject{
+		NIK:         consumer.NIK,
+		FullName:    consumer.FullName,
+		LegalName:   consumer.LegalName,
+		DateOfBirth: consumer.DateOfBirth,
+		Context:     ScreeningContextRegistration,
+	})
+	if err != nil {
+		return err
+	}
+	if screening.Decision == model.ScreeningBlock {
+		return ErrWatchlistBlocked
+	}
+
+	// New consumers cannot use credit until KYC verification passes;
+	// watchlist matches go straight to the analysts
+	consumer.KYCStatus = model.KYCStatusPending
+	if screening.Decision == model.ScreeningReview {
+		consumer.KYCStatus = model.KYCStatusManualReview
+	}
+
 	consumer.CreatedAt = time.Now()
 	consumer.UpdatedAt = time.Now()
 
@@ -101,13 +207,39 @@ func (u *consumerUsecase) RegisterConsumer(consumer *model.Consumer) error {
 func (u *consumerUsecase) GetConsumer(id uint) (*model.Consumer, error) {
 	u.mu.RLock()
 	defer u.mu.RUnlock()
-	return u.repo.GetByID(id)
+
+	consumer, err := u.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	attachNIKInfo(consumer)
+	return consumer, nil
 }
 
 func (u *consumerUsecase) GetConsumerByNIK(nik string) (*model.Consumer, error) {
 	u.mu.RLock()
 	defer u.mu.RUnlock()
-	return u.repo.GetByNIK(nik)
+
+	consumer, err := u.repo.GetByNIK(nik)
+	if err != nil {
+		return nil, err
+	}
+	attachNIKInfo(consumer)
+	return consumer, nil
+}
+
+// attachNIKInfo exposes the decoded NIK fields on the consumer response
+func attachNIKInfo(consumer *model.Consumer) {
+	if info, err := ktp.ParseNIK(consumer.NIK); err == nil {
+		consumer.NIKInfo = info
+	}
+}
+
+// sameDate reports whether a and b fall on the same calendar date
+func sameDate(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
 }
 
 func (u *consumerUsecase) UpdateConsumer(consumer *model.Consumer) error {
@@ -135,13 +267,20 @@ func (u *consumerUsecase) DeleteConsumer(id uint) error {
 
 // consumerLimitUsecase is the implementation of ConsumerLimitUsecase
 type consumerLimitUsecase struct {
-	limitRepo repository.ConsumerLimitRepository
-	mu        sync.RWMutex
+	limitRepo    repository.ConsumerLimitRepository
+	consumerRepo repository.ConsumerRepository
+	mu           sync.RWMutex
 }
 
 // NewConsumerLimitUsecase creates a new instance of ConsumerLimitUsecase
-func NewConsumerLimitUsecase(limitRepo repository.ConsumerLimitRepository) ConsumerLimitUsecase {
-	return &consumerLimitUsecase{limitRepo: limitRepo}
+func NewConsumerLimitUsecase(
+	limitRepo repository.ConsumerLimitRepository,
+	consumerRepo repository.ConsumerRepository,
+) ConsumerLimitUsecase {
+	return &consumerLimitUsecase{
+		limitRepo:    limitRepo,
+		consumerRepo: consumerRepo,
+	}
 }
 
 // AssignLimit assigns a credit limit to a consumer (ACID transaction)
@@ -150,8 +289,7 @@ func (u *consumerLimitUsecase) AssignLimit(limit *model.ConsumerLimit) error {
 	defer u.mu.Unlock()
 
 	// Validation 1: Check valid tenor
-	validTenors := map[int]bool{1: true, 2: true, 3: true, 6: true}
-	if !validTenors[limit.Tenor] {
+	if !limitTenors[limit.Tenor] {
 		return errors.New("tenor harus 1, 2, 3, atau 6 bulan")
 	}
 
@@ -165,7 +303,19 @@ func (u *consumerLimitUsecase) AssignLimit(limit *model.ConsumerLimit) error {
 		return errors.New("consumer ID tidak valid")
 	}
 
+	// Validation 4: Consumer must have passed KYC
+	consumer, err := getVerifiedConsumer(u.consumerRepo, limit.ConsumerID)
+	if err != nil {
+		return err
+	}
+
+	// Validation 5: No new limits after a write-off
+	if consumer.WrittenOff {
+		return ErrConsumerWrittenOff
+	}
+
 	limit.UsedAmount = 0
+	limit.HeldAmount = 0
 	limit.CreatedAt = time.Now()
 	limit.UpdatedAt = time.Now()
 
@@ -193,14 +343,56 @@ func (u *consumerLimitUsecase) UpdateLimit(limit *model.ConsumerLimit) error {
 		return errors.New("limit ID tidak valid")
 	}
 
+	// A consumer with a written-off contract keeps existing limits but cannot get them raised
+	existing, err := u.limitRepo.GetByID(limit.ID)
+	if err != nil {
+		return errors.New("limit tidak ditemukan")
+	}
+	if limit.LimitAmount > existing.LimitAmount {
+		consumer, err := u.consumerRepo.GetByID(existing.ConsumerID)
+		if err != nil {
+			return errors.New("konsumen tidak ditemukan")
+		}
+		if consumer.WrittenOff {
+			return ErrConsumerWrittenOff
+		}
+	}
+
 	limit.UpdatedAt = time.Now()
 	return u.limitRepo.Update(limit)
 }
 
+// TransactionPolicy holds what a new contract needs from the consumer before it is booked and activated
+type TransactionPolicy struct {
+	RequireOTP       bool // the limit is only used with a consumer OTP challenge bound to the consumer, merchant and amount
+	RequireSignature bool // contracts wait in PENDING_SIGNATURE until the consumer signs the contract document
+}
+
+// DefaultTransactionPolicy activates contracts only once the consumer has signed them.
+// OTP consent at checkout is off until merchants integrate the challenge step.
+func DefaultTransactionPolicy() TransactionPolicy {
+	return TransactionPolicy{
+		RequireSignature: true,
+	}
+}
+
 // transactionUsecase is the implementation of TransactionUsecase
 type transactionUsecase struct {
 	transactionRepo repository.TransactionRepository
 	limitRepo       repository.ConsumerLimitRepository
+	consumerRepo    repository.ConsumerRepository
+	rules           EligibilityRules
+	screener        WatchlistScreener
+	fraudEngine     *fraud.Engine
+	assessmentRepo  repository.FraudAssessmentRepository
+	installmentRepo repository.InstallmentRepository
+	holdRepo        repository.LimitHoldRepository
+	pricer          ProductPricer
+	merchantRepo    repository.MerchantRepository
+	transactor      repository.Transactor
+	notifier        EventNotifier
+	consent         OTPVerifier
+	policy          TransactionPolicy
 	mu              sync.Mutex // Mutex for concurrent transaction handling
 }
 
@@ -208,10 +400,36 @@ type transactionUsecase struct {
 func NewTransactionUsecase(
 	transactionRepo repository.TransactionRepository,
 	limitRepo repository.ConsumerLimitRepository,
+	consumerRepo repository.ConsumerRepository,
+	rules EligibilityRules,
+	screener WatchlistScreener,
+	fraudEngine *fraud.Engine,
+	assessmentRepo repository.FraudAssessmentRepository,
+	installmentRepo repository.InstallmentRepository,
+	holdRepo repository.LimitHoldRepository,
+	pricer ProductPricer,
+	merchantRepo repository.MerchantRepository,
+	transactor repository.Transactor,
+	notifier EventNotifier,
+	consent OTPVerifier,
+	policy TransactionPolicy,
 ) TransactionUsecase {
 	return &transactionUsecase{
 		transactionRepo: transactionRepo,
 		limitRepo:       limitRepo,
+		consumerRepo:    consumerRepo,
+		rules:           rules,
+		screener:        screener,
+		fraudEngine:     fraudEngine,
+		assessmentRepo:  assessmentRepo,
+		installmentRepo: installmentRepo,
+		holdRepo:        holdRepo,
+		pricer:          pricer,
+		merchantRepo:    merchantRepo,
+		transactor:      transactor,
+		notifier:        notifier,
+		consent:         consent,
+		policy:          policy,
 	}
 }
 
@@ -220,12 +438,27 @@ func NewTransactionUsecase(
 func (u *transactionUsecase) CreateTransaction(transaction *model.Transaction) error {
 	u.mu.Lock()
 	defer u.mu.Unlock()
+	return u.createTransaction(transaction, nil)
+}
 
+// createTransaction validates and books a transaction, capturing the hold when one is given; the caller holds u.mu
+func (u *transactionUsecase) createTransaction(transaction *model.Transaction, hold *model.LimitHold) error {
 	// Validation 1: Check required fields
 	if transaction.ConsumerID == 0 || transaction.ContractNumber == "" {
 		return errors.New("consumer ID dan nomor kontrak tidak boleh kosong")
 	}
 
+	// Validation 1b: Consumer must have passed KYC
+	consumer, err := getVerifiedConsumer(u.consumerRepo, transaction.ConsumerID)
+	if err != nil {
+		return err
+	}
+
+	// Validation 1c: Originating merchant must be ACTIVE
+	if err := checkMerchant(u.merchantRepo, transaction.MerchantID, transaction.BranchID); err != nil {
+		return err
+	}
+
 	// Validation 2: Check contract number uniqueness
 	existingTx, err := u.transactionRepo.GetByContractNumber(transaction.ContractNumber)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
@@ -240,10 +473,39 @@ func (u *transactionUsecase) CreateTransaction(transaction *model.Transaction) e
 		return errors.New("OTR harus lebih dari 0")
 	}
 
-	// Validation 4: Check tenor
-	validTenors := map[int]bool{1: true, 2: true, 3: true, 6: true}
-	if !validTenors[transaction.Tenor] {
-		return errors.New("tenor harus 1, 2, 3, atau 6 bulan")
+	// Validation 4: Product tenor, OTR range, minimum down payment and server-side pricing
+	resetServerFields(transaction)
+	pricing, err := u.pricer.Price(transaction.ProductCode, transaction.Tenor, transaction.OTR, transaction.DownPayment, time.Now())
+	if err != nil {
+		return err
+	}
+	transaction.ProductCode = pricing.ProductCode
+	transaction.PromoCode = pricing.PromoCode
+	transaction.InterestRate = pricing.InterestRate
+	transaction.InterestAmount = pricing.InterestAmount
+	transaction.AdminFee = pricing.AdminFee
+	transaction.InstallmentAmount = pricing.InstallmentAmount
+
+	// Validation 5: Age eligibility at the end of the tenor
+	if err := u.rules.CheckTransaction(consumer, transaction.Tenor, time.Now()); err != nil {
+		return err
+	}
+
+	// Validation 6: Blacklist / watchlist screening (lists change after registration)
+	screening, err := u.screener.Screen(ScreeningSubject{
+		ConsumerID:  consumer.ID,
+		NIK:         consumer.NIK,
+		FullName:    consumer.FullName,
+		LegalName:   consumer.LegalName,
+		DateOfBirth: consumer.DateOfBirth,
+		Context:     ScreeningContextTransaction,
+		Reference:   transaction.ContractNumber,
+	})
+	if err != nil {
+		return err
+	}
+	if err := screeningError(screening); err != nil {
+		return err
 	}
 
 	// CRITICAL: Check and deduct from consumer limit (ACID compliance)
@@ -252,23 +514,144 @@ func (u *transactionUsecase) CreateTransaction(transaction *model.Transaction) e
 		return errors.New("limit tidak ditemukan untuk tenor tersebut")
 	}
 
-	// Check if limit is sufficient
-	if limit.UsedAmount+transaction.OTR > limit.LimitAmount {
+	// Check if limit is sufficient for the financed amount (open holds are reserved for their merchants,
+	// the hold being captured is released by this booking)
+	available := limit.Available()
+	if hold != nil {
+		available += hold.Amount
+	}
+	if transaction.FinancedAmount() > available {
 		return errors.New("limit tidak cukup untuk transaksi ini")
 	}
 
-	// Deduct the limit (Atomicity - ensure this is transactional)
-	limit.UsedAmount += transaction.OTR
-	if err := u.limitRepo.Update(limit); err != nil {
-		return errors.New("gagal update limit")
+	// Validation 7: Fraud velocity rules
+	assessment, err := u.assessFraud(consumer, limit, transaction)
+	if err != nil {
+		return err
+	}
+	if assessment.Decision == fraud.DecisionDeny {
+		return ErrTransactionDenied
+	}
+
+	// Validation 8: Consumer consent, an OTP challenge for this consumer, merchant and financed amount.
+	// A challenge given when none is required is still checked, since it is kept as evidence.
+	var consent *OTPConsent
+	if u.policy.RequireOTP || transaction.OTPChallengeID != 0 {
+		consent = &OTPConsent{
+			ChallengeID: transaction.OTPChallengeID,
+			Code:        transaction.OTPCode,
+			ConsumerID:  transaction.ConsumerID,
+			MerchantID:  transaction.MerchantID,
+			Amount:      transaction.FinancedAmount(),
+			Reference:   transaction.ContractNumber,
+		}
+		transaction.OTPCode = ""
+		if err := u.consent.VerifyChallenge(*consent); err != nil {
+			return err
+		}
 	}
 
-	transaction.Status = "ACTIVE"
+	transaction.Status = model.TransactionStatusActive
+	if assessment.Decision == fraud.DecisionReview {
+		transaction.Status = model.TransactionStatusPendingReview
+	} else if u.policy.RequireSignature {
+		transaction.Status = model.TransactionStatusPendingSignature
+	} else if transaction.DownPayment > 0 {
+		// The down payment receipt can only be posted against the booked contract
+		transaction.Status = model.TransactionStatusPendingDownPayment
+	}
 	transaction.CreatedAt = time.Now()
 	transaction.UpdatedAt = time.Now()
 
 	log.Println("✓ Transaction validation OK. Creating transaction...")
-	return u.transactionRepo.Create(transaction)
+	// The limit, the contract, its schedule and its activation entry commit together or not at all
+	err = u.transactor.Transaction(func(tx *repository.Repositories) error {
+		// Deduct the limit. Transactions held for fraud review or waiting for the signature keep the limit reserved until then.
+		// The row is read again under lock: other bookings, holds and releases may have moved it since the check above.
+		limit, err := tx.Limits.GetByConsumerAndTenorForUpdate(transaction.ConsumerID, transaction.Tenor)
+		if err != nil {
+			return errors.New("limit tidak ditemukan untuk tenor tersebut")
+		}
+		available := limit.Available()
+		if hold != nil {
+			available += hold.Amount
+		}
+		if transaction.FinancedAmount() > available {
+			return errors.New("limit tidak cukup untuk transaksi ini")
+		}
+		limit.UsedAmount += transaction.FinancedAmount()
+		if hold != nil {
+			limit.HeldAmount -= hold.Amount
+			if limit.HeldAmount < 0 {
+				limit.HeldAmount = 0
+			}
+		}
+		limit.UpdatedAt = time.Now()
+		if err := tx.Limits.UpdateUsage(limit); err != nil {
+			return errors.New("gagal update limit")
+		}
+
+		if err := tx.Transactions.Create(transaction); err != nil {
+			return err
+		}
+		schedule := buildInstallmentSchedule(transaction.ID, transaction.FinancedAmount(), transaction.InterestAmount, transaction.Tenor, transaction.CreatedAt)
+		if err := tx.Installments.CreateBatch(schedule); err != nil {
+			return errors.New("gagal membuat jadwal angsuran")
+		}
+		if err := recordAssessment(tx.FraudAssessments, transaction, assessment); err != nil {
+			return err
+		}
+
+		if hold != nil {
+			now := time.Now()
+			hold.Status = model.LimitHoldStatusCaptured
+			hold.TransactionID = transaction.ID
+			hold.ClosedAt = &now
+			hold.UpdatedAt = now
+			if err := tx.LimitHolds.Update(hold); err != nil {
+				return err
+			}
+		}
+
+		if transaction.Status == model.TransactionStatusActive {
+			if err := u.activate(tx, transaction); err != nil {
+				return err
+			}
+			if err := tx.Transactions.Update(transaction); err != nil {
+				return err
+			}
+		}
+
+		// The consent is used up with the booking: a booking rolled back leaves the challenge PENDING
+		if consent != nil {
+			return u.consent.ConsumeChallenge(tx.OTPChallenges, *consent)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if transaction.Status == model.TransactionStatusActive {
+		u.notifier.NotifyTransactionBooked(transaction)
+	}
+	return nil
+}
+
+// resetServerFields clears what the request body may carry but only the server sets: risk, aging,
+// restructuring and activation state start from a fresh contract
+func resetServerFields(transaction *model.Transaction) {
+	transaction.ID = 0
+	transaction.Consumer = model.Consumer{}
+	transaction.Status = ""
+	transaction.RiskScore = 0
+	transaction.RiskDecision = ""
+	transaction.DaysPastDue = 0
+	transaction.Collectibility = collectibilityGrade(0)
+	transaction.LateFeeAmount = 0
+	transaction.RestructureCount = 0
+	transaction.RestructuredAt = nil
+	transaction.ActivatedAt = nil
 }
 
 func (u *transactionUsecase) GetTransaction(id uint) (*model.Transaction, error) {
@@ -283,9 +666,9 @@ func (u *transactionUsecase) UpdateTransactionStatus(id uint, status string) err
 	u.mu.Lock()
 	defer u.mu.Unlock()
 
-	validStatuses := map[string]bool{"ACTIVE": true, "COMPLETED": true, "DEFAULTED": true}
-	if !validStatuses[status] {
-		return errors.New("status tidak valid")
+	// ACTIVE and DEFAULTED follow the DPD and collectibility owned by the daily aging job, see RunDailyAging
+	if status != model.TransactionStatusCompleted {
+		return errors.New("status hanya dapat diubah menjadi COMPLETED, ACTIVE dan DEFAULTED ditentukan oleh aging harian")
 	}
 
 	transaction, err := u.transactionRepo.GetByID(id)
@@ -293,7 +676,117 @@ func (u *transactionUsecase) UpdateTransactionStatus(id uint, status string) err
 		return err
 	}
 
+	if transaction.Status == model.TransactionStatusPendingReview || transaction.Status == model.TransactionStatusRejected {
+		return errors.New("transaksi dalam review fraud tidak dapat diubah statusnya")
+	}
+	if transaction.Status == model.TransactionStatusPendingSignature {
+		return errors.New("kontrak belum ditandatangani konsumen")
+	}
+	if transaction.Status == model.TransactionStatusPendingDownPayment {
+		return errors.New("pembayaran DP kontrak belum diterima")
+	}
+	if transaction.Status == model.TransactionStatusCompleted || transaction.Status == model.TransactionStatusCancelled ||
+		transaction.Status == model.TransactionStatusWrittenOff {
+		return fmt.Errorf("transaksi berstatus %s tidak dapat diubah statusnya", transaction.Status)
+	}
+
+	// Completing a contract releases its limit, which is only allowed once every installment and late fee is paid.
+	// Contracts with an outstanding balance are closed through early settlement.
+	installments, err := u.installmentRepo.GetByTransactionID(transaction.ID)
+	if err != nil {
+		return err
+	}
+	for _, installment := range installments {
+		if installment.Outstanding() > 0 {
+			return errors.New("kontrak masih memiliki angsuran terutang, gunakan pelunasan dipercepat")
+		}
+		if installment.OutstandingLateFee() > 0 {
+			return errors.New("kontrak masih memiliki denda keterlambatan terutang")
+		}
+	}
+
+	previousStatus := transaction.Status
 	transaction.Status = status
 	transaction.UpdatedAt = time.Now()
-	return u.transactionRepo.Update(transaction)
+	err = u.transactor.Transaction(func(tx *repository.Repositories) error {
+		if err := releaseLimit(tx.Limits, transaction.ConsumerID, transaction.Tenor, transaction.FinancedAmount()); err != nil {
+			return err
+		}
+		return tx.Transactions.Update(transaction)
+	})
+	if err != nil {
+		return err
+	}
+	u.notifier.NotifyStatusChanged(transaction, previousStatus)
+	return nil
+}
+
+// ActivateSignedTransaction starts a contract once the consumer has signed its document
+func (u *transactionUsecase) ActivateSignedTransaction(id uint) (*model.Transaction, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	transaction, err := u.transactionRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("transaksi tidak ditemukan")
+	}
+	if transaction.Status != model.TransactionStatusPendingSignature {
+		return nil, fmt.Errorf("transaksi berstatus %s tidak sedang menunggu tanda tangan", transaction.Status)
+	}
+
+	transaction.UpdatedAt = time.Now()
+	err = u.transactor.Transaction(func(tx *repository.Repositories) error {
+		if err := u.activate(tx, transaction); err != nil {
+			return err
+		}
+		return tx.Transactions.Update(transaction)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.Status == model.TransactionStatusActive {
+		u.notifier.NotifyTransactionBooked(transaction)
+	} else {
+		u.notifier.NotifyStatusChanged(transaction, model.TransactionStatusPendingSignature)
+	}
+	return transaction, nil
+}
+
+// releaseLimit gives a booked amount back to the consumer limit of the tenor. It must run inside the database
+// transaction of the caller: the limit row stays locked until it commits.
+func releaseLimit(limitRepo repository.ConsumerLimitRepository, consumerID uint, tenor int, amount float64) error {
+	limit, err := limitRepo.GetByConsumerAndTenorForUpdate(consumerID, tenor)
+	if err != nil {
+		return errors.New("limit tidak ditemukan untuk tenor tersebut")
+	}
+	limit.UsedAmount -= amount
+	if limit.UsedAmount < 0 {
+		limit.UsedAmount = 0
+	}
+	limit.UpdatedAt = time.Now()
+	if err := limitRepo.UpdateUsage(limit); err != nil {
+		return errors.New("gagal update limit")
+	}
+	return nil
+}
+
+// activate starts the contract and posts its activation entry inside the database transaction of the caller.
+// A contract with a down payment waits in PENDING_DOWN_PAYMENT until receipts covering it are posted,
+// see RecordUpfrontPayment.
+func (u *transactionUsecase) activate(tx *repository.Repositories, transaction *model.Transaction) error {
+	if transaction.DownPayment > 0 {
+		received, err := upfrontReceived(tx.Payments, transaction.ID, model.PaymentTypeDownPayment)
+		if err != nil {
+			return err
+		}
+		if received < roundMoney(transaction.DownPayment) {
+			transaction.Status = model.TransactionStatusPendingDownPayment
+			return nil
+		}
+	}
+	now := time.Now()
+	transaction.Status = model.TransactionStatusActive
+	transaction.ActivatedAt = &now
+	return ledgerIn(tx).Post(activationEntry(transaction, now))
 }
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -53,6 +54,16 @@ func (m *MockConsumerRepository) GetAll() ([]model.Consumer, error) {
 	return consumers, nil
 }
 
+func (m *MockConsumerRepository) GetByKYCStatus(status string) ([]model.Consumer, error) {
+	var consumers []model.Consumer
+	for _, consumer := range m.consumers {
+		if consumer.KYCStatus == status {
+			consumers = append(consumers, *consumer)
+		}
+	}
+	return consumers, nil
+}
+
 func (m *MockConsumerRepository) Update(consumer *model.Consumer) error {
 	if _, exists := m.consumers[consumer.ID]; exists {
 		m.consumers[consumer.ID] = consumer
@@ -66,13 +77,26 @@ func (m *MockConsumerRepository) Delete(id uint) error {
 	return nil
 }
 
+// newVerifiedConsumerRepository returns a mock holding consumer 1 with a VERIFIED KYC
+func newVerifiedConsumerRepository() *MockConsumerRepository {
+	repo := NewMockConsumerRepository()
+	repo.Create(&model.Consumer{
+		NIK:       "3174010101900001",
+		FullName:  "John Doe",
+		LegalName: "John Doe",
+		Salary:    5000000,
+		KYCStatus: model.KYCStatusVerified,
+	})
+	return repo
+}
+
 // Test: Valid Consumer Registration
 func TestRegisterConsumer_Valid(t *testing.T) {
 	mockRepo := NewMockConsumerRepository()
-	uc := NewConsumerUsecase(mockRepo)
+	uc := NewConsumerUsecase(mockRepo, DefaultEligibilityRules(), DefaultNIKPolicy(), newTestWatchlist(mockRepo))
 
 	consumer := &model.Consumer{
-		NIK:         "1234567890123456",
+		NIK:         "3174010101900001",
 		FullName:    "John Doe",
 		LegalName:   "John Doe",
 		Salary:      5000000,
@@ -92,7 +116,7 @@ func TestRegisterConsumer_Valid(t *testing.T) {
 // Test: Invalid NIK Format
 func TestRegisterConsumer_InvalidNIK(t *testing.T) {
 	mockRepo := NewMockConsumerRepository()
-	uc := NewConsumerUsecase(mockRepo)
+	uc := NewConsumerUsecase(mockRepo, DefaultEligibilityRules(), DefaultNIKPolicy(), newTestWatchlist(mockRepo))
 
 	consumer := &model.Consumer{
 		NIK:       "INVALID",
@@ -107,13 +131,182 @@ func TestRegisterConsumer_InvalidNIK(t *testing.T) {
 	}
 }
 
+// Test: Date of Birth must match the NIK
+func TestRegisterConsumer_DateOfBirthMismatch(t *testing.T) {
+	mockRepo := NewMockConsumerRepository()
+	uc := NewConsumerUsecase(mockRepo, DefaultEligibilityRules(), DefaultNIKPolicy(), newTestWatchlist(mockRepo))
+
+	consumer := &model.Consumer{
+		NIK:         "3174010101900001",
+		FullName:    "John Doe",
+		LegalName:   "John Doe",
+		Salary:      5000000,
+		DateOfBirth: time.Date(1991, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	err := uc.RegisterConsumer(consumer)
+	if err == nil {
+		t.Error("Expected error for date of birth mismatch, got nil")
+	}
+}
+
+// Test: Gender and Date of Birth derived from the NIK
+func TestRegisterConsumer_DerivesFromNIK(t *testing.T) {
+	mockRepo := NewMockConsumerRepository()
+	uc := NewConsumerUsecase(mockRepo, DefaultEligibilityRules(), DefaultNIKPolicy(), newTestWatchlist(mockRepo))
+
+	consumer := &model.Consumer{
+		NIK:       "3273015506950002",
+		FullName:  "Jane Doe",
+		LegalName: "Jane Doe",
+		Salary:    5000000,
+	}
+
+	if err := uc.RegisterConsumer(consumer); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if consumer.Gender != "FEMALE" {
+		t.Errorf("Expected gender FEMALE, got %s", consumer.Gender)
+	}
+	if !consumer.DateOfBirth.Equal(time.Date(1995, 6, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected date of birth 1995-06-15, got %v", consumer.DateOfBirth)
+	}
+	if consumer.NIKInfo == nil || consumer.NIKInfo.Regency != "KOTA BANDUNG" {
+		t.Error("Expected decoded NIK info on consumer")
+	}
+}
+
+// Test: With the skips turned off, NIKs the region table cannot check are rejected
+func TestRegisterConsumer_UnlistedRegion(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  NIKPolicy
+		nik     string
+		wantErr error
+	}{
+		{"regency skipped", NIKPolicy{SkipUnlistedRegencies: true, SkipUnlistedDistricts: true}, "1271010101850003", nil},
+		{"regency required", NIKPolicy{SkipUnlistedDistricts: true}, "1271010101850003", ErrNIKRegionUnlisted},
+		{"listed regency", NIKPolicy{SkipUnlistedDistricts: true}, "3174010101850003", nil},
+		{"district required", NIKPolicy{SkipUnlistedRegencies: true}, "3174010101850003", ErrNIKRegionUnlisted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := NewMockConsumerRepository()
+			uc := NewConsumerUsecase(mockRepo, DefaultEligibilityRules(), tt.policy, newTestWatchlist(mockRepo))
+
+			err := uc.RegisterConsumer(&model.Consumer{
+				NIK:       tt.nik,
+				FullName:  "John Doe",
+				LegalName: "John Doe",
+				Salary:    5000000,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// nikBornOn builds a Jakarta Barat NIK for a man born on dob
+func nikBornOn(dob time.Time) string {
+	return "317401" + dob.Format("020106") + "0001"
+}
+
+// Test: Borrower below the minimum age
+func TestRegisterConsumer_Underage(t *testing.T) {
+	mockRepo := NewMockConsumerRepository()
+	uc := NewConsumerUsecase(mockRepo, DefaultEligibilityRules(), DefaultNIKPolicy(), newTestWatchlist(mockRepo))
+
+	consumer := &model.Consumer{
+		NIK:       nikBornOn(time.Now().AddDate(-18, 0, 0)),
+		FullName:  "John Doe",
+		LegalName: "John Doe",
+		Salary:    5000000,
+	}
+
+	err := uc.RegisterConsumer(consumer)
+	eligibilityErr, ok := err.(*EligibilityError)
+	if !ok || eligibilityErr.Code != CodeBelowMinimumAge {
+		t.Errorf("Expected %s, got %v", CodeBelowMinimumAge, err)
+	}
+}
+
+// Test: Married borrowers qualify from the lower minimum age
+func TestRegisterConsumer_MarriedMinimumAge(t *testing.T) {
+	mockRepo := NewMockConsumerRepository()
+	uc := NewConsumerUsecase(mockRepo, DefaultEligibilityRules(), DefaultNIKPolicy(), newTestWatchlist(mockRepo))
+
+	consumer := &model.Consumer{
+		NIK:           nikBornOn(time.Now().AddDate(-18, 0, 0)), // too young unless married
+		FullName:      "John Doe",
+		LegalName:     "John Doe",
+		Salary:        5000000,
+		MaritalStatus: model.MaritalStatusMarried,
+	}
+
+	if err := uc.RegisterConsumer(consumer); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+// Test: Minimum age boundaries for single and married borrowers
+func TestEligibilityRules_MinimumAge(t *testing.T) {
+	rules := DefaultEligibilityRules()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		dob     time.Time
+		marital string
+		code    string
+	}{
+		{time.Date(2005, 1, 1, 0, 0, 0, 0, time.UTC), model.MaritalStatusSingle, ""},                   // 21 today
+		{time.Date(2005, 1, 2, 0, 0, 0, 0, time.UTC), model.MaritalStatusSingle, CodeBelowMinimumAge},  // 21 tomorrow
+		{time.Date(2009, 1, 1, 0, 0, 0, 0, time.UTC), model.MaritalStatusMarried, ""},                  // 17 today
+		{time.Date(2009, 1, 2, 0, 0, 0, 0, time.UTC), model.MaritalStatusMarried, CodeBelowMinimumAge}, // 17 tomorrow
+		{time.Date(2009, 1, 1, 0, 0, 0, 0, time.UTC), model.MaritalStatusSingle, CodeBelowMinimumAge},
+	}
+
+	for _, c := range cases {
+		consumer := &model.Consumer{DateOfBirth: c.dob, MaritalStatus: c.marital}
+		err := rules.CheckRegistration(consumer, now)
+		if c.code == "" {
+			if err != nil {
+				t.Errorf("%s born %s: expected eligible, got %v", c.marital, c.dob.Format("2006-01-02"), err)
+			}
+			continue
+		}
+		if eligibilityErr, ok := err.(*EligibilityError); !ok || eligibilityErr.Code != c.code {
+			t.Errorf("%s born %s: expected %s, got %v", c.marital, c.dob.Format("2006-01-02"), c.code, err)
+		}
+	}
+}
+
+// Test: Age at maturity depends on the tenor
+func TestEligibilityRules_MaxAgeAtMaturity(t *testing.T) {
+	rules := DefaultEligibilityRules()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	consumer := &model.Consumer{DateOfBirth: time.Date(1965, 5, 1, 0, 0, 0, 0, time.UTC)}
+
+	if err := rules.CheckTransaction(consumer, 3, now); err != nil {
+		t.Errorf("Expected 3 month tenor to be eligible, got %v", err)
+	}
+
+	err := rules.CheckTransaction(consumer, 6, now)
+	eligibilityErr, ok := err.(*EligibilityError)
+	if !ok || eligibilityErr.Code != CodeAboveMaximumAge {
+		t.Errorf("Expected %s, got %v", CodeAboveMaximumAge, err)
+	}
+}
+
 // Test: Missing Required Fields
 func TestRegisterConsumer_MissingFields(t *testing.T) {
 	mockRepo := NewMockConsumerRepository()
-	uc := NewConsumerUsecase(mockRepo)
+	uc := NewConsumerUsecase(mockRepo, DefaultEligibilityRules(), DefaultNIKPolicy(), newTestWatchlist(mockRepo))
 
 	consumer := &model.Consumer{
-		NIK:      "1234567890123456",
+		NIK:      "3174010101900001",
 		FullName: "",
 		Salary:   5000000,
 	}
@@ -127,10 +320,10 @@ func TestRegisterConsumer_MissingFields(t *testing.T) {
 // Test: Insufficient Salary
 func TestRegisterConsumer_LowSalary(t *testing.T) {
 	mockRepo := NewMockConsumerRepository()
-	uc := NewConsumerUsecase(mockRepo)
+	uc := NewConsumerUsecase(mockRepo, DefaultEligibilityRules(), DefaultNIKPolicy(), newTestWatchlist(mockRepo))
 
 	consumer := &model.Consumer{
-		NIK:       "1234567890123456",
+		NIK:       "3174010101900001",
 		FullName:  "John Doe",
 		LegalName: "John Doe",
 		Salary:    500000, // Below minimum
@@ -145,10 +338,10 @@ func TestRegisterConsumer_LowSalary(t *testing.T) {
 // Test: Get Consumer by ID
 func TestGetConsumer(t *testing.T) {
 	mockRepo := NewMockConsumerRepository()
-	uc := NewConsumerUsecase(mockRepo)
+	uc := NewConsumerUsecase(mockRepo, DefaultEligibilityRules(), DefaultNIKPolicy(), newTestWatchlist(mockRepo))
 
 	consumer := &model.Consumer{
-		NIK:       "1234567890123456",
+		NIK:       "3174010101900001",
 		FullName:  "John Doe",
 		LegalName: "John Doe",
 		Salary:    5000000,
@@ -160,8 +353,8 @@ func TestGetConsumer(t *testing.T) {
 		t.Errorf("Expected no error, got %v", err)
 	}
 
-	if retrieved.NIK != "1234567890123456" {
-		t.Errorf("Expected NIK 1234567890123456, got %s", retrieved.NIK)
+	if retrieved.NIK != "3174010101900001" {
+		t.Errorf("Expected NIK 3174010101900001, got %s", retrieved.NIK)
 	}
 }
 
@@ -201,6 +394,10 @@ func (m *MockConsumerLimitRepository) GetByConsumerAndTenor(consumerID uint, ten
 	return nil, gorm.ErrRecordNotFound
 }
 
+func (m *MockConsumerLimitRepository) GetByConsumerAndTenorForUpdate(consumerID uint, tenor int) (*model.ConsumerLimit, error) {
+	return m.GetByConsumerAndTenor(consumerID, tenor)
+}
+
 func (m *MockConsumerLimitRepository) GetByConsumerID(consumerID uint) ([]model.ConsumerLimit, error) {
 	var limits []model.ConsumerLimit
 	for _, limit := range m.limits {
@@ -219,6 +416,17 @@ func (m *MockConsumerLimitRepository) Update(limit *model.ConsumerLimit) error {
 	return gorm.ErrRecordNotFound
 }
 
+func (m *MockConsumerLimitRepository) UpdateUsage(limit *model.ConsumerLimit) error {
+	stored, exists := m.limits[limit.ID]
+	if !exists {
+		return gorm.ErrRecordNotFound
+	}
+	stored.UsedAmount = limit.UsedAmount
+	stored.HeldAmount = limit.HeldAmount
+	stored.UpdatedAt = limit.UpdatedAt
+	return nil
+}
+
 func (m *MockConsumerLimitRepository) Delete(id uint) error {
 	delete(m.limits, id)
 	return nil
@@ -227,7 +435,7 @@ func (m *MockConsumerLimitRepository) Delete(id uint) error {
 // Test: Valid Limit Assignment
 func TestAssignLimit_Valid(t *testing.T) {
 	mockRepo := NewMockConsumerLimitRepository()
-	uc := NewConsumerLimitUsecase(mockRepo)
+	uc := NewConsumerLimitUsecase(mockRepo, newVerifiedConsumerRepository())
 
 	limit := &model.ConsumerLimit{
 		ConsumerID:  1,
@@ -248,7 +456,7 @@ func TestAssignLimit_Valid(t *testing.T) {
 // Test: Invalid Tenor
 func TestAssignLimit_InvalidTenor(t *testing.T) {
 	mockRepo := NewMockConsumerLimitRepository()
-	uc := NewConsumerLimitUsecase(mockRepo)
+	uc := NewConsumerLimitUsecase(mockRepo, newVerifiedConsumerRepository())
 
 	limit := &model.ConsumerLimit{
 		ConsumerID:  1,
@@ -265,7 +473,7 @@ func TestAssignLimit_InvalidTenor(t *testing.T) {
 // Test: Invalid Limit Amount
 func TestAssignLimit_InvalidAmount(t *testing.T) {
 	mockRepo := NewMockConsumerLimitRepository()
-	uc := NewConsumerLimitUsecase(mockRepo)
+	uc := NewConsumerLimitUsecase(mockRepo, newVerifiedConsumerRepository())
 
 	limit := &model.ConsumerLimit{
 		ConsumerID:  1,
@@ -278,3 +486,21 @@ func TestAssignLimit_InvalidAmount(t *testing.T) {
 		t.Error("Expected error for negative limit, got nil")
 	}
 }
+
+// Test: Limit Assignment refused before KYC verification
+func TestAssignLimit_ConsumerNotVerified(t *testing.T) {
+	consumerRepo := NewMockConsumerRepository()
+	consumerRepo.Create(&model.Consumer{NIK: "3174010101900001", KYCStatus: model.KYCStatusManualReview})
+	uc := NewConsumerLimitUsecase(NewMockConsumerLimitRepository(), consumerRepo)
+
+	limit := &model.ConsumerLimit{
+		ConsumerID:  1,
+		Tenor:       6,
+		LimitAmount: 1000000,
+	}
+
+	err := uc.AssignLimit(limit)
+	if err != ErrConsumerNotVerified {
+		t.Errorf("Expected ErrConsumerNotVerified, got %v", err)
+	}
+}
@@ -0,0 +1,662 @@
+package usecase
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"main/internal/model"
+	"main/internal/notification"
+	"main/internal/otp"
+	"main/internal/pdf"
+	"main/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+var ErrSignatureLocked = errors.New("kode OTP tanda tangan salah terlalu banyak, kontrak harus diajukan ulang")
+
+// ContractActivator starts a contract that was waiting for the consumer signature
+type ContractActivator interface {
+	ActivateSignedTransaction(transactionID uint) (*model.Transaction, error)
+}
+
+// DefaultContractTemplateCode is the template of products without wording of their own
+const DefaultContractTemplateCode = "FINANCING"
+
+// ContractTemplateRequest is a new version of a contract template
+type ContractTemplateRequest struct {
+	Code      string `json:"code"` // FINANCING or a product code
+	Title     string `json:"title"`
+	Body      string `json:"body"`
+	CreatedBy string `json:"created_by"`
+}
+
+// SignContractRequest is the code the consumer received; the IP address and user agent are kept as evidence
+type SignContractRequest struct {
+	OTP       string `json:"otp"`
+	IPAddress string `json:"-"`
+	UserAgent string `json:"-"`
+}
+
+// ContractClauseData is what the clauses of a contract template can refer to
+type ContractClauseData struct {
+	ContractNumber    string
+	ContractDate      time.Time
+	ConsumerName      string
+	NIK               string
+	AssetName         string
+	MerchantName      string
+	OTR               float64
+	DownPayment       float64
+	FinancedAmount    float64
+	InterestRate      float64 // percent per month, flat
+	InterestAmount    float64
+	AdminFee          float64
+	InstallmentAmount float64
+	Tenor             int
+	FirstDueDate      time.Time
+	LastDueDate       time.Time
+}
+
+// defaultContractTemplate is seeded as version 1 of FINANCING the first time a contract is generated
+var defaultContractTemplate = model.ContractTemplate{
+	Code:  DefaultContractTemplateCode,
+	Title: "Perjanjian Pembiayaan Konsumen",
+	Body: `Pasal 1 - Pembiayaan
+Perusahaan memberikan pembiayaan kepada {{.ConsumerName}} untuk pembelian {{.AssetName}} dengan pokok pembiayaan {{rupiah .FinancedAmount}}, yaitu harga {{rupiah .OTR}} dikurangi uang muka {{rupiah .DownPayment}}.
+
+Pasal 2 - Bunga dan Angsuran
+Atas pembiayaan dikenakan bunga flat {{.InterestRate}}% per bulan sebesar {{rupiah .InterestAmount}}. Konsumen wajib membayar {{.Tenor}} kali angsuran sebesar {{rupiah .InstallmentAmount}} sesuai jadwal angsuran, mulai {{date .FirstDueDate}} sampai {{date .LastDueDate}}.
+
+Pasal 3 - Biaya
+Biaya administrasi sebesar {{rupiah .AdminFee}} dibayar saat kontrak diaktifkan dan tidak dikembalikan setelah kontrak berjalan.
+
+Pasal 4 - Keterlambatan
+Angsuran yang dibayar setelah tanggal jatuh tempo dikenakan denda keterlambatan harian sesuai ketentuan Perusahaan yang berlaku, dihitung sejak hari setelah tanggal jatuh tempo.
+
+Pasal 5 - Pelunasan Dipercepat
+Konsumen dapat melunasi seluruh sisa pembiayaan sebelum jatuh tempo terakhir dengan membayar sisa pokok, bunga berjalan dan biaya pelunasan sesuai ketentuan Perusahaan.
+
+Pasal 6 - Tanda Tangan Elektronik
+Perjanjian ini ditandatangani secara elektronik dengan kode OTP yang dikirim kepada Konsumen dan mengikat para pihak sejak kode tersebut diverifikasi.`,
+	CreatedBy: "SYSTEM",
+}
+
+// ContractUsecase defines the contract documents of a transaction and their OTP signature
+type ContractUsecase interface {
+	CreateTemplate(req ContractTemplateRequest) (*model.ContractTemplate, error)
+	GetTemplates(code string) ([]model.ContractTemplate, error)
+	GenerateContract(transactionID uint) (*model.ContractDocument, error)
+	GetContractDocument(transactionID uint) (*model.ContractDocument, error)
+	GetContractDocuments(transactionID uint) ([]model.ContractDocument, error)
+	RequestSignature(transactionID uint) (*model.ContractSignature, error)
+	SignContract(transactionID uint, req SignContractRequest) (*model.ContractSignature, error)
+	GetSignatures(transactionID uint) ([]model.ContractSignature, error)
+}
+
+// contractUsecase is the implementation of ContractUsecase
+type contractUsecase struct {
+	transactionRepo repository.TransactionRepository
+	consumerRepo    repository.ConsumerRepository
+	installmentRepo repository.InstallmentRepository
+	merchantRepo    repository.MerchantRepository
+	templateRepo    repository.ContractTemplateRepository
+	documentRepo    repository.ContractDocumentRepository
+	signatureRepo   repository.ContractSignatureRepository
+	activator       ContractActivator
+	otpSender       OTPSender
+	otpKey          []byte
+	policy          OTPPolicy
+	mu              sync.Mutex
+}
+
+// NewContractUsecase creates a new instance of ContractUsecase. otpKey signs the stored code hashes.
+func NewContractUsecase(
+	transactionRepo repository.TransactionRepository,
+	consumerRepo repository.ConsumerRepository,
+	installmentRepo repository.InstallmentRepository,
+	merchantRepo repository.MerchantRepository,
+	templateRepo repository.ContractTemplateRepository,
+	documentRepo repository.ContractDocumentRepository,
+	signatureRepo repository.ContractSignatureRepository,
+	activator ContractActivator,
+	otpSender OTPSender,
+	otpKey []byte,
+	policy OTPPolicy,
+) ContractUsecase {
+	return &contractUsecase{
+		transactionRepo: transactionRepo,
+		consumerRepo:    consumerRepo,
+		installmentRepo: installmentRepo,
+		merchantRepo:    merchantRepo,
+		templateRepo:    templateRepo,
+		documentRepo:    documentRepo,
+		signatureRepo:   signatureRepo,
+		activator:       activator,
+		otpSender:       otpSender,
+		otpKey:          otpKey,
+		policy:          policy,
+	}
+}
+
+// CreateTemplate adds a new version of a template and makes it the active one.
+// Documents already generated keep the version they were rendered from.
+func (u *contractUsecase) CreateTemplate(req ContractTemplateRequest) (*model.ContractTemplate, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	code := strings.ToUpper(strings.TrimSpace(req.Code))
+	if code == "" || strings.TrimSpace(req.Title) == "" || strings.TrimSpace(req.Body) == "" {
+		return nil, errors.New("kode, judul dan isi template wajib diisi")
+	}
+	if _, err := renderClauses(req.Body, sampleClauseData()); err != nil {
+		return nil, fmt.Errorf("isi template tidak valid: %w", err)
+	}
+
+	versions, err := u.templateRepo.GetByCode(code)
+	if err != nil {
+		return nil, err
+	}
+	next := 1
+	if len(versions) > 0 {
+		next = versions[0].Version + 1
+	}
+
+	now := time.Now()
+	contractTemplate := &model.ContractTemplate{
+		Code:      code,
+		Version:   next,
+		Title:     strings.TrimSpace(req.Title),
+		Body:      req.Body,
+		Active:    true,
+		CreatedBy: req.CreatedBy,
+		CreatedAt: now,
+	}
+	if err := u.templateRepo.Create(contractTemplate); err != nil {
+		return nil, err
+	}
+	for i := range versions {
+		if !versions[i].Active {
+			continue
+		}
+		versions[i].Active = false
+		if err := u.templateRepo.Update(&versions[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Printf("✓ Template kontrak %s versi %d aktif\n", code, next)
+	return contractTemplate, nil
+}
+
+func (u *contractUsecase) GetTemplates(code string) ([]model.ContractTemplate, error) {
+	return u.templateRepo.GetByCode(strings.ToUpper(strings.TrimSpace(code)))
+}
+
+// GenerateContract renders the contract of a transaction waiting for its signature. Rendering is
+// deterministic, so the pending document is reused while nothing in the contract or template changed.
+func (u *contractUsecase) GenerateContract(transactionID uint) (*model.ContractDocument, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	transaction, err := u.pendingTransaction(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	return u.generate(transaction)
+}
+
+// GetContractDocument returns the latest document of a contract with its content
+func (u *contractUsecase) GetContractDocument(transactionID uint) (*model.ContractDocument, error) {
+	document, err := u.documentRepo.GetLatest(transactionID)
+	if err != nil {
+		return nil, errors.New("dokumen kontrak belum dibuat")
+	}
+	return document, nil
+}
+
+func (u *contractUsecase) GetContractDocuments(transactionID uint) ([]model.ContractDocument, error) {
+	return u.documentRepo.GetByTransactionID(transactionID)
+}
+
+// RequestSignature sends a new code for the current document. An earlier code stops working.
+// Wrong codes carry over and the codes sent per contract are capped, so resending does not buy more guesses.
+func (u *contractUsecase) RequestSignature(transactionID uint) (*model.ContractSignature, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	transaction, err := u.pendingTransaction(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	consumer, err := u.consumerRepo.GetByID(transaction.ConsumerID)
+	if err != nil {
+		return nil, errors.New("konsumen tidak ditemukan")
+	}
+	document, err := u.generate(transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if previous, err := u.signatureRepo.GetPending(transactionID); err == nil {
+		if now.Before(previous.CreatedAt.Add(u.policy.ResendCooldown)) {
+			return nil, ErrOTPCooldown
+		}
+	}
+	previous, err := u.signatureRepo.GetByTransactionID(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	attempts, sent := 0, 0
+	for _, signature := range previous {
+		attempts = max(attempts, signature.Attempts)
+		if signature.OTPChannel != "" {
+			sent++
+		}
+	}
+	if attempts >= u.policy.MaxAttempts {
+		return nil, ErrSignatureLocked
+	}
+	if sent >= u.policy.MaxSends {
+		return nil, fmt.Errorf("kode OTP tanda tangan sudah dikirim %d kali, kontrak harus diajukan ulang", sent)
+	}
+	if err := u.expirePendingSignature(transactionID, now); err != nil {
+		return nil, err
+	}
+
+	code, err := otp.Generate(u.policy.Digits)
+	if err != nil {
+		return nil, err
+	}
+	signature := &model.ContractSignature{
+		TransactionID: transactionID,
+		DocumentID:    document.ID,
+		DocumentHash:  document.DocumentHash,
+		OTPHash:       otp.Hash(u.otpKey, signingBinding(transactionID, document.DocumentHash), code),
+		OTPExpiresAt:  now.Add(u.policy.TTL),
+		Attempts:      attempts,
+		Status:        model.SignatureStatusPending,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := u.signatureRepo.Create(signature); err != nil {
+		return nil, err
+	}
+
+	channel, err := u.otpSender.SendOTP(consumer.ID, transactionID, notification.EventSigningOTP,
+		fmt.Sprintf("OTP-SIGN-%d", signature.ID), notification.TemplateData{
+			Name:           consumer.FullName,
+			ContractNumber: transaction.ContractNumber,
+			Amount:         transaction.FinancedAmount(),
+			Code:           code,
+			ExpiresAt:      signature.OTPExpiresAt,
+		})
+	if err != nil {
+		signature.Status = model.SignatureStatusExpired
+		signature.UpdatedAt = time.Now()
+		u.signatureRepo.Update(signature)
+		return nil, fmt.Errorf("gagal mengirim kode OTP: %w", err)
+	}
+	signature.OTPChannel = channel
+	if err := u.signatureRepo.Update(signature); err != nil {
+		return nil, err
+	}
+
+	log.Printf("✓ Kode OTP tanda tangan kontrak %s dikirim via %s\n", transaction.ContractNumber, channel)
+	return signature, nil
+}
+
+// SignContract checks the code against the document it was sent for, keeps the evidence and activates the contract
+func (u *contractUsecase) SignContract(transactionID uint, req SignContractRequest) (*model.ContractSignature, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	code := strings.TrimSpace(req.OTP)
+	if code == "" {
+		return nil, errors.New("kode OTP wajib diisi")
+	}
+	transaction, err := u.pendingTransaction(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := u.signatureRepo.GetPending(transactionID)
+	if err != nil {
+		return nil, errors.New("tidak ada permintaan tanda tangan yang aktif, minta kode OTP terlebih dahulu")
+	}
+
+	now := time.Now()
+	if now.After(signature.OTPExpiresAt) {
+		if err := u.closeSignature(signature, model.SignatureStatusExpired, now); err != nil {
+			return nil, err
+		}
+		return nil, ErrOTPExpired
+	}
+
+	// The code only signs the exact bytes it was sent for
+	document, err := u.documentRepo.GetLatest(transactionID)
+	if err != nil || document.ID != signature.DocumentID || document.Status != model.ContractDocumentPending ||
+		documentHash(document.Content) != signature.DocumentHash {
+		if err := u.closeSignature(signature, model.SignatureStatusExpired, now); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("dokumen kontrak berubah sejak kode OTP dikirim, minta kode baru")
+	}
+
+	if !otp.Verify(u.otpKey, signingBinding(transactionID, signature.DocumentHash), code, signature.OTPHash) {
+		signature.Attempts++
+		if signature.Attempts >= u.policy.MaxAttempts {
+			if err := u.closeSignature(signature, model.SignatureStatusLocked, now); err != nil {
+				return nil, err
+			}
+			log.Printf("⚠ Tanda tangan kontrak %s dikunci setelah %d kode salah\n", transaction.ContractNumber, signature.Attempts)
+			return nil, ErrSignatureLocked
+		}
+		signature.UpdatedAt = now
+		if err := u.signatureRepo.Update(signature); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w, sisa %d percobaan", ErrInvalidOTP, u.policy.MaxAttempts-signature.Attempts)
+	}
+
+	signature.Status = model.SignatureStatusSigned
+	signature.SignedAt = &now
+	signature.IPAddress = req.IPAddress
+	signature.UserAgent = req.UserAgent
+	if len(signature.UserAgent) > 500 {
+		signature.UserAgent = signature.UserAgent[:500]
+	}
+	signature.UpdatedAt = now
+	if err := u.signatureRepo.Update(signature); err != nil {
+		return nil, err
+	}
+	document.Status = model.ContractDocumentSigned
+	document.SignedAt = &now
+	document.UpdatedAt = now
+	if err := u.documentRepo.Update(document); err != nil {
+		return nil, err
+	}
+
+	if _, err := u.activator.ActivateSignedTransaction(transactionID); err != nil {
+		return nil, err
+	}
+	log.Printf("✓ Kontrak %s ditandatangani (dokumen %s)\n", transaction.ContractNumber, document.DocumentHash)
+	return signature, nil
+}
+
+func (u *contractUsecase) GetSignatures(transactionID uint) ([]model.ContractSignature, error) {
+	return u.signatureRepo.GetByTransactionID(transactionID)
+}
+
+func (u *contractUsecase) pendingTransaction(transactionID uint) (*model.Transaction, error) {
+	transaction, err := u.transactionRepo.GetByID(transactionID)
+	if err != nil {
+		return nil, errors.New("transaksi tidak ditemukan")
+	}
+	if transaction.Status != model.TransactionStatusPendingSignature {
+		return nil, fmt.Errorf("transaksi berstatus %s tidak sedang menunggu tanda tangan", transaction.Status)
+	}
+	return transaction, nil
+}
+
+// generate renders the contract and stores it unless the pending document already has the same content.
+// A changed document supersedes the pending one and voids the code sent for it.
+func (u *contractUsecase) generate(transaction *model.Transaction) (*model.ContractDocument, error) {
+	contractTemplate, err := u.activeTemplate(transaction.ProductCode)
+	if err != nil {
+		return nil, err
+	}
+	data, schedule, err := u.clauseData(transaction)
+	if err != nil {
+		return nil, err
+	}
+	content, err := renderContractPDF(contractTemplate, data, schedule)
+	if err != nil {
+		return nil, err
+	}
+	hash := documentHash(content)
+
+	now := time.Now()
+	latest, err := u.documentRepo.GetLatest(transaction.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if latest != nil && latest.Status == model.ContractDocumentPending {
+		if latest.DocumentHash == hash {
+			return latest, nil
+		}
+		latest.Status = model.ContractDocumentSuperseded
+		latest.UpdatedAt = now
+		if err := u.documentRepo.Update(latest); err != nil {
+			return nil, err
+		}
+		if err := u.expirePendingSignature(transaction.ID, now); err != nil {
+			return nil, err
+		}
+	}
+
+	document := &model.ContractDocument{
+		TransactionID:   transaction.ID,
+		TemplateID:      contractTemplate.ID,
+		TemplateCode:    contractTemplate.Code,
+		TemplateVersion: contractTemplate.Version,
+		FileName:        fmt.Sprintf("kontrak-%s.pdf", transaction.ContractNumber),
+		DocumentHash:    hash,
+		Content:         content,
+		SizeBytes:       len(content),
+		Status:          model.ContractDocumentPending,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := u.documentRepo.Create(document); err != nil {
+		return nil, err
+	}
+	log.Printf("✓ Dokumen kontrak %s dibuat dari template %s versi %d\n", transaction.ContractNumber, contractTemplate.Code, contractTemplate.Version)
+	return document, nil
+}
+
+// activeTemplate picks the template of the product, else FINANCING, seeding the built-in wording when none exists
+func (u *contractUsecase) activeTemplate(productCode string) (*model.ContractTemplate, error) {
+	if productCode != "" {
+		contractTemplate, err := u.templateRepo.GetActive(productCode)
+		if err == nil {
+			return contractTemplate, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+
+	contractTemplate, err := u.templateRepo.GetActive(DefaultContractTemplateCode)
+	if err == nil {
+		return contractTemplate, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	seeded := defaultContractTemplate
+	seeded.Version = 1
+	seeded.Active = true
+	seeded.CreatedAt = time.Now()
+	if err := u.templateRepo.Create(&seeded); err != nil {
+		return nil, err
+	}
+	return &seeded, nil
+}
+
+func (u *contractUsecase) clauseData(transaction *model.Transaction) (ContractClauseData, []model.Installment, error) {
+	consumer, err := u.consumerRepo.GetByID(transaction.ConsumerID)
+	if err != nil {
+		return ContractClauseData{}, nil, errors.New("konsumen tidak ditemukan")
+	}
+	schedule, err := u.installmentRepo.GetByTransactionID(transaction.ID)
+	if err != nil {
+		return ContractClauseData{}, nil, err
+	}
+	if len(schedule) == 0 {
+		return ContractClauseData{}, nil, errors.New("jadwal angsuran tidak ditemukan")
+	}
+	sort.Slice(schedule, func(i, j int) bool { return schedule[i].Sequence < schedule[j].Sequence })
+
+	data := ContractClauseData{
+		ContractNumber:    transaction.ContractNumber,
+		ContractDate:      transaction.CreatedAt,
+		ConsumerName:      consumer.FullName,
+		NIK:               consumer.NIK,
+		AssetName:         transaction.AssetName,
+		OTR:               transaction.OTR,
+		DownPayment:       transaction.DownPayment,
+		FinancedAmount:    transaction.FinancedAmount(),
+		InterestRate:      transaction.InterestRate,
+		InterestAmount:    transaction.InterestAmount,
+		AdminFee:          transaction.AdminFee,
+		InstallmentAmount: transaction.InstallmentAmount,
+		Tenor:             transaction.Tenor,
+		FirstDueDate:      schedule[0].DueDate,
+		LastDueDate:       schedule[len(schedule)-1].DueDate,
+	}
+	if transaction.MerchantID != 0 {
+		if merchant, err := u.merchantRepo.GetByID(transaction.MerchantID); err == nil {
+			data.MerchantName = merchant.Name
+		}
+	}
+	return data, schedule, nil
+}
+
+// expirePendingSignature voids the code waiting for the consumer, if any
+func (u *contractUsecase) expirePendingSignature(transactionID uint, now time.Time) error {
+	signature, err := u.signatureRepo.GetPending(transactionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	return u.closeSignature(signature, model.SignatureStatusExpired, now)
+}
+
+func (u *contractUsecase) closeSignature(signature *model.ContractSignature, status string, now time.Time) error {
+	signature.Status = status
+	signature.UpdatedAt = now
+	return u.signatureRepo.Update(signature)
+}
+
+// signingBinding ties a code to one contract and one exact document
+func signingBinding(transactionID uint, documentHash string) string {
+	return fmt.Sprintf("SIGN:%d:%s", transactionID, documentHash)
+}
+
+func documentHash(content []byte) string {
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:])
+}
+
+var clauseFuncs = template.FuncMap{
+	"rupiah": notification.FormatRupiah,
+	"date":   func(t time.Time) string { return t.Format("02/01/2006") },
+}
+
+// renderClauses executes the body of a template; unknown fields fail instead of printing blanks
+func renderClauses(body string, data ContractClauseData) (string, error) {
+	parsed, err := template.New("contract").Funcs(clauseFuncs).Option("missingkey=error").Parse(body)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	if err := parsed.Execute(&out, data); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// sampleClauseData checks a new template renders before it goes live
+func sampleClauseData() ContractClauseData {
+	date := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	return ContractClauseData{
+		ContractNumber:    "CTR-CONTOH",
+		ContractDate:      date,
+		ConsumerName:      "Konsumen Contoh",
+		NIK:               "3201010101900001",
+		AssetName:         "Barang Contoh",
+		MerchantName:      "Merchant Contoh",
+		OTR:               3000000,
+		FinancedAmount:    3000000,
+		InterestRate:      1.5,
+		InterestAmount:    135000,
+		InstallmentAmount: 1045000,
+		Tenor:             3,
+		FirstDueDate:      addMonths(date, 1),
+		LastDueDate:       addMonths(date, 3),
+	}
+}
+
+// renderContractPDF lays out the parties, the financing terms, the schedule and the template clauses
+func renderContractPDF(contractTemplate *model.ContractTemplate, data ContractClauseData, schedule []model.Installment) ([]byte, error) {
+	clauses, err := renderClauses(contractTemplate.Body, data)
+	if err != nil {
+		return nil, fmt.Errorf("gagal menyusun template kontrak %s versi %d: %w", contractTemplate.Code, contractTemplate.Version, err)
+	}
+
+	doc := pdf.New(contractTemplate.Title + " " + data.ContractNumber)
+	doc.Title(contractTemplate.Title)
+	doc.Field("Nomor kontrak", data.ContractNumber)
+	doc.Field("Tanggal", data.ContractDate.Format("02/01/2006"))
+	doc.Field("Template", fmt.Sprintf("%s versi %d", contractTemplate.Code, contractTemplate.Version))
+
+	doc.Heading("Para Pihak")
+	doc.Field("Konsumen", data.ConsumerName)
+	doc.Field("NIK", data.NIK)
+	if data.MerchantName != "" {
+		doc.Field("Merchant", data.MerchantName)
+	}
+
+	doc.Heading("Pembiayaan")
+	doc.Field("Barang", data.AssetName)
+	doc.Field("Harga (OTR)", notification.FormatRupiah(data.OTR))
+	doc.Field("Uang muka", notification.FormatRupiah(data.DownPayment))
+	doc.Field("Pokok pembiayaan", notification.FormatRupiah(data.FinancedAmount))
+	doc.Field("Bunga flat", fmt.Sprintf("%.2f%% per bulan", data.InterestRate))
+	doc.Field("Total bunga", notification.FormatRupiah(data.InterestAmount))
+	doc.Field("Biaya administrasi", notification.FormatRupiah(data.AdminFee))
+	doc.Field("Tenor", fmt.Sprintf("%d bulan", data.Tenor))
+	doc.Field("Angsuran per bulan", notification.FormatRupiah(data.InstallmentAmount))
+
+	doc.Heading("Jadwal Angsuran")
+	columns := []pdf.Column{
+		{Title: "Ke", Width: 40},
+		{Title: "Jatuh tempo", Width: 95},
+		{Title: "Pokok", Width: 120, Right: true},
+		{Title: "Bunga", Width: 110, Right: true},
+		{Title: "Angsuran", Width: 130, Right: true},
+	}
+	rows := make([][]string, len(schedule))
+	for i, installment := range schedule {
+		rows[i] = []string{fmt.Sprintf("%d", installment.Sequence), installment.DueDate.Format("02/01/2006"),
+			notification.FormatRupiah(installment.PrincipalAmount), notification.FormatRupiah(installment.InterestAmount),
+			notification.FormatRupiah(installment.Amount)}
+	}
+	doc.Table(columns, rows)
+
+	doc.Heading("Ketentuan")
+	for _, line := range strings.Split(strings.TrimSpace(clauses), "\n") {
+		if strings.TrimSpace(line) == "" {
+			doc.Space(6)
+			continue
+		}
+		doc.Text(line)
+	}
+
+	doc.Heading("Persetujuan")
+	doc.Text("Konsumen menyetujui perjanjian ini dengan memasukkan kode OTP yang dikirim ke kontak terdaftar. " +
+		"Waktu, alamat IP, perangkat dan hash SHA-256 dokumen yang ditandatangani disimpan oleh Perusahaan sebagai bukti.")
+	return doc.Bytes(), nil
+}
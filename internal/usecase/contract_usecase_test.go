@@ -0,0 +1,389 @@
+package usecase
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"main/internal/model"
+	"main/internal/notification"
+
+	"gorm.io/gorm"
+)
+
+// MockContractTemplateRepository for testing
+type MockContractTemplateRepository struct {
+	templates []model.ContractTemplate
+}
+
+func (m *MockContractTemplateRepository) Create(template *model.ContractTemplate) error {
+	template.ID = uint(len(m.templates) + 1)
+	m.templates = append(m.templates, *template)
+	return nil
+}
+
+func (m *MockContractTemplateRepository) GetByID(id uint) (*model.ContractTemplate, error) {
+	for i := range m.templates {
+		if m.templates[i].ID == id {
+			template := m.templates[i]
+			return &template, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockContractTemplateRepository) GetActive(code string) (*model.ContractTemplate, error) {
+	for i := len(m.templates) - 1; i >= 0; i-- {
+		if m.templates[i].Code == code && m.templates[i].Active {
+			template := m.templates[i]
+			return &template, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockContractTemplateRepository) GetByCode(code string) ([]model.ContractTemplate, error) {
+	var templates []model.ContractTemplate
+	for i := len(m.templates) - 1; i >= 0; i-- {
+		if m.templates[i].Code == code {
+			templates = append(templates, m.templates[i])
+		}
+	}
+	return templates, nil
+}
+
+func (m *MockContractTemplateRepository) Update(template *model.ContractTemplate) error {
+	m.templates[template.ID-1] = *template
+	return nil
+}
+
+// MockContractDocumentRepository for testing
+type MockContractDocumentRepository struct {
+	documents []model.ContractDocument
+}
+
+func (m *MockContractDocumentRepository) Create(document *model.ContractDocument) error {
+	document.ID = uint(len(m.documents) + 1)
+	m.documents = append(m.documents, *document)
+	return nil
+}
+
+func (m *MockContractDocumentRepository) GetByID(id uint) (*model.ContractDocument, error) {
+	if id == 0 || int(id) > len(m.documents) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	document := m.documents[id-1]
+	return &document, nil
+}
+
+func (m *MockContractDocumentRepository) GetLatest(transactionID uint) (*model.ContractDocument, error) {
+	for i := len(m.documents) - 1; i >= 0; i-- {
+		if m.documents[i].TransactionID == transactionID {
+			document := m.documents[i]
+			return &document, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockContractDocumentRepository) GetByTransactionID(transactionID uint) ([]model.ContractDocument, error) {
+	var documents []model.ContractDocument
+	for i := len(m.documents) - 1; i >= 0; i-- {
+		if m.documents[i].TransactionID == transactionID {
+			document := m.documents[i]
+			document.Content = nil
+			documents = append(documents, document)
+		}
+	}
+	return documents, nil
+}
+
+func (m *MockContractDocumentRepository) Update(document *model.ContractDocument) error {
+	m.documents[document.ID-1] = *document
+	return nil
+}
+
+// MockContractSignatureRepository for testing
+type MockContractSignatureRepository struct {
+	signatures []model.ContractSignature
+}
+
+func (m *MockContractSignatureRepository) Create(signature *model.ContractSignature) error {
+	signature.ID = uint(len(m.signatures) + 1)
+	m.signatures = append(m.signatures, *signature)
+	return nil
+}
+
+func (m *MockContractSignatureRepository) GetPending(transactionID uint) (*model.ContractSignature, error) {
+	for i := len(m.signatures) - 1; i >= 0; i-- {
+		if m.signatures[i].TransactionID == transactionID && m.signatures[i].Status == model.SignatureStatusPending {
+			signature := m.signatures[i]
+			return &signature, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockContractSignatureRepository) GetByTransactionID(transactionID uint) ([]model.ContractSignature, error) {
+	var signatures []model.ContractSignature
+	for i := len(m.signatures) - 1; i >= 0; i-- {
+		if m.signatures[i].TransactionID == transactionID {
+			signatures = append(signatures, m.signatures[i])
+		}
+	}
+	return signatures, nil
+}
+
+func (m *MockContractSignatureRepository) Update(signature *model.ContractSignature) error {
+	m.signatures[signature.ID-1] = *signature
+	return nil
+}
+
+// MockOTPSender keeps the codes it was asked to send
+type MockOTPSender struct {
+	codes []string
+	fail  bool
+}
+
+func (m *MockOTPSender) SendOTP(consumerID, transactionID uint, event, dedupKey string, data notification.TemplateData) (string, error) {
+	if m.fail {
+		return "", errors.New("semua kanal gagal")
+	}
+	m.codes = append(m.codes, data.Code)
+	return notification.ChannelSMS, nil
+}
+
+func (m *MockOTPSender) lastCode() string {
+	return m.codes[len(m.codes)-1]
+}
+
+type contractFixture struct {
+	*transactionFixture
+	contracts     ContractUsecase
+	templateRepo  *MockContractTemplateRepository
+	documentRepo  *MockContractDocumentRepository
+	signatureRepo *MockContractSignatureRepository
+	sender        *MockOTPSender
+	transaction   *model.Transaction
+}
+
+// newContractFixture books a GADGET contract that waits for the consumer signature
+func newContractFixture(t *testing.T) *contractFixture {
+	f := &contractFixture{
+		transactionFixture: newTransactionFixtureWithPolicy(t, DefaultTransactionPolicy()),
+		templateRepo:       &MockContractTemplateRepository{},
+		documentRepo:       &MockContractDocumentRepository{},
+		signatureRepo:      &MockContractSignatureRepository{},
+		sender:             &MockOTPSender{},
+	}
+	f.contracts = NewContractUsecase(
+		f.transactionRepo, f.consumerRepo, f.installmentRepo, f.merchantRepo, f.templateRepo, f.documentRepo, f.signatureRepo,
+		f.uc, f.sender, []byte("test-otp-key"), DefaultOTPPolicy(),
+	)
+
+	f.transaction = &model.Transaction{
+		ConsumerID:     1,
+		ContractNumber: "CONT-SIGN",
+		ProductCode:    "GADGET",
+		Tenor:          3,
+		OTR:            3000000,
+		AssetName:      "Kulkas",
+		MerchantID:     5,
+	}
+	if err := f.uc.CreateTransaction(f.transaction); err != nil {
+		t.Fatalf("Expected transaction booked, got %v", err)
+	}
+	return f
+}
+
+func (f *contractFixture) wrongCode() string {
+	if f.sender.lastCode() == "000000" {
+		return "000001"
+	}
+	return "000000"
+}
+
+// Test: The built-in template is seeded and rendering the same contract twice gives the same document
+func TestGenerateContract_Deterministic(t *testing.T) {
+	f := newContractFixture(t)
+
+	first, err := f.contracts.GenerateContract(f.transaction.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !bytes.HasPrefix(first.Content, []byte("%PDF-")) || first.DocumentHash != documentHash(first.Content) {
+		t.Error("Expected a PDF stored with its SHA-256")
+	}
+	if first.TemplateCode != DefaultContractTemplateCode || first.TemplateVersion != 1 || len(f.templateRepo.templates) != 1 {
+		t.Errorf("Expected the FINANCING template seeded as version 1, got %s v%d", first.TemplateCode, first.TemplateVersion)
+	}
+
+	second, err := f.contracts.GenerateContract(f.transaction.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if second.ID != first.ID || len(f.documentRepo.documents) != 1 {
+		t.Error("Expected the unchanged document reused")
+	}
+}
+
+// Test: A new template version supersedes the pending document and voids the code sent for it
+func TestCreateTemplate_SupersedesPendingDocument(t *testing.T) {
+	f := newContractFixture(t)
+	if _, err := f.contracts.RequestSignature(f.transaction.ID); err != nil {
+		t.Fatalf("Expected code sent, got %v", err)
+	}
+	code := f.sender.lastCode()
+
+	if _, err := f.contracts.CreateTemplate(ContractTemplateRequest{Code: "gadget", Title: "Perjanjian", Body: "{{.Unknown}}"}); err == nil {
+		t.Error("Expected a template with unknown fields rejected")
+	}
+	template, err := f.contracts.CreateTemplate(ContractTemplateRequest{
+		Code:  "gadget",
+		Title: "Perjanjian Pembiayaan Gadget",
+		Body:  "Konsumen {{.ConsumerName}} membayar {{.Tenor}} x {{rupiah .InstallmentAmount}}.",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if template.Code != "GADGET" || template.Version != 1 || !template.Active {
+		t.Errorf("Expected GADGET version 1 active, got %+v", template)
+	}
+
+	document, err := f.contracts.GenerateContract(f.transaction.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if document.TemplateCode != "GADGET" || f.documentRepo.documents[0].Status != model.ContractDocumentSuperseded {
+		t.Error("Expected the product template used and the first document superseded")
+	}
+	if f.signatureRepo.signatures[0].Status != model.SignatureStatusExpired {
+		t.Errorf("Expected the earlier code voided, got %s", f.signatureRepo.signatures[0].Status)
+	}
+	if _, err := f.contracts.SignContract(f.transaction.ID, SignContractRequest{OTP: code}); err == nil {
+		t.Error("Expected the code of the superseded document rejected")
+	}
+}
+
+// Test: The right code signs the document, keeps the evidence and activates the contract
+func TestSignContract_Valid(t *testing.T) {
+	f := newContractFixture(t)
+
+	signature, err := f.contracts.RequestSignature(f.transaction.ID)
+	if err != nil {
+		t.Fatalf("Expected code sent, got %v", err)
+	}
+	if signature.OTPChannel != notification.ChannelSMS || signature.OTPHash == "" || signature.OTPHash == f.sender.lastCode() {
+		t.Errorf("Expected only the code hash stored, got %+v", signature)
+	}
+
+	signed, err := f.contracts.SignContract(f.transaction.ID, SignContractRequest{
+		OTP:       f.sender.lastCode(),
+		IPAddress: "10.0.0.7",
+		UserAgent: "Mozilla/5.0",
+	})
+	if err != nil {
+		t.Fatalf("Expected contract signed, got %v", err)
+	}
+	if signed.Status != model.SignatureStatusSigned || signed.SignedAt == nil || signed.IPAddress != "10.0.0.7" ||
+		signed.UserAgent != "Mozilla/5.0" || signed.DocumentHash != f.documentRepo.documents[0].DocumentHash {
+		t.Errorf("Expected signature evidence stored, got %+v", signed)
+	}
+	if f.documentRepo.documents[0].Status != model.ContractDocumentSigned {
+		t.Error("Expected the document signed")
+	}
+	transaction, _ := f.transactionRepo.GetByID(f.transaction.ID)
+	if transaction.Status != model.TransactionStatusActive {
+		t.Errorf("Expected ACTIVE after signing, got %s", transaction.Status)
+	}
+	if _, err := f.contracts.RequestSignature(f.transaction.ID); err == nil {
+		t.Error("Expected no new code for a signed contract")
+	}
+}
+
+// Test: Wrong codes count down and lock the request at the limit
+func TestSignContract_Lock(t *testing.T) {
+	f := newContractFixture(t)
+	if _, err := f.contracts.RequestSignature(f.transaction.ID); err != nil {
+		t.Fatalf("Expected code sent, got %v", err)
+	}
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		_, err := f.contracts.SignContract(f.transaction.ID, SignContractRequest{OTP: f.wrongCode()})
+		if attempt < 3 && !errors.Is(err, ErrInvalidOTP) {
+			t.Fatalf("Expected ErrInvalidOTP on attempt %d, got %v", attempt, err)
+		}
+		if attempt == 3 && !errors.Is(err, ErrSignatureLocked) {
+			t.Fatalf("Expected ErrSignatureLocked on attempt 3, got %v", err)
+		}
+	}
+
+	if _, err := f.contracts.SignContract(f.transaction.ID, SignContractRequest{OTP: f.sender.lastCode()}); err == nil {
+		t.Error("Expected the right code rejected once locked")
+	}
+	if f.signatureRepo.signatures[0].Status != model.SignatureStatusLocked || f.signatureRepo.signatures[0].Attempts != 3 {
+		t.Errorf("Expected LOCKED after 3 attempts, got %+v", f.signatureRepo.signatures[0])
+	}
+	f.signatureRepo.signatures[0].CreatedAt = time.Now().Add(-time.Hour)
+	if _, err := f.contracts.RequestSignature(f.transaction.ID); !errors.Is(err, ErrSignatureLocked) {
+		t.Errorf("Expected no new code once locked, got %v", err)
+	}
+}
+
+// Test: Wrong codes carry over to the next code and the codes sent per contract are capped
+func TestRequestSignature_Resend(t *testing.T) {
+	f := newContractFixture(t)
+	if _, err := f.contracts.RequestSignature(f.transaction.ID); err != nil {
+		t.Fatalf("Expected code sent, got %v", err)
+	}
+	if _, err := f.contracts.SignContract(f.transaction.ID, SignContractRequest{OTP: f.wrongCode()}); !errors.Is(err, ErrInvalidOTP) {
+		t.Fatalf("Expected ErrInvalidOTP, got %v", err)
+	}
+	if _, err := f.contracts.RequestSignature(f.transaction.ID); !errors.Is(err, ErrOTPCooldown) {
+		t.Errorf("Expected ErrOTPCooldown, got %v", err)
+	}
+
+	for send := 2; send <= 3; send++ {
+		f.signatureRepo.signatures[len(f.signatureRepo.signatures)-1].CreatedAt = time.Now().Add(-time.Hour)
+		signature, err := f.contracts.RequestSignature(f.transaction.ID)
+		if err != nil {
+			t.Fatalf("Expected code %d sent, got %v", send, err)
+		}
+		if signature.Attempts != 1 {
+			t.Errorf("Expected the wrong code carried over, got %d attempts", signature.Attempts)
+		}
+	}
+
+	f.signatureRepo.signatures[len(f.signatureRepo.signatures)-1].CreatedAt = time.Now().Add(-time.Hour)
+	if _, err := f.contracts.RequestSignature(f.transaction.ID); err == nil {
+		t.Error("Expected no fourth code, got nil")
+	}
+	if len(f.signatureRepo.signatures) != 3 {
+		t.Errorf("Expected 3 codes sent, got %d", len(f.signatureRepo.signatures))
+	}
+}
+
+// Test: An expired code is rejected and a failed delivery leaves no pending request
+func TestSignContract_Expired(t *testing.T) {
+	f := newContractFixture(t)
+	if _, err := f.contracts.RequestSignature(f.transaction.ID); err != nil {
+		t.Fatalf("Expected code sent, got %v", err)
+	}
+	f.signatureRepo.signatures[0].OTPExpiresAt = time.Now().Add(-time.Second)
+
+	if _, err := f.contracts.SignContract(f.transaction.ID, SignContractRequest{OTP: f.sender.lastCode()}); !errors.Is(err, ErrOTPExpired) {
+		t.Errorf("Expected ErrOTPExpired, got %v", err)
+	}
+	if f.signatureRepo.signatures[0].Status != model.SignatureStatusExpired {
+		t.Errorf("Expected EXPIRED, got %s", f.signatureRepo.signatures[0].Status)
+	}
+
+	f.sender.fail = true
+	if _, err := f.contracts.RequestSignature(f.transaction.ID); err == nil {
+		t.Error("Expected the request to fail when no channel delivers")
+	}
+	if _, err := f.signatureRepo.GetPending(f.transaction.ID); err == nil {
+		t.Error("Expected no pending request after a failed delivery")
+	}
+}
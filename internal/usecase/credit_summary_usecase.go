@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"main/internal/model"
+	"main/internal/repository"
+)
+
+// CreditSummaryUsecase defines the consolidated credit position of a consumer
+type CreditSummaryUsecase interface {
+	GetCreditSummary(consumerID uint) (*CreditSummary, error)
+}
+
+// TenorLimitSummary is the limit position of one tenor
+type TenorLimitSummary struct {
+	Tenor          int     `json:"tenor"`
+	LimitAmount    float64 `json:"limit_amount"`
+	UsedAmount     float64 `json:"used_amount"`
+	HeldAmount     float64 `json:"held_amount"`
+	Available      float64 `json:"available_amount"`
+	UtilisationPct float64 `json:"utilisation_pct"`
+}
+
+// NextDueInstallment is the earliest unpaid installment across the consumer's active contracts
+type NextDueInstallment struct {
+	TransactionID  uint      `json:"transaction_id"`
+	ContractNumber string    `json:"contract_number"`
+	Sequence       int       `json:"sequence"`
+	DueDate        time.Time `json:"due_date"`
+	Amount         float64   `json:"amount"` // outstanding installment amount
+	LateFee        float64   `json:"late_fee"`
+	DaysPastDue    int       `json:"days_past_due"`
+}
+
+// CreditSummary is the consolidated credit position of a consumer
+type CreditSummary struct {
+	ConsumerID           uint                `json:"consumer_id"`
+	Tenors               []TenorLimitSummary `json:"tenors"`
+	Totals               TenorLimitSummary   `json:"totals"` // Tenor is 0
+	ActiveContracts      int                 `json:"active_contracts"`
+	OutstandingPrincipal float64             `json:"outstanding_principal"`
+	NextDue              *NextDueInstallment `json:"next_due,omitempty"`
+	WorstDaysPastDue     int                 `json:"worst_days_past_due"`
+	WrittenOffContracts  int                 `json:"written_off_contracts,omitempty"`
+}
+
+// creditSummaryUsecase is the implementation of CreditSummaryUsecase
+type creditSummaryUsecase struct {
+	consumerRepo    repository.ConsumerRepository
+	limitRepo       repository.ConsumerLimitRepository
+	transactionRepo repository.TransactionRepository
+	installmentRepo repository.InstallmentRepository
+}
+
+// NewCreditSummaryUsecase creates a new instance of CreditSummaryUsecase
+func NewCreditSummaryUsecase(
+	consumerRepo repository.ConsumerRepository,
+	limitRepo repository.ConsumerLimitRepository,
+	transactionRepo repository.TransactionRepository,
+	installmentRepo repository.InstallmentRepository,
+) CreditSummaryUsecase {
+	return &creditSummaryUsecase{
+		consumerRepo:    consumerRepo,
+		limitRepo:       limitRepo,
+		transactionRepo: transactionRepo,
+		installmentRepo: installmentRepo,
+	}
+}
+
+// GetCreditSummary combines the per-tenor limits with the open contracts of the consumer.
+// Outstanding principal and the next due installment cover ACTIVE contracts; the worst DPD
+// also includes DEFAULTED and WRITTEN_OFF contracts.
+func (u *creditSummaryUsecase) GetCreditSummary(consumerID uint) (*CreditSummary, error) {
+	if _, err := u.consumerRepo.GetByID(consumerID); err != nil {
+		return nil, errors.New("konsumen tidak ditemukan")
+	}
+
+	limits, err := u.limitRepo.GetByConsumerID(consumerID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(limits, func(i, j int) bool { return limits[i].Tenor < limits[j].Tenor })
+
+	summary := &CreditSummary{
+		ConsumerID: consumerID,
+		Tenors:     make([]TenorLimitSummary, 0, len(limits)),
+	}
+	for _, limit := range limits {
+		summary.Tenors = append(summary.Tenors, tenorSummary(limit.Tenor, limit.LimitAmount, limit.UsedAmount, limit.HeldAmount))
+		summary.Totals.LimitAmount += limit.LimitAmount
+		summary.Totals.UsedAmount += limit.UsedAmount
+		summary.Totals.HeldAmount += limit.HeldAmount
+	}
+	summary.Totals = tenorSummary(0, summary.Totals.LimitAmount, summary.Totals.UsedAmount, summary.Totals.HeldAmount)
+
+	transactions, err := u.transactionRepo.GetByConsumerID(consumerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var principal float64
+	for _, transaction := range transactions {
+		switch transaction.Status {
+		case model.TransactionStatusDefaulted:
+			summary.WorstDaysPastDue = max(summary.WorstDaysPastDue, transaction.DaysPastDue)
+			continue
+		case model.TransactionStatusWrittenOff:
+			summary.WrittenOffContracts++
+			summary.WorstDaysPastDue = max(summary.WorstDaysPastDue, transaction.DaysPastDue)
+			continue
+		case model.TransactionStatusActive:
+		default:
+			continue
+		}
+
+		summary.ActiveContracts++
+		summary.WorstDaysPastDue = max(summary.WorstDaysPastDue, transaction.DaysPastDue)
+
+		installments, err := u.installmentRepo.GetByTransactionID(transaction.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, installment := range installments {
+			outstanding := installment.Outstanding()
+			if outstanding <= 0 || installment.Amount <= 0 {
+				continue
+			}
+			principal += installment.PrincipalAmount * outstanding / installment.Amount
+
+			if summary.NextDue == nil || installment.DueDate.Before(summary.NextDue.DueDate) {
+				summary.NextDue = &NextDueInstallment{
+					TransactionID:  transaction.ID,
+					ContractNumber: transaction.ContractNumber,
+					Sequence:       installment.Sequence,
+					DueDate:        installment.DueDate,
+					Amount:         roundMoney(outstanding),
+					LateFee:        installment.OutstandingLateFee(),
+					DaysPastDue:    installment.DaysPastDue,
+				}
+			}
+		}
+	}
+	summary.OutstandingPrincipal = roundMoney(principal)
+
+	return summary, nil
+}
+
+// tenorSummary derives the available amount and utilisation from the limit figures
+func tenorSummary(tenor int, limitAmount, used, held float64) TenorLimitSummary {
+	summary := TenorLimitSummary{
+		Tenor:       tenor,
+		LimitAmount: roundMoney(limitAmount),
+		UsedAmount:  roundMoney(used),
+		HeldAmount:  roundMoney(held),
+		Available:   roundMoney(max(limitAmount-used-held, 0)),
+	}
+	if limitAmount > 0 {
+		summary.UtilisationPct = roundMoney((used + held) / limitAmount * 100)
+	}
+	return summary
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"main/internal/model"
+)
+
+// Test: Summary combines limits, holds and open contracts
+func TestGetCreditSummary(t *testing.T) {
+	consumerRepo := newVerifiedConsumerRepository()
+	limitRepo := NewMockConsumerLimitRepository()
+	transactionRepo := NewMockTransactionRepository()
+	installmentRepo := &MockInstallmentRepository{}
+
+	limitRepo.Create(&model.ConsumerLimit{ConsumerID: 1, Tenor: 6, LimitAmount: 5000000, HeldAmount: 1000000})
+	limitRepo.Create(&model.ConsumerLimit{ConsumerID: 1, Tenor: 3, LimitAmount: 10000000, UsedAmount: 6000000})
+
+	first := bookTestContract(transactionRepo, installmentRepo, time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC))
+	first.DaysPastDue = 5
+	installmentRepo.installments[0].PaidAmount = installmentRepo.installments[0].Amount
+	bookTestContract(transactionRepo, installmentRepo, time.Date(2026, 2, 10, 10, 0, 0, 0, time.UTC))
+
+	transactionRepo.Create(&model.Transaction{ConsumerID: 1, ContractNumber: "CTR-OLD", Tenor: 1, OTR: 500000, Status: model.TransactionStatusDefaulted, DaysPastDue: 95})
+	transactionRepo.Create(&model.Transaction{ConsumerID: 1, ContractNumber: "CTR-DONE", Tenor: 1, OTR: 500000, Status: model.TransactionStatusCompleted})
+
+	uc := NewCreditSummaryUsecase(consumerRepo, limitRepo, transactionRepo, installmentRepo)
+	summary, err := uc.GetCreditSummary(1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(summary.Tenors) != 2 || summary.Tenors[0].Tenor != 3 {
+		t.Fatalf("Expected tenors 3 and 6 in order, got %+v", summary.Tenors)
+	}
+	if summary.Tenors[0].Available != 4000000 || summary.Tenors[0].UtilisationPct != 60 {
+		t.Errorf("Expected tenor 3 available 4000000 at 60%%, got %.2f at %.2f%%", summary.Tenors[0].Available, summary.Tenors[0].UtilisationPct)
+	}
+	if summary.Tenors[1].Available != 4000000 || summary.Tenors[1].UtilisationPct != 20 {
+		t.Errorf("Expected tenor 6 available 4000000 at 20%%, got %.2f at %.2f%%", summary.Tenors[1].Available, summary.Tenors[1].UtilisationPct)
+	}
+	if summary.Totals.Available != 8000000 || summary.Totals.UtilisationPct != 46.67 {
+		t.Errorf("Expected total available 8000000 at 46.67%%, got %.2f at %.2f%%", summary.Totals.Available, summary.Totals.UtilisationPct)
+	}
+
+	if summary.ActiveContracts != 2 || summary.OutstandingPrincipal != 5000000 {
+		t.Errorf("Expected 2 active contracts with 5000000 principal, got %d and %.2f", summary.ActiveContracts, summary.OutstandingPrincipal)
+	}
+	if summary.NextDue == nil || summary.NextDue.TransactionID != first.ID || summary.NextDue.Sequence != 2 {
+		t.Errorf("Expected next due installment 2 of the first contract, got %+v", summary.NextDue)
+	}
+	if summary.WorstDaysPastDue != 95 {
+		t.Errorf("Expected worst DPD 95 from the defaulted contract, got %d", summary.WorstDaysPastDue)
+	}
+}
+
+// Test: Consumer without limits or contracts gets an empty summary
+func TestGetCreditSummary_Empty(t *testing.T) {
+	uc := NewCreditSummaryUsecase(newVerifiedConsumerRepository(), NewMockConsumerLimitRepository(), NewMockTransactionRepository(), &MockInstallmentRepository{})
+
+	summary, err := uc.GetCreditSummary(1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(summary.Tenors) != 0 || summary.NextDue != nil || summary.Totals.UtilisationPct != 0 {
+		t.Errorf("Expected empty summary, got %+v", summary)
+	}
+
+	if _, err := uc.GetCreditSummary(99); err == nil {
+		t.Error("Expected error for unknown consumer, got nil")
+	}
+}
@@ -0,0 +1,270 @@
+package usecase
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"main/internal/model"
+	"main/internal/repository"
+)
+
+// MockInstallmentRepository for testing
+type MockInstallmentRepository struct {
+	installments []*model.Installment
+}
+
+func (m *MockInstallmentRepository) CreateBatch(installments []model.Installment) error {
+	for i := range installments {
+		installment := installments[i]
+		installment.ID = uint(len(m.installments) + 1)
+		m.installments = append(m.installments, &installment)
+	}
+	return nil
+}
+
+func (m *MockInstallmentRepository) GetByTransactionID(transactionID uint) ([]model.Installment, error) {
+	var installments []model.Installment
+	for _, installment := range m.installments {
+		if installment.TransactionID == transactionID {
+			installments = append(installments, *installment)
+		}
+	}
+	return installments, nil
+}
+
+func (m *MockInstallmentRepository) GetByTransactionIDForUpdate(transactionID uint) ([]model.Installment, error) {
+	return m.GetByTransactionID(transactionID)
+}
+
+func (m *MockInstallmentRepository) Update(installment *model.Installment) error {
+	for i, existing := range m.installments {
+		if existing.ID == installment.ID {
+			updated := *installment
+			m.installments[i] = &updated
+		}
+	}
+	return nil
+}
+
+func (m *MockInstallmentRepository) UpdateAging(installment *model.Installment) error {
+	for _, existing := range m.installments {
+		if existing.ID == installment.ID {
+			existing.DaysPastDue = installment.DaysPastDue
+			existing.LateFee = installment.LateFee
+			existing.UpdatedAt = installment.UpdatedAt
+		}
+	}
+	return nil
+}
+
+// MockLateFeeChargeRepository for testing
+type MockLateFeeChargeRepository struct {
+	charges []model.LateFeeCharge
+}
+
+func (m *MockLateFeeChargeRepository) Create(charge *model.LateFeeCharge) error {
+	charge.ID = uint(len(m.charges) + 1)
+	m.charges = append(m.charges, *charge)
+	return nil
+}
+
+func (m *MockLateFeeChargeRepository) GetByTransactionID(transactionID uint) ([]model.LateFeeCharge, error) {
+	var charges []model.LateFeeCharge
+	for _, charge := range m.charges {
+		if charge.TransactionID == transactionID {
+			charges = append(charges, charge)
+		}
+	}
+	return charges, nil
+}
+
+func (m *MockLateFeeChargeRepository) Update(charge *model.LateFeeCharge) error {
+	for i := range m.charges {
+		if m.charges[i].ID == charge.ID {
+			m.charges[i] = *charge
+		}
+	}
+	return nil
+}
+
+// bookTestContract books a 3 month contract of 3.000.000 + 300.000 interest for consumer 1
+func bookTestContract(transactionRepo *MockTransactionRepository, installmentRepo *MockInstallmentRepository, start time.Time) *model.Transaction {
+	transaction := &model.Transaction{
+		ConsumerID:        1,
+		ContractNumber:    fmt.Sprintf("CTR-%s", start.Format("20060102")),
+		Tenor:             3,
+		OTR:               3000000,
+		InterestAmount:    300000,
+		InstallmentAmount: 1100000,
+		Status:            model.TransactionStatusActive,
+		CreatedAt:         start,
+	}
+	transactionRepo.Create(transaction)
+	installmentRepo.CreateBatch(buildInstallmentSchedule(transaction.ID, 3000000, 300000, 3, start))
+	return transaction
+}
+
+// newTestDelinquency returns the aging job over the given repositories with the default policy
+func newTestDelinquency(transactionRepo *MockTransactionRepository, installmentRepo *MockInstallmentRepository,
+	chargeRepo *MockLateFeeChargeRepository, notifier EventNotifier) DelinquencyUsecase {
+	transactor := newTestTransactor(repository.Repositories{
+		Transactions:   transactionRepo,
+		Installments:   installmentRepo,
+		LateFeeCharges: chargeRepo,
+	})
+	return NewDelinquencyUsecase(transactionRepo, installmentRepo, transactor, notifier, DefaultDelinquencyPolicy())
+}
+
+// newDelinquencyFixture books the test contract starting 1 Jan 2026
+func newDelinquencyFixture() (DelinquencyUsecase, *MockTransactionRepository, *MockInstallmentRepository) {
+	transactionRepo := NewMockTransactionRepository()
+	installmentRepo := &MockInstallmentRepository{}
+	bookTestContract(transactionRepo, installmentRepo, time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC))
+
+	return newTestDelinquency(transactionRepo, installmentRepo, &MockLateFeeChargeRepository{}, &MockEventNotifier{}),
+		transactionRepo, installmentRepo
+}
+
+// Test: Schedule splits principal and interest and puts the rounding on the last installment
+func TestBuildInstallmentSchedule(t *testing.T) {
+	start := time.Date(2026, 1, 15, 9, 30, 0, 0, time.UTC)
+	schedule := buildInstallmentSchedule(7, 1000000, 100000, 3, start)
+
+	if len(schedule) != 3 {
+		t.Fatalf("Expected 3 installments, got %d", len(schedule))
+	}
+
+	total := 0.0
+	for _, installment := range schedule {
+		total += installment.Amount
+	}
+	if roundMoney(total) != 1100000 {
+		t.Errorf("Expected schedule total 1100000, got %.2f", total)
+	}
+
+	if schedule[2].PrincipalAmount != 333333.34 {
+		t.Errorf("Expected last principal 333333.34, got %.2f", schedule[2].PrincipalAmount)
+	}
+	if !schedule[0].DueDate.Equal(time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected first due date 2026-02-15, got %s", schedule[0].DueDate)
+	}
+}
+
+// Test: Due dates past the end of a shorter month fall on its last day instead of spilling into the next
+func TestBuildInstallmentSchedule_MonthEnd(t *testing.T) {
+	tests := []struct {
+		name  string
+		start time.Time
+		tenor int
+		want  map[int]time.Time // sequence -> due date
+	}{
+		{"booked 31 Jan", time.Date(2026, 1, 31, 15, 0, 0, 0, time.UTC), 3, map[int]time.Time{
+			1: time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
+			2: time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+			3: time.Date(2026, 4, 30, 0, 0, 0, 0, time.UTC),
+		}},
+		{"booked 29 Feb", time.Date(2028, 2, 29, 9, 0, 0, 0, time.UTC), 12, map[int]time.Time{
+			1:  time.Date(2028, 3, 29, 0, 0, 0, 0, time.UTC),
+			12: time.Date(2029, 2, 28, 0, 0, 0, 0, time.UTC),
+		}},
+	}
+
+	for _, tt := range tests {
+		schedule := buildInstallmentSchedule(1, 3000000, 300000, tt.tenor, tt.start)
+		for sequence, want := range tt.want {
+			if got := schedule[sequence-1].DueDate; !got.Equal(want) {
+				t.Errorf("%s: expected installment %d due %s, got %s", tt.name, sequence, want.Format("2006-01-02"), got.Format("2006-01-02"))
+			}
+		}
+	}
+}
+
+// Test: Overdue installment accrues daily late fee and moves to DPK
+func TestRunDailyAging_LateFee(t *testing.T) {
+	uc, transactionRepo, _ := newDelinquencyFixture()
+
+	// First installment due 1 Feb, 10 days late
+	result, err := uc.RunDailyAging(time.Date(2026, 2, 11, 0, 30, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.ContractsDelinquent != 1 {
+		t.Errorf("Expected 1 delinquent contract, got %d", result.ContractsDelinquent)
+	}
+
+	transaction, _ := transactionRepo.GetByID(1)
+	if transaction.DaysPastDue != 10 || transaction.Collectibility != 2 {
+		t.Errorf("Expected DPD 10 collectibility 2, got DPD %d collectibility %d", transaction.DaysPastDue, transaction.Collectibility)
+	}
+	// 1.100.000 * 0.1% * 10 days
+	if transaction.LateFeeAmount != 11000 {
+		t.Errorf("Expected late fee 11000, got %.2f", transaction.LateFeeAmount)
+	}
+}
+
+// Test: Running twice for the same date does not double the late fee or its charge history
+func TestRunDailyAging_Idempotent(t *testing.T) {
+	transactionRepo := NewMockTransactionRepository()
+	installmentRepo := &MockInstallmentRepository{}
+	chargeRepo := &MockLateFeeChargeRepository{}
+	bookTestContract(transactionRepo, installmentRepo, time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC))
+	uc := newTestDelinquency(transactionRepo, installmentRepo, chargeRepo, &MockEventNotifier{})
+	asOf := time.Date(2026, 2, 11, 0, 30, 0, 0, time.UTC)
+
+	uc.RunDailyAging(asOf)
+	uc.RunDailyAging(asOf)
+
+	transaction, _ := transactionRepo.GetByID(1)
+	if transaction.LateFeeAmount != 11000 {
+		t.Errorf("Expected late fee 11000 after re-run, got %.2f", transaction.LateFeeAmount)
+	}
+	if len(chargeRepo.charges) != 1 || chargeRepo.charges[0].LateFee != 11000 ||
+		!chargeRepo.charges[0].BusinessDate.Equal(time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected one charge of 11000 on 2026-02-11, got %+v", chargeRepo.charges)
+	}
+}
+
+// Test: Late fee is capped and the contract defaults past the DPD threshold
+func TestRunDailyAging_CapAndDefault(t *testing.T) {
+	uc, transactionRepo, installmentRepo := newDelinquencyFixture()
+
+	// 1 Feb + 125 days
+	uc.RunDailyAging(time.Date(2026, 6, 6, 0, 30, 0, 0, time.UTC))
+
+	transaction, _ := transactionRepo.GetByID(1)
+	if transaction.Status != model.TransactionStatusDefaulted {
+		t.Errorf("Expected DEFAULTED, got %s", transaction.Status)
+	}
+	if transaction.Collectibility != 4 {
+		t.Errorf("Expected collectibility 4 (Diragukan), got %d", transaction.Collectibility)
+	}
+
+	installments, _ := installmentRepo.GetByTransactionID(1)
+	if installments[0].LateFee != 110000 {
+		t.Errorf("Expected late fee capped at 110000, got %.2f", installments[0].LateFee)
+	}
+}
+
+// Test: Paid installments are not aged
+func TestRunDailyAging_PaidInstallment(t *testing.T) {
+	uc, transactionRepo, installmentRepo := newDelinquencyFixture()
+
+	installments, _ := installmentRepo.GetByTransactionID(1)
+	paid := installments[0]
+	paid.PaidAmount = paid.Amount
+	paid.Status = model.InstallmentStatusPaid
+	installmentRepo.Update(&paid)
+
+	uc.RunDailyAging(time.Date(2026, 2, 11, 0, 30, 0, 0, time.UTC))
+
+	transaction, _ := transactionRepo.GetByID(1)
+	if transaction.DaysPastDue != 0 || transaction.Collectibility != 1 {
+		t.Errorf("Expected current contract, got DPD %d collectibility %d", transaction.DaysPastDue, transaction.Collectibility)
+	}
+
+	view, _ := uc.GetDelinquency(1)
+	if view.CollectibilityLabel != "Lancar" {
+		t.Errorf("Expected Lancar, got %s", view.CollectibilityLabel)
+	}
+}
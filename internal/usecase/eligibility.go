@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"main/internal/model"
+)
+
+// Eligibility error codes returned to API clients
+const (
+	CodeDateOfBirthMissing  = "ELIGIBILITY_DOB_MISSING"
+	CodeDateOfBirthInFuture = "ELIGIBILITY_DOB_IN_FUTURE"
+	CodeBelowMinimumAge     = "ELIGIBILITY_BELOW_MIN_AGE"
+	CodeAboveMaximumAge     = "ELIGIBILITY_ABOVE_MAX_AGE_AT_MATURITY"
+)
+
+// longestTenorMonths is the longest tenor offered, used for the age check at registration
+const longestTenorMonths = 6
+
+// EligibilityError is a failed eligibility rule with a machine readable code
+type EligibilityError struct {
+	Code    string
+	Message string
+}
+
+func (e *EligibilityError) Error() string {
+	return e.Message
+}
+
+// EligibilityRules holds the financing policy age limits
+type EligibilityRules struct {
+	MinAge           int // minimum age for unmarried borrowers
+	MinAgeMarried    int // minimum age for borrowers who are or have been married
+	MaxAgeAtMaturity int // maximum age at the end of the tenor
+}
+
+// DefaultEligibilityRules returns the financing policy defaults: 21 (17 if married) up to 60 at maturity
+func DefaultEligibilityRules() EligibilityRules {
+	return EligibilityRules{
+		MinAge:           21,
+		MinAgeMarried:    17,
+		MaxAgeAtMaturity: 60,
+	}
+}
+
+// CheckRegistration evaluates the age rules against the longest available tenor
+func (r EligibilityRules) CheckRegistration(consumer *model.Consumer, now time.Time) error {
+	return r.check(consumer, longestTenorMonths, now)
+}
+
+// CheckTransaction evaluates the age rules against the maturity date of the given tenor
+func (r EligibilityRules) CheckTransaction(consumer *model.Consumer, tenor int, now time.Time) error {
+	return r.check(consumer, tenor, now)
+}
+
+func (r EligibilityRules) check(consumer *model.Consumer, tenor int, now time.Time) error {
+	if consumer.DateOfBirth.IsZero() {
+		return &EligibilityError{Code: CodeDateOfBirthMissing, Message: "tanggal lahir wajib diisi"}
+	}
+	if consumer.DateOfBirth.After(now) {
+		return &EligibilityError{Code: CodeDateOfBirthInFuture, Message: "tanggal lahir tidak valid"}
+	}
+
+	minAge := r.MinAge
+	if consumer.HasBeenMarried() {
+		minAge = r.MinAgeMarried
+	}
+	if ageAt(consumer.DateOfBirth, now) < minAge {
+		return &EligibilityError{
+			Code:    CodeBelowMinimumAge,
+			Message: fmt.Sprintf("usia minimum peminjam adalah %d tahun", minAge),
+		}
+	}
+
+	maturity := now.AddDate(0, tenor, 0)
+	if ageAt(consumer.DateOfBirth, maturity) > r.MaxAgeAtMaturity {
+		return &EligibilityError{
+			Code:    CodeAboveMaximumAge,
+			Message: fmt.Sprintf("usia peminjam melebihi %d tahun pada akhir tenor %d bulan", r.MaxAgeAtMaturity, tenor),
+		}
+	}
+
+	return nil
+}
+
+// ageAt returns the age in completed years on date t
+func ageAt(dob, t time.Time) int {
+	age := t.Year() - dob.Year()
+	if t.Month() < dob.Month() || (t.Month() == dob.Month() && t.Day() < dob.Day()) {
+		age--
+	}
+	return age
+}
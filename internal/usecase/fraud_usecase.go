@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"main/internal/fraud"
+	"main/internal/model"
+	"main/internal/repository"
+)
+
+var ErrTransactionDenied = errors.New("transaksi ditolak oleh aturan fraud")
+
+// assessFraud evaluates the velocity rules for a transaction request.
+// Denied requests are stored right away since no transaction will reference them.
+func (u *transactionUsecase) assessFraud(consumer *model.Consumer, limit *model.ConsumerLimit, transaction *model.Transaction) (*model.FraudAssessment, error) {
+	now := time.Now()
+	since := now.Add(-u.fraudEngine.MaxWindow())
+
+	consumerHistory, err := u.transactionRepo.GetByConsumerSince(consumer.ID, since)
+	if err != nil {
+		return nil, err
+	}
+	priorCount, err := u.transactionRepo.CountByConsumerID(consumer.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var merchantHistory []model.Transaction
+	if transaction.MerchantID != 0 {
+		if merchantHistory, err = u.transactionRepo.GetByMerchantSince(transaction.MerchantID, since); err != nil {
+			return nil, err
+		}
+	}
+
+	result := u.fraudEngine.Evaluate(fraud.Input{
+		ConsumerID:        consumer.ID,
+		MerchantID:        transaction.MerchantID,
+		Amount:            transaction.FinancedAmount(),
+		AssetName:         transaction.AssetName,
+		LimitAmount:       limit.LimitAmount,
+		ConsumerCreatedAt: consumer.CreatedAt,
+		PriorTransactions: int(priorCount),
+		ConsumerHistory:   consumerHistory,
+		MerchantHistory:   merchantHistory,
+		Now:               now,
+	})
+
+	hits, _ := json.Marshal(result.Hits)
+	assessment := &model.FraudAssessment{
+		ConsumerID:     consumer.ID,
+		ContractNumber: transaction.ContractNumber,
+		Score:          result.Score,
+		Decision:       result.Decision,
+		TriggeredRules: string(hits),
+		CreatedAt:      now,
+	}
+
+	transaction.RiskScore = result.Score
+	transaction.RiskDecision = result.Decision
+
+	if result.Decision != fraud.DecisionAllow {
+		log.Printf("⚠ Fraud rules %s kontrak %s (skor %d)\n", result.Decision, transaction.ContractNumber, result.Score)
+	}
+	if result.Decision == fraud.DecisionDeny {
+		if err := u.assessmentRepo.Create(assessment); err != nil {
+			return nil, err
+		}
+	}
+	return assessment, nil
+}
+
+// recordAssessment stores the assessment linked to the booked transaction
+func recordAssessment(assessmentRepo repository.FraudAssessmentRepository, transaction *model.Transaction, assessment *model.FraudAssessment) error {
+	assessment.TransactionID = transaction.ID
+	return assessmentRepo.Create(assessment)
+}
+
+func (u *transactionUsecase) GetFlaggedTransactions() ([]model.Transaction, error) {
+	return u.transactionRepo.GetByStatus(model.TransactionStatusPendingReview)
+}
+
+// ReviewFlaggedTransaction activates a transaction held by the fraud rules, or rejects it and releases the limit.
+// An approved transaction still waits for the consumer signature when the policy requires one.
+func (u *transactionUsecase) ReviewFlaggedTransaction(id uint, approve bool, reviewer string) (*model.Transaction, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if strings.TrimSpace(reviewer) == "" {
+		return nil, errors.New("nama analis tidak boleh kosong")
+	}
+
+	transaction, err := u.transactionRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if transaction.Status != model.TransactionStatusPendingReview {
+		return nil, errors.New("transaksi tidak sedang menunggu review fraud")
+	}
+
+	if approve && u.policy.RequireSignature {
+		transaction.Status = model.TransactionStatusPendingSignature
+	} else if approve {
+		transaction.Status = model.TransactionStatusActive
+	} else {
+		transaction.Status = model.TransactionStatusRejected
+	}
+	transaction.UpdatedAt = time.Now()
+
+	err = u.transactor.Transaction(func(tx *repository.Repositories) error {
+		switch transaction.Status {
+		case model.TransactionStatusActive:
+			if err := u.activate(tx, transaction); err != nil {
+				return err
+			}
+		case model.TransactionStatusRejected:
+			if err := releaseLimit(tx.Limits, transaction.ConsumerID, transaction.Tenor, transaction.FinancedAmount()); err != nil {
+				return err
+			}
+		}
+		if err := tx.Transactions.Update(transaction); err != nil {
+			return err
+		}
+
+		if assessment, err := tx.FraudAssessments.GetByTransactionID(transaction.ID); err == nil {
+			assessment.ReviewedBy = reviewer
+			if err := tx.FraudAssessments.Update(assessment); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.Status == model.TransactionStatusActive {
+		u.notifier.NotifyTransactionBooked(transaction)
+	} else {
+		u.notifier.NotifyStatusChanged(transaction, model.TransactionStatusPendingReview)
+	}
+
+	log.Printf("✓ Review fraud kontrak %s: %s oleh %s\n", transaction.ContractNumber, transaction.Status, reviewer)
+	return transaction, nil
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"math"
+	"time"
+
+	"main/internal/model"
+)
+
+// buildInstallmentSchedule splits principal and interest evenly over the tenor,
+// with monthly due dates starting one month after the start date, on the same day of the month or the last
+// day of shorter months. Rounding differences are absorbed by the last installment.
+func buildInstallmentSchedule(transactionID uint, principal, interest float64, tenor int, start time.Time) []model.Installment {
+	schedule := make([]model.Installment, 0, tenor)
+	principalPart := roundMoney(principal / float64(tenor))
+	interestPart := roundMoney(interest / float64(tenor))
+
+	for i := 1; i <= tenor; i++ {
+		p, in := principalPart, interestPart
+		if i == tenor {
+			p = roundMoney(principal - principalPart*float64(tenor-1))
+			in = roundMoney(interest - interestPart*float64(tenor-1))
+		}
+
+		schedule = append(schedule, model.Installment{
+			TransactionID:   transactionID,
+			Sequence:        i,
+			DueDate:         dateOnly(addMonths(start, i)),
+			PrincipalAmount: p,
+			InterestAmount:  in,
+			Amount:          roundMoney(p + in),
+			Status:          model.InstallmentStatusUnpaid,
+			CreatedAt:       start,
+			UpdatedAt:       start,
+		})
+	}
+	return schedule
+}
+
+// addMonths moves t by whole months, keeping its day of the month clamped to the last day of the target month:
+// 31 January plus one month is the end of February, not 3 March
+func addMonths(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(t.Day(), lastDay)-1)
+}
+
+// roundMoney rounds an amount to whole sen
+func roundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// dateOnly truncates t to midnight in its own location
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// daysBetween returns the number of calendar days from a to b
+func daysBetween(a, b time.Time) int {
+	return int(dateOnly(b).Sub(dateOnly(a)).Hours() / 24)
+}
@@ -0,0 +1,241 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"main/internal/kyc"
+	"main/internal/model"
+	"main/internal/repository"
+)
+
+// KYC decision thresholds for provider scores
+const (
+	faceMatchVerifiedThreshold = 0.80 // at or above: same person
+	faceMatchRejectThreshold   = 0.50 // below: different person
+	livenessThreshold          = 0.70 // below: spoofed selfie
+)
+
+var ErrConsumerNotVerified = errors.New("konsumen belum lolos verifikasi KYC")
+
+// KYCUsecase defines all business logic operations for consumer KYC verification
+type KYCUsecase interface {
+	VerifyConsumer(consumerID uint) (*model.KYCVerification, error)
+	GetManualReviewQueue() ([]KYCReviewItem, error)
+	ReviewConsumer(consumerID uint, decision, reviewer, note string) (*model.KYCVerification, error)
+	GetVerificationHistory(consumerID uint) ([]model.KYCVerification, error)
+}
+
+// KYCReviewItem is one consumer waiting in the manual review queue
+type KYCReviewItem struct {
+	Consumer      model.Consumer         `json:"consumer"`
+	Verification  *model.KYCVerification `json:"latest_verification,omitempty"`
+	WatchlistHits []model.WatchlistHit   `json:"watchlist_hits,omitempty"`
+}
+
+// kycUsecase is the implementation of KYCUsecase
+type kycUsecase struct {
+	consumerRepo     repository.ConsumerRepository
+	verificationRepo repository.KYCVerificationRepository
+	hitRepo          repository.WatchlistHitRepository
+	ocr              kyc.KTPOCRProvider
+	faceMatch        kyc.FaceMatchProvider
+	liveness         kyc.LivenessProvider
+	mu               sync.Mutex
+}
+
+// NewKYCUsecase creates a new instance of KYCUsecase
+func NewKYCUsecase(
+	consumerRepo repository.ConsumerRepository,
+	verificationRepo repository.KYCVerificationRepository,
+	hitRepo repository.WatchlistHitRepository,
+	ocr kyc.KTPOCRProvider,
+	faceMatch kyc.FaceMatchProvider,
+	liveness kyc.LivenessProvider,
+) KYCUsecase {
+	return &kycUsecase{
+		consumerRepo:     consumerRepo,
+		verificationRepo: verificationRepo,
+		hitRepo:          hitRepo,
+		ocr:              ocr,
+		faceMatch:        faceMatch,
+		liveness:         liveness,
+	}
+}
+
+// VerifyConsumer runs OCR, face match and liveness checks and stores the outcome
+func (u *kycUsecase) VerifyConsumer(consumerID uint) (*model.KYCVerification, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	consumer, err := u.consumerRepo.GetByID(consumerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if consumer.KYCStatus == model.KYCStatusVerified || consumer.KYCStatus == model.KYCStatusRejected {
+		return nil, fmt.Errorf("KYC konsumen sudah final (%s)", consumer.KYCStatus)
+	}
+
+	verification := u.runChecks(consumer)
+
+	consumer.KYCStatus = verification.Status
+	consumer.UpdatedAt = time.Now()
+	if err := u.consumerRepo.Update(consumer); err != nil {
+		return nil, err
+	}
+
+	if err := u.verificationRepo.Create(verification); err != nil {
+		return nil, err
+	}
+
+	log.Printf("✓ KYC konsumen %d: %s (%s)\n", consumer.ID, verification.Status, verification.Reason)
+	return verification, nil
+}
+
+// runChecks calls the providers and decides the KYC status.
+// Provider failures never verify a consumer, they route the case to manual review.
+func (u *kycUsecase) runChecks(consumer *model.Consumer) *model.KYCVerification {
+	verification := &model.KYCVerification{
+		ConsumerID: consumer.ID,
+		CreatedAt:  time.Now(),
+	}
+
+	decide := func(status, reason string) *model.KYCVerification {
+		verification.Status = status
+		verification.Reason = reason
+		return verification
+	}
+
+	if consumer.KTPPhoto == "" || consumer.SelfiePhoto == "" {
+		return decide(model.KYCStatusRejected, "foto KTP dan selfie wajib diunggah")
+	}
+
+	ktpData, err := u.ocr.ExtractKTP(consumer.KTPPhoto)
+	if err != nil {
+		return decide(model.KYCStatusManualReview, "OCR KTP gagal: "+err.Error())
+	}
+	verification.OCRNIK = ktpData.NIK
+	verification.OCRName = ktpData.FullName
+
+	if ktpData.NIK != consumer.NIK {
+		return decide(model.KYCStatusRejected, "NIK pada foto KTP tidak sesuai dengan data pendaftaran")
+	}
+
+	liveness, err := u.liveness.CheckLiveness(consumer.SelfiePhoto)
+	if err != nil {
+		return decide(model.KYCStatusManualReview, "pemeriksaan liveness gagal: "+err.Error())
+	}
+	verification.LivenessScore = liveness
+
+	if liveness < livenessThreshold {
+		return decide(model.KYCStatusRejected, "selfie tidak lolos pemeriksaan liveness")
+	}
+
+	faceScore, err := u.faceMatch.MatchFaces(consumer.KTPPhoto, consumer.SelfiePhoto)
+	if err != nil {
+		return decide(model.KYCStatusManualReview, "pencocokan wajah gagal: "+err.Error())
+	}
+	verification.FaceMatchScore = faceScore
+
+	if faceScore < faceMatchRejectThreshold {
+		return decide(model.KYCStatusRejected, "wajah pada selfie tidak cocok dengan foto KTP")
+	}
+	if faceScore < faceMatchVerifiedThreshold {
+		return decide(model.KYCStatusManualReview, "skor pencocokan wajah di bawah ambang verifikasi otomatis")
+	}
+
+	if normalizeName(ktpData.FullName) != normalizeName(consumer.LegalName) {
+		return decide(model.KYCStatusManualReview, "nama pada KTP berbeda dengan nama sah")
+	}
+
+	return decide(model.KYCStatusVerified, "verifikasi otomatis berhasil")
+}
+
+// GetManualReviewQueue lists consumers waiting for an analyst, oldest first
+func (u *kycUsecase) GetManualReviewQueue() ([]KYCReviewItem, error) {
+	consumers, err := u.consumerRepo.GetByKYCStatus(model.KYCStatusManualReview)
+	if err != nil {
+		return nil, err
+	}
+
+	queue := make([]KYCReviewItem, 0, len(consumers))
+	for _, consumer := range consumers {
+		item := KYCReviewItem{Consumer: consumer}
+		if verification, err := u.verificationRepo.GetLatestByConsumerID(consumer.ID); err == nil {
+			item.Verification = verification
+		}
+		if hits, err := u.hitRepo.GetBySubjectNIK(consumer.NIK); err == nil {
+			item.WatchlistHits = hits
+		}
+		queue = append(queue, item)
+	}
+	return queue, nil
+}
+
+// ReviewConsumer records an analyst decision for a consumer in manual review
+func (u *kycUsecase) ReviewConsumer(consumerID uint, decision, reviewer, note string) (*model.KYCVerification, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if decision != model.KYCStatusVerified && decision != model.KYCStatusRejected {
+		return nil, errors.New("keputusan review harus VERIFIED atau REJECTED")
+	}
+	if strings.TrimSpace(reviewer) == "" {
+		return nil, errors.New("nama analis tidak boleh kosong")
+	}
+
+	consumer, err := u.consumerRepo.GetByID(consumerID)
+	if err != nil {
+		return nil, err
+	}
+	if consumer.KYCStatus != model.KYCStatusManualReview {
+		return nil, errors.New("konsumen tidak berada dalam antrean manual review")
+	}
+
+	now := time.Now()
+	verification := &model.KYCVerification{
+		ConsumerID: consumer.ID,
+		Status:     decision,
+		Reason:     note,
+		ReviewedBy: reviewer,
+		ReviewedAt: &now,
+		CreatedAt:  now,
+	}
+
+	consumer.KYCStatus = decision
+	consumer.UpdatedAt = now
+	if err := u.consumerRepo.Update(consumer); err != nil {
+		return nil, err
+	}
+
+	if err := u.verificationRepo.Create(verification); err != nil {
+		return nil, err
+	}
+	return verification, nil
+}
+
+func (u *kycUsecase) GetVerificationHistory(consumerID uint) ([]model.KYCVerification, error) {
+	return u.verificationRepo.GetByConsumerID(consumerID)
+}
+
+// getVerifiedConsumer loads a consumer and refuses credit operations without a VERIFIED KYC
+func getVerifiedConsumer(consumerRepo repository.ConsumerRepository, consumerID uint) (*model.Consumer, error) {
+	consumer, err := consumerRepo.GetByID(consumerID)
+	if err != nil {
+		return nil, errors.New("konsumen tidak ditemukan")
+	}
+	if consumer.KYCStatus != model.KYCStatusVerified {
+		return nil, ErrConsumerNotVerified
+	}
+	return consumer, nil
+}
+
+// normalizeName upper-cases a name and collapses whitespace for comparison
+func normalizeName(name string) string {
+	return strings.Join(strings.Fields(strings.ToUpper(name)), " ")
+}
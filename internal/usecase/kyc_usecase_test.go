@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"testing"
+
+	"main/internal/kyc"
+	"main/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// MockKYCVerificationRepository for testing
+type MockKYCVerificationRepository struct {
+	verifications []model.KYCVerification
+}
+
+func (m *MockKYCVerificationRepository) Create(verification *model.KYCVerification) error {
+	verification.ID = uint(len(m.verifications) + 1)
+	m.verifications = append(m.verifications, *verification)
+	return nil
+}
+
+func (m *MockKYCVerificationRepository) GetLatestByConsumerID(consumerID uint) (*model.KYCVerification, error) {
+	for i := len(m.verifications) - 1; i >= 0; i-- {
+		if m.verifications[i].ConsumerID == consumerID {
+			return &m.verifications[i], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockKYCVerificationRepository) GetByConsumerID(consumerID uint) ([]model.KYCVerification, error) {
+	var verifications []model.KYCVerification
+	for _, verification := range m.verifications {
+		if verification.ConsumerID == consumerID {
+			verifications = append(verifications, verification)
+		}
+	}
+	return verifications, nil
+}
+
+// newKYCFixture registers a pending consumer and returns a KYC usecase with fake providers
+func newKYCFixture(faceScore, livenessScore float64) (KYCUsecase, *MockConsumerRepository) {
+	consumerRepo := NewMockConsumerRepository()
+	consumerRepo.Create(&model.Consumer{
+		NIK:         "3174010101900001",
+		FullName:    "John Doe",
+		LegalName:   "John Doe",
+		KTPPhoto:    "ktp-john",
+		SelfiePhoto: "selfie-john",
+		KYCStatus:   model.KYCStatusPending,
+	})
+
+	ocr := kyc.NewFakeKTPOCR()
+	ocr.Results["ktp-john"] = kyc.KTPData{NIK: "3174010101900001", FullName: "JOHN  DOE"}
+
+	uc := NewKYCUsecase(
+		consumerRepo,
+		&MockKYCVerificationRepository{},
+		&MockWatchlistHitRepository{},
+		ocr,
+		&kyc.FakeFaceMatch{Score: faceScore},
+		&kyc.FakeLiveness{Score: livenessScore},
+	)
+	return uc, consumerRepo
+}
+
+// Test: All checks pass
+func TestVerifyConsumer_Verified(t *testing.T) {
+	uc, consumerRepo := newKYCFixture(0.95, 0.9)
+
+	verification, err := uc.VerifyConsumer(1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if verification.Status != model.KYCStatusVerified {
+		t.Errorf("Expected VERIFIED, got %s (%s)", verification.Status, verification.Reason)
+	}
+
+	consumer, _ := consumerRepo.GetByID(1)
+	if consumer.KYCStatus != model.KYCStatusVerified {
+		t.Errorf("Expected consumer KYC status VERIFIED, got %s", consumer.KYCStatus)
+	}
+}
+
+// Test: Borderline face match goes to manual review and an analyst approves it
+func TestVerifyConsumer_ManualReview(t *testing.T) {
+	uc, consumerRepo := newKYCFixture(0.65, 0.9)
+
+	verification, err := uc.VerifyConsumer(1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if verification.Status != model.KYCStatusManualReview {
+		t.Fatalf("Expected MANUAL_REVIEW, got %s", verification.Status)
+	}
+
+	queue, _ := uc.GetManualReviewQueue()
+	if len(queue) != 1 || queue[0].Verification == nil {
+		t.Fatalf("Expected one consumer with verification in the review queue, got %d", len(queue))
+	}
+
+	if _, err := uc.ReviewConsumer(1, model.KYCStatusVerified, "analyst-01", "foto cocok"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	consumer, _ := consumerRepo.GetByID(1)
+	if consumer.KYCStatus != model.KYCStatusVerified {
+		t.Errorf("Expected consumer KYC status VERIFIED, got %s", consumer.KYCStatus)
+	}
+}
+
+// Test: Failed liveness rejects the consumer
+func TestVerifyConsumer_LivenessRejected(t *testing.T) {
+	uc, _ := newKYCFixture(0.95, 0.2)
+
+	verification, _ := uc.VerifyConsumer(1)
+	if verification.Status != model.KYCStatusRejected {
+		t.Errorf("Expected REJECTED, got %s", verification.Status)
+	}
+}
+
+// Test: Unreadable KTP goes to manual review
+func TestVerifyConsumer_UnreadableKTP(t *testing.T) {
+	uc, consumerRepo := newKYCFixture(0.95, 0.9)
+	consumer, _ := consumerRepo.GetByID(1)
+	consumer.KTPPhoto = "blurry"
+
+	verification, _ := uc.VerifyConsumer(1)
+	if verification.Status != model.KYCStatusManualReview {
+		t.Errorf("Expected MANUAL_REVIEW, got %s", verification.Status)
+	}
+}
+
+// Test: Review is only possible for consumers in the manual review queue
+func TestReviewConsumer_NotInQueue(t *testing.T) {
+	uc, _ := newKYCFixture(0.95, 0.9)
+
+	if _, err := uc.ReviewConsumer(1, model.KYCStatusVerified, "analyst-01", ""); err == nil {
+		t.Error("Expected error for consumer not in manual review, got nil")
+	}
+}
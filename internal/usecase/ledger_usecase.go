@@ -0,0 +1,300 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"main/internal/ledger"
+	"main/internal/model"
+	"main/internal/repository"
+)
+
+// LedgerPoster posts the journal entry of a business event
+type LedgerPoster interface {
+	Post(entry *ledger.Entry) error
+}
+
+// ContractLedger posts entries and reads back the balance a contract holds on an account
+type ContractLedger interface {
+	LedgerPoster
+	ContractBalance(transactionID uint, account string) (float64, error)
+}
+
+// ContractJournal reads back every line posted for a contract
+type ContractJournal interface {
+	ContractLines(transactionID uint) ([]ledger.PostedLine, error)
+}
+
+// GLExport is the daily general ledger file for the core accounting system
+type GLExport struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+	Lines       int
+}
+
+// LedgerUsecase defines all business logic operations for the general ledger
+type LedgerUsecase interface {
+	ContractLedger
+	ContractJournal
+	GetTrialBalance(asOf time.Time) (*ledger.TrialBalance, error)
+	ExportGL(postingDate time.Time) (*GLExport, error)
+}
+
+// ledgerUsecase is the implementation of LedgerUsecase
+type ledgerUsecase struct {
+	journalRepo repository.JournalRepository
+	mu          sync.Mutex
+}
+
+// NewLedgerUsecase creates a new instance of LedgerUsecase
+func NewLedgerUsecase(journalRepo repository.JournalRepository) LedgerUsecase {
+	return &ledgerUsecase{journalRepo: journalRepo}
+}
+
+// ledgerIn returns the ledger of an open database transaction, so an entry commits or rolls back
+// together with the business event it books
+func ledgerIn(tx *repository.Repositories) ContractLedger {
+	return &ledgerUsecase{journalRepo: tx.Journal}
+}
+
+// Post validates the entry and stores it with its lines in one insert. An entry whose reference
+// is already posted is skipped, so replaying an event never doubles the books.
+func (u *ledgerUsecase) Post(entry *ledger.Entry) error {
+	if entry.Empty() {
+		return nil
+	}
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if _, err := u.journalRepo.GetByReference(entry.Reference); err == nil {
+		log.Printf("⚠ Jurnal %s sudah diposting, dilewati\n", entry.Reference)
+		return nil
+	}
+
+	journal := &model.JournalEntry{
+		Reference:     entry.Reference,
+		EventType:     entry.EventType,
+		TransactionID: entry.TransactionID,
+		Description:   entry.Description,
+		PostingDate:   dateOnly(entry.PostingDate),
+		TotalAmount:   entry.Total(),
+		CreatedAt:     time.Now(),
+	}
+	for _, line := range entry.Lines {
+		journal.Lines = append(journal.Lines, model.JournalLine{
+			AccountCode: line.Account,
+			Debit:       roundMoney(line.Debit),
+			Credit:      roundMoney(line.Credit),
+			Memo:        line.Memo,
+		})
+	}
+	return u.journalRepo.Create(journal)
+}
+
+func (u *ledgerUsecase) GetTrialBalance(asOf time.Time) (*ledger.TrialBalance, error) {
+	date := dateOnly(asOf)
+	entries, err := u.journalRepo.GetUntil(date)
+	if err != nil {
+		return nil, err
+	}
+	return ledger.BuildTrialBalance(date, postedLines(entries)), nil
+}
+
+// ContractBalance sums the lines a contract posted on the account, on the normal side of the account
+func (u *ledgerUsecase) ContractBalance(transactionID uint, account string) (float64, error) {
+	entries, err := u.journalRepo.GetByTransactionID(transactionID)
+	if err != nil {
+		return 0, err
+	}
+	trial := ledger.BuildTrialBalance(time.Now(), postedLines(entries))
+	for _, balance := range trial.Accounts {
+		if balance.Code == account {
+			return balance.Balance, nil
+		}
+	}
+	return 0, fmt.Errorf("akun %s tidak terdaftar", account)
+}
+
+func (u *ledgerUsecase) ContractLines(transactionID uint) ([]ledger.PostedLine, error) {
+	entries, err := u.journalRepo.GetByTransactionID(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	return postedLines(entries), nil
+}
+
+// ExportGL writes every line posted on the date into the GL upload file
+func (u *ledgerUsecase) ExportGL(postingDate time.Time) (*GLExport, error) {
+	date := dateOnly(postingDate)
+	entries, err := u.journalRepo.GetByPostingDate(date)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := postedLines(entries)
+	var buf bytes.Buffer
+	if err := ledger.WriteGL(&buf, lines); err != nil {
+		return nil, err
+	}
+
+	return &GLExport{
+		FileName:    "gl-" + date.Format("20060102") + ".csv",
+		ContentType: "text/csv",
+		Content:     buf.Bytes(),
+		Lines:       len(lines),
+	}, nil
+}
+
+func postedLines(entries []model.JournalEntry) []ledger.PostedLine {
+	var lines []ledger.PostedLine
+	for _, entry := range entries {
+		for _, line := range entry.Lines {
+			lines = append(lines, ledger.PostedLine{
+				PostingDate:   entry.PostingDate,
+				Reference:     entry.Reference,
+				EventType:     entry.EventType,
+				TransactionID: entry.TransactionID,
+				Account:       line.AccountCode,
+				Debit:         line.Debit,
+				Credit:        line.Credit,
+				Memo:          line.Memo,
+			})
+		}
+	}
+	return lines
+}
+
+// activationEntry books the receivable of an activated contract. The merchant is owed the financed amount
+// and interest is deferred until earned. The down payment is paid to the merchant directly and never
+// passes through the books; the admin fee is booked when its receipt is recorded.
+func activationEntry(transaction *model.Transaction, at time.Time) *ledger.Entry {
+	financed := roundMoney(transaction.FinancedAmount())
+	return ledger.NewEntry("ACT-"+transaction.ContractNumber, model.JournalEventActivation, transaction.ID, at,
+		"aktivasi kontrak "+transaction.ContractNumber).
+		Debit(ledger.Receivable, roundMoney(financed+transaction.InterestAmount), "pokok dan bunga").
+		Credit(ledger.MerchantPayable, financed, "pembiayaan ke merchant").
+		Credit(ledger.UnearnedInterest, transaction.InterestAmount, "bunga ditangguhkan")
+}
+
+// adminFeeEntry books the admin fee received from the consumer
+func adminFeeEntry(transaction *model.Transaction, payment *model.Payment) *ledger.Entry {
+	return ledger.NewEntry(fmt.Sprintf("ADM-%d", payment.ID), model.JournalEventAdminFee, transaction.ID, payment.PaidAt,
+		"biaya admin kontrak "+transaction.ContractNumber).
+		Debit(ledger.Cash, payment.Amount, payment.Reference).
+		Credit(ledger.AdminFeeIncome, payment.Amount, payment.Reference)
+}
+
+// cancellationEntry reverses the cancelled principal and interest and moves the refunds to the refund payable.
+// Deferred interest released beyond the cancelled interest reverses income already accrued.
+// The down payment is recovered from the merchant.
+func cancellationEntry(transaction *model.Transaction, cancellation *model.TransactionCancellation,
+	principal, interest, deferred, adminFeeRefund, downPaymentRefund float64) *ledger.Entry {
+	return ledger.NewEntry(fmt.Sprintf("CNL-%d", cancellation.ID), model.JournalEventCancellation, transaction.ID, cancellation.CreatedAt,
+		fmt.Sprintf("pembatalan kontrak %s (%s)", transaction.ContractNumber, cancellation.ReasonCode)).
+		Debit(ledger.MerchantPayable, principal, "pembiayaan dibatalkan").
+		Debit(ledger.UnearnedInterest, deferred, "bunga ditangguhkan dibatalkan").
+		Signed(ledger.InterestIncome, roundMoney(interest-deferred), "pembalikan akrual bunga").
+		Credit(ledger.Receivable, roundMoney(principal+interest), "pokok dan bunga").
+		Debit(ledger.AdminFeeIncome, adminFeeRefund, "pengembalian biaya admin").
+		Credit(ledger.RefundPayable, adminFeeRefund, "pengembalian biaya admin").
+		Debit(ledger.MerchantPayable, downPaymentRefund, "pengembalian DP ditagih ke merchant").
+		Credit(ledger.RefundPayable, downPaymentRefund, "pengembalian DP")
+}
+
+// payoffEntry closes the receivable against the payoff payment and releases the deferred interest
+// still held for the contract. Interest waived by the quote is never earned; the rest of the
+// deferred balance becomes income, or reverses accruals that ran ahead of the quote.
+func payoffEntry(transaction *model.Transaction, quote *PayoffQuote, payment *model.Payment, deferred float64) *ledger.Entry {
+	return ledger.NewEntry("PAYOFF-"+transaction.ContractNumber, model.JournalEventPayoff, transaction.ID, payment.PaidAt,
+		"pelunasan dipercepat "+transaction.ContractNumber).
+		Debit(ledger.Cash, payment.Amount, payment.Reference).
+		Debit(ledger.UnearnedInterest, deferred, "bunga ditangguhkan").
+		Credit(ledger.Receivable, roundMoney(quote.RemainingPrincipal+quote.AccruedInterest+quote.WaivedInterest), "pokok dan bunga").
+		Signed(ledger.InterestIncome, roundMoney(quote.WaivedInterest-deferred), "bunga berjalan").
+		Credit(ledger.LateFeeIncome, quote.OutstandingLateFees, "denda").
+		Credit(ledger.EarlyTerminationFeeIncome, quote.EarlyTerminationFee, "penalti pelunasan")
+}
+
+// restructuringEntry replaces the interest of the old schedule that was not yet due with the interest of the
+// new schedule. Overdue interest is capitalised into the new principal and stays earned, so the deferred
+// balance beyond the not yet due interest becomes income, or reverses accruals that ran ahead.
+func restructuringEntry(transaction *model.Transaction, restructuring *model.Restructuring, futureInterest, deferred float64) *ledger.Entry {
+	return ledger.NewEntry(fmt.Sprintf("RST-%d", restructuring.ID), model.JournalEventRestructuring, transaction.ID, *restructuring.EffectiveDate,
+		"restrukturisasi kontrak "+transaction.ContractNumber).
+		Signed(ledger.Receivable, roundMoney(restructuring.NewInterestAmount-futureInterest), "bunga jadwal baru").
+		Signed(ledger.UnearnedInterest, roundMoney(deferred-restructuring.NewInterestAmount), "bunga ditangguhkan jadwal baru").
+		Signed(ledger.InterestIncome, roundMoney(futureInterest-deferred), "bunga diakui sampai restrukturisasi")
+}
+
+// writeOffEntry takes the receivable of a written-off contract off the books. The deferred interest
+// in it was never earned; the rest is the loss. Accrued interest is reversed before this entry.
+func writeOffEntry(transaction *model.Transaction, writeOff *model.WriteOff) *ledger.Entry {
+	return ledger.NewEntry("WO-"+transaction.ContractNumber, model.JournalEventWriteOff, transaction.ID, *writeOff.WrittenOffAt,
+		"hapus buku kontrak "+transaction.ContractNumber).
+		Debit(ledger.UnearnedInterest, writeOff.DeferredInterest, "bunga ditangguhkan").
+		Debit(ledger.WriteOffExpense, writeOff.LossAmount, "kerugian hapus buku").
+		Credit(ledger.Receivable, writeOff.ReceivableAmount, "pokok dan bunga")
+}
+
+// recoveryEntry books money received on a written-off contract as income
+func recoveryEntry(recovery *model.Recovery) *ledger.Entry {
+	return ledger.NewEntry(fmt.Sprintf("REC-%d", recovery.ID), model.JournalEventRecovery, recovery.TransactionID, recovery.ReceivedAt,
+		"pemulihan piutang hapus buku "+recovery.Source).
+		Debit(ledger.Cash, recovery.Amount, recovery.Reference).
+		Credit(ledger.RecoveryIncome, recovery.Amount, recovery.Reference)
+}
+
+// mdrEntry earns the merchant discount when a settlement batch is created
+func mdrEntry(batch *model.SettlementBatch) *ledger.Entry {
+	return ledger.NewEntry("MDR-"+batch.BatchNumber, model.JournalEventMDR, 0, batch.BusinessDate,
+		"MDR batch "+batch.BatchNumber).
+		Debit(ledger.MerchantPayable, batch.MDRAmount, "potongan MDR").
+		Credit(ledger.MDRIncome, batch.MDRAmount, "potongan MDR")
+}
+
+// merchantPaymentEntry settles the merchant payable when the bank confirms the transfer
+func merchantPaymentEntry(batch *model.SettlementBatch, paidAt time.Time) *ledger.Entry {
+	return ledger.NewEntry("PAY-"+batch.BatchNumber, model.JournalEventMerchantPayment, 0, paidAt,
+		"pembayaran batch "+batch.BatchNumber).
+		Debit(ledger.MerchantPayable, batch.NetAmount, batch.BankReference).
+		Credit(ledger.Cash, batch.NetAmount, batch.BankReference)
+}
+
+// installmentPaymentEntry reduces the receivable by the installment part of a payment and earns the late fee
+// part. Money applied from suspense was already booked to cash when the callback arrived.
+func installmentPaymentEntry(transaction *model.Transaction, posting *PaymentPosting, fromSuspense bool) *ledger.Entry {
+	source := ledger.Cash
+	if fromSuspense {
+		source = ledger.PaymentSuspense
+	}
+	payment := posting.Payment
+	return ledger.NewEntry(fmt.Sprintf("INS-%d", payment.ID), model.JournalEventInstallment, transaction.ID, payment.PaidAt,
+		"pembayaran angsuran "+transaction.ContractNumber).
+		Debit(source, payment.Amount, payment.Reference).
+		Credit(ledger.Receivable, posting.InstallmentAmount, "pokok dan bunga").
+		Credit(ledger.LateFeeIncome, posting.LateFeeAmount, "denda")
+}
+
+// suspenseEntry holds money received on a callback that could not be applied to a contract
+func suspenseEntry(callback *model.PaymentCallback) *ledger.Entry {
+	return ledger.NewEntry(fmt.Sprintf("SUS-%d", callback.ID), model.JournalEventSuspense, callback.TransactionID, callback.PaidAt,
+		fmt.Sprintf("titipan pembayaran %s %s", callback.BankCode, callback.VANumber)).
+		Debit(ledger.Cash, callback.Amount, callback.ExternalID).
+		Credit(ledger.PaymentSuspense, callback.Amount, callback.ExternalID)
+}
+
+// suspenseRefundEntry returns money held in suspense to the sender
+func suspenseRefundEntry(callback *model.PaymentCallback) *ledger.Entry {
+	return ledger.NewEntry(fmt.Sprintf("SUSR-%d", callback.ID), model.JournalEventSuspenseRefund, callback.TransactionID, *callback.ResolvedAt,
+		fmt.Sprintf("pengembalian titipan %s %s", callback.BankCode, callback.VANumber)).
+		Debit(ledger.PaymentSuspense, callback.Amount, callback.ExternalID).
+		Credit(ledger.Cash, callback.Amount, callback.ExternalID)
+}
@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"main/internal/ledger"
+	"main/internal/model"
+	"main/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// MockJournalRepository for testing
+type MockJournalRepository struct {
+	entries   []model.JournalEntry
+	createErr error
+}
+
+func (m *MockJournalRepository) Create(entry *model.JournalEntry) error {
+	if m.createErr != nil {
+		return m.createErr
+	}
+	entry.ID = uint(len(m.entries) + 1)
+	for i := range entry.Lines {
+		entry.Lines[i].EntryID = entry.ID
+	}
+	m.entries = append(m.entries, *entry)
+	return nil
+}
+
+func (m *MockJournalRepository) GetByReference(reference string) (*model.JournalEntry, error) {
+	for i := range m.entries {
+		if m.entries[i].Reference == reference {
+			return &m.entries[i], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockJournalRepository) GetByPostingDate(date time.Time) ([]model.JournalEntry, error) {
+	var entries []model.JournalEntry
+	for _, entry := range m.entries {
+		if entry.PostingDate.Equal(date) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (m *MockJournalRepository) GetUntil(date time.Time) ([]model.JournalEntry, error) {
+	var entries []model.JournalEntry
+	for _, entry := range m.entries {
+		if !entry.PostingDate.After(date) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (m *MockJournalRepository) GetByTransactionID(transactionID uint) ([]model.JournalEntry, error) {
+	var entries []model.JournalEntry
+	for _, entry := range m.entries {
+		if entry.TransactionID == transactionID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// MockTransactor runs each unit of work against the in-memory repositories. The mocks cannot roll back,
+// so it counts the units of work that failed instead.
+type MockTransactor struct {
+	repos  *repository.Repositories
+	failed int
+}
+
+func (m *MockTransactor) Transaction(fn func(tx *repository.Repositories) error) error {
+	err := fn(m.repos)
+	if err != nil {
+		m.failed++
+	}
+	return err
+}
+
+// newTestTransactor returns a transactor over the fixture repositories
+func newTestTransactor(repos repository.Repositories) *MockTransactor {
+	return &MockTransactor{repos: &repos}
+}
+
+// newTestLedger returns a ledger backed by an in-memory journal
+func newTestLedger() (LedgerUsecase, *MockJournalRepository) {
+	journalRepo := &MockJournalRepository{}
+	return NewLedgerUsecase(journalRepo), journalRepo
+}
+
+// accountBalance returns the balance of an account on its normal side from the trial balance of today
+func accountBalance(t *testing.T, uc LedgerUsecase, account string) float64 {
+	t.Helper()
+	trial, err := uc.GetTrialBalance(time.Now())
+	if err != nil {
+		t.Fatalf("Expected trial balance, got %v", err)
+	}
+	if !trial.Balanced {
+		t.Fatalf("Expected balanced books, got debit %.2f credit %.2f", trial.TotalDebit, trial.TotalCredit)
+	}
+	for _, balance := range trial.Accounts {
+		if balance.Code == account {
+			return balance.Balance
+		}
+	}
+	t.Fatalf("Account %s missing from trial balance", account)
+	return 0
+}
+
+// Test: Posting the same reference twice books it once
+func TestLedgerPost_Idempotent(t *testing.T) {
+	uc, journalRepo := newTestLedger()
+	entry := ledger.NewEntry("ACT-KTR-001", model.JournalEventActivation, 1, time.Now(), "").
+		Debit(ledger.Receivable, 1000000, "").
+		Credit(ledger.MerchantPayable, 1000000, "")
+
+	for i := 0; i < 2; i++ {
+		if err := uc.Post(entry); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if len(journalRepo.entries) != 1 {
+		t.Errorf("Expected 1 journal entry, got %d", len(journalRepo.entries))
+	}
+	if journalRepo.entries[0].TotalAmount != 1000000 {
+		t.Errorf("Expected total 1000000, got %.2f", journalRepo.entries[0].TotalAmount)
+	}
+}
+
+// Test: Unbalanced entries are never stored
+func TestLedgerPost_Unbalanced(t *testing.T) {
+	uc, journalRepo := newTestLedger()
+	entry := ledger.NewEntry("ACT-KTR-001", model.JournalEventActivation, 1, time.Now(), "").
+		Debit(ledger.Receivable, 1000000, "").
+		Credit(ledger.MerchantPayable, 999999.99, "")
+
+	if err := uc.Post(entry); !errors.Is(err, ledger.ErrUnbalanced) {
+		t.Errorf("Expected ErrUnbalanced, got %v", err)
+	}
+	if len(journalRepo.entries) != 0 {
+		t.Errorf("Expected no journal entry, got %d", len(journalRepo.entries))
+	}
+}
+
+// Test: GL export holds the lines of the posting date only
+func TestExportGL(t *testing.T) {
+	uc, _ := newTestLedger()
+	today := time.Now()
+	uc.Post(ledger.NewEntry("A", model.JournalEventActivation, 1, today, "").
+		Debit(ledger.Cash, 50000, "").Credit(ledger.AdminFeeIncome, 50000, ""))
+	uc.Post(ledger.NewEntry("B", model.JournalEventActivation, 2, today.AddDate(0, 0, -1), "").
+		Debit(ledger.Cash, 75000, "").Credit(ledger.AdminFeeIncome, 75000, ""))
+
+	export, err := uc.ExportGL(today)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if export.Lines != 2 {
+		t.Errorf("Expected 2 lines, got %d", export.Lines)
+	}
+	if !strings.HasPrefix(export.FileName, "gl-") || strings.Contains(string(export.Content), ",B,") {
+		t.Errorf("Unexpected export %s:\n%s", export.FileName, export.Content)
+	}
+}
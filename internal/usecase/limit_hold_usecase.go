@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"main/internal/model"
+	"main/internal/repository"
+)
+
+// Limit hold lifetime when the merchant does not ask for one, and the longest allowed
+const (
+	defaultHoldTTL = 72 * time.Hour
+	maxHoldTTL     = 14 * 24 * time.Hour
+)
+
+var ErrHoldExpired = errors.New("hold limit sudah kedaluwarsa")
+
+// AuthorizeHold reserves part of the consumer limit for a merchant checkout
+func (u *transactionUsecase) AuthorizeHold(hold *model.LimitHold, ttl time.Duration) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if hold.ConsumerID == 0 {
+		return errors.New("consumer ID tidak valid")
+	}
+	if hold.Amount <= 0 {
+		return errors.New("jumlah hold harus lebih dari 0")
+	}
+	if ttl == 0 {
+		ttl = defaultHoldTTL
+	}
+	if ttl < 0 || ttl > maxHoldTTL {
+		return fmt.Errorf("masa berlaku hold maksimal %d hari", int(maxHoldTTL.Hours()/24))
+	}
+
+	if _, err := getVerifiedConsumer(u.consumerRepo, hold.ConsumerID); err != nil {
+		return err
+	}
+	if err := checkMerchant(u.merchantRepo, hold.MerchantID, 0); err != nil {
+		return err
+	}
+
+	limit, err := u.limitRepo.GetByConsumerAndTenor(hold.ConsumerID, hold.Tenor)
+	if err != nil {
+		return errors.New("limit tidak ditemukan untuk tenor tersebut")
+	}
+	if hold.Amount > limit.Available() {
+		return errors.New("limit tidak cukup untuk hold ini")
+	}
+
+	now := time.Now()
+	hold.Status = model.LimitHoldStatusAuthorized
+	hold.ExpiresAt = now.Add(ttl)
+	hold.TransactionID = 0
+	hold.CreatedAt = now
+	hold.UpdatedAt = now
+
+	// The held amount and the hold commit together, so the sweeper always finds what it has to release
+	return u.transactor.Transaction(func(tx *repository.Repositories) error {
+		limit, err := tx.Limits.GetByConsumerAndTenorForUpdate(hold.ConsumerID, hold.Tenor)
+		if err != nil {
+			return errors.New("limit tidak ditemukan untuk tenor tersebut")
+		}
+		if hold.Amount > limit.Available() {
+			return errors.New("limit tidak cukup untuk hold ini")
+		}
+		limit.HeldAmount += hold.Amount
+		limit.UpdatedAt = now
+		if err := tx.Limits.UpdateUsage(limit); err != nil {
+			return errors.New("gagal update limit")
+		}
+		return tx.LimitHolds.Create(hold)
+	})
+}
+
+// CaptureHold books the held checkout as a transaction, possibly for a lower amount than held.
+// The whole hold is released; only the captured OTR stays used.
+func (u *transactionUsecase) CaptureHold(holdID uint, transaction *model.Transaction) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	hold, err := u.getOpenHold(holdID)
+	if err != nil {
+		return err
+	}
+	if time.Now().After(hold.ExpiresAt) {
+		return ErrHoldExpired
+	}
+
+	if transaction.ConsumerID != 0 && transaction.ConsumerID != hold.ConsumerID {
+		return errors.New("konsumen transaksi berbeda dengan konsumen hold")
+	}
+	if transaction.MerchantID != 0 && transaction.MerchantID != hold.MerchantID {
+		return errors.New("merchant transaksi berbeda dengan merchant hold")
+	}
+	if transaction.FinancedAmount() > hold.Amount {
+		return errors.New("jumlah pembiayaan melebihi jumlah hold")
+	}
+	transaction.ConsumerID = hold.ConsumerID
+	transaction.MerchantID = hold.MerchantID
+	transaction.Tenor = hold.Tenor
+
+	// The hold is released and captured in the booking transaction, so a failed booking keeps it open for a retry
+	return u.createTransaction(transaction, hold)
+}
+
+// VoidHold cancels an open hold and releases the reserved limit
+func (u *transactionUsecase) VoidHold(holdID uint) (*model.LimitHold, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	hold, err := u.getOpenHold(holdID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.closeHold(hold, model.LimitHoldStatusVoided); err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+func (u *transactionUsecase) GetHold(holdID uint) (*model.LimitHold, error) {
+	return u.holdRepo.GetByID(holdID)
+}
+
+// ReleaseExpiredHolds expires every open hold past its expiry and returns how many were released
+func (u *transactionUsecase) ReleaseExpiredHolds(now time.Time) (int, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	holds, err := u.holdRepo.GetExpired(now)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range holds {
+		if err := u.closeHold(&holds[i], model.LimitHoldStatusExpired); err != nil {
+			return i, err
+		}
+	}
+	if len(holds) > 0 {
+		log.Printf("✓ %d hold limit kedaluwarsa dilepas\n", len(holds))
+	}
+	return len(holds), nil
+}
+
+// getOpenHold loads a hold that is still authorized
+func (u *transactionUsecase) getOpenHold(holdID uint) (*model.LimitHold, error) {
+	hold, err := u.holdRepo.GetByID(holdID)
+	if err != nil {
+		return nil, errors.New("hold tidak ditemukan")
+	}
+	if hold.Status != model.LimitHoldStatusAuthorized {
+		return nil, fmt.Errorf("hold berstatus %s", hold.Status)
+	}
+	return hold, nil
+}
+
+// closeHold releases the held amount and moves the hold to a final status in one database transaction
+func (u *transactionUsecase) closeHold(hold *model.LimitHold, status string) error {
+	now := time.Now()
+	return u.transactor.Transaction(func(tx *repository.Repositories) error {
+		if err := adjustHeld(tx.Limits, hold, -hold.Amount); err != nil {
+			return err
+		}
+		hold.Status = status
+		hold.ClosedAt = &now
+		hold.UpdatedAt = now
+		return tx.LimitHolds.Update(hold)
+	})
+}
+
+// adjustHeld changes the held amount of the hold's limit by delta inside the database transaction of the caller
+func adjustHeld(limitRepo repository.ConsumerLimitRepository, hold *model.LimitHold, delta float64) error {
+	limit, err := limitRepo.GetByConsumerAndTenorForUpdate(hold.ConsumerID, hold.Tenor)
+	if err != nil {
+		return errors.New("limit tidak ditemukan untuk tenor tersebut")
+	}
+	limit.HeldAmount += delta
+	if limit.HeldAmount < 0 {
+		limit.HeldAmount = 0
+	}
+	limit.UpdatedAt = time.Now()
+	if err := limitRepo.UpdateUsage(limit); err != nil {
+		return errors.New("gagal update limit")
+	}
+	return nil
+}
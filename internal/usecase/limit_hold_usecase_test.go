@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"main/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// MockLimitHoldRepository for testing
+type MockLimitHoldRepository struct {
+	holds []*model.LimitHold
+}
+
+func (m *MockLimitHoldRepository) Create(hold *model.LimitHold) error {
+	hold.ID = uint(len(m.holds) + 1)
+	m.holds = append(m.holds, hold)
+	return nil
+}
+
+func (m *MockLimitHoldRepository) GetByID(id uint) (*model.LimitHold, error) {
+	for _, hold := range m.holds {
+		if hold.ID == id {
+			return hold, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockLimitHoldRepository) GetExpired(now time.Time) ([]model.LimitHold, error) {
+	var holds []model.LimitHold
+	for _, hold := range m.holds {
+		if hold.Status == model.LimitHoldStatusAuthorized && !hold.ExpiresAt.After(now) {
+			holds = append(holds, *hold)
+		}
+	}
+	return holds, nil
+}
+
+func (m *MockLimitHoldRepository) Update(hold *model.LimitHold) error {
+	for i, existing := range m.holds {
+		if existing.ID == hold.ID {
+			m.holds[i] = hold
+		}
+	}
+	return nil
+}
+
+func (f *transactionFixture) heldAmount(tenor int) float64 {
+	limit, _ := f.limitRepo.GetByConsumerAndTenor(1, tenor)
+	return limit.HeldAmount
+}
+
+// Test: A hold reserves the limit against direct transactions
+func TestAuthorizeHold_ReservesLimit(t *testing.T) {
+	f := newTransactionFixture(t)
+
+	hold := &model.LimitHold{ConsumerID: 1, Tenor: 3, Amount: 8000000, MerchantID: 5, Reference: "ORDER-1"}
+	if err := f.uc.AuthorizeHold(hold, 0); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if f.heldAmount(3) != 8000000 {
+		t.Errorf("Expected held amount 8000000, got %f", f.heldAmount(3))
+	}
+
+	err := f.uc.CreateTransaction(&model.Transaction{
+		ConsumerID:        1,
+		ContractNumber:    "CONT-001",
+		ProductCode:       "GADGET",
+		Tenor:             3,
+		OTR:               3000000,
+		InstallmentAmount: 1000000,
+	})
+	if err == nil {
+		t.Error("Expected error for limit reserved by the hold, got nil")
+	}
+
+	if err := f.uc.AuthorizeHold(&model.LimitHold{ConsumerID: 1, Tenor: 3, Amount: 3000000}, 0); err == nil {
+		t.Error("Expected error for hold above the available limit, got nil")
+	}
+}
+
+// Test: Capturing for a lower amount books only the captured OTR
+func TestCaptureHold_LowerAmount(t *testing.T) {
+	f := newTransactionFixture(t)
+
+	hold := &model.LimitHold{ConsumerID: 1, Tenor: 3, Amount: 5000000, MerchantID: 5}
+	f.uc.AuthorizeHold(hold, time.Hour)
+
+	transaction := &model.Transaction{ContractNumber: "CONT-001", ProductCode: "GADGET", OTR: 4500000, InstallmentAmount: 1500000}
+	if err := f.uc.CaptureHold(hold.ID, transaction); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if f.usedAmount(3) != 4500000 || f.heldAmount(3) != 0 {
+		t.Errorf("Expected used 4500000 held 0, got used %f held %f", f.usedAmount(3), f.heldAmount(3))
+	}
+	if hold.Status != model.LimitHoldStatusCaptured || hold.TransactionID != transaction.ID {
+		t.Errorf("Expected hold CAPTURED for transaction %d, got %s %d", transaction.ID, hold.Status, hold.TransactionID)
+	}
+	if transaction.MerchantID != 5 || transaction.Tenor != 3 {
+		t.Errorf("Expected merchant and tenor from the hold, got %d and %d", transaction.MerchantID, transaction.Tenor)
+	}
+
+	if err := f.uc.CaptureHold(hold.ID, &model.Transaction{ContractNumber: "CONT-002", ProductCode: "GADGET", OTR: 100000}); err == nil {
+		t.Error("Expected error capturing a hold twice, got nil")
+	}
+}
+
+// Test: A failed capture keeps the reservation
+func TestCaptureHold_Invalid(t *testing.T) {
+	f := newTransactionFixture(t)
+
+	hold := &model.LimitHold{ConsumerID: 1, Tenor: 3, Amount: 5000000}
+	f.uc.AuthorizeHold(hold, time.Hour)
+
+	if err := f.uc.CaptureHold(hold.ID, &model.Transaction{ContractNumber: "CONT-001", ProductCode: "GADGET", OTR: 6000000}); err == nil {
+		t.Error("Expected error for OTR above the hold, got nil")
+	}
+	if err := f.uc.CaptureHold(hold.ID, &model.Transaction{ContractNumber: "", ProductCode: "GADGET", OTR: 1000000}); err == nil {
+		t.Error("Expected error for missing contract number, got nil")
+	}
+	if f.heldAmount(3) != 5000000 || hold.Status != model.LimitHoldStatusAuthorized {
+		t.Errorf("Expected hold still reserving 5000000, got held %f status %s", f.heldAmount(3), hold.Status)
+	}
+}
+
+// Test: Void and expiry release the reservation
+func TestVoidAndExpireHolds(t *testing.T) {
+	f := newTransactionFixture(t)
+
+	voided := &model.LimitHold{ConsumerID: 1, Tenor: 3, Amount: 2000000}
+	f.uc.AuthorizeHold(voided, time.Hour)
+	shipped := &model.LimitHold{ConsumerID: 1, Tenor: 3, Amount: 3000000}
+	f.uc.AuthorizeHold(shipped, time.Minute)
+
+	if _, err := f.uc.VoidHold(voided.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if f.heldAmount(3) != 3000000 {
+		t.Errorf("Expected held 3000000 after void, got %f", f.heldAmount(3))
+	}
+
+	if err := f.uc.CaptureHold(shipped.ID, &model.Transaction{ContractNumber: "CONT-001", ProductCode: "GADGET", OTR: 1000000}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	late := &model.LimitHold{ConsumerID: 1, Tenor: 6, Amount: 1000000}
+	f.uc.AuthorizeHold(late, time.Minute)
+
+	released, err := f.uc.ReleaseExpiredHolds(time.Now().Add(2 * time.Minute))
+	if err != nil || released != 1 {
+		t.Fatalf("Expected 1 expired hold released, got %d (%v)", released, err)
+	}
+	if f.heldAmount(6) != 0 {
+		t.Errorf("Expected held 0 after expiry, got %f", f.heldAmount(6))
+	}
+
+	hold, _ := f.holdRepo.GetByID(late.ID)
+	if hold.Status != model.LimitHoldStatusExpired {
+		t.Errorf("Expected EXPIRED, got %s", hold.Status)
+	}
+	if err := f.uc.CaptureHold(late.ID, &model.Transaction{ContractNumber: "TOO-LATE", ProductCode: "GADGET", OTR: 1000000}); err == nil {
+		t.Error("Expected error capturing an expired hold, got nil")
+	}
+}
@@ -0,0 +1,357 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"main/internal/model"
+	"main/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrMerchantNotActive = errors.New("merchant tidak aktif")
+	ErrInvalidAPIKey     = errors.New("API key tidak valid")
+)
+
+// merchantAPIKeyPrefix marks merchant keys so they are recognisable in logs and secret scanners
+const merchantAPIKeyPrefix = "mk_"
+
+// maxReportDays caps the period of a merchant transaction report
+const maxReportDays = 366
+
+// MerchantTransactionReport lists the contracts a merchant originated in a period
+type MerchantTransactionReport struct {
+	MerchantID       uint                `json:"merchant_id"`
+	MerchantCode     string              `json:"merchant_code"`
+	From             time.Time           `json:"from"`
+	To               time.Time           `json:"to"`
+	TransactionCount int                 `json:"transaction_count"`
+	TotalOTR         float64             `json:"total_otr"`
+	TotalFinanced    float64             `json:"total_financed"` // OTR minus down payments
+	ByStatus         map[string]int      `json:"by_status"`
+	Transactions     []model.Transaction `json:"transactions"`
+}
+
+// MerchantUsecase defines all business logic operations for the merchant registry
+type MerchantUsecase interface {
+	RegisterMerchant(merchant *model.Merchant) error
+	GetMerchant(id uint) (*model.Merchant, error)
+	GetMerchants() ([]model.Merchant, error)
+	UpdateMerchantStatus(id uint, status string) (*model.Merchant, error)
+	AddBranch(merchantID uint, branch *model.MerchantBranch) error
+	IssueAPIKey(merchantID uint) (string, *model.MerchantAPIKey, error)
+	RevokeAPIKey(merchantID, keyID uint) error
+	AuthenticateAPIKey(apiKey string) (uint, error)
+	GetMerchantTransactions(merchantID uint, from, to time.Time) (*MerchantTransactionReport, error)
+}
+
+// merchantUsecase is the implementation of MerchantUsecase
+type merchantUsecase struct {
+	merchantRepo    repository.MerchantRepository
+	apiKeyRepo      repository.MerchantAPIKeyRepository
+	transactionRepo repository.TransactionRepository
+	mu              sync.Mutex
+}
+
+// NewMerchantUsecase creates a new instance of MerchantUsecase
+func NewMerchantUsecase(
+	merchantRepo repository.MerchantRepository,
+	apiKeyRepo repository.MerchantAPIKeyRepository,
+	transactionRepo repository.TransactionRepository,
+) MerchantUsecase {
+	return &merchantUsecase{
+		merchantRepo:    merchantRepo,
+		apiKeyRepo:      apiKeyRepo,
+		transactionRepo: transactionRepo,
+	}
+}
+
+// RegisterMerchant validates and stores a merchant with its initial branches
+func (u *merchantUsecase) RegisterMerchant(merchant *model.Merchant) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	merchant.Code = strings.ToUpper(strings.TrimSpace(merchant.Code))
+	if merchant.Code == "" || strings.TrimSpace(merchant.Name) == "" {
+		return errors.New("kode dan nama merchant tidak boleh kosong")
+	}
+	if strings.TrimSpace(merchant.BankName) == "" || strings.TrimSpace(merchant.BankAccountNumber) == "" || strings.TrimSpace(merchant.BankAccountName) == "" {
+		return errors.New("rekening bank merchant wajib diisi untuk settlement")
+	}
+	if merchant.MDRRate < 0 || merchant.MDRRate >= 100 {
+		return errors.New("MDR harus antara 0 dan 100 persen")
+	}
+
+	existing, err := u.merchantRepo.GetByCode(merchant.Code)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if existing != nil {
+		return errors.New("kode merchant sudah digunakan")
+	}
+
+	now := time.Now()
+	seen := make(map[string]bool)
+	for i := range merchant.Branches {
+		branch := &merchant.Branches[i]
+		if err := normalizeBranch(branch); err != nil {
+			return err
+		}
+		if seen[branch.Code] {
+			return fmt.Errorf("kode cabang %s terdaftar lebih dari sekali", branch.Code)
+		}
+		seen[branch.Code] = true
+		branch.Active = true
+		branch.CreatedAt = now
+	}
+
+	merchant.Status = model.MerchantStatusActive
+	merchant.CreatedAt = now
+	merchant.UpdatedAt = now
+
+	log.Printf("✓ Merchant %s terdaftar dengan %d cabang\n", merchant.Code, len(merchant.Branches))
+	return u.merchantRepo.Create(merchant)
+}
+
+func (u *merchantUsecase) GetMerchant(id uint) (*model.Merchant, error) {
+	return u.merchantRepo.GetByID(id)
+}
+
+func (u *merchantUsecase) GetMerchants() ([]model.Merchant, error) {
+	return u.merchantRepo.GetAll()
+}
+
+// UpdateMerchantStatus suspends, reactivates or terminates a merchant. Terminated merchants cannot be reactivated.
+func (u *merchantUsecase) UpdateMerchantStatus(id uint, status string) (*model.Merchant, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	validStatuses := map[string]bool{
+		model.MerchantStatusActive:     true,
+		model.MerchantStatusSuspended:  true,
+		model.MerchantStatusTerminated: true,
+	}
+	if !validStatuses[status] {
+		return nil, errors.New("status merchant tidak valid")
+	}
+
+	merchant, err := u.merchantRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("merchant tidak ditemukan")
+	}
+	if merchant.Status == model.MerchantStatusTerminated {
+		return nil, errors.New("merchant yang sudah diputus kerjasamanya tidak dapat diubah statusnya")
+	}
+
+	merchant.Status = status
+	merchant.UpdatedAt = time.Now()
+	if err := u.merchantRepo.Update(merchant); err != nil {
+		return nil, err
+	}
+
+	log.Printf("✓ Status merchant %s diubah menjadi %s\n", merchant.Code, status)
+	return merchant, nil
+}
+
+// AddBranch registers a new outlet of a merchant
+func (u *merchantUsecase) AddBranch(merchantID uint, branch *model.MerchantBranch) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	merchant, err := u.merchantRepo.GetByID(merchantID)
+	if err != nil {
+		return errors.New("merchant tidak ditemukan")
+	}
+	if err := normalizeBranch(branch); err != nil {
+		return err
+	}
+	for _, existing := range merchant.Branches {
+		if existing.Code == branch.Code {
+			return errors.New("kode cabang sudah digunakan")
+		}
+	}
+
+	branch.MerchantID = merchant.ID
+	branch.Active = true
+	branch.CreatedAt = time.Now()
+	return u.merchantRepo.CreateBranch(branch)
+}
+
+// IssueAPIKey creates a merchant-scoped API key. The plain key is returned once and never stored.
+func (u *merchantUsecase) IssueAPIKey(merchantID uint) (string, *model.MerchantAPIKey, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	merchant, err := u.merchantRepo.GetByID(merchantID)
+	if err != nil {
+		return "", nil, errors.New("merchant tidak ditemukan")
+	}
+	if merchant.Status == model.MerchantStatusTerminated {
+		return "", nil, ErrMerchantNotActive
+	}
+
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, err
+	}
+	plain := merchantAPIKeyPrefix + hex.EncodeToString(secret)
+
+	key := &model.MerchantAPIKey{
+		MerchantID: merchant.ID,
+		Prefix:     plain[:len(merchantAPIKeyPrefix)+8],
+		KeyHash:    hashAPIKey(plain),
+		CreatedAt:  time.Now(),
+	}
+	if err := u.apiKeyRepo.Create(key); err != nil {
+		return "", nil, err
+	}
+
+	log.Printf("✓ API key %s diterbitkan untuk merchant %s\n", key.Prefix, merchant.Code)
+	return plain, key, nil
+}
+
+// RevokeAPIKey disables a key of the merchant immediately
+func (u *merchantUsecase) RevokeAPIKey(merchantID, keyID uint) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	key, err := u.apiKeyRepo.GetByID(keyID)
+	if err != nil || key.MerchantID != merchantID {
+		return errors.New("API key tidak ditemukan")
+	}
+	if key.RevokedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	key.RevokedAt = &now
+	return u.apiKeyRepo.Update(key)
+}
+
+// AuthenticateAPIKey resolves an API key to its merchant. Revoked keys and terminated merchants are refused.
+func (u *merchantUsecase) AuthenticateAPIKey(apiKey string) (uint, error) {
+	if !strings.HasPrefix(apiKey, merchantAPIKeyPrefix) {
+		return 0, ErrInvalidAPIKey
+	}
+
+	key, err := u.apiKeyRepo.GetByHash(hashAPIKey(apiKey))
+	if err != nil || key.RevokedAt != nil {
+		return 0, ErrInvalidAPIKey
+	}
+	merchant, err := u.merchantRepo.GetByID(key.MerchantID)
+	if err != nil || merchant.Status == model.MerchantStatusTerminated {
+		return 0, ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	key.LastUsedAt = &now
+	if err := u.apiKeyRepo.Update(key); err != nil {
+		log.Printf("⚠ Gagal mencatat pemakaian API key %s: %v\n", key.Prefix, err)
+	}
+	return merchant.ID, nil
+}
+
+// GetMerchantTransactions reports the contracts a merchant originated between two dates, both inclusive.
+// The period defaults to the last 30 days.
+func (u *merchantUsecase) GetMerchantTransactions(merchantID uint, from, to time.Time) (*MerchantTransactionReport, error) {
+	merchant, err := u.merchantRepo.GetByID(merchantID)
+	if err != nil {
+		return nil, errors.New("merchant tidak ditemukan")
+	}
+
+	if to.IsZero() {
+		to = time.Now()
+	}
+	to = dateOnly(to)
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -30)
+	}
+	from = dateOnly(from)
+	if from.After(to) {
+		return nil, errors.New("tanggal awal laporan setelah tanggal akhir")
+	}
+	if daysBetween(from, to) > maxReportDays {
+		return nil, fmt.Errorf("periode laporan maksimal %d hari", maxReportDays)
+	}
+
+	transactions, err := u.transactionRepo.GetByMerchantSince(merchant.ID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &MerchantTransactionReport{
+		MerchantID:   merchant.ID,
+		MerchantCode: merchant.Code,
+		From:         from,
+		To:           to,
+		ByStatus:     make(map[string]int),
+		Transactions: []model.Transaction{},
+	}
+	end := to.AddDate(0, 0, 1)
+	for _, transaction := range transactions {
+		if !transaction.CreatedAt.Before(end) {
+			continue
+		}
+		report.Transactions = append(report.Transactions, transaction)
+		report.ByStatus[transaction.Status]++
+		report.TotalOTR += transaction.OTR
+		report.TotalFinanced += transaction.FinancedAmount()
+	}
+	report.TransactionCount = len(report.Transactions)
+	report.TotalOTR = roundMoney(report.TotalOTR)
+	report.TotalFinanced = roundMoney(report.TotalFinanced)
+	return report, nil
+}
+
+// checkMerchant makes sure a contract is originated by an ACTIVE merchant and, when given, one of its active branches.
+// Contracts booked directly carry no merchant.
+func checkMerchant(merchantRepo repository.MerchantRepository, merchantID, branchID uint) error {
+	if merchantID == 0 {
+		if branchID != 0 {
+			return errors.New("cabang merchant diisi tanpa merchant")
+		}
+		return nil
+	}
+
+	merchant, err := merchantRepo.GetByID(merchantID)
+	if err != nil {
+		return errors.New("merchant tidak ditemukan")
+	}
+	if merchant.Status != model.MerchantStatusActive {
+		return ErrMerchantNotActive
+	}
+	if branchID == 0 {
+		return nil
+	}
+	for _, branch := range merchant.Branches {
+		if branch.ID == branchID {
+			if !branch.Active {
+				return errors.New("cabang merchant tidak aktif")
+			}
+			return nil
+		}
+	}
+	return errors.New("cabang tidak terdaftar pada merchant tersebut")
+}
+
+func normalizeBranch(branch *model.MerchantBranch) error {
+	branch.Code = strings.ToUpper(strings.TrimSpace(branch.Code))
+	if branch.Code == "" || strings.TrimSpace(branch.Name) == "" {
+		return errors.New("kode dan nama cabang tidak boleh kosong")
+	}
+	return nil
+}
+
+func hashAPIKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,238 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"main/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// MockMerchantRepository for testing
+type MockMerchantRepository struct {
+	merchants map[uint]*model.Merchant
+	nextID    uint
+}
+
+func NewMockMerchantRepository() *MockMerchantRepository {
+	return &MockMerchantRepository{
+		merchants: make(map[uint]*model.Merchant),
+		nextID:    1,
+	}
+}
+
+func (m *MockMerchantRepository) Create(merchant *model.Merchant) error {
+	merchant.ID = m.nextID
+	m.nextID++
+	for i := range merchant.Branches {
+		merchant.Branches[i].ID = uint(i + 1)
+		merchant.Branches[i].MerchantID = merchant.ID
+	}
+	m.merchants[merchant.ID] = merchant
+	return nil
+}
+
+func (m *MockMerchantRepository) GetByID(id uint) (*model.Merchant, error) {
+	if merchant, exists := m.merchants[id]; exists {
+		return merchant, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockMerchantRepository) GetByCode(code string) (*model.Merchant, error) {
+	for _, merchant := range m.merchants {
+		if merchant.Code == code {
+			return merchant, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockMerchantRepository) GetAll() ([]model.Merchant, error) {
+	var merchants []model.Merchant
+	for _, merchant := range m.merchants {
+		merchants = append(merchants, *merchant)
+	}
+	return merchants, nil
+}
+
+func (m *MockMerchantRepository) Update(merchant *model.Merchant) error {
+	m.merchants[merchant.ID] = merchant
+	return nil
+}
+
+func (m *MockMerchantRepository) CreateBranch(branch *model.MerchantBranch) error {
+	merchant, exists := m.merchants[branch.MerchantID]
+	if !exists {
+		return gorm.ErrRecordNotFound
+	}
+	branch.ID = uint(len(merchant.Branches) + 1)
+	merchant.Branches = append(merchant.Branches, *branch)
+	return nil
+}
+
+// MockMerchantAPIKeyRepository for testing
+type MockMerchantAPIKeyRepository struct {
+	keys []*model.MerchantAPIKey
+}
+
+func (m *MockMerchantAPIKeyRepository) Create(key *model.MerchantAPIKey) error {
+	key.ID = uint(len(m.keys) + 1)
+	m.keys = append(m.keys, key)
+	return nil
+}
+
+func (m *MockMerchantAPIKeyRepository) GetByID(id uint) (*model.MerchantAPIKey, error) {
+	for _, key := range m.keys {
+		if key.ID == id {
+			return key, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockMerchantAPIKeyRepository) GetByHash(hash string) (*model.MerchantAPIKey, error) {
+	for _, key := range m.keys {
+		if key.KeyHash == hash {
+			return key, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockMerchantAPIKeyRepository) GetByMerchantID(merchantID uint) ([]model.MerchantAPIKey, error) {
+	var keys []model.MerchantAPIKey
+	for _, key := range m.keys {
+		if key.MerchantID == merchantID {
+			keys = append(keys, *key)
+		}
+	}
+	return keys, nil
+}
+
+func (m *MockMerchantAPIKeyRepository) Update(key *model.MerchantAPIKey) error {
+	return nil
+}
+
+func newTestMerchant() *model.Merchant {
+	return &model.Merchant{
+		Code:              "dealer-01",
+		Name:              "Dealer Motor Jaya",
+		Category:          "MOTOR_DEALER",
+		BankName:          "BCA",
+		BankAccountNumber: "1234567890",
+		BankAccountName:   "PT Motor Jaya",
+		Branches:          []model.MerchantBranch{{Code: "jkt", Name: "Jakarta"}},
+	}
+}
+
+// Test: Merchants need a settlement bank account and a unique code
+func TestRegisterMerchant(t *testing.T) {
+	uc := NewMerchantUsecase(NewMockMerchantRepository(), &MockMerchantAPIKeyRepository{}, NewMockTransactionRepository())
+
+	merchant := newTestMerchant()
+	if err := uc.RegisterMerchant(merchant); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if merchant.Code != "DEALER-01" || merchant.Status != model.MerchantStatusActive || merchant.Branches[0].Code != "JKT" {
+		t.Errorf("Expected normalized ACTIVE merchant, got %s %s %s", merchant.Code, merchant.Status, merchant.Branches[0].Code)
+	}
+
+	if err := uc.RegisterMerchant(newTestMerchant()); err == nil {
+		t.Error("Expected error for a duplicate merchant code, got nil")
+	}
+
+	noBank := newTestMerchant()
+	noBank.Code = "DEALER-02"
+	noBank.BankAccountNumber = ""
+	if err := uc.RegisterMerchant(noBank); err == nil {
+		t.Error("Expected error for a merchant without bank account, got nil")
+	}
+}
+
+// Test: API keys resolve to their merchant until revoked
+func TestMerchantAPIKey(t *testing.T) {
+	keyRepo := &MockMerchantAPIKeyRepository{}
+	uc := NewMerchantUsecase(NewMockMerchantRepository(), keyRepo, NewMockTransactionRepository())
+
+	merchant := newTestMerchant()
+	uc.RegisterMerchant(merchant)
+
+	plain, key, err := uc.IssueAPIKey(merchant.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if key.KeyHash == plain || key.KeyHash != hashAPIKey(plain) {
+		t.Error("Expected only the key hash to be stored")
+	}
+
+	merchantID, err := uc.AuthenticateAPIKey(plain)
+	if err != nil || merchantID != merchant.ID {
+		t.Fatalf("Expected key to resolve to merchant %d, got %d (%v)", merchant.ID, merchantID, err)
+	}
+	if _, err := uc.AuthenticateAPIKey(plain + "x"); err != ErrInvalidAPIKey {
+		t.Errorf("Expected ErrInvalidAPIKey for an unknown key, got %v", err)
+	}
+
+	if err := uc.RevokeAPIKey(merchant.ID, key.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := uc.AuthenticateAPIKey(plain); err != ErrInvalidAPIKey {
+		t.Errorf("Expected ErrInvalidAPIKey for a revoked key, got %v", err)
+	}
+}
+
+// Test: The merchant report only covers the merchant's contracts within the period
+func TestGetMerchantTransactions(t *testing.T) {
+	transactionRepo := NewMockTransactionRepository()
+	uc := NewMerchantUsecase(NewMockMerchantRepository(), &MockMerchantAPIKeyRepository{}, transactionRepo)
+
+	merchant := newTestMerchant()
+	uc.RegisterMerchant(merchant)
+
+	today := dateOnly(time.Now())
+	transactionRepo.Create(&model.Transaction{MerchantID: merchant.ID, OTR: 3000000, DownPayment: 500000, Status: model.TransactionStatusActive, CreatedAt: today.Add(10 * time.Hour)})
+	transactionRepo.Create(&model.Transaction{MerchantID: merchant.ID, OTR: 1000000, Status: model.TransactionStatusCancelled, CreatedAt: today.AddDate(0, 0, -5)})
+	transactionRepo.Create(&model.Transaction{MerchantID: merchant.ID, OTR: 2000000, Status: model.TransactionStatusActive, CreatedAt: today.AddDate(0, 0, -60)})
+	transactionRepo.Create(&model.Transaction{MerchantID: 99, OTR: 5000000, Status: model.TransactionStatusActive, CreatedAt: today})
+
+	report, err := uc.GetMerchantTransactions(merchant.ID, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if report.TransactionCount != 2 || report.TotalOTR != 4000000 || report.TotalFinanced != 3500000 {
+		t.Errorf("Expected 2 contracts, OTR 4000000, financed 3500000, got %d, %.2f, %.2f",
+			report.TransactionCount, report.TotalOTR, report.TotalFinanced)
+	}
+	if report.ByStatus[model.TransactionStatusCancelled] != 1 {
+		t.Errorf("Expected one cancelled contract, got %v", report.ByStatus)
+	}
+
+	report, _ = uc.GetMerchantTransactions(merchant.ID, today.AddDate(0, 0, -5), today.AddDate(0, 0, -1))
+	if report.TransactionCount != 1 {
+		t.Errorf("Expected 1 contract in the closed period, got %d", report.TransactionCount)
+	}
+}
+
+// Test: Only ACTIVE merchants and their own branches can originate contracts
+func TestCreateTransaction_MerchantNotActive(t *testing.T) {
+	f := newTransactionFixture(t)
+
+	transaction := &model.Transaction{ConsumerID: 1, ContractNumber: "CONT-M-001", ProductCode: "GADGET", Tenor: 3, OTR: 1000000, MerchantID: 5, BranchID: 9}
+	if err := f.uc.CreateTransaction(transaction); err == nil {
+		t.Error("Expected error for a branch of another merchant, got nil")
+	}
+
+	merchant, _ := f.merchantRepo.GetByID(5)
+	merchant.Status = model.MerchantStatusSuspended
+	transaction.BranchID = 0
+	if err := f.uc.CreateTransaction(transaction); err != ErrMerchantNotActive {
+		t.Errorf("Expected ErrMerchantNotActive, got %v", err)
+	}
+
+	transaction.MerchantID = 42
+	if err := f.uc.CreateTransaction(transaction); err == nil {
+		t.Error("Expected error for an unknown merchant, got nil")
+	}
+}
@@ -0,0 +1,393 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"main/internal/model"
+	"main/internal/notification"
+	"main/internal/repository"
+)
+
+// EventNotifier tells consumers about events on their contracts.
+// A failed delivery is logged and never fails the event itself.
+type EventNotifier interface {
+	NotifyTransactionBooked(transaction *model.Transaction)
+	NotifyPaymentReceived(transaction *model.Transaction, payment *model.Payment)
+	NotifyStatusChanged(transaction *model.Transaction, previousStatus string)
+}
+
+// OTPSender delivers a one-time code to a consumer on the first channel that accepts it and returns that channel.
+// Codes are sent even to consumers who opted out of notifications, since they asked for them.
+type OTPSender interface {
+	SendOTP(consumerID, transactionID uint, event, dedupKey string, data notification.TemplateData) (string, error)
+}
+
+// NotificationPolicy holds when installment reminders go out relative to the due date
+type NotificationPolicy struct {
+	DaysBeforeDue int // H-n reminder
+	DaysAfterDue  int // H+n reminder
+}
+
+// DefaultNotificationPolicy reminds consumers at H-3, on the due date and at H+1
+func DefaultNotificationPolicy() NotificationPolicy {
+	return NotificationPolicy{
+		DaysBeforeDue: 3,
+		DaysAfterDue:  1,
+	}
+}
+
+// PreferenceRequest replaces the notification preference of a consumer
+type PreferenceRequest struct {
+	Language        string `json:"language"` // id, en
+	SMSEnabled      bool   `json:"sms_enabled"`
+	WhatsAppEnabled bool   `json:"whatsapp_enabled"`
+	EmailEnabled    bool   `json:"email_enabled"`
+	OptedOut        bool   `json:"opted_out"`
+}
+
+// ReminderResult summarises one run of the installment reminders
+type ReminderResult struct {
+	BusinessDate time.Time `json:"business_date"`
+	Installments int       `json:"installments"`
+	Sent         int       `json:"sent"`
+	Failed       int       `json:"failed"`
+}
+
+// NotificationUsecase defines all business logic operations for consumer notifications
+type NotificationUsecase interface {
+	EventNotifier
+	OTPSender
+	SendDueReminders(asOf time.Time) (*ReminderResult, error)
+	GetPreference(consumerID uint) (*model.NotificationPreference, error)
+	UpdatePreference(consumerID uint, req PreferenceRequest) (*model.NotificationPreference, error)
+	GetDeliveryLog(consumerID uint) ([]model.NotificationLog, error)
+}
+
+// notificationUsecase is the implementation of NotificationUsecase
+type notificationUsecase struct {
+	consumerRepo    repository.ConsumerRepository
+	transactionRepo repository.TransactionRepository
+	installmentRepo repository.InstallmentRepository
+	preferenceRepo  repository.NotificationPreferenceRepository
+	logRepo         repository.NotificationLogRepository
+	notifiers       map[string]notification.Notifier
+	policy          NotificationPolicy
+}
+
+// NewNotificationUsecase creates a new instance of NotificationUsecase. Channels without a notifier are never used.
+func NewNotificationUsecase(
+	consumerRepo repository.ConsumerRepository,
+	transactionRepo repository.TransactionRepository,
+	installmentRepo repository.InstallmentRepository,
+	preferenceRepo repository.NotificationPreferenceRepository,
+	logRepo repository.NotificationLogRepository,
+	notifiers map[string]notification.Notifier,
+	policy NotificationPolicy,
+) NotificationUsecase {
+	return &notificationUsecase{
+		consumerRepo:    consumerRepo,
+		transactionRepo: transactionRepo,
+		installmentRepo: installmentRepo,
+		preferenceRepo:  preferenceRepo,
+		logRepo:         logRepo,
+		notifiers:       notifiers,
+		policy:          policy,
+	}
+}
+
+// NotifyTransactionBooked confirms a contract booked on the consumer limit with its first due date
+func (u *notificationUsecase) NotifyTransactionBooked(transaction *model.Transaction) {
+	data := notification.TemplateData{
+		ContractNumber:    transaction.ContractNumber,
+		Amount:            transaction.FinancedAmount(),
+		InstallmentAmount: transaction.InstallmentAmount,
+		Tenor:             transaction.Tenor,
+	}
+	if installments, err := u.installmentRepo.GetByTransactionID(transaction.ID); err == nil {
+		for _, installment := range installments {
+			if data.DueDate.IsZero() || installment.DueDate.Before(data.DueDate) {
+				data.DueDate = installment.DueDate
+			}
+		}
+	}
+	u.notifyEvent(transaction, notification.EventTransactionBooked, fmt.Sprintf("BOOKED-%d", transaction.ID), data)
+}
+
+func (u *notificationUsecase) NotifyPaymentReceived(transaction *model.Transaction, payment *model.Payment) {
+	u.notifyEvent(transaction, notification.EventPaymentReceived, fmt.Sprintf("PAYMENT-%d", payment.ID), notification.TemplateData{
+		ContractNumber: transaction.ContractNumber,
+		Amount:         payment.Amount,
+	})
+}
+
+func (u *notificationUsecase) NotifyStatusChanged(transaction *model.Transaction, previousStatus string) {
+	if transaction.Status == previousStatus {
+		return
+	}
+	u.notifyEvent(transaction, notification.EventStatusChanged, fmt.Sprintf("STATUS-%d-%s", transaction.ID, transaction.Status), notification.TemplateData{
+		ContractNumber: transaction.ContractNumber,
+		Status:         transaction.Status,
+	})
+}
+
+func (u *notificationUsecase) notifyEvent(transaction *model.Transaction, event, dedupKey string, data notification.TemplateData) {
+	if _, failed, err := u.notify(transaction.ConsumerID, transaction.ID, event, dedupKey, data); err != nil || failed > 0 {
+		log.Printf("⚠ Notifikasi %s kontrak %s gagal dikirim: %v\n", event, transaction.ContractNumber, err)
+	}
+}
+
+func (u *notificationUsecase) SendOTP(consumerID, transactionID uint, event, dedupKey string, data notification.TemplateData) (string, error) {
+	consumer, err := u.consumerRepo.GetByID(consumerID)
+	if err != nil {
+		return "", errors.New("konsumen tidak ditemukan")
+	}
+	preference, err := u.GetPreference(consumerID)
+	if err != nil {
+		return "", err
+	}
+
+	data.Name = consumer.FullName
+	subject, body, err := notification.Render(event, preference.Language, data)
+	if err != nil {
+		return "", err
+	}
+	// The delivery log is readable by operators, so it never holds the code itself
+	logged := body
+	if data.Code != "" {
+		logged = strings.ReplaceAll(body, data.Code, strings.Repeat("*", len(data.Code)))
+	}
+
+	for _, channel := range notification.Channels {
+		notifier, recipient := u.notifiers[channel], channelRecipient(consumer, preference, channel)
+		if notifier == nil || recipient == "" {
+			continue
+		}
+
+		now := time.Now()
+		entry := &model.NotificationLog{
+			ConsumerID:    consumerID,
+			TransactionID: transactionID,
+			Event:         event,
+			Channel:       channel,
+			Recipient:     recipient,
+			Language:      preference.Language,
+			Subject:       subject,
+			Body:          logged,
+			DedupKey:      dedupKey + "-" + channel,
+			CreatedAt:     now,
+		}
+		messageID, sendErr := notifier.Send(notification.Message{Channel: channel, Recipient: recipient, Subject: subject, Body: body})
+		if sendErr != nil {
+			entry.Status = model.NotificationStatusFailed
+			entry.Error = sendErr.Error()
+		} else {
+			entry.Status = model.NotificationStatusSent
+			entry.ProviderMessageID = messageID
+			entry.SentAt = &now
+		}
+		if err := u.logRepo.Create(entry); err != nil {
+			return "", err
+		}
+		if sendErr == nil {
+			return channel, nil
+		}
+		log.Printf("⚠ OTP ke konsumen %d lewat %s gagal: %v\n", consumerID, channel, sendErr)
+	}
+	return "", errors.New("kode OTP tidak dapat dikirim, konsumen tidak memiliki kanal notifikasi yang aktif")
+}
+
+// SendDueReminders reminds consumers of ACTIVE contracts about unpaid installments due in DaysBeforeDue days,
+// due on the date and overdue by DaysAfterDue days. A reminder is sent once per installment and channel,
+// so the job can be rerun for the same date.
+func (u *notificationUsecase) SendDueReminders(asOf time.Time) (*ReminderResult, error) {
+	date := dateOnly(asOf)
+	result := &ReminderResult{BusinessDate: date}
+	reminders := map[time.Time]string{
+		date.AddDate(0, 0, u.policy.DaysBeforeDue): notification.EventDueSoon,
+		date: notification.EventDueToday,
+		date.AddDate(0, 0, -u.policy.DaysAfterDue): notification.EventOverdue,
+	}
+
+	transactions, err := u.transactionRepo.GetByStatus(model.TransactionStatusActive)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, transaction := range transactions {
+		installments, err := u.installmentRepo.GetByTransactionID(transaction.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, installment := range installments {
+			event, due := reminders[dateOnly(installment.DueDate)]
+			if !due || installment.Outstanding() <= 0 {
+				continue
+			}
+			result.Installments++
+
+			sent, failed, err := u.notify(transaction.ConsumerID, transaction.ID, event, fmt.Sprintf("%s-%d", event, installment.ID), notification.TemplateData{
+				ContractNumber: transaction.ContractNumber,
+				Amount:         roundMoney(installment.Outstanding() + installment.OutstandingLateFee()),
+				Sequence:       installment.Sequence,
+				DueDate:        installment.DueDate,
+			})
+			if err != nil {
+				return nil, err
+			}
+			result.Sent += sent
+			result.Failed += failed
+		}
+	}
+
+	log.Printf("✓ Pengingat angsuran %s: %d angsuran, %d terkirim, %d gagal\n",
+		date.Format("2006-01-02"), result.Installments, result.Sent, result.Failed)
+	return result, nil
+}
+
+// notify renders the event in the language of the consumer and sends it on every channel the consumer allows
+// and has a contact for. Channels that already delivered the key are skipped.
+func (u *notificationUsecase) notify(consumerID, transactionID uint, event, dedupKey string, data notification.TemplateData) (int, int, error) {
+	consumer, err := u.consumerRepo.GetByID(consumerID)
+	if err != nil {
+		return 0, 0, errors.New("konsumen tidak ditemukan")
+	}
+	preference, err := u.GetPreference(consumerID)
+	if err != nil {
+		return 0, 0, err
+	}
+	if preference.OptedOut {
+		return 0, 0, nil
+	}
+
+	data.Name = consumer.FullName
+	subject, body, err := notification.Render(event, preference.Language, data)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	sent, failed := 0, 0
+	for _, channel := range notification.Channels {
+		notifier, recipient := u.notifiers[channel], channelRecipient(consumer, preference, channel)
+		if notifier == nil || recipient == "" {
+			continue
+		}
+		key := dedupKey + "-" + channel
+		if delivered, err := u.logRepo.HasSent(key); err != nil {
+			return sent, failed, err
+		} else if delivered {
+			continue
+		}
+
+		now := time.Now()
+		entry := &model.NotificationLog{
+			ConsumerID:    consumerID,
+			TransactionID: transactionID,
+			Event:         event,
+			Channel:       channel,
+			Recipient:     recipient,
+			Language:      preference.Language,
+			Subject:       subject,
+			Body:          body,
+			DedupKey:      key,
+			CreatedAt:     now,
+		}
+		messageID, sendErr := notifier.Send(notification.Message{Channel: channel, Recipient: recipient, Subject: subject, Body: body})
+		if sendErr != nil {
+			entry.Status = model.NotificationStatusFailed
+			entry.Error = sendErr.Error()
+			failed++
+		} else {
+			entry.Status = model.NotificationStatusSent
+			entry.ProviderMessageID = messageID
+			entry.SentAt = &now
+			sent++
+		}
+		if err := u.logRepo.Create(entry); err != nil {
+			return sent, failed, err
+		}
+	}
+	return sent, failed, nil
+}
+
+// channelRecipient returns where the channel reaches the consumer, or "" when the consumer turned it off
+func channelRecipient(consumer *model.Consumer, preference *model.NotificationPreference, channel string) string {
+	switch channel {
+	case notification.ChannelSMS:
+		if preference.SMSEnabled {
+			return consumer.PhoneNumber
+		}
+	case notification.ChannelWhatsApp:
+		if preference.WhatsAppEnabled {
+			return consumer.PhoneNumber
+		}
+	case notification.ChannelEmail:
+		if preference.EmailEnabled {
+			return consumer.Email
+		}
+	}
+	return ""
+}
+
+// GetPreference returns the stored preference, or the default of every channel in Indonesian
+func (u *notificationUsecase) GetPreference(consumerID uint) (*model.NotificationPreference, error) {
+	if preference, err := u.preferenceRepo.GetByConsumerID(consumerID); err == nil {
+		return preference, nil
+	}
+	if _, err := u.consumerRepo.GetByID(consumerID); err != nil {
+		return nil, errors.New("konsumen tidak ditemukan")
+	}
+	return &model.NotificationPreference{
+		ConsumerID:      consumerID,
+		Language:        notification.LanguageIndonesian,
+		SMSEnabled:      true,
+		WhatsAppEnabled: true,
+		EmailEnabled:    true,
+	}, nil
+}
+
+func (u *notificationUsecase) UpdatePreference(consumerID uint, req PreferenceRequest) (*model.NotificationPreference, error) {
+	req.Language = strings.ToLower(strings.TrimSpace(req.Language))
+	if req.Language == "" {
+		req.Language = notification.LanguageIndonesian
+	}
+	if !notification.SupportedLanguage(req.Language) {
+		return nil, errors.New("bahasa notifikasi harus id atau en")
+	}
+
+	preference, err := u.GetPreference(consumerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if req.OptedOut && !preference.OptedOut {
+		preference.OptedOutAt = &now
+	} else if !req.OptedOut {
+		preference.OptedOutAt = nil
+	}
+	preference.Language = req.Language
+	preference.SMSEnabled = req.SMSEnabled
+	preference.WhatsAppEnabled = req.WhatsAppEnabled
+	preference.EmailEnabled = req.EmailEnabled
+	preference.OptedOut = req.OptedOut
+	preference.UpdatedAt = now
+
+	if preference.ID == 0 {
+		preference.CreatedAt = now
+		err = u.preferenceRepo.Create(preference)
+	} else {
+		err = u.preferenceRepo.Update(preference)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return preference, nil
+}
+
+func (u *notificationUsecase) GetDeliveryLog(consumerID uint) ([]model.NotificationLog, error) {
+	return u.logRepo.GetByConsumerID(consumerID)
+}
@@ -0,0 +1,220 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"main/internal/model"
+	"main/internal/notification"
+
+	"gorm.io/gorm"
+)
+
+// MockEventNotifier records the events it is told about
+type MockEventNotifier struct {
+	events []string
+}
+
+func (m *MockEventNotifier) NotifyTransactionBooked(transaction *model.Transaction) {
+	m.events = append(m.events, "BOOKED "+transaction.ContractNumber)
+}
+
+func (m *MockEventNotifier) NotifyPaymentReceived(transaction *model.Transaction, payment *model.Payment) {
+	m.events = append(m.events, "PAYMENT "+transaction.ContractNumber)
+}
+
+func (m *MockEventNotifier) NotifyStatusChanged(transaction *model.Transaction, previousStatus string) {
+	m.events = append(m.events, fmt.Sprintf("STATUS %s %s->%s", transaction.ContractNumber, previousStatus, transaction.Status))
+}
+
+// MockNotificationPreferenceRepository for testing
+type MockNotificationPreferenceRepository struct {
+	preferences []*model.NotificationPreference
+}
+
+func (m *MockNotificationPreferenceRepository) Create(preference *model.NotificationPreference) error {
+	preference.ID = uint(len(m.preferences) + 1)
+	m.preferences = append(m.preferences, preference)
+	return nil
+}
+
+func (m *MockNotificationPreferenceRepository) GetByConsumerID(consumerID uint) (*model.NotificationPreference, error) {
+	for _, preference := range m.preferences {
+		if preference.ConsumerID == consumerID {
+			return preference, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockNotificationPreferenceRepository) Update(preference *model.NotificationPreference) error {
+	for i := range m.preferences {
+		if m.preferences[i].ID == preference.ID {
+			*m.preferences[i] = *preference
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+// MockNotificationLogRepository for testing
+type MockNotificationLogRepository struct {
+	entries []model.NotificationLog
+}
+
+func (m *MockNotificationLogRepository) Create(entry *model.NotificationLog) error {
+	entry.ID = uint(len(m.entries) + 1)
+	m.entries = append(m.entries, *entry)
+	return nil
+}
+
+func (m *MockNotificationLogRepository) GetByConsumerID(consumerID uint) ([]model.NotificationLog, error) {
+	var entries []model.NotificationLog
+	for _, entry := range m.entries {
+		if entry.ConsumerID == consumerID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (m *MockNotificationLogRepository) HasSent(dedupKey string) (bool, error) {
+	for _, entry := range m.entries {
+		if entry.DedupKey == dedupKey && entry.Status == model.NotificationStatusSent {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// recordingNotifier keeps every message it sends, or fails while err is set
+type recordingNotifier struct {
+	messages []notification.Message
+	err      error
+}
+
+func (n *recordingNotifier) Send(msg notification.Message) (string, error) {
+	if n.err != nil {
+		return "", n.err
+	}
+	n.messages = append(n.messages, msg)
+	return fmt.Sprintf("msg-%d", len(n.messages)), nil
+}
+
+type notificationFixture struct {
+	uc      NotificationUsecase
+	sms     *recordingNotifier
+	email   *recordingNotifier
+	logRepo *MockNotificationLogRepository
+}
+
+// newNotificationFixture books the test contract on 1 Jan 2026, due on the 1st of Feb, Mar and Apr,
+// for a consumer reachable by phone and email. WhatsApp has no notifier.
+func newNotificationFixture() *notificationFixture {
+	f := &notificationFixture{
+		sms:     &recordingNotifier{},
+		email:   &recordingNotifier{},
+		logRepo: &MockNotificationLogRepository{},
+	}
+	consumerRepo := NewMockConsumerRepository()
+	consumerRepo.Create(&model.Consumer{NIK: "3201011501900001", FullName: "Budi", PhoneNumber: "081234567890", Email: "budi@example.com"})
+	transactionRepo := NewMockTransactionRepository()
+	installmentRepo := &MockInstallmentRepository{}
+	bookTestContract(transactionRepo, installmentRepo, time.Date(2026, 1, 1, 10, 0, 0, 0, time.Local))
+
+	notifiers := map[string]notification.Notifier{notification.ChannelSMS: f.sms, notification.ChannelEmail: f.email}
+	f.uc = NewNotificationUsecase(consumerRepo, transactionRepo, installmentRepo, &MockNotificationPreferenceRepository{},
+		f.logRepo, notifiers, DefaultNotificationPolicy())
+	return f
+}
+
+// Test: Reminders go out at H-3, H-0 and H+1 once per installment and channel
+func TestSendDueReminders(t *testing.T) {
+	f := newNotificationFixture()
+
+	result, err := f.uc.SendDueReminders(time.Date(2026, 1, 29, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Installments != 1 || result.Sent != 2 {
+		t.Errorf("Expected one installment reminded on SMS and email, got %+v", result)
+	}
+	if body := f.sms.messages[0].Body; body != "Halo Budi, cicilan ke-1 kontrak CTR-20260101 sebesar Rp1.100.000 jatuh tempo pada 01-02-2026. Mohon siapkan pembayaran Anda." {
+		t.Errorf("Unexpected H-3 reminder %q", body)
+	}
+	if f.email.messages[0].Recipient != "budi@example.com" || f.email.messages[0].Subject == "" {
+		t.Errorf("Expected email with a subject to budi@example.com, got %+v", f.email.messages[0])
+	}
+
+	if result, _ := f.uc.SendDueReminders(time.Date(2026, 1, 29, 0, 0, 0, 0, time.Local)); result.Sent != 0 {
+		t.Errorf("Expected rerun to send nothing, got %d", result.Sent)
+	}
+	if result, _ := f.uc.SendDueReminders(time.Date(2026, 1, 30, 0, 0, 0, 0, time.Local)); result.Installments != 0 {
+		t.Errorf("Expected no reminder at H-2, got %+v", result)
+	}
+	f.uc.SendDueReminders(time.Date(2026, 2, 1, 0, 0, 0, 0, time.Local))
+	f.uc.SendDueReminders(time.Date(2026, 2, 2, 0, 0, 0, 0, time.Local))
+
+	var events []string
+	for _, entry := range f.logRepo.entries {
+		if entry.Channel == notification.ChannelSMS {
+			events = append(events, entry.Event)
+		}
+	}
+	if len(events) != 3 || events[1] != notification.EventDueToday || events[2] != notification.EventOverdue {
+		t.Errorf("Expected H-3, H-0 and H+1 reminders, got %v", events)
+	}
+}
+
+// Test: Preferences choose the language and channels, opt-out silences every channel
+func TestNotificationPreference(t *testing.T) {
+	f := newNotificationFixture()
+	transaction := &model.Transaction{ID: 1, ConsumerID: 1, ContractNumber: "CTR-20260101", Status: model.TransactionStatusCompleted}
+
+	if _, err := f.uc.UpdatePreference(1, PreferenceRequest{Language: "fr"}); err == nil {
+		t.Error("Expected error for unsupported language, got nil")
+	}
+	if _, err := f.uc.UpdatePreference(1, PreferenceRequest{Language: "EN", SMSEnabled: true}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	f.uc.NotifyStatusChanged(transaction, model.TransactionStatusActive)
+	if len(f.sms.messages) != 1 || len(f.email.messages) != 0 || f.sms.messages[0].Body != "Hi Budi, contract CTR-20260101 is now paid off." {
+		t.Errorf("Expected one English SMS, got %+v / %+v", f.sms.messages, f.email.messages)
+	}
+
+	preference, _ := f.uc.UpdatePreference(1, PreferenceRequest{Language: "id", SMSEnabled: true, EmailEnabled: true, OptedOut: true})
+	if preference.OptedOutAt == nil {
+		t.Error("Expected opt-out time recorded")
+	}
+	f.uc.NotifyPaymentReceived(transaction, &model.Payment{ID: 7, Amount: 1100000})
+	if len(f.sms.messages) != 1 || len(f.email.messages) != 0 {
+		t.Error("Expected nothing sent after opt-out")
+	}
+}
+
+// Test: Failed deliveries are logged and retried by the next trigger
+func TestNotificationDeliveryLog(t *testing.T) {
+	f := newNotificationFixture()
+	transaction := &model.Transaction{ID: 1, ConsumerID: 1, ContractNumber: "CTR-20260101", Status: model.TransactionStatusDefaulted}
+
+	f.sms.err = errors.New("gateway timeout")
+	f.uc.NotifyStatusChanged(transaction, model.TransactionStatusActive)
+	f.sms.err = nil
+	f.uc.NotifyStatusChanged(transaction, model.TransactionStatusActive)
+	f.uc.NotifyStatusChanged(transaction, model.TransactionStatusActive)
+
+	entries, _ := f.uc.GetDeliveryLog(1)
+	statuses := map[string]int{}
+	for _, entry := range entries {
+		statuses[entry.Channel+" "+entry.Status]++
+	}
+	if len(entries) != 3 || statuses["SMS FAILED"] != 1 || statuses["SMS SENT"] != 1 || statuses["EMAIL SENT"] != 1 {
+		t.Errorf("Expected SMS failed once then sent and email sent once, got %v", statuses)
+	}
+	if entries[0].Error != "gateway timeout" {
+		t.Errorf("Expected provider error logged, got %q", entries[0].Error)
+	}
+}
@@ -0,0 +1,303 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"main/internal/model"
+	"main/internal/notification"
+	"main/internal/otp"
+	"main/internal/repository"
+)
+
+// Errors of one-time codes
+var (
+	ErrInvalidOTP     = errors.New("kode OTP salah")
+	ErrOTPExpired     = errors.New("kode OTP sudah kedaluwarsa, minta kode baru")
+	ErrOTPLocked      = errors.New("kode OTP salah terlalu banyak, minta kode baru")
+	ErrOTPCooldown    = errors.New("kode OTP baru saja dikirim, tunggu sebelum meminta lagi")
+	ErrOTPRequired    = errors.New("transaksi memerlukan persetujuan konsumen dengan kode OTP")
+	ErrOTPNotUsable   = errors.New("challenge OTP sudah tidak berlaku")
+	ErrOTPBindingDiff = errors.New("challenge OTP tidak sesuai dengan konsumen, merchant, jumlah atau nomor kontrak transaksi")
+)
+
+// OTPPolicy holds how one-time codes are issued and checked
+type OTPPolicy struct {
+	Digits         int
+	TTL            time.Duration // how long a code can be used
+	MaxAttempts    int           // wrong codes before the challenge is locked
+	ResendCooldown time.Duration // wait before another code is sent for the same challenge
+	MaxSends       int           // codes sent per challenge, the first one included
+}
+
+// DefaultOTPPolicy sends 6-digit codes valid for 5 minutes, locks after 3 wrong codes
+// and resends at most twice, a minute apart
+func DefaultOTPPolicy() OTPPolicy {
+	return OTPPolicy{
+		Digits:         6,
+		TTL:            5 * time.Minute,
+		MaxAttempts:    3,
+		ResendCooldown: time.Minute,
+		MaxSends:       3,
+	}
+}
+
+// OTPChallengeRequest asks a consumer to consent to putting an amount from a merchant on their limit
+type OTPChallengeRequest struct {
+	ConsumerID     uint    `json:"consumer_id"`
+	MerchantID     uint    `json:"merchant_id"`
+	Amount         float64 `json:"amount"`          // financed amount: OTR minus the down payment
+	ContractNumber string  `json:"contract_number"` // optional, the code then only books this contract
+}
+
+// OTPConsent is the code given for an action together with what the action is
+type OTPConsent struct {
+	ChallengeID uint
+	Code        string
+	ConsumerID  uint
+	MerchantID  uint
+	Amount      float64
+	Reference   string // what uses the consent, e.g. the contract number
+}
+
+// OTPVerifier checks a code for the action it was issued for; the action uses the challenge up
+// inside its own database transaction, so an action rolled back leaves the challenge usable
+type OTPVerifier interface {
+	VerifyChallenge(consent OTPConsent) error
+	ConsumeChallenge(challenges repository.OTPChallengeRepository, consent OTPConsent) error
+}
+
+// OTPUsecase defines the one-time codes consumers confirm actions with
+type OTPUsecase interface {
+	OTPVerifier
+	CreateChallenge(req OTPChallengeRequest) (*model.OTPChallenge, error)
+	ResendChallenge(id uint) (*model.OTPChallenge, error)
+	GetChallenge(id uint) (*model.OTPChallenge, error)
+}
+
+// otpUsecase is the implementation of OTPUsecase
+type otpUsecase struct {
+	challengeRepo repository.OTPChallengeRepository
+	consumerRepo  repository.ConsumerRepository
+	merchantRepo  repository.MerchantRepository
+	sender        OTPSender
+	key           []byte
+	policy        OTPPolicy
+	mu            sync.Mutex
+}
+
+// NewOTPUsecase creates a new instance of OTPUsecase. key signs the stored code hashes.
+func NewOTPUsecase(
+	challengeRepo repository.OTPChallengeRepository,
+	consumerRepo repository.ConsumerRepository,
+	merchantRepo repository.MerchantRepository,
+	sender OTPSender,
+	key []byte,
+	policy OTPPolicy,
+) OTPUsecase {
+	return &otpUsecase{
+		challengeRepo: challengeRepo,
+		consumerRepo:  consumerRepo,
+		merchantRepo:  merchantRepo,
+		sender:        sender,
+		key:           key,
+		policy:        policy,
+	}
+}
+
+// CreateChallenge sends a code for a transaction the consumer is about to make
+func (u *otpUsecase) CreateChallenge(req OTPChallengeRequest) (*model.OTPChallenge, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if req.ConsumerID == 0 || req.Amount <= 0 {
+		return nil, errors.New("consumer ID dan jumlah transaksi wajib diisi")
+	}
+	if _, err := u.consumerRepo.GetByID(req.ConsumerID); err != nil {
+		return nil, errors.New("konsumen tidak ditemukan")
+	}
+	if err := checkMerchant(u.merchantRepo, req.MerchantID, 0); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	challenge := &model.OTPChallenge{
+		ConsumerID: req.ConsumerID,
+		Purpose:    model.OTPPurposeTransaction,
+		MerchantID: req.MerchantID,
+		Amount:     math.Round(req.Amount*100) / 100,
+		Reference:  strings.TrimSpace(req.ContractNumber),
+		Status:     model.OTPStatusPending,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	// Stored first so the delivery log can refer to the challenge
+	if err := u.challengeRepo.Create(challenge); err != nil {
+		return nil, err
+	}
+	if err := u.send(challenge, now); err != nil {
+		challenge.Status = model.OTPStatusExpired
+		challenge.UpdatedAt = time.Now()
+		u.challengeRepo.Update(challenge)
+		return nil, err
+	}
+	return challenge, nil
+}
+
+// ResendChallenge sends a fresh code for a pending challenge; the earlier code stops working.
+// Wrong attempts carry over, so resending does not buy more guesses.
+func (u *otpUsecase) ResendChallenge(id uint) (*model.OTPChallenge, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	challenge, err := u.getPending(id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if now.Before(challenge.LastSentAt.Add(u.policy.ResendCooldown)) {
+		wait := challenge.LastSentAt.Add(u.policy.ResendCooldown).Sub(now)
+		return nil, fmt.Errorf("%w (%d detik lagi)", ErrOTPCooldown, int(math.Ceil(wait.Seconds())))
+	}
+	if challenge.SendCount >= u.policy.MaxSends {
+		return nil, fmt.Errorf("kode OTP sudah dikirim %d kali, buat challenge baru", challenge.SendCount)
+	}
+	if err := u.send(challenge, now); err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+func (u *otpUsecase) GetChallenge(id uint) (*model.OTPChallenge, error) {
+	challenge, err := u.challengeRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("challenge OTP tidak ditemukan")
+	}
+	return challenge, nil
+}
+
+// VerifyChallenge checks the code against what it was issued for. Wrong codes, expiry and locking
+// are recorded at once; a right code leaves the challenge PENDING for ConsumeChallenge.
+func (u *otpUsecase) VerifyChallenge(consent OTPConsent) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if consent.ChallengeID == 0 || consent.Code == "" {
+		return ErrOTPRequired
+	}
+	challenge, err := u.getPending(consent.ChallengeID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if now.After(challenge.ExpiresAt) {
+		if err := u.close(challenge, model.OTPStatusExpired, now); err != nil {
+			return err
+		}
+		return ErrOTPExpired
+	}
+	if challenge.ConsumerID != consent.ConsumerID || challenge.MerchantID != consent.MerchantID ||
+		math.Abs(challenge.Amount-consent.Amount) >= 0.01 || (challenge.Reference != "" && challenge.Reference != consent.Reference) {
+		return ErrOTPBindingDiff
+	}
+
+	if !otp.Verify(u.key, challengeBinding(challenge), consent.Code, challenge.CodeHash) {
+		challenge.Attempts++
+		if challenge.Attempts >= u.policy.MaxAttempts {
+			if err := u.close(challenge, model.OTPStatusLocked, now); err != nil {
+				return err
+			}
+			log.Printf("⚠ Challenge OTP %d konsumen %d dikunci setelah %d kode salah\n", challenge.ID, challenge.ConsumerID, challenge.Attempts)
+			return ErrOTPLocked
+		}
+		challenge.UpdatedAt = now
+		if err := u.challengeRepo.Update(challenge); err != nil {
+			return err
+		}
+		return fmt.Errorf("%w, sisa %d percobaan", ErrInvalidOTP, u.policy.MaxAttempts-challenge.Attempts)
+	}
+	return nil
+}
+
+// ConsumeChallenge uses up a verified challenge through challenges, the repositories of the caller's database
+// transaction. The row is locked, so of two actions given the same code only the first to commit books.
+func (u *otpUsecase) ConsumeChallenge(challenges repository.OTPChallengeRepository, consent OTPConsent) error {
+	challenge, err := challenges.GetByIDForUpdate(consent.ChallengeID)
+	if err != nil {
+		return errors.New("challenge OTP tidak ditemukan")
+	}
+	if challenge.Status != model.OTPStatusPending {
+		return fmt.Errorf("%w (%s)", ErrOTPNotUsable, challenge.Status)
+	}
+
+	now := time.Now()
+	challenge.Status = model.OTPStatusConsumed
+	challenge.ConsumedAt = &now
+	challenge.Reference = consent.Reference
+	challenge.UpdatedAt = now
+	return challenges.Update(challenge)
+}
+
+func (u *otpUsecase) getPending(id uint) (*model.OTPChallenge, error) {
+	challenge, err := u.challengeRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("challenge OTP tidak ditemukan")
+	}
+	if challenge.Status != model.OTPStatusPending {
+		return nil, fmt.Errorf("%w (%s)", ErrOTPNotUsable, challenge.Status)
+	}
+	return challenge, nil
+}
+
+// send generates a new code, stores only its hash and delivers it on the first channel that works
+func (u *otpUsecase) send(challenge *model.OTPChallenge, now time.Time) error {
+	code, err := otp.Generate(u.policy.Digits)
+	if err != nil {
+		return err
+	}
+	data := notification.TemplateData{
+		ContractNumber: challenge.Reference,
+		Amount:         challenge.Amount,
+		Code:           code,
+		ExpiresAt:      now.Add(u.policy.TTL),
+	}
+	if challenge.MerchantID != 0 {
+		if merchant, err := u.merchantRepo.GetByID(challenge.MerchantID); err == nil {
+			data.MerchantName = merchant.Name
+		}
+	}
+
+	channel, err := u.sender.SendOTP(challenge.ConsumerID, 0, notification.EventTransactionOTP,
+		fmt.Sprintf("OTP-%d-%d", challenge.ID, challenge.SendCount+1), data)
+	if err != nil {
+		return fmt.Errorf("gagal mengirim kode OTP: %w", err)
+	}
+
+	challenge.CodeHash = otp.Hash(u.key, challengeBinding(challenge), code)
+	challenge.Channel = channel
+	challenge.ExpiresAt = data.ExpiresAt
+	challenge.SendCount++
+	challenge.LastSentAt = now
+	challenge.UpdatedAt = now
+	if err := u.challengeRepo.Update(challenge); err != nil {
+		return err
+	}
+	log.Printf("✓ Kode OTP challenge %d dikirim ke konsumen %d via %s\n", challenge.ID, challenge.ConsumerID, channel)
+	return nil
+}
+
+func (u *otpUsecase) close(challenge *model.OTPChallenge, status string, now time.Time) error {
+	challenge.Status = status
+	challenge.UpdatedAt = now
+	return u.challengeRepo.Update(challenge)
+}
+
+// challengeBinding ties a code to the purpose, consumer, merchant, amount and contract number it was sent for
+func challengeBinding(challenge *model.OTPChallenge) string {
+	return fmt.Sprintf("%s:%d:%d:%.2f:%s", challenge.Purpose, challenge.ConsumerID, challenge.MerchantID, challenge.Amount, challenge.Reference)
+}
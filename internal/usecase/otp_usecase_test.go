@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"main/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// MockOTPChallengeRepository for testing
+type MockOTPChallengeRepository struct {
+	challenges []model.OTPChallenge
+}
+
+func (m *MockOTPChallengeRepository) Create(challenge *model.OTPChallenge) error {
+	challenge.ID = uint(len(m.challenges) + 1)
+	m.challenges = append(m.challenges, *challenge)
+	return nil
+}
+
+func (m *MockOTPChallengeRepository) GetByID(id uint) (*model.OTPChallenge, error) {
+	if id == 0 || int(id) > len(m.challenges) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	challenge := m.challenges[id-1]
+	return &challenge, nil
+}
+
+func (m *MockOTPChallengeRepository) GetByIDForUpdate(id uint) (*model.OTPChallenge, error) {
+	return m.GetByID(id)
+}
+
+func (m *MockOTPChallengeRepository) Update(challenge *model.OTPChallenge) error {
+	m.challenges[challenge.ID-1] = *challenge
+	return nil
+}
+
+// newOTPFixture issues codes for verified consumer 1 at ACTIVE merchant 5
+func newOTPFixture() (OTPUsecase, *MockOTPChallengeRepository, *MockOTPSender) {
+	merchantRepo := NewMockMerchantRepository()
+	merchantRepo.merchants[5] = &model.Merchant{ID: 5, Code: "DEALER-05", Name: "Dealer Lima", Status: model.MerchantStatusActive}
+	challengeRepo := &MockOTPChallengeRepository{}
+	sender := &MockOTPSender{}
+	uc := NewOTPUsecase(challengeRepo, newVerifiedConsumerRepository(), merchantRepo, sender, []byte("test-otp-key"), DefaultOTPPolicy())
+	return uc, challengeRepo, sender
+}
+
+func transactionConsent(challengeID uint, code string) OTPConsent {
+	return OTPConsent{ChallengeID: challengeID, Code: code, ConsumerID: 1, MerchantID: 5, Amount: 3000000, Reference: "CONT-OTP"}
+}
+
+// Test: A code only works for the consumer, merchant and amount it was sent for, and only once
+func TestVerifyChallenge_Binding(t *testing.T) {
+	uc, challengeRepo, sender := newOTPFixture()
+
+	challenge, err := uc.CreateChallenge(OTPChallengeRequest{ConsumerID: 1, MerchantID: 5, Amount: 3000000})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if challenge.SendCount != 1 || challenge.CodeHash == "" || challenge.CodeHash == sender.lastCode() {
+		t.Errorf("Expected one code sent and only its hash stored, got %+v", challenge)
+	}
+
+	other := transactionConsent(challenge.ID, sender.lastCode())
+	other.Amount = 3500000
+	if err := uc.VerifyChallenge(other); !errors.Is(err, ErrOTPBindingDiff) {
+		t.Errorf("Expected ErrOTPBindingDiff for another amount, got %v", err)
+	}
+	other = transactionConsent(challenge.ID, sender.lastCode())
+	other.MerchantID = 6
+	if err := uc.VerifyChallenge(other); !errors.Is(err, ErrOTPBindingDiff) {
+		t.Errorf("Expected ErrOTPBindingDiff for another merchant, got %v", err)
+	}
+
+	consent := transactionConsent(challenge.ID, sender.lastCode())
+	if err := uc.VerifyChallenge(consent); err != nil {
+		t.Fatalf("Expected the code accepted, got %v", err)
+	}
+	if challengeRepo.challenges[0].Status != model.OTPStatusPending {
+		t.Errorf("Expected a verified challenge left for the action to use up, got %s", challengeRepo.challenges[0].Status)
+	}
+	if err := uc.ConsumeChallenge(challengeRepo, consent); err != nil {
+		t.Fatalf("Expected the challenge consumed, got %v", err)
+	}
+	stored := challengeRepo.challenges[0]
+	if stored.Status != model.OTPStatusConsumed || stored.ConsumedAt == nil || stored.Reference != "CONT-OTP" {
+		t.Errorf("Expected the challenge consumed by CONT-OTP, got %+v", stored)
+	}
+	if err := uc.VerifyChallenge(consent); !errors.Is(err, ErrOTPNotUsable) {
+		t.Errorf("Expected a consumed challenge rejected, got %v", err)
+	}
+	if err := uc.ConsumeChallenge(challengeRepo, consent); !errors.Is(err, ErrOTPNotUsable) {
+		t.Errorf("Expected a consumed challenge not used twice, got %v", err)
+	}
+}
+
+// Test: Wrong codes lock the challenge and resending does not reset the attempts
+func TestVerifyChallenge_Lock(t *testing.T) {
+	uc, challengeRepo, sender := newOTPFixture()
+	challenge, _ := uc.CreateChallenge(OTPChallengeRequest{ConsumerID: 1, MerchantID: 5, Amount: 3000000})
+	wrong := func() string {
+		if sender.lastCode() == "000000" {
+			return "000001"
+		}
+		return "000000"
+	}
+
+	if err := uc.VerifyChallenge(transactionConsent(challenge.ID, wrong())); !errors.Is(err, ErrInvalidOTP) {
+		t.Fatalf("Expected ErrInvalidOTP, got %v", err)
+	}
+	challengeRepo.challenges[0].LastSentAt = time.Now().Add(-2 * time.Minute)
+	if _, err := uc.ResendChallenge(challenge.ID); err != nil {
+		t.Fatalf("Expected resend after the cooldown, got %v", err)
+	}
+	if challengeRepo.challenges[0].Attempts != 1 {
+		t.Errorf("Expected attempts kept across resends, got %d", challengeRepo.challenges[0].Attempts)
+	}
+
+	uc.VerifyChallenge(transactionConsent(challenge.ID, wrong()))
+	if err := uc.VerifyChallenge(transactionConsent(challenge.ID, wrong())); !errors.Is(err, ErrOTPLocked) {
+		t.Fatalf("Expected ErrOTPLocked on the third wrong code, got %v", err)
+	}
+	if err := uc.VerifyChallenge(transactionConsent(challenge.ID, sender.lastCode())); !errors.Is(err, ErrOTPNotUsable) {
+		t.Errorf("Expected the right code rejected once locked, got %v", err)
+	}
+}
+
+// Test: Resends wait for the cooldown, stop at the send limit and void the earlier code; expired codes fail
+func TestResendChallenge(t *testing.T) {
+	uc, challengeRepo, sender := newOTPFixture()
+	challenge, _ := uc.CreateChallenge(OTPChallengeRequest{ConsumerID: 1, MerchantID: 5, Amount: 3000000})
+	first := sender.lastCode()
+
+	if _, err := uc.ResendChallenge(challenge.ID); !errors.Is(err, ErrOTPCooldown) {
+		t.Errorf("Expected ErrOTPCooldown right after sending, got %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		challengeRepo.challenges[0].LastSentAt = time.Now().Add(-2 * time.Minute)
+		if _, err := uc.ResendChallenge(challenge.ID); err != nil {
+			t.Fatalf("Expected resend %d, got %v", i+1, err)
+		}
+	}
+	challengeRepo.challenges[0].LastSentAt = time.Now().Add(-2 * time.Minute)
+	if _, err := uc.ResendChallenge(challenge.ID); err == nil {
+		t.Error("Expected no fourth code for one challenge")
+	}
+
+	if first != sender.lastCode() {
+		if err := uc.VerifyChallenge(transactionConsent(challenge.ID, first)); !errors.Is(err, ErrInvalidOTP) {
+			t.Errorf("Expected the first code voided by the resend, got %v", err)
+		}
+	}
+
+	challengeRepo.challenges[0].ExpiresAt = time.Now().Add(-time.Second)
+	if err := uc.VerifyChallenge(transactionConsent(challenge.ID, sender.lastCode())); !errors.Is(err, ErrOTPExpired) {
+		t.Errorf("Expected ErrOTPExpired, got %v", err)
+	}
+	if challengeRepo.challenges[0].Status != model.OTPStatusExpired {
+		t.Errorf("Expected EXPIRED, got %s", challengeRepo.challenges[0].Status)
+	}
+}
@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"main/internal/model"
+	"main/internal/repository"
+)
+
+var ErrPaymentExceedsOutstanding = errors.New("jumlah pembayaran melebihi sisa tagihan kontrak")
+
+// InstallmentPaymentRequest is money received for the installments of a contract
+type InstallmentPaymentRequest struct {
+	Amount       float64   `json:"amount"`
+	Reference    string    `json:"reference"` // bank / channel reference, a payment is posted once per reference
+	PaidAt       time.Time `json:"paid_at"`
+	FromSuspense bool      `json:"-"` // money already held in payment suspense
+}
+
+// PaymentPosting is how a payment was applied to a contract
+type PaymentPosting struct {
+	Payment           *model.Payment `json:"payment"`
+	InstallmentAmount float64        `json:"installment_amount"`
+	LateFeeAmount     float64        `json:"late_fee_amount"`
+	Completed         bool           `json:"completed"` // the payment closed the last installment
+	Duplicate         bool           `json:"duplicate"` // the reference was already posted, nothing changed
+}
+
+// PaymentPoster applies money received to the installments of a contract
+type PaymentPoster interface {
+	PostInstallmentPayment(transactionID uint, req InstallmentPaymentRequest) (*PaymentPosting, error)
+}
+
+// PaymentUsecase defines all business logic operations for installment repayments
+type PaymentUsecase interface {
+	PaymentPoster
+	GetPayments(transactionID uint) ([]model.Payment, error)
+}
+
+// paymentUsecase is the implementation of PaymentUsecase
+type paymentUsecase struct {
+	transactionRepo repository.TransactionRepository
+	installmentRepo repository.InstallmentRepository
+	paymentRepo     repository.PaymentRepository
+	transactor      repository.Transactor
+	notifier        EventNotifier
+	mu              sync.Mutex
+}
+
+// NewPaymentUsecase creates a new instance of PaymentUsecase
+func NewPaymentUsecase(
+	transactionRepo repository.TransactionRepository,
+	installmentRepo repository.InstallmentRepository,
+	paymentRepo repository.PaymentRepository,
+	transactor repository.Transactor,
+	notifier EventNotifier,
+) PaymentUsecase {
+	return &paymentUsecase{
+		transactionRepo: transactionRepo,
+		installmentRepo: installmentRepo,
+		paymentRepo:     paymentRepo,
+		transactor:      transactor,
+		notifier:        notifier,
+	}
+}
+
+// PostInstallmentPayment applies a payment to the oldest installments first, the unpaid late fee of
+// an installment before its amount. The payment is recorded before the installments change, in the
+// same database transaction, so a reference posted twice concurrently fails on its unique key and
+// leaves the installments untouched. A reference already posted to the contract returns the earlier
+// payment unchanged. Paying the last installment completes the contract and gives the financed
+// amount back to the consumer limit.
+func (u *paymentUsecase) PostInstallmentPayment(transactionID uint, req InstallmentPaymentRequest) (*PaymentPosting, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	req.Reference = strings.TrimSpace(req.Reference)
+	if req.Reference == "" {
+		return nil, errors.New("referensi pembayaran wajib diisi")
+	}
+	req.Amount = roundMoney(req.Amount)
+	if req.Amount <= 0 {
+		return nil, errors.New("jumlah pembayaran harus lebih dari 0")
+	}
+
+	if existing, err := u.paymentRepo.GetByReference(req.Reference); err == nil {
+		if existing.TransactionID != transactionID || existing.Type != model.PaymentTypeInstallment {
+			return nil, fmt.Errorf("referensi pembayaran %s sudah dipakai", req.Reference)
+		}
+		log.Printf("⚠ Pembayaran %s sudah diposting, dilewati\n", req.Reference)
+		return &PaymentPosting{Payment: existing, Duplicate: true}, nil
+	}
+
+	transaction, err := u.transactionRepo.GetByID(transactionID)
+	if err != nil {
+		return nil, errors.New("transaksi tidak ditemukan")
+	}
+	if transaction.Status != model.TransactionStatusActive && transaction.Status != model.TransactionStatusDefaulted {
+		return nil, fmt.Errorf("transaksi berstatus %s tidak menerima pembayaran angsuran", transaction.Status)
+	}
+
+	installments, err := u.installmentRepo.GetByTransactionID(transaction.ID)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(installments, func(i, j int) bool {
+		return installments[i].DueDate.Before(installments[j].DueDate)
+	})
+
+	var owed float64
+	for _, installment := range installments {
+		owed += installment.Outstanding() + installment.OutstandingLateFee()
+	}
+	if req.Amount > roundMoney(owed) {
+		return nil, fmt.Errorf("%w (Rp %.2f)", ErrPaymentExceedsOutstanding, roundMoney(owed))
+	}
+
+	now := time.Now()
+	if req.PaidAt.IsZero() {
+		req.PaidAt = now
+	}
+
+	posting := &PaymentPosting{
+		Payment: &model.Payment{
+			TransactionID: transaction.ID,
+			ConsumerID:    transaction.ConsumerID,
+			Type:          model.PaymentTypeInstallment,
+			Amount:        req.Amount,
+			Reference:     req.Reference,
+			PaidAt:        req.PaidAt,
+			CreatedAt:     now,
+		},
+		Completed: true,
+	}
+	previousStatus := transaction.Status
+	err = u.transactor.Transaction(func(tx *repository.Repositories) error {
+		if err := tx.Payments.Create(posting.Payment); err != nil {
+			return fmt.Errorf("gagal mencatat pembayaran %s: %w", req.Reference, err)
+		}
+
+		remaining := req.Amount
+		for i := range installments {
+			installment := &installments[i]
+			lateFee := min(remaining, roundMoney(installment.OutstandingLateFee()))
+			amount := min(remaining-lateFee, roundMoney(installment.Outstanding()))
+			remaining = roundMoney(remaining - lateFee - amount)
+
+			if lateFee > 0 || amount > 0 {
+				installment.LateFeePaid = roundMoney(installment.LateFeePaid + lateFee)
+				installment.PaidAmount = roundMoney(installment.PaidAmount + amount)
+				if installment.Outstanding() <= 0 {
+					installment.Status = model.InstallmentStatusPaid
+					installment.PaidAt = &req.PaidAt
+					installment.DaysPastDue = 0
+				} else {
+					installment.Status = model.InstallmentStatusPartial
+				}
+				installment.UpdatedAt = now
+				if err := tx.Installments.Update(installment); err != nil {
+					return err
+				}
+				posting.LateFeeAmount = roundMoney(posting.LateFeeAmount + lateFee)
+				posting.InstallmentAmount = roundMoney(posting.InstallmentAmount + amount)
+			}
+			if installment.Outstanding() > 0 {
+				posting.Completed = false
+			}
+		}
+
+		if err := ledgerIn(tx).Post(installmentPaymentEntry(transaction, posting, req.FromSuspense)); err != nil {
+			return err
+		}
+
+		if !posting.Completed {
+			return nil
+		}
+		if err := releaseLimit(tx.Limits, transaction.ConsumerID, transaction.Tenor, transaction.FinancedAmount()); err != nil {
+			return err
+		}
+		transaction.Status = model.TransactionStatusCompleted
+		transaction.DaysPastDue = 0
+		transaction.Collectibility = 1
+		transaction.UpdatedAt = now
+		return tx.Transactions.Update(transaction)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	u.notifier.NotifyPaymentReceived(transaction, posting.Payment)
+	if posting.Completed {
+		u.notifier.NotifyStatusChanged(transaction, previousStatus)
+	}
+
+	log.Printf("✓ Pembayaran angsuran kontrak %s: Rp %.2f (%s)\n", transaction.ContractNumber, req.Amount, req.Reference)
+	return posting, nil
+}
+
+func (u *paymentUsecase) GetPayments(transactionID uint) ([]model.Payment, error) {
+	if _, err := u.transactionRepo.GetByID(transactionID); err != nil {
+		return nil, errors.New("transaksi tidak ditemukan")
+	}
+	return u.paymentRepo.GetByTransactionID(transactionID)
+}
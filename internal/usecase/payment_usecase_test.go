@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"main/internal/ledger"
+	"main/internal/model"
+	"main/internal/repository"
+)
+
+type paymentFixture struct {
+	uc              PaymentUsecase
+	transactionRepo *MockTransactionRepository
+	installmentRepo *MockInstallmentRepository
+	limitRepo       *MockConsumerLimitRepository
+	paymentRepo     *MockPaymentRepository
+	ledger          LedgerUsecase
+	transactor      *MockTransactor
+	notifier        *MockEventNotifier
+}
+
+// newPaymentFixture books the test contract on 1 Jan 2026 with the OTR used from the tenor 3 limit
+func newPaymentFixture() *paymentFixture {
+	start := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	f := &paymentFixture{
+		transactionRepo: NewMockTransactionRepository(),
+		installmentRepo: &MockInstallmentRepository{},
+		limitRepo:       NewMockConsumerLimitRepository(),
+		notifier:        &MockEventNotifier{},
+	}
+	transaction := bookTestContract(f.transactionRepo, f.installmentRepo, start)
+	f.limitRepo.Create(&model.ConsumerLimit{ConsumerID: 1, Tenor: 3, LimitAmount: 10000000, UsedAmount: 3000000})
+
+	var journalRepo *MockJournalRepository
+	f.ledger, journalRepo = newTestLedger()
+	f.ledger.Post(activationEntry(transaction, start))
+
+	paymentRepo := &MockPaymentRepository{}
+	f.paymentRepo = paymentRepo
+	f.transactor = newTestTransactor(repository.Repositories{
+		Transactions: f.transactionRepo,
+		Limits:       f.limitRepo,
+		Installments: f.installmentRepo,
+		Payments:     paymentRepo,
+		Journal:      journalRepo,
+	})
+	f.uc = NewPaymentUsecase(f.transactionRepo, f.installmentRepo, paymentRepo, f.transactor, f.notifier)
+	return f
+}
+
+// Test: A payment settles the late fee before the installment and spills into the next installment
+func TestPostInstallmentPayment_Allocation(t *testing.T) {
+	f := newPaymentFixture()
+	f.installmentRepo.installments[0].LateFee = 50000
+
+	posting, err := f.uc.PostInstallmentPayment(1, InstallmentPaymentRequest{
+		Amount:    1500000,
+		Reference: "TRF-001",
+		PaidAt:    time.Date(2026, 2, 10, 9, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if posting.LateFeeAmount != 50000 || posting.InstallmentAmount != 1450000 || posting.Completed {
+		t.Errorf("Expected late fee 50000 and installments 1450000, got %+v", posting)
+	}
+
+	first, second := f.installmentRepo.installments[0], f.installmentRepo.installments[1]
+	if first.Status != model.InstallmentStatusPaid || first.LateFeePaid != 50000 || first.PaidAt == nil {
+		t.Errorf("Expected installment 1 paid with its late fee, got %+v", first)
+	}
+	if second.Status != model.InstallmentStatusPartial || second.PaidAmount != 350000 {
+		t.Errorf("Expected installment 2 partially paid 350000, got %s %.2f", second.Status, second.PaidAmount)
+	}
+
+	if receivable := accountBalance(t, f.ledger, ledger.Receivable); receivable != 1850000 {
+		t.Errorf("Expected receivable 1850000, got %.2f", receivable)
+	}
+	if lateFees := accountBalance(t, f.ledger, ledger.LateFeeIncome); lateFees != 50000 {
+		t.Errorf("Expected late fee income 50000, got %.2f", lateFees)
+	}
+
+	// The bank retries the same transfer
+	duplicate, err := f.uc.PostInstallmentPayment(1, InstallmentPaymentRequest{Amount: 1500000, Reference: "TRF-001"})
+	if err != nil || !duplicate.Duplicate || duplicate.Payment.ID != posting.Payment.ID {
+		t.Errorf("Expected the earlier payment returned as duplicate, got %+v, %v", duplicate, err)
+	}
+	if receivable := accountBalance(t, f.ledger, ledger.Receivable); receivable != 1850000 {
+		t.Errorf("Expected receivable unchanged at 1850000, got %.2f", receivable)
+	}
+}
+
+// Test: A payment that cannot be recorded, such as a reference posted concurrently, leaves the installments untouched
+func TestPostInstallmentPayment_PaymentNotRecorded(t *testing.T) {
+	f := newPaymentFixture()
+	f.paymentRepo.createErr = errors.New("duplicate payment reference")
+
+	if _, err := f.uc.PostInstallmentPayment(1, InstallmentPaymentRequest{Amount: 1100000, Reference: "TRF-001"}); err == nil {
+		t.Fatal("Expected error when the payment cannot be recorded, got nil")
+	}
+	for _, installment := range f.installmentRepo.installments {
+		if installment.PaidAmount != 0 || installment.Status != model.InstallmentStatusUnpaid {
+			t.Errorf("Expected installment %d untouched, got %s %.2f", installment.Sequence, installment.Status, installment.PaidAmount)
+		}
+	}
+	if receivable := accountBalance(t, f.ledger, ledger.Receivable); receivable != 3300000 {
+		t.Errorf("Expected receivable unchanged at 3300000, got %.2f", receivable)
+	}
+	if f.transactor.failed != 1 || len(f.notifier.events) != 0 {
+		t.Errorf("Expected the posting rolled back and not announced, got %d failed units and %v", f.transactor.failed, f.notifier.events)
+	}
+}
+
+// Test: Paying the last installment completes the contract and releases the limit; overpayment is refused
+func TestPostInstallmentPayment_Completion(t *testing.T) {
+	f := newPaymentFixture()
+
+	if _, err := f.uc.PostInstallmentPayment(1, InstallmentPaymentRequest{Amount: 3300001, Reference: "TRF-001"}); !errors.Is(err, ErrPaymentExceedsOutstanding) {
+		t.Errorf("Expected ErrPaymentExceedsOutstanding, got %v", err)
+	}
+
+	posting, err := f.uc.PostInstallmentPayment(1, InstallmentPaymentRequest{Amount: 3300000, Reference: "TRF-002"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !posting.Completed {
+		t.Error("Expected the contract completed")
+	}
+
+	transaction, _ := f.transactionRepo.GetByID(1)
+	if transaction.Status != model.TransactionStatusCompleted {
+		t.Errorf("Expected COMPLETED, got %s", transaction.Status)
+	}
+	if limit, _ := f.limitRepo.GetByConsumerAndTenor(1, 3); limit.UsedAmount != 0 {
+		t.Errorf("Expected limit released, got used %.2f", limit.UsedAmount)
+	}
+	if receivable := accountBalance(t, f.ledger, ledger.Receivable); receivable != 0 {
+		t.Errorf("Expected receivable cleared, got %.2f", receivable)
+	}
+	if len(f.notifier.events) != 2 || f.notifier.events[1] != "STATUS CTR-20260101 ACTIVE->COMPLETED" {
+		t.Errorf("Expected payment and completion notified, got %v", f.notifier.events)
+	}
+
+	if _, err := f.uc.PostInstallmentPayment(1, InstallmentPaymentRequest{Amount: 1000, Reference: "TRF-003"}); err == nil {
+		t.Error("Expected error for payment on a completed contract, got nil")
+	}
+}
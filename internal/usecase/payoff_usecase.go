@@ -0,0 +1,267 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"main/internal/ledger"
+	"main/internal/model"
+	"main/internal/repository"
+)
+
+var ErrPayoffQuoteExpired = errors.New("penawaran pelunasan sudah kedaluwarsa, minta penawaran baru")
+
+// PayoffPolicy holds the early settlement terms
+type PayoffPolicy struct {
+	EarlyTerminationFeeRate float64 // fee as a fraction of the remaining principal
+	QuoteValidityDays       int     // days after the quote date the quoted amount is honoured
+}
+
+// DefaultPayoffPolicy returns a 2% early termination fee and quotes valid for 3 days
+func DefaultPayoffPolicy() PayoffPolicy {
+	return PayoffPolicy{
+		EarlyTerminationFeeRate: 0.02,
+		QuoteValidityDays:       3,
+	}
+}
+
+// PayoffQuote is the amount needed to close a contract early on a given date
+type PayoffQuote struct {
+	TransactionID       uint      `json:"transaction_id"`
+	ContractNumber      string    `json:"contract_number"`
+	QuoteDate           time.Time `json:"quote_date"`
+	ValidUntil          time.Time `json:"valid_until"`
+	RemainingPrincipal  float64   `json:"remaining_principal"`
+	AccruedInterest     float64   `json:"accrued_interest"`
+	WaivedInterest      float64   `json:"waived_interest"`
+	OutstandingLateFees float64   `json:"outstanding_late_fees"`
+	EarlyTerminationFee float64   `json:"early_termination_fee"`
+	TotalAmount         float64   `json:"total_amount"`
+}
+
+// PayoffUsecase defines all business logic operations for early settlement
+type PayoffUsecase interface {
+	GetPayoffQuote(transactionID uint, quoteDate time.Time) (*PayoffQuote, error)
+	Settle(transactionID uint, quoteDate time.Time, amount float64, reference string) (*model.Payment, error)
+}
+
+// payoffUsecase is the implementation of PayoffUsecase
+type payoffUsecase struct {
+	transactionRepo repository.TransactionRepository
+	installmentRepo repository.InstallmentRepository
+	transactor      repository.Transactor
+	notifier        EventNotifier
+	policy          PayoffPolicy
+	mu              sync.Mutex
+}
+
+// NewPayoffUsecase creates a new instance of PayoffUsecase
+func NewPayoffUsecase(
+	transactionRepo repository.TransactionRepository,
+	installmentRepo repository.InstallmentRepository,
+	transactor repository.Transactor,
+	notifier EventNotifier,
+	policy PayoffPolicy,
+) PayoffUsecase {
+	return &payoffUsecase{
+		transactionRepo: transactionRepo,
+		installmentRepo: installmentRepo,
+		transactor:      transactor,
+		notifier:        notifier,
+		policy:          policy,
+	}
+}
+
+func (u *payoffUsecase) GetPayoffQuote(transactionID uint, quoteDate time.Time) (*PayoffQuote, error) {
+	transaction, err := u.getOpenTransaction(transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	quote, _, err := u.quote(transaction, quoteDate)
+	return quote, err
+}
+
+// Settle closes every installment of the contract against a payment of exactly the quoted amount
+// and gives the financed amount back to the consumer limit
+func (u *payoffUsecase) Settle(transactionID uint, quoteDate time.Time, amount float64, reference string) (*model.Payment, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	reference = strings.TrimSpace(reference)
+	if reference == "" {
+		return nil, errors.New("referensi pembayaran wajib diisi")
+	}
+
+	transaction, err := u.getOpenTransaction(transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if dateOnly(quoteDate).After(dateOnly(now)) {
+		return nil, errors.New("tanggal penawaran pelunasan belum berlaku")
+	}
+
+	quote, allocations, err := u.quote(transaction, quoteDate)
+	if err != nil {
+		return nil, err
+	}
+	if dateOnly(now).After(quote.ValidUntil) {
+		return nil, ErrPayoffQuoteExpired
+	}
+	if math.Abs(amount-quote.TotalAmount) >= 0.01 {
+		return nil, fmt.Errorf("jumlah pembayaran tidak sesuai dengan penawaran pelunasan (Rp %.2f)", quote.TotalAmount)
+	}
+
+	previousStatus := transaction.Status
+	payment := &model.Payment{
+		TransactionID: transaction.ID,
+		ConsumerID:    transaction.ConsumerID,
+		Type:          model.PaymentTypeSettlement,
+		Amount:        quote.TotalAmount,
+		Reference:     reference,
+		PaidAt:        now,
+		CreatedAt:     now,
+	}
+	err = u.transactor.Transaction(func(tx *repository.Repositories) error {
+		// The payment is recorded first: a reference already posted fails on its unique key before anything changes
+		if err := tx.Payments.Create(payment); err != nil {
+			return fmt.Errorf("gagal mencatat pembayaran %s: %w", reference, err)
+		}
+
+		// Only the money received counts as paid; the interest of later periods is waived
+		for _, allocation := range allocations {
+			installment := allocation.installment
+			installment.PaidAmount = roundMoney(installment.PaidAmount + allocation.paid)
+			installment.WaivedAmount = roundMoney(installment.WaivedAmount + allocation.waived)
+			installment.LateFeePaid = installment.LateFee
+			installment.PaidAt = &now
+			installment.DaysPastDue = 0
+			installment.Status = model.InstallmentStatusPaid
+			installment.UpdatedAt = now
+			if err := tx.Installments.Update(installment); err != nil {
+				return err
+			}
+		}
+
+		if err := releaseLimit(tx.Limits, transaction.ConsumerID, transaction.Tenor, transaction.FinancedAmount()); err != nil {
+			return err
+		}
+
+		transaction.Status = model.TransactionStatusCompleted
+		transaction.DaysPastDue = 0
+		transaction.Collectibility = 1
+		transaction.UpdatedAt = now
+		if err := tx.Transactions.Update(transaction); err != nil {
+			return err
+		}
+
+		books := ledgerIn(tx)
+		deferred, err := books.ContractBalance(transaction.ID, ledger.UnearnedInterest)
+		if err != nil {
+			return err
+		}
+		return books.Post(payoffEntry(transaction, quote, payment, deferred))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	u.notifier.NotifyPaymentReceived(transaction, payment)
+	u.notifier.NotifyStatusChanged(transaction, previousStatus)
+
+	log.Printf("✓ Kontrak %s dilunasi dipercepat: Rp %.2f\n", transaction.ContractNumber, quote.TotalAmount)
+	return payment, nil
+}
+
+// payoffAllocation is how a payoff closes one installment: the part paid and the interest waived
+type payoffAllocation struct {
+	installment *model.Installment
+	paid        float64
+	waived      float64
+}
+
+// quote computes the payoff amount on quoteDate. Interest is owed for elapsed periods and
+// pro rata for the running period; interest of later periods is waived.
+func (u *payoffUsecase) quote(transaction *model.Transaction, quoteDate time.Time) (*PayoffQuote, []payoffAllocation, error) {
+	quoteDate = dateOnly(quoteDate)
+	if quoteDate.Before(dateOnly(transaction.CreatedAt)) {
+		return nil, nil, errors.New("tanggal penawaran sebelum tanggal kontrak")
+	}
+
+	installments, err := u.installmentRepo.GetByTransactionID(transaction.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	quote := &PayoffQuote{
+		TransactionID:  transaction.ID,
+		ContractNumber: transaction.ContractNumber,
+		QuoteDate:      quoteDate,
+		ValidUntil:     quoteDate.AddDate(0, 0, u.policy.QuoteValidityDays),
+	}
+
+	var principal, accrued, waived, lateFees float64
+	var allocations []payoffAllocation
+	for i := range installments {
+		installment := &installments[i]
+		outstanding := installment.Outstanding()
+		if outstanding <= 0 || installment.Amount <= 0 {
+			continue
+		}
+
+		// Partial payments reduce principal and interest proportionally
+		ratio := outstanding / installment.Amount
+		interest := installment.InterestAmount * ratio
+		principal += installment.PrincipalAmount * ratio
+		lateFees += installment.OutstandingLateFee()
+
+		dueDate := dateOnly(installment.DueDate)
+		periodStart := addMonths(dueDate, -1)
+		waivedInterest := 0.0
+		switch {
+		case !quoteDate.Before(dueDate):
+			// elapsed period: the interest is owed in full
+		case quoteDate.After(periodStart):
+			earned := interest * float64(daysBetween(periodStart, quoteDate)) / float64(daysBetween(periodStart, dueDate))
+			waivedInterest = interest - earned
+		default:
+			waivedInterest = interest
+		}
+		accrued += interest - waivedInterest
+		waived += waivedInterest
+
+		waivedInterest = roundMoney(waivedInterest)
+		allocations = append(allocations, payoffAllocation{
+			installment: installment,
+			paid:        roundMoney(outstanding - waivedInterest),
+			waived:      waivedInterest,
+		})
+	}
+
+	quote.RemainingPrincipal = roundMoney(principal)
+	quote.AccruedInterest = roundMoney(accrued)
+	quote.WaivedInterest = roundMoney(waived)
+	quote.OutstandingLateFees = roundMoney(lateFees)
+	quote.EarlyTerminationFee = roundMoney(quote.RemainingPrincipal * u.policy.EarlyTerminationFeeRate)
+	quote.TotalAmount = roundMoney(quote.RemainingPrincipal + quote.AccruedInterest + quote.OutstandingLateFees + quote.EarlyTerminationFee)
+	return quote, allocations, nil
+}
+
+// getOpenTransaction loads a transaction that can still be settled
+func (u *payoffUsecase) getOpenTransaction(transactionID uint) (*model.Transaction, error) {
+	transaction, err := u.transactionRepo.GetByID(transactionID)
+	if err != nil {
+		return nil, errors.New("transaksi tidak ditemukan")
+	}
+	if transaction.Status != model.TransactionStatusActive && transaction.Status != model.TransactionStatusDefaulted {
+		return nil, fmt.Errorf("transaksi berstatus %s tidak dapat dilunasi", transaction.Status)
+	}
+	return transaction, nil
+}
@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"main/internal/ledger"
+	"main/internal/model"
+	"main/internal/repository"
+)
+
+// MockPaymentRepository for testing
+type MockPaymentRepository struct {
+	payments  []model.Payment
+	createErr error // returned by Create, as a unique key violation would be
+}
+
+func (m *MockPaymentRepository) Create(payment *model.Payment) error {
+	if m.createErr != nil {
+		return m.createErr
+	}
+	for _, existing := range m.payments {
+		if existing.Reference == payment.Reference {
+			return errors.New("duplicate payment reference")
+		}
+	}
+	payment.ID = uint(len(m.payments) + 1)
+	m.payments = append(m.payments, *payment)
+	return nil
+}
+
+func (m *MockPaymentRepository) GetByTransactionID(transactionID uint) ([]model.Payment, error) {
+	var payments []model.Payment
+	for _, payment := range m.payments {
+		if payment.TransactionID == transactionID {
+			payments = append(payments, payment)
+		}
+	}
+	return payments, nil
+}
+
+func (m *MockPaymentRepository) GetByReference(reference string) (*model.Payment, error) {
+	for i := range m.payments {
+		if m.payments[i].Reference == reference {
+			payment := m.payments[i]
+			return &payment, nil
+		}
+	}
+	return nil, errors.New("payment not found")
+}
+
+type payoffFixture struct {
+	uc              PayoffUsecase
+	transactionRepo *MockTransactionRepository
+	installmentRepo *MockInstallmentRepository
+	limitRepo       *MockConsumerLimitRepository
+	paymentRepo     *MockPaymentRepository
+	ledger          LedgerUsecase
+	notifier        *MockEventNotifier
+}
+
+// newPayoffFixture books the test contract on start with the OTR used from the tenor 3 limit
+func newPayoffFixture(start time.Time) *payoffFixture {
+	f := &payoffFixture{
+		transactionRepo: NewMockTransactionRepository(),
+		installmentRepo: &MockInstallmentRepository{},
+		limitRepo:       NewMockConsumerLimitRepository(),
+		paymentRepo:     &MockPaymentRepository{},
+		notifier:        &MockEventNotifier{},
+	}
+	transaction := bookTestContract(f.transactionRepo, f.installmentRepo, start)
+	f.limitRepo.Create(&model.ConsumerLimit{ConsumerID: 1, Tenor: 3, LimitAmount: 10000000, UsedAmount: 3000000})
+
+	var journalRepo *MockJournalRepository
+	f.ledger, journalRepo = newTestLedger()
+	f.ledger.Post(activationEntry(transaction, start))
+
+	f.uc = NewPayoffUsecase(f.transactionRepo, f.installmentRepo, newTestTransactor(repository.Repositories{
+		Transactions: f.transactionRepo,
+		Limits:       f.limitRepo,
+		Installments: f.installmentRepo,
+		Payments:     f.paymentRepo,
+		Journal:      journalRepo,
+	}), f.notifier, DefaultPayoffPolicy())
+	return f
+}
+
+// Test: Quote mid-period charges elapsed interest pro rata and waives the rest
+func TestGetPayoffQuote(t *testing.T) {
+	f := newPayoffFixture(time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC))
+
+	// Installment 1 (due 1 Feb) is unpaid, 14 of 28 days of period 2 have elapsed
+	quote, err := f.uc.GetPayoffQuote(1, time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if quote.RemainingPrincipal != 3000000 {
+		t.Errorf("Expected remaining principal 3000000, got %.2f", quote.RemainingPrincipal)
+	}
+	if quote.AccruedInterest != 150000 || quote.WaivedInterest != 150000 {
+		t.Errorf("Expected accrued 150000 and waived 150000, got %.2f and %.2f", quote.AccruedInterest, quote.WaivedInterest)
+	}
+	if quote.EarlyTerminationFee != 60000 {
+		t.Errorf("Expected early termination fee 60000, got %.2f", quote.EarlyTerminationFee)
+	}
+	if quote.TotalAmount != 3210000 {
+		t.Errorf("Expected total 3210000, got %.2f", quote.TotalAmount)
+	}
+	if !quote.ValidUntil.Equal(time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected quote valid until 2026-02-18, got %s", quote.ValidUntil)
+	}
+}
+
+// Test: Paid installments are excluded from the quote
+func TestGetPayoffQuote_PaidInstallment(t *testing.T) {
+	f := newPayoffFixture(time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC))
+
+	f.installmentRepo.installments[0].PaidAmount = f.installmentRepo.installments[0].Amount
+	f.installmentRepo.installments[0].Status = model.InstallmentStatusPaid
+
+	quote, _ := f.uc.GetPayoffQuote(1, time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC))
+	if quote.RemainingPrincipal != 2000000 || quote.TotalAmount != 2090000 {
+		t.Errorf("Expected principal 2000000 total 2090000, got %.2f and %.2f", quote.RemainingPrincipal, quote.TotalAmount)
+	}
+}
+
+// Test: Settling with the quoted amount closes the contract and restores the limit
+func TestSettle_Valid(t *testing.T) {
+	f := newPayoffFixture(time.Now().AddDate(0, 0, -40))
+	today := time.Now()
+
+	quote, _ := f.uc.GetPayoffQuote(1, today)
+
+	if _, err := f.uc.Settle(1, today, quote.TotalAmount-1000, "BANK-REF-1"); err == nil {
+		t.Error("Expected error for amount not matching the quote, got nil")
+	}
+	if _, err := f.uc.Settle(1, today, quote.TotalAmount, " "); err == nil {
+		t.Error("Expected error for a payoff without reference, got nil")
+	}
+
+	// Interest already recognised by the accrual job is not recognised again
+	f.ledger.Post(ledger.NewEntry("ACR-TEST", model.JournalEventAccrual, 1, today, "").
+		Debit(ledger.UnearnedInterest, 50000, "").Credit(ledger.InterestIncome, 50000, ""))
+
+	payment, err := f.uc.Settle(1, today, quote.TotalAmount, "BANK-REF-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if payment.Type != model.PaymentTypeSettlement || payment.Amount != quote.TotalAmount {
+		t.Errorf("Expected settlement payment of %.2f, got %s %.2f", quote.TotalAmount, payment.Type, payment.Amount)
+	}
+
+	transaction, _ := f.transactionRepo.GetByID(1)
+	if transaction.Status != model.TransactionStatusCompleted {
+		t.Errorf("Expected COMPLETED, got %s", transaction.Status)
+	}
+
+	limit, _ := f.limitRepo.GetByConsumerAndTenor(1, 3)
+	if limit.UsedAmount != 0 {
+		t.Errorf("Expected used amount restored to 0, got %.2f", limit.UsedAmount)
+	}
+
+	// Installments record the cash received; the waived interest is kept apart
+	installments, _ := f.installmentRepo.GetByTransactionID(1)
+	var paid, waived float64
+	for _, installment := range installments {
+		if installment.Status != model.InstallmentStatusPaid || installment.Outstanding() != 0 {
+			t.Errorf("Expected installment %d PAID, got %s", installment.Sequence, installment.Status)
+		}
+		paid += installment.PaidAmount
+		waived += installment.WaivedAmount
+	}
+	if math.Abs(paid-quote.RemainingPrincipal-quote.AccruedInterest) > 0.02 || waived == 0 || math.Abs(waived-quote.WaivedInterest) > 0.02 {
+		t.Errorf("Expected %.2f paid and %.2f waived, got %.2f and %.2f",
+			quote.RemainingPrincipal+quote.AccruedInterest, quote.WaivedInterest, paid, waived)
+	}
+
+	// The receivable is closed; only earned interest and the fees reach income
+	if accountBalance(t, f.ledger, ledger.Receivable) != 0 || accountBalance(t, f.ledger, ledger.UnearnedInterest) != 0 {
+		t.Error("Expected receivable and deferred interest closed by the payoff")
+	}
+	if accountBalance(t, f.ledger, ledger.InterestIncome) != quote.AccruedInterest ||
+		accountBalance(t, f.ledger, ledger.Cash) != quote.TotalAmount {
+		t.Errorf("Expected interest income %.2f and cash %.2f", quote.AccruedInterest, quote.TotalAmount)
+	}
+
+	if events := f.notifier.events; len(events) != 2 || events[0] != "PAYMENT "+transaction.ContractNumber ||
+		events[1] != "STATUS "+transaction.ContractNumber+" ACTIVE->COMPLETED" {
+		t.Errorf("Expected payment and completion notified, got %v", events)
+	}
+
+	if _, err := f.uc.Settle(1, today, quote.TotalAmount, "BANK-REF-2"); err == nil {
+		t.Error("Expected error settling a completed contract, got nil")
+	}
+}
+
+// Test: A quote past its validity cannot be settled
+func TestSettle_ExpiredQuote(t *testing.T) {
+	f := newPayoffFixture(time.Now().AddDate(0, 0, -40))
+	quoteDate := time.Now().AddDate(0, 0, -10)
+
+	quote, _ := f.uc.GetPayoffQuote(1, quoteDate)
+	if _, err := f.uc.Settle(1, quoteDate, quote.TotalAmount, "BANK-REF-1"); !errors.Is(err, ErrPayoffQuoteExpired) {
+		t.Errorf("Expected ErrPayoffQuoteExpired, got %v", err)
+	}
+}
@@ -0,0 +1,258 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"main/internal/model"
+	"main/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// limitTenors are the tenor buckets a consumer limit can be assigned to.
+// Products offer a subset of these tenors.
+var limitTenors = map[int]bool{1: true, 2: true, 3: true, 6: true}
+
+// Pricing is the server-side price of a financed purchase
+type Pricing struct {
+	ProductCode       string  `json:"product_code"`
+	PromoCode         string  `json:"promo_code,omitempty"`
+	Tenor             int     `json:"tenor"`
+	OTR               float64 `json:"otr"`
+	DownPayment       float64 `json:"down_payment"`
+	FinancedAmount    float64 `json:"financed_amount"` // OTR - down payment
+	InterestRate      float64 `json:"interest_rate"`   // percent per month, flat
+	InterestAmount    float64 `json:"interest_amount"`
+	AdminFee          float64 `json:"admin_fee"`
+	InstallmentAmount float64 `json:"installment_amount"`
+	TotalPayable      float64 `json:"total_payable"` // OTR + interest + admin fee, down payment included
+}
+
+// ProductPricer prices a purchase from the product catalogue
+type ProductPricer interface {
+	Price(productCode string, tenor int, otr, downPayment float64, at time.Time) (*Pricing, error)
+}
+
+// ProductUsecase defines all business logic operations for the product catalogue
+type ProductUsecase interface {
+	ProductPricer
+	CreateProduct(product *model.Product) error
+	GetProducts() ([]model.Product, error)
+	GetProduct(code string) (*model.Product, error)
+	AddPromo(productCode string, promo *model.ProductPromo) error
+}
+
+// productUsecase is the implementation of ProductUsecase
+type productUsecase struct {
+	repo repository.ProductRepository
+	mu   sync.RWMutex
+}
+
+// NewProductUsecase creates a new instance of ProductUsecase
+func NewProductUsecase(repo repository.ProductRepository) ProductUsecase {
+	return &productUsecase{repo: repo}
+}
+
+// CreateProduct validates and stores a product with its tenor rates
+func (u *productUsecase) CreateProduct(product *model.Product) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	product.Code = normalizeProductCode(product.Code)
+	if product.Code == "" || strings.TrimSpace(product.Name) == "" {
+		return errors.New("kode dan nama produk tidak boleh kosong")
+	}
+	if product.AdminFeeType != model.AdminFeeTypeFlat && product.AdminFeeType != model.AdminFeeTypePercent {
+		return errors.New("tipe biaya admin harus FLAT atau PERCENT")
+	}
+	if product.AdminFeeValue < 0 {
+		return errors.New("biaya admin tidak boleh negatif")
+	}
+	if product.MinOTR < 0 || (product.MaxOTR > 0 && product.MaxOTR < product.MinOTR) {
+		return errors.New("rentang OTR produk tidak valid")
+	}
+	if product.MinDownPaymentPct < 0 || product.MinDownPaymentPct >= 100 {
+		return errors.New("minimal DP harus antara 0 dan 100 persen")
+	}
+	if len(product.Rates) == 0 {
+		return errors.New("produk harus memiliki minimal satu tenor")
+	}
+
+	seen := make(map[int]bool)
+	for _, rate := range product.Rates {
+		if !limitTenors[rate.Tenor] {
+			return errors.New("tenor harus 1, 2, 3, atau 6 bulan")
+		}
+		if seen[rate.Tenor] {
+			return fmt.Errorf("tenor %d terdaftar lebih dari sekali", rate.Tenor)
+		}
+		if rate.InterestRate < 0 {
+			return errors.New("bunga tidak boleh negatif")
+		}
+		seen[rate.Tenor] = true
+	}
+
+	existing, err := u.repo.GetByCode(product.Code)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if existing != nil {
+		return errors.New("kode produk sudah digunakan")
+	}
+
+	now := time.Now()
+	if product.ValidFrom.IsZero() {
+		product.ValidFrom = now
+	}
+	if product.ValidUntil != nil && !product.ValidUntil.After(product.ValidFrom) {
+		return errors.New("masa berlaku produk tidak valid")
+	}
+	product.Active = true
+	product.Promos = nil
+	product.CreatedAt = now
+	product.UpdatedAt = now
+
+	log.Printf("✓ Produk %s dibuat dengan %d tenor\n", product.Code, len(product.Rates))
+	return u.repo.Create(product)
+}
+
+func (u *productUsecase) GetProducts() ([]model.Product, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.repo.GetAll()
+}
+
+func (u *productUsecase) GetProduct(code string) (*model.Product, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.repo.GetByCode(normalizeProductCode(code))
+}
+
+// AddPromo attaches a time-boxed pricing override to a product
+func (u *productUsecase) AddPromo(productCode string, promo *model.ProductPromo) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	product, err := u.repo.GetByCode(normalizeProductCode(productCode))
+	if err != nil {
+		return errors.New("produk tidak ditemukan")
+	}
+
+	promo.Code = normalizeProductCode(promo.Code)
+	if promo.Code == "" {
+		return errors.New("kode promo tidak boleh kosong")
+	}
+	if promo.InterestRate < 0 {
+		return errors.New("bunga tidak boleh negatif")
+	}
+	if promo.ValidFrom.IsZero() || !promo.ValidUntil.After(promo.ValidFrom) {
+		return errors.New("masa berlaku promo tidak valid")
+	}
+	if promo.Tenor != 0 && productRate(product, promo.Tenor) == nil {
+		return fmt.Errorf("tenor %d tidak tersedia untuk produk %s", promo.Tenor, product.Code)
+	}
+
+	promo.ProductID = product.ID
+	promo.CreatedAt = time.Now()
+	return u.repo.CreatePromo(promo)
+}
+
+// Price derives interest, admin fee and installment from the product and any promo running at the given time.
+// Interest is flat on the financed principal: (OTR - down payment) x monthly rate x tenor.
+func (u *productUsecase) Price(productCode string, tenor int, otr, downPayment float64, at time.Time) (*Pricing, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	productCode = normalizeProductCode(productCode)
+	if productCode == "" {
+		return nil, errors.New("kode produk wajib diisi")
+	}
+
+	product, err := u.repo.GetByCode(productCode)
+	if err != nil {
+		return nil, errors.New("produk tidak ditemukan")
+	}
+	if !product.Active || at.Before(product.ValidFrom) || (product.ValidUntil != nil && !at.Before(*product.ValidUntil)) {
+		return nil, fmt.Errorf("produk %s tidak berlaku", product.Code)
+	}
+
+	rate := productRate(product, tenor)
+	if rate == nil {
+		return nil, fmt.Errorf("tenor %d tidak tersedia untuk produk %s", tenor, product.Code)
+	}
+	if otr < product.MinOTR || (product.MaxOTR > 0 && otr > product.MaxOTR) {
+		return nil, fmt.Errorf("OTR di luar rentang produk %s", product.Code)
+	}
+	if downPayment < 0 || downPayment >= otr {
+		return nil, errors.New("DP harus lebih dari atau sama dengan 0 dan kurang dari OTR")
+	}
+	if minDP := roundMoney(otr * product.MinDownPaymentPct / 100); downPayment < minDP {
+		return nil, fmt.Errorf("DP minimal %.2f (%.2f%% dari OTR) untuk produk %s", minDP, product.MinDownPaymentPct, product.Code)
+	}
+	principal := otr - downPayment
+
+	pricing := &Pricing{
+		ProductCode:    product.Code,
+		Tenor:          tenor,
+		OTR:            otr,
+		DownPayment:    downPayment,
+		FinancedAmount: principal,
+		InterestRate:   rate.InterestRate,
+	}
+
+	adminFee := product.AdminFeeValue
+	if product.AdminFeeType == model.AdminFeeTypePercent {
+		adminFee = otr * product.AdminFeeValue / 100
+	}
+
+	if promo := activePromo(product, tenor, at); promo != nil {
+		pricing.PromoCode = promo.Code
+		pricing.InterestRate = promo.InterestRate
+		if promo.WaiveAdminFee {
+			adminFee = 0
+		}
+	}
+
+	pricing.InterestAmount = roundMoney(principal * pricing.InterestRate / 100 * float64(tenor))
+	pricing.AdminFee = roundMoney(adminFee)
+	pricing.InstallmentAmount = roundMoney(roundMoney(principal/float64(tenor)) + roundMoney(pricing.InterestAmount/float64(tenor)))
+	pricing.TotalPayable = roundMoney(otr + pricing.InterestAmount + pricing.AdminFee)
+	return pricing, nil
+}
+
+// productRate returns the rate of the given tenor, nil when the product does not offer it
+func productRate(product *model.Product, tenor int) *model.ProductRate {
+	for i := range product.Rates {
+		if product.Rates[i].Tenor == tenor {
+			return &product.Rates[i]
+		}
+	}
+	return nil
+}
+
+// activePromo returns the cheapest promo running at the given time for the tenor
+func activePromo(product *model.Product, tenor int, at time.Time) *model.ProductPromo {
+	var best *model.ProductPromo
+	for i := range product.Promos {
+		promo := &product.Promos[i]
+		if promo.Tenor != 0 && promo.Tenor != tenor {
+			continue
+		}
+		if at.Before(promo.ValidFrom) || !at.Before(promo.ValidUntil) {
+			continue
+		}
+		if best == nil || promo.InterestRate < best.InterestRate {
+			best = promo
+		}
+	}
+	return best
+}
+
+func normalizeProductCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"main/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// MockProductRepository for testing
+type MockProductRepository struct {
+	products map[string]*model.Product
+	nextID   uint
+}
+
+func NewMockProductRepository() *MockProductRepository {
+	return &MockProductRepository{
+		products: make(map[string]*model.Product),
+		nextID:   1,
+	}
+}
+
+func (m *MockProductRepository) Create(product *model.Product) error {
+	product.ID = m.nextID
+	m.nextID++
+	m.products[product.Code] = product
+	return nil
+}
+
+func (m *MockProductRepository) GetByCode(code string) (*model.Product, error) {
+	if product, exists := m.products[code]; exists {
+		return product, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockProductRepository) GetAll() ([]model.Product, error) {
+	var products []model.Product
+	for _, product := range m.products {
+		products = append(products, *product)
+	}
+	return products, nil
+}
+
+func (m *MockProductRepository) Update(product *model.Product) error {
+	m.products[product.Code] = product
+	return nil
+}
+
+func (m *MockProductRepository) CreatePromo(promo *model.ProductPromo) error {
+	for _, product := range m.products {
+		if product.ID == promo.ProductID {
+			promo.ID = uint(len(product.Promos) + 1)
+			product.Promos = append(product.Promos, *promo)
+		}
+	}
+	return nil
+}
+
+// newTestCatalogue returns a catalogue with GADGET: 2% flat per month on every tenor,
+// 50.000 admin fee and OTR between 100.000 and 20.000.000
+func newTestCatalogue() ProductUsecase {
+	uc := NewProductUsecase(NewMockProductRepository())
+	uc.CreateProduct(&model.Product{
+		Code:          "GADGET",
+		Name:          "Gadget & Elektronik",
+		Category:      "ELECTRONICS",
+		AdminFeeType:  model.AdminFeeTypeFlat,
+		AdminFeeValue: 50000,
+		MinOTR:        100000,
+		MaxOTR:        20000000,
+		ValidFrom:     time.Now().AddDate(-1, 0, 0),
+		Rates: []model.ProductRate{
+			{Tenor: 1, InterestRate: 2},
+			{Tenor: 2, InterestRate: 2},
+			{Tenor: 3, InterestRate: 2},
+			{Tenor: 6, InterestRate: 2},
+		},
+	})
+	return uc
+}
+
+// Test: Pricing is derived from the product rate and admin fee
+func TestPrice_Product(t *testing.T) {
+	uc := newTestCatalogue()
+
+	pricing, err := uc.Price("gadget", 3, 3000000, 0, time.Now())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// 3.000.000 x 2% x 3 months
+	if pricing.InterestAmount != 180000 {
+		t.Errorf("Expected interest 180000, got %.2f", pricing.InterestAmount)
+	}
+	if pricing.AdminFee != 50000 || pricing.InstallmentAmount != 1060000 || pricing.TotalPayable != 3230000 {
+		t.Errorf("Expected admin fee 50000, installment 1060000, total 3230000, got %.2f, %.2f, %.2f",
+			pricing.AdminFee, pricing.InstallmentAmount, pricing.TotalPayable)
+	}
+}
+
+// Test: Running promo overrides the rate for its tenor only
+func TestPrice_Promo(t *testing.T) {
+	uc := newTestCatalogue()
+
+	err := uc.AddPromo("GADGET", &model.ProductPromo{
+		Code:          "CICIL0",
+		Tenor:         3,
+		InterestRate:  0,
+		WaiveAdminFee: true,
+		ValidFrom:     time.Now().AddDate(0, 0, -1),
+		ValidUntil:    time.Now().AddDate(0, 1, 0),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	pricing, _ := uc.Price("GADGET", 3, 3000000, 0, time.Now())
+	if pricing.PromoCode != "CICIL0" || pricing.InterestAmount != 0 || pricing.AdminFee != 0 {
+		t.Errorf("Expected 0%% promo without admin fee, got %s %.2f %.2f", pricing.PromoCode, pricing.InterestAmount, pricing.AdminFee)
+	}
+
+	pricing, _ = uc.Price("GADGET", 6, 3000000, 0, time.Now())
+	if pricing.PromoCode != "" || pricing.InterestAmount != 360000 {
+		t.Errorf("Expected regular pricing on tenor 6, got %s %.2f", pricing.PromoCode, pricing.InterestAmount)
+	}
+
+	pricing, _ = uc.Price("GADGET", 3, 3000000, 0, time.Now().AddDate(0, 2, 0))
+	if pricing.PromoCode != "" {
+		t.Errorf("Expected promo to have ended, got %s", pricing.PromoCode)
+	}
+}
+
+// Test: Unknown product, unoffered tenor and OTR outside the range are refused
+func TestPrice_Invalid(t *testing.T) {
+	uc := newTestCatalogue()
+	uc.CreateProduct(&model.Product{
+		Code:          "MOTOR",
+		Name:          "Motor",
+		AdminFeeType:  model.AdminFeeTypePercent,
+		AdminFeeValue: 1,
+		Rates:         []model.ProductRate{{Tenor: 6, InterestRate: 1.5}},
+	})
+
+	cases := []struct {
+		code  string
+		tenor int
+		otr   float64
+	}{
+		{"", 3, 3000000},
+		{"UNKNOWN", 3, 3000000},
+		{"MOTOR", 3, 3000000},
+		{"GADGET", 3, 50000},
+		{"GADGET", 3, 25000000},
+	}
+	for _, c := range cases {
+		if _, err := uc.Price(c.code, c.tenor, c.otr, 0, time.Now()); err == nil {
+			t.Errorf("Expected error for %s tenor %d OTR %.0f, got nil", c.code, c.tenor, c.otr)
+		}
+	}
+
+	pricing, err := uc.Price("MOTOR", 6, 20000000, 0, time.Now())
+	if err != nil || pricing.AdminFee != 200000 {
+		t.Errorf("Expected 1%% admin fee of 200000, got %v (%v)", pricing, err)
+	}
+}
+
+// Test: Products enforce their minimum down payment and price the financed principal
+func TestPrice_DownPayment(t *testing.T) {
+	uc := NewProductUsecase(NewMockProductRepository())
+	uc.CreateProduct(&model.Product{
+		Code:              "MOTOR",
+		Name:              "Motor",
+		AdminFeeType:      model.AdminFeeTypeFlat,
+		AdminFeeValue:     100000,
+		MinDownPaymentPct: 20,
+		ValidFrom:         time.Now().AddDate(-1, 0, 0),
+		Rates:             []model.ProductRate{{Tenor: 6, InterestRate: 1.5}},
+	})
+
+	if _, err := uc.Price("MOTOR", 6, 10000000, 1000000, time.Now()); err == nil {
+		t.Error("Expected error for a down payment below 20%, got nil")
+	}
+
+	pricing, err := uc.Price("MOTOR", 6, 10000000, 2000000, time.Now())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// 8.000.000 x 1.5% x 6 months
+	if pricing.FinancedAmount != 8000000 || pricing.InterestAmount != 720000 {
+		t.Errorf("Expected financed 8000000 and interest 720000, got %.2f and %.2f", pricing.FinancedAmount, pricing.InterestAmount)
+	}
+	if pricing.InstallmentAmount != 1453333.33 {
+		t.Errorf("Expected installment 1453333.33, got %.2f", pricing.InstallmentAmount)
+	}
+}
+
+// Test: Products may only offer limit tenors
+func TestCreateProduct_InvalidTenor(t *testing.T) {
+	uc := NewProductUsecase(NewMockProductRepository())
+
+	err := uc.CreateProduct(&model.Product{
+		Code:         "LONG",
+		Name:         "Long tenor",
+		AdminFeeType: model.AdminFeeTypeFlat,
+		Rates:        []model.ProductRate{{Tenor: 12, InterestRate: 1}},
+	})
+	if err == nil {
+		t.Error("Expected error for tenor 12, got nil")
+	}
+}
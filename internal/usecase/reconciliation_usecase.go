@@ -0,0 +1,495 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"main/internal/bankstatement"
+	"main/internal/model"
+	"main/internal/repository"
+)
+
+// ReconciliationPolicy holds how statement lines are matched to installments and callbacks
+type ReconciliationPolicy struct {
+	EarlyPaymentDays int // days before the due date a payment still counts for that installment
+	CallbackDateDays int // days between a callback and the statement value date for both to be the same money
+}
+
+// DefaultReconciliationPolicy matches payments up to 7 days before the due date and callbacks within a day
+func DefaultReconciliationPolicy() ReconciliationPolicy {
+	return ReconciliationPolicy{
+		EarlyPaymentDays: 7,
+		CallbackDateDays: 1,
+	}
+}
+
+// matchedByAuto marks lines reconciled by the importer rather than an operator
+const matchedByAuto = "AUTO"
+
+// Manual match actions
+const (
+	MatchActionPost   = "POST"   // post the line as a payment to a contract
+	MatchActionLink   = "LINK"   // the line is a payment callback already received
+	MatchActionIgnore = "IGNORE" // the line is not a repayment
+)
+
+// ManualMatchRequest is an operator's decision on a statement line the importer could not match
+type ManualMatchRequest struct {
+	Action        string `json:"action"`         // POST, LINK, IGNORE
+	TransactionID uint   `json:"transaction_id"` // POST: contract to post to, defaults to the contract of the VA
+	CallbackID    uint   `json:"callback_id"`    // LINK: payment callback of the same money
+	MatchedBy     string `json:"matched_by"`
+	Note          string `json:"note"`
+}
+
+// StatementReport is an imported statement with its credit lines grouped by match status
+type StatementReport struct {
+	Statement *model.BankStatement      `json:"statement"`
+	Matched   []model.BankStatementLine `json:"matched"`
+	Partial   []model.BankStatementLine `json:"partial"`
+	Unmatched []model.BankStatementLine `json:"unmatched"`
+	Ignored   []model.BankStatementLine `json:"ignored"`
+	Errors    []string                  `json:"errors,omitempty"` // malformed lines skipped at import
+}
+
+// ReconciliationUsecase defines all business logic operations for bank statement reconciliation
+type ReconciliationUsecase interface {
+	ImportStatement(data []byte, format, fileName, importedBy string) (*StatementReport, error)
+	GetStatements() ([]model.BankStatement, error)
+	GetReport(statementID uint) (*StatementReport, error)
+	MatchLine(lineID uint, req ManualMatchRequest) (*model.BankStatementLine, error)
+}
+
+// reconciliationUsecase is the implementation of ReconciliationUsecase
+type reconciliationUsecase struct {
+	statementRepo   repository.BankStatementRepository
+	lineRepo        repository.BankStatementLineRepository
+	transactionRepo repository.TransactionRepository
+	installmentRepo repository.InstallmentRepository
+	accountRepo     repository.VirtualAccountRepository
+	callbackRepo    repository.PaymentCallbackRepository
+	payments        PaymentPoster
+	policy          ReconciliationPolicy
+	mu              sync.Mutex
+}
+
+// NewReconciliationUsecase creates a new instance of ReconciliationUsecase
+func NewReconciliationUsecase(
+	statementRepo repository.BankStatementRepository,
+	lineRepo repository.BankStatementLineRepository,
+	transactionRepo repository.TransactionRepository,
+	installmentRepo repository.InstallmentRepository,
+	accountRepo repository.VirtualAccountRepository,
+	callbackRepo repository.PaymentCallbackRepository,
+	payments PaymentPoster,
+	policy ReconciliationPolicy,
+) ReconciliationUsecase {
+	return &reconciliationUsecase{
+		statementRepo:   statementRepo,
+		lineRepo:        lineRepo,
+		transactionRepo: transactionRepo,
+		installmentRepo: installmentRepo,
+		accountRepo:     accountRepo,
+		callbackRepo:    callbackRepo,
+		payments:        payments,
+		policy:          policy,
+	}
+}
+
+// ImportStatement stores the credits of a statement file and matches each to a payment callback of its VA
+// or a line of an earlier statement covering the same money, or posts it as a payment when it pays the
+// expected installment of the VA's contract. Debits are not repayments and are left out. A file is imported once.
+func (u *reconciliationUsecase) ImportStatement(data []byte, format, fileName, importedBy string) (*StatementReport, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if strings.TrimSpace(importedBy) == "" {
+		return nil, errors.New("imported_by wajib diisi")
+	}
+	parsed, lineErrors, err := bankstatement.Parse(data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := sha256.Sum256(data)
+	fileHash := hex.EncodeToString(digest[:])
+	if existing, err := u.statementRepo.GetByFileHash(fileHash); err == nil {
+		return nil, fmt.Errorf("file mutasi sudah diimpor sebagai mutasi #%d", existing.ID)
+	}
+
+	now := time.Now()
+	statement := &model.BankStatement{
+		Format:        parsed.Format,
+		AccountNumber: parsed.AccountNumber,
+		FileName:      fileName,
+		FileHash:      fileHash,
+		ImportedBy:    importedBy,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := u.statementRepo.Create(statement); err != nil {
+		return nil, err
+	}
+
+	claimed := make(map[uint]bool) // earlier lines already matched by a line of this statement
+	for _, entry := range parsed.Lines {
+		if !entry.Credit() {
+			continue
+		}
+		line := &model.BankStatementLine{
+			StatementID: statement.ID,
+			LineNumber:  entry.Number,
+			ValueDate:   dateOnly(entry.ValueDate),
+			Amount:      roundMoney(entry.Amount),
+			Reference:   entry.Reference,
+			Description: entry.Description,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		if err := u.autoMatch(line, entry.Candidates(), claimed); err != nil {
+			return nil, err
+		}
+		if err := u.lineRepo.Create(line); err != nil {
+			return nil, err
+		}
+	}
+
+	report, err := u.refreshStatement(statement)
+	if err != nil {
+		return nil, err
+	}
+	report.Errors = lineErrors
+
+	log.Printf("✓ Mutasi %s %s diimpor: %d cocok, %d cocok sebagian, %d tidak cocok\n",
+		statement.Format, statement.AccountNumber, statement.MatchedCount, statement.PartialCount, statement.UnmatchedCount)
+	return report, nil
+}
+
+// autoMatch decides the status of a new statement line. A line whose VA cannot be found is unmatched;
+// one whose VA is found but does not fit the expected installment is partially matched.
+func (u *reconciliationUsecase) autoMatch(line *model.BankStatementLine, candidates []string, claimed map[uint]bool) error {
+	var account *model.VirtualAccount
+	for _, candidate := range candidates {
+		if found, err := u.accountRepo.GetByNumber(candidate); err == nil {
+			account = found
+			break
+		}
+	}
+	if account == nil {
+		line.Status = model.StatementLineUnmatched
+		line.MatchNote = "nomor VA tidak ditemukan"
+		return nil
+	}
+	line.VANumber = account.VANumber
+	line.TransactionID = account.TransactionID
+
+	// Statement files may overlap; money an earlier statement reconciled is in the books
+	previous, err := u.findReconciledLine(line, claimed)
+	if err != nil {
+		return err
+	}
+	if previous != nil {
+		claimed[previous.ID] = true
+		line.TransactionID = previous.TransactionID
+		line.InstallmentID = previous.InstallmentID
+		line.CallbackID = previous.CallbackID
+		u.markMatched(line, previous.PaymentID, matchedByAuto,
+			fmt.Sprintf("sudah dicocokkan di mutasi #%d baris %d", previous.StatementID, previous.LineNumber))
+		return nil
+	}
+
+	// Money the bank already called back is in the books
+	callback, err := u.findCallback(account.VANumber, line.Amount, line.ValueDate)
+	if err != nil {
+		return err
+	}
+	if callback != nil {
+		u.linkCallback(line, callback, matchedByAuto)
+		return nil
+	}
+
+	installment, reason := u.expectedInstallment(account.TransactionID, line)
+	if installment == nil {
+		line.Status = model.StatementLinePartial
+		line.MatchNote = reason
+		return nil
+	}
+	line.InstallmentID = installment.ID
+
+	posting, err := u.payments.PostInstallmentPayment(account.TransactionID, InstallmentPaymentRequest{
+		Amount:    line.Amount,
+		Reference: statementReference(line),
+		PaidAt:    line.ValueDate,
+	})
+	if err != nil {
+		line.Status = model.StatementLinePartial
+		line.MatchNote = err.Error()
+		return nil
+	}
+	u.markMatched(line, posting.Payment.ID, matchedByAuto, fmt.Sprintf("angsuran ke-%d", installment.Sequence))
+	return nil
+}
+
+// expectedInstallment returns the oldest unpaid installment of the contract when the line pays it:
+// its outstanding amount with or without the late fee, not too long before its due date
+func (u *reconciliationUsecase) expectedInstallment(transactionID uint, line *model.BankStatementLine) (*model.Installment, string) {
+	transaction, err := u.transactionRepo.GetByID(transactionID)
+	if err != nil {
+		return nil, "transaksi tidak ditemukan"
+	}
+	if transaction.Status != model.TransactionStatusActive && transaction.Status != model.TransactionStatusDefaulted {
+		return nil, fmt.Sprintf("kontrak %s berstatus %s", transaction.ContractNumber, transaction.Status)
+	}
+
+	installments, err := u.installmentRepo.GetByTransactionID(transactionID)
+	if err != nil {
+		return nil, err.Error()
+	}
+	sort.SliceStable(installments, func(i, j int) bool {
+		return installments[i].DueDate.Before(installments[j].DueDate)
+	})
+	for i := range installments {
+		installment := &installments[i]
+		outstanding := roundMoney(installment.Outstanding())
+		if outstanding <= 0 {
+			continue
+		}
+		withLateFee := roundMoney(outstanding + installment.OutstandingLateFee())
+		if !sameAmount(line.Amount, outstanding) && !sameAmount(line.Amount, withLateFee) {
+			return nil, fmt.Sprintf("jumlah Rp %.2f tidak sesuai angsuran ke-%d (Rp %.2f, dengan denda Rp %.2f)",
+				line.Amount, installment.Sequence, outstanding, withLateFee)
+		}
+		if line.ValueDate.Before(dateOnly(installment.DueDate).AddDate(0, 0, -u.policy.EarlyPaymentDays)) {
+			return nil, fmt.Sprintf("tanggal %s terlalu awal untuk angsuran ke-%d jatuh tempo %s",
+				line.ValueDate.Format("2006-01-02"), installment.Sequence, installment.DueDate.Format("2006-01-02"))
+		}
+		return installment, ""
+	}
+	return nil, fmt.Sprintf("kontrak %s tidak memiliki angsuran terutang", transaction.ContractNumber)
+}
+
+// findReconciledLine returns the matched line of an earlier statement for the same VA, amount and value date,
+// with the same bank reference when both carry one, that no line of this statement has claimed yet
+func (u *reconciliationUsecase) findReconciledLine(line *model.BankStatementLine, claimed map[uint]bool) (*model.BankStatementLine, error) {
+	lines, err := u.lineRepo.GetByVANumber(line.VANumber)
+	if err != nil {
+		return nil, err
+	}
+	for i := range lines {
+		previous := &lines[i]
+		if previous.StatementID == line.StatementID || previous.Status != model.StatementLineMatched || claimed[previous.ID] {
+			continue
+		}
+		if !sameAmount(previous.Amount, line.Amount) || daysBetween(previous.ValueDate, line.ValueDate) != 0 {
+			continue
+		}
+		if previous.Reference != "" && line.Reference != "" && previous.Reference != line.Reference {
+			continue
+		}
+		return previous, nil
+	}
+	return nil, nil
+}
+
+// findCallback returns the callback of the VA with the amount of the line around its value date
+// that is not reconciled to another line yet
+func (u *reconciliationUsecase) findCallback(vaNumber string, amount float64, valueDate time.Time) (*model.PaymentCallback, error) {
+	callbacks, err := u.callbackRepo.GetByVANumber(vaNumber)
+	if err != nil {
+		return nil, err
+	}
+	for i := range callbacks {
+		callback := &callbacks[i]
+		days := daysBetween(dateOnly(callback.PaidAt.In(valueDate.Location())), valueDate)
+		if !sameAmount(callback.Amount, amount) || days < -u.policy.CallbackDateDays || days > u.policy.CallbackDateDays {
+			continue
+		}
+		if _, err := u.lineRepo.GetByCallbackID(callback.ID); err == nil {
+			continue
+		}
+		return callback, nil
+	}
+	return nil, nil
+}
+
+func (u *reconciliationUsecase) linkCallback(line *model.BankStatementLine, callback *model.PaymentCallback, matchedBy string) {
+	line.CallbackID = callback.ID
+	if callback.TransactionID != 0 {
+		line.TransactionID = callback.TransactionID
+	}
+	u.markMatched(line, callback.PaymentID, matchedBy, fmt.Sprintf("callback %s %s (%s)", callback.BankCode, callback.ExternalID, callback.Status))
+}
+
+func (u *reconciliationUsecase) markMatched(line *model.BankStatementLine, paymentID uint, matchedBy, note string) {
+	now := time.Now()
+	line.Status = model.StatementLineMatched
+	line.PaymentID = paymentID
+	line.MatchedBy = matchedBy
+	line.MatchedAt = &now
+	line.MatchNote = note
+}
+
+func (u *reconciliationUsecase) GetStatements() ([]model.BankStatement, error) {
+	return u.statementRepo.GetAll()
+}
+
+func (u *reconciliationUsecase) GetReport(statementID uint) (*StatementReport, error) {
+	statement, err := u.statementRepo.GetByID(statementID)
+	if err != nil {
+		return nil, errors.New("mutasi bank tidak ditemukan")
+	}
+	lines, err := u.lineRepo.GetByStatementID(statement.ID)
+	if err != nil {
+		return nil, err
+	}
+	return groupStatementLines(statement, lines), nil
+}
+
+// MatchLine lets an operator post, link or ignore a statement line the importer left partially matched or unmatched
+func (u *reconciliationUsecase) MatchLine(lineID uint, req ManualMatchRequest) (*model.BankStatementLine, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	req.Action = strings.ToUpper(strings.TrimSpace(req.Action))
+	if strings.TrimSpace(req.MatchedBy) == "" {
+		return nil, errors.New("matched_by wajib diisi")
+	}
+
+	line, err := u.lineRepo.GetByID(lineID)
+	if err != nil {
+		return nil, errors.New("baris mutasi tidak ditemukan")
+	}
+	if line.Status != model.StatementLinePartial && line.Status != model.StatementLineUnmatched {
+		return nil, fmt.Errorf("baris mutasi berstatus %s tidak dapat dicocokkan ulang", line.Status)
+	}
+
+	switch req.Action {
+	case MatchActionPost:
+		transactionID := req.TransactionID
+		if transactionID == 0 {
+			transactionID = line.TransactionID
+		}
+		if transactionID == 0 {
+			return nil, errors.New("transaction_id wajib diisi untuk baris tanpa VA")
+		}
+		posting, err := u.payments.PostInstallmentPayment(transactionID, InstallmentPaymentRequest{
+			Amount:    line.Amount,
+			Reference: statementReference(line),
+			PaidAt:    line.ValueDate,
+		})
+		if err != nil {
+			return nil, err
+		}
+		line.TransactionID = transactionID
+		u.markMatched(line, posting.Payment.ID, req.MatchedBy, req.Note)
+	case MatchActionLink:
+		callback, err := u.callbackRepo.GetByID(req.CallbackID)
+		if err != nil {
+			return nil, errors.New("callback pembayaran tidak ditemukan")
+		}
+		if !sameAmount(callback.Amount, line.Amount) {
+			return nil, fmt.Errorf("jumlah callback Rp %.2f tidak sama dengan baris mutasi Rp %.2f", callback.Amount, line.Amount)
+		}
+		if linked, err := u.lineRepo.GetByCallbackID(callback.ID); err == nil {
+			return nil, fmt.Errorf("callback sudah dicocokkan dengan baris mutasi #%d", linked.ID)
+		}
+		u.linkCallback(line, callback, req.MatchedBy)
+		if req.Note != "" {
+			line.MatchNote = req.Note
+		}
+	case MatchActionIgnore:
+		if strings.TrimSpace(req.Note) == "" {
+			return nil, errors.New("catatan wajib diisi untuk baris yang diabaikan")
+		}
+		now := time.Now()
+		line.Status = model.StatementLineIgnored
+		line.MatchedBy = req.MatchedBy
+		line.MatchedAt = &now
+		line.MatchNote = req.Note
+	default:
+		return nil, errors.New("aksi pencocokan harus POST, LINK atau IGNORE")
+	}
+
+	line.UpdatedAt = time.Now()
+	if err := u.lineRepo.Update(line); err != nil {
+		return nil, err
+	}
+	statement, err := u.statementRepo.GetByID(line.StatementID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.refreshStatement(statement); err != nil {
+		return nil, err
+	}
+
+	log.Printf("✓ Baris mutasi #%d %s oleh %s\n", line.ID, line.Status, req.MatchedBy)
+	return line, nil
+}
+
+// refreshStatement recounts the lines of the statement by status and stores the totals
+func (u *reconciliationUsecase) refreshStatement(statement *model.BankStatement) (*StatementReport, error) {
+	lines, err := u.lineRepo.GetByStatementID(statement.ID)
+	if err != nil {
+		return nil, err
+	}
+	report := groupStatementLines(statement, lines)
+
+	var total, matched float64
+	for _, line := range lines {
+		total += line.Amount
+	}
+	for _, line := range report.Matched {
+		matched += line.Amount
+	}
+	statement.CreditLines = len(lines)
+	statement.TotalCredit = roundMoney(total)
+	statement.MatchedCount = len(report.Matched)
+	statement.MatchedAmount = roundMoney(matched)
+	statement.PartialCount = len(report.Partial)
+	statement.UnmatchedCount = len(report.Unmatched)
+	statement.UpdatedAt = time.Now()
+	if err := u.statementRepo.Update(statement); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func groupStatementLines(statement *model.BankStatement, lines []model.BankStatementLine) *StatementReport {
+	report := &StatementReport{
+		Statement: statement,
+		Matched:   []model.BankStatementLine{},
+		Partial:   []model.BankStatementLine{},
+		Unmatched: []model.BankStatementLine{},
+		Ignored:   []model.BankStatementLine{},
+	}
+	for _, line := range lines {
+		switch line.Status {
+		case model.StatementLineMatched:
+			report.Matched = append(report.Matched, line)
+		case model.StatementLinePartial:
+			report.Partial = append(report.Partial, line)
+		case model.StatementLineIgnored:
+			report.Ignored = append(report.Ignored, line)
+		default:
+			report.Unmatched = append(report.Unmatched, line)
+		}
+	}
+	return report
+}
+
+// statementReference is the payment reference of a statement line, unique per statement and line
+func statementReference(line *model.BankStatementLine) string {
+	return fmt.Sprintf("STMT-%d-%d", line.StatementID, line.LineNumber)
+}
+
+func sameAmount(a, b float64) bool {
+	return math.Abs(a-b) < 0.01
+}
@@ -0,0 +1,237 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"main/internal/ledger"
+	"main/internal/model"
+)
+
+// MockBankStatementRepository for testing
+type MockBankStatementRepository struct {
+	statements []*model.BankStatement
+}
+
+func (m *MockBankStatementRepository) Create(statement *model.BankStatement) error {
+	statement.ID = uint(len(m.statements) + 1)
+	m.statements = append(m.statements, statement)
+	return nil
+}
+
+func (m *MockBankStatementRepository) GetByID(id uint) (*model.BankStatement, error) {
+	for _, statement := range m.statements {
+		if statement.ID == id {
+			return statement, nil
+		}
+	}
+	return nil, errors.New("bank statement not found")
+}
+
+func (m *MockBankStatementRepository) GetByFileHash(fileHash string) (*model.BankStatement, error) {
+	for _, statement := range m.statements {
+		if statement.FileHash == fileHash {
+			return statement, nil
+		}
+	}
+	return nil, errors.New("bank statement not found")
+}
+
+func (m *MockBankStatementRepository) GetAll() ([]model.BankStatement, error) {
+	var statements []model.BankStatement
+	for _, statement := range m.statements {
+		statements = append(statements, *statement)
+	}
+	return statements, nil
+}
+
+func (m *MockBankStatementRepository) Update(statement *model.BankStatement) error {
+	return nil
+}
+
+// MockBankStatementLineRepository for testing
+type MockBankStatementLineRepository struct {
+	lines []*model.BankStatementLine
+}
+
+func (m *MockBankStatementLineRepository) Create(line *model.BankStatementLine) error {
+	line.ID = uint(len(m.lines) + 1)
+	m.lines = append(m.lines, line)
+	return nil
+}
+
+func (m *MockBankStatementLineRepository) GetByID(id uint) (*model.BankStatementLine, error) {
+	for _, line := range m.lines {
+		if line.ID == id {
+			copied := *line
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("statement line not found")
+}
+
+func (m *MockBankStatementLineRepository) GetByStatementID(statementID uint) ([]model.BankStatementLine, error) {
+	var lines []model.BankStatementLine
+	for _, line := range m.lines {
+		if line.StatementID == statementID {
+			lines = append(lines, *line)
+		}
+	}
+	return lines, nil
+}
+
+func (m *MockBankStatementLineRepository) GetByCallbackID(callbackID uint) (*model.BankStatementLine, error) {
+	for _, line := range m.lines {
+		if line.CallbackID == callbackID {
+			return line, nil
+		}
+	}
+	return nil, errors.New("statement line not found")
+}
+
+func (m *MockBankStatementLineRepository) GetByVANumber(vaNumber string) ([]model.BankStatementLine, error) {
+	var lines []model.BankStatementLine
+	for _, line := range m.lines {
+		if line.VANumber == vaNumber {
+			lines = append(lines, *line)
+		}
+	}
+	return lines, nil
+}
+
+func (m *MockBankStatementLineRepository) Update(line *model.BankStatementLine) error {
+	for i, existing := range m.lines {
+		if existing.ID == line.ID {
+			copied := *line
+			m.lines[i] = &copied
+			return nil
+		}
+	}
+	return errors.New("statement line not found")
+}
+
+// newTestReconciliation reconciles statements against the virtual accounts and callbacks of the fixture
+func newTestReconciliation(f *virtualAccountFixture) ReconciliationUsecase {
+	return NewReconciliationUsecase(&MockBankStatementRepository{}, f.lineRepo, f.transactionRepo, f.installmentRepo,
+		f.accountRepo, f.callbackRepo, f.paymentFixture.uc, DefaultReconciliationPolicy())
+}
+
+// testStatement has a called-back VA payment, an installment paid only on the statement, a VA transfer of the
+// wrong amount, a transfer without a VA and a debit
+const testStatement = "date,amount,type,reference,description,va_number\n" +
+	"2026-02-01,1100000,C,BNK-1,SETORAN VA,3935800000000015\n" +
+	"2026-02-27,1100000,C,BNK-2,SETORAN 3935800000000015 BUDI,\n" +
+	"2026-02-27,500000,C,BNK-3,SETORAN 3935800000000015 BUDI,\n" +
+	"2026-02-27,750000,C,BNK-4,TRANSFER DARI ANDI,\n" +
+	"2026-02-27,250000,D,BNK-5,BIAYA ADMIN BANK,\n"
+
+// Test: Statement credits are matched to callbacks and expected installments, and the rest reported
+func TestImportStatement(t *testing.T) {
+	f := newVirtualAccountFixture()
+	account, _ := f.uc.IssueVirtualAccount(1, "BCA")
+	if _, err := f.sendCallback("TRX-1", account.VANumber, 1100000); err != nil {
+		t.Fatalf("Expected callback posted, got %v", err)
+	}
+
+	uc := newTestReconciliation(f)
+
+	report, err := uc.ImportStatement([]byte(testStatement), "", "bca-20260227.csv", "finance-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(report.Matched) != 2 || len(report.Partial) != 1 || len(report.Unmatched) != 1 {
+		t.Fatalf("Expected 2 matched, 1 partial and 1 unmatched, got %d, %d and %d",
+			len(report.Matched), len(report.Partial), len(report.Unmatched))
+	}
+	if report.Matched[0].CallbackID == 0 || report.Matched[1].PaymentID == 0 || report.Matched[1].InstallmentID == 0 {
+		t.Errorf("Expected the callback linked and installment 2 posted, got %+v", report.Matched)
+	}
+	if report.Statement.TotalCredit != 3450000 || report.Statement.MatchedAmount != 2200000 {
+		t.Errorf("Expected credits 3450000 with 2200000 matched, got %.2f and %.2f",
+			report.Statement.TotalCredit, report.Statement.MatchedAmount)
+	}
+	if receivable := accountBalance(t, f.ledger, ledger.Receivable); receivable != 1100000 {
+		t.Errorf("Expected receivable 1100000 after both installments, got %.2f", receivable)
+	}
+
+	if _, err := uc.ImportStatement([]byte(testStatement), "", "again.csv", "finance-1"); err == nil {
+		t.Error("Expected error importing the same file twice, got nil")
+	}
+
+	// The operator posts the partial transfer and explains the other
+	partial, unmatched := report.Partial[0], report.Unmatched[0]
+	if _, err := uc.MatchLine(partial.ID, ManualMatchRequest{Action: MatchActionPost, MatchedBy: "finance-2"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := uc.MatchLine(unmatched.ID, ManualMatchRequest{Action: MatchActionIgnore, MatchedBy: "finance-2"}); err == nil {
+		t.Error("Expected error ignoring a line without a note, got nil")
+	}
+	if _, err := uc.MatchLine(unmatched.ID, ManualMatchRequest{Action: MatchActionIgnore, MatchedBy: "finance-2", Note: "salah transfer, dikembalikan"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	report, _ = uc.GetReport(report.Statement.ID)
+	if report.Statement.MatchedCount != 3 || report.Statement.PartialCount != 0 || report.Statement.UnmatchedCount != 0 || len(report.Ignored) != 1 {
+		t.Errorf("Expected 3 matched and 1 ignored, got %+v", report.Statement)
+	}
+	if receivable := accountBalance(t, f.ledger, ledger.Receivable); receivable != 600000 {
+		t.Errorf("Expected receivable 600000, got %.2f", receivable)
+	}
+}
+
+// vaStatement carries a VA transfer the bank has not called back yet
+const vaStatement = "date,amount,type,reference,description,va_number\n" +
+	"2026-02-01,1100000,C,BNK-1,SETORAN VA,3935800000000015\n"
+
+// Test: A callback for money a statement already posted is linked to that payment, not posted again
+func TestHandleCallback_PostedByStatement(t *testing.T) {
+	f := newVirtualAccountFixture()
+	account, _ := f.uc.IssueVirtualAccount(1, "BCA")
+	uc := newTestReconciliation(f)
+
+	report, err := uc.ImportStatement([]byte(vaStatement), "", "bca-20260201.csv", "finance-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(report.Matched) != 1 || report.Matched[0].PaymentID == 0 {
+		t.Fatalf("Expected the transfer posted from the statement, got %+v", report)
+	}
+
+	callback, err := f.sendCallback("TRX-1", account.VANumber, 1100000)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if callback.Status != model.PaymentCallbackPosted || callback.PaymentID != report.Matched[0].PaymentID {
+		t.Errorf("Expected the callback linked to payment %d, got %+v", report.Matched[0].PaymentID, callback)
+	}
+	if line, err := f.lineRepo.GetByCallbackID(callback.ID); err != nil || line.ID != report.Matched[0].ID {
+		t.Errorf("Expected the statement line linked to the callback, got %v", err)
+	}
+	if receivable := accountBalance(t, f.ledger, ledger.Receivable); receivable != 2200000 {
+		t.Errorf("Expected one installment off the receivable, got %.2f", receivable)
+	}
+}
+
+// Test: A transfer on two overlapping statement files is posted once
+func TestImportStatement_Overlapping(t *testing.T) {
+	f := newVirtualAccountFixture()
+	f.uc.IssueVirtualAccount(1, "BCA")
+	uc := newTestReconciliation(f)
+
+	first, err := uc.ImportStatement([]byte(vaStatement), "", "bca-20260201.csv", "finance-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	overlapping := vaStatement + "2026-02-02,250000,D,BNK-6,BIAYA ADMIN BANK,\n"
+	second, err := uc.ImportStatement([]byte(overlapping), "", "bca-20260201-02.csv", "finance-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(second.Matched) != 1 || second.Matched[0].PaymentID != first.Matched[0].PaymentID {
+		t.Errorf("Expected the line matched to payment %d, got %+v", first.Matched[0].PaymentID, second.Matched)
+	}
+	if receivable := accountBalance(t, f.ledger, ledger.Receivable); receivable != 2200000 {
+		t.Errorf("Expected one installment off the receivable, got %.2f", receivable)
+	}
+}
@@ -0,0 +1,421 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"main/internal/ledger"
+	"main/internal/model"
+	"main/internal/repository"
+)
+
+// RestructuringPolicy holds the limits of loan restructuring
+type RestructuringPolicy struct {
+	MaxHolidayMonths int // longest payment holiday before the new schedule starts
+	MaxRestructures  int // approved restructurings allowed per contract
+}
+
+// DefaultRestructuringPolicy allows a payment holiday of up to 3 months and 2 restructurings per contract
+func DefaultRestructuringPolicy() RestructuringPolicy {
+	return RestructuringPolicy{
+		MaxHolidayMonths: 3,
+		MaxRestructures:  2,
+	}
+}
+
+// RestructureRequest describes the new terms proposed for a distressed contract
+type RestructureRequest struct {
+	NewTenor      int     `json:"new_tenor"`
+	InterestRate  float64 `json:"interest_rate"` // percent per month, flat; 0 makes the new schedule interest-free
+	HolidayMonths int     `json:"holiday_months"`
+	Reason        string  `json:"reason"`
+	RequestedBy   string  `json:"requested_by"`
+}
+
+// RestructuringUsecase defines all business logic operations for loan restructuring
+type RestructuringUsecase interface {
+	RequestRestructuring(transactionID uint, req RestructureRequest) (*model.Restructuring, error)
+	ReviewRestructuring(id uint, approve bool, reviewer, note string) (*model.Restructuring, error)
+	GetRestructurings(transactionID uint) ([]model.Restructuring, error)
+	GetPendingRestructurings() ([]model.Restructuring, error)
+}
+
+// restructuringUsecase is the implementation of RestructuringUsecase
+type restructuringUsecase struct {
+	transactionRepo   repository.TransactionRepository
+	installmentRepo   repository.InstallmentRepository
+	restructuringRepo repository.RestructuringRepository
+	transactor        repository.Transactor
+	policy            RestructuringPolicy
+	mu                sync.Mutex
+}
+
+// NewRestructuringUsecase creates a new instance of RestructuringUsecase
+func NewRestructuringUsecase(
+	transactionRepo repository.TransactionRepository,
+	installmentRepo repository.InstallmentRepository,
+	restructuringRepo repository.RestructuringRepository,
+	transactor repository.Transactor,
+	policy RestructuringPolicy,
+) RestructuringUsecase {
+	return &restructuringUsecase{
+		transactionRepo:   transactionRepo,
+		installmentRepo:   installmentRepo,
+		restructuringRepo: restructuringRepo,
+		transactor:        transactor,
+		policy:            policy,
+	}
+}
+
+// restructuredTerms is the outstanding balance of a contract split the way a restructuring treats it
+type restructuredTerms struct {
+	principal      float64 // principal not yet repaid
+	capitalised    float64 // interest already due, added to the new principal
+	futureInterest float64 // interest not yet due, replaced by the interest of the new schedule
+	lateFees       float64 // waived
+}
+
+// RequestRestructuring proposes new terms for an ACTIVE or DEFAULTED contract. The amounts are a preview
+// as of today; nothing changes on the contract until another officer approves the request.
+func (u *restructuringUsecase) RequestRestructuring(transactionID uint, req RestructureRequest) (*model.Restructuring, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	req.RequestedBy = strings.TrimSpace(req.RequestedBy)
+	if req.RequestedBy == "" {
+		return nil, errors.New("nama petugas pengaju tidak boleh kosong")
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, errors.New("alasan restrukturisasi wajib diisi")
+	}
+	if !limitTenors[req.NewTenor] {
+		return nil, errors.New("tenor baru harus 1, 2, 3, atau 6 bulan")
+	}
+	if req.InterestRate < 0 {
+		return nil, errors.New("bunga restrukturisasi tidak boleh negatif")
+	}
+	if req.HolidayMonths < 0 || req.HolidayMonths > u.policy.MaxHolidayMonths {
+		return nil, fmt.Errorf("masa tenggang maksimal %d bulan", u.policy.MaxHolidayMonths)
+	}
+
+	transaction, err := u.getRestructurableTransaction(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	requests, err := u.restructuringRepo.GetByTransactionID(transaction.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range requests {
+		if existing.Status == model.RestructuringStatusPending {
+			return nil, errors.New("kontrak masih memiliki pengajuan restrukturisasi yang menunggu persetujuan")
+		}
+	}
+
+	now := time.Now()
+	restructuring := &model.Restructuring{
+		TransactionID:   transaction.ID,
+		Reason:          strings.TrimSpace(req.Reason),
+		OldTenor:        transaction.Tenor,
+		NewTenor:        req.NewTenor,
+		OldInterestRate: transaction.InterestRate,
+		NewInterestRate: req.InterestRate,
+		HolidayMonths:   req.HolidayMonths,
+		Status:          model.RestructuringStatusPending,
+		RequestedBy:     req.RequestedBy,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	installments, err := u.installmentRepo.GetByTransactionID(transaction.ID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := applyRestructuredTerms(restructuring, installments, now); err != nil {
+		return nil, err
+	}
+
+	if err := u.restructuringRepo.Create(restructuring); err != nil {
+		return nil, err
+	}
+
+	log.Printf("✓ Restrukturisasi kontrak %s diajukan oleh %s: tenor %d → %d bulan\n",
+		transaction.ContractNumber, restructuring.RequestedBy, restructuring.OldTenor, restructuring.NewTenor)
+	return restructuring, nil
+}
+
+// ReviewRestructuring approves or rejects a pending request. The reviewer must not be the officer who
+// requested it. Approval replaces the unpaid installments with a new schedule from today, moves the
+// limit exposure to the new tenor and posts the change of receivable and interest to the ledger.
+func (u *restructuringUsecase) ReviewRestructuring(id uint, approve bool, reviewer, note string) (*model.Restructuring, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	reviewer = strings.TrimSpace(reviewer)
+	if reviewer == "" {
+		return nil, errors.New("nama petugas penyetuju tidak boleh kosong")
+	}
+
+	restructuring, err := u.restructuringRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("pengajuan restrukturisasi tidak ditemukan")
+	}
+	if restructuring.Status != model.RestructuringStatusPending {
+		return nil, errors.New("pengajuan restrukturisasi tidak sedang menunggu persetujuan")
+	}
+	if strings.EqualFold(reviewer, restructuring.RequestedBy) {
+		return nil, errors.New("restrukturisasi harus disetujui oleh petugas selain pengaju")
+	}
+
+	now := time.Now()
+	restructuring.ReviewedBy = reviewer
+	restructuring.ReviewNote = note
+	restructuring.ReviewedAt = &now
+	restructuring.UpdatedAt = now
+
+	if !approve {
+		restructuring.Status = model.RestructuringStatusRejected
+		if err := u.restructuringRepo.Update(restructuring); err != nil {
+			return nil, err
+		}
+		log.Printf("✓ Restrukturisasi #%d ditolak oleh %s\n", restructuring.ID, reviewer)
+		return restructuring, nil
+	}
+
+	transaction, err := u.getRestructurableTransaction(restructuring.TransactionID)
+	if err != nil {
+		return nil, err
+	}
+	err = u.transactor.Transaction(func(tx *repository.Repositories) error {
+		return u.approve(tx, transaction, restructuring, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("✓ Restrukturisasi kontrak %s disetujui oleh %s: pokok baru Rp %.2f, %d x Rp %.2f\n",
+		transaction.ContractNumber, reviewer, restructuring.NewPrincipal, restructuring.NewTenor, restructuring.NewInstallmentAmount)
+	return restructuring, nil
+}
+
+// approve carries out an approved restructuring effective today inside one database transaction
+func (u *restructuringUsecase) approve(tx *repository.Repositories, transaction *model.Transaction, restructuring *model.Restructuring, now time.Time) error {
+	installments, err := tx.Installments.GetByTransactionID(transaction.ID)
+	if err != nil {
+		return err
+	}
+	terms, err := applyRestructuredTerms(restructuring, installments, now)
+	if err != nil {
+		return err
+	}
+
+	// Check the new tenor bucket before anything changes
+	var newLimit *model.ConsumerLimit
+	if restructuring.NewTenor != transaction.Tenor {
+		if newLimit, err = tx.Limits.GetByConsumerAndTenorForUpdate(transaction.ConsumerID, restructuring.NewTenor); err != nil {
+			return errors.New("limit tidak ditemukan untuk tenor baru")
+		}
+	}
+
+	effective := dateOnly(now)
+	restructuring.Status = model.RestructuringStatusApproved
+	restructuring.EffectiveDate = &effective
+	if err := tx.Restructurings.Update(restructuring); err != nil {
+		return err
+	}
+
+	// The old schedule stays for history; what was still owed moves to the new schedule
+	charges, err := tx.LateFeeCharges.GetByTransactionID(transaction.ID)
+	if err != nil {
+		return err
+	}
+	lastSequence := 0
+	for i := range installments {
+		installment := &installments[i]
+		lastSequence = max(lastSequence, installment.Sequence)
+		if installment.Outstanding() <= 0 {
+			continue
+		}
+		waivedLateFee := installment.LateFee != installment.LateFeePaid
+		installment.Status = model.InstallmentStatusRescheduled
+		installment.LateFee = installment.LateFeePaid
+		installment.DaysPastDue = 0
+		installment.UpdatedAt = now
+		if err := tx.Installments.Update(installment); err != nil {
+			return err
+		}
+		if waivedLateFee {
+			if err := recordLateFeeCharge(tx.LateFeeCharges, charges, installment, effective); err != nil {
+				return err
+			}
+		}
+	}
+
+	schedule := buildInstallmentSchedule(transaction.ID, restructuring.NewPrincipal, restructuring.NewInterestAmount,
+		restructuring.NewTenor, addMonths(effective, restructuring.HolidayMonths))
+	for i := range schedule {
+		schedule[i].Sequence += lastSequence
+		schedule[i].RestructuringID = restructuring.ID
+		schedule[i].CreatedAt = now
+		schedule[i].UpdatedAt = now
+	}
+	if err := tx.Installments.CreateBatch(schedule); err != nil {
+		return errors.New("gagal membuat jadwal angsuran restrukturisasi")
+	}
+
+	// Later releases give back the financed amount on the contract tenor, so the same amount moves buckets.
+	// Restructuring is relief for a contract already booked, so the new bucket may go over its limit.
+	if newLimit != nil {
+		if err := releaseLimit(tx.Limits, transaction.ConsumerID, transaction.Tenor, transaction.FinancedAmount()); err != nil {
+			return err
+		}
+		newLimit.UsedAmount += transaction.FinancedAmount()
+		newLimit.UpdatedAt = now
+		if err := tx.Limits.UpdateUsage(newLimit); err != nil {
+			return errors.New("gagal update limit")
+		}
+		if newLimit.Available() < 0 {
+			log.Printf("⚠ Limit tenor %d konsumen %d terlampaui karena restrukturisasi\n", newLimit.Tenor, newLimit.ConsumerID)
+		}
+	}
+
+	books := ledgerIn(tx)
+	deferred, err := books.ContractBalance(transaction.ID, ledger.UnearnedInterest)
+	if err != nil {
+		return err
+	}
+	if err := books.Post(restructuringEntry(transaction, restructuring, terms.futureInterest, deferred)); err != nil {
+		return err
+	}
+	if err := recordAccrualTrueUp(tx.Accruals, transaction.ID, effective, roundMoney(deferred-terms.futureInterest)); err != nil {
+		return err
+	}
+
+	var lateFees float64
+	for _, installment := range installments {
+		lateFees += installment.LateFee
+	}
+	transaction.Tenor = restructuring.NewTenor
+	transaction.InterestRate = restructuring.NewInterestRate
+	transaction.InterestAmount = restructuring.NewInterestAmount
+	transaction.InstallmentAmount = restructuring.NewInstallmentAmount
+	transaction.Status = model.TransactionStatusActive
+	transaction.DaysPastDue = 0
+	transaction.LateFeeAmount = roundMoney(lateFees)
+	transaction.RestructureCount++
+	transaction.RestructuredAt = &effective
+	transaction.UpdatedAt = now
+	return tx.Transactions.Update(transaction)
+}
+
+// recordAccrualTrueUp books the income adjustment of the restructuring entry as the accrual of the
+// effective date, so the accrual rows keep adding up to the interest recognised for the contract
+func recordAccrualTrueUp(accrualRepo repository.InterestAccrualRepository, transactionID uint, effective time.Time, amount float64) error {
+	if amount == 0 {
+		return nil
+	}
+	accruals, err := accrualRepo.GetByTransactionID(transactionID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var cumulative float64
+	var existing *model.InterestAccrual
+	for i := range accruals {
+		accrual := &accruals[i]
+		if accrual.ReversedAt != nil || accrual.AccrualDate.After(effective) {
+			continue
+		}
+		cumulative += accrual.Amount
+		if dateOnly(accrual.AccrualDate).Equal(effective) {
+			existing = accrual
+		}
+	}
+	cumulative = roundMoney(cumulative + amount)
+
+	if existing != nil {
+		existing.Amount = roundMoney(existing.Amount + amount)
+		existing.CumulativeAmount = cumulative
+		existing.Revision++
+		existing.UpdatedAt = now
+		return accrualRepo.Update(existing)
+	}
+	return accrualRepo.Create(&model.InterestAccrual{
+		TransactionID:    transactionID,
+		AccrualDate:      effective,
+		Amount:           amount,
+		CumulativeAmount: cumulative,
+		Revision:         1,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	})
+}
+
+func (u *restructuringUsecase) GetRestructurings(transactionID uint) ([]model.Restructuring, error) {
+	return u.restructuringRepo.GetByTransactionID(transactionID)
+}
+
+func (u *restructuringUsecase) GetPendingRestructurings() ([]model.Restructuring, error) {
+	return u.restructuringRepo.GetByStatus(model.RestructuringStatusPending)
+}
+
+// getRestructurableTransaction loads a running contract that has restructurings left
+func (u *restructuringUsecase) getRestructurableTransaction(transactionID uint) (*model.Transaction, error) {
+	transaction, err := u.transactionRepo.GetByID(transactionID)
+	if err != nil {
+		return nil, errors.New("transaksi tidak ditemukan")
+	}
+	if transaction.Status != model.TransactionStatusActive && transaction.Status != model.TransactionStatusDefaulted {
+		return nil, fmt.Errorf("transaksi berstatus %s tidak dapat direstrukturisasi", transaction.Status)
+	}
+	if transaction.RestructureCount >= u.policy.MaxRestructures {
+		return nil, fmt.Errorf("kontrak sudah direstrukturisasi %d kali", transaction.RestructureCount)
+	}
+	return transaction, nil
+}
+
+// applyRestructuredTerms computes the new principal, interest and installment of the restructuring
+// from the installments still owed on the date. Overdue interest is capitalised and late fees are waived;
+// the new interest is the flat monthly rate on the new principal over the new tenor.
+func applyRestructuredTerms(restructuring *model.Restructuring, installments []model.Installment, date time.Time) (*restructuredTerms, error) {
+	date = dateOnly(date)
+	terms := &restructuredTerms{}
+	for _, installment := range installments {
+		outstanding := installment.Outstanding()
+		if outstanding <= 0 || installment.Amount <= 0 {
+			continue
+		}
+
+		// Partial payments reduce principal and interest proportionally
+		ratio := outstanding / installment.Amount
+		interest := installment.InterestAmount * ratio
+		terms.principal += installment.PrincipalAmount * ratio
+		terms.lateFees += installment.OutstandingLateFee()
+		if date.Before(dateOnly(installment.DueDate)) {
+			terms.futureInterest += interest
+		} else {
+			terms.capitalised += interest
+		}
+	}
+	terms.principal = roundMoney(terms.principal)
+	terms.capitalised = roundMoney(terms.capitalised)
+	terms.futureInterest = roundMoney(terms.futureInterest)
+	terms.lateFees = roundMoney(terms.lateFees)
+	if terms.principal <= 0 {
+		return nil, errors.New("kontrak tidak memiliki sisa pokok untuk direstrukturisasi")
+	}
+
+	restructuring.OutstandingPrincipal = terms.principal
+	restructuring.CapitalisedInterest = terms.capitalised
+	restructuring.WaivedLateFees = terms.lateFees
+	restructuring.NewPrincipal = roundMoney(terms.principal + terms.capitalised)
+	restructuring.NewInterestAmount = roundMoney(restructuring.NewPrincipal * restructuring.NewInterestRate / 100 * float64(restructuring.NewTenor))
+	schedule := buildInstallmentSchedule(restructuring.TransactionID, restructuring.NewPrincipal, restructuring.NewInterestAmount, restructuring.NewTenor, date)
+	restructuring.NewInstallmentAmount = schedule[0].Amount
+	return terms, nil
+}
@@ -0,0 +1,244 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"main/internal/ledger"
+	"main/internal/model"
+	"main/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// MockRestructuringRepository for testing
+type MockRestructuringRepository struct {
+	restructurings []*model.Restructuring
+}
+
+func (m *MockRestructuringRepository) Create(restructuring *model.Restructuring) error {
+	restructuring.ID = uint(len(m.restructurings) + 1)
+	m.restructurings = append(m.restructurings, restructuring)
+	return nil
+}
+
+func (m *MockRestructuringRepository) GetByID(id uint) (*model.Restructuring, error) {
+	for _, restructuring := range m.restructurings {
+		if restructuring.ID == id {
+			return restructuring, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockRestructuringRepository) GetByTransactionID(transactionID uint) ([]model.Restructuring, error) {
+	var restructurings []model.Restructuring
+	for _, restructuring := range m.restructurings {
+		if restructuring.TransactionID == transactionID {
+			restructurings = append(restructurings, *restructuring)
+		}
+	}
+	return restructurings, nil
+}
+
+func (m *MockRestructuringRepository) GetByStatus(status string) ([]model.Restructuring, error) {
+	var restructurings []model.Restructuring
+	for _, restructuring := range m.restructurings {
+		if restructuring.Status == status {
+			restructurings = append(restructurings, *restructuring)
+		}
+	}
+	return restructurings, nil
+}
+
+func (m *MockRestructuringRepository) Update(restructuring *model.Restructuring) error {
+	return nil
+}
+
+type restructuringFixture struct {
+	uc              RestructuringUsecase
+	accrualUC       AccrualUsecase
+	transactionRepo *MockTransactionRepository
+	installmentRepo *MockInstallmentRepository
+	limitRepo       *MockConsumerLimitRepository
+	accrualRepo     *MockInterestAccrualRepository
+	ledger          LedgerUsecase
+}
+
+// newRestructuringFixture books the test contract 40 days ago, so installment 1 is overdue,
+// with interest accrued up to yesterday and tenor 3 and tenor 6 limits
+func newRestructuringFixture() *restructuringFixture {
+	f := &restructuringFixture{
+		transactionRepo: NewMockTransactionRepository(),
+		installmentRepo: &MockInstallmentRepository{},
+		limitRepo:       NewMockConsumerLimitRepository(),
+		accrualRepo:     &MockInterestAccrualRepository{},
+	}
+	start := time.Now().AddDate(0, 0, -40)
+	transaction := bookTestContract(f.transactionRepo, f.installmentRepo, start)
+	f.limitRepo.Create(&model.ConsumerLimit{ConsumerID: 1, Tenor: 3, LimitAmount: 10000000, UsedAmount: 3000000})
+	f.limitRepo.Create(&model.ConsumerLimit{ConsumerID: 1, Tenor: 6, LimitAmount: 10000000})
+
+	var journalRepo *MockJournalRepository
+	f.ledger, journalRepo = newTestLedger()
+	f.ledger.Post(activationEntry(transaction, start))
+	restructuringRepo := &MockRestructuringRepository{}
+	transactor := newTestTransactor(repository.Repositories{
+		Transactions:   f.transactionRepo,
+		Limits:         f.limitRepo,
+		Installments:   f.installmentRepo,
+		Accruals:       f.accrualRepo,
+		Restructurings: restructuringRepo,
+		Journal:        journalRepo,
+		LateFeeCharges: &MockLateFeeChargeRepository{},
+	})
+	f.accrualUC = NewAccrualUsecase(f.transactionRepo, f.installmentRepo, f.accrualRepo, transactor, DefaultAccrualPolicy())
+	f.accrualUC.RunAccrual(time.Now().AddDate(0, 0, -1))
+
+	f.uc = NewRestructuringUsecase(f.transactionRepo, f.installmentRepo, restructuringRepo, transactor, DefaultRestructuringPolicy())
+	return f
+}
+
+func validRestructureRequest() RestructureRequest {
+	return RestructureRequest{
+		NewTenor:      6,
+		InterestRate:  1,
+		HolidayMonths: 1,
+		Reason:        "Konsumen terkena PHK",
+		RequestedBy:   "collector-1",
+	}
+}
+
+// Test: Invalid terms are rejected and a valid request previews the new schedule
+func TestRequestRestructuring(t *testing.T) {
+	f := newRestructuringFixture()
+
+	invalid := []func(req *RestructureRequest){
+		func(req *RestructureRequest) { req.NewTenor = 4 },
+		func(req *RestructureRequest) { req.HolidayMonths = 4 },
+		func(req *RestructureRequest) { req.InterestRate = -1 },
+		func(req *RestructureRequest) { req.RequestedBy = " " },
+	}
+	for i, mutate := range invalid {
+		req := validRestructureRequest()
+		mutate(&req)
+		if _, err := f.uc.RequestRestructuring(1, req); err == nil {
+			t.Errorf("Case %d: expected error for invalid request, got nil", i)
+		}
+	}
+
+	restructuring, err := f.uc.RequestRestructuring(1, validRestructureRequest())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Installment 1 is overdue: its interest is capitalised, the rest of the old interest is dropped
+	if restructuring.Status != model.RestructuringStatusPending || restructuring.OutstandingPrincipal != 3000000 ||
+		restructuring.CapitalisedInterest != 100000 || restructuring.NewPrincipal != 3100000 {
+		t.Errorf("Expected pending request on 3100000 new principal, got %+v", restructuring)
+	}
+	if restructuring.NewInterestAmount != 186000 || restructuring.NewInstallmentAmount != 547666.67 {
+		t.Errorf("Expected 186000 interest in installments of 547666.67, got %.2f and %.2f",
+			restructuring.NewInterestAmount, restructuring.NewInstallmentAmount)
+	}
+	if len(f.installmentRepo.installments) != 3 {
+		t.Error("Expected the schedule unchanged until approval")
+	}
+
+	if _, err := f.uc.RequestRestructuring(1, validRestructureRequest()); err == nil {
+		t.Error("Expected error for a second pending request, got nil")
+	}
+}
+
+// Test: Approval reschedules the balance, moves the limit and keeps the books in line
+func TestReviewRestructuring_Approve(t *testing.T) {
+	f := newRestructuringFixture()
+	restructuring, _ := f.uc.RequestRestructuring(1, validRestructureRequest())
+
+	if _, err := f.uc.ReviewRestructuring(restructuring.ID, true, "collector-1", ""); err == nil {
+		t.Error("Expected error when the requester approves, got nil")
+	}
+
+	approved, err := f.uc.ReviewRestructuring(restructuring.ID, true, "supervisor-1", "ok")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	today := dateOnly(time.Now())
+	if approved.Status != model.RestructuringStatusApproved || !approved.EffectiveDate.Equal(today) {
+		t.Errorf("Expected approval effective today, got %+v", approved)
+	}
+
+	// The old schedule stays as history, the new one follows after the payment holiday
+	var rescheduled, current []*model.Installment
+	for _, installment := range f.installmentRepo.installments {
+		if installment.Status == model.InstallmentStatusRescheduled {
+			rescheduled = append(rescheduled, installment)
+		}
+		if installment.RestructuringID == approved.ID {
+			current = append(current, installment)
+		}
+	}
+	if len(rescheduled) != 3 || len(current) != 6 {
+		t.Fatalf("Expected 3 rescheduled and 6 new installments, got %d and %d", len(rescheduled), len(current))
+	}
+	if current[0].Sequence != 4 || !current[0].DueDate.Equal(today.AddDate(0, 2, 0)) {
+		t.Errorf("Expected installment 4 due in 2 months, got %d due %s", current[0].Sequence, current[0].DueDate)
+	}
+
+	transaction, _ := f.transactionRepo.GetByID(1)
+	if transaction.Tenor != 6 || transaction.InterestAmount != 186000 || transaction.RestructureCount != 1 || transaction.RestructuredAt == nil {
+		t.Errorf("Expected contract moved to the new terms, got %+v", transaction)
+	}
+
+	tenor3, _ := f.limitRepo.GetByConsumerAndTenor(1, 3)
+	tenor6, _ := f.limitRepo.GetByConsumerAndTenor(1, 6)
+	if tenor3.UsedAmount != 0 || tenor6.UsedAmount != 3000000 {
+		t.Errorf("Expected exposure moved to tenor 6, got %.2f and %.2f", tenor3.UsedAmount, tenor6.UsedAmount)
+	}
+
+	// Capitalised interest stays earned and the new interest is deferred
+	if accountBalance(t, f.ledger, ledger.Receivable) != 3286000 ||
+		accountBalance(t, f.ledger, ledger.UnearnedInterest) != 186000 ||
+		accountBalance(t, f.ledger, ledger.InterestIncome) != 100000 {
+		t.Error("Expected receivable 3286000, deferred 186000 and income 100000")
+	}
+	accruals, _ := f.accrualUC.GetAccruals(1)
+	var accrued float64
+	for _, accrual := range accruals {
+		accrued += accrual.Amount
+	}
+	if roundMoney(accrued) != 100000 {
+		t.Errorf("Expected accrual rows to add up to 100000, got %.2f", accrued)
+	}
+
+	// The new schedule earns from the restructuring date and is fully earned at its last due date
+	if result, _ := f.accrualUC.RunAccrual(today); result.ContractsAccrued != 0 {
+		t.Errorf("Expected nothing to accrue on the restructuring date, got %+v", result)
+	}
+	installments, _ := f.installmentRepo.GetByTransactionID(1)
+	if earned := recognisedInterest(transaction, installments, today.AddDate(0, 7, 0)); earned != 186000 {
+		t.Errorf("Expected 186000 earned over the new schedule, got %.2f", earned)
+	}
+}
+
+// Test: A rejected request leaves the contract untouched
+func TestReviewRestructuring_Reject(t *testing.T) {
+	f := newRestructuringFixture()
+	restructuring, _ := f.uc.RequestRestructuring(1, validRestructureRequest())
+
+	rejected, err := f.uc.ReviewRestructuring(restructuring.ID, false, "supervisor-1", "penghasilan belum jelas")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if rejected.Status != model.RestructuringStatusRejected {
+		t.Errorf("Expected REJECTED, got %s", rejected.Status)
+	}
+
+	transaction, _ := f.transactionRepo.GetByID(1)
+	if transaction.Tenor != 3 || transaction.RestructureCount != 0 || len(f.installmentRepo.installments) != 3 {
+		t.Error("Expected contract and schedule unchanged")
+	}
+	if _, err := f.uc.ReviewRestructuring(restructuring.ID, true, "supervisor-1", ""); err == nil {
+		t.Error("Expected error reviewing a closed request, got nil")
+	}
+}
@@ -0,0 +1,457 @@
+package usecase
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"main/internal/model"
+	"main/internal/repository"
+	"main/internal/settlement"
+)
+
+// Bulk-transfer file formats
+const (
+	SettlementFormatCSV        = "CSV"
+	SettlementFormatFixedWidth = "FIXED"
+)
+
+// SettlementPolicy holds the disbursement terms
+type SettlementPolicy struct {
+	SourceAccount string // company account debited by the bulk transfer
+}
+
+// DefaultSettlementPolicy returns a policy without source account; set it per environment
+func DefaultSettlementPolicy() SettlementPolicy {
+	return SettlementPolicy{}
+}
+
+// SettlementExport is a bulk-transfer file ready to upload to the bank
+type SettlementExport struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+	Batches     []model.SettlementBatch
+}
+
+// SettlementConfirmationResult summarises a bank confirmation import
+type SettlementConfirmationResult struct {
+	Paid   int      `json:"paid"`
+	Failed int      `json:"failed"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// SettlementUsecase defines all business logic operations for merchant disbursement
+type SettlementUsecase interface {
+	GenerateBatches(businessDate time.Time) ([]model.SettlementBatch, error)
+	GetBatches(businessDate time.Time) ([]model.SettlementBatch, error)
+	GetBatch(id uint) (*model.SettlementBatch, error)
+	ExportBatches(businessDate time.Time, format string) (*SettlementExport, error)
+	ImportConfirmation(data io.Reader) (*SettlementConfirmationResult, error)
+}
+
+// settlementUsecase is the implementation of SettlementUsecase
+type settlementUsecase struct {
+	settlementRepo   repository.SettlementRepository
+	transactionRepo  repository.TransactionRepository
+	cancellationRepo repository.TransactionCancellationRepository
+	merchantRepo     repository.MerchantRepository
+	transactor       repository.Transactor
+	policy           SettlementPolicy
+	mu               sync.Mutex
+}
+
+// NewSettlementUsecase creates a new instance of SettlementUsecase
+func NewSettlementUsecase(
+	settlementRepo repository.SettlementRepository,
+	transactionRepo repository.TransactionRepository,
+	cancellationRepo repository.TransactionCancellationRepository,
+	merchantRepo repository.MerchantRepository,
+	transactor repository.Transactor,
+	policy SettlementPolicy,
+) SettlementUsecase {
+	return &settlementUsecase{
+		settlementRepo:   settlementRepo,
+		transactionRepo:  transactionRepo,
+		cancellationRepo: cancellationRepo,
+		merchantRepo:     merchantRepo,
+		transactor:       transactor,
+		policy:           policy,
+	}
+}
+
+// GenerateBatches groups what is owed to and by each merchant up to the business date into one batch per merchant:
+// the financed amount (OTR - down payment) as activated of contracts not settled yet, whatever their status now,
+// less every cancellation not settled yet, billed back as the financed principal it cancelled plus the down
+// payment refunded to the consumer. A cancellation made after its contract was settled is taken off the next batch
+// of the merchant. MDR is charged on the financed amount still standing when the contract is settled and is not
+// given back for later cancellations. A batch netting to zero or less transfers nothing: it is CARRIED_FORWARD and
+// its balance is taken off the next batch of the merchant.
+// Contracts activated after a fraud review, signature or down payment are picked up by the next run;
+// running again settles nothing twice.
+func (u *settlementUsecase) GenerateBatches(businessDate time.Time) ([]model.SettlementBatch, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	date := dateOnly(businessDate)
+	cutoff := date.AddDate(0, 0, 1)
+
+	candidates, err := u.transactionRepo.GetMerchantActivatedBefore(cutoff)
+	if err != nil {
+		return nil, err
+	}
+	cancellations, err := u.cancellationRepo.GetBilledBefore(cutoff)
+	if err != nil {
+		return nil, err
+	}
+
+	contracts := make(map[uint]*model.Transaction, len(candidates))
+	ids := make([]uint, 0, len(candidates))
+	for i := range candidates {
+		contracts[candidates[i].ID] = &candidates[i]
+		ids = append(ids, candidates[i].ID)
+	}
+	// Contracts cancelled before activation still bill back the down payment the merchant collected
+	for _, cancellation := range cancellations {
+		if _, exists := contracts[cancellation.TransactionID]; exists {
+			continue
+		}
+		transaction, err := u.transactionRepo.GetByID(cancellation.TransactionID)
+		if err != nil {
+			return nil, err
+		}
+		contracts[transaction.ID] = transaction
+		ids = append(ids, transaction.ID)
+	}
+
+	settled, err := u.settlementRepo.GetItemsByTransactionIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	isSettled := make(map[uint]bool, len(settled))
+	isBilled := make(map[uint]bool)
+	for _, item := range settled {
+		if item.CancellationID == 0 {
+			isSettled[item.TransactionID] = true
+		} else {
+			isBilled[item.CancellationID] = true
+		}
+	}
+
+	dues := make(map[uint]*merchantDue)
+	var merchantIDs []uint
+	dueOf := func(merchantID uint) *merchantDue {
+		if _, exists := dues[merchantID]; !exists {
+			dues[merchantID] = &merchantDue{}
+			merchantIDs = append(merchantIDs, merchantID)
+		}
+		return dues[merchantID]
+	}
+	settling := make(map[uint]bool)
+	for i := range candidates {
+		transaction := &candidates[i]
+		if isSettled[transaction.ID] {
+			continue
+		}
+		settling[transaction.ID] = true
+		due := dueOf(transaction.MerchantID)
+		due.contracts = append(due.contracts, transaction)
+	}
+	for _, cancellation := range cancellations {
+		transaction := contracts[cancellation.TransactionID]
+		if isBilled[cancellation.ID] || transaction.MerchantID == 0 {
+			continue
+		}
+		// The cancellation of an activated contract is billed with or after its financed amount, never before
+		if transaction.ActivatedAt != nil && !isSettled[transaction.ID] && !settling[transaction.ID] {
+			continue
+		}
+		due := dueOf(transaction.MerchantID)
+		due.cancellations = append(due.cancellations, cancellation)
+	}
+	sort.Slice(merchantIDs, func(i, j int) bool { return merchantIDs[i] < merchantIDs[j] })
+
+	existing, err := u.settlementRepo.GetByBusinessDate(date)
+	if err != nil {
+		return nil, err
+	}
+	runs := make(map[uint]int)
+	for _, batch := range existing {
+		runs[batch.MerchantID]++
+	}
+
+	now := time.Now()
+	var batches []model.SettlementBatch
+	for _, merchantID := range merchantIDs {
+		merchant, err := u.merchantRepo.GetByID(merchantID)
+		if err != nil {
+			log.Printf("⚠ Settlement merchant %d dilewati: merchant tidak ditemukan\n", merchantID)
+			continue
+		}
+
+		batch := model.SettlementBatch{
+			BatchNumber:       fmt.Sprintf("STL-%s-%s", date.Format("20060102"), merchant.Code),
+			MerchantID:        merchant.ID,
+			BusinessDate:      date,
+			BankName:          merchant.BankName,
+			BankAccountNumber: merchant.BankAccountNumber,
+			BankAccountName:   merchant.BankAccountName,
+			Status:            model.SettlementStatusPending,
+			CreatedAt:         now,
+			UpdatedAt:         now,
+		}
+		if runs[merchant.ID] > 0 {
+			batch.BatchNumber = fmt.Sprintf("%s-%d", batch.BatchNumber, runs[merchant.ID]+1)
+		}
+
+		counted := make(map[uint]bool)
+		due := dues[merchantID]
+		for _, transaction := range due.contracts {
+			cancelled, err := u.cancelledPrincipal(transaction.ID)
+			if err != nil {
+				return nil, err
+			}
+			// A partial cancellation already lowered the OTR of the contract by the returned amount
+			gross := roundMoney(transaction.FinancedAmount() + cancelled)
+			standing := transaction.FinancedAmount()
+			if transaction.Status == model.TransactionStatusCancelled {
+				standing = 0
+			}
+			mdr := roundMoney(standing * merchant.MDRRate / 100)
+			batch.Items = append(batch.Items, model.SettlementItem{
+				TransactionID:  transaction.ID,
+				ContractNumber: transaction.ContractNumber,
+				GrossAmount:    gross,
+				MDRAmount:      mdr,
+				NetAmount:      roundMoney(gross - mdr),
+			})
+			batch.GrossAmount += gross
+			batch.MDRAmount += mdr
+			counted[transaction.ID] = true
+		}
+		for _, cancellation := range due.cancellations {
+			batch.Items = append(batch.Items, model.SettlementItem{
+				TransactionID:   cancellation.TransactionID,
+				CancellationID:  cancellation.ID,
+				ContractNumber:  contracts[cancellation.TransactionID].ContractNumber,
+				CancelledAmount: cancellation.MerchantAmount,
+				NetAmount:       -cancellation.MerchantAmount,
+			})
+			batch.CancelledAmount += cancellation.MerchantAmount
+			counted[cancellation.TransactionID] = true
+		}
+
+		carried, err := u.settlementRepo.GetCarriedForward(merchant.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, earlier := range carried {
+			batch.CarriedAmount += earlier.NetAmount
+		}
+
+		batch.TransactionCount = len(counted)
+		batch.GrossAmount = roundMoney(batch.GrossAmount)
+		batch.CancelledAmount = roundMoney(batch.CancelledAmount)
+		batch.MDRAmount = roundMoney(batch.MDRAmount)
+		batch.CarriedAmount = roundMoney(batch.CarriedAmount)
+		batch.NetAmount = roundMoney(batch.GrossAmount - batch.CancelledAmount - batch.MDRAmount + batch.CarriedAmount)
+		if batch.NetAmount <= 0 {
+			batch.Status = model.SettlementStatusCarriedForward
+		}
+
+		err = u.transactor.Transaction(func(tx *repository.Repositories) error {
+			if err := tx.Settlements.Create(&batch); err != nil {
+				return err
+			}
+			for i := range carried {
+				carried[i].CarriedToBatchID = batch.ID
+				carried[i].UpdatedAt = now
+				if err := tx.Settlements.Update(&carried[i]); err != nil {
+					return err
+				}
+			}
+			return ledgerIn(tx).Post(mdrEntry(&batch))
+		})
+		if err != nil {
+			return nil, err
+		}
+		if batch.Status == model.SettlementStatusCarriedForward {
+			log.Printf("⚠ Settlement %s bernilai Rp %.2f, dibawa ke batch berikutnya\n", batch.BatchNumber, batch.NetAmount)
+		}
+		batches = append(batches, batch)
+	}
+
+	log.Printf("✓ Settlement %s: %d batch dibuat\n", date.Format("2006-01-02"), len(batches))
+	return batches, nil
+}
+
+// merchantDue is what one settlement run pays out to a merchant and bills back to it
+type merchantDue struct {
+	contracts     []*model.Transaction
+	cancellations []model.TransactionCancellation
+}
+
+// cancelledPrincipal sums the partial cancellations of a contract, each taken off its financed principal
+func (u *settlementUsecase) cancelledPrincipal(transactionID uint) (float64, error) {
+	cancellations, err := u.cancellationRepo.GetByTransactionID(transactionID)
+	if err != nil {
+		return 0, err
+	}
+	total := 0.0
+	for _, cancellation := range cancellations {
+		if cancellation.Partial {
+			total += cancellation.Amount
+		}
+	}
+	return roundMoney(total), nil
+}
+
+func (u *settlementUsecase) GetBatches(businessDate time.Time) ([]model.SettlementBatch, error) {
+	return u.settlementRepo.GetByBusinessDate(dateOnly(businessDate))
+}
+
+func (u *settlementUsecase) GetBatch(id uint) (*model.SettlementBatch, error) {
+	return u.settlementRepo.GetByID(id)
+}
+
+// ExportBatches writes the PENDING and FAILED batches of the business date into one bulk-transfer file
+// and marks them EXPORTED. Exported batches are not written again until the bank reports them FAILED.
+func (u *settlementUsecase) ExportBatches(businessDate time.Time, format string) (*SettlementExport, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	format = strings.ToUpper(strings.TrimSpace(format))
+	if format == "" {
+		format = SettlementFormatCSV
+	}
+	if format != SettlementFormatCSV && format != SettlementFormatFixedWidth {
+		return nil, errors.New("format file harus CSV atau FIXED")
+	}
+
+	date := dateOnly(businessDate)
+	batches, err := u.settlementRepo.GetByBusinessDate(date)
+	if err != nil {
+		return nil, err
+	}
+
+	var exported []model.SettlementBatch
+	var transfers []settlement.Transfer
+	for _, batch := range batches {
+		if batch.Status != model.SettlementStatusPending && batch.Status != model.SettlementStatusFailed {
+			continue
+		}
+		if batch.NetAmount <= 0 {
+			continue
+		}
+		exported = append(exported, batch)
+		transfers = append(transfers, settlement.Transfer{
+			Reference:     batch.BatchNumber,
+			BankName:      batch.BankName,
+			AccountNumber: batch.BankAccountNumber,
+			AccountName:   batch.BankAccountName,
+			Amount:        batch.NetAmount,
+		})
+	}
+	if len(exported) == 0 {
+		return nil, errors.New("tidak ada batch settlement yang perlu diekspor")
+	}
+
+	header := settlement.FileHeader{SourceAccount: u.policy.SourceAccount, ValueDate: time.Now()}
+	export := &SettlementExport{FileName: "settlement-" + date.Format("20060102")}
+	var buf bytes.Buffer
+	if format == SettlementFormatFixedWidth {
+		err = settlement.WriteFixedWidth(&buf, header, transfers)
+		export.FileName += ".txt"
+		export.ContentType = "text/plain"
+	} else {
+		err = settlement.WriteCSV(&buf, header, transfers)
+		export.FileName += ".csv"
+		export.ContentType = "text/csv"
+	}
+	if err != nil {
+		return nil, err
+	}
+	export.Content = buf.Bytes()
+
+	now := time.Now()
+	for i := range exported {
+		exported[i].Status = model.SettlementStatusExported
+		exported[i].FailureReason = ""
+		exported[i].ExportedAt = &now
+		exported[i].UpdatedAt = now
+		if err := u.settlementRepo.Update(&exported[i]); err != nil {
+			return nil, err
+		}
+	}
+	export.Batches = exported
+
+	log.Printf("✓ File settlement %s berisi %d transfer\n", export.FileName, len(exported))
+	return export, nil
+}
+
+// ImportConfirmation applies a bank confirmation file: SUCCESS marks an EXPORTED batch PAID
+// when the amount matches its net amount, FAILED marks it for the next export
+func (u *settlementUsecase) ImportConfirmation(data io.Reader) (*SettlementConfirmationResult, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	confirmations, lineErrors, err := settlement.ParseConfirmations(data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SettlementConfirmationResult{Errors: lineErrors}
+	now := time.Now()
+	for _, confirmation := range confirmations {
+		batch, err := u.settlementRepo.GetByBatchNumber(confirmation.Reference)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("baris %d: batch %s tidak ditemukan", confirmation.Line, confirmation.Reference))
+			continue
+		}
+		if batch.Status != model.SettlementStatusExported {
+			result.Errors = append(result.Errors, fmt.Sprintf("baris %d: batch %s berstatus %s", confirmation.Line, batch.BatchNumber, batch.Status))
+			continue
+		}
+		if roundMoney(confirmation.Amount) != batch.NetAmount {
+			result.Errors = append(result.Errors, fmt.Sprintf("baris %d: jumlah %.2f tidak sesuai batch %s (%.2f)",
+				confirmation.Line, confirmation.Amount, batch.BatchNumber, batch.NetAmount))
+			continue
+		}
+
+		batch.BankReference = confirmation.BankReference
+		batch.UpdatedAt = now
+		if confirmation.Status == settlement.ConfirmationSuccess {
+			batch.Status = model.SettlementStatusPaid
+			batch.PaidAt = &now
+		} else {
+			batch.Status = model.SettlementStatusFailed
+			batch.FailureReason = confirmation.Reason
+		}
+		err = u.transactor.Transaction(func(tx *repository.Repositories) error {
+			if err := tx.Settlements.Update(batch); err != nil {
+				return err
+			}
+			if batch.Status != model.SettlementStatusPaid {
+				return nil
+			}
+			return ledgerIn(tx).Post(merchantPaymentEntry(batch, now))
+		})
+		if err != nil {
+			return nil, err
+		}
+		if batch.Status == model.SettlementStatusPaid {
+			result.Paid++
+		} else {
+			result.Failed++
+		}
+	}
+
+	log.Printf("✓ Konfirmasi settlement: %d dibayar, %d gagal, %d error\n", result.Paid, result.Failed, len(result.Errors))
+	return result, nil
+}
@@ -0,0 +1,364 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"main/internal/model"
+	"main/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// MockSettlementRepository for testing
+type MockSettlementRepository struct {
+	batches []*model.SettlementBatch
+}
+
+func (m *MockSettlementRepository) Create(batch *model.SettlementBatch) error {
+	batch.ID = uint(len(m.batches) + 1)
+	for i := range batch.Items {
+		batch.Items[i].BatchID = batch.ID
+	}
+	stored := *batch
+	m.batches = append(m.batches, &stored)
+	return nil
+}
+
+func (m *MockSettlementRepository) GetByID(id uint) (*model.SettlementBatch, error) {
+	for _, batch := range m.batches {
+		if batch.ID == id {
+			return batch, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockSettlementRepository) GetByBatchNumber(batchNumber string) (*model.SettlementBatch, error) {
+	for _, batch := range m.batches {
+		if batch.BatchNumber == batchNumber {
+			return batch, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockSettlementRepository) GetByBusinessDate(date time.Time) ([]model.SettlementBatch, error) {
+	var batches []model.SettlementBatch
+	for _, batch := range m.batches {
+		if batch.BusinessDate.Equal(date) {
+			batches = append(batches, *batch)
+		}
+	}
+	return batches, nil
+}
+
+func (m *MockSettlementRepository) GetItemsByTransactionIDs(transactionIDs []uint) ([]model.SettlementItem, error) {
+	wanted := make(map[uint]bool)
+	for _, id := range transactionIDs {
+		wanted[id] = true
+	}
+	var items []model.SettlementItem
+	for _, batch := range m.batches {
+		for _, item := range batch.Items {
+			if wanted[item.TransactionID] {
+				items = append(items, item)
+			}
+		}
+	}
+	return items, nil
+}
+
+func (m *MockSettlementRepository) GetCarriedForward(merchantID uint) ([]model.SettlementBatch, error) {
+	var batches []model.SettlementBatch
+	for _, batch := range m.batches {
+		if batch.MerchantID == merchantID && batch.Status == model.SettlementStatusCarriedForward && batch.CarriedToBatchID == 0 {
+			batches = append(batches, *batch)
+		}
+	}
+	return batches, nil
+}
+
+func (m *MockSettlementRepository) Update(batch *model.SettlementBatch) error {
+	for i, stored := range m.batches {
+		if stored.ID == batch.ID {
+			updated := *batch
+			updated.Items = stored.Items
+			m.batches[i] = &updated
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+type settlementFixture struct {
+	uc               SettlementUsecase
+	settlementRepo   *MockSettlementRepository
+	transactionRepo  *MockTransactionRepository
+	cancellationRepo *MockTransactionCancellationRepository
+	ledger           LedgerUsecase
+	journalRepo      *MockJournalRepository
+	businessDate     time.Time
+}
+
+// newSettlementFixture books contracts for DEALER-01 (3% MDR) and TOKO-02 (no MDR) on the business date,
+// plus contracts that must not be settled: held for review, booked directly and activated the next day
+func newSettlementFixture() *settlementFixture {
+	f := &settlementFixture{
+		settlementRepo:   &MockSettlementRepository{},
+		transactionRepo:  NewMockTransactionRepository(),
+		cancellationRepo: &MockTransactionCancellationRepository{},
+		businessDate:     time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local),
+	}
+
+	merchantRepo := NewMockMerchantRepository()
+	merchantRepo.Create(&model.Merchant{Code: "DEALER-01", BankName: "BCA", BankAccountNumber: "1234567890", BankAccountName: "PT Motor Jaya", MDRRate: 3, Status: model.MerchantStatusActive})
+	merchantRepo.Create(&model.Merchant{Code: "TOKO-02", BankName: "Mandiri", BankAccountNumber: "9876543210", BankAccountName: "Toko Sentosa", Status: model.MerchantStatusActive})
+
+	booked := f.businessDate.Add(14 * time.Hour)
+	nextDay := f.businessDate.AddDate(0, 0, 1)
+	contracts := []model.Transaction{
+		{ContractNumber: "CTR-1", MerchantID: 1, OTR: 3000000, Status: model.TransactionStatusActive, CreatedAt: booked, ActivatedAt: &booked},
+		{ContractNumber: "CTR-2", MerchantID: 1, OTR: 2000000, DownPayment: 500000, Status: model.TransactionStatusActive, CreatedAt: booked, ActivatedAt: &booked},
+		{ContractNumber: "CTR-3", MerchantID: 1, OTR: 1000000, Status: model.TransactionStatusPendingReview, CreatedAt: booked},
+		{ContractNumber: "CTR-4", MerchantID: 2, OTR: 1500000, Status: model.TransactionStatusActive, CreatedAt: booked, ActivatedAt: &booked},
+		{ContractNumber: "CTR-5", OTR: 1000000, Status: model.TransactionStatusActive, CreatedAt: booked, ActivatedAt: &booked},
+		{ContractNumber: "CTR-6", MerchantID: 2, OTR: 1000000, Status: model.TransactionStatusActive, CreatedAt: nextDay, ActivatedAt: &nextDay},
+	}
+	for i := range contracts {
+		f.transactionRepo.Create(&contracts[i])
+	}
+
+	f.ledger, f.journalRepo = newTestLedger()
+	transactor := newTestTransactor(repository.Repositories{Settlements: f.settlementRepo, Journal: f.journalRepo})
+	f.uc = NewSettlementUsecase(f.settlementRepo, f.transactionRepo, f.cancellationRepo, merchantRepo, transactor, SettlementPolicy{SourceAccount: "8880001111"})
+	return f
+}
+
+// Test: Activated merchant contracts are batched per merchant with MDR deducted, once
+func TestGenerateBatches(t *testing.T) {
+	f := newSettlementFixture()
+
+	batches, err := f.uc.GenerateBatches(f.businessDate)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(batches) != 2 {
+		t.Fatalf("Expected 2 batches, got %d", len(batches))
+	}
+
+	dealer := batches[0]
+	if dealer.BatchNumber != "STL-20260301-DEALER-01" || dealer.TransactionCount != 2 {
+		t.Errorf("Expected 2 contracts in STL-20260301-DEALER-01, got %d in %s", dealer.TransactionCount, dealer.BatchNumber)
+	}
+	// 3.000.000 + (2.000.000 - 500.000 DP), 3% MDR
+	if dealer.GrossAmount != 4500000 || dealer.MDRAmount != 135000 || dealer.NetAmount != 4365000 {
+		t.Errorf("Expected gross 4500000, MDR 135000, net 4365000, got %.2f, %.2f, %.2f", dealer.GrossAmount, dealer.MDRAmount, dealer.NetAmount)
+	}
+	if batches[1].NetAmount != 1500000 || batches[1].BankAccountNumber != "9876543210" {
+		t.Errorf("Expected 1500000 to TOKO-02 account, got %.2f to %s", batches[1].NetAmount, batches[1].BankAccountNumber)
+	}
+	// MDR is earned when the batch is created; TOKO-02 pays none
+	if len(f.journalRepo.entries) != 1 || f.journalRepo.entries[0].Reference != "MDR-STL-20260301-DEALER-01" ||
+		f.journalRepo.entries[0].TotalAmount != 135000 {
+		t.Errorf("Expected one MDR entry of 135000, got %+v", f.journalRepo.entries)
+	}
+
+	again, _ := f.uc.GenerateBatches(f.businessDate)
+	if len(again) != 0 {
+		t.Errorf("Expected no batches on a re-run, got %d", len(again))
+	}
+
+	// The reviewed contract is approved later and paid with a follow-up batch
+	reviewed, _ := f.transactionRepo.GetByContractNumber("CTR-3")
+	reviewed.Status = model.TransactionStatusActive
+	reviewed.ActivatedAt = &reviewed.CreatedAt
+	followUp, _ := f.uc.GenerateBatches(f.businessDate)
+	if len(followUp) != 1 || followUp[0].BatchNumber != "STL-20260301-DEALER-01-2" || followUp[0].NetAmount != 970000 {
+		t.Errorf("Expected follow-up batch STL-20260301-DEALER-01-2 of 970000, got %+v", followUp)
+	}
+}
+
+// Test: Contracts activated on the business date are settled whatever their status now, net of cancellations
+func TestGenerateBatches_StatusAfterActivation(t *testing.T) {
+	f := newSettlementFixture()
+	f.uc.GenerateBatches(f.businessDate)
+
+	date := f.businessDate.AddDate(0, 0, 1)
+	activated := date.Add(10 * time.Hour)
+	contracts := []model.Transaction{
+		// paid off early before the settlement run
+		{ContractNumber: "CTR-7", MerchantID: 1, OTR: 1000000, Status: model.TransactionStatusCompleted, CreatedAt: activated, ActivatedAt: &activated},
+		// 1.000.000 of goods returned: the OTR was lowered from 3.000.000
+		{ContractNumber: "CTR-8", MerchantID: 1, OTR: 2000000, Status: model.TransactionStatusActive, CreatedAt: activated, ActivatedAt: &activated},
+		// cancelled in full: what the merchant was owed is billed back in the same batch
+		{ContractNumber: "CTR-9", MerchantID: 1, OTR: 1000000, Status: model.TransactionStatusCancelled, CreatedAt: activated, ActivatedAt: &activated},
+	}
+	for i := range contracts {
+		f.transactionRepo.Create(&contracts[i])
+	}
+	f.cancellationRepo.Create(&model.TransactionCancellation{TransactionID: contracts[1].ID, Amount: 1000000, Partial: true,
+		MerchantAmount: 1000000, CreatedAt: activated})
+	f.cancellationRepo.Create(&model.TransactionCancellation{TransactionID: contracts[2].ID, Amount: 1000000,
+		MerchantAmount: 1000000, CreatedAt: activated})
+
+	batches, err := f.uc.GenerateBatches(date)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var dealer *model.SettlementBatch
+	for i := range batches {
+		if batches[i].MerchantID == 1 {
+			dealer = &batches[i]
+		}
+	}
+	if dealer == nil || dealer.TransactionCount != 3 || len(dealer.Items) != 5 {
+		t.Fatalf("Expected the three contracts and the two cancellations settled, got %+v", batches)
+	}
+	// 1.000.000 + 3.000.000 + 1.000.000 activated, 2.000.000 cancelled, 3% MDR on the 3.000.000 left
+	if dealer.GrossAmount != 5000000 || dealer.CancelledAmount != 2000000 || dealer.MDRAmount != 90000 || dealer.NetAmount != 2910000 {
+		t.Errorf("Expected gross 5000000, cancelled 2000000, MDR 90000, net 2910000, got %.2f, %.2f, %.2f, %.2f",
+			dealer.GrossAmount, dealer.CancelledAmount, dealer.MDRAmount, dealer.NetAmount)
+	}
+	net := 0.0
+	for _, item := range dealer.Items {
+		if item.ContractNumber == "CTR-9" {
+			net += item.NetAmount
+		}
+	}
+	if net != 0 {
+		t.Errorf("Expected the fully cancelled contract to net to zero, got %.2f", net)
+	}
+}
+
+// Test: Cancellations after settlement are billed to the next batch; a batch netting below zero is carried forward
+func TestGenerateBatches_CancelledAfterSettlement(t *testing.T) {
+	f := newSettlementFixture()
+	f.uc.GenerateBatches(f.businessDate)
+
+	// 1.000.000 of CTR-1 returned, CTR-2 cancelled in full with its 500.000 down payment refunded
+	dayTwo := f.businessDate.AddDate(0, 0, 1)
+	cancelledAt := dayTwo.Add(9 * time.Hour)
+	f.cancellationRepo.Create(&model.TransactionCancellation{TransactionID: 1, Amount: 1000000, Partial: true,
+		MerchantAmount: 1000000, CreatedAt: cancelledAt})
+	f.cancellationRepo.Create(&model.TransactionCancellation{TransactionID: 2, Amount: 2000000,
+		MerchantAmount: 2000000, CreatedAt: cancelledAt})
+
+	batches, err := f.uc.GenerateBatches(dayTwo)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	carried := batches[0]
+	if carried.MerchantID != 1 || carried.CancelledAmount != 3000000 || carried.NetAmount != -3000000 ||
+		carried.Status != model.SettlementStatusCarriedForward || carried.TransactionCount != 2 {
+		t.Fatalf("Expected -3000000 carried forward for DEALER-01, got %+v", carried)
+	}
+	if export, err := f.uc.ExportBatches(dayTwo, "CSV"); err == nil {
+		for _, batch := range export.Batches {
+			if batch.MerchantID == 1 {
+				t.Error("Expected the carried-forward batch left out of the bank file")
+			}
+		}
+	}
+
+	// The next contract of the merchant pays the balance off
+	dayThree := dayTwo.AddDate(0, 0, 1)
+	activated := dayThree.Add(10 * time.Hour)
+	f.transactionRepo.Create(&model.Transaction{ContractNumber: "CTR-10", MerchantID: 1, OTR: 5000000,
+		Status: model.TransactionStatusActive, CreatedAt: activated, ActivatedAt: &activated})
+
+	batches, err = f.uc.GenerateBatches(dayThree)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// 5.000.000 less 3% MDR less the 3.000.000 carried
+	if len(batches) != 1 || batches[0].CarriedAmount != -3000000 || batches[0].NetAmount != 1850000 ||
+		batches[0].Status != model.SettlementStatusPending {
+		t.Fatalf("Expected 1850000 due after the carried balance, got %+v", batches)
+	}
+	earlier, _ := f.settlementRepo.GetByBatchNumber(carried.BatchNumber)
+	if earlier.CarriedToBatchID != batches[0].ID {
+		t.Errorf("Expected the carried balance taken by batch %d, got %d", batches[0].ID, earlier.CarriedToBatchID)
+	}
+
+	if again, _ := f.uc.GenerateBatches(dayThree); len(again) != 0 {
+		t.Errorf("Expected nothing billed twice, got %+v", again)
+	}
+}
+
+// Test: Export writes pending batches once and the confirmation file marks them paid or failed
+func TestExportAndConfirmBatches(t *testing.T) {
+	f := newSettlementFixture()
+	f.uc.GenerateBatches(f.businessDate)
+
+	if _, err := f.uc.ExportBatches(f.businessDate, "XML"); err == nil {
+		t.Error("Expected error for an unknown format, got nil")
+	}
+
+	export, err := f.uc.ExportBatches(f.businessDate, "csv")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if export.FileName != "settlement-20260301.csv" || len(export.Batches) != 2 {
+		t.Errorf("Expected 2 batches in settlement-20260301.csv, got %d in %s", len(export.Batches), export.FileName)
+	}
+	if !strings.Contains(string(export.Content), "STL-20260301-DEALER-01,8880001111,") {
+		t.Errorf("Expected the batch number as transfer reference, got %s", export.Content)
+	}
+	if _, err := f.uc.ExportBatches(f.businessDate, "csv"); err == nil {
+		t.Error("Expected error exporting batches already sent to the bank, got nil")
+	}
+
+	confirmation := "reference,status,bank_reference,amount,reason\n" +
+		"STL-20260301-DEALER-01,SUCCESS,TRF001,4365000.00,\n" +
+		"STL-20260301-TOKO-02,FAILED,TRF002,1500000.00,rekening ditutup\n" +
+		"STL-20260301-DEALER-01,SUCCESS,TRF003,4365000.00,\n" +
+		"STL-20260301-UNKNOWN,SUCCESS,TRF004,100.00,\n"
+	result, err := f.uc.ImportConfirmation(strings.NewReader(confirmation))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Paid != 1 || result.Failed != 1 || len(result.Errors) != 2 {
+		t.Errorf("Expected 1 paid, 1 failed and 2 errors, got %+v", result)
+	}
+
+	paid, _ := f.settlementRepo.GetByBatchNumber("STL-20260301-DEALER-01")
+	if paid.Status != model.SettlementStatusPaid || paid.BankReference != "TRF001" || paid.PaidAt == nil {
+		t.Errorf("Expected PAID with bank reference, got %s %s", paid.Status, paid.BankReference)
+	}
+	if entry, err := f.journalRepo.GetByReference("PAY-STL-20260301-DEALER-01"); err != nil || entry.TotalAmount != 4365000 {
+		t.Error("Expected merchant payment entry of 4365000 for the paid batch")
+	}
+	if _, err := f.journalRepo.GetByReference("PAY-STL-20260301-TOKO-02"); err == nil {
+		t.Error("Expected no merchant payment entry for the failed batch")
+	}
+
+	// Failed transfers go out again with the next file
+	retry, err := f.uc.ExportBatches(f.businessDate, "FIXED")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(retry.Batches) != 1 || retry.Batches[0].BatchNumber != "STL-20260301-TOKO-02" || !strings.HasSuffix(retry.FileName, ".txt") {
+		t.Errorf("Expected only the failed batch in a fixed-width file, got %d batches in %s", len(retry.Batches), retry.FileName)
+	}
+}
+
+// Test: Confirmation amounts must match the batch
+func TestImportConfirmation_AmountMismatch(t *testing.T) {
+	f := newSettlementFixture()
+	f.uc.GenerateBatches(f.businessDate)
+	f.uc.ExportBatches(f.businessDate, "CSV")
+
+	result, err := f.uc.ImportConfirmation(strings.NewReader("reference,status,bank_reference,amount\nSTL-20260301-TOKO-02,SUCCESS,TRF001,1000000\n"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Paid != 0 || len(result.Errors) != 1 {
+		t.Errorf("Expected the mismatching line to be refused, got %+v", result)
+	}
+	batch, _ := f.settlementRepo.GetByBatchNumber("STL-20260301-TOKO-02")
+	if batch.Status != model.SettlementStatusExported {
+		t.Errorf("Expected batch to stay EXPORTED, got %s", batch.Status)
+	}
+}
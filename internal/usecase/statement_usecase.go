@@ -0,0 +1,528 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"main/internal/ledger"
+	"main/internal/model"
+	"main/internal/notification"
+	"main/internal/pdf"
+	"main/internal/repository"
+)
+
+// Statement entry types
+const (
+	StatementEntryBooked     = "BOOKED"     // contract activated, principal and interest owed
+	StatementEntryBilled     = "BILLED"     // installment fell due; informational, the amount was owed since activation
+	StatementEntryPenalty    = "PENALTY"    // late fee or early termination fee charged
+	StatementEntryPayment    = "PAYMENT"    // money received
+	StatementEntryAdjustment = "ADJUSTMENT" // cancellation, restructuring, write-off or waiver, signed
+)
+
+// StatementRequest selects the period, contract and format of an account statement
+type StatementRequest struct {
+	From          time.Time // defaults to the first day of the month of To
+	To            time.Time // defaults to today
+	TransactionID uint      // 0 for every contract of the consumer
+	Format        string    // JSON (default) or PDF
+}
+
+// StatementTotals is how the balance owed moved over the period. The closing balance is the opening
+// balance plus new contracts and penalties, less payments, plus adjustments.
+type StatementTotals struct {
+	OpeningBalance     float64 `json:"opening_balance"`
+	NewContracts       float64 `json:"new_contracts"`
+	InstallmentsBilled float64 `json:"installments_billed"`
+	Payments           float64 `json:"payments"`
+	Penalties          float64 `json:"penalties"`
+	Adjustments        float64 `json:"adjustments"`
+	ClosingBalance     float64 `json:"closing_balance"`
+}
+
+func (t *StatementTotals) add(other StatementTotals) {
+	t.OpeningBalance = roundMoney(t.OpeningBalance + other.OpeningBalance)
+	t.NewContracts = roundMoney(t.NewContracts + other.NewContracts)
+	t.InstallmentsBilled = roundMoney(t.InstallmentsBilled + other.InstallmentsBilled)
+	t.Payments = roundMoney(t.Payments + other.Payments)
+	t.Penalties = roundMoney(t.Penalties + other.Penalties)
+	t.Adjustments = roundMoney(t.Adjustments + other.Adjustments)
+	t.ClosingBalance = roundMoney(t.ClosingBalance + other.ClosingBalance)
+}
+
+// StatementEntry is one movement of a contract within the period
+type StatementEntry struct {
+	Date        time.Time `json:"date"`
+	Type        string    `json:"type"` // BOOKED, BILLED, PENALTY, PAYMENT, ADJUSTMENT
+	Description string    `json:"description"`
+	Amount      float64   `json:"amount"`
+}
+
+// ContractStatement is the statement of one contract
+type ContractStatement struct {
+	TransactionID  uint   `json:"transaction_id"`
+	ContractNumber string `json:"contract_number"`
+	Status         string `json:"status"`
+	StatementTotals
+	Entries []StatementEntry `json:"entries"`
+}
+
+// AccountStatement is the statement of a consumer over a period, per contract and in total
+type AccountStatement struct {
+	ConsumerID   uint                `json:"consumer_id"`
+	ConsumerName string              `json:"consumer_name"`
+	PeriodFrom   time.Time           `json:"period_from"`
+	PeriodTo     time.Time           `json:"period_to"`
+	GeneratedAt  time.Time           `json:"generated_at"`
+	Totals       StatementTotals     `json:"totals"`
+	Contracts    []ContractStatement `json:"contracts"`
+}
+
+// StatementDocument is a generated statement file and the archive record holding its hash
+type StatementDocument struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+	Archive     *model.StatementArchive
+}
+
+// StatementUsecase defines all business logic operations for consumer account statements
+type StatementUsecase interface {
+	GenerateStatement(consumerID uint, req StatementRequest) (*StatementDocument, error)
+	GetArchives(consumerID uint) ([]model.StatementArchive, error)
+	GetArchive(id uint) (*model.StatementArchive, error)
+	VerifyStatement(content []byte) (*model.StatementArchive, error)
+}
+
+// statementUsecase is the implementation of StatementUsecase
+type statementUsecase struct {
+	consumerRepo    repository.ConsumerRepository
+	transactionRepo repository.TransactionRepository
+	installmentRepo repository.InstallmentRepository
+	chargeRepo      repository.LateFeeChargeRepository
+	archiveRepo     repository.StatementArchiveRepository
+	journal         ContractJournal
+}
+
+// NewStatementUsecase creates a new instance of StatementUsecase
+func NewStatementUsecase(
+	consumerRepo repository.ConsumerRepository,
+	transactionRepo repository.TransactionRepository,
+	installmentRepo repository.InstallmentRepository,
+	chargeRepo repository.LateFeeChargeRepository,
+	archiveRepo repository.StatementArchiveRepository,
+	journal ContractJournal,
+) StatementUsecase {
+	return &statementUsecase{
+		consumerRepo:    consumerRepo,
+		transactionRepo: transactionRepo,
+		installmentRepo: installmentRepo,
+		chargeRepo:      chargeRepo,
+		archiveRepo:     archiveRepo,
+		journal:         journal,
+	}
+}
+
+// GenerateStatement builds the statement, renders it and archives the exact bytes with their SHA-256
+func (u *statementUsecase) GenerateStatement(consumerID uint, req StatementRequest) (*StatementDocument, error) {
+	format := strings.ToUpper(strings.TrimSpace(req.Format))
+	if format == "" {
+		format = model.StatementFormatJSON
+	}
+	if format != model.StatementFormatJSON && format != model.StatementFormatPDF {
+		return nil, fmt.Errorf("format laporan %s tidak didukung, gunakan JSON atau PDF", req.Format)
+	}
+
+	to := dateOnly(req.To)
+	if req.To.IsZero() {
+		to = dateOnly(time.Now())
+	}
+	from := dateOnly(req.From)
+	if req.From.IsZero() {
+		from = time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, to.Location())
+	}
+	if from.After(to) {
+		return nil, errors.New("tanggal awal periode harus sebelum tanggal akhir")
+	}
+
+	consumer, err := u.consumerRepo.GetByID(consumerID)
+	if err != nil {
+		return nil, errors.New("konsumen tidak ditemukan")
+	}
+
+	statement, err := u.buildStatement(consumer, req.TransactionID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	document := &StatementDocument{}
+	base := fmt.Sprintf("statement-%d-%s-%s", consumer.ID, from.Format("20060102"), to.Format("20060102"))
+	if req.TransactionID != 0 {
+		base = fmt.Sprintf("%s-%d", base, req.TransactionID)
+	}
+	switch format {
+	case model.StatementFormatPDF:
+		document.FileName = base + ".pdf"
+		document.ContentType = "application/pdf"
+		document.Content = renderStatementPDF(statement)
+	default:
+		document.FileName = base + ".json"
+		document.ContentType = "application/json"
+		if document.Content, err = json.MarshalIndent(statement, "", "  "); err != nil {
+			return nil, err
+		}
+	}
+
+	hash := sha256.Sum256(document.Content)
+	document.Archive = &model.StatementArchive{
+		ConsumerID:    consumer.ID,
+		TransactionID: req.TransactionID,
+		PeriodFrom:    from,
+		PeriodTo:      to,
+		Format:        format,
+		FileName:      document.FileName,
+		DocumentHash:  hex.EncodeToString(hash[:]),
+		Content:       document.Content,
+		SizeBytes:     len(document.Content),
+		CreatedAt:     statement.GeneratedAt,
+	}
+	if err := u.archiveRepo.Create(document.Archive); err != nil {
+		return nil, err
+	}
+
+	log.Printf("✓ Laporan rekening konsumen %d periode %s s/d %s diarsipkan (%s)\n",
+		consumer.ID, from.Format("2006-01-02"), to.Format("2006-01-02"), document.Archive.DocumentHash)
+	return document, nil
+}
+
+func (u *statementUsecase) buildStatement(consumer *model.Consumer, transactionID uint, from, to time.Time) (*AccountStatement, error) {
+	transactions, err := u.transactionRepo.GetByConsumerID(consumer.ID)
+	if err != nil {
+		return nil, err
+	}
+	if transactionID != 0 {
+		var selected []model.Transaction
+		for _, transaction := range transactions {
+			if transaction.ID == transactionID {
+				selected = append(selected, transaction)
+			}
+		}
+		if len(selected) == 0 {
+			return nil, errors.New("transaksi tidak ditemukan untuk konsumen ini")
+		}
+		transactions = selected
+	}
+	sort.Slice(transactions, func(i, j int) bool { return transactions[i].ID < transactions[j].ID })
+
+	statement := &AccountStatement{
+		ConsumerID:   consumer.ID,
+		ConsumerName: consumer.FullName,
+		PeriodFrom:   from,
+		PeriodTo:     to,
+		GeneratedAt:  time.Now(),
+		Contracts:    []ContractStatement{},
+	}
+	for i := range transactions {
+		transaction := &transactions[i]
+		if transaction.Status == model.TransactionStatusPendingReview || transaction.Status == model.TransactionStatusPendingSignature ||
+			transaction.Status == model.TransactionStatusPendingDownPayment || transaction.Status == model.TransactionStatusRejected {
+			continue
+		}
+		contract, err := u.contractStatement(transaction, from, to)
+		if err != nil {
+			return nil, err
+		}
+		if contract.OpeningBalance == 0 && contract.ClosingBalance == 0 && len(contract.Entries) == 0 {
+			continue
+		}
+		statement.Totals.add(contract.StatementTotals)
+		statement.Contracts = append(statement.Contracts, *contract)
+	}
+	return statement, nil
+}
+
+// contractStatement reads the balances and movements of a contract from the ledger. The receivable holds
+// principal and interest; late fees are owed as the charge history stood on the day until paid, and are
+// earned in the ledger only when paid.
+func (u *statementUsecase) contractStatement(transaction *model.Transaction, from, to time.Time) (*ContractStatement, error) {
+	lines, err := u.journal.ContractLines(transaction.ID)
+	if err != nil {
+		return nil, err
+	}
+	installments, err := u.installmentRepo.GetByTransactionID(transaction.ID)
+	if err != nil {
+		return nil, err
+	}
+	charges, err := u.chargeRepo.GetByTransactionID(transaction.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	contract := &ContractStatement{
+		TransactionID:  transaction.ID,
+		ContractNumber: transaction.ContractNumber,
+		Status:         transaction.Status,
+		Entries:        []StatementEntry{},
+	}
+	inPeriod := func(date time.Time) bool {
+		date = dateOnly(date)
+		return !date.Before(from) && !date.After(to)
+	}
+
+	// Movements posted to the ledger, one journal entry at a time
+	type posted struct {
+		date       time.Time
+		eventType  string
+		reference  string
+		receivable float64
+		cash       float64
+		lateFee    float64
+		penalty    float64
+	}
+	var order []string
+	byReference := make(map[string]*posted)
+	for _, line := range lines {
+		entry, ok := byReference[line.Reference]
+		if !ok {
+			entry = &posted{date: dateOnly(line.PostingDate), eventType: line.EventType}
+			byReference[line.Reference] = entry
+			order = append(order, line.Reference)
+		}
+		switch line.Account {
+		case ledger.Receivable:
+			entry.receivable += line.Debit - line.Credit
+		case ledger.Cash, ledger.PaymentSuspense:
+			entry.cash += line.Debit
+			if entry.reference == "" {
+				entry.reference = line.Memo
+			}
+		case ledger.LateFeeIncome:
+			entry.lateFee += line.Credit - line.Debit
+		case ledger.EarlyTerminationFeeIncome:
+			entry.penalty += line.Credit - line.Debit
+		}
+	}
+
+	// balance owed at the end of a day: receivable plus late fees charged and not yet paid
+	balance := func(asOf time.Time) float64 {
+		var owed float64
+		for _, reference := range order {
+			entry := byReference[reference]
+			if !entry.date.After(asOf) {
+				owed += entry.receivable - entry.lateFee
+			}
+		}
+		for i := range installments {
+			fee, _ := lateFeeAsOf(&installments[i], charges, asOf)
+			owed += fee
+		}
+		return roundMoney(owed)
+	}
+	contract.OpeningBalance = balance(from.AddDate(0, 0, -1))
+	contract.ClosingBalance = balance(to)
+
+	var listedAdjustments float64
+	for _, reference := range order {
+		entry := byReference[reference]
+		if !inPeriod(entry.date) {
+			continue
+		}
+		switch entry.eventType {
+		case model.JournalEventActivation:
+			contract.NewContracts = roundMoney(contract.NewContracts + entry.receivable)
+			contract.Entries = append(contract.Entries, StatementEntry{entry.date, StatementEntryBooked,
+				"Aktivasi kontrak (pokok dan bunga)", roundMoney(entry.receivable)})
+		case model.JournalEventInstallment, model.JournalEventPayoff:
+			label := "Pembayaran angsuran"
+			if entry.eventType == model.JournalEventPayoff {
+				label = "Pelunasan dipercepat"
+			}
+			if entry.reference != "" {
+				label += " " + entry.reference
+			}
+			contract.Payments = roundMoney(contract.Payments + entry.cash)
+			contract.Entries = append(contract.Entries, StatementEntry{entry.date, StatementEntryPayment, label, roundMoney(entry.cash)})
+			if entry.penalty != 0 {
+				contract.Penalties = roundMoney(contract.Penalties + entry.penalty)
+				contract.Entries = append(contract.Entries, StatementEntry{entry.date, StatementEntryPenalty,
+					"Penalti pelunasan dipercepat", roundMoney(entry.penalty)})
+			}
+		default:
+			if roundMoney(entry.receivable) == 0 {
+				continue
+			}
+			listedAdjustments = roundMoney(listedAdjustments + entry.receivable)
+			contract.Entries = append(contract.Entries, StatementEntry{entry.date, StatementEntryAdjustment,
+				statementAdjustmentLabel(entry.eventType), roundMoney(entry.receivable)})
+		}
+	}
+
+	for _, installment := range installments {
+		if installment.Status != model.InstallmentStatusCancelled && installment.Status != model.InstallmentStatusRescheduled &&
+			inPeriod(installment.DueDate) {
+			contract.InstallmentsBilled = roundMoney(contract.InstallmentsBilled + installment.Amount)
+			contract.Entries = append(contract.Entries, StatementEntry{dateOnly(installment.DueDate), StatementEntryBilled,
+				fmt.Sprintf("Angsuran ke-%d jatuh tempo", installment.Sequence), roundMoney(installment.Amount)})
+		}
+		// Late fees waived by a restructuring lower the balance and show among the adjustments
+		opening, _ := lateFeeAsOf(&installment, charges, from.AddDate(0, 0, -1))
+		closing, chargedOn := lateFeeAsOf(&installment, charges, to)
+		if charged := roundMoney(closing - opening); charged > 0 {
+			contract.Penalties = roundMoney(contract.Penalties + charged)
+			contract.Entries = append(contract.Entries, StatementEntry{chargedOn, StatementEntryPenalty,
+				fmt.Sprintf("Denda keterlambatan angsuran ke-%d", installment.Sequence), charged})
+		}
+	}
+
+	// Whatever the listed movements do not explain, such as interest waived at payoff, is shown as one adjustment
+	contract.Adjustments = roundMoney(contract.ClosingBalance - contract.OpeningBalance - contract.NewContracts -
+		contract.Penalties + contract.Payments)
+	if other := roundMoney(contract.Adjustments - listedAdjustments); other != 0 {
+		contract.Entries = append(contract.Entries, StatementEntry{to, StatementEntryAdjustment, "Penyesuaian lainnya", other})
+	}
+
+	sort.SliceStable(contract.Entries, func(i, j int) bool {
+		return contract.Entries[i].Date.Before(contract.Entries[j].Date)
+	})
+	return contract, nil
+}
+
+// lateFeeChargedOn is the day an installment's late fee starts, the day after it fell due
+func lateFeeChargedOn(installment *model.Installment) time.Time {
+	return dateOnly(installment.DueDate).AddDate(0, 0, 1)
+}
+
+// lateFeeAsOf returns the late fee of an installment at the end of a day and the date it was last changed,
+// from the charge history. Installments aged before the history was kept owe their current late fee
+// from the day after they fell due.
+func lateFeeAsOf(installment *model.Installment, charges []model.LateFeeCharge, asOf time.Time) (float64, time.Time) {
+	var fee float64
+	var chargedOn time.Time
+	history := false
+	for _, charge := range charges {
+		if charge.InstallmentID != installment.ID {
+			continue
+		}
+		history = true
+		if date := dateOnly(charge.BusinessDate); !date.After(asOf) && !date.Before(chargedOn) {
+			fee, chargedOn = charge.LateFee, date
+		}
+	}
+	if !history && !lateFeeChargedOn(installment).After(asOf) {
+		return installment.LateFee, lateFeeChargedOn(installment)
+	}
+	return fee, chargedOn
+}
+
+func statementAdjustmentLabel(eventType string) string {
+	switch eventType {
+	case model.JournalEventCancellation:
+		return "Pembatalan kontrak"
+	case model.JournalEventRestructuring:
+		return "Restrukturisasi kontrak"
+	case model.JournalEventWriteOff:
+		return "Hapus buku kontrak"
+	}
+	return "Penyesuaian " + strings.ToLower(strings.ReplaceAll(eventType, "_", " "))
+}
+
+func (u *statementUsecase) GetArchives(consumerID uint) ([]model.StatementArchive, error) {
+	if _, err := u.consumerRepo.GetByID(consumerID); err != nil {
+		return nil, errors.New("konsumen tidak ditemukan")
+	}
+	return u.archiveRepo.GetByConsumerID(consumerID)
+}
+
+func (u *statementUsecase) GetArchive(id uint) (*model.StatementArchive, error) {
+	archive, err := u.archiveRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("arsip laporan tidak ditemukan")
+	}
+	return archive, nil
+}
+
+// VerifyStatement finds the archived statement a document was generated as, by its SHA-256
+func (u *statementUsecase) VerifyStatement(content []byte) (*model.StatementArchive, error) {
+	hash := sha256.Sum256(content)
+	archive, err := u.archiveRepo.GetByHash(hex.EncodeToString(hash[:]))
+	if err != nil {
+		return nil, errors.New("dokumen tidak cocok dengan arsip laporan manapun")
+	}
+	return archive, nil
+}
+
+// renderStatementPDF lays the statement out as a printable document, one table of movements per contract
+func renderStatementPDF(statement *AccountStatement) []byte {
+	period := statement.PeriodFrom.Format("02/01/2006") + " - " + statement.PeriodTo.Format("02/01/2006")
+	doc := pdf.New("Laporan Rekening Pembiayaan " + period)
+	doc.Title("Laporan Rekening Pembiayaan")
+	doc.Field("Konsumen", fmt.Sprintf("%s (ID %d)", statement.ConsumerName, statement.ConsumerID))
+	doc.Field("Periode", period)
+	doc.Field("Dibuat", statement.GeneratedAt.Format("02/01/2006 15:04:05 MST"))
+
+	doc.Heading("Ringkasan")
+	writeStatementTotals(doc, statement.Totals)
+
+	columns := []pdf.Column{
+		{Title: "Tanggal", Width: 70},
+		{Title: "Jenis", Width: 75},
+		{Title: "Keterangan", Width: 250},
+		{Title: "Jumlah", Width: 100, Right: true},
+	}
+	for _, contract := range statement.Contracts {
+		doc.Heading(fmt.Sprintf("Kontrak %s (%s)", contract.ContractNumber, contract.Status))
+		writeStatementTotals(doc, contract.StatementTotals)
+		doc.Space(6)
+		if len(contract.Entries) == 0 {
+			doc.Text("Tidak ada mutasi dalam periode ini.")
+			continue
+		}
+		rows := make([][]string, len(contract.Entries))
+		for i, entry := range contract.Entries {
+			rows[i] = []string{entry.Date.Format("02/01/2006"), statementEntryLabel(entry.Type), entry.Description,
+				notification.FormatRupiah(entry.Amount)}
+		}
+		doc.Table(columns, rows)
+	}
+	if len(statement.Contracts) == 0 {
+		doc.Text("Tidak ada kontrak dengan saldo atau mutasi dalam periode ini.")
+	}
+
+	doc.Space(12)
+	doc.Text("Tagihan angsuran bersifat informasi; jumlahnya sudah tercatat sejak aktivasi kontrak. " +
+		"Keaslian dokumen ini dapat diperiksa dengan hash SHA-256 yang tersimpan di arsip laporan.")
+	return doc.Bytes()
+}
+
+func writeStatementTotals(doc *pdf.Document, totals StatementTotals) {
+	doc.Field("Saldo awal", notification.FormatRupiah(totals.OpeningBalance))
+	doc.Field("Kontrak baru", notification.FormatRupiah(totals.NewContracts))
+	doc.Field("Tagihan angsuran", notification.FormatRupiah(totals.InstallmentsBilled))
+	doc.Field("Pembayaran diterima", notification.FormatRupiah(totals.Payments))
+	doc.Field("Denda dan penalti", notification.FormatRupiah(totals.Penalties))
+	doc.Field("Penyesuaian", notification.FormatRupiah(totals.Adjustments))
+	doc.Field("Saldo akhir", notification.FormatRupiah(totals.ClosingBalance))
+}
+
+func statementEntryLabel(entryType string) string {
+	switch entryType {
+	case StatementEntryBooked:
+		return "Kontrak baru"
+	case StatementEntryBilled:
+		return "Tagihan"
+	case StatementEntryPenalty:
+		return "Denda"
+	case StatementEntryPayment:
+		return "Pembayaran"
+	case StatementEntryAdjustment:
+		return "Penyesuaian"
+	}
+	return entryType
+}
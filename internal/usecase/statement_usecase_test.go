@@ -0,0 +1,211 @@
+package usecase
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"main/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// MockStatementArchiveRepository for testing
+type MockStatementArchiveRepository struct {
+	archives []model.StatementArchive
+}
+
+func (m *MockStatementArchiveRepository) Create(archive *model.StatementArchive) error {
+	archive.ID = uint(len(m.archives) + 1)
+	m.archives = append(m.archives, *archive)
+	return nil
+}
+
+func (m *MockStatementArchiveRepository) GetByID(id uint) (*model.StatementArchive, error) {
+	for i := range m.archives {
+		if m.archives[i].ID == id {
+			archive := m.archives[i]
+			return &archive, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockStatementArchiveRepository) GetByConsumerID(consumerID uint) ([]model.StatementArchive, error) {
+	var archives []model.StatementArchive
+	for _, archive := range m.archives {
+		if archive.ConsumerID == consumerID {
+			archive.Content = nil
+			archives = append(archives, archive)
+		}
+	}
+	return archives, nil
+}
+
+func (m *MockStatementArchiveRepository) GetByHash(documentHash string) (*model.StatementArchive, error) {
+	for i := range m.archives {
+		if m.archives[i].DocumentHash == documentHash {
+			archive := m.archives[i]
+			archive.Content = nil
+			return &archive, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// newStatementFixture books the test contract on 1 Jan 2026 and pays the first installment with its
+// 50000 late fee on 10 Feb
+func newStatementFixture(t *testing.T) (StatementUsecase, *MockStatementArchiveRepository) {
+	f := newPaymentFixture()
+	f.installmentRepo.installments[0].LateFee = 50000
+	if _, err := f.uc.PostInstallmentPayment(1, InstallmentPaymentRequest{
+		Amount:    1500000,
+		Reference: "TRF-001",
+		PaidAt:    time.Date(2026, 2, 10, 9, 0, 0, 0, time.UTC),
+	}); err != nil {
+		t.Fatalf("Expected payment posted, got %v", err)
+	}
+
+	consumerRepo := NewMockConsumerRepository()
+	consumerRepo.Create(&model.Consumer{FullName: "Budi Santoso"})
+	archiveRepo := &MockStatementArchiveRepository{}
+	return NewStatementUsecase(consumerRepo, f.transactionRepo, f.installmentRepo, &MockLateFeeChargeRepository{}, archiveRepo, f.ledger), archiveRepo
+}
+
+func generateJSONStatement(t *testing.T, uc StatementUsecase, from, to time.Time) *AccountStatement {
+	document, err := uc.GenerateStatement(1, StatementRequest{From: from, To: to})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var statement AccountStatement
+	if err := json.Unmarshal(document.Content, &statement); err != nil {
+		t.Fatalf("Expected JSON statement, got %v", err)
+	}
+	return &statement
+}
+
+// Test: Balances roll from the booking month into the month of the first installment
+func TestGenerateStatement_Balances(t *testing.T) {
+	uc, _ := newStatementFixture(t)
+
+	january := generateJSONStatement(t, uc, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC))
+	if january.Totals.OpeningBalance != 0 || january.Totals.NewContracts != 3300000 || january.Totals.ClosingBalance != 3300000 {
+		t.Errorf("Expected contract booked at 3300000 in January, got %+v", january.Totals)
+	}
+
+	february := generateJSONStatement(t, uc, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC))
+	want := StatementTotals{
+		OpeningBalance:     3300000,
+		InstallmentsBilled: 1100000,
+		Payments:           1500000,
+		Penalties:          50000,
+		ClosingBalance:     1850000,
+	}
+	if february.Totals != want {
+		t.Errorf("Expected February totals %+v, got %+v", want, february.Totals)
+	}
+	if len(february.Contracts) != 1 || february.Contracts[0].ContractNumber != "CTR-20260101" {
+		t.Fatalf("Expected the test contract, got %+v", february.Contracts)
+	}
+
+	var types []string
+	for _, entry := range february.Contracts[0].Entries {
+		types = append(types, entry.Type)
+	}
+	if len(types) != 3 || types[0] != StatementEntryBilled || types[1] != StatementEntryPenalty || types[2] != StatementEntryPayment {
+		t.Errorf("Expected billed, penalty and payment in date order, got %v", types)
+	}
+
+	// Nothing moved and nothing is owed before the contract
+	december := generateJSONStatement(t, uc, time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC))
+	if len(december.Contracts) != 0 {
+		t.Errorf("Expected no contracts before the booking, got %d", len(december.Contracts))
+	}
+}
+
+// Test: Past periods show the late fee as the daily aging charged it then, not the fee owed today
+func TestGenerateStatement_LateFeeHistory(t *testing.T) {
+	f := newPaymentFixture()
+	chargeRepo := &MockLateFeeChargeRepository{}
+	aging := newTestDelinquency(f.transactionRepo, f.installmentRepo, chargeRepo, f.notifier)
+	aging.RunDailyAging(time.Date(2026, 2, 11, 0, 30, 0, 0, time.UTC)) // installment 1 10 days late
+	aging.RunDailyAging(time.Date(2026, 3, 5, 0, 30, 0, 0, time.UTC))  // installment 1 32 days, installment 2 4 days late
+
+	consumerRepo := NewMockConsumerRepository()
+	consumerRepo.Create(&model.Consumer{FullName: "Budi Santoso"})
+	uc := NewStatementUsecase(consumerRepo, f.transactionRepo, f.installmentRepo, chargeRepo, &MockStatementArchiveRepository{}, f.ledger)
+
+	february := generateJSONStatement(t, uc, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC))
+	if february.Totals.Penalties != 11000 || february.Totals.ClosingBalance != 3311000 || february.Totals.Adjustments != 0 {
+		t.Errorf("Expected 11000 late fee charged in February, got %+v", february.Totals)
+	}
+
+	march := generateJSONStatement(t, uc, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC))
+	if march.Totals.OpeningBalance != 3311000 || march.Totals.Penalties != 28600 || march.Totals.ClosingBalance != 3339600 {
+		t.Errorf("Expected February's balance carried and 28600 charged in March, got %+v", march.Totals)
+	}
+	for _, entry := range march.Contracts[0].Entries {
+		if entry.Type == StatementEntryPenalty && !entry.Date.Equal(time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("Expected the late fees dated on the aging run, got %s", entry.Date)
+		}
+	}
+}
+
+// Test: Generated documents are archived with their hash and can be verified byte for byte
+func TestGenerateStatement_Archive(t *testing.T) {
+	uc, archiveRepo := newStatementFixture(t)
+
+	document, err := uc.GenerateStatement(1, StatementRequest{
+		From:   time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		To:     time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
+		Format: "pdf",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !bytes.HasPrefix(document.Content, []byte("%PDF-")) || document.ContentType != "application/pdf" {
+		t.Errorf("Expected a PDF document, got %s", document.ContentType)
+	}
+
+	hash := sha256.Sum256(document.Content)
+	if document.Archive.ID == 0 || document.Archive.DocumentHash != hex.EncodeToString(hash[:]) {
+		t.Errorf("Expected the document archived with its SHA-256, got %+v", document.Archive)
+	}
+	if len(archiveRepo.archives) != 1 || !bytes.Equal(archiveRepo.archives[0].Content, document.Content) {
+		t.Error("Expected the exact bytes archived")
+	}
+
+	if archive, err := uc.VerifyStatement(document.Content); err != nil || archive.ID != document.Archive.ID {
+		t.Errorf("Expected the document verified against its archive, got %+v, %v", archive, err)
+	}
+	tampered := bytes.Replace(document.Content, []byte("1.850.000"), []byte("1.050.000"), 1)
+	if _, err := uc.VerifyStatement(tampered); err == nil {
+		t.Error("Expected error for a tampered document, got nil")
+	}
+}
+
+// Test: Periods, formats and contracts are validated
+func TestGenerateStatement_Validation(t *testing.T) {
+	uc, archiveRepo := newStatementFixture(t)
+	from := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)
+
+	if _, err := uc.GenerateStatement(1, StatementRequest{From: to, To: from}); err == nil {
+		t.Error("Expected error for a period ending before it starts, got nil")
+	}
+	if _, err := uc.GenerateStatement(1, StatementRequest{From: from, To: to, Format: "xml"}); err == nil {
+		t.Error("Expected error for an unknown format, got nil")
+	}
+	if _, err := uc.GenerateStatement(1, StatementRequest{From: from, To: to, TransactionID: 99}); err == nil {
+		t.Error("Expected error for a contract of another consumer, got nil")
+	}
+	if _, err := uc.GenerateStatement(2, StatementRequest{From: from, To: to}); err == nil {
+		t.Error("Expected error for an unknown consumer, got nil")
+	}
+	if len(archiveRepo.archives) != 0 {
+		t.Errorf("Expected nothing archived, got %d", len(archiveRepo.archives))
+	}
+}
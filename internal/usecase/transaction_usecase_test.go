@@ -0,0 +1,642 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"main/internal/fraud"
+	"main/internal/ledger"
+	"main/internal/model"
+	"main/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// MockTransactionRepository for testing
+type MockTransactionRepository struct {
+	transactions map[uint]*model.Transaction
+	nextID       uint
+}
+
+func NewMockTransactionRepository() *MockTransactionRepository {
+	return &MockTransactionRepository{
+		transactions: make(map[uint]*model.Transaction),
+		nextID:       1,
+	}
+}
+
+func (m *MockTransactionRepository) Create(transaction *model.Transaction) error {
+	transaction.ID = m.nextID
+	m.transactions[m.nextID] = transaction
+	m.nextID++
+	return nil
+}
+
+func (m *MockTransactionRepository) GetByID(id uint) (*model.Transaction, error) {
+	if transaction, exists := m.transactions[id]; exists {
+		return transaction, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockTransactionRepository) GetByIDForUpdate(id uint) (*model.Transaction, error) {
+	return m.GetByID(id)
+}
+
+func (m *MockTransactionRepository) GetByContractNumber(contractNumber string) (*model.Transaction, error) {
+	for _, transaction := range m.transactions {
+		if transaction.ContractNumber == contractNumber {
+			return transaction, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockTransactionRepository) GetByConsumerID(consumerID uint) ([]model.Transaction, error) {
+	return m.filter(func(tx *model.Transaction) bool { return tx.ConsumerID == consumerID }), nil
+}
+
+func (m *MockTransactionRepository) GetByConsumerSince(consumerID uint, since time.Time) ([]model.Transaction, error) {
+	return m.filter(func(tx *model.Transaction) bool {
+		return tx.ConsumerID == consumerID && !tx.CreatedAt.Before(since)
+	}), nil
+}
+
+func (m *MockTransactionRepository) GetByMerchantSince(merchantID uint, since time.Time) ([]model.Transaction, error) {
+	return m.filter(func(tx *model.Transaction) bool {
+		return tx.MerchantID == merchantID && !tx.CreatedAt.Before(since)
+	}), nil
+}
+
+func (m *MockTransactionRepository) GetByStatus(status string) ([]model.Transaction, error) {
+	return m.filter(func(tx *model.Transaction) bool { return tx.Status == status }), nil
+}
+
+func (m *MockTransactionRepository) GetMerchantActivatedBefore(before time.Time) ([]model.Transaction, error) {
+	return m.filter(func(tx *model.Transaction) bool {
+		return tx.MerchantID != 0 && tx.ActivatedAt != nil && tx.ActivatedAt.Before(before)
+	}), nil
+}
+
+func (m *MockTransactionRepository) CountByConsumerID(consumerID uint) (int64, error) {
+	transactions, _ := m.GetByConsumerID(consumerID)
+	return int64(len(transactions)), nil
+}
+
+func (m *MockTransactionRepository) Update(transaction *model.Transaction) error {
+	if _, exists := m.transactions[transaction.ID]; exists {
+		m.transactions[transaction.ID] = transaction
+		return nil
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (m *MockTransactionRepository) UpdateAging(transaction *model.Transaction) error {
+	stored, exists := m.transactions[transaction.ID]
+	if !exists {
+		return gorm.ErrRecordNotFound
+	}
+	stored.DaysPastDue = transaction.DaysPastDue
+	stored.Collectibility = transaction.Collectibility
+	stored.LateFeeAmount = transaction.LateFeeAmount
+	stored.Status = transaction.Status
+	stored.UpdatedAt = transaction.UpdatedAt
+	return nil
+}
+
+func (m *MockTransactionRepository) Delete(id uint) error {
+	delete(m.transactions, id)
+	return nil
+}
+
+func (m *MockTransactionRepository) filter(match func(*model.Transaction) bool) []model.Transaction {
+	var transactions []model.Transaction
+	for id := uint(1); id < m.nextID; id++ {
+		if tx, exists := m.transactions[id]; exists && match(tx) {
+			transactions = append(transactions, *tx)
+		}
+	}
+	return transactions
+}
+
+// MockFraudAssessmentRepository for testing
+type MockFraudAssessmentRepository struct {
+	assessments []*model.FraudAssessment
+}
+
+func (m *MockFraudAssessmentRepository) Create(assessment *model.FraudAssessment) error {
+	assessment.ID = uint(len(m.assessments) + 1)
+	m.assessments = append(m.assessments, assessment)
+	return nil
+}
+
+func (m *MockFraudAssessmentRepository) GetByTransactionID(transactionID uint) (*model.FraudAssessment, error) {
+	for _, assessment := range m.assessments {
+		if assessment.TransactionID == transactionID {
+			return assessment, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockFraudAssessmentRepository) Update(assessment *model.FraudAssessment) error {
+	return nil
+}
+
+// transactionFixture wires a transaction usecase around a verified consumer 1
+// holding a 10.000.000 limit on every tenor, with ACTIVE merchant 5
+type transactionFixture struct {
+	uc              TransactionUsecase
+	transactionRepo *MockTransactionRepository
+	limitRepo       *MockConsumerLimitRepository
+	consumerRepo    *MockConsumerRepository
+	assessmentRepo  *MockFraudAssessmentRepository
+	installmentRepo *MockInstallmentRepository
+	paymentRepo     *MockPaymentRepository
+	holdRepo        *MockLimitHoldRepository
+	merchantRepo    *MockMerchantRepository
+	ledger          LedgerUsecase
+	journalRepo     *MockJournalRepository
+	transactor      *MockTransactor
+	notifier        *MockEventNotifier
+	otp             OTPUsecase
+	otpRepo         *MockOTPChallengeRepository
+	otpSender       *MockOTPSender
+}
+
+func newTransactionFixture(t *testing.T) *transactionFixture {
+	return newTransactionFixtureWithPolicy(t, TransactionPolicy{})
+}
+
+// newTransactionFixtureWithPolicy is newTransactionFixture with what new contracts need before they activate
+func newTransactionFixtureWithPolicy(t *testing.T, policy TransactionPolicy) *transactionFixture {
+	f := &transactionFixture{
+		transactionRepo: NewMockTransactionRepository(),
+		limitRepo:       NewMockConsumerLimitRepository(),
+		consumerRepo:    newVerifiedConsumerRepository(),
+		assessmentRepo:  &MockFraudAssessmentRepository{},
+		installmentRepo: &MockInstallmentRepository{},
+		paymentRepo:     &MockPaymentRepository{},
+		holdRepo:        &MockLimitHoldRepository{},
+		merchantRepo:    NewMockMerchantRepository(),
+		notifier:        &MockEventNotifier{},
+		otpRepo:         &MockOTPChallengeRepository{},
+		otpSender:       &MockOTPSender{},
+	}
+	f.ledger, f.journalRepo = newTestLedger()
+	f.transactor = newTestTransactor(repository.Repositories{
+		Transactions:     f.transactionRepo,
+		Limits:           f.limitRepo,
+		Consumers:        f.consumerRepo,
+		Installments:     f.installmentRepo,
+		Payments:         f.paymentRepo,
+		LimitHolds:       f.holdRepo,
+		FraudAssessments: f.assessmentRepo,
+		Refunds:          &MockRefundRepository{},
+		Journal:          f.journalRepo,
+		OTPChallenges:    f.otpRepo,
+	})
+	f.merchantRepo.merchants[5] = &model.Merchant{ID: 5, Code: "DEALER-05", Status: model.MerchantStatusActive}
+
+	consumer, _ := f.consumerRepo.GetByID(1)
+	consumer.DateOfBirth = time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+	consumer.CreatedAt = time.Now().AddDate(-1, 0, 0)
+
+	for _, tenor := range []int{1, 2, 3, 6} {
+		f.limitRepo.Create(&model.ConsumerLimit{ConsumerID: 1, Tenor: tenor, LimitAmount: 10000000})
+	}
+
+	f.otp = NewOTPUsecase(f.otpRepo, f.consumerRepo, f.merchantRepo, f.otpSender, []byte("test-otp-key"), DefaultOTPPolicy())
+
+	engine, err := fraud.LoadEngine("")
+	if err != nil {
+		t.Fatalf("Expected fraud rules to load, got %v", err)
+	}
+
+	f.uc = NewTransactionUsecase(
+		f.transactionRepo,
+		f.limitRepo,
+		f.consumerRepo,
+		DefaultEligibilityRules(),
+		newTestWatchlist(f.consumerRepo),
+		engine,
+		f.assessmentRepo,
+		f.installmentRepo,
+		f.holdRepo,
+		newTestCatalogue(),
+		f.merchantRepo,
+		f.transactor,
+		f.notifier,
+		f.otp,
+		policy,
+	)
+	return f
+}
+
+func (f *transactionFixture) usedAmount(tenor int) float64 {
+	limit, _ := f.limitRepo.GetByConsumerAndTenor(1, tenor)
+	return limit.UsedAmount
+}
+
+// Test: Valid transaction deducts the limit and stores the assessment
+func TestCreateTransaction_Valid(t *testing.T) {
+	f := newTransactionFixture(t)
+
+	transaction := &model.Transaction{
+		ConsumerID:        1,
+		ContractNumber:    "CONT-001",
+		ProductCode:       "GADGET",
+		Tenor:             3,
+		OTR:               3000000,
+		InstallmentAmount: 1000000,
+		AssetName:         "Kulkas",
+	}
+
+	if err := f.uc.CreateTransaction(transaction); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if transaction.Status != model.TransactionStatusActive {
+		t.Errorf("Expected ACTIVE, got %s", transaction.Status)
+	}
+	if f.usedAmount(3) != 3000000 {
+		t.Errorf("Expected used amount 3000000, got %f", f.usedAmount(3))
+	}
+	if len(f.assessmentRepo.assessments) != 1 || f.assessmentRepo.assessments[0].TransactionID != transaction.ID {
+		t.Error("Expected fraud assessment linked to the transaction")
+	}
+	if transaction.InterestAmount != 180000 || transaction.AdminFee != 50000 || transaction.InstallmentAmount != 1060000 {
+		t.Errorf("Expected server-side pricing from GADGET, got interest %.2f admin fee %.2f installment %.2f",
+			transaction.InterestAmount, transaction.AdminFee, transaction.InstallmentAmount)
+	}
+	if transaction.ActivatedAt == nil {
+		t.Error("Expected the activation time recorded")
+	}
+	// Receivable of principal and interest, merchant owed the OTR; the admin fee waits for its receipt
+	if accountBalance(t, f.ledger, ledger.Receivable) != 3180000 || accountBalance(t, f.ledger, ledger.MerchantPayable) != 3000000 ||
+		accountBalance(t, f.ledger, ledger.AdminFeeIncome) != 0 {
+		t.Error("Expected activation entry posted to the ledger")
+	}
+	if len(f.notifier.events) != 1 || f.notifier.events[0] != "BOOKED CONT-001" {
+		t.Errorf("Expected booking notification, got %v", f.notifier.events)
+	}
+}
+
+// Test: Risk, aging, restructuring and activation fields sent by the client are ignored
+func TestCreateTransaction_IgnoresServerFields(t *testing.T) {
+	f := newTransactionFixtureWithPolicy(t, DefaultTransactionPolicy())
+
+	restructuredAt := time.Now().AddDate(0, -1, 0)
+	activatedAt := time.Now().AddDate(0, -2, 0)
+	transaction := &model.Transaction{
+		ConsumerID:       1,
+		ContractNumber:   "CONT-001",
+		ProductCode:      "GADGET",
+		Tenor:            3,
+		OTR:              3000000,
+		AssetName:        "Kulkas",
+		Status:           model.TransactionStatusCompleted,
+		RiskScore:        -100,
+		RiskDecision:     "ALLOW",
+		DaysPastDue:      200,
+		Collectibility:   5,
+		LateFeeAmount:    750000,
+		RestructureCount: 3,
+		RestructuredAt:   &restructuredAt,
+		ActivatedAt:      &activatedAt,
+	}
+
+	if err := f.uc.CreateTransaction(transaction); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if transaction.Status != model.TransactionStatusPendingSignature {
+		t.Errorf("Expected PENDING_SIGNATURE, got %s", transaction.Status)
+	}
+	if transaction.RiskScore != 0 || transaction.RiskDecision != "ALLOW" {
+		t.Errorf("Expected the server assessment, got score %d decision %s", transaction.RiskScore, transaction.RiskDecision)
+	}
+	if transaction.DaysPastDue != 0 || transaction.Collectibility != 1 || transaction.LateFeeAmount != 0 {
+		t.Errorf("Expected a current contract, got DPD %d grade %d late fee %.2f",
+			transaction.DaysPastDue, transaction.Collectibility, transaction.LateFeeAmount)
+	}
+	if transaction.RestructureCount != 0 || transaction.RestructuredAt != nil || transaction.ActivatedAt != nil {
+		t.Error("Expected no restructuring or activation on an unsigned contract")
+	}
+	if f.usedAmount(3) != 3000000 {
+		t.Errorf("Expected used amount 3000000, got %f", f.usedAmount(3))
+	}
+}
+
+// Test: A booking whose activation entry cannot be posted fails as one unit of work and is not announced
+func TestCreateTransaction_LedgerFailure(t *testing.T) {
+	f := newTransactionFixture(t)
+	f.journalRepo.createErr = errors.New("journal unavailable")
+
+	transaction := &model.Transaction{
+		ConsumerID:     1,
+		ContractNumber: "CONT-001",
+		ProductCode:    "GADGET",
+		Tenor:          3,
+		OTR:            3000000,
+		AssetName:      "Kulkas",
+	}
+	if err := f.uc.CreateTransaction(transaction); err == nil {
+		t.Fatal("Expected the booking to fail when the ledger rejects the activation entry")
+	}
+	if f.transactor.failed != 1 {
+		t.Errorf("Expected the booking rolled back as one database transaction, got %d failed units", f.transactor.failed)
+	}
+	if len(f.notifier.events) != 0 {
+		t.Errorf("Expected no notification for a rolled back booking, got %v", f.notifier.events)
+	}
+}
+
+// Test: With signatures required the limit is reserved but nothing is collected or posted until the contract is signed
+func TestCreateTransaction_PendingSignature(t *testing.T) {
+	f := newTransactionFixtureWithPolicy(t, DefaultTransactionPolicy())
+
+	transaction := &model.Transaction{
+		ConsumerID:     1,
+		ContractNumber: "CONT-SIGN",
+		ProductCode:    "GADGET",
+		Tenor:          3,
+		OTR:            3000000,
+		AssetName:      "Kulkas",
+	}
+	if err := f.uc.CreateTransaction(transaction); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if transaction.Status != model.TransactionStatusPendingSignature {
+		t.Errorf("Expected PENDING_SIGNATURE, got %s", transaction.Status)
+	}
+	if f.usedAmount(3) != 3000000 {
+		t.Errorf("Expected the limit reserved, got used amount %.2f", f.usedAmount(3))
+	}
+	if len(f.paymentRepo.payments) != 0 || accountBalance(t, f.ledger, ledger.Receivable) != 0 || len(f.notifier.events) != 0 {
+		t.Error("Expected no upfront payments, ledger entry or notification before signing")
+	}
+	if err := f.uc.UpdateTransactionStatus(transaction.ID, model.TransactionStatusActive); err == nil {
+		t.Error("Expected manual activation of an unsigned contract rejected")
+	}
+
+	activated, err := f.uc.ActivateSignedTransaction(transaction.ID)
+	if err != nil {
+		t.Fatalf("Expected activation, got %v", err)
+	}
+	if activated.Status != model.TransactionStatusActive || accountBalance(t, f.ledger, ledger.Receivable) != 3180000 {
+		t.Errorf("Expected ACTIVE with the activation entry posted, got %s", activated.Status)
+	}
+	if len(f.notifier.events) != 1 || f.notifier.events[0] != "BOOKED CONT-SIGN" {
+		t.Errorf("Expected booking notification on activation, got %v", f.notifier.events)
+	}
+	if _, err := f.uc.ActivateSignedTransaction(transaction.ID); err == nil {
+		t.Error("Expected a second activation rejected")
+	}
+}
+
+// Test: With OTP consent required the limit is only used with a challenge for this consumer, merchant and amount
+func TestCreateTransaction_RequireOTP(t *testing.T) {
+	f := newTransactionFixtureWithPolicy(t, TransactionPolicy{RequireOTP: true})
+	newTransaction := func(number string) *model.Transaction {
+		return &model.Transaction{
+			ConsumerID:     1,
+			ContractNumber: number,
+			ProductCode:    "GADGET",
+			Tenor:          3,
+			OTR:            3500000,
+			DownPayment:    500000,
+			MerchantID:     5,
+		}
+	}
+
+	if err := f.uc.CreateTransaction(newTransaction("CONT-OTP-1")); !errors.Is(err, ErrOTPRequired) {
+		t.Errorf("Expected ErrOTPRequired without a challenge, got %v", err)
+	}
+	if f.usedAmount(3) != 0 {
+		t.Errorf("Expected the limit untouched, got used amount %.2f", f.usedAmount(3))
+	}
+
+	challenge, err := f.otp.CreateChallenge(OTPChallengeRequest{ConsumerID: 1, MerchantID: 5, Amount: 3000000})
+	if err != nil {
+		t.Fatalf("Expected challenge created, got %v", err)
+	}
+	transaction := newTransaction("CONT-OTP-1")
+	transaction.OTPChallengeID = challenge.ID
+	transaction.OTPCode = f.otpSender.lastCode()
+	if err := f.uc.CreateTransaction(transaction); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if transaction.OTPCode != "" || transaction.OTPChallengeID != challenge.ID || f.usedAmount(3) != 3000000 {
+		t.Errorf("Expected the consent kept without the code and the limit used, got %+v", transaction)
+	}
+
+	replay := newTransaction("CONT-OTP-2")
+	replay.OTPChallengeID = challenge.ID
+	replay.OTPCode = f.otpSender.lastCode()
+	if err := f.uc.CreateTransaction(replay); !errors.Is(err, ErrOTPNotUsable) {
+		t.Errorf("Expected a used challenge rejected, got %v", err)
+	}
+	if f.usedAmount(3) != 3000000 {
+		t.Errorf("Expected no second deduction, got used amount %.2f", f.usedAmount(3))
+	}
+}
+
+// Test: A booking rolled back leaves its OTP challenge usable for the retry
+func TestCreateTransaction_RequireOTPRollback(t *testing.T) {
+	f := newTransactionFixtureWithPolicy(t, TransactionPolicy{RequireOTP: true})
+	challenge, err := f.otp.CreateChallenge(OTPChallengeRequest{ConsumerID: 1, MerchantID: 5, Amount: 3000000})
+	if err != nil {
+		t.Fatalf("Expected challenge created, got %v", err)
+	}
+	newTransaction := func(number string) *model.Transaction {
+		return &model.Transaction{
+			ConsumerID:     1,
+			ContractNumber: number,
+			ProductCode:    "GADGET",
+			Tenor:          3,
+			OTR:            3000000,
+			MerchantID:     5,
+			OTPChallengeID: challenge.ID,
+			OTPCode:        f.otpSender.lastCode(),
+		}
+	}
+
+	f.journalRepo.createErr = errors.New("journal unavailable")
+	if err := f.uc.CreateTransaction(newTransaction("CONT-OTP-1")); err == nil {
+		t.Fatal("Expected the booking to fail when the ledger rejects the activation entry")
+	}
+	if stored, _ := f.otpRepo.GetByID(challenge.ID); stored.Status != model.OTPStatusPending {
+		t.Errorf("Expected the challenge still PENDING after the rollback, got %s", stored.Status)
+	}
+
+	// The in-memory repositories keep the failed contract, so the retry books under another number
+	f.journalRepo.createErr = nil
+	if err := f.uc.CreateTransaction(newTransaction("CONT-OTP-2")); err != nil {
+		t.Fatalf("Expected the retry booked with the same code, got %v", err)
+	}
+	if stored, _ := f.otpRepo.GetByID(challenge.ID); stored.Status != model.OTPStatusConsumed || stored.Reference != "CONT-OTP-2" {
+		t.Errorf("Expected the challenge consumed by CONT-OTP-2, got %+v", stored)
+	}
+}
+
+// Test: Only the financed amount is booked and amortized, and the contract waits for the down payment
+func TestCreateTransaction_DownPayment(t *testing.T) {
+	f := newTransactionFixture(t)
+
+	transaction := &model.Transaction{
+		ConsumerID:     1,
+		ContractNumber: "CONT-DP-001",
+		ProductCode:    "GADGET",
+		Tenor:          3,
+		OTR:            3000000,
+		DownPayment:    600000,
+	}
+	if err := f.uc.CreateTransaction(transaction); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if f.usedAmount(3) != 2400000 {
+		t.Errorf("Expected used amount 2400000, got %f", f.usedAmount(3))
+	}
+	// 2.400.000 x 2% x 3 months
+	if transaction.InterestAmount != 144000 || transaction.InstallmentAmount != 848000 {
+		t.Errorf("Expected interest 144000 and installment 848000, got %.2f and %.2f", transaction.InterestAmount, transaction.InstallmentAmount)
+	}
+
+	principal := 0.0
+	for _, installment := range f.installmentRepo.installments {
+		principal += installment.PrincipalAmount
+	}
+	if principal != 2400000 {
+		t.Errorf("Expected amortized principal 2400000, got %.2f", principal)
+	}
+
+	// Nothing is received at booking: the contract waits for the down payment receipt
+	if transaction.Status != model.TransactionStatusPendingDownPayment {
+		t.Errorf("Expected PENDING_DOWN_PAYMENT, got %s", transaction.Status)
+	}
+	if len(f.paymentRepo.payments) != 0 || accountBalance(t, f.ledger, ledger.Receivable) != 0 {
+		t.Errorf("Expected no payment and no activation entry before the receipt, got %+v", f.paymentRepo.payments)
+	}
+
+	err := f.uc.CreateTransaction(&model.Transaction{ConsumerID: 1, ContractNumber: "CONT-DP-002", ProductCode: "GADGET", Tenor: 3, OTR: 1000000, DownPayment: 1000000})
+	if err == nil {
+		t.Error("Expected error for a down payment covering the whole OTR, got nil")
+	}
+}
+
+// Test: Manual completion is refused while installments are outstanding
+func TestUpdateTransactionStatus_CompletedWithOutstanding(t *testing.T) {
+	f := newTransactionFixture(t)
+
+	transaction := &model.Transaction{
+		ConsumerID:        1,
+		ContractNumber:    "CONT-001",
+		ProductCode:       "GADGET",
+		Tenor:             3,
+		OTR:               3000000,
+		InstallmentAmount: 1000000,
+	}
+	if err := f.uc.CreateTransaction(transaction); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(f.installmentRepo.installments) != 3 {
+		t.Fatalf("Expected 3 installments scheduled, got %d", len(f.installmentRepo.installments))
+	}
+
+	if err := f.uc.UpdateTransactionStatus(transaction.ID, model.TransactionStatusCompleted); err == nil {
+		t.Error("Expected error completing a contract with outstanding installments, got nil")
+	}
+
+	for _, installment := range f.installmentRepo.installments {
+		installment.PaidAmount = installment.Amount
+	}
+	// A late fee charged by the aging job must be paid before the contract closes
+	f.installmentRepo.installments[0].LateFee = 11000
+	if err := f.uc.UpdateTransactionStatus(transaction.ID, model.TransactionStatusCompleted); err == nil {
+		t.Error("Expected error completing a contract with an unpaid late fee, got nil")
+	}
+	if f.usedAmount(3) != 3000000 {
+		t.Errorf("Expected the limit kept while the late fee is owed, got used amount %.2f", f.usedAmount(3))
+	}
+
+	f.installmentRepo.installments[0].LateFeePaid = 11000
+	if err := f.uc.UpdateTransactionStatus(transaction.ID, model.TransactionStatusCompleted); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if f.usedAmount(3) != 0 {
+		t.Errorf("Expected limit released on completion, got used amount %f", f.usedAmount(3))
+	}
+}
+
+// Test: ACTIVE and DEFAULTED are left to the aging job
+func TestUpdateTransactionStatus_AgingOwned(t *testing.T) {
+	f := newTransactionFixture(t)
+
+	transaction := &model.Transaction{ConsumerID: 1, ContractNumber: "CONT-001", ProductCode: "GADGET", Tenor: 3, OTR: 3000000}
+	if err := f.uc.CreateTransaction(transaction); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, status := range []string{model.TransactionStatusDefaulted, model.TransactionStatusActive} {
+		if err := f.uc.UpdateTransactionStatus(transaction.ID, status); err == nil {
+			t.Errorf("Expected manual %s refused, got nil", status)
+		}
+	}
+	if transaction.Status != model.TransactionStatusActive {
+		t.Errorf("Expected the contract left ACTIVE, got %s", transaction.Status)
+	}
+}
+
+// Test: Rapid transactions are held for review and a rejected review releases the limit
+func TestCreateTransaction_FraudReview(t *testing.T) {
+	f := newTransactionFixture(t)
+
+	var last *model.Transaction
+	for i, tenor := range []int{1, 2, 3} {
+		last = &model.Transaction{
+			ConsumerID:        1,
+			ContractNumber:    "CONT-BURST-" + string(rune('A'+i)),
+			ProductCode:       "GADGET",
+			Tenor:             tenor,
+			OTR:               1000000,
+			InstallmentAmount: 1000000,
+		}
+		if err := f.uc.CreateTransaction(last); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	if last.Status != model.TransactionStatusPendingReview {
+		t.Fatalf("Expected PENDING_REVIEW for the third transaction in a minute, got %s", last.Status)
+	}
+
+	if _, err := f.uc.ReviewFlaggedTransaction(last.ID, false, "fraud-analyst"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if last.Status != model.TransactionStatusRejected || f.usedAmount(3) != 0 {
+		t.Errorf("Expected REJECTED with released limit, got %s / %f", last.Status, f.usedAmount(3))
+	}
+	// Only the two contracts that activated reach the books
+	if accountBalance(t, f.ledger, ledger.MerchantPayable) != 2000000 {
+		t.Errorf("Expected merchant payable 2000000, got %.2f", accountBalance(t, f.ledger, ledger.MerchantPayable))
+	}
+	if events := f.notifier.events; len(events) != 3 || events[2] != "STATUS CONT-BURST-C PENDING_REVIEW->REJECTED" {
+		t.Errorf("Expected two bookings and the rejection notified, got %v", events)
+	}
+}
+
+// Test: Transactions are refused for consumers without a verified KYC
+func TestCreateTransaction_ConsumerNotVerified(t *testing.T) {
+	f := newTransactionFixture(t)
+	consumer, _ := f.consumerRepo.GetByID(1)
+	consumer.KYCStatus = model.KYCStatusPending
+
+	err := f.uc.CreateTransaction(&model.Transaction{ConsumerID: 1, ContractNumber: "CONT-001", ProductCode: "GADGET", Tenor: 1, OTR: 100000})
+	if err != ErrConsumerNotVerified {
+		t.Errorf("Expected ErrConsumerNotVerified, got %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"main/internal/model"
+	"main/internal/repository"
+)
+
+// UpfrontPaymentRequest is the receipt of money the consumer pays before the contract runs
+type UpfrontPaymentRequest struct {
+	Type      string    `json:"type"` // ADMIN_FEE, DOWN_PAYMENT
+	Amount    float64   `json:"amount"`
+	Reference string    `json:"reference"` // cashier receipt or transfer reference
+	PaidAt    time.Time `json:"paid_at"`
+}
+
+// RecordUpfrontPayment records an admin fee or down payment receipt for a contract. Only money actually
+// received is recorded, so a cancellation never refunds more than the consumer paid. The admin fee is
+// booked as income; the down payment is kept by the merchant and activates a contract waiting for it
+// once the receipts cover it. A reference already recorded for the same contract and type returns the
+// earlier payment unchanged.
+func (u *transactionUsecase) RecordUpfrontPayment(id uint, req UpfrontPaymentRequest) (*model.Payment, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	req.Type = strings.ToUpper(strings.TrimSpace(req.Type))
+	if req.Type != model.PaymentTypeAdminFee && req.Type != model.PaymentTypeDownPayment {
+		return nil, errors.New("jenis pembayaran di muka harus ADMIN_FEE atau DOWN_PAYMENT")
+	}
+	req.Reference = strings.TrimSpace(req.Reference)
+	if req.Reference == "" {
+		return nil, errors.New("referensi pembayaran wajib diisi")
+	}
+	req.Amount = roundMoney(req.Amount)
+	if req.Amount <= 0 {
+		return nil, errors.New("jumlah pembayaran harus lebih dari 0")
+	}
+
+	transaction, err := u.transactionRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("transaksi tidak ditemukan")
+	}
+	if transaction.Status != model.TransactionStatusActive && transaction.Status != model.TransactionStatusPendingReview &&
+		transaction.Status != model.TransactionStatusPendingSignature && transaction.Status != model.TransactionStatusPendingDownPayment {
+		return nil, fmt.Errorf("transaksi berstatus %s tidak menerima pembayaran di muka", transaction.Status)
+	}
+	previousStatus := transaction.Status
+
+	now := time.Now()
+	if req.PaidAt.IsZero() {
+		req.PaidAt = now
+	}
+	payment := &model.Payment{
+		TransactionID: transaction.ID,
+		ConsumerID:    transaction.ConsumerID,
+		Type:          req.Type,
+		Amount:        req.Amount,
+		Reference:     req.Reference,
+		PaidAt:        req.PaidAt,
+		CreatedAt:     now,
+	}
+
+	duplicate := false
+	err = u.transactor.Transaction(func(tx *repository.Repositories) error {
+		if existing, err := tx.Payments.GetByReference(req.Reference); err == nil {
+			if existing.TransactionID != transaction.ID || existing.Type != req.Type {
+				return fmt.Errorf("referensi pembayaran %s sudah dipakai", req.Reference)
+			}
+			payment, duplicate = existing, true
+			return nil
+		}
+
+		received, err := upfrontReceived(tx.Payments, transaction.ID, req.Type)
+		if err != nil {
+			return err
+		}
+
+		if req.Type == model.PaymentTypeDownPayment {
+			if remaining := roundMoney(transaction.DownPayment - received); req.Amount > remaining {
+				return fmt.Errorf("jumlah pembayaran melebihi sisa DP (Rp %.2f)", remaining)
+			}
+			if err := tx.Payments.Create(payment); err != nil {
+				return err
+			}
+			if transaction.Status != model.TransactionStatusPendingDownPayment {
+				return nil
+			}
+			transaction.UpdatedAt = now
+			if err := u.activate(tx, transaction); err != nil {
+				return err
+			}
+			return tx.Transactions.Update(transaction)
+		}
+
+		// A partial cancellation lowers the admin fee by the share it refunded
+		refunds, err := tx.Refunds.GetByTransactionID(transaction.ID)
+		if err != nil {
+			return err
+		}
+		for _, refund := range refunds {
+			received -= refund.Amount
+		}
+		if remaining := roundMoney(transaction.AdminFee - received); req.Amount > remaining {
+			return fmt.Errorf("jumlah pembayaran melebihi sisa biaya admin (Rp %.2f)", remaining)
+		}
+
+		if err := tx.Payments.Create(payment); err != nil {
+			return err
+		}
+		return ledgerIn(tx).Post(adminFeeEntry(transaction, payment))
+	})
+	if err != nil {
+		return nil, err
+	}
+	if duplicate {
+		log.Printf("⚠ Pembayaran %s sudah dicatat, dilewati\n", req.Reference)
+		return payment, nil
+	}
+
+	if previousStatus != transaction.Status {
+		u.notifier.NotifyTransactionBooked(transaction)
+	}
+
+	log.Printf("✓ Pembayaran %s kontrak %s diterima: Rp %.2f (%s)\n", payment.Type, transaction.ContractNumber, payment.Amount, payment.Reference)
+	return payment, nil
+}
+
+// upfrontReceived sums the payments of a type recorded for the contract
+func upfrontReceived(paymentRepo repository.PaymentRepository, transactionID uint, paymentType string) (float64, error) {
+	payments, err := paymentRepo.GetByTransactionID(transactionID)
+	if err != nil {
+		return 0, err
+	}
+	total := 0.0
+	for _, payment := range payments {
+		if payment.Type == paymentType {
+			total += payment.Amount
+		}
+	}
+	return roundMoney(total), nil
+}
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"testing"
+
+	"main/internal/ledger"
+	"main/internal/model"
+)
+
+// bookUpfrontTestContract books a 3.000.000 GADGET contract priced with a 50.000 admin fee
+func bookUpfrontTestContract(t *testing.T, f *transactionFixture) *model.Transaction {
+	t.Helper()
+	transaction := &model.Transaction{
+		ConsumerID:     1,
+		ContractNumber: "CONT-001",
+		ProductCode:    "GADGET",
+		Tenor:          3,
+		OTR:            3000000,
+		AssetName:      "Kulkas",
+	}
+	if err := f.uc.CreateTransaction(transaction); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return transaction
+}
+
+// Test: The admin fee is booked as income only when its receipt is recorded
+func TestRecordUpfrontPayment_AdminFee(t *testing.T) {
+	f := newTransactionFixture(t)
+	transaction := bookUpfrontTestContract(t, f)
+
+	payment, err := f.uc.RecordUpfrontPayment(transaction.ID, UpfrontPaymentRequest{Type: "admin_fee", Amount: 50000, Reference: "KSR-001"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if payment.Type != model.PaymentTypeAdminFee || payment.Amount != 50000 || payment.Reference != "KSR-001" {
+		t.Errorf("Expected admin fee receipt KSR-001 of 50000, got %+v", payment)
+	}
+	if accountBalance(t, f.ledger, ledger.AdminFeeIncome) != 50000 || accountBalance(t, f.ledger, ledger.Cash) != 50000 {
+		t.Error("Expected the admin fee receipt posted to cash and admin fee income")
+	}
+}
+
+// Test: Receipts are capped at the admin fee of the contract
+func TestRecordUpfrontPayment_OverAdminFee(t *testing.T) {
+	f := newTransactionFixture(t)
+	transaction := bookUpfrontTestContract(t, f)
+
+	if _, err := f.uc.RecordUpfrontPayment(transaction.ID, UpfrontPaymentRequest{Type: model.PaymentTypeAdminFee, Amount: 30000, Reference: "KSR-001"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := f.uc.RecordUpfrontPayment(transaction.ID, UpfrontPaymentRequest{Type: model.PaymentTypeAdminFee, Amount: 30000, Reference: "KSR-002"}); err == nil {
+		t.Error("Expected error for receipts above the admin fee, got nil")
+	}
+	if accountBalance(t, f.ledger, ledger.AdminFeeIncome) != 30000 {
+		t.Errorf("Expected only the first receipt booked, got %.2f", accountBalance(t, f.ledger, ledger.AdminFeeIncome))
+	}
+}
+
+// Test: Recording the same reference twice returns the earlier receipt without booking it again
+func TestRecordUpfrontPayment_DuplicateReference(t *testing.T) {
+	f := newTransactionFixture(t)
+	transaction := bookUpfrontTestContract(t, f)
+
+	req := UpfrontPaymentRequest{Type: model.PaymentTypeAdminFee, Amount: 50000, Reference: "KSR-001"}
+	first, err := f.uc.RecordUpfrontPayment(transaction.ID, req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	second, err := f.uc.RecordUpfrontPayment(transaction.ID, req)
+	if err != nil {
+		t.Fatalf("Expected the repeated receipt accepted, got %v", err)
+	}
+	if second.ID != first.ID {
+		t.Errorf("Expected the earlier payment %d returned, got %d", first.ID, second.ID)
+	}
+	if accountBalance(t, f.ledger, ledger.AdminFeeIncome) != 50000 {
+		t.Errorf("Expected the admin fee booked once, got %.2f", accountBalance(t, f.ledger, ledger.AdminFeeIncome))
+	}
+}
+
+// Test: Only admin fee receipts with a reference are accepted
+func TestRecordUpfrontPayment_Invalid(t *testing.T) {
+	f := newTransactionFixture(t)
+	transaction := bookUpfrontTestContract(t, f)
+
+	if _, err := f.uc.RecordUpfrontPayment(transaction.ID, UpfrontPaymentRequest{Type: model.PaymentTypeInstallment, Amount: 50000, Reference: "KSR-001"}); err == nil {
+		t.Error("Expected error for an installment payment, got nil")
+	}
+	if _, err := f.uc.RecordUpfrontPayment(transaction.ID, UpfrontPaymentRequest{Type: model.PaymentTypeAdminFee, Amount: 50000}); err == nil {
+		t.Error("Expected error for a receipt without reference, got nil")
+	}
+	if _, err := f.uc.RecordUpfrontPayment(99, UpfrontPaymentRequest{Type: model.PaymentTypeAdminFee, Amount: 50000, Reference: "KSR-001"}); err == nil {
+		t.Error("Expected error for an unknown transaction, got nil")
+	}
+}
+
+// bookDownPaymentTestContract books a 3.000.000 GADGET contract with a 600.000 down payment
+func bookDownPaymentTestContract(t *testing.T, f *transactionFixture) *model.Transaction {
+	t.Helper()
+	transaction := &model.Transaction{
+		ConsumerID:     1,
+		ContractNumber: "CONT-DP-001",
+		ProductCode:    "GADGET",
+		Tenor:          3,
+		OTR:            3000000,
+		DownPayment:    600000,
+	}
+	if err := f.uc.CreateTransaction(transaction); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if transaction.Status != model.TransactionStatusPendingDownPayment {
+		t.Fatalf("Expected PENDING_DOWN_PAYMENT, got %s", transaction.Status)
+	}
+	return transaction
+}
+
+// Test: The contract activates once the down payment receipts cover the down payment
+func TestRecordUpfrontPayment_DownPaymentActivates(t *testing.T) {
+	f := newTransactionFixture(t)
+	transaction := bookDownPaymentTestContract(t, f)
+
+	if _, err := f.uc.RecordUpfrontPayment(transaction.ID, UpfrontPaymentRequest{Type: model.PaymentTypeDownPayment, Amount: 400000, Reference: "DLR-001"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if transaction.Status != model.TransactionStatusPendingDownPayment || accountBalance(t, f.ledger, ledger.Receivable) != 0 {
+		t.Errorf("Expected the contract still waiting after a partial receipt, got %s", transaction.Status)
+	}
+
+	if _, err := f.uc.RecordUpfrontPayment(transaction.ID, UpfrontPaymentRequest{Type: model.PaymentTypeDownPayment, Amount: 200000, Reference: "DLR-002"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if transaction.Status != model.TransactionStatusActive {
+		t.Errorf("Expected ACTIVE once the down payment is received, got %s", transaction.Status)
+	}
+	// The merchant keeps the down payment, only the financed amount is booked
+	if accountBalance(t, f.ledger, ledger.Receivable) != 2544000 || accountBalance(t, f.ledger, ledger.MerchantPayable) != 2400000 ||
+		accountBalance(t, f.ledger, ledger.Cash) != 0 {
+		t.Error("Expected the activation entry posted for the financed amount")
+	}
+	if len(f.notifier.events) != 1 || f.notifier.events[0] != "BOOKED CONT-DP-001" {
+		t.Errorf("Expected booking notification on activation, got %v", f.notifier.events)
+	}
+
+	if _, err := f.uc.RecordUpfrontPayment(transaction.ID, UpfrontPaymentRequest{Type: model.PaymentTypeDownPayment, Amount: 1, Reference: "DLR-003"}); err == nil {
+		t.Error("Expected error for receipts above the down payment, got nil")
+	}
+}
+
+// Test: A contract waiting for the down payment cannot be activated by hand
+func TestUpdateTransactionStatus_PendingDownPayment(t *testing.T) {
+	f := newTransactionFixture(t)
+	transaction := bookDownPaymentTestContract(t, f)
+
+	if err := f.uc.UpdateTransactionStatus(transaction.ID, model.TransactionStatusActive); err == nil {
+		t.Error("Expected error activating a contract without its down payment receipt, got nil")
+	}
+}
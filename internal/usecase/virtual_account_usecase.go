@@ -0,0 +1,412 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"main/internal/model"
+	"main/internal/repository"
+	"main/internal/va"
+)
+
+var ErrUnknownBank = errors.New("bank virtual account tidak dikenal")
+
+// VirtualAccountPolicy holds how bank payment callbacks are accepted
+type VirtualAccountPolicy struct {
+	CallbackTolerance time.Duration // maximum clock difference between the callback timestamp and now
+	StatementDateDays int           // days between a callback and a statement line already posted for both to be the same money
+}
+
+// DefaultVirtualAccountPolicy accepts callbacks signed within 5 minutes of receipt and links them to
+// statement lines within a day
+func DefaultVirtualAccountPolicy() VirtualAccountPolicy {
+	return VirtualAccountPolicy{
+		CallbackTolerance: 5 * time.Minute,
+		StatementDateDays: 1,
+	}
+}
+
+// Suspense resolution actions
+const (
+	SuspenseActionPost   = "POST"   // apply the money to a contract
+	SuspenseActionRefund = "REFUND" // return the money to the sender
+)
+
+// SuspenseResolutionRequest is an operator's decision on a callback held in suspense
+type SuspenseResolutionRequest struct {
+	Action        string `json:"action"`         // POST, REFUND
+	TransactionID uint   `json:"transaction_id"` // contract to post to, defaults to the contract of the virtual account
+	ResolvedBy    string `json:"resolved_by"`
+	Note          string `json:"note"`
+}
+
+// VirtualAccountUsecase defines all business logic operations for virtual account payments
+type VirtualAccountUsecase interface {
+	IssueVirtualAccount(transactionID uint, bankCode string) (*model.VirtualAccount, error)
+	GetVirtualAccounts(transactionID uint) ([]model.VirtualAccount, error)
+	HandleCallback(bankCode, timestamp, signature string, body []byte) (*model.PaymentCallback, error)
+	GetSuspense() ([]model.PaymentCallback, error)
+	ResolveSuspense(callbackID uint, req SuspenseResolutionRequest) (*model.PaymentCallback, error)
+}
+
+// virtualAccountUsecase is the implementation of VirtualAccountUsecase
+type virtualAccountUsecase struct {
+	transactionRepo repository.TransactionRepository
+	accountRepo     repository.VirtualAccountRepository
+	callbackRepo    repository.PaymentCallbackRepository
+	payments        PaymentPoster
+	transactor      repository.Transactor
+	banks           map[string]va.Bank
+	policy          VirtualAccountPolicy
+	mu              sync.Mutex
+}
+
+// NewVirtualAccountUsecase creates a new instance of VirtualAccountUsecase for the banks keyed by bank code
+func NewVirtualAccountUsecase(
+	transactionRepo repository.TransactionRepository,
+	accountRepo repository.VirtualAccountRepository,
+	callbackRepo repository.PaymentCallbackRepository,
+	payments PaymentPoster,
+	transactor repository.Transactor,
+	banks map[string]va.Bank,
+	policy VirtualAccountPolicy,
+) VirtualAccountUsecase {
+	return &virtualAccountUsecase{
+		transactionRepo: transactionRepo,
+		accountRepo:     accountRepo,
+		callbackRepo:    callbackRepo,
+		payments:        payments,
+		transactor:      transactor,
+		banks:           banks,
+		policy:          policy,
+	}
+}
+
+// IssueVirtualAccount returns the virtual account of the contract at the bank, creating it on first request
+func (u *virtualAccountUsecase) IssueVirtualAccount(transactionID uint, bankCode string) (*model.VirtualAccount, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	bank, err := u.bank(bankCode)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, err := u.transactionRepo.GetByID(transactionID)
+	if err != nil {
+		return nil, errors.New("transaksi tidak ditemukan")
+	}
+	if transaction.Status != model.TransactionStatusActive && transaction.Status != model.TransactionStatusDefaulted {
+		return nil, fmt.Errorf("transaksi berstatus %s tidak menerima pembayaran angsuran", transaction.Status)
+	}
+
+	accounts, err := u.accountRepo.GetByTransactionID(transaction.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range accounts {
+		if accounts[i].BankCode == bank.Code && accounts[i].Status == model.VirtualAccountActive {
+			return &accounts[i], nil
+		}
+	}
+
+	number, err := va.Number(bank.Prefix, transaction.ID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	account := &model.VirtualAccount{
+		TransactionID: transaction.ID,
+		ConsumerID:    transaction.ConsumerID,
+		BankCode:      bank.Code,
+		VANumber:      number,
+		Status:        model.VirtualAccountActive,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := u.accountRepo.Create(account); err != nil {
+		return nil, err
+	}
+
+	log.Printf("✓ VA %s %s diterbitkan untuk kontrak %s\n", bank.Code, number, transaction.ContractNumber)
+	return account, nil
+}
+
+func (u *virtualAccountUsecase) GetVirtualAccounts(transactionID uint) ([]model.VirtualAccount, error) {
+	if _, err := u.transactionRepo.GetByID(transactionID); err != nil {
+		return nil, errors.New("transaksi tidak ditemukan")
+	}
+	return u.accountRepo.GetByTransactionID(transactionID)
+}
+
+// HandleCallback verifies a bank payment notification and posts it to the contract of the virtual account.
+// A payment ID the bank already sent returns the stored callback, so banks can retry safely. Money a
+// statement import already posted is linked to that payment. Money that cannot be applied is held in
+// suspense for an operator.
+func (u *virtualAccountUsecase) HandleCallback(bankCode, timestamp, signature string, body []byte) (*model.PaymentCallback, error) {
+	bank, err := u.bank(bankCode)
+	if err != nil {
+		return nil, err
+	}
+	if err := va.Verify(bank.Secret, timestamp, signature, body, time.Now(), u.policy.CallbackTolerance); err != nil {
+		log.Printf("⚠ Callback %s ditolak: %v\n", bank.Code, err)
+		return nil, err
+	}
+	notice, err := va.ParseCallback(body)
+	if err != nil {
+		return nil, err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if existing, err := u.callbackRepo.GetByExternalID(bank.Code, notice.PaymentID); err == nil {
+		log.Printf("⚠ Callback %s %s sudah diterima, dilewati\n", bank.Code, notice.PaymentID)
+		if existing.Status == model.PaymentCallbackSuspense {
+			// The suspense entry is posted once per reference; a retry completes an earlier failed posting
+			err := u.transactor.Transaction(func(tx *repository.Repositories) error {
+				return ledgerIn(tx).Post(suspenseEntry(existing))
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		return existing, nil
+	}
+
+	now := time.Now()
+	callback := &model.PaymentCallback{
+		BankCode:   bank.Code,
+		ExternalID: notice.PaymentID,
+		VANumber:   notice.VANumber,
+		Amount:     roundMoney(notice.Amount),
+		PaidAt:     notice.PaidAt,
+		Payload:    string(body),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	linked, err := u.linkStatementPayment(callback)
+	if err != nil {
+		return nil, err
+	}
+	if linked {
+		log.Printf("✓ Callback %s %s sudah diposting dari mutasi sebagai pembayaran #%d\n", bank.Code, notice.PaymentID, callback.PaymentID)
+		return callback, nil
+	}
+
+	account, reason := u.matchAccount(bank, notice.VANumber)
+	if account != nil {
+		callback.TransactionID = account.TransactionID
+		posting, err := u.payments.PostInstallmentPayment(account.TransactionID, InstallmentPaymentRequest{
+			Amount:    callback.Amount,
+			Reference: callbackReference(callback),
+			PaidAt:    callback.PaidAt,
+		})
+		if err != nil {
+			reason = err.Error()
+		} else {
+			callback.Status = model.PaymentCallbackPosted
+			callback.PaymentID = posting.Payment.ID
+			if posting.Completed {
+				u.closeAccounts(account.TransactionID)
+			}
+		}
+	}
+
+	if callback.Status == model.PaymentCallbackPosted {
+		if err := u.callbackRepo.Create(callback); err != nil {
+			return nil, err
+		}
+		log.Printf("✓ Callback %s %s diposting: Rp %.2f\n", bank.Code, notice.PaymentID, callback.Amount)
+		return callback, nil
+	}
+
+	callback.Status = model.PaymentCallbackSuspense
+	callback.SuspenseReason = reason
+	err = u.transactor.Transaction(func(tx *repository.Repositories) error {
+		if err := tx.PaymentCallbacks.Create(callback); err != nil {
+			return err
+		}
+		return ledgerIn(tx).Post(suspenseEntry(callback))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("⚠ Callback %s %s masuk titipan: %s\n", bank.Code, notice.PaymentID, reason)
+	return callback, nil
+}
+
+// linkStatementPayment stores the callback as posted when a statement line of its VA with the same amount
+// around its payment date was already posted and not linked to a callback, and links the lines of that
+// payment to it. It reports whether the callback was linked.
+func (u *virtualAccountUsecase) linkStatementPayment(callback *model.PaymentCallback) (bool, error) {
+	linked := false
+	err := u.transactor.Transaction(func(tx *repository.Repositories) error {
+		lines, err := tx.BankStatementLines.GetByVANumber(callback.VANumber)
+		if err != nil {
+			return err
+		}
+		var posted *model.BankStatementLine
+		for i := range lines {
+			line := &lines[i]
+			days := daysBetween(dateOnly(callback.PaidAt.In(line.ValueDate.Location())), line.ValueDate)
+			if line.Status == model.StatementLineMatched && line.PaymentID != 0 && line.CallbackID == 0 &&
+				sameAmount(line.Amount, callback.Amount) && days >= -u.policy.StatementDateDays && days <= u.policy.StatementDateDays {
+				posted = line
+				break
+			}
+		}
+		if posted == nil {
+			return nil
+		}
+
+		callback.Status = model.PaymentCallbackPosted
+		callback.PaymentID = posted.PaymentID
+		callback.TransactionID = posted.TransactionID
+		if err := tx.PaymentCallbacks.Create(callback); err != nil {
+			return err
+		}
+		// Overlapping statements may carry the same payment on several lines
+		paymentID := posted.PaymentID
+		for i := range lines {
+			if lines[i].PaymentID != paymentID {
+				continue
+			}
+			lines[i].CallbackID = callback.ID
+			lines[i].UpdatedAt = callback.CreatedAt
+			if err := tx.BankStatementLines.Update(&lines[i]); err != nil {
+				return err
+			}
+		}
+		linked = true
+		return nil
+	})
+	return linked, err
+}
+
+// matchAccount finds the open virtual account a callback pays into, or the reason it has none
+func (u *virtualAccountUsecase) matchAccount(bank va.Bank, vaNumber string) (*model.VirtualAccount, string) {
+	account, err := u.accountRepo.GetByNumber(vaNumber)
+	switch {
+	case err != nil:
+		return nil, "nomor VA tidak terdaftar"
+	case account.BankCode != bank.Code:
+		return nil, fmt.Sprintf("nomor VA terdaftar di bank %s", account.BankCode)
+	case account.Status != model.VirtualAccountActive:
+		return nil, "VA sudah ditutup"
+	}
+	return account, ""
+}
+
+// closeAccounts closes the virtual accounts of a completed contract so later transfers go to suspense
+func (u *virtualAccountUsecase) closeAccounts(transactionID uint) {
+	accounts, err := u.accountRepo.GetByTransactionID(transactionID)
+	if err != nil {
+		log.Printf("⚠ VA kontrak %d gagal ditutup: %v\n", transactionID, err)
+		return
+	}
+	for i := range accounts {
+		if accounts[i].Status != model.VirtualAccountActive {
+			continue
+		}
+		accounts[i].Status = model.VirtualAccountClosed
+		accounts[i].UpdatedAt = time.Now()
+		if err := u.accountRepo.Update(&accounts[i]); err != nil {
+			log.Printf("⚠ VA %s gagal ditutup: %v\n", accounts[i].VANumber, err)
+		}
+	}
+}
+
+func (u *virtualAccountUsecase) GetSuspense() ([]model.PaymentCallback, error) {
+	return u.callbackRepo.GetByStatus(model.PaymentCallbackSuspense)
+}
+
+// ResolveSuspense applies a callback held in suspense to a contract or refunds it to the sender
+func (u *virtualAccountUsecase) ResolveSuspense(callbackID uint, req SuspenseResolutionRequest) (*model.PaymentCallback, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	req.Action = strings.ToUpper(strings.TrimSpace(req.Action))
+	if req.Action != SuspenseActionPost && req.Action != SuspenseActionRefund {
+		return nil, errors.New("aksi penyelesaian harus POST atau REFUND")
+	}
+	if strings.TrimSpace(req.ResolvedBy) == "" {
+		return nil, errors.New("resolved_by wajib diisi")
+	}
+
+	callback, err := u.callbackRepo.GetByID(callbackID)
+	if err != nil {
+		return nil, errors.New("callback pembayaran tidak ditemukan")
+	}
+	if callback.Status != model.PaymentCallbackSuspense {
+		return nil, fmt.Errorf("callback berstatus %s tidak ada di titipan", callback.Status)
+	}
+
+	now := time.Now()
+	switch req.Action {
+	case SuspenseActionPost:
+		transactionID := req.TransactionID
+		if transactionID == 0 {
+			transactionID = callback.TransactionID
+		}
+		if transactionID == 0 {
+			return nil, errors.New("transaction_id wajib diisi untuk pembayaran yang tidak cocok dengan VA")
+		}
+		posting, err := u.payments.PostInstallmentPayment(transactionID, InstallmentPaymentRequest{
+			Amount:       callback.Amount,
+			Reference:    callbackReference(callback),
+			PaidAt:       callback.PaidAt,
+			FromSuspense: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if posting.Completed {
+			u.closeAccounts(transactionID)
+		}
+		callback.Status = model.PaymentCallbackResolved
+		callback.TransactionID = transactionID
+		callback.PaymentID = posting.Payment.ID
+	case SuspenseActionRefund:
+		callback.Status = model.PaymentCallbackRefunded
+	}
+
+	callback.ResolvedBy = req.ResolvedBy
+	callback.ResolutionNote = req.Note
+	callback.ResolvedAt = &now
+	callback.UpdatedAt = now
+	err = u.transactor.Transaction(func(tx *repository.Repositories) error {
+		if err := tx.PaymentCallbacks.Update(callback); err != nil {
+			return err
+		}
+		if callback.Status != model.PaymentCallbackRefunded {
+			return nil
+		}
+		return ledgerIn(tx).Post(suspenseRefundEntry(callback))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("✓ Titipan %s %s diselesaikan: %s oleh %s\n", callback.BankCode, callback.ExternalID, callback.Status, req.ResolvedBy)
+	return callback, nil
+}
+
+func (u *virtualAccountUsecase) bank(bankCode string) (va.Bank, error) {
+	bank, ok := u.banks[strings.ToUpper(strings.TrimSpace(bankCode))]
+	if !ok {
+		return va.Bank{}, ErrUnknownBank
+	}
+	return bank, nil
+}
+
+// callbackReference is the payment reference of a callback, unique per bank and bank payment ID
+func callbackReference(callback *model.PaymentCallback) string {
+	return fmt.Sprintf("VA-%s-%s", callback.BankCode, callback.ExternalID)
+}
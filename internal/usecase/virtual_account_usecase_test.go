@@ -0,0 +1,217 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"main/internal/ledger"
+	"main/internal/model"
+	"main/internal/va"
+)
+
+// MockVirtualAccountRepository for testing
+type MockVirtualAccountRepository struct {
+	accounts []*model.VirtualAccount
+}
+
+func (m *MockVirtualAccountRepository) Create(account *model.VirtualAccount) error {
+	account.ID = uint(len(m.accounts) + 1)
+	m.accounts = append(m.accounts, account)
+	return nil
+}
+
+func (m *MockVirtualAccountRepository) GetByNumber(vaNumber string) (*model.VirtualAccount, error) {
+	for _, account := range m.accounts {
+		if account.VANumber == vaNumber {
+			return account, nil
+		}
+	}
+	return nil, errors.New("virtual account not found")
+}
+
+func (m *MockVirtualAccountRepository) GetByTransactionID(transactionID uint) ([]model.VirtualAccount, error) {
+	var accounts []model.VirtualAccount
+	for _, account := range m.accounts {
+		if account.TransactionID == transactionID {
+			accounts = append(accounts, *account)
+		}
+	}
+	return accounts, nil
+}
+
+func (m *MockVirtualAccountRepository) Update(account *model.VirtualAccount) error {
+	for i, existing := range m.accounts {
+		if existing.ID == account.ID {
+			m.accounts[i] = account
+			return nil
+		}
+	}
+	return errors.New("virtual account not found")
+}
+
+// MockPaymentCallbackRepository for testing
+type MockPaymentCallbackRepository struct {
+	callbacks []*model.PaymentCallback
+}
+
+func (m *MockPaymentCallbackRepository) Create(callback *model.PaymentCallback) error {
+	callback.ID = uint(len(m.callbacks) + 1)
+	m.callbacks = append(m.callbacks, callback)
+	return nil
+}
+
+func (m *MockPaymentCallbackRepository) GetByID(id uint) (*model.PaymentCallback, error) {
+	for _, callback := range m.callbacks {
+		if callback.ID == id {
+			return callback, nil
+		}
+	}
+	return nil, errors.New("payment callback not found")
+}
+
+func (m *MockPaymentCallbackRepository) GetByExternalID(bankCode, externalID string) (*model.PaymentCallback, error) {
+	for _, callback := range m.callbacks {
+		if callback.BankCode == bankCode && callback.ExternalID == externalID {
+			return callback, nil
+		}
+	}
+	return nil, errors.New("payment callback not found")
+}
+
+func (m *MockPaymentCallbackRepository) GetByVANumber(vaNumber string) ([]model.PaymentCallback, error) {
+	var callbacks []model.PaymentCallback
+	for _, callback := range m.callbacks {
+		if callback.VANumber == vaNumber {
+			callbacks = append(callbacks, *callback)
+		}
+	}
+	return callbacks, nil
+}
+
+func (m *MockPaymentCallbackRepository) GetByStatus(status string) ([]model.PaymentCallback, error) {
+	var callbacks []model.PaymentCallback
+	for _, callback := range m.callbacks {
+		if callback.Status == status {
+			callbacks = append(callbacks, *callback)
+		}
+	}
+	return callbacks, nil
+}
+
+func (m *MockPaymentCallbackRepository) Update(callback *model.PaymentCallback) error {
+	return nil
+}
+
+const testBankSecret = "bca-secret"
+
+type virtualAccountFixture struct {
+	*paymentFixture
+	uc           VirtualAccountUsecase
+	accountRepo  *MockVirtualAccountRepository
+	callbackRepo *MockPaymentCallbackRepository
+	lineRepo     *MockBankStatementLineRepository
+}
+
+func newVirtualAccountFixture() *virtualAccountFixture {
+	f := &virtualAccountFixture{
+		paymentFixture: newPaymentFixture(),
+		accountRepo:    &MockVirtualAccountRepository{},
+		callbackRepo:   &MockPaymentCallbackRepository{},
+		lineRepo:       &MockBankStatementLineRepository{},
+	}
+	f.transactor.repos.VirtualAccounts = f.accountRepo
+	f.transactor.repos.PaymentCallbacks = f.callbackRepo
+	f.transactor.repos.BankStatementLines = f.lineRepo
+	banks := map[string]va.Bank{"BCA": {Code: "BCA", Prefix: "39358", Secret: testBankSecret}}
+	f.uc = NewVirtualAccountUsecase(f.transactionRepo, f.accountRepo, f.callbackRepo,
+		f.paymentFixture.uc, f.transactor, banks, DefaultVirtualAccountPolicy())
+	return f
+}
+
+// sendCallback signs a callback body like the bank does and hands it to the usecase
+func (f *virtualAccountFixture) sendCallback(paymentID, vaNumber string, amount float64) (*model.PaymentCallback, error) {
+	body := []byte(fmt.Sprintf(`{"payment_id":%q,"va_number":%q,"amount":%.2f,"paid_at":"2026-02-01T09:00:00Z"}`, paymentID, vaNumber, amount))
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	return f.uc.HandleCallback("bca", timestamp, va.Sign(testBankSecret, timestamp, body), body)
+}
+
+// Test: A signed callback to an issued VA posts once, however often the bank retries it
+func TestHandleCallback_Posts(t *testing.T) {
+	f := newVirtualAccountFixture()
+
+	account, err := f.uc.IssueVirtualAccount(1, "BCA")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if account.VANumber != "3935800000000015" {
+		t.Errorf("Expected VA number 3935800000000015, got %s", account.VANumber)
+	}
+	if again, _ := f.uc.IssueVirtualAccount(1, "BCA"); again.ID != account.ID {
+		t.Error("Expected the existing virtual account returned")
+	}
+
+	for i := 0; i < 2; i++ {
+		callback, err := f.sendCallback("TRX-1", account.VANumber, 1100000)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if callback.Status != model.PaymentCallbackPosted || callback.PaymentID == 0 {
+			t.Errorf("Expected callback posted, got %+v", callback)
+		}
+	}
+
+	if receivable := accountBalance(t, f.ledger, ledger.Receivable); receivable != 2200000 {
+		t.Errorf("Expected one installment off the receivable, got %.2f", receivable)
+	}
+
+	body := []byte(`{"payment_id":"TRX-2","va_number":"3935800000000015","amount":1100000,"paid_at":"2026-02-01T09:00:00Z"}`)
+	stale := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	if _, err := f.uc.HandleCallback("BCA", stale, va.Sign(testBankSecret, stale, body), body); !errors.Is(err, va.ErrStaleCallback) {
+		t.Errorf("Expected ErrStaleCallback, got %v", err)
+	}
+	if _, err := f.uc.HandleCallback("BCA", stale, "deadbeef", body); !errors.Is(err, va.ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature, got %v", err)
+	}
+	if _, err := f.uc.HandleCallback("BNI", stale, "deadbeef", body); !errors.Is(err, ErrUnknownBank) {
+		t.Errorf("Expected ErrUnknownBank, got %v", err)
+	}
+}
+
+// Test: Unmatched money is held in suspense until an operator posts it to a contract
+func TestHandleCallback_Suspense(t *testing.T) {
+	f := newVirtualAccountFixture()
+
+	callback, err := f.sendCallback("TRX-9", "3935800000009999", 500000)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if callback.Status != model.PaymentCallbackSuspense || callback.SuspenseReason == "" {
+		t.Errorf("Expected callback in suspense with a reason, got %+v", callback)
+	}
+	if suspense := accountBalance(t, f.ledger, ledger.PaymentSuspense); suspense != 500000 {
+		t.Errorf("Expected suspense 500000, got %.2f", suspense)
+	}
+
+	if _, err := f.uc.ResolveSuspense(callback.ID, SuspenseResolutionRequest{Action: SuspenseActionPost, ResolvedBy: "ops1"}); err == nil {
+		t.Error("Expected error posting without a contract, got nil")
+	}
+	resolved, err := f.uc.ResolveSuspense(callback.ID, SuspenseResolutionRequest{Action: SuspenseActionPost, TransactionID: 1, ResolvedBy: "ops1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resolved.Status != model.PaymentCallbackResolved || resolved.PaymentID == 0 {
+		t.Errorf("Expected callback resolved to a payment, got %+v", resolved)
+	}
+	if suspense := accountBalance(t, f.ledger, ledger.PaymentSuspense); suspense != 0 {
+		t.Errorf("Expected suspense cleared, got %.2f", suspense)
+	}
+	if receivable := accountBalance(t, f.ledger, ledger.Receivable); receivable != 2800000 {
+		t.Errorf("Expected receivable 2800000, got %.2f", receivable)
+	}
+	if cash := accountBalance(t, f.ledger, ledger.Cash); cash != 500000 {
+		t.Errorf("Expected cash 500000, got %.2f", cash)
+	}
+}
@@ -0,0 +1,503 @@
+package usecase
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"main/internal/model"
+	"main/internal/repository"
+)
+
+// Screening contexts
+const (
+	ScreeningContextRegistration = "REGISTRATION"
+	ScreeningContextTransaction  = "TRANSACTION"
+)
+
+// Watchlist match types
+const (
+	MatchTypeExactNIK     = "EXACT_NIK"
+	MatchTypeFuzzyNameDOB = "FUZZY_NAME_DOB"
+)
+
+// fuzzyNameThreshold is the minimum Jaro-Winkler similarity for a name match
+const fuzzyNameThreshold = 0.90
+
+var (
+	ErrWatchlistBlocked = errors.New("konsumen terdaftar dalam daftar hitam")
+	ErrWatchlistReview  = errors.New("konsumen cocok dengan watchlist, menunggu review kepatuhan")
+)
+
+// WatchlistScreener screens a person against the watchlist before credit is extended
+type WatchlistScreener interface {
+	Screen(subject ScreeningSubject) (*ScreeningResult, error)
+}
+
+// WatchlistUsecase defines all business logic operations for watchlist screening
+type WatchlistUsecase interface {
+	WatchlistScreener
+	AddEntry(entry *model.WatchlistEntry) error
+	ImportCSV(source, listType string, data io.Reader, replace bool) (*WatchlistImportResult, error)
+	GetHits(status string) ([]model.WatchlistHit, error)
+	ReviewHit(id uint, decision, reviewer, note string) (*model.WatchlistHit, error)
+}
+
+// ScreeningSubject is the person being screened
+type ScreeningSubject struct {
+	ConsumerID  uint
+	NIK         string
+	FullName    string
+	LegalName   string
+	DateOfBirth time.Time
+	Context     string
+	Reference   string
+}
+
+// ScreeningResult is the outcome of screening: CLEAR, REVIEW or BLOCK with the stored hits
+type ScreeningResult struct {
+	Decision string               `json:"decision"`
+	Hits     []model.WatchlistHit `json:"hits"`
+}
+
+// WatchlistImportResult summarises a CSV list update
+type WatchlistImportResult struct {
+	Created     int      `json:"created"`
+	Updated     int      `json:"updated"`
+	Deactivated int      `json:"deactivated"`
+	Errors      []string `json:"errors,omitempty"`
+}
+
+// watchlistUsecase is the implementation of WatchlistUsecase
+type watchlistUsecase struct {
+	entryRepo    repository.WatchlistRepository
+	hitRepo      repository.WatchlistHitRepository
+	consumerRepo repository.ConsumerRepository
+	mu           sync.Mutex
+}
+
+// NewWatchlistUsecase creates a new instance of WatchlistUsecase
+func NewWatchlistUsecase(
+	entryRepo repository.WatchlistRepository,
+	hitRepo repository.WatchlistHitRepository,
+	consumerRepo repository.ConsumerRepository,
+) WatchlistUsecase {
+	return &watchlistUsecase{
+		entryRepo:    entryRepo,
+		hitRepo:      hitRepo,
+		consumerRepo: consumerRepo,
+	}
+}
+
+// Screen matches on exact NIK and on fuzzy name with the same date of birth.
+// Exact NIK matches on a blacklist block; every other match needs review.
+// Every hit is stored, hits for an entry already cleared for this NIK are stored as cleared.
+func (u *watchlistUsecase) Screen(subject ScreeningSubject) (*ScreeningResult, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	type match struct {
+		entry     model.WatchlistEntry
+		matchType string
+		score     float64
+	}
+	matches := make(map[uint]match)
+
+	byNIK, err := u.entryRepo.GetActiveByNIK(subject.NIK)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range byNIK {
+		matches[entry.ID] = match{entry: entry, matchType: MatchTypeExactNIK, score: 1}
+	}
+
+	if !subject.DateOfBirth.IsZero() {
+		byDOB, err := u.entryRepo.GetActiveByDateOfBirth(subject.DateOfBirth)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range byDOB {
+			if _, exists := matches[entry.ID]; exists {
+				continue
+			}
+			score := nameSimilarity(entry.FullName, subject.FullName)
+			if legal := nameSimilarity(entry.FullName, subject.LegalName); legal > score {
+				score = legal
+			}
+			if score >= fuzzyNameThreshold {
+				matches[entry.ID] = match{entry: entry, matchType: MatchTypeFuzzyNameDOB, score: score}
+			}
+		}
+	}
+
+	result := &ScreeningResult{Decision: model.ScreeningClear}
+	if len(matches) == 0 {
+		return result, nil
+	}
+
+	cleared, err := u.clearedEntries(subject.NIK)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range matches {
+		action := model.ScreeningReview
+		if m.matchType == MatchTypeExactNIK && m.entry.ListType == model.WatchlistTypeBlacklist {
+			action = model.ScreeningBlock
+		}
+
+		hit := model.WatchlistHit{
+			WatchlistEntryID: m.entry.ID,
+			ConsumerID:       subject.ConsumerID,
+			SubjectNIK:       subject.NIK,
+			SubjectName:      subject.FullName,
+			Context:          subject.Context,
+			Reference:        subject.Reference,
+			MatchType:        m.matchType,
+			Score:            m.score,
+			Action:           action,
+			Status:           model.HitStatusOpen,
+			CreatedAt:        time.Now(),
+		}
+
+		// A compliance officer already cleared this entry as a false positive for this person
+		if cleared[m.entry.ID] && action == model.ScreeningReview {
+			hit.Status = model.HitStatusCleared
+			hit.ReviewNote = "sebelumnya dinyatakan bukan kecocokan"
+		} else if severity(action) > severity(result.Decision) {
+			result.Decision = action
+		}
+
+		if err := u.hitRepo.Create(&hit); err != nil {
+			return nil, err
+		}
+		result.Hits = append(result.Hits, hit)
+	}
+
+	log.Printf("⚠ Watchlist screening %s untuk NIK %s: %s (%d hit)\n", subject.Context, subject.NIK, result.Decision, len(result.Hits))
+	return result, nil
+}
+
+// clearedEntries returns the watchlist entries already cleared for a NIK
+func (u *watchlistUsecase) clearedEntries(nik string) (map[uint]bool, error) {
+	hits, err := u.hitRepo.GetBySubjectNIK(nik)
+	if err != nil {
+		return nil, err
+	}
+	cleared := make(map[uint]bool)
+	for _, hit := range hits {
+		if hit.Status == model.HitStatusCleared && hit.ReviewedBy != "" {
+			cleared[hit.WatchlistEntryID] = true
+		}
+	}
+	return cleared, nil
+}
+
+// AddEntry adds a single person to a list, e.g. by the internal fraud team
+func (u *watchlistUsecase) AddEntry(entry *model.WatchlistEntry) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if err := validateWatchlistEntry(entry); err != nil {
+		return err
+	}
+
+	entry.Active = true
+	entry.CreatedAt = time.Now()
+	entry.UpdatedAt = time.Now()
+	return u.entryRepo.Create(entry)
+}
+
+// ImportCSV upserts list entries from a CSV with the header nik,full_name,date_of_birth,reason.
+// With replace, entries of the source missing from the file are deactivated.
+func (u *watchlistUsecase) ImportCSV(source, listType string, data io.Reader, replace bool) (*WatchlistImportResult, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	source = strings.ToUpper(strings.TrimSpace(source))
+	if source == "" {
+		return nil, errors.New("sumber daftar tidak boleh kosong")
+	}
+	if listType != model.WatchlistTypeBlacklist && listType != model.WatchlistTypeWatchlist {
+		return nil, errors.New("jenis daftar harus BLACKLIST atau WATCHLIST")
+	}
+
+	reader := csv.NewReader(data)
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("file CSV tidak valid: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("file CSV kosong")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["full_name"]; !ok {
+		return nil, errors.New("kolom full_name wajib ada pada header CSV")
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	existing, err := u.entryRepo.GetBySource(source)
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[string]*model.WatchlistEntry, len(existing))
+	for i := range existing {
+		byKey[watchlistKey(&existing[i])] = &existing[i]
+	}
+
+	result := &WatchlistImportResult{}
+	seen := make(map[string]bool)
+	now := time.Now()
+
+	for line, record := range records[1:] {
+		entry := &model.WatchlistEntry{
+			Source:   source,
+			ListType: listType,
+			NIK:      field(record, "nik"),
+			FullName: field(record, "full_name"),
+			Reason:   field(record, "reason"),
+			Active:   true,
+		}
+		if dob := field(record, "date_of_birth"); dob != "" {
+			parsed, err := time.Parse("2006-01-02", dob)
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("baris %d: tanggal lahir harus YYYY-MM-DD", line+2))
+				continue
+			}
+			entry.DateOfBirth = &parsed
+		}
+		if err := validateWatchlistEntry(entry); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("baris %d: %s", line+2, err.Error()))
+			continue
+		}
+
+		key := watchlistKey(entry)
+		seen[key] = true
+
+		if current, exists := byKey[key]; exists {
+			current.ListType = entry.ListType
+			current.NIK = entry.NIK
+			current.FullName = entry.FullName
+			current.DateOfBirth = entry.DateOfBirth
+			current.Reason = entry.Reason
+			current.Active = true
+			current.UpdatedAt = now
+			if err := u.entryRepo.Update(current); err != nil {
+				return nil, err
+			}
+			result.Updated++
+			continue
+		}
+
+		entry.CreatedAt = now
+		entry.UpdatedAt = now
+		if err := u.entryRepo.Create(entry); err != nil {
+			return nil, err
+		}
+		byKey[key] = entry
+		result.Created++
+	}
+
+	if replace {
+		for key, entry := range byKey {
+			if seen[key] || !entry.Active {
+				continue
+			}
+			entry.Active = false
+			entry.UpdatedAt = now
+			if err := u.entryRepo.Update(entry); err != nil {
+				return nil, err
+			}
+			result.Deactivated++
+		}
+	}
+
+	log.Printf("✓ Import watchlist %s: %d baru, %d diperbarui, %d dinonaktifkan\n", source, result.Created, result.Updated, result.Deactivated)
+	return result, nil
+}
+
+func (u *watchlistUsecase) GetHits(status string) ([]model.WatchlistHit, error) {
+	if status == "" {
+		status = model.HitStatusOpen
+	}
+	return u.hitRepo.GetByStatus(status)
+}
+
+// ReviewHit records the compliance decision. A confirmed hit rejects the consumer's KYC.
+func (u *watchlistUsecase) ReviewHit(id uint, decision, reviewer, note string) (*model.WatchlistHit, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if decision != model.HitStatusCleared && decision != model.HitStatusConfirmed {
+		return nil, errors.New("keputusan review harus CLEARED atau CONFIRMED")
+	}
+	if strings.TrimSpace(reviewer) == "" {
+		return nil, errors.New("nama petugas kepatuhan tidak boleh kosong")
+	}
+
+	hit, err := u.hitRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if hit.Status != model.HitStatusOpen {
+		return nil, errors.New("hit watchlist sudah direview")
+	}
+
+	now := time.Now()
+	hit.Status = decision
+	hit.ReviewedBy = reviewer
+	hit.ReviewNote = note
+	hit.ReviewedAt = &now
+	if err := u.hitRepo.Update(hit); err != nil {
+		return nil, err
+	}
+
+	if decision == model.HitStatusConfirmed {
+		if consumer, err := u.consumerRepo.GetByNIK(hit.SubjectNIK); err == nil {
+			consumer.KYCStatus = model.KYCStatusRejected
+			consumer.UpdatedAt = now
+			if err := u.consumerRepo.Update(consumer); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return hit, nil
+}
+
+// screeningError converts a screening decision into the error returned to the caller
+func screeningError(result *ScreeningResult) error {
+	switch result.Decision {
+	case model.ScreeningBlock:
+		return ErrWatchlistBlocked
+	case model.ScreeningReview:
+		return ErrWatchlistReview
+	}
+	return nil
+}
+
+func validateWatchlistEntry(entry *model.WatchlistEntry) error {
+	if strings.TrimSpace(entry.FullName) == "" {
+		return errors.New("nama lengkap tidak boleh kosong")
+	}
+	if entry.NIK != "" && !isDigits(entry.NIK, 16) {
+		return errors.New("NIK harus 16 digit angka")
+	}
+	if entry.NIK == "" && entry.DateOfBirth == nil {
+		return errors.New("NIK atau tanggal lahir wajib diisi")
+	}
+	if entry.ListType != model.WatchlistTypeBlacklist && entry.ListType != model.WatchlistTypeWatchlist {
+		return errors.New("jenis daftar harus BLACKLIST atau WATCHLIST")
+	}
+	return nil
+}
+
+// watchlistKey identifies a person within one source: by NIK, or by name and date of birth
+func watchlistKey(entry *model.WatchlistEntry) string {
+	if entry.NIK != "" {
+		return "nik:" + entry.NIK
+	}
+	dob := ""
+	if entry.DateOfBirth != nil {
+		dob = entry.DateOfBirth.Format("2006-01-02")
+	}
+	return "name:" + normalizeName(entry.FullName) + "|" + dob
+}
+
+func severity(decision string) int {
+	switch decision {
+	case model.ScreeningBlock:
+		return 2
+	case model.ScreeningReview:
+		return 1
+	}
+	return 0
+}
+
+func isDigits(value string, length int) bool {
+	if len(value) != length {
+		return false
+	}
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// nameSimilarity compares two names with Jaro-Winkler after normalisation
+func nameSimilarity(a, b string) float64 {
+	return jaroWinkler(normalizeName(a), normalizeName(b))
+}
+
+// jaroWinkler returns the Jaro-Winkler similarity (0..1) of two strings
+func jaroWinkler(a, b string) float64 {
+	s1, s2 := []rune(a), []rune(b)
+	if len(s1) == 0 || len(s2) == 0 {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+
+	window := max(len(s1), len(s2))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+
+	matched1 := make([]bool, len(s1))
+	matched2 := make([]bool, len(s2))
+	matches := 0
+	for i := range s1 {
+		start, end := max(0, i-window), min(len(s2), i+window+1)
+		for j := start; j < end; j++ {
+			if !matched2[j] && s1[i] == s2[j] {
+				matched1[i], matched2[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions, k := 0, 0
+	for i := range s1 {
+		if !matched1[i] {
+			continue
+		}
+		for !matched2[k] {
+			k++
+		}
+		if s1[i] != s2[k] {
+			transpositions++
+		}
+		k++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(s1)) + m/float64(len(s2)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for i := 0; i < min(4, len(s1), len(s2)) && s1[i] == s2[i]; i++ {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
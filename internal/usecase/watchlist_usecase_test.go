@@ -0,0 +1,215 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"main/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// MockWatchlistRepository for testing
+type MockWatchlistRepository struct {
+	entries []*model.WatchlistEntry
+}
+
+func (m *MockWatchlistRepository) Create(entry *model.WatchlistEntry) error {
+	entry.ID = uint(len(m.entries) + 1)
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+func (m *MockWatchlistRepository) Update(entry *model.WatchlistEntry) error {
+	for i, existing := range m.entries {
+		if existing.ID == entry.ID {
+			m.entries[i] = entry
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (m *MockWatchlistRepository) GetActiveByNIK(nik string) ([]model.WatchlistEntry, error) {
+	var entries []model.WatchlistEntry
+	for _, entry := range m.entries {
+		if entry.Active && entry.NIK == nik {
+			entries = append(entries, *entry)
+		}
+	}
+	return entries, nil
+}
+
+func (m *MockWatchlistRepository) GetActiveByDateOfBirth(dateOfBirth time.Time) ([]model.WatchlistEntry, error) {
+	var entries []model.WatchlistEntry
+	for _, entry := range m.entries {
+		if entry.Active && entry.DateOfBirth != nil && entry.DateOfBirth.Format("2006-01-02") == dateOfBirth.Format("2006-01-02") {
+			entries = append(entries, *entry)
+		}
+	}
+	return entries, nil
+}
+
+func (m *MockWatchlistRepository) GetBySource(source string) ([]model.WatchlistEntry, error) {
+	var entries []model.WatchlistEntry
+	for _, entry := range m.entries {
+		if entry.Source == source {
+			entries = append(entries, *entry)
+		}
+	}
+	return entries, nil
+}
+
+// MockWatchlistHitRepository for testing
+type MockWatchlistHitRepository struct {
+	hits []*model.WatchlistHit
+}
+
+func (m *MockWatchlistHitRepository) Create(hit *model.WatchlistHit) error {
+	hit.ID = uint(len(m.hits) + 1)
+	stored := *hit
+	m.hits = append(m.hits, &stored)
+	return nil
+}
+
+func (m *MockWatchlistHitRepository) GetByID(id uint) (*model.WatchlistHit, error) {
+	for _, hit := range m.hits {
+		if hit.ID == id {
+			return hit, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockWatchlistHitRepository) GetByStatus(status string) ([]model.WatchlistHit, error) {
+	var hits []model.WatchlistHit
+	for _, hit := range m.hits {
+		if hit.Status == status {
+			hits = append(hits, *hit)
+		}
+	}
+	return hits, nil
+}
+
+func (m *MockWatchlistHitRepository) GetBySubjectNIK(nik string) ([]model.WatchlistHit, error) {
+	var hits []model.WatchlistHit
+	for _, hit := range m.hits {
+		if hit.SubjectNIK == nik {
+			hits = append(hits, *hit)
+		}
+	}
+	return hits, nil
+}
+
+func (m *MockWatchlistHitRepository) Update(hit *model.WatchlistHit) error {
+	for i, existing := range m.hits {
+		if existing.ID == hit.ID {
+			m.hits[i] = hit
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+// newTestWatchlist returns a watchlist usecase with empty lists
+func newTestWatchlist(consumerRepo *MockConsumerRepository) WatchlistUsecase {
+	return NewWatchlistUsecase(&MockWatchlistRepository{}, &MockWatchlistHitRepository{}, consumerRepo)
+}
+
+const watchlistCSV = `nik,full_name,date_of_birth,reason
+3174010101900001,John Doe,1990-01-01,Pemalsuan dokumen
+,Budi Santoso,1985-03-12,Penipuan berulang
+`
+
+// Test: Exact NIK on a blacklist blocks registration and the hit is stored
+func TestRegisterConsumer_Blacklisted(t *testing.T) {
+	consumerRepo := NewMockConsumerRepository()
+	hitRepo := &MockWatchlistHitRepository{}
+	watchlist := NewWatchlistUsecase(&MockWatchlistRepository{}, hitRepo, consumerRepo)
+
+	if _, err := watchlist.ImportCSV("internal_fraud", model.WatchlistTypeBlacklist, strings.NewReader(watchlistCSV), false); err != nil {
+		t.Fatalf("Expected no import error, got %v", err)
+	}
+
+	uc := NewConsumerUsecase(consumerRepo, DefaultEligibilityRules(), DefaultNIKPolicy(), watchlist)
+	err := uc.RegisterConsumer(&model.Consumer{
+		NIK:       "3174010101900001",
+		FullName:  "John Doe",
+		LegalName: "John Doe",
+		Salary:    5000000,
+	})
+	if err != ErrWatchlistBlocked {
+		t.Errorf("Expected ErrWatchlistBlocked, got %v", err)
+	}
+
+	if len(hitRepo.hits) != 1 || hitRepo.hits[0].MatchType != MatchTypeExactNIK {
+		t.Errorf("Expected one stored EXACT_NIK hit, got %d", len(hitRepo.hits))
+	}
+}
+
+// Test: Similar name with the same date of birth routes the consumer to manual review
+func TestRegisterConsumer_FuzzyWatchlistMatch(t *testing.T) {
+	consumerRepo := NewMockConsumerRepository()
+	watchlist := NewWatchlistUsecase(&MockWatchlistRepository{}, &MockWatchlistHitRepository{}, consumerRepo)
+	watchlist.ImportCSV("OJK", model.WatchlistTypeBlacklist, strings.NewReader(watchlistCSV), false)
+
+	uc := NewConsumerUsecase(consumerRepo, DefaultEligibilityRules(), DefaultNIKPolicy(), watchlist)
+	consumer := &model.Consumer{
+		NIK:       "3174011203850002", // born 1985-03-12
+		FullName:  "Budi Santosa",
+		LegalName: "Budi Santosa",
+		Salary:    5000000,
+	}
+
+	if err := uc.RegisterConsumer(consumer); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if consumer.KYCStatus != model.KYCStatusManualReview {
+		t.Errorf("Expected MANUAL_REVIEW, got %s", consumer.KYCStatus)
+	}
+}
+
+// Test: Re-importing with replace deactivates entries missing from the file
+func TestImportCSV_Replace(t *testing.T) {
+	entryRepo := &MockWatchlistRepository{}
+	watchlist := NewWatchlistUsecase(entryRepo, &MockWatchlistHitRepository{}, NewMockConsumerRepository())
+	watchlist.ImportCSV("OJK", model.WatchlistTypeBlacklist, strings.NewReader(watchlistCSV), false)
+
+	update := "nik,full_name,date_of_birth,reason\n3174010101900001,John Doe,1990-01-01,Masih aktif\nbad,,,\n"
+	result, err := watchlist.ImportCSV("OJK", model.WatchlistTypeBlacklist, strings.NewReader(update), true)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Updated != 1 || result.Deactivated != 1 || len(result.Errors) != 1 {
+		t.Errorf("Unexpected import result %+v", result)
+	}
+}
+
+// Test: A cleared hit no longer triggers review for the same person
+func TestScreen_ClearedHitSuppressed(t *testing.T) {
+	watchlist := NewWatchlistUsecase(&MockWatchlistRepository{}, &MockWatchlistHitRepository{}, NewMockConsumerRepository())
+	watchlist.ImportCSV("OJK", model.WatchlistTypeWatchlist, strings.NewReader(watchlistCSV), false)
+
+	subject := ScreeningSubject{
+		NIK:         "3174011203850002",
+		FullName:    "Budi Santosa",
+		DateOfBirth: time.Date(1985, 3, 12, 0, 0, 0, 0, time.UTC),
+		Context:     ScreeningContextTransaction,
+	}
+
+	first, _ := watchlist.Screen(subject)
+	if first.Decision != model.ScreeningReview {
+		t.Fatalf("Expected REVIEW, got %s", first.Decision)
+	}
+
+	if _, err := watchlist.ReviewHit(first.Hits[0].ID, model.HitStatusCleared, "compliance-01", "orang berbeda"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	second, _ := watchlist.Screen(subject)
+	if second.Decision != model.ScreeningClear {
+		t.Errorf("Expected CLEAR after review, got %s", second.Decision)
+	}
+}
@@ -0,0 +1,394 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"main/internal/ledger"
+	"main/internal/model"
+	"main/internal/repository"
+)
+
+var ErrConsumerWrittenOff = errors.New("konsumen memiliki kontrak hapus buku, limit baru tidak dapat diberikan")
+
+var validRecoverySources = map[string]bool{
+	model.RecoverySourceCollection:   true,
+	model.RecoverySourceRepossession: true,
+}
+
+// WriteOffPolicy holds the write-off eligibility
+type WriteOffPolicy struct {
+	MinDaysPastDue int // DEFAULTED contracts can be written off from this DPD
+}
+
+// DefaultWriteOffPolicy allows write-off of DEFAULTED contracts from 180 DPD
+func DefaultWriteOffPolicy() WriteOffPolicy {
+	return WriteOffPolicy{MinDaysPastDue: 180}
+}
+
+// AccrualReverser takes back the interest recognised for a contract inside the caller's database transaction
+type AccrualReverser interface {
+	ReverseAccruals(tx *repository.Repositories, transactionID uint, at time.Time) (float64, error)
+}
+
+// WriteOffRequest proposes taking a contract off the books
+type WriteOffRequest struct {
+	Reason      string `json:"reason"`
+	RequestedBy string `json:"requested_by"`
+}
+
+// RecoveryRequest records money received on a written-off contract
+type RecoveryRequest struct {
+	Source     string    `json:"source"` // COLLECTION, REPOSSESSION
+	Amount     float64   `json:"amount"`
+	Reference  string    `json:"reference"`
+	ReceivedAt time.Time `json:"received_at"` // zero means now
+}
+
+// VintageRecovery is the recovery performance of written-off contracts booked in one month
+type VintageRecovery struct {
+	Vintage             string  `json:"vintage"` // YYYY-MM
+	ContractsWrittenOff int     `json:"contracts_written_off"`
+	LossAmount          float64 `json:"loss_amount"`
+	RecoveredAmount     float64 `json:"recovered_amount"`
+	RecoveryRate        float64 `json:"recovery_rate"` // recovered amount as a fraction of the loss
+}
+
+// WriteOffUsecase defines all business logic operations for write-off and recovery
+type WriteOffUsecase interface {
+	GetEligibleContracts() ([]model.Transaction, error)
+	RequestWriteOff(transactionID uint, req WriteOffRequest) (*model.WriteOff, error)
+	ReviewWriteOff(id uint, approve bool, reviewer, note string) (*model.WriteOff, error)
+	GetPendingWriteOffs() ([]model.WriteOff, error)
+	RecordRecovery(transactionID uint, req RecoveryRequest) (*model.Recovery, error)
+	GetRecoveries(transactionID uint) ([]model.Recovery, error)
+	GetRecoveryByVintage() ([]VintageRecovery, error)
+}
+
+// writeOffUsecase is the implementation of WriteOffUsecase
+type writeOffUsecase struct {
+	transactionRepo repository.TransactionRepository
+	writeOffRepo    repository.WriteOffRepository
+	recoveryRepo    repository.RecoveryRepository
+	accruals        AccrualReverser
+	transactor      repository.Transactor
+	policy          WriteOffPolicy
+	mu              sync.Mutex
+}
+
+// NewWriteOffUsecase creates a new instance of WriteOffUsecase
+func NewWriteOffUsecase(
+	transactionRepo repository.TransactionRepository,
+	writeOffRepo repository.WriteOffRepository,
+	recoveryRepo repository.RecoveryRepository,
+	accruals AccrualReverser,
+	transactor repository.Transactor,
+	policy WriteOffPolicy,
+) WriteOffUsecase {
+	return &writeOffUsecase{
+		transactionRepo: transactionRepo,
+		writeOffRepo:    writeOffRepo,
+		recoveryRepo:    recoveryRepo,
+		accruals:        accruals,
+		transactor:      transactor,
+		policy:          policy,
+	}
+}
+
+// GetEligibleContracts lists the DEFAULTED contracts past the write-off DPD
+func (u *writeOffUsecase) GetEligibleContracts() ([]model.Transaction, error) {
+	defaulted, err := u.transactionRepo.GetByStatus(model.TransactionStatusDefaulted)
+	if err != nil {
+		return nil, err
+	}
+
+	eligible := make([]model.Transaction, 0, len(defaulted))
+	for _, transaction := range defaulted {
+		if transaction.DaysPastDue >= u.policy.MinDaysPastDue {
+			eligible = append(eligible, transaction)
+		}
+	}
+	return eligible, nil
+}
+
+// RequestWriteOff proposes writing off an eligible contract. Nothing changes until another officer approves it.
+func (u *writeOffUsecase) RequestWriteOff(transactionID uint, req WriteOffRequest) (*model.WriteOff, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	req.RequestedBy = strings.TrimSpace(req.RequestedBy)
+	if req.RequestedBy == "" {
+		return nil, errors.New("nama petugas pengaju tidak boleh kosong")
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, errors.New("alasan hapus buku wajib diisi")
+	}
+
+	transaction, err := u.getEligibleTransaction(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	requests, err := u.writeOffRepo.GetByTransactionID(transaction.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range requests {
+		if existing.Status == model.WriteOffStatusPending {
+			return nil, errors.New("kontrak masih memiliki pengajuan hapus buku yang menunggu persetujuan")
+		}
+	}
+
+	now := time.Now()
+	writeOff := &model.WriteOff{
+		TransactionID: transaction.ID,
+		ConsumerID:    transaction.ConsumerID,
+		Vintage:       transaction.CreatedAt.Format("2006-01"),
+		DaysPastDue:   transaction.DaysPastDue,
+		Reason:        strings.TrimSpace(req.Reason),
+		Status:        model.WriteOffStatusPending,
+		RequestedBy:   req.RequestedBy,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := u.writeOffRepo.Create(writeOff); err != nil {
+		return nil, err
+	}
+
+	log.Printf("✓ Hapus buku kontrak %s diajukan oleh %s (DPD %d)\n", transaction.ContractNumber, writeOff.RequestedBy, writeOff.DaysPastDue)
+	return writeOff, nil
+}
+
+// ReviewWriteOff approves or rejects a pending write-off. The reviewer must not be the officer who requested it.
+// Approval reverses the accrued interest, takes the receivable off the books against the deferred interest
+// and the write-off expense, releases the limit and flags the consumer.
+func (u *writeOffUsecase) ReviewWriteOff(id uint, approve bool, reviewer, note string) (*model.WriteOff, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	reviewer = strings.TrimSpace(reviewer)
+	if reviewer == "" {
+		return nil, errors.New("nama petugas penyetuju tidak boleh kosong")
+	}
+
+	writeOff, err := u.writeOffRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("pengajuan hapus buku tidak ditemukan")
+	}
+	if writeOff.Status != model.WriteOffStatusPending {
+		return nil, errors.New("pengajuan hapus buku tidak sedang menunggu persetujuan")
+	}
+	if strings.EqualFold(reviewer, writeOff.RequestedBy) {
+		return nil, errors.New("hapus buku harus disetujui oleh petugas selain pengaju")
+	}
+
+	now := time.Now()
+	writeOff.ReviewedBy = reviewer
+	writeOff.ReviewNote = note
+	writeOff.ReviewedAt = &now
+	writeOff.UpdatedAt = now
+
+	if !approve {
+		writeOff.Status = model.WriteOffStatusRejected
+		if err := u.writeOffRepo.Update(writeOff); err != nil {
+			return nil, err
+		}
+		log.Printf("✓ Hapus buku #%d ditolak oleh %s\n", writeOff.ID, reviewer)
+		return writeOff, nil
+	}
+
+	transaction, err := u.getEligibleTransaction(writeOff.TransactionID)
+	if err != nil {
+		return nil, err
+	}
+	err = u.transactor.Transaction(func(tx *repository.Repositories) error {
+		return u.approve(tx, transaction, writeOff, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("✓ Kontrak %s dihapusbukukan, disetujui oleh %s: piutang Rp %.2f, kerugian Rp %.2f\n",
+		transaction.ContractNumber, reviewer, writeOff.ReceivableAmount, writeOff.LossAmount)
+	return writeOff, nil
+}
+
+// approve carries out an approved write-off inside one database transaction
+func (u *writeOffUsecase) approve(tx *repository.Repositories, transaction *model.Transaction, writeOff *model.WriteOff, now time.Time) error {
+	consumer, err := tx.Consumers.GetByID(transaction.ConsumerID)
+	if err != nil {
+		return errors.New("konsumen tidak ditemukan")
+	}
+
+	reversed, err := u.accruals.ReverseAccruals(tx, transaction.ID, now)
+	if err != nil {
+		return err
+	}
+	books := ledgerIn(tx)
+	receivable, err := books.ContractBalance(transaction.ID, ledger.Receivable)
+	if err != nil {
+		return err
+	}
+	deferred, err := books.ContractBalance(transaction.ID, ledger.UnearnedInterest)
+	if err != nil {
+		return err
+	}
+
+	writeOff.ReversedAccruals = reversed
+	writeOff.ReceivableAmount = receivable
+	writeOff.DeferredInterest = deferred
+	writeOff.LossAmount = roundMoney(receivable - deferred)
+	writeOff.Status = model.WriteOffStatusApproved
+	writeOff.WrittenOffAt = &now
+	if err := books.Post(writeOffEntry(transaction, writeOff)); err != nil {
+		return err
+	}
+	if err := tx.WriteOffs.Update(writeOff); err != nil {
+		return err
+	}
+
+	if err := releaseLimit(tx.Limits, transaction.ConsumerID, transaction.Tenor, transaction.FinancedAmount()); err != nil {
+		return err
+	}
+
+	transaction.Status = model.TransactionStatusWrittenOff
+	transaction.UpdatedAt = now
+	if err := tx.Transactions.Update(transaction); err != nil {
+		return err
+	}
+
+	consumer.WrittenOff = true
+	consumer.UpdatedAt = now
+	return tx.Consumers.Update(consumer)
+}
+
+func (u *writeOffUsecase) GetPendingWriteOffs() ([]model.WriteOff, error) {
+	return u.writeOffRepo.GetByStatus(model.WriteOffStatusPending)
+}
+
+// RecordRecovery books money received on a written-off contract as recovery income.
+// Recoveries cannot exceed the receivable that was written off.
+func (u *writeOffUsecase) RecordRecovery(transactionID uint, req RecoveryRequest) (*model.Recovery, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	req.Source = strings.ToUpper(strings.TrimSpace(req.Source))
+	if !validRecoverySources[req.Source] {
+		return nil, errors.New("sumber pemulihan harus COLLECTION atau REPOSSESSION")
+	}
+	if req.Amount <= 0 {
+		return nil, errors.New("jumlah pemulihan harus lebih dari 0")
+	}
+
+	writeOff, err := u.getApprovedWriteOff(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if remaining := roundMoney(writeOff.ReceivableAmount - writeOff.RecoveredAmount); req.Amount > remaining {
+		return nil, fmt.Errorf("jumlah pemulihan melebihi sisa piutang yang dihapusbukukan (Rp %.2f)", remaining)
+	}
+
+	now := time.Now()
+	receivedAt := req.ReceivedAt
+	if receivedAt.IsZero() {
+		receivedAt = now
+	}
+	recovery := &model.Recovery{
+		WriteOffID:    writeOff.ID,
+		TransactionID: transactionID,
+		Source:        req.Source,
+		Amount:        roundMoney(req.Amount),
+		Reference:     req.Reference,
+		ReceivedAt:    receivedAt,
+		CreatedAt:     now,
+	}
+	err = u.transactor.Transaction(func(tx *repository.Repositories) error {
+		if err := tx.Recoveries.Create(recovery); err != nil {
+			return err
+		}
+		writeOff.RecoveredAmount = roundMoney(writeOff.RecoveredAmount + recovery.Amount)
+		writeOff.UpdatedAt = now
+		if err := tx.WriteOffs.Update(writeOff); err != nil {
+			return err
+		}
+		return ledgerIn(tx).Post(recoveryEntry(recovery))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("✓ Pemulihan %s kontrak #%d: Rp %.2f\n", recovery.Source, transactionID, recovery.Amount)
+	return recovery, nil
+}
+
+func (u *writeOffUsecase) GetRecoveries(transactionID uint) ([]model.Recovery, error) {
+	writeOff, err := u.getApprovedWriteOff(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	return u.recoveryRepo.GetByWriteOffID(writeOff.ID)
+}
+
+// GetRecoveryByVintage sums losses and recoveries of written-off contracts per booking month
+func (u *writeOffUsecase) GetRecoveryByVintage() ([]VintageRecovery, error) {
+	writeOffs, err := u.writeOffRepo.GetByStatus(model.WriteOffStatusApproved)
+	if err != nil {
+		return nil, err
+	}
+
+	byVintage := make(map[string]*VintageRecovery)
+	for _, writeOff := range writeOffs {
+		vintage, ok := byVintage[writeOff.Vintage]
+		if !ok {
+			vintage = &VintageRecovery{Vintage: writeOff.Vintage}
+			byVintage[writeOff.Vintage] = vintage
+		}
+		vintage.ContractsWrittenOff++
+		vintage.LossAmount = roundMoney(vintage.LossAmount + writeOff.LossAmount)
+		vintage.RecoveredAmount = roundMoney(vintage.RecoveredAmount + writeOff.RecoveredAmount)
+	}
+
+	report := make([]VintageRecovery, 0, len(byVintage))
+	for _, vintage := range byVintage {
+		if vintage.LossAmount > 0 {
+			vintage.RecoveryRate = math.Round(vintage.RecoveredAmount/vintage.LossAmount*10000) / 10000
+		}
+		report = append(report, *vintage)
+	}
+	sort.Slice(report, func(i, j int) bool { return report[i].Vintage < report[j].Vintage })
+	return report, nil
+}
+
+// getEligibleTransaction loads a DEFAULTED contract past the write-off DPD
+func (u *writeOffUsecase) getEligibleTransaction(transactionID uint) (*model.Transaction, error) {
+	transaction, err := u.transactionRepo.GetByID(transactionID)
+	if err != nil {
+		return nil, errors.New("transaksi tidak ditemukan")
+	}
+	if transaction.Status != model.TransactionStatusDefaulted {
+		return nil, fmt.Errorf("transaksi berstatus %s tidak dapat dihapusbukukan", transaction.Status)
+	}
+	if transaction.DaysPastDue < u.policy.MinDaysPastDue {
+		return nil, fmt.Errorf("kontrak belum memenuhi syarat hapus buku: DPD %d, minimal %d", transaction.DaysPastDue, u.policy.MinDaysPastDue)
+	}
+	return transaction, nil
+}
+
+// getApprovedWriteOff loads the write-off of a written-off contract
+func (u *writeOffUsecase) getApprovedWriteOff(transactionID uint) (*model.WriteOff, error) {
+	writeOffs, err := u.writeOffRepo.GetByTransactionID(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range writeOffs {
+		if writeOffs[i].Status == model.WriteOffStatusApproved {
+			return &writeOffs[i], nil
+		}
+	}
+	return nil, errors.New("kontrak belum dihapusbukukan")
+}
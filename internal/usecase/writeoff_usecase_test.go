@@ -0,0 +1,266 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"main/internal/ledger"
+	"main/internal/model"
+	"main/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// MockWriteOffRepository for testing
+type MockWriteOffRepository struct {
+	writeOffs []*model.WriteOff
+}
+
+func (m *MockWriteOffRepository) Create(writeOff *model.WriteOff) error {
+	writeOff.ID = uint(len(m.writeOffs) + 1)
+	m.writeOffs = append(m.writeOffs, writeOff)
+	return nil
+}
+
+func (m *MockWriteOffRepository) GetByID(id uint) (*model.WriteOff, error) {
+	for _, writeOff := range m.writeOffs {
+		if writeOff.ID == id {
+			return writeOff, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockWriteOffRepository) GetByTransactionID(transactionID uint) ([]model.WriteOff, error) {
+	var writeOffs []model.WriteOff
+	for _, writeOff := range m.writeOffs {
+		if writeOff.TransactionID == transactionID {
+			writeOffs = append(writeOffs, *writeOff)
+		}
+	}
+	return writeOffs, nil
+}
+
+func (m *MockWriteOffRepository) GetByStatus(status string) ([]model.WriteOff, error) {
+	var writeOffs []model.WriteOff
+	for _, writeOff := range m.writeOffs {
+		if writeOff.Status == status {
+			writeOffs = append(writeOffs, *writeOff)
+		}
+	}
+	return writeOffs, nil
+}
+
+func (m *MockWriteOffRepository) Update(writeOff *model.WriteOff) error {
+	for i := range m.writeOffs {
+		if m.writeOffs[i].ID == writeOff.ID {
+			*m.writeOffs[i] = *writeOff
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+// MockRecoveryRepository for testing
+type MockRecoveryRepository struct {
+	recoveries []model.Recovery
+}
+
+func (m *MockRecoveryRepository) Create(recovery *model.Recovery) error {
+	recovery.ID = uint(len(m.recoveries) + 1)
+	m.recoveries = append(m.recoveries, *recovery)
+	return nil
+}
+
+func (m *MockRecoveryRepository) GetByWriteOffID(writeOffID uint) ([]model.Recovery, error) {
+	var recoveries []model.Recovery
+	for _, recovery := range m.recoveries {
+		if recovery.WriteOffID == writeOffID {
+			recoveries = append(recoveries, recovery)
+		}
+	}
+	return recoveries, nil
+}
+
+type writeOffFixture struct {
+	uc              WriteOffUsecase
+	transactionRepo *MockTransactionRepository
+	installmentRepo *MockInstallmentRepository
+	consumerRepo    *MockConsumerRepository
+	limitRepo       *MockConsumerLimitRepository
+	accrualRepo     *MockInterestAccrualRepository
+	ledger          LedgerUsecase
+}
+
+// newWriteOffFixture books the test contract on 1 Jan 2026, accrues interest while it performs
+// and then leaves it DEFAULTED at 200 DPD
+func newWriteOffFixture() *writeOffFixture {
+	f := &writeOffFixture{
+		transactionRepo: NewMockTransactionRepository(),
+		installmentRepo: &MockInstallmentRepository{},
+		consumerRepo:    NewMockConsumerRepository(),
+		limitRepo:       NewMockConsumerLimitRepository(),
+		accrualRepo:     &MockInterestAccrualRepository{},
+	}
+	installmentRepo := f.installmentRepo
+	start := time.Date(2026, 1, 1, 10, 0, 0, 0, time.Local)
+	f.consumerRepo.Create(&model.Consumer{NIK: "3201011501900001", FullName: "Budi", KYCStatus: model.KYCStatusVerified})
+	transaction := bookTestContract(f.transactionRepo, installmentRepo, start)
+	f.limitRepo.Create(&model.ConsumerLimit{ConsumerID: 1, Tenor: 3, LimitAmount: 10000000, UsedAmount: 3000000})
+
+	var journalRepo *MockJournalRepository
+	f.ledger, journalRepo = newTestLedger()
+	f.ledger.Post(activationEntry(transaction, start))
+	writeOffRepo := &MockWriteOffRepository{}
+	recoveryRepo := &MockRecoveryRepository{}
+	transactor := newTestTransactor(repository.Repositories{
+		Transactions: f.transactionRepo,
+		Limits:       f.limitRepo,
+		Consumers:    f.consumerRepo,
+		Installments: installmentRepo,
+		Accruals:     f.accrualRepo,
+		WriteOffs:    writeOffRepo,
+		Recoveries:   recoveryRepo,
+		Journal:      journalRepo,
+	})
+	accrualUC := NewAccrualUsecase(f.transactionRepo, installmentRepo, f.accrualRepo, transactor, DefaultAccrualPolicy())
+	accrualUC.RunAccrual(time.Date(2026, 2, 1, 0, 0, 0, 0, time.Local))
+
+	transaction.Status = model.TransactionStatusDefaulted
+	transaction.DaysPastDue = 200
+
+	f.uc = NewWriteOffUsecase(f.transactionRepo, writeOffRepo, recoveryRepo, accrualUC, transactor, DefaultWriteOffPolicy())
+	return f
+}
+
+// Test: Only DEFAULTED contracts past the write-off DPD can be proposed
+func TestRequestWriteOff_Eligibility(t *testing.T) {
+	f := newWriteOffFixture()
+	transaction, _ := f.transactionRepo.GetByID(1)
+	transaction.DaysPastDue = 120
+
+	if _, err := f.uc.RequestWriteOff(1, WriteOffRequest{Reason: "Macet", RequestedBy: "collector-1"}); err == nil {
+		t.Error("Expected error below the write-off DPD, got nil")
+	}
+	if eligible, _ := f.uc.GetEligibleContracts(); len(eligible) != 0 {
+		t.Errorf("Expected no eligible contracts, got %d", len(eligible))
+	}
+
+	transaction.DaysPastDue = 200
+	writeOff, err := f.uc.RequestWriteOff(1, WriteOffRequest{Reason: "Macet", RequestedBy: "collector-1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if writeOff.Status != model.WriteOffStatusPending || writeOff.Vintage != "2026-01" {
+		t.Errorf("Expected pending write-off of vintage 2026-01, got %+v", writeOff)
+	}
+	if transaction.Status != model.TransactionStatusDefaulted {
+		t.Error("Expected contract unchanged until approval")
+	}
+}
+
+// Test: Approval takes the contract off the books, releases the limit and blocks new limits
+func TestReviewWriteOff_Approve(t *testing.T) {
+	f := newWriteOffFixture()
+	writeOff, _ := f.uc.RequestWriteOff(1, WriteOffRequest{Reason: "Macet", RequestedBy: "collector-1"})
+
+	if _, err := f.uc.ReviewWriteOff(writeOff.ID, true, "collector-1", ""); err == nil {
+		t.Error("Expected error when the requester approves, got nil")
+	}
+
+	approved, err := f.uc.ReviewWriteOff(writeOff.ID, true, "supervisor-1", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if approved.ReversedAccruals != 147636.77 || approved.ReceivableAmount != 3300000 ||
+		approved.DeferredInterest != 300000 || approved.LossAmount != 3000000 {
+		t.Errorf("Expected 3300000 receivable written off with a 3000000 loss, got %+v", approved)
+	}
+
+	if accountBalance(t, f.ledger, ledger.Receivable) != 0 || accountBalance(t, f.ledger, ledger.UnearnedInterest) != 0 ||
+		accountBalance(t, f.ledger, ledger.InterestIncome) != 0 || accountBalance(t, f.ledger, ledger.WriteOffExpense) != 3000000 {
+		t.Error("Expected receivable and deferred interest cleared against the write-off expense")
+	}
+
+	transaction, _ := f.transactionRepo.GetByID(1)
+	limit, _ := f.limitRepo.GetByConsumerAndTenor(1, 3)
+	consumer, _ := f.consumerRepo.GetByID(1)
+	if transaction.Status != model.TransactionStatusWrittenOff || limit.UsedAmount != 0 || !consumer.WrittenOff {
+		t.Errorf("Expected contract WRITTEN_OFF, limit released and consumer flagged, got %s, %.2f, %v",
+			transaction.Status, limit.UsedAmount, consumer.WrittenOff)
+	}
+
+	limitUC := NewConsumerLimitUsecase(f.limitRepo, f.consumerRepo)
+	if err := limitUC.AssignLimit(&model.ConsumerLimit{ConsumerID: 1, Tenor: 6, LimitAmount: 5000000}); !errors.Is(err, ErrConsumerWrittenOff) {
+		t.Errorf("Expected ErrConsumerWrittenOff, got %v", err)
+	}
+}
+
+// Test: Interest collected before the write-off stays earned, only the accrued unpaid interest is reversed
+func TestReviewWriteOff_PaidInstallment(t *testing.T) {
+	f := newWriteOffFixture()
+	transaction, _ := f.transactionRepo.GetByID(1)
+
+	// The first installment, 1.000.000 principal and 100.000 interest, was paid on its due date
+	installments, _ := f.installmentRepo.GetByTransactionID(1)
+	first := &installments[0]
+	paidAt := first.DueDate
+	first.PaidAmount = first.Amount
+	first.PaidAt = &paidAt
+	first.Status = model.InstallmentStatusPaid
+	f.installmentRepo.Update(first)
+	payment := &model.Payment{ID: 1, TransactionID: 1, Type: model.PaymentTypeInstallment, Amount: first.Amount, Reference: "TRF-001", PaidAt: paidAt}
+	f.ledger.Post(installmentPaymentEntry(transaction, &PaymentPosting{Payment: payment, InstallmentAmount: first.Amount}, false))
+
+	writeOff, _ := f.uc.RequestWriteOff(1, WriteOffRequest{Reason: "Macet", RequestedBy: "collector-1"})
+	approved, err := f.uc.ReviewWriteOff(writeOff.ID, true, "supervisor-1", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// 147.636,77 accrued, of which 100.000 was paid
+	if approved.ReversedAccruals != 47636.77 || approved.ReceivableAmount != 2200000 ||
+		approved.DeferredInterest != 200000 || approved.LossAmount != 2000000 {
+		t.Errorf("Expected 2200000 receivable written off with a 2000000 loss, got %+v", approved)
+	}
+	if accountBalance(t, f.ledger, ledger.InterestIncome) != 100000 || accountBalance(t, f.ledger, ledger.WriteOffExpense) != 2000000 ||
+		accountBalance(t, f.ledger, ledger.UnearnedInterest) != 0 || accountBalance(t, f.ledger, ledger.Receivable) != 0 {
+		t.Error("Expected the paid interest kept as income and the unpaid principal written off")
+	}
+}
+
+// Test: Recoveries are booked as income, capped at the written-off receivable and reported per vintage
+func TestRecordRecovery(t *testing.T) {
+	f := newWriteOffFixture()
+	writeOff, _ := f.uc.RequestWriteOff(1, WriteOffRequest{Reason: "Macet", RequestedBy: "collector-1"})
+
+	if _, err := f.uc.RecordRecovery(1, RecoveryRequest{Source: "COLLECTION", Amount: 100000}); err == nil {
+		t.Error("Expected error before the write-off is approved, got nil")
+	}
+	f.uc.ReviewWriteOff(writeOff.ID, true, "supervisor-1", "")
+
+	if _, err := f.uc.RecordRecovery(1, RecoveryRequest{Source: "GIFT", Amount: 100000}); err == nil {
+		t.Error("Expected error for unknown source, got nil")
+	}
+	if _, err := f.uc.RecordRecovery(1, RecoveryRequest{Source: "REPOSSESSION", Amount: 500000, Reference: "LELANG-01"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := f.uc.RecordRecovery(1, RecoveryRequest{Source: "COLLECTION", Amount: 2800001}); err == nil {
+		t.Error("Expected error for recovery above the written-off receivable, got nil")
+	}
+
+	if accountBalance(t, f.ledger, ledger.RecoveryIncome) != 500000 || accountBalance(t, f.ledger, ledger.Cash) != 500000 {
+		t.Error("Expected 500000 recovery income received in cash")
+	}
+	if recoveries, _ := f.uc.GetRecoveries(1); len(recoveries) != 1 {
+		t.Errorf("Expected 1 recovery, got %d", len(recoveries))
+	}
+
+	report, err := f.uc.GetRecoveryByVintage()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(report) != 1 || report[0].Vintage != "2026-01" || report[0].RecoveredAmount != 500000 || report[0].RecoveryRate != 0.1667 {
+		t.Errorf("Expected 16.67%% recovery for vintage 2026-01, got %+v", report)
+	}
+}
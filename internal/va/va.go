@@ -0,0 +1,119 @@
+package va
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidSignature = errors.New("signature callback tidak valid")
+	ErrStaleCallback    = errors.New("timestamp callback di luar batas waktu")
+	ErrInvalidCallback  = errors.New("isi callback tidak valid")
+)
+
+// Bank is a bank issuing virtual accounts. Prefix is the company code the bank assigned,
+// Secret the key the bank signs its callbacks with.
+type Bank struct {
+	Code   string
+	Prefix string
+	Secret string
+}
+
+// suffixDigits is the width of the contract part of the VA number, before the check digit
+const suffixDigits = 10
+
+// Number builds the VA number of a contract at a bank: the bank prefix, the contract ID
+// zero-padded to ten digits and a Luhn check digit over both
+func Number(prefix string, contractID uint) (string, error) {
+	if prefix == "" || strings.Trim(prefix, "0123456789") != "" {
+		return "", fmt.Errorf("prefix VA %q harus berupa angka", prefix)
+	}
+	suffix := strconv.FormatUint(uint64(contractID), 10)
+	if len(suffix) > suffixDigits {
+		return "", fmt.Errorf("ID kontrak %d melebihi %d digit", contractID, suffixDigits)
+	}
+	body := prefix + strings.Repeat("0", suffixDigits-len(suffix)) + suffix
+	return body + strconv.Itoa(luhnCheckDigit(body)), nil
+}
+
+// ValidNumber reports whether the last digit of the number is its Luhn check digit
+func ValidNumber(number string) bool {
+	if len(number) < 2 || strings.Trim(number, "0123456789") != "" {
+		return false
+	}
+	body, check := number[:len(number)-1], number[len(number)-1]
+	return int(check-'0') == luhnCheckDigit(body)
+}
+
+func luhnCheckDigit(digits string) int {
+	sum := 0
+	double := true
+	for i := len(digits) - 1; i >= 0; i-- {
+		digit := int(digits[i] - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+	return (10 - sum%10) % 10
+}
+
+// Sign computes the callback signature: hex HMAC-SHA256 of "<timestamp>.<body>" with the bank secret
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of a callback and that its Unix timestamp is within tolerance of now,
+// so a captured callback cannot be replayed later
+func Verify(secret, timestamp, signature string, body []byte, now time.Time, tolerance time.Duration) error {
+	if secret == "" || signature == "" {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(strings.ToLower(signature))) {
+		return ErrInvalidSignature
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrStaleCallback
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > tolerance || age < -tolerance {
+		return ErrStaleCallback
+	}
+	return nil
+}
+
+// Callback is the payment notification a bank sends for a transfer into a virtual account
+type Callback struct {
+	PaymentID string    `json:"payment_id"` // the bank's unique ID of the transfer
+	VANumber  string    `json:"va_number"`
+	Amount    float64   `json:"amount"`
+	PaidAt    time.Time `json:"paid_at"`
+}
+
+// ParseCallback decodes and validates a callback body
+func ParseCallback(body []byte) (*Callback, error) {
+	var callback Callback
+	if err := json.Unmarshal(body, &callback); err != nil {
+		return nil, ErrInvalidCallback
+	}
+	callback.PaymentID = strings.TrimSpace(callback.PaymentID)
+	callback.VANumber = strings.TrimSpace(callback.VANumber)
+	if callback.PaymentID == "" || callback.VANumber == "" || callback.Amount <= 0 || callback.PaidAt.IsZero() {
+		return nil, ErrInvalidCallback
+	}
+	return &callback, nil
+}
@@ -0,0 +1,59 @@
+package va
+
+import (
+	"testing"
+	"time"
+)
+
+// Test: VA numbers carry the bank prefix, the padded contract ID and a Luhn check digit
+func TestNumber(t *testing.T) {
+	number, err := Number("39358", 42)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if number[:15] != "393580000000042" || len(number) != 16 {
+		t.Errorf("Unexpected VA number %s", number)
+	}
+	if !ValidNumber(number) {
+		t.Errorf("Expected %s to pass the check digit", number)
+	}
+	if ValidNumber(number[:14] + "3" + number[15:]) {
+		t.Error("Expected a mistyped digit to fail the check digit")
+	}
+
+	if _, err := Number("BCA", 1); err == nil {
+		t.Error("Expected error for non-numeric prefix, got nil")
+	}
+	if _, err := Number("39358", 12345678901); err == nil {
+		t.Error("Expected error for contract ID longer than the suffix, got nil")
+	}
+}
+
+// Test: Only callbacks signed with the secret within the tolerance pass
+func TestVerify(t *testing.T) {
+	body := []byte(`{"payment_id":"TRX-1","va_number":"3935800000000421","amount":1100000,"paid_at":"2026-02-01T09:00:00+07:00"}`)
+	now := time.Unix(1769911200, 0)
+	timestamp := "1769911200"
+	signature := Sign("s3cret", timestamp, body)
+
+	if err := Verify("s3cret", timestamp, signature, body, now.Add(time.Minute), 5*time.Minute); err != nil {
+		t.Errorf("Expected valid signature, got %v", err)
+	}
+	if err := Verify("other", timestamp, signature, body, now, 5*time.Minute); err != ErrInvalidSignature {
+		t.Errorf("Expected ErrInvalidSignature for wrong secret, got %v", err)
+	}
+	if err := Verify("s3cret", timestamp, signature, append(body, ' '), now, 5*time.Minute); err != ErrInvalidSignature {
+		t.Errorf("Expected ErrInvalidSignature for tampered body, got %v", err)
+	}
+	if err := Verify("s3cret", timestamp, signature, body, now.Add(10*time.Minute), 5*time.Minute); err != ErrStaleCallback {
+		t.Errorf("Expected ErrStaleCallback for replayed callback, got %v", err)
+	}
+
+	callback, err := ParseCallback(body)
+	if err != nil || callback.PaymentID != "TRX-1" || callback.Amount != 1100000 {
+		t.Errorf("Expected callback parsed, got %+v, %v", callback, err)
+	}
+	if _, err := ParseCallback([]byte(`{"payment_id":"TRX-1","amount":-5}`)); err != ErrInvalidCallback {
+		t.Errorf("Expected ErrInvalidCallback, got %v", err)
+	}
+}
@@ -5,11 +5,15 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
+	"time"
 
 	"main/config"
 	"main/internal/handler"
+	"main/internal/kyc"
 	"main/internal/middleware"
 	"main/internal/repository"
+	"main/internal/scheduler"
 	"main/internal/usecase"
 )
 
@@ -22,15 +26,139 @@ func main() {
 	consumerRepo := repository.NewConsumerRepository(db)
 	consumerLimitRepo := repository.NewConsumerLimitRepository(db)
 	transactionRepo := repository.NewTransactionRepository(db)
+	kycRepo := repository.NewKYCVerificationRepository(db)
+	watchlistRepo := repository.NewWatchlistRepository(db)
+	watchlistHitRepo := repository.NewWatchlistHitRepository(db)
+	fraudAssessmentRepo := repository.NewFraudAssessmentRepository(db)
+	installmentRepo := repository.NewInstallmentRepository(db)
+	paymentRepo := repository.NewPaymentRepository(db)
+	cancellationRepo := repository.NewTransactionCancellationRepository(db)
+	refundRepo := repository.NewRefundRepository(db)
+	limitHoldRepo := repository.NewLimitHoldRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	merchantRepo := repository.NewMerchantRepository(db)
+	merchantAPIKeyRepo := repository.NewMerchantAPIKeyRepository(db)
+	settlementRepo := repository.NewSettlementRepository(db)
+	journalRepo := repository.NewJournalRepository(db)
+	accrualRepo := repository.NewInterestAccrualRepository(db)
+	restructuringRepo := repository.NewRestructuringRepository(db)
+	writeOffRepo := repository.NewWriteOffRepository(db)
+	recoveryRepo := repository.NewRecoveryRepository(db)
+	collectionCaseRepo := repository.NewCollectionCaseRepository(db)
+	contactAttemptRepo := repository.NewContactAttemptRepository(db)
+	promiseRepo := repository.NewPromiseToPayRepository(db)
+	notificationPreferenceRepo := repository.NewNotificationPreferenceRepository(db)
+	notificationLogRepo := repository.NewNotificationLogRepository(db)
+	virtualAccountRepo := repository.NewVirtualAccountRepository(db)
+	paymentCallbackRepo := repository.NewPaymentCallbackRepository(db)
+	bankStatementRepo := repository.NewBankStatementRepository(db)
+	bankStatementLineRepo := repository.NewBankStatementLineRepository(db)
+	statementArchiveRepo := repository.NewStatementArchiveRepository(db)
+	lateFeeChargeRepo := repository.NewLateFeeChargeRepository(db)
+	contractTemplateRepo := repository.NewContractTemplateRepository(db)
+	contractDocumentRepo := repository.NewContractDocumentRepository(db)
+	contractSignatureRepo := repository.NewContractSignatureRepository(db)
+	otpChallengeRepo := repository.NewOTPChallengeRepository(db)
+	transactor := repository.NewTransactor(db)
+
+	// KYC providers: local stand-ins route every consumer to manual review
+	// until real OCR / face match / liveness vendors are wired in
+	ktpOCR := kyc.NewFakeKTPOCR()
+	faceMatch := &kyc.FakeFaceMatch{Score: 0}
+	liveness := &kyc.FakeLiveness{Score: 0}
 
 	// 3. Usecase Layer
-	consumerUC := usecase.NewConsumerUsecase(consumerRepo)
-	limitUC := usecase.NewConsumerLimitUsecase(consumerLimitRepo)
-	transactionUC := usecase.NewTransactionUsecase(transactionRepo, consumerLimitRepo)
+	eligibilityRules := config.LoadEligibilityRules()
+	nikPolicy := config.LoadNIKPolicy()
+	fraudEngine := config.LoadFraudEngine()
+	delinquencyPolicy := config.LoadDelinquencyPolicy()
+	payoffPolicy := config.LoadPayoffPolicy()
+	cancellationPolicy := config.LoadCancellationPolicy()
+	settlementPolicy := config.LoadSettlementPolicy()
+	accrualPolicy := config.LoadAccrualPolicy()
+	restructuringPolicy := config.LoadRestructuringPolicy()
+	writeOffPolicy := config.LoadWriteOffPolicy()
+	collectionPolicy := config.LoadCollectionPolicy()
+	notificationPolicy := config.LoadNotificationPolicy()
+	virtualAccountPolicy := config.LoadVirtualAccountPolicy()
+	reconciliationPolicy := config.LoadReconciliationPolicy()
+	transactionPolicy := config.LoadTransactionPolicy()
+	otpPolicy := config.LoadOTPPolicy()
+	otpKey := config.LoadOTPKey()
+	ledgerUC := usecase.NewLedgerUsecase(journalRepo)
+	notificationUC := usecase.NewNotificationUsecase(
+		consumerRepo, transactionRepo, installmentRepo, notificationPreferenceRepo, notificationLogRepo,
+		config.LoadNotifiers(), notificationPolicy,
+	)
+	otpUC := usecase.NewOTPUsecase(otpChallengeRepo, consumerRepo, merchantRepo, notificationUC, otpKey, otpPolicy)
+	productUC := usecase.NewProductUsecase(productRepo)
+	watchlistUC := usecase.NewWatchlistUsecase(watchlistRepo, watchlistHitRepo, consumerRepo)
+	consumerUC := usecase.NewConsumerUsecase(consumerRepo, eligibilityRules, nikPolicy, watchlistUC)
+	limitUC := usecase.NewConsumerLimitUsecase(consumerLimitRepo, consumerRepo)
+	transactionUC := usecase.NewTransactionUsecase(
+		transactionRepo, consumerLimitRepo, consumerRepo, eligibilityRules, watchlistUC, fraudEngine, fraudAssessmentRepo,
+		installmentRepo, limitHoldRepo, productUC, merchantRepo, transactor, notificationUC, otpUC,
+		transactionPolicy,
+	)
+	kycUC := usecase.NewKYCUsecase(consumerRepo, kycRepo, watchlistHitRepo, ktpOCR, faceMatch, liveness)
+	delinquencyUC := usecase.NewDelinquencyUsecase(transactionRepo, installmentRepo, transactor, notificationUC, delinquencyPolicy)
+	payoffUC := usecase.NewPayoffUsecase(transactionRepo, installmentRepo, transactor, notificationUC, payoffPolicy)
+	creditSummaryUC := usecase.NewCreditSummaryUsecase(consumerRepo, consumerLimitRepo, transactionRepo, installmentRepo)
+	cancellationUC := usecase.NewCancellationUsecase(
+		transactionRepo, installmentRepo, paymentRepo, refundRepo, transactor, notificationUC, cancellationPolicy,
+	)
+	merchantUC := usecase.NewMerchantUsecase(merchantRepo, merchantAPIKeyRepo, transactionRepo)
+	settlementUC := usecase.NewSettlementUsecase(
+		settlementRepo, transactionRepo, cancellationRepo, merchantRepo, transactor, settlementPolicy,
+	)
+	accrualUC := usecase.NewAccrualUsecase(transactionRepo, installmentRepo, accrualRepo, transactor, accrualPolicy)
+	restructuringUC := usecase.NewRestructuringUsecase(
+		transactionRepo, installmentRepo, restructuringRepo, transactor, restructuringPolicy,
+	)
+	writeOffUC := usecase.NewWriteOffUsecase(
+		transactionRepo, writeOffRepo, recoveryRepo, accrualUC, transactor, writeOffPolicy,
+	)
+	collectionUC := usecase.NewCollectionUsecase(
+		transactionRepo, installmentRepo, collectionCaseRepo, contactAttemptRepo, promiseRepo, collectionPolicy,
+	)
+	paymentUC := usecase.NewPaymentUsecase(transactionRepo, installmentRepo, paymentRepo, transactor, notificationUC)
+	virtualAccountUC := usecase.NewVirtualAccountUsecase(
+		transactionRepo, virtualAccountRepo, paymentCallbackRepo, paymentUC, transactor, config.LoadVirtualAccountBanks(), virtualAccountPolicy,
+	)
+	reconciliationUC := usecase.NewReconciliationUsecase(
+		bankStatementRepo, bankStatementLineRepo, transactionRepo, installmentRepo, virtualAccountRepo, paymentCallbackRepo, paymentUC,
+		reconciliationPolicy,
+	)
+	statementUC := usecase.NewStatementUsecase(consumerRepo, transactionRepo, installmentRepo, lateFeeChargeRepo, statementArchiveRepo, ledgerUC)
+	contractUC := usecase.NewContractUsecase(
+		transactionRepo, consumerRepo, installmentRepo, merchantRepo, contractTemplateRepo, contractDocumentRepo, contractSignatureRepo,
+		transactionUC, notificationUC, otpKey, otpPolicy,
+	)
 
 	// 4. Handler Layer
-	consumerHandler := handler.NewConsumerHandler(consumerUC, limitUC)
+	consumerHandler := handler.NewConsumerHandler(consumerUC, limitUC, kycUC)
 	transactionHandler := handler.NewTransactionHandler(transactionUC)
+	kycHandler := handler.NewKYCHandler(kycUC)
+	watchlistHandler := handler.NewWatchlistHandler(watchlistUC)
+	delinquencyHandler := handler.NewDelinquencyHandler(delinquencyUC)
+	payoffHandler := handler.NewPayoffHandler(payoffUC)
+	cancellationHandler := handler.NewCancellationHandler(cancellationUC)
+	limitHoldHandler := handler.NewLimitHoldHandler(transactionUC)
+	creditSummaryHandler := handler.NewCreditSummaryHandler(creditSummaryUC)
+	productHandler := handler.NewProductHandler(productUC)
+	merchantHandler := handler.NewMerchantHandler(merchantUC)
+	settlementHandler := handler.NewSettlementHandler(settlementUC)
+	ledgerHandler := handler.NewLedgerHandler(ledgerUC)
+	accrualHandler := handler.NewAccrualHandler(accrualUC)
+	restructuringHandler := handler.NewRestructuringHandler(restructuringUC)
+	writeOffHandler := handler.NewWriteOffHandler(writeOffUC)
+	collectionHandler := handler.NewCollectionHandler(collectionUC)
+	notificationHandler := handler.NewNotificationHandler(notificationUC)
+	paymentHandler := handler.NewPaymentHandler(paymentUC, virtualAccountUC)
+	reconciliationHandler := handler.NewReconciliationHandler(reconciliationUC)
+	statementHandler := handler.NewStatementHandler(statementUC)
+	contractHandler := handler.NewContractHandler(contractUC)
+	otpHandler := handler.NewOTPHandler(otpUC)
 
 	// 5. Setup Routes with Security Middleware
 	mux := http.NewServeMux()
@@ -47,20 +175,226 @@ func main() {
 	mux.HandleFunc("/api/transactions/consumer", transactionHandler.GetConsumerTransactions)
 	mux.HandleFunc("/api/transactions/status", transactionHandler.UpdateTransactionStatus)
 
+	// Credit summary endpoint
+	mux.HandleFunc("GET /api/v1/consumers/{id}/credit-summary", creditSummaryHandler.GetCreditSummary)
+
+	// KYC endpoints
+	mux.HandleFunc("POST /api/v1/consumers/{id}/kyc/verify", kycHandler.VerifyConsumer)
+	mux.HandleFunc("GET /api/v1/consumers/{id}/kyc", kycHandler.GetVerificationHistory)
+	mux.HandleFunc("GET /api/v1/kyc/reviews", kycHandler.GetReviewQueue)
+	mux.HandleFunc("POST /api/v1/kyc/reviews/{id}", kycHandler.ReviewConsumer)
+
+	// Fraud review endpoints
+	mux.HandleFunc("GET /api/v1/fraud/reviews", transactionHandler.GetFlaggedTransactions)
+	mux.HandleFunc("POST /api/v1/fraud/reviews/{id}", transactionHandler.ReviewFlaggedTransaction)
+
+	// Watchlist endpoints
+	mux.HandleFunc("POST /api/v1/watchlist/entries", watchlistHandler.AddEntry)
+	mux.HandleFunc("POST /api/v1/watchlist/import", watchlistHandler.ImportCSV)
+	mux.HandleFunc("GET /api/v1/watchlist/hits", watchlistHandler.GetHits)
+	mux.HandleFunc("POST /api/v1/watchlist/hits/{id}/review", watchlistHandler.ReviewHit)
+
+	// Delinquency endpoints
+	mux.HandleFunc("GET /api/v1/transactions/{id}/delinquency", delinquencyHandler.GetDelinquency)
+	mux.HandleFunc("POST /api/v1/delinquency/aging", delinquencyHandler.RunAging)
+
+	// Early settlement endpoints
+	mux.HandleFunc("GET /api/v1/transactions/{id}/payoff-quote", payoffHandler.GetPayoffQuote)
+	mux.HandleFunc("POST /api/v1/transactions/{id}/settle", payoffHandler.Settle)
+
+	// Cancellation endpoints
+	mux.HandleFunc("POST /api/v1/transactions/{id}/cancel", cancellationHandler.CancelTransaction)
+
+	// Upfront payment endpoints
+	mux.HandleFunc("POST /api/v1/transactions/{id}/upfront-payments", transactionHandler.RecordUpfrontPayment)
+
+	// Product catalogue endpoints
+	mux.HandleFunc("POST /api/v1/products", productHandler.CreateProduct)
+	mux.HandleFunc("GET /api/v1/products", productHandler.GetProducts)
+	mux.HandleFunc("GET /api/v1/products/{code}", productHandler.GetProduct)
+	mux.HandleFunc("POST /api/v1/products/{code}/promos", productHandler.AddPromo)
+	mux.HandleFunc("GET /api/v1/products/{code}/pricing", productHandler.GetPricing)
+
+	// Limit hold endpoints (authorize / capture for merchant checkout)
+	mux.HandleFunc("POST /api/v1/limits/holds", limitHoldHandler.AuthorizeHold)
+	mux.HandleFunc("GET /api/v1/limits/holds/{id}", limitHoldHandler.GetHold)
+	mux.HandleFunc("POST /api/v1/limits/holds/{id}/capture", limitHoldHandler.CaptureHold)
+	mux.HandleFunc("POST /api/v1/limits/holds/{id}/void", limitHoldHandler.VoidHold)
+
+	// Merchant registry endpoints
+	mux.HandleFunc("POST /api/v1/merchants", merchantHandler.RegisterMerchant)
+	mux.HandleFunc("GET /api/v1/merchants", merchantHandler.GetMerchants)
+	mux.HandleFunc("GET /api/v1/merchants/{id}", merchantHandler.GetMerchant)
+	mux.HandleFunc("PUT /api/v1/merchants/{id}/status", merchantHandler.UpdateMerchantStatus)
+	mux.HandleFunc("POST /api/v1/merchants/{id}/branches", merchantHandler.AddBranch)
+	mux.HandleFunc("POST /api/v1/merchants/{id}/api-keys", merchantHandler.IssueAPIKey)
+	mux.HandleFunc("DELETE /api/v1/merchants/{id}/api-keys/{keyId}", merchantHandler.RevokeAPIKey)
+	mux.HandleFunc("GET /api/v1/merchants/{id}/transactions", merchantHandler.GetMerchantTransactions)
+
+	// Merchant settlement endpoints
+	mux.HandleFunc("POST /api/v1/settlements/batches", settlementHandler.GenerateBatches)
+	mux.HandleFunc("GET /api/v1/settlements/batches", settlementHandler.GetBatches)
+	mux.HandleFunc("GET /api/v1/settlements/batches/{id}", settlementHandler.GetBatch)
+	mux.HandleFunc("POST /api/v1/settlements/export", settlementHandler.ExportBatches)
+	mux.HandleFunc("POST /api/v1/settlements/confirmations", settlementHandler.ImportConfirmation)
+
+	// General ledger endpoints
+	mux.HandleFunc("GET /api/v1/ledger/trial-balance", ledgerHandler.GetTrialBalance)
+	mux.HandleFunc("GET /api/v1/ledger/gl-export", ledgerHandler.ExportGL)
+
+	// Interest accrual endpoints
+	mux.HandleFunc("POST /api/v1/accruals/run", accrualHandler.RunAccrual)
+	mux.HandleFunc("GET /api/v1/transactions/{id}/accruals", accrualHandler.GetAccruals)
+
+	// Restructuring endpoints
+	mux.HandleFunc("POST /api/v1/transactions/{id}/restructurings", restructuringHandler.RequestRestructuring)
+	mux.HandleFunc("GET /api/v1/transactions/{id}/restructurings", restructuringHandler.GetRestructurings)
+	mux.HandleFunc("GET /api/v1/restructurings/pending", restructuringHandler.GetPendingRestructurings)
+	mux.HandleFunc("POST /api/v1/restructurings/{id}/review", restructuringHandler.ReviewRestructuring)
+
+	// Write-off and recovery endpoints
+	mux.HandleFunc("GET /api/v1/write-offs/eligible", writeOffHandler.GetEligibleContracts)
+	mux.HandleFunc("POST /api/v1/transactions/{id}/write-off", writeOffHandler.RequestWriteOff)
+	mux.HandleFunc("GET /api/v1/write-offs/pending", writeOffHandler.GetPendingWriteOffs)
+	mux.HandleFunc("POST /api/v1/write-offs/{id}/review", writeOffHandler.ReviewWriteOff)
+	mux.HandleFunc("POST /api/v1/transactions/{id}/recoveries", writeOffHandler.RecordRecovery)
+	mux.HandleFunc("GET /api/v1/transactions/{id}/recoveries", writeOffHandler.GetRecoveries)
+	mux.HandleFunc("GET /api/v1/write-offs/recovery-by-vintage", writeOffHandler.GetRecoveryByVintage)
+
+	// Collections endpoints
+	mux.HandleFunc("POST /api/v1/collections/queue/refresh", collectionHandler.RefreshQueue)
+	mux.HandleFunc("GET /api/v1/collections/queue", collectionHandler.GetQueue)
+	mux.HandleFunc("POST /api/v1/collections/assign", collectionHandler.AutoAssign)
+	mux.HandleFunc("PUT /api/v1/collections/cases/{id}/assignee", collectionHandler.AssignCase)
+	mux.HandleFunc("GET /api/v1/collections/cases/{id}", collectionHandler.GetCase)
+	mux.HandleFunc("POST /api/v1/collections/cases/{id}/contacts", collectionHandler.RecordContact)
+	mux.HandleFunc("POST /api/v1/collections/promises/check", collectionHandler.CheckPromises)
+
+	// Notification endpoints
+	mux.HandleFunc("GET /api/v1/consumers/{id}/notification-preference", notificationHandler.GetPreference)
+	mux.HandleFunc("PUT /api/v1/consumers/{id}/notification-preference", notificationHandler.UpdatePreference)
+	mux.HandleFunc("GET /api/v1/consumers/{id}/notifications", notificationHandler.GetDeliveryLog)
+	mux.HandleFunc("POST /api/v1/notifications/reminders/run", notificationHandler.SendDueReminders)
+
+	// Payment endpoints (bank callbacks are authenticated by their signature)
+	mux.HandleFunc("POST /api/v1/transactions/{id}/payments", paymentHandler.PostPayment)
+	mux.HandleFunc("GET /api/v1/transactions/{id}/payments", paymentHandler.GetPayments)
+	mux.HandleFunc("POST /api/v1/transactions/{id}/virtual-accounts", paymentHandler.IssueVirtualAccount)
+	mux.HandleFunc("GET /api/v1/transactions/{id}/virtual-accounts", paymentHandler.GetVirtualAccounts)
+	mux.HandleFunc("POST /api/v1/payments/callbacks/{bank}", paymentHandler.HandleCallback)
+	mux.HandleFunc("GET /api/v1/payments/suspense", paymentHandler.GetSuspense)
+	mux.HandleFunc("POST /api/v1/payments/suspense/{id}/resolve", paymentHandler.ResolveSuspense)
+
+	// Bank reconciliation endpoints
+	mux.HandleFunc("POST /api/v1/reconciliation/statements", reconciliationHandler.ImportStatement)
+	mux.HandleFunc("GET /api/v1/reconciliation/statements", reconciliationHandler.GetStatements)
+	mux.HandleFunc("GET /api/v1/reconciliation/statements/{id}", reconciliationHandler.GetReport)
+	mux.HandleFunc("POST /api/v1/reconciliation/lines/{id}/match", reconciliationHandler.MatchLine)
+
+	// Consumer account statement endpoints
+	mux.HandleFunc("GET /api/v1/consumers/{id}/statements", statementHandler.GetStatement)
+	mux.HandleFunc("GET /api/v1/consumers/{id}/statements/archive", statementHandler.GetArchives)
+	mux.HandleFunc("GET /api/v1/statements/archive/{id}", statementHandler.GetArchive)
+	mux.HandleFunc("POST /api/v1/statements/verify", statementHandler.VerifyStatement)
+
+	// Contract document and e-signature endpoints
+	mux.HandleFunc("POST /api/v1/contract-templates", contractHandler.CreateTemplate)
+	mux.HandleFunc("GET /api/v1/contract-templates/{code}", contractHandler.GetTemplates)
+	mux.HandleFunc("POST /api/v1/transactions/{id}/contract", contractHandler.GenerateContract)
+	mux.HandleFunc("GET /api/v1/transactions/{id}/contract", contractHandler.GetContractDocument)
+	mux.HandleFunc("GET /api/v1/transactions/{id}/contract/documents", contractHandler.GetContractDocuments)
+	mux.HandleFunc("POST /api/v1/transactions/{id}/contract/otp", contractHandler.RequestSignature)
+	mux.HandleFunc("POST /api/v1/transactions/{id}/contract/sign", contractHandler.SignContract)
+	mux.HandleFunc("GET /api/v1/transactions/{id}/contract/signatures", contractHandler.GetSignatures)
+
+	// Consumer OTP consent endpoints
+	mux.HandleFunc("POST /api/v1/otp/challenges", otpHandler.CreateChallenge)
+	mux.HandleFunc("GET /api/v1/otp/challenges/{id}", otpHandler.GetChallenge)
+	mux.HandleFunc("POST /api/v1/otp/challenges/{id}/resend", otpHandler.ResendChallenge)
+
 	// Health check endpoint
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		fmt.Fprintf(w, `{"status":"healthy","version":"1.0.0"}`)
 	})
 
+	// 6. Background Jobs
+	// DPD aging runs after midnight so installments due yesterday count as one day late
+	scheduler.Daily("delinquency-aging", 0, 30, func(now time.Time) error {
+		_, err := delinquencyUC.RunDailyAging(now)
+		return err
+	})
+	// Promises are settled before the queue is rebuilt on the DPD aging just produced
+	scheduler.Daily("collections", 0, 35, func(now time.Time) error {
+		if _, err := collectionUC.CheckPromises(now); err != nil {
+			return err
+		}
+		_, err := collectionUC.RefreshQueue(now)
+		return err
+	})
+	// Interest is recognised for the previous day once aging has moved non-performing contracts to DEFAULTED
+	scheduler.Daily("interest-accrual", 0, 45, func(now time.Time) error {
+		_, err := accrualUC.RunAccrual(now.AddDate(0, 0, -1))
+		return err
+	})
+	// Merchants are paid for the contracts booked up to the previous day
+	scheduler.Daily("settlement-batching", 1, 0, func(now time.Time) error {
+		_, err := settlementUC.GenerateBatches(now.AddDate(0, 0, -1))
+		return err
+	})
+	// The core accounting system picks up the GL file of the previous day
+	glExportDir := os.Getenv("GL_EXPORT_DIR")
+	if glExportDir == "" {
+		glExportDir = "exports/gl"
+	}
+	scheduler.Daily("gl-export", 2, 0, func(now time.Time) error {
+		export, err := ledgerUC.ExportGL(now.AddDate(0, 0, -1))
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(glExportDir, 0o750); err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(glExportDir, export.FileName), export.Content, 0o640)
+	})
+	// Consumers get their installment reminders in the morning rather than overnight
+	scheduler.Daily("installment-reminders", 8, 0, func(now time.Time) error {
+		_, err := notificationUC.SendDueReminders(now)
+		return err
+	})
+	scheduler.Every("limit-hold-sweeper", 5*time.Minute, func(now time.Time) error {
+		_, err := transactionUC.ReleaseExpiredHolds(now)
+		return err
+	})
+	scheduler.Every("unsigned-contract-sweeper", 15*time.Minute, func(now time.Time) error {
+		_, err := cancellationUC.CancelUnsignedTransactions(now)
+		return err
+	})
+
+	// Merchant API keys may only reach checkout and their own reports
+	merchantAuth := middleware.MerchantAPIKey(merchantUC.AuthenticateAPIKey,
+		"POST /api/transactions",
+		"GET /api/transactions/get",
+		"GET /api/transactions/consumer",
+		"GET /api/v1/products",
+		"GET /api/v1/products/",
+		"POST /api/v1/limits/holds",
+		"GET /api/v1/limits/holds/",
+		"POST /api/v1/limits/holds/",
+		"GET /api/v1/merchants/",
+		"POST /api/v1/otp/challenges",
+		"GET /api/v1/otp/challenges/",
+		"POST /api/v1/otp/challenges/",
+	)
+
 	// Wrap mux with security middleware
 	chain := middleware.SecurityHeaders(
 		middleware.InputValidation(
-			middleware.CORS(mux),
+			middleware.CORS(merchantAuth(mux)),
 		),
 	)
 
-	// 6. Start Server
+	// 7. Start Server
 	port := os.Getenv("API_PORT")
 	if port == "" {
 		port = "8080"
@@ -71,6 +405,7 @@ func main() {
 	log.Printf("✓ OWASP Security Headers: ENABLED\n")
 	log.Printf("✓ Input Validation: ENABLED\n")
 	log.Printf("✓ CORS Protection: ENABLED\n")
+	log.Printf("✓ Merchant API Keys: ENABLED\n")
 
 	if err := http.ListenAndServe(":"+port, chain); err != nil {
 		log.Fatal("Server error:", err)